@@ -93,6 +93,23 @@ func (t changesetTranslator) getFields() []string {
 	return ret
 }
 
+// addFields marks the provided fields as being included in the input.
+func (t *changesetTranslator) addFields(fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+
+	if t.inputMap == nil {
+		t.inputMap = make(map[string]interface{})
+	}
+
+	for _, f := range fields {
+		if _, found := t.inputMap[f]; !found {
+			t.inputMap[f] = nil
+		}
+	}
+}
+
 func (t changesetTranslator) string(value *string) string {
 	if value == nil {
 		return ""
@@ -14,6 +14,10 @@ type VisualFile interface {
 	IsVisualFile()
 }
 
+// SearchResultObject is one of the object types returned by the search
+// query.
+type SearchResultObject interface{}
+
 func convertVisualFile(f models.File) (VisualFile, error) {
 	switch f := f.(type) {
 	case VisualFile:
@@ -27,6 +31,20 @@ func convertVisualFile(f models.File) (VisualFile, error) {
 	}
 }
 
+// fileMetadata converts the embedded metadata of a file to the type of the
+// graphql Map scalar.
+func fileMetadata(m map[string]string) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+
+	ret := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		ret[k] = v
+	}
+	return ret
+}
+
 type GalleryFile struct {
 	*models.BaseFile
 }
@@ -51,6 +69,10 @@ func (f *VideoFile) Fingerprints() []models.Fingerprint {
 	return f.VideoFile.Fingerprints
 }
 
+func (f *VideoFile) Metadata() map[string]interface{} {
+	return fileMetadata(f.VideoFile.Metadata)
+}
+
 type ImageFile struct {
 	*models.ImageFile
 }
@@ -62,3 +84,7 @@ func (ImageFile) IsVisualFile() {}
 func (f *ImageFile) Fingerprints() []models.Fingerprint {
 	return f.ImageFile.Fingerprints
 }
+
+func (f *ImageFile) Metadata() map[string]interface{} {
+	return fileMetadata(f.ImageFile.Metadata)
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/stashapp/stash/pkg/plugin/hook"
 	"github.com/stashapp/stash/pkg/scraper"
 	"github.com/stashapp/stash/pkg/scraper/stashbox"
+	"github.com/stashapp/stash/pkg/session"
+	"github.com/stashapp/stash/pkg/sliceutil"
 )
 
 var (
@@ -26,9 +28,19 @@ var (
 
 	// ErrInput signifies errors where the input isn't valid for some reason. And no more specific error exists.
 	ErrInput = errors.New("input error")
+
+	// ErrPreHookMutation is returned when a mutation is performed from a Pre hook. Pre hooks are executed within
+	// the transaction of the triggering operation, so the mutation would wait until the hook has timed out.
+	ErrPreHookMutation = errors.New("mutations cannot be performed from a Pre hook")
 )
 
+// imageInputFields are the input fields containing images. Images are processed
+// before the transaction is started, since they may be downloaded from a URL,
+// so they cannot be changed by Pre hooks.
+var imageInputFields = []string{"image", "cover_image", "front_image", "back_image"}
+
 type hookExecutor interface {
+	ExecutePreHooks(ctx context.Context, id int, hookType hook.TriggerEnum, input interface{}, inputFields []string) ([]string, error)
 	ExecutePostHooks(ctx context.Context, id int, hookType hook.TriggerEnum, input interface{}, inputFields []string)
 }
 
@@ -51,6 +63,9 @@ func (r *Resolver) Gallery() GalleryResolver {
 func (r *Resolver) GalleryChapter() GalleryChapterResolver {
 	return &galleryChapterResolver{r}
 }
+func (r *Resolver) IdentifyProposal() IdentifyProposalResolver {
+	return &identifyProposalResolver{r}
+}
 func (r *Resolver) Mutation() MutationResolver {
 	return &mutationResolver{r}
 }
@@ -93,12 +108,27 @@ func (r *Resolver) ImageFile() ImageFileResolver {
 func (r *Resolver) SavedFilter() SavedFilterResolver {
 	return &savedFilterResolver{r}
 }
+func (r *Resolver) StashBoxSubmission() StashBoxSubmissionResolver {
+	return &stashBoxSubmissionResolver{r}
+}
+func (r *Resolver) StashBoxUpdate() StashBoxUpdateResolver {
+	return &stashBoxUpdateResolver{r}
+}
+func (r *Resolver) AutoTagRulePreview() AutoTagRulePreviewResolver {
+	return &autoTagRulePreviewResolver{r}
+}
 func (r *Resolver) Plugin() PluginResolver {
 	return &pluginResolver{r}
 }
 func (r *Resolver) ConfigResult() ConfigResultResolver {
 	return &configResultResolver{r}
 }
+func (r *Resolver) Playlist() PlaylistResolver {
+	return &playlistResolver{r}
+}
+func (r *Resolver) PlaylistItem() PlaylistItemResolver {
+	return &playlistItemResolver{r}
+}
 
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
@@ -106,6 +136,10 @@ type subscriptionResolver struct{ *Resolver }
 
 type galleryResolver struct{ *Resolver }
 type galleryChapterResolver struct{ *Resolver }
+type identifyProposalResolver struct{ *Resolver }
+type stashBoxSubmissionResolver struct{ *Resolver }
+type stashBoxUpdateResolver struct{ *Resolver }
+type autoTagRulePreviewResolver struct{ *Resolver }
 type performerResolver struct{ *Resolver }
 type sceneResolver struct{ *Resolver }
 type sceneMarkerResolver struct{ *Resolver }
@@ -119,15 +153,60 @@ type imageFileResolver struct{ *Resolver }
 type savedFilterResolver struct{ *Resolver }
 type pluginResolver struct{ *Resolver }
 type configResultResolver struct{ *Resolver }
+type playlistResolver struct{ *Resolver }
+type playlistItemResolver struct{ *Resolver }
 
 func (r *Resolver) withTxn(ctx context.Context, fn func(ctx context.Context) error) error {
+	if inPreHook(ctx) {
+		return ErrPreHookMutation
+	}
+
 	return r.repository.WithTxn(ctx, fn)
 }
 
+// inPreHook returns true if the request was made by a plugin from a Pre hook.
+func inPreHook(ctx context.Context) bool {
+	for _, v := range session.GetVisitedPluginHooks(ctx) {
+		if v.HookType.IsPre() {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (r *Resolver) withReadTxn(ctx context.Context, fn func(ctx context.Context) error) error {
 	return r.repository.WithReadTxn(ctx, fn)
 }
 
+// executePreHooks executes the pre-hooks for hookType. It must be called
+// within the transaction of the operation, so that an error returned by a hook
+// aborts the operation. Any fields set by the hooks are added to translator,
+// which may be nil if the operation does not have input fields.
+func (r *Resolver) executePreHooks(ctx context.Context, id int, hookType hook.TriggerEnum, input interface{}, translator *changesetTranslator) error {
+	var inputFields []string
+	if translator != nil {
+		inputFields = translator.getFields()
+	}
+
+	setFields, err := r.hookExecutor.ExecutePreHooks(ctx, id, hookType, input, inputFields)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range setFields {
+		if sliceutil.Contains(imageInputFields, f) {
+			return fmt.Errorf("%s: %s cannot be set by a Pre hook", hookType, f)
+		}
+	}
+
+	if translator != nil {
+		translator.addFields(setFields)
+	}
+
+	return nil
+}
+
 func (r *Resolver) stashboxRepository() stashbox.Repository {
 	return stashbox.NewRepository(r.repository)
 }
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/api/loaders"
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *autoTagRulePreviewResolver) Scenes(ctx context.Context, obj *manager.AutoTagRulePreview) (ret []*models.Scene, err error) {
+	var errs []error
+	ret, errs = loaders.From(ctx).SceneByID.LoadAll(obj.SceneIDs)
+	return ret, firstError(errs)
+}
+
+func (r *autoTagRulePreviewResolver) Images(ctx context.Context, obj *manager.AutoTagRulePreview) (ret []*models.Image, err error) {
+	var errs []error
+	ret, errs = loaders.From(ctx).ImageByID.LoadAll(obj.ImageIDs)
+	return ret, firstError(errs)
+}
+
+func (r *autoTagRulePreviewResolver) Galleries(ctx context.Context, obj *manager.AutoTagRulePreview) (ret []*models.Gallery, err error) {
+	var errs []error
+	ret, errs = loaders.From(ctx).GalleryByID.LoadAll(obj.GalleryIDs)
+	return ret, firstError(errs)
+}
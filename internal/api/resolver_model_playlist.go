@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/api/loaders"
+	"github.com/stashapp/stash/internal/api/urlbuilders"
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *playlistResolver) Items(ctx context.Context, obj *models.Playlist) (ret []*models.PlaylistItem, err error) {
+	var items []models.PlaylistItem
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		items, err = r.repository.Playlist.GetItems(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	ret = make([]*models.PlaylistItem, len(items))
+	for i := range items {
+		ret[i] = &items[i]
+	}
+
+	return ret, nil
+}
+
+func (r *playlistResolver) ItemCount(ctx context.Context, obj *models.Playlist) (ret int, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		items, err := r.repository.Playlist.GetItems(ctx, obj.ID)
+		ret = len(items)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	return ret, nil
+}
+
+func (r *playlistResolver) M3uPath(ctx context.Context, obj *models.Playlist) (string, error) {
+	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+	config := manager.GetInstance().Config
+	return urlbuilders.NewPlaylistURLBuilder(baseURL, obj).GetM3UURL(config.GetAPIKey()).String(), nil
+}
+
+func (r *playlistItemResolver) Scene(ctx context.Context, obj *models.PlaylistItem) (*models.Scene, error) {
+	if obj.SceneID == nil {
+		return nil, nil
+	}
+
+	return loaders.From(ctx).SceneByID.Load(*obj.SceneID)
+}
+
+func (r *playlistItemResolver) SceneMarker(ctx context.Context, obj *models.PlaylistItem) (ret *models.SceneMarker, err error) {
+	if obj.SceneMarkerID == nil {
+		return nil, nil
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.SceneMarker.Find(ctx, *obj.SceneMarkerID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *playlistItemResolver) Image(ctx context.Context, obj *models.PlaylistItem) (*models.Image, error) {
+	if obj.ImageID == nil {
+		return nil, nil
+	}
+
+	return loaders.From(ctx).ImageByID.Load(*obj.ImageID)
+}
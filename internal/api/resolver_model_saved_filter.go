@@ -9,3 +9,14 @@ import (
 func (r *savedFilterResolver) Filter(ctx context.Context, obj *models.SavedFilter) (string, error) {
 	return "", nil
 }
+
+func (r *savedFilterResolver) Count(ctx context.Context, obj *models.SavedFilter) (ret int, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = savedFilterCount(ctx, r.repository, obj)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	return ret, nil
+}
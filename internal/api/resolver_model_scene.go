@@ -252,6 +252,17 @@ func (r *sceneResolver) StashIds(ctx context.Context, obj *models.Scene) (ret []
 	return stashIDsSliceToPtrSlice(obj.StashIDs.List()), nil
 }
 
+func (r *sceneResolver) StashBoxSubmissions(ctx context.Context, obj *models.Scene) (ret []*models.StashBoxSubmission, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.StashBoxSubmission.FindBySceneID(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func (r *sceneResolver) SceneStreams(ctx context.Context, obj *models.Scene) ([]*manager.SceneStreamEndpoint, error) {
 	// load the primary file into the scene
 	_, err := r.getPrimaryFile(ctx, obj)
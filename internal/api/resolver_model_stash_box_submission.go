@@ -0,0 +1,24 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/api/loaders"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *stashBoxSubmissionResolver) Scene(ctx context.Context, obj *models.StashBoxSubmission) (*models.Scene, error) {
+	if obj.SceneID == nil {
+		return nil, nil
+	}
+
+	return loaders.From(ctx).SceneByID.Load(*obj.SceneID)
+}
+
+func (r *stashBoxSubmissionResolver) Performer(ctx context.Context, obj *models.StashBoxSubmission) (*models.Performer, error) {
+	if obj.PerformerID == nil {
+		return nil, nil
+	}
+
+	return loaders.From(ctx).PerformerByID.Load(*obj.PerformerID)
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/api/loaders"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *stashBoxUpdateResolver) Scene(ctx context.Context, obj *models.StashBoxUpdate) (*models.Scene, error) {
+	if obj.ObjectType != models.ChangeObjectTypeScene {
+		return nil, nil
+	}
+
+	return loaders.From(ctx).SceneByID.Load(obj.ObjectID)
+}
+
+func (r *stashBoxUpdateResolver) Performer(ctx context.Context, obj *models.StashBoxUpdate) (*models.Performer, error) {
+	if obj.ObjectType != models.ChangeObjectTypePerformer {
+		return nil, nil
+	}
+
+	return loaders.From(ctx).PerformerByID.Load(obj.ObjectID)
+}
+
+func (r *stashBoxUpdateResolver) Studio(ctx context.Context, obj *models.StashBoxUpdate) (*models.Studio, error) {
+	if obj.ObjectType != models.ChangeObjectTypeStudio {
+		return nil, nil
+	}
+
+	return loaders.From(ctx).StudioByID.Load(obj.ObjectID)
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/stashapp/stash/internal/autotag"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *mutationResolver) AutoTagRuleCreate(ctx context.Context, input AutoTagRuleCreateInput) (*models.AutoTagRule, error) {
+	newRule := models.NewAutoTagRule()
+	newRule.Name = input.Name
+	if input.Enabled != nil {
+		newRule.Enabled = *input.Enabled
+	}
+	if input.Conditions != nil {
+		newRule.Conditions = *input.Conditions
+	}
+	if input.Actions != nil {
+		newRule.Actions = *input.Actions
+	}
+
+	if err := autotag.ValidateRule(&newRule); err != nil {
+		return nil, err
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return r.repository.AutoTagRule.Create(ctx, &newRule)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &newRule, nil
+}
+
+func (r *mutationResolver) AutoTagRuleUpdate(ctx context.Context, input AutoTagRuleUpdateInput) (ret *models.AutoTagRule, err error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.AutoTagRule
+
+		ret, err = qb.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+		if ret == nil {
+			return fmt.Errorf("auto-tag rule with id %d not found", id)
+		}
+
+		if input.Name != nil {
+			ret.Name = *input.Name
+		}
+		if input.Enabled != nil {
+			ret.Enabled = *input.Enabled
+		}
+		if input.Conditions != nil {
+			ret.Conditions = *input.Conditions
+		}
+		if input.Actions != nil {
+			ret.Actions = *input.Actions
+		}
+		ret.UpdatedAt = time.Now()
+
+		if err := autotag.ValidateRule(ret); err != nil {
+			return err
+		}
+
+		return qb.Update(ctx, ret)
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) AutoTagRuleDestroy(ctx context.Context, id string) (bool, error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return false, fmt.Errorf("converting id: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return r.repository.AutoTagRule.Destroy(ctx, idInt)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
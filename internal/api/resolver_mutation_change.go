@@ -0,0 +1,237 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/plugin/hook"
+)
+
+func (r *mutationResolver) RevertChange(ctx context.Context, id string) (bool, error) {
+	changeID, err := strconv.Atoi(id)
+	if err != nil {
+		return false, fmt.Errorf("converting id: %w", err)
+	}
+
+	var change *models.Change
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		change, err = r.repository.Change.Find(ctx, changeID)
+		return err
+	}); err != nil {
+		return false, err
+	}
+
+	if change == nil {
+		return false, fmt.Errorf("change with id %d not found", changeID)
+	}
+
+	if err := r.revertChange(ctx, change); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// revertChange sets the changed field of the object back to its old value.
+// The object is updated with an update input containing only the reverted
+// field, in the same way as the object's update mutation.
+func (r *mutationResolver) revertChange(ctx context.Context, change *models.Change) error {
+	id := change.ObjectID
+
+	inputMap, err := changeUpdateInputMap(change)
+	if err != nil {
+		return err
+	}
+
+	translator := changesetTranslator{
+		inputMap: inputMap,
+	}
+
+	switch change.ObjectType {
+	case models.ChangeObjectTypeScene:
+		var input models.SceneUpdateInput
+		if err := decodeUpdateInputMap(inputMap, &input); err != nil {
+			return err
+		}
+
+		if err := r.withTxn(ctx, func(ctx context.Context) error {
+			if err := r.executePreHooks(ctx, id, hook.SceneUpdatePre, &input, &translator); err != nil {
+				return err
+			}
+
+			_, err := r.sceneUpdate(ctx, input, translator)
+			return err
+		}); err != nil {
+			return err
+		}
+
+		r.hookExecutor.ExecutePostHooks(ctx, id, hook.SceneUpdatePost, input, translator.getFields())
+	case models.ChangeObjectTypeImage:
+		var input ImageUpdateInput
+		if err := decodeUpdateInputMap(inputMap, &input); err != nil {
+			return err
+		}
+
+		if err := r.withTxn(ctx, func(ctx context.Context) error {
+			if err := r.executePreHooks(ctx, id, hook.ImageUpdatePre, &input, &translator); err != nil {
+				return err
+			}
+
+			_, err := r.imageUpdate(ctx, input, translator)
+			return err
+		}); err != nil {
+			return err
+		}
+
+		r.hookExecutor.ExecutePostHooks(ctx, id, hook.ImageUpdatePost, input, translator.getFields())
+	case models.ChangeObjectTypeGallery:
+		var input models.GalleryUpdateInput
+		if err := decodeUpdateInputMap(inputMap, &input); err != nil {
+			return err
+		}
+
+		if err := r.withTxn(ctx, func(ctx context.Context) error {
+			if err := r.executePreHooks(ctx, id, hook.GalleryUpdatePre, &input, &translator); err != nil {
+				return err
+			}
+
+			_, err := r.galleryUpdate(ctx, input, translator)
+			return err
+		}); err != nil {
+			return err
+		}
+
+		r.hookExecutor.ExecutePostHooks(ctx, id, hook.GalleryUpdatePost, input, translator.getFields())
+	case models.ChangeObjectTypePerformer:
+		var input models.PerformerUpdateInput
+		if err := decodeUpdateInputMap(inputMap, &input); err != nil {
+			return err
+		}
+
+		if err := r.withTxn(ctx, func(ctx context.Context) error {
+			if err := r.executePreHooks(ctx, id, hook.PerformerUpdatePre, &input, &translator); err != nil {
+				return err
+			}
+
+			return r.performerUpdate(ctx, id, input, translator)
+		}); err != nil {
+			return err
+		}
+
+		r.hookExecutor.ExecutePostHooks(ctx, id, hook.PerformerUpdatePost, input, translator.getFields())
+	case models.ChangeObjectTypeStudio:
+		var input models.StudioUpdateInput
+		if err := decodeUpdateInputMap(inputMap, &input); err != nil {
+			return err
+		}
+
+		if err := r.withTxn(ctx, func(ctx context.Context) error {
+			if err := r.executePreHooks(ctx, id, hook.StudioUpdatePre, &input, &translator); err != nil {
+				return err
+			}
+
+			return r.studioUpdate(ctx, id, input, translator)
+		}); err != nil {
+			return err
+		}
+
+		r.hookExecutor.ExecutePostHooks(ctx, id, hook.StudioUpdatePost, input, translator.getFields())
+	case models.ChangeObjectTypeMovie:
+		var input MovieUpdateInput
+		if err := decodeUpdateInputMap(inputMap, &input); err != nil {
+			return err
+		}
+
+		if err := r.withTxn(ctx, func(ctx context.Context) error {
+			if err := r.executePreHooks(ctx, id, hook.MovieUpdatePre, &input, &translator); err != nil {
+				return err
+			}
+
+			_, err := r.movieUpdate(ctx, id, input, translator)
+			return err
+		}); err != nil {
+			return err
+		}
+
+		r.hookExecutor.ExecutePostHooks(ctx, id, hook.MovieUpdatePost, input, translator.getFields())
+	default:
+		return fmt.Errorf("invalid object type %s", change.ObjectType)
+	}
+
+	return nil
+}
+
+// changeUpdateInputMap returns the update input map that sets the changed
+// field of the object back to its old value. Change fields are mapped to
+// their update input fields, and ids are converted to strings.
+func changeUpdateInputMap(change *models.Change) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(change.OldValue)))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("decoding value of %s: %w", change.Field, err)
+	}
+
+	field := change.Field
+	switch field {
+	case "rating":
+		field = "rating100"
+	case "studio_id", "parent_id":
+		value = changeIDToString(value)
+	case "gallery_ids", "scene_ids", "tag_ids", "performer_ids":
+		if ids, ok := value.([]interface{}); ok {
+			for i, v := range ids {
+				ids[i] = changeIDToString(v)
+			}
+		}
+	case "movies":
+		if movies, ok := value.([]interface{}); ok {
+			for _, m := range movies {
+				if mm, ok := m.(map[string]interface{}); ok {
+					mm["movie_id"] = changeIDToString(mm["movie_id"])
+				}
+			}
+		}
+	}
+
+	if change.ObjectType == models.ChangeObjectTypePerformer {
+		switch field {
+		case "height":
+			field = "height_cm"
+		case "aliases":
+			field = "alias_list"
+		}
+	}
+
+	return map[string]interface{}{
+		"id":  strconv.Itoa(change.ObjectID),
+		field: value,
+	}, nil
+}
+
+func changeIDToString(v interface{}) interface{} {
+	if n, ok := v.(json.Number); ok {
+		return n.String()
+	}
+	return v
+}
+
+// decodeUpdateInputMap decodes inputMap into the update input pointed to by
+// input.
+func decodeUpdateInputMap(inputMap map[string]interface{}, input interface{}) error {
+	data, err := json.Marshal(inputMap)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, input); err != nil {
+		return fmt.Errorf("decoding update input: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func TestChangeUpdateInputMap(t *testing.T) {
+	var (
+		title     = "Old title"
+		rating    = 60
+		studioID  = "3"
+		height    = 170
+		sceneIdx  = 2
+		emptyURLs = []string{}
+	)
+
+	tests := []struct {
+		name       string
+		change     models.Change
+		wantFields []string
+		want       interface{}
+	}{
+		{
+			"string",
+			models.Change{ObjectType: models.ChangeObjectTypeScene, ObjectID: 1, Field: "title", OldValue: `"Old title"`},
+			[]string{"id", "title"},
+			&models.SceneUpdateInput{ID: "1", Title: &title},
+		},
+		{
+			"null",
+			models.Change{ObjectType: models.ChangeObjectTypeScene, ObjectID: 1, Field: "title", OldValue: `null`},
+			[]string{"id", "title"},
+			&models.SceneUpdateInput{ID: "1"},
+		},
+		{
+			"rating",
+			models.Change{ObjectType: models.ChangeObjectTypeScene, ObjectID: 1, Field: "rating", OldValue: `60`},
+			[]string{"id", "rating100"},
+			&models.SceneUpdateInput{ID: "1", Rating100: &rating},
+		},
+		{
+			"studio id",
+			models.Change{ObjectType: models.ChangeObjectTypeScene, ObjectID: 1, Field: "studio_id", OldValue: `3`},
+			[]string{"id", "studio_id"},
+			&models.SceneUpdateInput{ID: "1", StudioID: &studioID},
+		},
+		{
+			"ids",
+			models.Change{ObjectType: models.ChangeObjectTypeScene, ObjectID: 1, Field: "tag_ids", OldValue: `[1,2]`},
+			[]string{"id", "tag_ids"},
+			&models.SceneUpdateInput{ID: "1", TagIds: []string{"1", "2"}},
+		},
+		{
+			"movies",
+			models.Change{ObjectType: models.ChangeObjectTypeScene, ObjectID: 1, Field: "movies", OldValue: `[{"movie_id":4,"scene_index":2}]`},
+			[]string{"id", "movies"},
+			&models.SceneUpdateInput{ID: "1", Movies: []models.SceneMovieInput{{MovieID: "4", SceneIndex: &sceneIdx}}},
+		},
+		{
+			"empty urls",
+			models.Change{ObjectType: models.ChangeObjectTypeScene, ObjectID: 1, Field: "urls", OldValue: `[]`},
+			[]string{"id", "urls"},
+			&models.SceneUpdateInput{ID: "1", Urls: emptyURLs},
+		},
+		{
+			"performer height",
+			models.Change{ObjectType: models.ChangeObjectTypePerformer, ObjectID: 1, Field: "height", OldValue: `170`},
+			[]string{"id", "height_cm"},
+			&models.PerformerUpdateInput{ID: "1", HeightCm: &height},
+		},
+		{
+			"performer aliases",
+			models.Change{ObjectType: models.ChangeObjectTypePerformer, ObjectID: 1, Field: "aliases", OldValue: `["a"]`},
+			[]string{"id", "alias_list"},
+			&models.PerformerUpdateInput{ID: "1", AliasList: []string{"a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputMap, err := changeUpdateInputMap(&tt.change)
+			if err != nil {
+				t.Errorf("changeUpdateInputMap() error = %v", err)
+				return
+			}
+
+			translator := changesetTranslator{inputMap: inputMap}
+			assert.ElementsMatch(t, tt.wantFields, translator.getFields())
+
+			var got interface{}
+			switch tt.want.(type) {
+			case *models.SceneUpdateInput:
+				got = &models.SceneUpdateInput{}
+			case *models.PerformerUpdateInput:
+				got = &models.PerformerUpdateInput{}
+			}
+
+			if err := decodeUpdateInputMap(inputMap, got); err != nil {
+				t.Errorf("decodeUpdateInputMap() error = %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
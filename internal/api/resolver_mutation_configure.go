@@ -149,6 +149,15 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 		c.SetString(config.BackupDirectoryPath, *input.BackupDirectoryPath)
 	}
 
+	if input.BackupInterval != nil && *input.BackupInterval < 0 {
+		return makeConfigGeneralResult(), errors.New("backup interval must not be negative")
+	}
+	r.setConfigInt(config.BackupInterval, input.BackupInterval)
+	r.setConfigInt(config.BackupKeepDaily, input.BackupKeepDaily)
+	r.setConfigInt(config.BackupKeepWeekly, input.BackupKeepWeekly)
+	r.setConfigBool(config.BackupCompress, input.BackupCompress)
+	r.setConfigBool(config.BackupIncludeBlobs, input.BackupIncludeBlobs)
+
 	existingGeneratedPath := c.GetGeneratedPath()
 	if input.GeneratedPath != nil && existingGeneratedPath != *input.GeneratedPath {
 		if err := validateDir(config.Generated, *input.GeneratedPath, false); err != nil {
@@ -437,6 +446,9 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 	if refreshPluginSource {
 		manager.GetInstance().RefreshPluginSourceManager()
 	}
+	if input.BackupInterval != nil {
+		manager.GetInstance().DatabaseBackups.Trigger()
+	}
 
 	return makeConfigGeneralResult(), nil
 }
@@ -526,6 +538,7 @@ func (r *mutationResolver) ConfigureDlna(ctx context.Context, input ConfigDLNAIn
 	}
 
 	r.setConfigString(config.DLNAVideoSortOrder, input.VideoSortOrder)
+	r.setConfigString(config.DLNAFilterQuery, input.FilterQuery)
 	r.setConfigInt(config.DLNAPort, input.Port)
 
 	refresh := false
@@ -573,6 +586,10 @@ func (r *mutationResolver) ConfigureScraping(ctx context.Context, input ConfigSc
 		c.SetInterface(config.ScraperExcludeTagPatterns, input.ExcludeTagPatterns)
 	}
 
+	if input.EmbeddedMetadataMappings != nil {
+		c.SetInterface(config.EmbeddedMetadataMappings, input.EmbeddedMetadataMappings.WithDefaults())
+	}
+
 	r.setConfigBool(config.ScraperCertCheck, input.ScraperCertCheck)
 
 	if refreshScraperCache {
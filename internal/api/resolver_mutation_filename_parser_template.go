@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+func (r *mutationResolver) FilenameParserTemplateCreate(ctx context.Context, input FilenameParserTemplateCreateInput) (*models.FilenameParserTemplate, error) {
+	newTemplate := models.NewFilenameParserTemplate()
+	newTemplate.Name = input.Name
+	newTemplate.Pattern = input.Pattern
+	newTemplate.Paths = input.Paths
+	if input.Options != nil {
+		newTemplate.Options = *input.Options
+	}
+	if input.Enabled != nil {
+		newTemplate.Enabled = *input.Enabled
+	}
+
+	if err := scene.ValidateFilenameParserTemplate(&newTemplate); err != nil {
+		return nil, err
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return r.repository.FilenameParserTemplate.Create(ctx, &newTemplate)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &newTemplate, nil
+}
+
+func (r *mutationResolver) FilenameParserTemplateUpdate(ctx context.Context, input FilenameParserTemplateUpdateInput) (ret *models.FilenameParserTemplate, err error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.FilenameParserTemplate
+
+		ret, err = qb.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+		if ret == nil {
+			return fmt.Errorf("filename parser template with id %d not found", id)
+		}
+
+		if input.Name != nil {
+			ret.Name = *input.Name
+		}
+		if input.Pattern != nil {
+			ret.Pattern = *input.Pattern
+		}
+		if input.Options != nil {
+			ret.Options = *input.Options
+		}
+		if input.Paths != nil {
+			ret.Paths = input.Paths
+		}
+		if input.Enabled != nil {
+			ret.Enabled = *input.Enabled
+		}
+		ret.UpdatedAt = time.Now()
+
+		if err := scene.ValidateFilenameParserTemplate(ret); err != nil {
+			return err
+		}
+
+		return qb.Update(ctx, ret)
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) FilenameParserTemplateDestroy(ctx context.Context, id string) (bool, error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return false, fmt.Errorf("converting id: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return r.repository.FilenameParserTemplate.Destroy(ctx, idInt)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) MetadataApplyFilenameParserTemplates(ctx context.Context, input manager.ApplyFilenameParserTemplatesInput) (string, error) {
+	jobID, err := manager.GetInstance().ApplyFilenameParserTemplates(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
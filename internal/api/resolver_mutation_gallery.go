@@ -31,56 +31,61 @@ func (r *mutationResolver) getGallery(ctx context.Context, id int) (ret *models.
 }
 
 func (r *mutationResolver) GalleryCreate(ctx context.Context, input GalleryCreateInput) (*models.Gallery, error) {
-	// name must be provided
-	if input.Title == "" {
-		return nil, errors.New("title must not be empty")
-	}
-
 	translator := changesetTranslator{
 		inputMap: getUpdateInputMap(ctx),
 	}
 
-	// Populate a new gallery from the input
-	newGallery := models.NewGallery()
+	// Start the transaction and save the gallery
+	var newGallery models.Gallery
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, 0, hook.GalleryCreatePre, &input, &translator); err != nil {
+			return err
+		}
 
-	newGallery.Title = input.Title
-	newGallery.Code = translator.string(input.Code)
-	newGallery.Details = translator.string(input.Details)
-	newGallery.Photographer = translator.string(input.Photographer)
-	newGallery.Rating = input.Rating100
+		// name must be provided
+		if input.Title == "" {
+			return errors.New("title must not be empty")
+		}
 
-	var err error
+		// Populate a new gallery from the input
+		newGallery = models.NewGallery()
 
-	newGallery.Date, err = translator.datePtr(input.Date)
-	if err != nil {
-		return nil, fmt.Errorf("converting date: %w", err)
-	}
-	newGallery.StudioID, err = translator.intPtrFromString(input.StudioID)
-	if err != nil {
-		return nil, fmt.Errorf("converting studio id: %w", err)
-	}
+		newGallery.Title = input.Title
+		newGallery.Code = translator.string(input.Code)
+		newGallery.Details = translator.string(input.Details)
+		newGallery.Photographer = translator.string(input.Photographer)
+		newGallery.Rating = input.Rating100
 
-	newGallery.PerformerIDs, err = translator.relatedIds(input.PerformerIds)
-	if err != nil {
-		return nil, fmt.Errorf("converting performer ids: %w", err)
-	}
-	newGallery.TagIDs, err = translator.relatedIds(input.TagIds)
-	if err != nil {
-		return nil, fmt.Errorf("converting tag ids: %w", err)
-	}
-	newGallery.SceneIDs, err = translator.relatedIds(input.SceneIds)
-	if err != nil {
-		return nil, fmt.Errorf("converting scene ids: %w", err)
-	}
+		var err error
 
-	if input.Urls != nil {
-		newGallery.URLs = models.NewRelatedStrings(input.Urls)
-	} else if input.URL != nil {
-		newGallery.URLs = models.NewRelatedStrings([]string{*input.URL})
-	}
+		newGallery.Date, err = translator.datePtr(input.Date)
+		if err != nil {
+			return fmt.Errorf("converting date: %w", err)
+		}
+		newGallery.StudioID, err = translator.intPtrFromString(input.StudioID)
+		if err != nil {
+			return fmt.Errorf("converting studio id: %w", err)
+		}
+
+		newGallery.PerformerIDs, err = translator.relatedIds(input.PerformerIds)
+		if err != nil {
+			return fmt.Errorf("converting performer ids: %w", err)
+		}
+		newGallery.TagIDs, err = translator.relatedIds(input.TagIds)
+		if err != nil {
+			return fmt.Errorf("converting tag ids: %w", err)
+		}
+		newGallery.SceneIDs, err = translator.relatedIds(input.SceneIds)
+		if err != nil {
+			return fmt.Errorf("converting scene ids: %w", err)
+		}
+
+		if input.Urls != nil {
+			newGallery.URLs = models.NewRelatedStrings(input.Urls)
+		} else if input.URL != nil {
+			newGallery.URLs = models.NewRelatedStrings([]string{*input.URL})
+		}
 
-	// Start the transaction and save the gallery
-	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Gallery
 		if err := qb.Create(ctx, &newGallery, nil); err != nil {
 			return err
@@ -96,12 +101,21 @@ func (r *mutationResolver) GalleryCreate(ctx context.Context, input GalleryCreat
 }
 
 func (r *mutationResolver) GalleryUpdate(ctx context.Context, input models.GalleryUpdateInput) (ret *models.Gallery, err error) {
+	galleryID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
 	translator := changesetTranslator{
 		inputMap: getUpdateInputMap(ctx),
 	}
 
 	// Start the transaction and save the gallery
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, galleryID, hook.GalleryUpdatePre, &input, &translator); err != nil {
+			return err
+		}
+
 		ret, err = r.galleryUpdate(ctx, input, translator)
 		return err
 	}); err != nil {
@@ -123,6 +137,15 @@ func (r *mutationResolver) GalleriesUpdate(ctx context.Context, input []*models.
 				inputMap: inputMaps[i],
 			}
 
+			galleryID, err := strconv.Atoi(gallery.ID)
+			if err != nil {
+				return fmt.Errorf("converting id: %w", err)
+			}
+
+			if err := r.executePreHooks(ctx, galleryID, hook.GalleryUpdatePre, gallery, &translator); err != nil {
+				return err
+			}
+
 			thisGallery, err := r.galleryUpdate(ctx, *gallery, translator)
 			if err != nil {
 				return err
@@ -298,6 +321,11 @@ func (r *mutationResolver) BulkGalleryUpdate(ctx context.Context, input BulkGall
 		qb := r.repository.Gallery
 
 		for _, galleryID := range galleryIDs {
+			// output is ignored, since the input applies to all of the objects
+			if err := r.executePreHooks(ctx, galleryID, hook.GalleryUpdatePre, input, &translator); err != nil {
+				return err
+			}
+
 			gallery, err := qb.UpdatePartial(ctx, galleryID, updatedGallery)
 			if err != nil {
 				return err
@@ -360,6 +388,14 @@ func (r *mutationResolver) GalleryDestroy(ctx context.Context, input models.Gall
 				return fmt.Errorf("loading files for gallery %d", id)
 			}
 
+			if err := r.executePreHooks(ctx, gallery.ID, hook.GalleryDestroyPre, plugin.GalleryDestroyInput{
+				GalleryDestroyInput: input,
+				Checksum:            gallery.PrimaryChecksum(),
+				Path:                gallery.Path,
+			}, nil); err != nil {
+				return err
+			}
+
 			galleries = append(galleries, gallery)
 
 			imgsDestroyed, err = r.galleryService.Destroy(ctx, gallery, fileDeleter, deleteGenerated, deleteFile)
@@ -490,20 +526,25 @@ func (r *mutationResolver) getGalleryChapter(ctx context.Context, id int) (ret *
 }
 
 func (r *mutationResolver) GalleryChapterCreate(ctx context.Context, input GalleryChapterCreateInput) (*models.GalleryChapter, error) {
-	galleryID, err := strconv.Atoi(input.GalleryID)
-	if err != nil {
-		return nil, fmt.Errorf("converting gallery id: %w", err)
-	}
+	// Start the transaction and save the gallery chapter
+	var newChapter models.GalleryChapter
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, 0, hook.GalleryChapterCreatePre, &input, nil); err != nil {
+			return err
+		}
 
-	// Populate a new gallery chapter from the input
-	newChapter := models.NewGalleryChapter()
+		galleryID, err := strconv.Atoi(input.GalleryID)
+		if err != nil {
+			return fmt.Errorf("converting gallery id: %w", err)
+		}
 
-	newChapter.Title = input.Title
-	newChapter.ImageIndex = input.ImageIndex
-	newChapter.GalleryID = galleryID
+		// Populate a new gallery chapter from the input
+		newChapter = models.NewGalleryChapter()
+
+		newChapter.Title = input.Title
+		newChapter.ImageIndex = input.ImageIndex
+		newChapter.GalleryID = galleryID
 
-	// Start the transaction and save the gallery chapter
-	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		imageCount, err := r.repository.Image.CountByGalleryID(ctx, galleryID)
 		if err != nil {
 			return err
@@ -533,18 +574,22 @@ func (r *mutationResolver) GalleryChapterUpdate(ctx context.Context, input Galle
 		inputMap: getUpdateInputMap(ctx),
 	}
 
-	// Populate gallery chapter from the input
-	updatedChapter := models.NewGalleryChapterPartial()
-
-	updatedChapter.Title = translator.optionalString(input.Title, "title")
-	updatedChapter.ImageIndex = translator.optionalInt(input.ImageIndex, "image_index")
-	updatedChapter.GalleryID, err = translator.optionalIntFromString(input.GalleryID, "gallery_id")
-	if err != nil {
-		return nil, fmt.Errorf("converting gallery id: %w", err)
-	}
-
 	// Start the transaction and save the gallery chapter
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, chapterID, hook.GalleryChapterUpdatePre, &input, &translator); err != nil {
+			return err
+		}
+
+		// Populate gallery chapter from the input
+		updatedChapter := models.NewGalleryChapterPartial()
+
+		updatedChapter.Title = translator.optionalString(input.Title, "title")
+		updatedChapter.ImageIndex = translator.optionalInt(input.ImageIndex, "image_index")
+		updatedChapter.GalleryID, err = translator.optionalIntFromString(input.GalleryID, "gallery_id")
+		if err != nil {
+			return fmt.Errorf("converting gallery id: %w", err)
+		}
+
 		qb := r.repository.GalleryChapter
 
 		existingChapter, err := qb.Find(ctx, chapterID)
@@ -608,6 +653,10 @@ func (r *mutationResolver) GalleryChapterDestroy(ctx context.Context, id string)
 			return fmt.Errorf("gallery chapter with id %d not found", chapterID)
 		}
 
+		if err := r.executePreHooks(ctx, chapterID, hook.GalleryChapterDestroyPre, id, nil); err != nil {
+			return err
+		}
+
 		return gallery.DestroyChapter(ctx, chapter, qb)
 	}); err != nil {
 		return false, err
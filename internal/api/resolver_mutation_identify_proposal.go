@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+func (r *mutationResolver) AcceptIdentifyProposals(ctx context.Context, ids []string) (string, error) {
+	proposalIDs, err := stringslice.StringSliceToIntSlice(ids)
+	if err != nil {
+		return "", fmt.Errorf("converting ids: %w", err)
+	}
+
+	t := &manager.AcceptIdentifyProposalsJob{
+		ProposalIDs: proposalIDs,
+	}
+	jobID := manager.GetInstance().JobManager.Add(ctx, "Applying identify proposals...", t)
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) RejectIdentifyProposals(ctx context.Context, ids []string) (bool, error) {
+	proposalIDs, err := stringslice.StringSliceToIntSlice(ids)
+	if err != nil {
+		return false, fmt.Errorf("converting ids: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.IdentifyProposal
+
+		proposals, err := qb.FindMany(ctx, proposalIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range proposals {
+			if p.Status != models.IdentifyProposalStatusPending {
+				return fmt.Errorf("proposal %d is %s", p.ID, p.Status)
+			}
+
+			if err := qb.UpdateStatus(ctx, p.ID, models.IdentifyProposalStatusRejected); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -29,12 +29,21 @@ func (r *mutationResolver) getImage(ctx context.Context, id int) (ret *models.Im
 }
 
 func (r *mutationResolver) ImageUpdate(ctx context.Context, input ImageUpdateInput) (ret *models.Image, err error) {
+	imageID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
 	translator := changesetTranslator{
 		inputMap: getUpdateInputMap(ctx),
 	}
 
 	// Start the transaction and save the image
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, imageID, hook.ImageUpdatePre, &input, &translator); err != nil {
+			return err
+		}
+
 		ret, err = r.imageUpdate(ctx, input, translator)
 		return err
 	}); err != nil {
@@ -56,6 +65,15 @@ func (r *mutationResolver) ImagesUpdate(ctx context.Context, input []*ImageUpdat
 				inputMap: inputMaps[i],
 			}
 
+			imageID, err := strconv.Atoi(image.ID)
+			if err != nil {
+				return fmt.Errorf("converting id: %w", err)
+			}
+
+			if err := r.executePreHooks(ctx, imageID, hook.ImageUpdatePre, image, &translator); err != nil {
+				return err
+			}
+
 			thisImage, err := r.imageUpdate(ctx, *image, translator)
 			if err != nil {
 				return err
@@ -243,6 +261,11 @@ func (r *mutationResolver) BulkImageUpdate(ctx context.Context, input BulkImageU
 		qb := r.repository.Image
 
 		for _, imageID := range imageIDs {
+			// output is ignored, since the input applies to all of the objects
+			if err := r.executePreHooks(ctx, imageID, hook.ImageUpdatePre, input, &translator); err != nil {
+				return err
+			}
+
 			i, err := r.repository.Image.Find(ctx, imageID)
 			if err != nil {
 				return err
@@ -323,6 +346,14 @@ func (r *mutationResolver) ImageDestroy(ctx context.Context, input models.ImageD
 			return fmt.Errorf("image with id %d not found", imageID)
 		}
 
+		if err := r.executePreHooks(ctx, i.ID, hook.ImageDestroyPre, plugin.ImageDestroyInput{
+			ImageDestroyInput: input,
+			Checksum:          i.Checksum,
+			Path:              i.Path,
+		}, nil); err != nil {
+			return err
+		}
+
 		return r.imageService.Destroy(ctx, i, fileDeleter, utils.IsTrue(input.DeleteGenerated), utils.IsTrue(input.DeleteFile))
 	}); err != nil {
 		fileDeleter.Rollback()
@@ -366,6 +397,14 @@ func (r *mutationResolver) ImagesDestroy(ctx context.Context, input models.Image
 				return fmt.Errorf("image with id %d not found", imageID)
 			}
 
+			if err := r.executePreHooks(ctx, i.ID, hook.ImageDestroyPre, plugin.ImagesDestroyInput{
+				ImagesDestroyInput: input,
+				Checksum:           i.Checksum,
+				Path:               i.Path,
+			}, nil); err != nil {
+				return err
+			}
+
 			images = append(images, i)
 
 			if err := r.imageService.Destroy(ctx, i, fileDeleter, utils.IsTrue(input.DeleteGenerated), utils.IsTrue(input.DeleteFile)); err != nil {
@@ -53,6 +53,15 @@ func (r *mutationResolver) MetadataExport(ctx context.Context) (string, error) {
 	return strconv.Itoa(jobID), nil
 }
 
+func (r *mutationResolver) MetadataExportSidecars(ctx context.Context, input manager.ExportSidecarsInput) (string, error) {
+	jobID, err := manager.GetInstance().ExportSidecars(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
 func (r *mutationResolver) ExportObjects(ctx context.Context, input manager.ExportObjectsInput) (*string, error) {
 	t := manager.CreateExportTask(config.GetInstance().GetVideoFileNamingAlgorithm(), input)
 
@@ -148,6 +157,15 @@ func (r *mutationResolver) BackupDatabase(ctx context.Context, input BackupDatab
 	return nil, nil
 }
 
+func (r *mutationResolver) RestoreDatabase(ctx context.Context, input RestoreDatabaseInput) (bool, error) {
+	if err := manager.GetInstance().RestoreDatabase(input.Name); err != nil {
+		logger.Errorf("Error restoring database: %v", err)
+		return false, err
+	}
+
+	return true, nil
+}
+
 func (r *mutationResolver) AnonymiseDatabase(ctx context.Context, input AnonymiseDatabaseInput) (*string, error) {
 	// if download is true, then save to temporary file and return a link
 	download := input.Download != nil && *input.Download
@@ -29,41 +29,10 @@ func (r *mutationResolver) MovieCreate(ctx context.Context, input MovieCreateInp
 		inputMap: getUpdateInputMap(ctx),
 	}
 
-	// Populate a new movie from the input
-	newMovie := models.NewMovie()
-
-	newMovie.Name = input.Name
-	newMovie.Aliases = translator.string(input.Aliases)
-	newMovie.Duration = input.Duration
-	newMovie.Rating = input.Rating100
-	newMovie.Director = translator.string(input.Director)
-	newMovie.Synopsis = translator.string(input.Synopsis)
-
-	var err error
-
-	newMovie.Date, err = translator.datePtr(input.Date)
-	if err != nil {
-		return nil, fmt.Errorf("converting date: %w", err)
-	}
-	newMovie.StudioID, err = translator.intPtrFromString(input.StudioID)
-	if err != nil {
-		return nil, fmt.Errorf("converting studio id: %w", err)
-	}
-
-	newMovie.TagIDs, err = translator.relatedIds(input.TagIds)
-	if err != nil {
-		return nil, fmt.Errorf("converting tag ids: %w", err)
-	}
-
-	if input.Urls != nil {
-		newMovie.URLs = models.NewRelatedStrings(input.Urls)
-	} else if input.URL != nil {
-		newMovie.URLs = models.NewRelatedStrings([]string{*input.URL})
-	}
-
 	// Process the base 64 encoded image string
 	var frontimageData []byte
 	if input.FrontImage != nil {
+		var err error
 		frontimageData, err = utils.ProcessImageInput(ctx, *input.FrontImage)
 		if err != nil {
 			return nil, fmt.Errorf("processing front image: %w", err)
@@ -73,20 +42,58 @@ func (r *mutationResolver) MovieCreate(ctx context.Context, input MovieCreateInp
 	// Process the base 64 encoded image string
 	var backimageData []byte
 	if input.BackImage != nil {
+		var err error
 		backimageData, err = utils.ProcessImageInput(ctx, *input.BackImage)
 		if err != nil {
 			return nil, fmt.Errorf("processing back image: %w", err)
 		}
 	}
 
-	// HACK: if back image is being set, set the front image to the default.
-	// This is because we can't have a null front image with a non-null back image.
-	if len(frontimageData) == 0 && len(backimageData) != 0 {
-		frontimageData = static.ReadAll(static.DefaultMovieImage)
-	}
-
 	// Start the transaction and save the movie
+	var newMovie models.Movie
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, 0, hook.MovieCreatePre, &input, &translator); err != nil {
+			return err
+		}
+
+		// Populate a new movie from the input
+		newMovie = models.NewMovie()
+
+		newMovie.Name = input.Name
+		newMovie.Aliases = translator.string(input.Aliases)
+		newMovie.Duration = input.Duration
+		newMovie.Rating = input.Rating100
+		newMovie.Director = translator.string(input.Director)
+		newMovie.Synopsis = translator.string(input.Synopsis)
+
+		var err error
+
+		newMovie.Date, err = translator.datePtr(input.Date)
+		if err != nil {
+			return fmt.Errorf("converting date: %w", err)
+		}
+		newMovie.StudioID, err = translator.intPtrFromString(input.StudioID)
+		if err != nil {
+			return fmt.Errorf("converting studio id: %w", err)
+		}
+
+		newMovie.TagIDs, err = translator.relatedIds(input.TagIds)
+		if err != nil {
+			return fmt.Errorf("converting tag ids: %w", err)
+		}
+
+		if input.Urls != nil {
+			newMovie.URLs = models.NewRelatedStrings(input.Urls)
+		} else if input.URL != nil {
+			newMovie.URLs = models.NewRelatedStrings([]string{*input.URL})
+		}
+
+		// HACK: if back image is being set, set the front image to the default.
+		// This is because we can't have a null front image with a non-null back image.
+		if len(frontimageData) == 0 && len(backimageData) != 0 {
+			frontimageData = static.ReadAll(static.DefaultMovieImage)
+		}
+
 		qb := r.repository.Movie
 
 		err = qb.Create(ctx, &newMovie)
@@ -126,32 +133,6 @@ func (r *mutationResolver) MovieUpdate(ctx context.Context, input MovieUpdateInp
 		inputMap: getUpdateInputMap(ctx),
 	}
 
-	// Populate movie from the input
-	updatedMovie := models.NewMoviePartial()
-
-	updatedMovie.Name = translator.optionalString(input.Name, "name")
-	updatedMovie.Aliases = translator.optionalString(input.Aliases, "aliases")
-	updatedMovie.Duration = translator.optionalInt(input.Duration, "duration")
-	updatedMovie.Rating = translator.optionalInt(input.Rating100, "rating100")
-	updatedMovie.Director = translator.optionalString(input.Director, "director")
-	updatedMovie.Synopsis = translator.optionalString(input.Synopsis, "synopsis")
-
-	updatedMovie.Date, err = translator.optionalDate(input.Date, "date")
-	if err != nil {
-		return nil, fmt.Errorf("converting date: %w", err)
-	}
-	updatedMovie.StudioID, err = translator.optionalIntFromString(input.StudioID, "studio_id")
-	if err != nil {
-		return nil, fmt.Errorf("converting studio id: %w", err)
-	}
-
-	updatedMovie.TagIDs, err = translator.updateIds(input.TagIds, "tag_ids")
-	if err != nil {
-		return nil, fmt.Errorf("converting tag ids: %w", err)
-	}
-
-	updatedMovie.URLs = translator.optionalURLs(input.Urls, input.URL)
-
 	var frontimageData []byte
 	frontImageIncluded := translator.hasField("front_image")
 	if input.FrontImage != nil {
@@ -173,12 +154,17 @@ func (r *mutationResolver) MovieUpdate(ctx context.Context, input MovieUpdateInp
 	// Start the transaction and save the movie
 	var movie *models.Movie
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
-		qb := r.repository.Movie
-		movie, err = qb.UpdatePartial(ctx, movieID, updatedMovie)
+		if err := r.executePreHooks(ctx, movieID, hook.MovieUpdatePre, &input, &translator); err != nil {
+			return err
+		}
+
+		movie, err = r.movieUpdate(ctx, movieID, input, translator)
 		if err != nil {
 			return err
 		}
 
+		qb := r.repository.Movie
+
 		// update image table
 		if frontImageIncluded {
 			if err := qb.UpdateFrontImage(ctx, movie.ID, frontimageData); err != nil {
@@ -201,6 +187,39 @@ func (r *mutationResolver) MovieUpdate(ctx context.Context, input MovieUpdateInp
 	return r.getMovie(ctx, movie.ID)
 }
 
+// movieUpdate updates the movie from the input, excluding the images.
+func (r *mutationResolver) movieUpdate(ctx context.Context, movieID int, input MovieUpdateInput, translator changesetTranslator) (*models.Movie, error) {
+	var err error
+
+	// Populate movie from the input
+	updatedMovie := models.NewMoviePartial()
+
+	updatedMovie.Name = translator.optionalString(input.Name, "name")
+	updatedMovie.Aliases = translator.optionalString(input.Aliases, "aliases")
+	updatedMovie.Duration = translator.optionalInt(input.Duration, "duration")
+	updatedMovie.Rating = translator.optionalInt(input.Rating100, "rating100")
+	updatedMovie.Director = translator.optionalString(input.Director, "director")
+	updatedMovie.Synopsis = translator.optionalString(input.Synopsis, "synopsis")
+
+	updatedMovie.Date, err = translator.optionalDate(input.Date, "date")
+	if err != nil {
+		return nil, fmt.Errorf("converting date: %w", err)
+	}
+	updatedMovie.StudioID, err = translator.optionalIntFromString(input.StudioID, "studio_id")
+	if err != nil {
+		return nil, fmt.Errorf("converting studio id: %w", err)
+	}
+
+	updatedMovie.TagIDs, err = translator.updateIds(input.TagIds, "tag_ids")
+	if err != nil {
+		return nil, fmt.Errorf("converting tag ids: %w", err)
+	}
+
+	updatedMovie.URLs = translator.optionalURLs(input.Urls, input.URL)
+
+	return r.repository.Movie.UpdatePartial(ctx, movieID, updatedMovie)
+}
+
 func (r *mutationResolver) BulkMovieUpdate(ctx context.Context, input BulkMovieUpdateInput) ([]*models.Movie, error) {
 	movieIDs, err := stringslice.StringSliceToIntSlice(input.Ids)
 	if err != nil {
@@ -235,6 +254,11 @@ func (r *mutationResolver) BulkMovieUpdate(ctx context.Context, input BulkMovieU
 		qb := r.repository.Movie
 
 		for _, movieID := range movieIDs {
+			// output is ignored, since the input applies to all of the objects
+			if err := r.executePreHooks(ctx, movieID, hook.MovieUpdatePre, input, &translator); err != nil {
+				return err
+			}
+
 			movie, err := qb.UpdatePartial(ctx, movieID, updatedMovie)
 			if err != nil {
 				return err
@@ -270,6 +294,10 @@ func (r *mutationResolver) MovieDestroy(ctx context.Context, input MovieDestroyI
 	}
 
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, id, hook.MovieDestroyPre, input, nil); err != nil {
+			return err
+		}
+
 		return r.repository.Movie.Destroy(ctx, id)
 	}); err != nil {
 		return false, err
@@ -289,6 +317,10 @@ func (r *mutationResolver) MoviesDestroy(ctx context.Context, movieIDs []string)
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Movie
 		for _, id := range ids {
+			if err := r.executePreHooks(ctx, id, hook.MovieDestroyPre, movieIDs, nil); err != nil {
+				return err
+			}
+
 			if err := qb.Destroy(ctx, id); err != nil {
 				return err
 			}
@@ -34,66 +34,10 @@ func (r *mutationResolver) PerformerCreate(ctx context.Context, input models.Per
 		inputMap: getUpdateInputMap(ctx),
 	}
 
-	// Populate a new performer from the input
-	newPerformer := models.NewPerformer()
-
-	newPerformer.Name = input.Name
-	newPerformer.Disambiguation = translator.string(input.Disambiguation)
-	newPerformer.Aliases = models.NewRelatedStrings(input.AliasList)
-	newPerformer.Gender = input.Gender
-	newPerformer.Ethnicity = translator.string(input.Ethnicity)
-	newPerformer.Country = translator.string(input.Country)
-	newPerformer.EyeColor = translator.string(input.EyeColor)
-	newPerformer.Measurements = translator.string(input.Measurements)
-	newPerformer.FakeTits = translator.string(input.FakeTits)
-	newPerformer.PenisLength = input.PenisLength
-	newPerformer.Circumcised = input.Circumcised
-	newPerformer.CareerLength = translator.string(input.CareerLength)
-	newPerformer.Tattoos = translator.string(input.Tattoos)
-	newPerformer.Piercings = translator.string(input.Piercings)
-	newPerformer.Favorite = translator.bool(input.Favorite)
-	newPerformer.Rating = input.Rating100
-	newPerformer.Details = translator.string(input.Details)
-	newPerformer.HairColor = translator.string(input.HairColor)
-	newPerformer.Height = input.HeightCm
-	newPerformer.Weight = input.Weight
-	newPerformer.IgnoreAutoTag = translator.bool(input.IgnoreAutoTag)
-	newPerformer.StashIDs = models.NewRelatedStashIDs(input.StashIds)
-
-	newPerformer.URLs = models.NewRelatedStrings([]string{})
-	if input.URL != nil {
-		newPerformer.URLs.Add(*input.URL)
-	}
-	if input.Twitter != nil {
-		newPerformer.URLs.Add(utils.URLFromHandle(*input.Twitter, twitterURL))
-	}
-	if input.Instagram != nil {
-		newPerformer.URLs.Add(utils.URLFromHandle(*input.Instagram, instagramURL))
-	}
-
-	if input.Urls != nil {
-		newPerformer.URLs.Add(input.Urls...)
-	}
-
-	var err error
-
-	newPerformer.Birthdate, err = translator.datePtr(input.Birthdate)
-	if err != nil {
-		return nil, fmt.Errorf("converting birthdate: %w", err)
-	}
-	newPerformer.DeathDate, err = translator.datePtr(input.DeathDate)
-	if err != nil {
-		return nil, fmt.Errorf("converting death date: %w", err)
-	}
-
-	newPerformer.TagIDs, err = translator.relatedIds(input.TagIds)
-	if err != nil {
-		return nil, fmt.Errorf("converting tag ids: %w", err)
-	}
-
 	// Process the base 64 encoded image string
 	var imageData []byte
 	if input.Image != nil {
+		var err error
 		imageData, err = utils.ProcessImageInput(ctx, *input.Image)
 		if err != nil {
 			return nil, fmt.Errorf("processing image: %w", err)
@@ -101,7 +45,69 @@ func (r *mutationResolver) PerformerCreate(ctx context.Context, input models.Per
 	}
 
 	// Start the transaction and save the performer
+	var newPerformer models.Performer
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, 0, hook.PerformerCreatePre, &input, &translator); err != nil {
+			return err
+		}
+
+		// Populate a new performer from the input
+		newPerformer = models.NewPerformer()
+
+		newPerformer.Name = input.Name
+		newPerformer.Disambiguation = translator.string(input.Disambiguation)
+		newPerformer.Aliases = models.NewRelatedStrings(input.AliasList)
+		newPerformer.Gender = input.Gender
+		newPerformer.Ethnicity = translator.string(input.Ethnicity)
+		newPerformer.Country = translator.string(input.Country)
+		newPerformer.EyeColor = translator.string(input.EyeColor)
+		newPerformer.Measurements = translator.string(input.Measurements)
+		newPerformer.FakeTits = translator.string(input.FakeTits)
+		newPerformer.PenisLength = input.PenisLength
+		newPerformer.Circumcised = input.Circumcised
+		newPerformer.CareerLength = translator.string(input.CareerLength)
+		newPerformer.Tattoos = translator.string(input.Tattoos)
+		newPerformer.Piercings = translator.string(input.Piercings)
+		newPerformer.Favorite = translator.bool(input.Favorite)
+		newPerformer.Rating = input.Rating100
+		newPerformer.Details = translator.string(input.Details)
+		newPerformer.HairColor = translator.string(input.HairColor)
+		newPerformer.Height = input.HeightCm
+		newPerformer.Weight = input.Weight
+		newPerformer.IgnoreAutoTag = translator.bool(input.IgnoreAutoTag)
+		newPerformer.StashIDs = models.NewRelatedStashIDs(input.StashIds)
+
+		newPerformer.URLs = models.NewRelatedStrings([]string{})
+		if input.URL != nil {
+			newPerformer.URLs.Add(*input.URL)
+		}
+		if input.Twitter != nil {
+			newPerformer.URLs.Add(utils.URLFromHandle(*input.Twitter, twitterURL))
+		}
+		if input.Instagram != nil {
+			newPerformer.URLs.Add(utils.URLFromHandle(*input.Instagram, instagramURL))
+		}
+
+		if input.Urls != nil {
+			newPerformer.URLs.Add(input.Urls...)
+		}
+
+		var err error
+
+		newPerformer.Birthdate, err = translator.datePtr(input.Birthdate)
+		if err != nil {
+			return fmt.Errorf("converting birthdate: %w", err)
+		}
+		newPerformer.DeathDate, err = translator.datePtr(input.DeathDate)
+		if err != nil {
+			return fmt.Errorf("converting death date: %w", err)
+		}
+
+		newPerformer.TagIDs, err = translator.relatedIds(input.TagIds)
+		if err != nil {
+			return fmt.Errorf("converting tag ids: %w", err)
+		}
+
 		qb := r.repository.Performer
 
 		if err := performer.ValidateCreate(ctx, newPerformer, qb); err != nil {
@@ -229,6 +235,45 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.Per
 		inputMap: getUpdateInputMap(ctx),
 	}
 
+	var imageData []byte
+	imageIncluded := translator.hasField("image")
+	if input.Image != nil {
+		imageData, err = utils.ProcessImageInput(ctx, *input.Image)
+		if err != nil {
+			return nil, fmt.Errorf("processing image: %w", err)
+		}
+	}
+
+	// Start the transaction and save the performer
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, performerID, hook.PerformerUpdatePre, &input, &translator); err != nil {
+			return err
+		}
+
+		if err := r.performerUpdate(ctx, performerID, input, translator); err != nil {
+			return err
+		}
+
+		// update image table
+		if imageIncluded {
+			if err := r.repository.Performer.UpdateImage(ctx, performerID, imageData); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	r.hookExecutor.ExecutePostHooks(ctx, performerID, hook.PerformerUpdatePost, input, translator.getFields())
+	return r.getPerformer(ctx, performerID)
+}
+
+// performerUpdate updates the performer from the input, excluding the image.
+func (r *mutationResolver) performerUpdate(ctx context.Context, performerID int, input models.PerformerUpdateInput, translator changesetTranslator) error {
+	var err error
+
 	// Populate performer from the input
 	updatedPerformer := models.NewPerformerPartial()
 
@@ -256,7 +301,7 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.Per
 	if translator.hasField("urls") {
 		// ensure url/twitter/instagram are not included in the input
 		if err := r.validateNoLegacyURLs(translator); err != nil {
-			return nil, err
+			return err
 		}
 
 		updatedPerformer.URLs = translator.updateStrings(input.Urls, "urls")
@@ -268,11 +313,11 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.Per
 
 	updatedPerformer.Birthdate, err = translator.optionalDate(input.Birthdate, "birthdate")
 	if err != nil {
-		return nil, fmt.Errorf("converting birthdate: %w", err)
+		return fmt.Errorf("converting birthdate: %w", err)
 	}
 	updatedPerformer.DeathDate, err = translator.optionalDate(input.DeathDate, "death_date")
 	if err != nil {
-		return nil, fmt.Errorf("converting death date: %w", err)
+		return fmt.Errorf("converting death date: %w", err)
 	}
 
 	// prefer height_cm over height
@@ -287,51 +332,23 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.Per
 
 	updatedPerformer.TagIDs, err = translator.updateIds(input.TagIds, "tag_ids")
 	if err != nil {
-		return nil, fmt.Errorf("converting tag ids: %w", err)
+		return fmt.Errorf("converting tag ids: %w", err)
 	}
 
-	var imageData []byte
-	imageIncluded := translator.hasField("image")
-	if input.Image != nil {
-		imageData, err = utils.ProcessImageInput(ctx, *input.Image)
-		if err != nil {
-			return nil, fmt.Errorf("processing image: %w", err)
-		}
-	}
-
-	// Start the transaction and save the performer
-	if err := r.withTxn(ctx, func(ctx context.Context) error {
-		qb := r.repository.Performer
-
-		if legacyURL.Set || legacyTwitter.Set || legacyInstagram.Set {
-			if err := r.handleLegacyURLs(ctx, performerID, legacyURL, legacyTwitter, legacyInstagram, &updatedPerformer); err != nil {
-				return err
-			}
-		}
-
-		if err := performer.ValidateUpdate(ctx, performerID, updatedPerformer, qb); err != nil {
-			return err
-		}
+	qb := r.repository.Performer
 
-		_, err = qb.UpdatePartial(ctx, performerID, updatedPerformer)
-		if err != nil {
+	if legacyURL.Set || legacyTwitter.Set || legacyInstagram.Set {
+		if err := r.handleLegacyURLs(ctx, performerID, legacyURL, legacyTwitter, legacyInstagram, &updatedPerformer); err != nil {
 			return err
 		}
+	}
 
-		// update image table
-		if imageIncluded {
-			if err := qb.UpdateImage(ctx, performerID, imageData); err != nil {
-				return err
-			}
-		}
-
-		return nil
-	}); err != nil {
-		return nil, err
+	if err := performer.ValidateUpdate(ctx, performerID, updatedPerformer, qb); err != nil {
+		return err
 	}
 
-	r.hookExecutor.ExecutePostHooks(ctx, performerID, hook.PerformerUpdatePost, input, translator.getFields())
-	return r.getPerformer(ctx, performerID)
+	_, err = qb.UpdatePartial(ctx, performerID, updatedPerformer)
+	return err
 }
 
 func (r *mutationResolver) BulkPerformerUpdate(ctx context.Context, input BulkPerformerUpdateInput) ([]*models.Performer, error) {
@@ -412,6 +429,11 @@ func (r *mutationResolver) BulkPerformerUpdate(ctx context.Context, input BulkPe
 		qb := r.repository.Performer
 
 		for _, performerID := range performerIDs {
+			// output is ignored, since the input applies to all of the objects
+			if err := r.executePreHooks(ctx, performerID, hook.PerformerUpdatePre, input, &translator); err != nil {
+				return err
+			}
+
 			if legacyURL.Set || legacyTwitter.Set || legacyInstagram.Set {
 				if err := r.handleLegacyURLs(ctx, performerID, legacyURL, legacyTwitter, legacyInstagram, &updatedPerformer); err != nil {
 					return err
@@ -458,6 +480,10 @@ func (r *mutationResolver) PerformerDestroy(ctx context.Context, input Performer
 	}
 
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, id, hook.PerformerDestroyPre, input, nil); err != nil {
+			return err
+		}
+
 		return r.repository.Performer.Destroy(ctx, id)
 	}); err != nil {
 		return false, err
@@ -477,6 +503,10 @@ func (r *mutationResolver) PerformersDestroy(ctx context.Context, performerIDs [
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Performer
 		for _, id := range ids {
+			if err := r.executePreHooks(ctx, id, hook.PerformerDestroyPre, performerIDs, nil); err != nil {
+				return err
+			}
+
 			if err := qb.Destroy(ctx, id); err != nil {
 				return err
 			}
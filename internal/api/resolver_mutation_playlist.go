@@ -0,0 +1,208 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/playlist"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+func playlistItemsFromInput(input []*PlaylistItemInput) ([]models.PlaylistItem, error) {
+	var t changesetTranslator
+
+	ret := make([]models.PlaylistItem, len(input))
+	for i, v := range input {
+		var err error
+		item := &ret[i]
+
+		item.SceneID, err = t.intPtrFromString(v.SceneID)
+		if err != nil {
+			return nil, fmt.Errorf("converting scene id: %w", err)
+		}
+		item.SceneMarkerID, err = t.intPtrFromString(v.SceneMarkerID)
+		if err != nil {
+			return nil, fmt.Errorf("converting scene marker id: %w", err)
+		}
+		item.ImageID, err = t.intPtrFromString(v.ImageID)
+		if err != nil {
+			return nil, fmt.Errorf("converting image id: %w", err)
+		}
+		item.EndSeconds = v.EndSeconds
+
+		if err := item.Validate(); err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+
+	return ret, nil
+}
+
+func validatePlaylistName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("name must not be blank")
+	}
+
+	return nil
+}
+
+func (r *mutationResolver) PlaylistCreate(ctx context.Context, input PlaylistCreateInput) (*models.Playlist, error) {
+	if err := validatePlaylistName(input.Name); err != nil {
+		return nil, err
+	}
+
+	items, err := playlistItemsFromInput(input.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	newPlaylist := models.NewPlaylist()
+	newPlaylist.Name = input.Name
+	if input.Description != nil {
+		newPlaylist.Description = *input.Description
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.Playlist
+		if err := qb.Create(ctx, &newPlaylist); err != nil {
+			return err
+		}
+
+		return qb.UpdateItems(ctx, newPlaylist.ID, items)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &newPlaylist, nil
+}
+
+// updatePlaylist updates the playlist and sets its items to those returned by
+// fn, and returns the updated playlist.
+func (r *mutationResolver) updatePlaylist(ctx context.Context, id string, fn func(p *models.Playlist, items []models.PlaylistItem) ([]models.PlaylistItem, error)) (ret *models.Playlist, err error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.Playlist
+
+		ret, err = qb.Find(ctx, idInt)
+		if err != nil {
+			return err
+		}
+		if ret == nil {
+			return fmt.Errorf("playlist with id %d not found", idInt)
+		}
+
+		items, err := qb.GetItems(ctx, idInt)
+		if err != nil {
+			return err
+		}
+
+		items, err = fn(ret, items)
+		if err != nil {
+			return err
+		}
+
+		if err := qb.UpdateItems(ctx, idInt, items); err != nil {
+			return err
+		}
+
+		ret.UpdatedAt = time.Now()
+		return qb.Update(ctx, ret)
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) PlaylistUpdate(ctx context.Context, input PlaylistUpdateInput) (*models.Playlist, error) {
+	if input.Name != nil {
+		if err := validatePlaylistName(*input.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	var newItems []models.PlaylistItem
+	if input.Items != nil {
+		var err error
+		newItems, err = playlistItemsFromInput(input.Items)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return r.updatePlaylist(ctx, input.ID, func(p *models.Playlist, items []models.PlaylistItem) ([]models.PlaylistItem, error) {
+		if input.Name != nil {
+			p.Name = *input.Name
+		}
+		if input.Description != nil {
+			p.Description = *input.Description
+		}
+		if input.Items != nil {
+			return newItems, nil
+		}
+		return items, nil
+	})
+}
+
+func (r *mutationResolver) PlaylistsDestroy(ctx context.Context, ids []string) (bool, error) {
+	idInts, err := stringslice.StringSliceToIntSlice(ids)
+	if err != nil {
+		return false, fmt.Errorf("converting ids: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.Playlist
+		for _, id := range idInts {
+			if err := qb.Destroy(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) PlaylistAddItems(ctx context.Context, input PlaylistAddItemsInput) (*models.Playlist, error) {
+	newItems, err := playlistItemsFromInput(input.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.updatePlaylist(ctx, input.ID, func(p *models.Playlist, items []models.PlaylistItem) ([]models.PlaylistItem, error) {
+		return playlist.InsertItems(items, newItems, input.Index), nil
+	})
+}
+
+func (r *mutationResolver) PlaylistRemoveItems(ctx context.Context, input PlaylistRemoveItemsInput) (*models.Playlist, error) {
+	itemIDs, err := stringslice.StringSliceToIntSlice(input.ItemIds)
+	if err != nil {
+		return nil, fmt.Errorf("converting item ids: %w", err)
+	}
+
+	return r.updatePlaylist(ctx, input.ID, func(p *models.Playlist, items []models.PlaylistItem) ([]models.PlaylistItem, error) {
+		return playlist.RemoveItems(items, itemIDs)
+	})
+}
+
+func (r *mutationResolver) PlaylistReorderItems(ctx context.Context, input PlaylistReorderItemsInput) (*models.Playlist, error) {
+	itemIDs, err := stringslice.StringSliceToIntSlice(input.ItemIds)
+	if err != nil {
+		return nil, fmt.Errorf("converting item ids: %w", err)
+	}
+
+	return r.updatePlaylist(ctx, input.ID, func(p *models.Playlist, items []models.PlaylistItem) ([]models.PlaylistItem, error) {
+		return playlist.ReorderItems(items, itemIDs)
+	})
+}
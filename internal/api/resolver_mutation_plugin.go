@@ -109,5 +109,8 @@ func (r *mutationResolver) SetPluginsEnabled(ctx context.Context, enabledMap map
 		return false, err
 	}
 
+	// start or stop the services of the affected plugins
+	manager.GetInstance().PluginCache.RefreshServices()
+
 	return true, nil
 }
@@ -36,65 +36,69 @@ func (r *mutationResolver) SceneCreate(ctx context.Context, input models.SceneCr
 		inputMap: getUpdateInputMap(ctx),
 	}
 
-	fileIDs, err := translator.fileIDSliceFromStringSlice(input.FileIds)
-	if err != nil {
-		return nil, fmt.Errorf("converting file ids: %w", err)
+	var coverImageData []byte
+	if input.CoverImage != nil {
+		var err error
+		coverImageData, err = utils.ProcessImageInput(ctx, *input.CoverImage)
+		if err != nil {
+			return nil, fmt.Errorf("processing cover image: %w", err)
+		}
 	}
 
-	// Populate a new scene from the input
-	newScene := models.NewScene()
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, 0, hook.SceneCreatePre, &input, &translator); err != nil {
+			return err
+		}
 
-	newScene.Title = translator.string(input.Title)
-	newScene.Code = translator.string(input.Code)
-	newScene.Details = translator.string(input.Details)
-	newScene.Director = translator.string(input.Director)
-	newScene.Rating = input.Rating100
-	newScene.Organized = translator.bool(input.Organized)
-	newScene.StashIDs = models.NewRelatedStashIDs(input.StashIds)
+		fileIDs, err := translator.fileIDSliceFromStringSlice(input.FileIds)
+		if err != nil {
+			return fmt.Errorf("converting file ids: %w", err)
+		}
 
-	newScene.Date, err = translator.datePtr(input.Date)
-	if err != nil {
-		return nil, fmt.Errorf("converting date: %w", err)
-	}
-	newScene.StudioID, err = translator.intPtrFromString(input.StudioID)
-	if err != nil {
-		return nil, fmt.Errorf("converting studio id: %w", err)
-	}
+		// Populate a new scene from the input
+		newScene := models.NewScene()
 
-	if input.Urls != nil {
-		newScene.URLs = models.NewRelatedStrings(input.Urls)
-	} else if input.URL != nil {
-		newScene.URLs = models.NewRelatedStrings([]string{*input.URL})
-	}
+		newScene.Title = translator.string(input.Title)
+		newScene.Code = translator.string(input.Code)
+		newScene.Details = translator.string(input.Details)
+		newScene.Director = translator.string(input.Director)
+		newScene.Rating = input.Rating100
+		newScene.Organized = translator.bool(input.Organized)
+		newScene.StashIDs = models.NewRelatedStashIDs(input.StashIds)
 
-	newScene.PerformerIDs, err = translator.relatedIds(input.PerformerIds)
-	if err != nil {
-		return nil, fmt.Errorf("converting performer ids: %w", err)
-	}
-	newScene.TagIDs, err = translator.relatedIds(input.TagIds)
-	if err != nil {
-		return nil, fmt.Errorf("converting tag ids: %w", err)
-	}
-	newScene.GalleryIDs, err = translator.relatedIds(input.GalleryIds)
-	if err != nil {
-		return nil, fmt.Errorf("converting gallery ids: %w", err)
-	}
+		newScene.Date, err = translator.datePtr(input.Date)
+		if err != nil {
+			return fmt.Errorf("converting date: %w", err)
+		}
+		newScene.StudioID, err = translator.intPtrFromString(input.StudioID)
+		if err != nil {
+			return fmt.Errorf("converting studio id: %w", err)
+		}
 
-	newScene.Movies, err = translator.relatedMovies(input.Movies)
-	if err != nil {
-		return nil, fmt.Errorf("converting movies: %w", err)
-	}
+		if input.Urls != nil {
+			newScene.URLs = models.NewRelatedStrings(input.Urls)
+		} else if input.URL != nil {
+			newScene.URLs = models.NewRelatedStrings([]string{*input.URL})
+		}
 
-	var coverImageData []byte
-	if input.CoverImage != nil {
-		var err error
-		coverImageData, err = utils.ProcessImageInput(ctx, *input.CoverImage)
+		newScene.PerformerIDs, err = translator.relatedIds(input.PerformerIds)
 		if err != nil {
-			return nil, fmt.Errorf("processing cover image: %w", err)
+			return fmt.Errorf("converting performer ids: %w", err)
+		}
+		newScene.TagIDs, err = translator.relatedIds(input.TagIds)
+		if err != nil {
+			return fmt.Errorf("converting tag ids: %w", err)
+		}
+		newScene.GalleryIDs, err = translator.relatedIds(input.GalleryIds)
+		if err != nil {
+			return fmt.Errorf("converting gallery ids: %w", err)
+		}
+
+		newScene.Movies, err = translator.relatedMovies(input.Movies)
+		if err != nil {
+			return fmt.Errorf("converting movies: %w", err)
 		}
-	}
 
-	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		ret, err = r.Resolver.sceneService.Create(ctx, &newScene, fileIDs, coverImageData)
 		return err
 	}); err != nil {
@@ -105,12 +109,21 @@ func (r *mutationResolver) SceneCreate(ctx context.Context, input models.SceneCr
 }
 
 func (r *mutationResolver) SceneUpdate(ctx context.Context, input models.SceneUpdateInput) (ret *models.Scene, err error) {
+	sceneID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
 	translator := changesetTranslator{
 		inputMap: getUpdateInputMap(ctx),
 	}
 
 	// Start the transaction and save the scene
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, sceneID, hook.SceneUpdatePre, &input, &translator); err != nil {
+			return err
+		}
+
 		ret, err = r.sceneUpdate(ctx, input, translator)
 		return err
 	}); err != nil {
@@ -131,6 +144,15 @@ func (r *mutationResolver) ScenesUpdate(ctx context.Context, input []*models.Sce
 				inputMap: inputMaps[i],
 			}
 
+			sceneID, err := strconv.Atoi(scene.ID)
+			if err != nil {
+				return fmt.Errorf("converting id: %w", err)
+			}
+
+			if err := r.executePreHooks(ctx, sceneID, hook.SceneUpdatePre, scene, &translator); err != nil {
+				return err
+			}
+
 			thisScene, err := r.sceneUpdate(ctx, *scene, translator)
 			if err != nil {
 				return err
@@ -370,6 +392,11 @@ func (r *mutationResolver) BulkSceneUpdate(ctx context.Context, input BulkSceneU
 		qb := r.repository.Scene
 
 		for _, sceneID := range sceneIDs {
+			// output is ignored, since the input applies to all of the objects
+			if err := r.executePreHooks(ctx, sceneID, hook.SceneUpdatePre, input, &translator); err != nil {
+				return err
+			}
+
 			scene, err := qb.UpdatePartial(ctx, sceneID, updatedScene)
 			if err != nil {
 				return err
@@ -429,6 +456,15 @@ func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneD
 			return fmt.Errorf("scene with id %d not found", sceneID)
 		}
 
+		if err := r.executePreHooks(ctx, s.ID, hook.SceneDestroyPre, plugin.SceneDestroyInput{
+			SceneDestroyInput: input,
+			Checksum:          s.Checksum,
+			OSHash:            s.OSHash,
+			Path:              s.Path,
+		}, nil); err != nil {
+			return err
+		}
+
 		// kill any running encoders
 		manager.KillRunningStreams(s, fileNamingAlgo)
 
@@ -482,6 +518,15 @@ func (r *mutationResolver) ScenesDestroy(ctx context.Context, input models.Scene
 				return fmt.Errorf("scene with id %d not found", id)
 			}
 
+			if err := r.executePreHooks(ctx, scene.ID, hook.SceneDestroyPre, plugin.ScenesDestroyInput{
+				ScenesDestroyInput: input,
+				Checksum:           scene.Checksum,
+				OSHash:             scene.OSHash,
+				Path:               scene.Path,
+			}, nil); err != nil {
+				return err
+			}
+
 			scenes = append(scenes, scene)
 
 			// kill any running encoders
@@ -579,6 +624,31 @@ func (r *mutationResolver) SceneMerge(ctx context.Context, input SceneMergeInput
 
 	var ret *models.Scene
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		// the source scenes are destroyed by the merge
+		for _, srcID := range srcIDs {
+			src, err := r.repository.Scene.Find(ctx, srcID)
+			if err != nil {
+				return err
+			}
+			if src == nil {
+				return fmt.Errorf("scene with id %d not found", srcID)
+			}
+
+			if err := r.executePreHooks(ctx, srcID, hook.SceneDestroyPre, plugin.SceneDestroyInput{
+				SceneDestroyInput: models.SceneDestroyInput{ID: strconv.Itoa(srcID)},
+				Checksum:          src.Checksum,
+				OSHash:            src.OSHash,
+				Path:              src.Path,
+			}, nil); err != nil {
+				return err
+			}
+		}
+
+		// output is ignored, since the values have already been converted
+		if err := r.executePreHooks(ctx, destID, hook.SceneUpdatePre, input, nil); err != nil {
+			return err
+		}
+
 		if err := r.Resolver.sceneService.Merge(ctx, srcIDs, destID, fileDeleter, scene.MergeOptions{
 			ScenePartial:       *values,
 			IncludePlayHistory: utils.IsTrue(input.PlayHistory),
@@ -615,33 +685,38 @@ func (r *mutationResolver) getSceneMarker(ctx context.Context, id int) (ret *mod
 }
 
 func (r *mutationResolver) SceneMarkerCreate(ctx context.Context, input SceneMarkerCreateInput) (*models.SceneMarker, error) {
-	sceneID, err := strconv.Atoi(input.SceneID)
-	if err != nil {
-		return nil, fmt.Errorf("converting scene id: %w", err)
-	}
+	var newMarker models.SceneMarker
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, 0, hook.SceneMarkerCreatePre, &input, nil); err != nil {
+			return err
+		}
 
-	primaryTagID, err := strconv.Atoi(input.PrimaryTagID)
-	if err != nil {
-		return nil, fmt.Errorf("converting primary tag id: %w", err)
-	}
+		sceneID, err := strconv.Atoi(input.SceneID)
+		if err != nil {
+			return fmt.Errorf("converting scene id: %w", err)
+		}
 
-	// Populate a new scene marker from the input
-	newMarker := models.NewSceneMarker()
+		primaryTagID, err := strconv.Atoi(input.PrimaryTagID)
+		if err != nil {
+			return fmt.Errorf("converting primary tag id: %w", err)
+		}
 
-	newMarker.Title = input.Title
-	newMarker.Seconds = input.Seconds
-	newMarker.PrimaryTagID = primaryTagID
-	newMarker.SceneID = sceneID
+		// Populate a new scene marker from the input
+		newMarker = models.NewSceneMarker()
 
-	tagIDs, err := stringslice.StringSliceToIntSlice(input.TagIds)
-	if err != nil {
-		return nil, fmt.Errorf("converting tag ids: %w", err)
-	}
+		newMarker.Title = input.Title
+		newMarker.Seconds = input.Seconds
+		newMarker.PrimaryTagID = primaryTagID
+		newMarker.SceneID = sceneID
+
+		tagIDs, err := stringslice.StringSliceToIntSlice(input.TagIds)
+		if err != nil {
+			return fmt.Errorf("converting tag ids: %w", err)
+		}
 
-	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.SceneMarker
 
-		err := qb.Create(ctx, &newMarker)
+		err = qb.Create(ctx, &newMarker)
 		if err != nil {
 			return err
 		}
@@ -668,29 +743,6 @@ func (r *mutationResolver) SceneMarkerUpdate(ctx context.Context, input SceneMar
 		inputMap: getUpdateInputMap(ctx),
 	}
 
-	// Populate scene marker from the input
-	updatedMarker := models.NewSceneMarkerPartial()
-
-	updatedMarker.Title = translator.optionalString(input.Title, "title")
-	updatedMarker.Seconds = translator.optionalFloat64(input.Seconds, "seconds")
-	updatedMarker.SceneID, err = translator.optionalIntFromString(input.SceneID, "scene_id")
-	if err != nil {
-		return nil, fmt.Errorf("converting scene id: %w", err)
-	}
-	updatedMarker.PrimaryTagID, err = translator.optionalIntFromString(input.PrimaryTagID, "primary_tag_id")
-	if err != nil {
-		return nil, fmt.Errorf("converting primary tag id: %w", err)
-	}
-
-	var tagIDs []int
-	tagIdsIncluded := translator.hasField("tag_ids")
-	if input.TagIds != nil {
-		tagIDs, err = stringslice.StringSliceToIntSlice(input.TagIds)
-		if err != nil {
-			return nil, fmt.Errorf("converting tag ids: %w", err)
-		}
-	}
-
 	mgr := manager.GetInstance()
 
 	fileDeleter := &scene.FileDeleter{
@@ -701,6 +753,33 @@ func (r *mutationResolver) SceneMarkerUpdate(ctx context.Context, input SceneMar
 
 	// Start the transaction and save the scene marker
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, markerID, hook.SceneMarkerUpdatePre, &input, &translator); err != nil {
+			return err
+		}
+
+		// Populate scene marker from the input
+		updatedMarker := models.NewSceneMarkerPartial()
+
+		updatedMarker.Title = translator.optionalString(input.Title, "title")
+		updatedMarker.Seconds = translator.optionalFloat64(input.Seconds, "seconds")
+		updatedMarker.SceneID, err = translator.optionalIntFromString(input.SceneID, "scene_id")
+		if err != nil {
+			return fmt.Errorf("converting scene id: %w", err)
+		}
+		updatedMarker.PrimaryTagID, err = translator.optionalIntFromString(input.PrimaryTagID, "primary_tag_id")
+		if err != nil {
+			return fmt.Errorf("converting primary tag id: %w", err)
+		}
+
+		var tagIDs []int
+		tagIdsIncluded := translator.hasField("tag_ids")
+		if input.TagIds != nil {
+			tagIDs, err = stringslice.StringSliceToIntSlice(input.TagIds)
+			if err != nil {
+				return fmt.Errorf("converting tag ids: %w", err)
+			}
+		}
+
 		qb := r.repository.SceneMarker
 		sqb := r.repository.Scene
 
@@ -793,6 +872,10 @@ func (r *mutationResolver) SceneMarkerDestroy(ctx context.Context, id string) (b
 			return fmt.Errorf("scene with id %d not found", marker.SceneID)
 		}
 
+		if err := r.executePreHooks(ctx, markerID, hook.SceneMarkerDestroyPre, id, nil); err != nil {
+			return err
+		}
+
 		return scene.DestroyMarker(ctx, s, marker, qb, fileDeleter)
 	}); err != nil {
 		fileDeleter.Rollback()
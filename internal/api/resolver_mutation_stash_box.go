@@ -6,7 +6,8 @@ import (
 	"strconv"
 
 	"github.com/stashapp/stash/internal/manager"
-	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 )
 
 func (r *mutationResolver) SubmitStashBoxFingerprints(ctx context.Context, input StashBoxFingerprintSubmissionInput) (bool, error) {
@@ -15,8 +16,24 @@ func (r *mutationResolver) SubmitStashBoxFingerprints(ctx context.Context, input
 		return false, err
 	}
 
-	client := r.newStashBoxClient(*b)
-	return client.SubmitStashBoxFingerprints(ctx, input.SceneIds)
+	ids, err := stringslice.StringSliceToIntSlice(input.SceneIds)
+	if err != nil {
+		return false, fmt.Errorf("converting scene ids: %w", err)
+	}
+
+	submissions := make([]*models.StashBoxSubmission, len(ids))
+	for i, id := range ids {
+		sceneID := id
+		s := models.NewStashBoxSubmission(models.StashBoxSubmissionTypeFingerprints, b.Endpoint)
+		s.SceneID = &sceneID
+		submissions[i] = &s
+	}
+
+	if err := manager.GetInstance().StashBoxOutbox.Queue(ctx, submissions); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
 func (r *mutationResolver) StashBoxBatchPerformerTag(ctx context.Context, input manager.StashBoxBatchTagInput) (string, error) {
@@ -39,45 +56,35 @@ func (r *mutationResolver) StashBoxBatchStudioTag(ctx context.Context, input man
 	return strconv.Itoa(jobID), nil
 }
 
+func (r *mutationResolver) StashBoxBatchSceneTag(ctx context.Context, input manager.StashBoxBatchTagInput) (string, error) {
+	b, err := resolveStashBoxBatchTagInput(input.Endpoint, input.StashBoxEndpoint)
+	if err != nil {
+		return "", err
+	}
+
+	jobID := manager.GetInstance().StashBoxBatchSceneTag(ctx, b, input)
+	return strconv.Itoa(jobID), nil
+}
+
 func (r *mutationResolver) SubmitStashBoxSceneDraft(ctx context.Context, input StashBoxDraftSubmissionInput) (*string, error) {
 	b, err := resolveStashBox(input.StashBoxIndex, input.StashBoxEndpoint)
 	if err != nil {
 		return nil, err
 	}
 
-	client := r.newStashBoxClient(*b)
-
 	id, err := strconv.Atoi(input.ID)
 	if err != nil {
 		return nil, fmt.Errorf("converting id: %w", err)
 	}
 
-	var res *string
-	err = r.withReadTxn(ctx, func(ctx context.Context) error {
-		qb := r.repository.Scene
-		scene, err := qb.Find(ctx, id)
-		if err != nil {
-			return err
-		}
-
-		if scene == nil {
-			return fmt.Errorf("scene with id %d not found", id)
-		}
-
-		cover, err := qb.GetCover(ctx, id)
-		if err != nil {
-			logger.Errorf("Error getting scene cover: %v", err)
-		}
-
-		if err := scene.LoadURLs(ctx, r.repository.Scene); err != nil {
-			return fmt.Errorf("loading scene URLs: %w", err)
-		}
+	s := models.NewStashBoxSubmission(models.StashBoxSubmissionTypeSceneDraft, b.Endpoint)
+	s.SceneID = &id
 
-		res, err = client.SubmitSceneDraft(ctx, scene, cover)
-		return err
-	})
+	if err := manager.GetInstance().StashBoxOutbox.Submit(ctx, &s); err != nil {
+		return nil, err
+	}
 
-	return res, err
+	return s.RemoteID, nil
 }
 
 func (r *mutationResolver) SubmitStashBoxPerformerDraft(ctx context.Context, input StashBoxDraftSubmissionInput) (*string, error) {
@@ -86,28 +93,51 @@ func (r *mutationResolver) SubmitStashBoxPerformerDraft(ctx context.Context, inp
 		return nil, err
 	}
 
-	client := r.newStashBoxClient(*b)
-
 	id, err := strconv.Atoi(input.ID)
 	if err != nil {
 		return nil, fmt.Errorf("converting id: %w", err)
 	}
 
-	var res *string
-	err = r.withReadTxn(ctx, func(ctx context.Context) error {
-		qb := r.repository.Performer
-		performer, err := qb.Find(ctx, id)
-		if err != nil {
-			return err
-		}
+	s := models.NewStashBoxSubmission(models.StashBoxSubmissionTypePerformerDraft, b.Endpoint)
+	s.PerformerID = &id
 
-		if performer == nil {
-			return fmt.Errorf("performer with id %d not found", id)
-		}
+	if err := manager.GetInstance().StashBoxOutbox.Submit(ctx, &s); err != nil {
+		return nil, err
+	}
+
+	return s.RemoteID, nil
+}
+
+func (r *mutationResolver) RetryStashBoxSubmissions(ctx context.Context, ids []string) (bool, error) {
+	submissionIDs, err := stringslice.StringSliceToIntSlice(ids)
+	if err != nil {
+		return false, fmt.Errorf("converting ids: %w", err)
+	}
 
-		res, err = client.SubmitPerformerDraft(ctx, performer)
-		return err
-	})
+	if err := manager.GetInstance().StashBoxOutbox.Retry(ctx, submissionIDs); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) DeleteStashBoxSubmissions(ctx context.Context, ids []string) (bool, error) {
+	submissionIDs, err := stringslice.StringSliceToIntSlice(ids)
+	if err != nil {
+		return false, fmt.Errorf("converting ids: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.StashBoxSubmission
+		for _, id := range submissionIDs {
+			if err := qb.Destroy(ctx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return false, err
+	}
 
-	return res, err
+	return true, nil
 }
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+func (r *mutationResolver) StashBoxUpdateCheck(ctx context.Context, input manager.StashBoxUpdateCheckInput) (string, error) {
+	b, err := resolveStashBox(nil, &input.StashBoxEndpoint)
+	if err != nil {
+		return "", err
+	}
+
+	jobID, err := manager.GetInstance().StashBoxUpdateCheck(ctx, b, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) ApplyStashBoxUpdates(ctx context.Context, ids []string, fields []string) (bool, error) {
+	updateIDs, err := stringslice.StringSliceToIntSlice(ids)
+	if err != nil {
+		return false, fmt.Errorf("converting ids: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		updates, err := r.repository.StashBoxUpdate.FindMany(ctx, updateIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range updates {
+			if err := manager.ApplyStashBoxUpdate(ctx, r.repository, u, fields); err != nil {
+				return fmt.Errorf("applying update %d: %w", u.ID, err)
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) DismissStashBoxUpdates(ctx context.Context, ids []string) (bool, error) {
+	updateIDs, err := stringslice.StringSliceToIntSlice(ids)
+	if err != nil {
+		return false, fmt.Errorf("converting ids: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.StashBoxUpdate
+
+		updates, err := qb.FindMany(ctx, updateIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range updates {
+			if u.Status != models.StashBoxUpdateStatusPending {
+				return fmt.Errorf("update %d is %s", u.ID, u.Status)
+			}
+
+			if err := qb.UpdateStatus(ctx, u.ID, models.StashBoxUpdateStatusDismissed); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
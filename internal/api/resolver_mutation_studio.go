@@ -29,30 +29,6 @@ func (r *mutationResolver) StudioCreate(ctx context.Context, input models.Studio
 		inputMap: getUpdateInputMap(ctx),
 	}
 
-	// Populate a new studio from the input
-	newStudio := models.NewStudio()
-
-	newStudio.Name = input.Name
-	newStudio.URL = translator.string(input.URL)
-	newStudio.Rating = input.Rating100
-	newStudio.Favorite = translator.bool(input.Favorite)
-	newStudio.Details = translator.string(input.Details)
-	newStudio.IgnoreAutoTag = translator.bool(input.IgnoreAutoTag)
-	newStudio.Aliases = models.NewRelatedStrings(input.Aliases)
-	newStudio.StashIDs = models.NewRelatedStashIDs(input.StashIds)
-
-	var err error
-
-	newStudio.ParentID, err = translator.intPtrFromString(input.ParentID)
-	if err != nil {
-		return nil, fmt.Errorf("converting parent id: %w", err)
-	}
-
-	newStudio.TagIDs, err = translator.relatedIds(input.TagIds)
-	if err != nil {
-		return nil, fmt.Errorf("converting tag ids: %w", err)
-	}
-
 	// Process the base 64 encoded image string
 	var imageData []byte
 	if input.Image != nil {
@@ -64,7 +40,36 @@ func (r *mutationResolver) StudioCreate(ctx context.Context, input models.Studio
 	}
 
 	// Start the transaction and save the studio
+	var newStudio models.Studio
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, 0, hook.StudioCreatePre, &input, &translator); err != nil {
+			return err
+		}
+
+		// Populate a new studio from the input
+		newStudio = models.NewStudio()
+
+		newStudio.Name = input.Name
+		newStudio.URL = translator.string(input.URL)
+		newStudio.Rating = input.Rating100
+		newStudio.Favorite = translator.bool(input.Favorite)
+		newStudio.Details = translator.string(input.Details)
+		newStudio.IgnoreAutoTag = translator.bool(input.IgnoreAutoTag)
+		newStudio.Aliases = models.NewRelatedStrings(input.Aliases)
+		newStudio.StashIDs = models.NewRelatedStashIDs(input.StashIds)
+
+		var err error
+
+		newStudio.ParentID, err = translator.intPtrFromString(input.ParentID)
+		if err != nil {
+			return fmt.Errorf("converting parent id: %w", err)
+		}
+
+		newStudio.TagIDs, err = translator.relatedIds(input.TagIds)
+		if err != nil {
+			return fmt.Errorf("converting tag ids: %w", err)
+		}
+
 		qb := r.repository.Studio
 
 		if err := studio.ValidateCreate(ctx, newStudio, qb); err != nil {
@@ -101,29 +106,6 @@ func (r *mutationResolver) StudioUpdate(ctx context.Context, input models.Studio
 		inputMap: getUpdateInputMap(ctx),
 	}
 
-	// Populate studio from the input
-	updatedStudio := models.NewStudioPartial()
-
-	updatedStudio.ID = studioID
-	updatedStudio.Name = translator.optionalString(input.Name, "name")
-	updatedStudio.URL = translator.optionalString(input.URL, "url")
-	updatedStudio.Details = translator.optionalString(input.Details, "details")
-	updatedStudio.Rating = translator.optionalInt(input.Rating100, "rating100")
-	updatedStudio.Favorite = translator.optionalBool(input.Favorite, "favorite")
-	updatedStudio.IgnoreAutoTag = translator.optionalBool(input.IgnoreAutoTag, "ignore_auto_tag")
-	updatedStudio.Aliases = translator.updateStrings(input.Aliases, "aliases")
-	updatedStudio.StashIDs = translator.updateStashIDs(input.StashIds, "stash_ids")
-
-	updatedStudio.ParentID, err = translator.optionalIntFromString(input.ParentID, "parent_id")
-	if err != nil {
-		return nil, fmt.Errorf("converting parent id: %w", err)
-	}
-
-	updatedStudio.TagIDs, err = translator.updateIds(input.TagIds, "tag_ids")
-	if err != nil {
-		return nil, fmt.Errorf("converting tag ids: %w", err)
-	}
-
 	// Process the base 64 encoded image string
 	var imageData []byte
 	imageIncluded := translator.hasField("image")
@@ -137,19 +119,16 @@ func (r *mutationResolver) StudioUpdate(ctx context.Context, input models.Studio
 
 	// Start the transaction and update the studio
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
-		qb := r.repository.Studio
-
-		if err := studio.ValidateModify(ctx, updatedStudio, qb); err != nil {
+		if err := r.executePreHooks(ctx, studioID, hook.StudioUpdatePre, &input, &translator); err != nil {
 			return err
 		}
 
-		_, err = qb.UpdatePartial(ctx, updatedStudio)
-		if err != nil {
+		if err := r.studioUpdate(ctx, studioID, input, translator); err != nil {
 			return err
 		}
 
 		if imageIncluded {
-			if err := qb.UpdateImage(ctx, studioID, imageData); err != nil {
+			if err := r.repository.Studio.UpdateImage(ctx, studioID, imageData); err != nil {
 				return err
 			}
 		}
@@ -163,6 +142,43 @@ func (r *mutationResolver) StudioUpdate(ctx context.Context, input models.Studio
 	return r.getStudio(ctx, studioID)
 }
 
+// studioUpdate updates the studio from the input, excluding the image.
+func (r *mutationResolver) studioUpdate(ctx context.Context, studioID int, input models.StudioUpdateInput, translator changesetTranslator) error {
+	var err error
+
+	// Populate studio from the input
+	updatedStudio := models.NewStudioPartial()
+
+	updatedStudio.ID = studioID
+	updatedStudio.Name = translator.optionalString(input.Name, "name")
+	updatedStudio.URL = translator.optionalString(input.URL, "url")
+	updatedStudio.Details = translator.optionalString(input.Details, "details")
+	updatedStudio.Rating = translator.optionalInt(input.Rating100, "rating100")
+	updatedStudio.Favorite = translator.optionalBool(input.Favorite, "favorite")
+	updatedStudio.IgnoreAutoTag = translator.optionalBool(input.IgnoreAutoTag, "ignore_auto_tag")
+	updatedStudio.Aliases = translator.updateStrings(input.Aliases, "aliases")
+	updatedStudio.StashIDs = translator.updateStashIDs(input.StashIds, "stash_ids")
+
+	updatedStudio.ParentID, err = translator.optionalIntFromString(input.ParentID, "parent_id")
+	if err != nil {
+		return fmt.Errorf("converting parent id: %w", err)
+	}
+
+	updatedStudio.TagIDs, err = translator.updateIds(input.TagIds, "tag_ids")
+	if err != nil {
+		return fmt.Errorf("converting tag ids: %w", err)
+	}
+
+	qb := r.repository.Studio
+
+	if err := studio.ValidateModify(ctx, updatedStudio, qb); err != nil {
+		return err
+	}
+
+	_, err = qb.UpdatePartial(ctx, updatedStudio)
+	return err
+}
+
 func (r *mutationResolver) StudioDestroy(ctx context.Context, input StudioDestroyInput) (bool, error) {
 	id, err := strconv.Atoi(input.ID)
 	if err != nil {
@@ -170,6 +186,10 @@ func (r *mutationResolver) StudioDestroy(ctx context.Context, input StudioDestro
 	}
 
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, id, hook.StudioDestroyPre, input, nil); err != nil {
+			return err
+		}
+
 		return r.repository.Studio.Destroy(ctx, id)
 	}); err != nil {
 		return false, err
@@ -189,6 +209,10 @@ func (r *mutationResolver) StudiosDestroy(ctx context.Context, studioIDs []strin
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Studio
 		for _, id := range ids {
+			if err := r.executePreHooks(ctx, id, hook.StudioDestroyPre, studioIDs, nil); err != nil {
+				return err
+			}
+
 			if err := qb.Destroy(ctx, id); err != nil {
 				return err
 			}
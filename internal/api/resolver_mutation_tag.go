@@ -29,30 +29,10 @@ func (r *mutationResolver) TagCreate(ctx context.Context, input TagCreateInput)
 		inputMap: getUpdateInputMap(ctx),
 	}
 
-	// Populate a new tag from the input
-	newTag := models.NewTag()
-
-	newTag.Name = input.Name
-	newTag.Aliases = models.NewRelatedStrings(input.Aliases)
-	newTag.Favorite = translator.bool(input.Favorite)
-	newTag.Description = translator.string(input.Description)
-	newTag.IgnoreAutoTag = translator.bool(input.IgnoreAutoTag)
-
-	var err error
-
-	newTag.ParentIDs, err = translator.relatedIds(input.ParentIds)
-	if err != nil {
-		return nil, fmt.Errorf("converting parent tag ids: %w", err)
-	}
-
-	newTag.ChildIDs, err = translator.relatedIds(input.ChildIds)
-	if err != nil {
-		return nil, fmt.Errorf("converting child tag ids: %w", err)
-	}
-
 	// Process the base 64 encoded image string
 	var imageData []byte
 	if input.Image != nil {
+		var err error
 		imageData, err = utils.ProcessImageInput(ctx, *input.Image)
 		if err != nil {
 			return nil, fmt.Errorf("processing image: %w", err)
@@ -60,7 +40,33 @@ func (r *mutationResolver) TagCreate(ctx context.Context, input TagCreateInput)
 	}
 
 	// Start the transaction and save the tag
+	var newTag models.Tag
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, 0, hook.TagCreatePre, &input, &translator); err != nil {
+			return err
+		}
+
+		// Populate a new tag from the input
+		newTag = models.NewTag()
+
+		newTag.Name = input.Name
+		newTag.Aliases = models.NewRelatedStrings(input.Aliases)
+		newTag.Favorite = translator.bool(input.Favorite)
+		newTag.Description = translator.string(input.Description)
+		newTag.IgnoreAutoTag = translator.bool(input.IgnoreAutoTag)
+
+		var err error
+
+		newTag.ParentIDs, err = translator.relatedIds(input.ParentIds)
+		if err != nil {
+			return fmt.Errorf("converting parent tag ids: %w", err)
+		}
+
+		newTag.ChildIDs, err = translator.relatedIds(input.ChildIds)
+		if err != nil {
+			return fmt.Errorf("converting child tag ids: %w", err)
+		}
+
 		qb := r.repository.Tag
 
 		if err := tag.ValidateCreate(ctx, newTag, qb); err != nil {
@@ -98,26 +104,6 @@ func (r *mutationResolver) TagUpdate(ctx context.Context, input TagUpdateInput)
 		inputMap: getUpdateInputMap(ctx),
 	}
 
-	// Populate tag from the input
-	updatedTag := models.NewTagPartial()
-
-	updatedTag.Name = translator.optionalString(input.Name, "name")
-	updatedTag.Favorite = translator.optionalBool(input.Favorite, "favorite")
-	updatedTag.IgnoreAutoTag = translator.optionalBool(input.IgnoreAutoTag, "ignore_auto_tag")
-	updatedTag.Description = translator.optionalString(input.Description, "description")
-
-	updatedTag.Aliases = translator.updateStrings(input.Aliases, "aliases")
-
-	updatedTag.ParentIDs, err = translator.updateIds(input.ParentIds, "parent_ids")
-	if err != nil {
-		return nil, fmt.Errorf("converting parent tag ids: %w", err)
-	}
-
-	updatedTag.ChildIDs, err = translator.updateIds(input.ChildIds, "child_ids")
-	if err != nil {
-		return nil, fmt.Errorf("converting child tag ids: %w", err)
-	}
-
 	var imageData []byte
 	imageIncluded := translator.hasField("image")
 	if input.Image != nil {
@@ -130,6 +116,30 @@ func (r *mutationResolver) TagUpdate(ctx context.Context, input TagUpdateInput)
 	// Start the transaction and save the tag
 	var t *models.Tag
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, tagID, hook.TagUpdatePre, &input, &translator); err != nil {
+			return err
+		}
+
+		// Populate tag from the input
+		updatedTag := models.NewTagPartial()
+
+		updatedTag.Name = translator.optionalString(input.Name, "name")
+		updatedTag.Favorite = translator.optionalBool(input.Favorite, "favorite")
+		updatedTag.IgnoreAutoTag = translator.optionalBool(input.IgnoreAutoTag, "ignore_auto_tag")
+		updatedTag.Description = translator.optionalString(input.Description, "description")
+
+		updatedTag.Aliases = translator.updateStrings(input.Aliases, "aliases")
+
+		updatedTag.ParentIDs, err = translator.updateIds(input.ParentIds, "parent_ids")
+		if err != nil {
+			return fmt.Errorf("converting parent tag ids: %w", err)
+		}
+
+		updatedTag.ChildIDs, err = translator.updateIds(input.ChildIds, "child_ids")
+		if err != nil {
+			return fmt.Errorf("converting child tag ids: %w", err)
+		}
+
 		qb := r.repository.Tag
 
 		if err := tag.ValidateUpdate(ctx, tagID, updatedTag, qb); err != nil {
@@ -193,6 +203,11 @@ func (r *mutationResolver) BulkTagUpdate(ctx context.Context, input BulkTagUpdat
 		qb := r.repository.Tag
 
 		for _, tagID := range tagIDs {
+			// output is ignored, since the input applies to all of the objects
+			if err := r.executePreHooks(ctx, tagID, hook.TagUpdatePre, input, &translator); err != nil {
+				return err
+			}
+
 			if err := tag.ValidateUpdate(ctx, tagID, updatedTag, qb); err != nil {
 				return err
 			}
@@ -233,6 +248,10 @@ func (r *mutationResolver) TagDestroy(ctx context.Context, input TagDestroyInput
 	}
 
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.executePreHooks(ctx, tagID, hook.TagDestroyPre, input, nil); err != nil {
+			return err
+		}
+
 		return r.repository.Tag.Destroy(ctx, tagID)
 	}); err != nil {
 		return false, err
@@ -252,6 +271,10 @@ func (r *mutationResolver) TagsDestroy(ctx context.Context, tagIDs []string) (bo
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Tag
 		for _, id := range ids {
+			if err := r.executePreHooks(ctx, id, hook.TagDestroyPre, tagIDs, nil); err != nil {
+				return err
+			}
+
 			if err := qb.Destroy(ctx, id); err != nil {
 				return err
 			}
@@ -298,6 +321,13 @@ func (r *mutationResolver) TagsMerge(ctx context.Context, input TagsMergeInput)
 			return fmt.Errorf("tag with id %d not found", destination)
 		}
 
+		// the source tags are destroyed by the merge
+		for _, id := range source {
+			if err := r.executePreHooks(ctx, id, hook.TagDestroyPre, input, nil); err != nil {
+				return err
+			}
+		}
+
 		parents, children, err := tag.MergeHierarchy(ctx, destination, source, qb)
 		if err != nil {
 			return err
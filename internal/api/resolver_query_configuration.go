@@ -83,6 +83,11 @@ func makeConfigGeneralResult() *ConfigGeneralResult {
 		Stashes:                       config.GetStashPaths(),
 		DatabasePath:                  config.GetDatabasePath(),
 		BackupDirectoryPath:           config.GetBackupDirectoryPath(),
+		BackupInterval:                int(config.GetBackupInterval().Hours()),
+		BackupKeepDaily:               config.GetBackupKeepDaily(),
+		BackupKeepWeekly:              config.GetBackupKeepWeekly(),
+		BackupCompress:                config.GetBackupCompress(),
+		BackupIncludeBlobs:            config.GetBackupIncludeBlobs(),
 		GeneratedPath:                 config.GetGeneratedPath(),
 		MetadataPath:                  config.GetMetadataPath(),
 		ConfigFilePath:                config.GetConfigFile(),
@@ -203,6 +208,7 @@ func makeConfigDLNAResult() *ConfigDLNAResult {
 		WhitelistedIPs: config.GetDLNADefaultIPWhitelist(),
 		Interfaces:     config.GetDLNAInterfaces(),
 		VideoSortOrder: config.GetVideoSortOrder(),
+		FilterQuery:    config.GetDLNAFilterQuery(),
 	}
 }
 
@@ -211,12 +217,15 @@ func makeConfigScrapingResult() *ConfigScrapingResult {
 
 	scraperUserAgent := config.GetScraperUserAgent()
 	scraperCDPPath := config.GetScraperCDPPath()
+	embeddedMetadataMappings := config.GetEmbeddedMetadataMappings()
 
 	return &ConfigScrapingResult{
 		ScraperUserAgent:   &scraperUserAgent,
 		ScraperCertCheck:   config.GetScraperCertCheck(),
 		ScraperCDPPath:     &scraperCDPPath,
 		ExcludeTagPatterns: config.GetScraperExcludeTagPatterns(),
+
+		EmbeddedMetadataMappings: &embeddedMetadataMappings,
 	}
 }
 
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+func (r *queryResolver) FindAutoTagRule(ctx context.Context, id string) (ret *models.AutoTagRule, err error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.AutoTagRule.Find(ctx, idInt)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return ret, err
+}
+
+func (r *queryResolver) AllAutoTagRules(ctx context.Context) (ret []*models.AutoTagRule, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.AutoTagRule.All(ctx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return ret, err
+}
+
+func (r *queryResolver) PreviewAutoTagRules(ctx context.Context, input manager.AutoTagRulePreviewInput) (ret []*manager.AutoTagRulePreview, err error) {
+	ruleIDs, err := stringslice.StringSliceToIntSlice(input.RuleIds)
+	if err != nil {
+		return nil, fmt.Errorf("converting rule ids: %w", err)
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.AutoTagRule
+
+		var rules []*models.AutoTagRule
+		if len(ruleIDs) > 0 {
+			rules, err = qb.FindMany(ctx, ruleIDs)
+			if err != nil {
+				return err
+			}
+		} else {
+			all, err := qb.All(ctx)
+			if err != nil {
+				return err
+			}
+
+			for _, rule := range all {
+				if rule.Enabled {
+					rules = append(rules, rule)
+				}
+			}
+		}
+
+		ret, err = manager.PreviewAutoTagRules(ctx, r.repository, rules, input.Paths, input.Limit)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
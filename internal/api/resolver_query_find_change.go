@@ -0,0 +1,26 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *queryResolver) FindChanges(ctx context.Context, changeFilter *models.ChangeFilterType, filter *models.FindFilterType) (ret *FindChangesResultType, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		changes, total, err := r.repository.Change.Query(ctx, changeFilter, filter)
+		if err != nil {
+			return err
+		}
+
+		ret = &FindChangesResultType{
+			Count:   total,
+			Changes: changes,
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
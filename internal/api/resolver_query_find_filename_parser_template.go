@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+func (r *queryResolver) FindFilenameParserTemplate(ctx context.Context, id string) (ret *models.FilenameParserTemplate, err error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.FilenameParserTemplate.Find(ctx, idInt)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return ret, err
+}
+
+func (r *queryResolver) AllFilenameParserTemplates(ctx context.Context) (ret []*models.FilenameParserTemplate, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.FilenameParserTemplate.All(ctx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return ret, err
+}
+
+func (r *queryResolver) FilenameParserTemplateFailures(ctx context.Context, id string, filter *models.FindFilterType) (*FindScenesResultType, error) {
+	template, err := r.FindFilenameParserTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, fmt.Errorf("filename parser template with id %s not found", id)
+	}
+
+	p, err := scene.NewTemplateParser(template, scene.NewFilenameParserRepository(r.repository))
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindScenes(ctx, p.FailuresFilter(), nil, nil, filter, nil)
+}
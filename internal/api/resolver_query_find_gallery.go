@@ -24,7 +24,14 @@ func (r *queryResolver) FindGallery(ctx context.Context, id string) (ret *models
 	return ret, nil
 }
 
-func (r *queryResolver) FindGalleries(ctx context.Context, galleryFilter *models.GalleryFilterType, filter *models.FindFilterType, ids []string) (ret *FindGalleriesResultType, err error) {
+func (r *queryResolver) FindGalleries(ctx context.Context, galleryFilter *models.GalleryFilterType, filter *models.FindFilterType, ids []string, savedFilterID *string) (ret *FindGalleriesResultType, err error) {
+	galleryFilter, filter, err = applySavedFilter(ctx, r, savedFilterID, models.FilterModeGalleries, galleryFilter, filter, func(f *models.GalleryFilterType) **models.MultiCriterionInput {
+		return &f.SavedFilters
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	idInts, err := stringslice.StringSliceToIntSlice(ids)
 	if err != nil {
 		return nil, err
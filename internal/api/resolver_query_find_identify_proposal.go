@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *queryResolver) FindIdentifyProposal(ctx context.Context, id string) (ret *models.IdentifyProposal, err error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.IdentifyProposal.Find(ctx, idInt)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *queryResolver) FindIdentifyProposals(ctx context.Context, proposalFilter *models.IdentifyProposalFilterType, filter *models.FindFilterType) (ret *FindIdentifyProposalsResultType, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		proposals, total, err := r.repository.IdentifyProposal.Query(ctx, proposalFilter, filter)
+		if err != nil {
+			return err
+		}
+
+		ret = &FindIdentifyProposalsResultType{
+			Count:     total,
+			Proposals: proposals,
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -53,7 +53,15 @@ func (r *queryResolver) FindImages(
 	imageIds []int,
 	ids []string,
 	filter *models.FindFilterType,
+	savedFilterID *string,
 ) (ret *FindImagesResultType, err error) {
+	imageFilter, filter, err = applySavedFilter(ctx, r, savedFilterID, models.FilterModeImages, imageFilter, filter, func(f *models.ImageFilterType) **models.MultiCriterionInput {
+		return &f.SavedFilters
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	if len(ids) > 0 {
 		imageIds, err = stringslice.StringSliceToIntSlice(ids)
 		if err != nil {
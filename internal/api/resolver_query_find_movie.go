@@ -24,7 +24,14 @@ func (r *queryResolver) FindMovie(ctx context.Context, id string) (ret *models.M
 	return ret, nil
 }
 
-func (r *queryResolver) FindMovies(ctx context.Context, movieFilter *models.MovieFilterType, filter *models.FindFilterType, ids []string) (ret *FindMoviesResultType, err error) {
+func (r *queryResolver) FindMovies(ctx context.Context, movieFilter *models.MovieFilterType, filter *models.FindFilterType, ids []string, savedFilterID *string) (ret *FindMoviesResultType, err error) {
+	movieFilter, filter, err = applySavedFilter(ctx, r, savedFilterID, models.FilterModeMovies, movieFilter, filter, func(f *models.MovieFilterType) **models.MultiCriterionInput {
+		return &f.SavedFilters
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	idInts, err := stringslice.StringSliceToIntSlice(ids)
 	if err != nil {
 		return nil, err
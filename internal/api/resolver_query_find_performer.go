@@ -24,7 +24,14 @@ func (r *queryResolver) FindPerformer(ctx context.Context, id string) (ret *mode
 	return ret, nil
 }
 
-func (r *queryResolver) FindPerformers(ctx context.Context, performerFilter *models.PerformerFilterType, filter *models.FindFilterType, performerIDs []int, ids []string) (ret *FindPerformersResultType, err error) {
+func (r *queryResolver) FindPerformers(ctx context.Context, performerFilter *models.PerformerFilterType, filter *models.FindFilterType, performerIDs []int, ids []string, savedFilterID *string) (ret *FindPerformersResultType, err error) {
+	performerFilter, filter, err = applySavedFilter(ctx, r, savedFilterID, models.FilterModePerformers, performerFilter, filter, func(f *models.PerformerFilterType) **models.MultiCriterionInput {
+		return &f.SavedFilters
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	if len(ids) > 0 {
 		performerIDs, err = stringslice.StringSliceToIntSlice(ids)
 		if err != nil {
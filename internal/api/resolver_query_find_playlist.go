@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *queryResolver) FindPlaylist(ctx context.Context, id string) (ret *models.Playlist, err error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Playlist.Find(ctx, idInt)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *queryResolver) FindPlaylists(ctx context.Context, filter *models.FindFilterType) (ret *FindPlaylistsResultType, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		playlists, total, err := r.repository.Playlist.Query(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		ret = &FindPlaylistsResultType{
+			Count:     total,
+			Playlists: playlists,
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
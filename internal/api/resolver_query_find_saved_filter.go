@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -73,3 +74,86 @@ func (r *queryResolver) FindDefaultFilter(ctx context.Context, mode models.Filte
 
 	return ret, nil
 }
+
+// applySavedFilter returns a copy of objectFilter restricted to the objects
+// matching the saved filter with the provided id, and the find filter of the
+// saved filter, overridden by the non-nil fields of findFilter. criterion
+// returns the saved filters criterion of the object filter. The filters are
+// returned unchanged if savedFilterID is nil.
+func applySavedFilter[T any](ctx context.Context, r *queryResolver, savedFilterID *string, mode models.FilterMode, objectFilter *T, findFilter *models.FindFilterType, criterion func(*T) **models.MultiCriterionInput) (*T, *models.FindFilterType, error) {
+	if savedFilterID == nil {
+		return objectFilter, findFilter, nil
+	}
+
+	id, err := strconv.Atoi(*savedFilterID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var savedFilter *models.SavedFilter
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		savedFilter, err = r.repository.SavedFilter.Find(ctx, id)
+		return err
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	if savedFilter == nil {
+		return nil, nil, fmt.Errorf("saved filter %d not found", id)
+	}
+	if savedFilter.Mode != mode {
+		return nil, nil, fmt.Errorf("saved filter %d has mode %s, expected %s", id, savedFilter.Mode, mode)
+	}
+
+	var ret T
+	if objectFilter != nil {
+		ret = *objectFilter
+	}
+
+	c := criterion(&ret)
+	switch {
+	case *c == nil:
+		*c = &models.MultiCriterionInput{
+			Value:    []string{*savedFilterID},
+			Modifier: models.CriterionModifierIncludesAll,
+		}
+	case (*c).Modifier == models.CriterionModifierIncludesAll:
+		v := **c
+		v.Value = append(append([]string(nil), v.Value...), *savedFilterID)
+		*c = &v
+	default:
+		return nil, nil, fmt.Errorf("saved_filter_id cannot be combined with a saved_filters criterion using the %s modifier", (*c).Modifier)
+	}
+
+	return &ret, savedFilter.GetFindFilter(findFilter), nil
+}
+
+// savedFilterCount returns the number of objects matching the saved filter.
+// Must be called within a transaction.
+func savedFilterCount(ctx context.Context, repo models.Repository, savedFilter *models.SavedFilter) (int, error) {
+	c := &models.MultiCriterionInput{
+		Value:    []string{strconv.Itoa(savedFilter.ID)},
+		Modifier: models.CriterionModifierIncludes,
+	}
+
+	switch savedFilter.Mode {
+	case models.FilterModeScenes:
+		return repo.Scene.QueryCount(ctx, &models.SceneFilterType{SavedFilters: c}, nil)
+	case models.FilterModeImages:
+		return repo.Image.QueryCount(ctx, &models.ImageFilterType{SavedFilters: c}, nil)
+	case models.FilterModeGalleries:
+		return repo.Gallery.QueryCount(ctx, &models.GalleryFilterType{SavedFilters: c}, nil)
+	case models.FilterModePerformers:
+		return repo.Performer.QueryCount(ctx, &models.PerformerFilterType{SavedFilters: c}, nil)
+	case models.FilterModeStudios:
+		return repo.Studio.QueryCount(ctx, &models.StudioFilterType{SavedFilters: c}, nil)
+	case models.FilterModeTags:
+		return repo.Tag.QueryCount(ctx, &models.TagFilterType{SavedFilters: c}, nil)
+	case models.FilterModeMovies:
+		return repo.Movie.QueryCount(ctx, &models.MovieFilterType{SavedFilters: c}, nil)
+	case models.FilterModeSceneMarkers:
+		return repo.SceneMarker.QueryCount(ctx, &models.SceneMarkerFilterType{SavedFilters: c}, nil)
+	}
+
+	return 0, fmt.Errorf("unsupported saved filter mode %q", savedFilter.Mode)
+}
@@ -81,7 +81,15 @@ func (r *queryResolver) FindScenes(
 	sceneIDs []int,
 	ids []string,
 	filter *models.FindFilterType,
+	savedFilterID *string,
 ) (ret *FindScenesResultType, err error) {
+	sceneFilter, filter, err = applySavedFilter(ctx, r, savedFilterID, models.FilterModeScenes, sceneFilter, filter, func(f *models.SceneFilterType) **models.MultiCriterionInput {
+		return &f.SavedFilters
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	if len(ids) > 0 {
 		sceneIDs, err = stringslice.StringSliceToIntSlice(ids)
 		if err != nil {
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *queryResolver) FindStashBoxSubmissions(ctx context.Context, submissionFilter *models.StashBoxSubmissionFilterType, filter *models.FindFilterType) (ret *FindStashBoxSubmissionsResultType, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		submissions, total, err := r.repository.StashBoxSubmission.Query(ctx, submissionFilter, filter)
+		if err != nil {
+			return err
+		}
+
+		ret = &FindStashBoxSubmissionsResultType{
+			Count:       total,
+			Submissions: submissions,
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *queryResolver) FindStashBoxUpdates(ctx context.Context, updateFilter *models.StashBoxUpdateFilterType, filter *models.FindFilterType) (ret *FindStashBoxUpdatesResultType, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		updates, total, err := r.repository.StashBoxUpdate.Query(ctx, updateFilter, filter)
+		if err != nil {
+			return err
+		}
+
+		ret = &FindStashBoxUpdatesResultType{
+			Count:   total,
+			Updates: updates,
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
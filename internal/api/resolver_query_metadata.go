@@ -9,3 +9,7 @@ import (
 func (r *queryResolver) SystemStatus(ctx context.Context) (*manager.SystemStatus, error) {
 	return manager.GetInstance().GetSystemStatus(), nil
 }
+
+func (r *queryResolver) DatabaseBackups(ctx context.Context) ([]*manager.DatabaseBackup, error) {
+	return manager.GetInstance().DatabaseBackups.List()
+}
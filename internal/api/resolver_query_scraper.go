@@ -127,6 +127,23 @@ func filterGalleryTags(g []*scraper.ScrapedGallery) {
 	}
 }
 
+// filterImageTags removes tags matching excluded tag patterns from the provided scraped images
+func filterImageTags(i []*scraper.ScrapedImage) {
+	excludeRegexps := compileRegexps(manager.GetInstance().Config.GetScraperExcludeTagPatterns())
+
+	var ignoredTags []string
+
+	for _, s := range i {
+		var ignored []string
+		s.Tags, ignored = filterTags(excludeRegexps, s.Tags)
+		ignoredTags = sliceutil.AppendUniques(ignoredTags, ignored)
+	}
+
+	if len(ignoredTags) > 0 {
+		logger.Debugf("Scraping ignored tags: %s", strings.Join(ignoredTags, ", "))
+	}
+}
+
 // filterGalleryTags removes tags matching excluded tag patterns from the provided scraped galleries
 func filterPerformerTags(p []*models.ScrapedPerformer) {
 	excludeRegexps := compileRegexps(manager.GetInstance().Config.GetScraperExcludeTagPatterns())
@@ -197,6 +214,24 @@ func (r *queryResolver) ScrapeGalleryURL(ctx context.Context, url string) (*scra
 	return ret, nil
 }
 
+func (r *queryResolver) ScrapeImageURL(ctx context.Context, url string) (*scraper.ScrapedImage, error) {
+	content, err := r.scraperCache().ScrapeURL(ctx, url, scraper.ScrapeContentTypeImage)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := marshalScrapedImage(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if ret != nil {
+		filterImageTags([]*scraper.ScrapedImage{ret})
+	}
+
+	return ret, nil
+}
+
 func (r *queryResolver) ScrapeMovieURL(ctx context.Context, url string) (*models.ScrapedMovie, error) {
 	content, err := r.scraperCache().ScrapeURL(ctx, url, scraper.ScrapeContentTypeMovie)
 	if err != nil {
@@ -213,6 +248,28 @@ func (r *queryResolver) ScrapeMovieURL(ctx context.Context, url string) (*models
 	return ret, nil
 }
 
+func (r *queryResolver) ScrapeStudioURL(ctx context.Context, url string) (*models.ScrapedStudio, error) {
+	content, err := r.scraperCache().ScrapeURL(ctx, url, scraper.ScrapeContentTypeStudio)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalScrapedStudio(content)
+}
+
+func (r *queryResolver) ScrapeTagURL(ctx context.Context, url string) (*models.ScrapedTag, error) {
+	content, err := r.scraperCache().ScrapeURL(ctx, url, scraper.ScrapeContentTypeTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalScrapedTag(content)
+}
+
+func (r *queryResolver) TestScraper(ctx context.Context, input scraper.ScraperTestInput) (*scraper.ScraperTestResult, error) {
+	return r.scraperCache().TestScraper(ctx, input)
+}
+
 func (r *queryResolver) ScrapeSingleScene(ctx context.Context, source scraper.Source, input ScrapeSingleSceneInput) ([]*scraper.ScrapedScene, error) {
 	var ret []*scraper.ScrapedScene
 
@@ -308,7 +365,43 @@ func (r *queryResolver) ScrapeMultiScenes(ctx context.Context, source scraper.So
 }
 
 func (r *queryResolver) ScrapeSingleStudio(ctx context.Context, source scraper.Source, input ScrapeSingleStudioInput) ([]*models.ScrapedStudio, error) {
-	if source.StashBoxIndex != nil || source.StashBoxEndpoint != nil {
+	switch {
+	case source.ScraperID != nil:
+		var content []scraper.ScrapedContent
+
+		switch {
+		case input.StudioID != nil:
+			studioID, err := strconv.Atoi(*input.StudioID)
+			if err != nil {
+				return nil, fmt.Errorf("%w: studio id is not an integer: '%s'", ErrInput, *input.StudioID)
+			}
+			c, err := r.scraperCache().ScrapeID(ctx, *source.ScraperID, studioID, scraper.ScrapeContentTypeStudio)
+			if err != nil {
+				return nil, err
+			}
+			content = []scraper.ScrapedContent{c}
+		case input.StudioInput != nil:
+			c, err := r.scraperCache().ScrapeFragment(ctx, *source.ScraperID, scraper.Input{Studio: input.StudioInput})
+			if err != nil {
+				return nil, err
+			}
+			content = []scraper.ScrapedContent{c}
+		case input.Query != nil:
+			var err error
+			content, err = r.scraperCache().ScrapeName(ctx, *source.ScraperID, *input.Query, scraper.ScrapeContentTypeStudio)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, ErrNotImplemented
+		}
+
+		return marshalScrapedStudios(content)
+	case source.StashBoxIndex != nil || source.StashBoxEndpoint != nil:
+		if input.Query == nil {
+			return nil, fmt.Errorf("%w: query must be set", ErrInput)
+		}
+
 		b, err := resolveStashBox(source.StashBoxIndex, source.StashBoxEndpoint)
 		if err != nil {
 			return nil, err
@@ -332,7 +425,48 @@ func (r *queryResolver) ScrapeSingleStudio(ctx context.Context, source scraper.S
 		return nil, nil
 	}
 
-	return nil, errors.New("stash_box_index must be set")
+	return nil, errors.New("scraper_id or stash_box_index must be set")
+}
+
+func (r *queryResolver) ScrapeSingleTag(ctx context.Context, source scraper.Source, input ScrapeSingleTagInput) ([]*models.ScrapedTag, error) {
+	if source.StashBoxIndex != nil || source.StashBoxEndpoint != nil {
+		return nil, ErrNotSupported
+	}
+
+	if source.ScraperID == nil {
+		return nil, fmt.Errorf("%w: scraper_id must be set", ErrInput)
+	}
+
+	var content []scraper.ScrapedContent
+
+	switch {
+	case input.TagID != nil:
+		tagID, err := strconv.Atoi(*input.TagID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: tag id is not an integer: '%s'", ErrInput, *input.TagID)
+		}
+		c, err := r.scraperCache().ScrapeID(ctx, *source.ScraperID, tagID, scraper.ScrapeContentTypeTag)
+		if err != nil {
+			return nil, err
+		}
+		content = []scraper.ScrapedContent{c}
+	case input.TagInput != nil:
+		c, err := r.scraperCache().ScrapeFragment(ctx, *source.ScraperID, scraper.Input{Tag: input.TagInput})
+		if err != nil {
+			return nil, err
+		}
+		content = []scraper.ScrapedContent{c}
+	case input.Query != nil:
+		var err error
+		content, err = r.scraperCache().ScrapeName(ctx, *source.ScraperID, *input.Query, scraper.ScrapeContentTypeTag)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrNotImplemented
+	}
+
+	return marshalScrapedTags(content)
 }
 
 func (r *queryResolver) ScrapeSinglePerformer(ctx context.Context, source scraper.Source, input ScrapeSinglePerformerInput) ([]*models.ScrapedPerformer, error) {
@@ -458,6 +592,50 @@ func (r *queryResolver) ScrapeSingleGallery(ctx context.Context, source scraper.
 	return ret, nil
 }
 
+func (r *queryResolver) ScrapeSingleImage(ctx context.Context, source scraper.Source, input ScrapeSingleImageInput) ([]*scraper.ScrapedImage, error) {
+	var ret []*scraper.ScrapedImage
+
+	if source.StashBoxIndex != nil || source.StashBoxEndpoint != nil {
+		return nil, ErrNotSupported
+	}
+
+	if source.ScraperID == nil {
+		return nil, fmt.Errorf("%w: scraper_id must be set", ErrInput)
+	}
+
+	var c scraper.ScrapedContent
+
+	switch {
+	case input.ImageID != nil:
+		imageID, err := strconv.Atoi(*input.ImageID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: image id is not an integer: '%s'", ErrInput, *input.ImageID)
+		}
+		c, err = r.scraperCache().ScrapeID(ctx, *source.ScraperID, imageID, scraper.ScrapeContentTypeImage)
+		if err != nil {
+			return nil, err
+		}
+		ret, err = marshalScrapedImages([]scraper.ScrapedContent{c})
+		if err != nil {
+			return nil, err
+		}
+	case input.ImageInput != nil:
+		c, err := r.scraperCache().ScrapeFragment(ctx, *source.ScraperID, scraper.Input{Image: input.ImageInput})
+		if err != nil {
+			return nil, err
+		}
+		ret, err = marshalScrapedImages([]scraper.ScrapedContent{c})
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrNotImplemented
+	}
+
+	filterImageTags(ret)
+	return ret, nil
+}
+
 func (r *queryResolver) ScrapeSingleMovie(ctx context.Context, source scraper.Source, input ScrapeSingleMovieInput) ([]*models.ScrapedMovie, error) {
 	return nil, ErrNotSupported
 }
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	defaultSearchLimit = 25
+	searchSort         = "relevance"
+)
+
+// searchCandidate is a search result before ranking.
+type searchCandidate struct {
+	typ    SearchResultType
+	object SearchResultObject
+	name   string
+	// position is the position of the object in the results for its type
+	position int
+}
+
+// searchNameScore returns how closely the name of an object matches the
+// query.
+func searchNameScore(name string, q string) float64 {
+	name = strings.ToLower(strings.TrimSpace(name))
+	q = strings.ToLower(strings.TrimSpace(q))
+
+	switch {
+	case name == "" || q == "":
+		return 0
+	case name == q:
+		return 3
+	case strings.HasPrefix(name, q):
+		return 2
+	case strings.Contains(name, q):
+		return 1
+	}
+
+	return 0
+}
+
+// rankSearchResults orders the candidates by how well they match the query,
+// returning at most limit results. Objects with names matching the query are
+// ranked first, followed by the order of the results for each type.
+func rankSearchResults(candidates []searchCandidate, q string, limit int) []*SearchResult {
+	ret := make([]*SearchResult, len(candidates))
+	for i, c := range candidates {
+		ret[i] = &SearchResult{
+			Type:   c.typ,
+			Score:  searchNameScore(c.name, q) + 1/float64(c.position+1),
+			Object: c.object,
+		}
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Score > ret[j].Score
+	})
+
+	if len(ret) > limit {
+		ret = ret[:limit]
+	}
+
+	return ret
+}
+
+func (r *queryResolver) Search(ctx context.Context, q string, types []SearchResultType, limit *int) ([]*SearchResult, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return []*SearchResult{}, nil
+	}
+
+	perPage := defaultSearchLimit
+	if limit != nil && *limit > 0 {
+		perPage = *limit
+	}
+
+	if len(types) == 0 {
+		types = AllSearchResultType
+	}
+
+	sortBy := searchSort
+	direction := models.SortDirectionEnumDesc
+	findFilter := &models.FindFilterType{
+		Q:         &q,
+		PerPage:   &perPage,
+		Sort:      &sortBy,
+		Direction: &direction,
+	}
+
+	var candidates []searchCandidate
+	add := func(typ SearchResultType, object SearchResultObject, name string, position int) {
+		candidates = append(candidates, searchCandidate{
+			typ:      typ,
+			object:   object,
+			name:     name,
+			position: position,
+		})
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		for _, typ := range types {
+			switch typ {
+			case SearchResultTypeScene:
+				result, err := r.repository.Scene.Query(ctx, models.SceneQueryOptions{
+					QueryOptions: models.QueryOptions{
+						FindFilter: findFilter,
+					},
+				})
+				if err != nil {
+					return err
+				}
+				scenes, err := result.Resolve(ctx)
+				if err != nil {
+					return err
+				}
+				for i, s := range scenes {
+					add(typ, s, s.GetTitle(), i)
+				}
+			case SearchResultTypeImage:
+				result, err := r.repository.Image.Query(ctx, models.ImageQueryOptions{
+					QueryOptions: models.QueryOptions{
+						FindFilter: findFilter,
+					},
+				})
+				if err != nil {
+					return err
+				}
+				images, err := result.Resolve(ctx)
+				if err != nil {
+					return err
+				}
+				for i, img := range images {
+					add(typ, img, img.GetTitle(), i)
+				}
+			case SearchResultTypeGallery:
+				galleries, _, err := r.repository.Gallery.Query(ctx, nil, findFilter)
+				if err != nil {
+					return err
+				}
+				for i, g := range galleries {
+					add(typ, g, g.GetTitle(), i)
+				}
+			case SearchResultTypePerformer:
+				performers, _, err := r.repository.Performer.Query(ctx, nil, findFilter)
+				if err != nil {
+					return err
+				}
+				for i, p := range performers {
+					add(typ, p, p.Name, i)
+				}
+			case SearchResultTypeStudio:
+				studios, _, err := r.repository.Studio.Query(ctx, nil, findFilter)
+				if err != nil {
+					return err
+				}
+				for i, s := range studios {
+					add(typ, s, s.Name, i)
+				}
+			case SearchResultTypeTag:
+				tags, _, err := r.repository.Tag.Query(ctx, nil, findFilter)
+				if err != nil {
+					return err
+				}
+				for i, t := range tags {
+					add(typ, t, t.Name, i)
+				}
+			case SearchResultTypeMovie:
+				movies, _, err := r.repository.Movie.Query(ctx, nil, findFilter)
+				if err != nil {
+					return err
+				}
+				for i, m := range movies {
+					add(typ, m, m.Name, i)
+				}
+			case SearchResultTypeSceneMarker:
+				markers, _, err := r.repository.SceneMarker.Query(ctx, nil, findFilter)
+				if err != nil {
+					return err
+				}
+				for i, m := range markers {
+					add(typ, m, m.Title, i)
+				}
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return rankSearchResults(candidates, q, perPage), nil
+}
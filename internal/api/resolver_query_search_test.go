@@ -0,0 +1,53 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func TestRankSearchResults(t *testing.T) {
+	exactTag := &models.Tag{ID: 1, Name: "Outdoor"}
+	prefixPerformer := &models.Performer{ID: 2, Name: "Outdoors Girl"}
+	firstScene := &models.Scene{ID: 3, Title: "A day at the beach"}
+	secondScene := &models.Scene{ID: 4, Title: "Another day"}
+
+	candidates := []searchCandidate{
+		{SearchResultTypeScene, firstScene, firstScene.GetTitle(), 0},
+		{SearchResultTypeScene, secondScene, secondScene.GetTitle(), 1},
+		{SearchResultTypePerformer, prefixPerformer, prefixPerformer.Name, 0},
+		{SearchResultTypeTag, exactTag, exactTag.Name, 0},
+	}
+
+	got := rankSearchResults(candidates, "outdoor", 3)
+
+	var objects []SearchResultObject
+	for _, r := range got {
+		objects = append(objects, r.Object)
+	}
+
+	assert.Equal(t, []SearchResultObject{exactTag, prefixPerformer, firstScene}, objects)
+	assert.Equal(t, SearchResultTypeTag, got[0].Type)
+	assert.Greater(t, got[0].Score, got[1].Score)
+}
+
+func TestSearchNameScore(t *testing.T) {
+	tests := []struct {
+		name string
+		q    string
+		want float64
+	}{
+		{"Outdoor", "outdoor", 3},
+		{"Outdoors", "outdoor", 2},
+		{"Great Outdoors", "outdoor", 1},
+		{"Beach", "outdoor", 0},
+		{"", "outdoor", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, searchNameScore(tt.name, tt.q))
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+// savedFilterUpdateDelay is the time to wait after a database change before
+// recounting saved filters, so that bursts of changes, such as during a scan,
+// are handled together.
+const savedFilterUpdateDelay = time.Second
+
+// savedFilterState is the state of a saved filter used to detect changes.
+type savedFilterState struct {
+	savedFilter *models.SavedFilter
+	// definition is the encoded saved filter
+	definition string
+	count      int
+}
+
+// savedFilterUpdates returns the updates for saved filters that were changed,
+// created or destroyed between the old and new states, ordered by id.
+func savedFilterUpdates(old map[int]savedFilterState, new map[int]savedFilterState) []*SavedFilterUpdate {
+	var ret []*SavedFilterUpdate
+	for id, s := range new {
+		if o, found := old[id]; found && o.definition == s.definition && o.count == s.count {
+			continue
+		}
+
+		ret = append(ret, &SavedFilterUpdate{
+			ID:          strconv.Itoa(id),
+			SavedFilter: s.savedFilter,
+			Count:       s.count,
+		})
+	}
+
+	for id := range old {
+		if _, found := new[id]; !found {
+			ret = append(ret, &SavedFilterUpdate{
+				ID: strconv.Itoa(id),
+			})
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		a, _ := strconv.Atoi(ret[i].ID)
+		b, _ := strconv.Atoi(ret[j].ID)
+		return a < b
+	})
+
+	return ret
+}
+
+// savedFilterStates returns the current state of the saved filters with the
+// provided ids, or of all saved filters if ids is empty. Saved filters that
+// cannot be evaluated are logged and omitted.
+func (r *subscriptionResolver) savedFilterStates(ctx context.Context, ids []int) (map[int]savedFilterState, error) {
+	ret := make(map[int]savedFilterState)
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		var savedFilters []*models.SavedFilter
+		var err error
+		if len(ids) > 0 {
+			const ignoreNotFound = true
+			savedFilters, err = r.repository.SavedFilter.FindMany(ctx, ids, ignoreNotFound)
+		} else {
+			savedFilters, err = r.repository.SavedFilter.All(ctx)
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, f := range savedFilters {
+			if f == nil {
+				continue
+			}
+
+			count, err := savedFilterCount(ctx, r.repository, f)
+			if err != nil {
+				logger.Warnf("error counting saved filter %d: %v", f.ID, err)
+				continue
+			}
+
+			definition, err := json.Marshal(f)
+			if err != nil {
+				return err
+			}
+
+			ret[f.ID] = savedFilterState{
+				savedFilter: f,
+				definition:  string(definition),
+				count:       count,
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *subscriptionResolver) SavedFilterUpdateSubscribe(ctx context.Context, ids []string) (<-chan *SavedFilterUpdate, error) {
+	idInts, err := stringslice.StringSliceToIntSlice(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := manager.GetInstance().Database.SubscribeCommits(ctx)
+
+	state, err := r.savedFilterStates(ctx, idInts)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(chan *SavedFilterUpdate, 100)
+
+	go func() {
+		defer close(ret)
+
+		var recount <-chan time.Time
+		for {
+			select {
+			case _, ok := <-commits:
+				if !ok {
+					return
+				}
+				if recount == nil {
+					recount = time.After(savedFilterUpdateDelay)
+				}
+			case <-recount:
+				recount = nil
+
+				newState, err := r.savedFilterStates(ctx, idInts)
+				if err != nil {
+					logger.Errorf("error getting saved filter updates: %v", err)
+					continue
+				}
+
+				for _, u := range savedFilterUpdates(state, newState) {
+					select {
+					case ret <- u:
+					case <-ctx.Done():
+						return
+					}
+				}
+				state = newState
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ret, nil
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func TestSavedFilterUpdates(t *testing.T) {
+	unchanged := &models.SavedFilter{ID: 1, Name: "unchanged"}
+	recounted := &models.SavedFilter{ID: 2, Name: "recounted"}
+	renamed := &models.SavedFilter{ID: 3, Name: "renamed"}
+	created := &models.SavedFilter{ID: 5, Name: "created"}
+
+	old := map[int]savedFilterState{
+		1: {unchanged, "1", 10},
+		2: {recounted, "2", 10},
+		3: {renamed, "3", 10},
+		4: {&models.SavedFilter{ID: 4}, "4", 10},
+	}
+	new := map[int]savedFilterState{
+		1: {unchanged, "1", 10},
+		2: {recounted, "2", 11},
+		3: {renamed, "3 renamed", 10},
+		5: {created, "5", 1},
+	}
+
+	assert.Equal(t, []*SavedFilterUpdate{
+		{ID: "2", SavedFilter: recounted, Count: 11},
+		{ID: "3", SavedFilter: renamed, Count: 10},
+		{ID: "4"},
+		{ID: "5", SavedFilter: created, Count: 1},
+	}, savedFilterUpdates(old, new))
+}
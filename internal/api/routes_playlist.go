@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/stashapp/stash/internal/api/urlbuilders"
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/playlist"
+)
+
+type PlaylistFinder interface {
+	Find(ctx context.Context, id int) (*models.Playlist, error)
+	GetItems(ctx context.Context, playlistID int) ([]models.PlaylistItem, error)
+}
+
+type playlistRoutes struct {
+	routes
+	playlistFinder    PlaylistFinder
+	sceneFinder       models.SceneGetter
+	sceneMarkerFinder models.SceneMarkerGetter
+	imageFinder       models.ImageGetter
+	fileGetter        models.FileGetter
+}
+
+func (rs playlistRoutes) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Route("/{playlistId}", func(r chi.Router) {
+		r.Use(rs.PlaylistCtx)
+		r.Get("/m3u", rs.M3U)
+	})
+
+	return r
+}
+
+// m3uEntry returns the M3U entry of the playlist item, or nil if the object of
+// the item does not exist.
+func (rs playlistRoutes) m3uEntry(ctx context.Context, baseURL string, apiKey string, item models.PlaylistItem) (*playlist.M3UEntry, error) {
+	switch {
+	case item.SceneMarkerID != nil:
+		marker, err := rs.sceneMarkerFinder.Find(ctx, *item.SceneMarkerID)
+		if err != nil || marker == nil {
+			return nil, err
+		}
+
+		scene, err := rs.sceneFinder.Find(ctx, marker.SceneID)
+		if err != nil || scene == nil {
+			return nil, err
+		}
+
+		start := marker.Seconds
+		ret := &playlist.M3UEntry{
+			Title: fmt.Sprintf("%s - %s", scene.GetTitle(), marker.Title),
+			URL:   urlbuilders.NewSceneURLBuilder(baseURL, scene).GetStreamURL(apiKey).String(),
+			Start: &start,
+			Stop:  item.EndSeconds,
+		}
+		if ret.Stop != nil {
+			duration := *ret.Stop - start
+			ret.Duration = &duration
+		}
+
+		return ret, nil
+	case item.SceneID != nil:
+		scene, err := rs.sceneFinder.Find(ctx, *item.SceneID)
+		if err != nil || scene == nil {
+			return nil, err
+		}
+
+		if err := scene.LoadPrimaryFile(ctx, rs.fileGetter); err != nil {
+			return nil, err
+		}
+
+		ret := &playlist.M3UEntry{
+			Title: scene.GetTitle(),
+			URL:   urlbuilders.NewSceneURLBuilder(baseURL, scene).GetStreamURL(apiKey).String(),
+		}
+		if f := scene.Files.Primary(); f != nil {
+			duration := f.DurationFinite()
+			ret.Duration = &duration
+		}
+
+		return ret, nil
+	case item.ImageID != nil:
+		image, err := rs.imageFinder.Find(ctx, *item.ImageID)
+		if err != nil || image == nil {
+			return nil, err
+		}
+
+		u, err := url.Parse(urlbuilders.NewImageURLBuilder(baseURL, image).GetImageURL())
+		if err != nil {
+			return nil, err
+		}
+		if apiKey != "" {
+			v := u.Query()
+			v.Set("apikey", apiKey)
+			u.RawQuery = v.Encode()
+		}
+
+		return &playlist.M3UEntry{
+			Title: image.GetTitle(),
+			URL:   u.String(),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+func (rs playlistRoutes) M3U(w http.ResponseWriter, r *http.Request) {
+	p := r.Context().Value(playlistKey).(*models.Playlist)
+	baseURL, _ := r.Context().Value(BaseURLCtxKey).(string)
+	apiKey := manager.GetInstance().Config.GetAPIKey()
+
+	var entries []playlist.M3UEntry
+	readTxnErr := rs.withReadTxn(r, func(ctx context.Context) error {
+		items, err := rs.playlistFinder.GetItems(ctx, p.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			e, err := rs.m3uEntry(ctx, baseURL, apiKey, item)
+			if err != nil {
+				return err
+			}
+			if e != nil {
+				entries = append(entries, *e)
+			}
+		}
+
+		return nil
+	})
+	if errors.Is(readTxnErr, context.Canceled) {
+		return
+	}
+	if readTxnErr != nil {
+		logger.Warnf("read transaction error on fetch playlist items: %v", readTxnErr)
+		http.Error(w, readTxnErr.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "audio/x-mpegurl")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.m3u\"", fsutil.SanitiseBasename(p.Name)))
+	if err := playlist.WriteM3U(w, p.Name, entries); err != nil {
+		logger.Warnf("error writing playlist %d: %v", p.ID, err)
+	}
+}
+
+func (rs playlistRoutes) PlaylistCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		playlistID, err := strconv.Atoi(chi.URLParam(r, "playlistId"))
+		if err != nil {
+			http.Error(w, http.StatusText(404), 404)
+			return
+		}
+
+		var p *models.Playlist
+		_ = rs.withReadTxn(r, func(ctx context.Context) error {
+			p, _ = rs.playlistFinder.Find(ctx, playlistID)
+			return nil
+		})
+		if p == nil {
+			http.Error(w, http.StatusText(404), 404)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), playlistKey, p)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
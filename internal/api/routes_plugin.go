@@ -23,6 +23,8 @@ func (rs pluginRoutes) Routes() chi.Router {
 		r.Get("/assets/*", rs.Assets)
 		r.Get("/javascript", rs.Javascript)
 		r.Get("/css", rs.CSS)
+		r.HandleFunc("/api", rs.API)
+		r.HandleFunc("/api/*", rs.API)
 	})
 
 	return r
@@ -88,6 +90,27 @@ func (rs pluginRoutes) CSS(w http.ResponseWriter, r *http.Request) {
 	serveFiles(w, r, p.UI.CSS)
 }
 
+// API passes the request to the plugin's service. The request path is
+// made relative to /plugin/{pluginId}/api.
+func (rs pluginRoutes) API(w http.ResponseWriter, r *http.Request) {
+	p := r.Context().Value(pluginKey).(*plugin.Plugin)
+
+	if !p.Enabled {
+		http.Error(w, "plugin disabled", http.StatusBadRequest)
+		return
+	}
+
+	prefix := "/plugin/" + chi.URLParam(r, "pluginId") + "/api"
+
+	r.URL.Path = strings.Replace(r.URL.Path, prefix, "", 1)
+	if r.URL.Path == "" {
+		r.URL.Path = "/"
+	}
+	r.URL.RawPath = ""
+
+	rs.pluginCache.ServeServiceHTTP(p.ID, w, r)
+}
+
 func (rs pluginRoutes) PluginCtx(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		p := rs.pluginCache.GetPlugin(chi.URLParam(r, "pluginId"))
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stashapp/stash/pkg/session"
+)
+
+var testSessionStoreKey = []byte("0123456789abcdef0123456789abcdef")
+
+type testPluginConfig struct {
+	pluginsPath     string
+	disabledPlugins []string
+}
+
+func (c *testPluginConfig) GetHost() string              { return "localhost" }
+func (c *testPluginConfig) GetPort() int                 { return 9999 }
+func (c *testPluginConfig) GetConfigPathAbs() string     { return c.pluginsPath }
+func (c *testPluginConfig) HasTLSConfig() bool           { return false }
+func (c *testPluginConfig) GetPluginsPath() string       { return c.pluginsPath }
+func (c *testPluginConfig) GetDisabledPlugins() []string { return c.disabledPlugins }
+func (c *testPluginConfig) GetPythonPath() string        { return "" }
+func (c *testPluginConfig) GetLibraryPaths() []string    { return nil }
+func (c *testPluginConfig) GetUsername() string          { return "" }
+func (c *testPluginConfig) GetAPIKey() string            { return "" }
+func (c *testPluginConfig) GetSessionStoreKey() []byte   { return testSessionStoreKey }
+func (c *testPluginConfig) GetMaxSessionAge() int        { return 0 }
+
+func (c *testPluginConfig) ValidateCredentials(username string, password string) bool {
+	return false
+}
+
+const (
+	testServicePluginYAML = `name: Service
+exec:
+  - service.js
+interface: js
+service: {}
+`
+	// responds with the request path
+	testServicePluginJS = `service.OnHTTP(function(req) { return { body: req.method + " " + req.path + "?" + req.query }; });`
+)
+
+func TestPluginRoutesAPI(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "service.yml"), []byte(testServicePluginYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "service.js"), []byte(testServicePluginJS), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &testPluginConfig{pluginsPath: dir}
+	pluginCache := plugin.NewCache(config)
+	pluginCache.RegisterSessionStore(session.NewStore(config))
+	pluginCache.ReloadPlugins()
+	pluginCache.StartServices()
+	defer pluginCache.StopServices()
+
+	r := chi.NewRouter()
+	r.Mount("/plugin", pluginRoutes{pluginCache: pluginCache}.Routes())
+	serve := func(method string, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	// wait for the service to start
+	deadline := time.Now().Add(10 * time.Second)
+	for serve(http.MethodGet, "/plugin/service/api").Code == http.StatusServiceUnavailable && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{"root", http.MethodGet, "/plugin/service/api", http.StatusOK, "GET /?"},
+		{"root slash", http.MethodGet, "/plugin/service/api/", http.StatusOK, "GET /?"},
+		{"sub path", http.MethodPost, "/plugin/service/api/foo/bar?x=1", http.StatusOK, "POST /foo/bar?x=1"},
+		{"escaped path", http.MethodGet, "/plugin/service/api/foo%2Fbar", http.StatusOK, "GET /foo/bar?"},
+		{"unknown plugin", http.MethodGet, "/plugin/unknown/api/foo", http.StatusNotFound, "Not Found\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(tt.method, tt.path)
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+
+	t.Run("disabled plugin", func(t *testing.T) {
+		config.disabledPlugins = []string{"service"}
+		defer func() {
+			config.disabledPlugins = nil
+		}()
+
+		w := serve(http.MethodGet, "/plugin/service/api/foo")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("stopped service", func(t *testing.T) {
+		pluginCache.StopServices()
+
+		w := serve(http.MethodGet, "/plugin/service/api/foo")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+}
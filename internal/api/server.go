@@ -198,6 +198,9 @@ func Initialize() (*Server, error) {
 	gqlHandler := visitedPluginHandler(dataloaders.Middleware(http.HandlerFunc(gqlHandlerFunc)))
 	pluginCache.RegisterGQLHandler(gqlHandler)
 
+	// services require the GQL handler, so can only be started now
+	pluginCache.StartServices()
+
 	r.HandleFunc(gqlEndpoint, gqlHandlerFunc)
 	r.HandleFunc(playgroundEndpoint, func(w http.ResponseWriter, r *http.Request) {
 		setPageSecurityHeaders(w, r, pluginCache.ListPlugins())
@@ -210,6 +213,7 @@ func Initialize() (*Server, error) {
 	r.Mount("/image", server.getImageRoutes())
 	r.Mount("/studio", server.getStudioRoutes())
 	r.Mount("/movie", server.getMovieRoutes())
+	r.Mount("/playlist", server.getPlaylistRoutes())
 	r.Mount("/tag", server.getTagRoutes())
 	r.Mount("/downloads", server.getDownloadsRoutes())
 	r.Mount("/plugin", server.getPluginRoutes())
@@ -351,6 +355,18 @@ func (s *Server) getMovieRoutes() chi.Router {
 	}.Routes()
 }
 
+func (s *Server) getPlaylistRoutes() chi.Router {
+	repo := s.manager.Repository
+	return playlistRoutes{
+		routes:            routes{txnManager: repo.TxnManager},
+		playlistFinder:    repo.Playlist,
+		sceneFinder:       repo.Scene,
+		sceneMarkerFinder: repo.SceneMarker,
+		imageFinder:       repo.Image,
+		fileGetter:        repo.File,
+	}.Routes()
+}
+
 func (s *Server) getTagRoutes() chi.Router {
 	repo := s.manager.Repository
 	return tagRoutes{
@@ -0,0 +1,36 @@
+package urlbuilders
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type PlaylistURLBuilder struct {
+	BaseURL    string
+	PlaylistID string
+}
+
+func NewPlaylistURLBuilder(baseURL string, playlist *models.Playlist) PlaylistURLBuilder {
+	return PlaylistURLBuilder{
+		BaseURL:    baseURL,
+		PlaylistID: strconv.Itoa(playlist.ID),
+	}
+}
+
+func (b PlaylistURLBuilder) GetM3UURL(apiKey string) *url.URL {
+	u, err := url.Parse(fmt.Sprintf("%s/playlist/%s/m3u", b.BaseURL, b.PlaylistID))
+	if err != nil {
+		// shouldn't happen
+		panic(err)
+	}
+
+	if apiKey != "" {
+		v := u.Query()
+		v.Set("apikey", apiKey)
+		u.RawQuery = v.Encode()
+	}
+	return u
+}
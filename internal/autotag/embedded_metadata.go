@@ -0,0 +1,228 @@
+package autotag
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/match"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+type SceneEmbeddedMetadataUpdater interface {
+	models.TagIDLoader
+	models.PerformerIDLoader
+	models.SceneUpdater
+}
+
+type ImageEmbeddedMetadataUpdater interface {
+	models.TagIDLoader
+	models.PerformerIDLoader
+	models.ImageUpdater
+}
+
+// EmbeddedMetadata populates scenes and images from the metadata embedded in
+// their primary file. Only empty fields are set, and only existing
+// performers and tags are added.
+type EmbeddedMetadata struct {
+	Mappings        models.EmbeddedMetadataMappings
+	PerformerFinder match.PerformerFinder
+	TagFinder       models.TagQueryer
+}
+
+// embeddedMetadataChanges holds the fields to set from the embedded metadata.
+// Fields that are already set on the object are left empty.
+type embeddedMetadataChanges struct {
+	title        models.OptionalString
+	date         models.OptionalDate
+	details      models.OptionalString
+	photographer models.OptionalString
+	performerIDs []int
+	tagIDs       []int
+}
+
+func (c embeddedMetadataChanges) empty() bool {
+	return !c.title.Set && !c.date.Set && !c.details.Set && !c.photographer.Set && len(c.performerIDs) == 0 && len(c.tagIDs) == 0
+}
+
+func setIfEmpty(existing string, v string) models.OptionalString {
+	if existing != "" || v == "" {
+		return models.OptionalString{}
+	}
+	return models.NewOptionalString(v)
+}
+
+func (e *EmbeddedMetadata) performerIDs(ctx context.Context, names []string, existing []int) ([]int, error) {
+	var ret []int
+	for _, name := range names {
+		name := name
+		p := &models.ScrapedPerformer{Name: &name}
+		if err := match.ScrapedPerformer(ctx, e.PerformerFinder, p, nil); err != nil {
+			return nil, fmt.Errorf("matching performer %q: %w", name, err)
+		}
+		if p.StoredID == nil {
+			continue
+		}
+
+		id, err := strconv.Atoi(*p.StoredID)
+		if err != nil {
+			return nil, err
+		}
+		if !sliceutil.Contains(existing, id) {
+			ret = sliceutil.AppendUnique(ret, id)
+		}
+	}
+
+	return ret, nil
+}
+
+func (e *EmbeddedMetadata) tagIDs(ctx context.Context, names []string, existing []int) ([]int, error) {
+	var ret []int
+	for _, name := range names {
+		t := &models.ScrapedTag{Name: name}
+		if err := match.ScrapedTag(ctx, e.TagFinder, t); err != nil {
+			return nil, fmt.Errorf("matching tag %q: %w", name, err)
+		}
+		if t.StoredID == nil {
+			continue
+		}
+
+		id, err := strconv.Atoi(*t.StoredID)
+		if err != nil {
+			return nil, err
+		}
+		if !sliceutil.Contains(existing, id) {
+			ret = sliceutil.AppendUnique(ret, id)
+		}
+	}
+
+	return ret, nil
+}
+
+// changes returns the changes for the values that are not already set.
+func (e *EmbeddedMetadata) changes(ctx context.Context, values models.EmbeddedMetadataValues, title string, date *models.Date, details string, performerIDs []int, tagIDs []int) (*embeddedMetadataChanges, error) {
+	ret := &embeddedMetadataChanges{
+		title:   setIfEmpty(title, values.Title),
+		details: setIfEmpty(details, values.Details),
+	}
+
+	if date == nil && values.Date != "" {
+		d, err := models.ParseDate(values.Date)
+		if err == nil {
+			ret.date = models.NewOptionalDate(d)
+		}
+	}
+
+	var err error
+	ret.performerIDs, err = e.performerIDs(ctx, values.Performers, performerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.tagIDs, err = e.tagIDs(ctx, values.Tags, tagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// ApplyScene populates the scene from the embedded metadata of its primary
+// file.
+func (e *EmbeddedMetadata) ApplyScene(ctx context.Context, s *models.Scene, rw SceneEmbeddedMetadataUpdater, fileGetter models.FileGetter) error {
+	if err := s.LoadPrimaryFile(ctx, fileGetter); err != nil {
+		return err
+	}
+
+	f := s.Files.Primary()
+	if f == nil {
+		return nil
+	}
+
+	values := e.Mappings.Values(f.Metadata)
+	if values.IsEmpty() {
+		return nil
+	}
+
+	if err := s.LoadTagIDs(ctx, rw); err != nil {
+		return err
+	}
+	if err := s.LoadPerformerIDs(ctx, rw); err != nil {
+		return err
+	}
+
+	c, err := e.changes(ctx, values, s.Title, s.Date, s.Details, s.PerformerIDs.List(), s.TagIDs.List())
+	if err != nil {
+		return err
+	}
+
+	if c.empty() {
+		return nil
+	}
+
+	partial := models.NewScenePartial()
+	partial.Title = c.title
+	partial.Date = c.date
+	partial.Details = c.details
+	partial.PerformerIDs = addIDs(c.performerIDs)
+	partial.TagIDs = addIDs(c.tagIDs)
+
+	if _, err := rw.UpdatePartial(ctx, s.ID, partial); err != nil {
+		return fmt.Errorf("updating scene: %w", err)
+	}
+
+	logger.Infof("Populated scene '%s' from embedded metadata", s.DisplayName())
+	return nil
+}
+
+// ApplyImage populates the image from the embedded metadata of its primary
+// file.
+func (e *EmbeddedMetadata) ApplyImage(ctx context.Context, i *models.Image, rw ImageEmbeddedMetadataUpdater, fileGetter models.FileGetter) error {
+	if err := i.LoadPrimaryFile(ctx, fileGetter); err != nil {
+		return err
+	}
+
+	f := i.Files.Primary()
+	if f == nil {
+		return nil
+	}
+
+	values := e.Mappings.Values(f.Base().Metadata)
+	if values.IsEmpty() {
+		return nil
+	}
+
+	if err := i.LoadTagIDs(ctx, rw); err != nil {
+		return err
+	}
+	if err := i.LoadPerformerIDs(ctx, rw); err != nil {
+		return err
+	}
+
+	c, err := e.changes(ctx, values, i.Title, i.Date, i.Details, i.PerformerIDs.List(), i.TagIDs.List())
+	if err != nil {
+		return err
+	}
+	c.photographer = setIfEmpty(i.Photographer, values.Photographer)
+
+	if c.empty() {
+		return nil
+	}
+
+	partial := models.NewImagePartial()
+	partial.Title = c.title
+	partial.Date = c.date
+	partial.Details = c.details
+	partial.Photographer = c.photographer
+	partial.PerformerIDs = addIDs(c.performerIDs)
+	partial.TagIDs = addIDs(c.tagIDs)
+
+	if _, err := rw.UpdatePartial(ctx, i.ID, partial); err != nil {
+		return fmt.Errorf("updating image: %w", err)
+	}
+
+	logger.Infof("Populated image '%s' from embedded metadata", i.DisplayName())
+	return nil
+}
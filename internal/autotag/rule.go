@@ -0,0 +1,522 @@
+package autotag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+type SceneRuleReader interface {
+	models.TagIDLoader
+	models.PerformerIDLoader
+	models.GalleryIDLoader
+	models.SceneMovieLoader
+}
+
+type SceneRuleUpdater interface {
+	SceneRuleReader
+	models.SceneUpdater
+}
+
+type ImageRuleReader interface {
+	models.TagIDLoader
+	models.PerformerIDLoader
+	models.GalleryIDLoader
+}
+
+type ImageRuleUpdater interface {
+	ImageRuleReader
+	models.ImageUpdater
+}
+
+type GalleryRuleReader interface {
+	models.TagIDLoader
+	models.PerformerIDLoader
+}
+
+type GalleryRuleUpdater interface {
+	GalleryRuleReader
+	models.GalleryUpdater
+}
+
+// ValidateRule returns an error if the rule cannot be applied.
+func ValidateRule(r *models.AutoTagRule) error {
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("name must not be blank")
+	}
+
+	c := r.Conditions
+	if c.PathRegex != nil {
+		if _, err := regexp.Compile(*c.PathRegex); err != nil {
+			return fmt.Errorf("invalid path regex: %w", err)
+		}
+	}
+
+	if c.MinDuration != nil && c.MaxDuration != nil && *c.MinDuration > *c.MaxDuration {
+		return errors.New("minimum duration must not be greater than maximum duration")
+	}
+
+	if c.MinResolution != nil && c.MaxResolution != nil && c.MinResolution.GetMinResolution() > c.MaxResolution.GetMaxResolution() {
+		return errors.New("minimum resolution must not be greater than maximum resolution")
+	}
+
+	a := r.Actions
+	if len(a.AddTagIDs) == 0 && len(a.AddPerformerIDs) == 0 && a.StudioID == nil && a.Organized == nil && a.MovieID == nil && a.GalleryID == nil {
+		return errors.New("rule has no actions")
+	}
+
+	return nil
+}
+
+type compiledRule struct {
+	*models.AutoTagRule
+	pathRegex *regexp.Regexp
+}
+
+// RuleSet is a set of auto-tag rules, in the order that they are applied.
+//
+// The conditions of every rule are matched against the item as it was
+// before any rule was applied, so that rules cannot trigger each other. If
+// more than one matching rule sets the studio or organized flag of an item,
+// the last rule wins.
+type RuleSet struct {
+	rules []compiledRule
+}
+
+// NewRuleSet returns a RuleSet for the rules. The enabled flag of the rules
+// is not checked.
+func NewRuleSet(rules []*models.AutoTagRule) (*RuleSet, error) {
+	ret := &RuleSet{}
+	for _, r := range rules {
+		cr := compiledRule{AutoTagRule: r}
+		if r.Conditions.PathRegex != nil {
+			var err error
+			cr.pathRegex, err = regexp.Compile(*r.Conditions.PathRegex)
+			if err != nil {
+				return nil, fmt.Errorf("compiling path regex of rule %q: %w", r.Name, err)
+			}
+		}
+
+		ret.rules = append(ret.rules, cr)
+	}
+
+	return ret, nil
+}
+
+// Empty returns true if there are no rules to apply.
+func (s *RuleSet) Empty() bool {
+	return len(s.rules) == 0
+}
+
+// ruleSubject is the information about an item that the conditions of a
+// rule are matched against.
+type ruleSubject struct {
+	path string
+	// duration in seconds
+	duration *float64
+	// the smaller of the width and height
+	resolution *int
+	tagIDs     []int
+	studioID   *int
+}
+
+func (s *ruleSubject) setFile(f models.File) {
+	if vf, ok := f.(models.VisualFile); ok {
+		res := vf.GetWidth()
+		if h := vf.GetHeight(); h < res {
+			res = h
+		}
+		s.resolution = &res
+	}
+
+	if vf, ok := f.(*models.VideoFile); ok {
+		s.duration = &vf.Duration
+	}
+}
+
+func (r compiledRule) matches(s ruleSubject) bool {
+	c := r.Conditions
+
+	if r.pathRegex != nil && !r.pathRegex.MatchString(s.path) {
+		return false
+	}
+
+	if c.Folder != nil {
+		folder := filepath.Clean(*c.Folder)
+		if !strings.HasPrefix(s.path, folder+string(filepath.Separator)) {
+			return false
+		}
+	}
+
+	if c.MinDuration != nil || c.MaxDuration != nil {
+		if s.duration == nil {
+			return false
+		}
+		if c.MinDuration != nil && *s.duration < float64(*c.MinDuration) {
+			return false
+		}
+		if c.MaxDuration != nil && *s.duration > float64(*c.MaxDuration) {
+			return false
+		}
+	}
+
+	if c.MinResolution != nil || c.MaxResolution != nil {
+		if s.resolution == nil {
+			return false
+		}
+		if c.MinResolution != nil && *s.resolution < c.MinResolution.GetMinResolution() {
+			return false
+		}
+		if c.MaxResolution != nil && *s.resolution > c.MaxResolution.GetMaxResolution() {
+			return false
+		}
+	}
+
+	for _, id := range c.TagIDs {
+		if !sliceutil.Contains(s.tagIDs, id) {
+			return false
+		}
+	}
+
+	if len(sliceutil.Intersect(s.tagIDs, c.ExcludedTagIDs)) > 0 {
+		return false
+	}
+
+	if len(c.StudioIDs) > 0 && (s.studioID == nil || !sliceutil.Contains(c.StudioIDs, *s.studioID)) {
+		return false
+	}
+
+	return true
+}
+
+// ruleChanges accumulates the changes made to an item by the matching rules.
+type ruleChanges struct {
+	tagIDs       []int
+	performerIDs []int
+	galleryIDs   []int
+	movieIDs     []int
+	studioID     *int
+	organized    bool
+
+	addTagIDs       []int
+	addPerformerIDs []int
+	addGalleryIDs   []int
+	addMovieIDs     []int
+	setStudio       bool
+	setOrganized    bool
+
+	// rules that changed the item
+	rules []*models.AutoTagRule
+}
+
+func addMissing(existing []int, added []int, toAdd []int) ([]int, bool) {
+	changed := false
+	for _, id := range toAdd {
+		if !sliceutil.Contains(existing, id) && !sliceutil.Contains(added, id) {
+			added = append(added, id)
+			changed = true
+		}
+	}
+
+	return added, changed
+}
+
+// apply adds the actions of the rule to the changes, if they change the item.
+func (c *ruleChanges) apply(r *models.AutoTagRule, movies bool, galleries bool) {
+	a := r.Actions
+	changed := false
+
+	var ch bool
+	c.addTagIDs, ch = addMissing(c.tagIDs, c.addTagIDs, a.AddTagIDs)
+	changed = changed || ch
+
+	c.addPerformerIDs, ch = addMissing(c.performerIDs, c.addPerformerIDs, a.AddPerformerIDs)
+	changed = changed || ch
+
+	if galleries && a.GalleryID != nil {
+		c.addGalleryIDs, ch = addMissing(c.galleryIDs, c.addGalleryIDs, []int{*a.GalleryID})
+		changed = changed || ch
+	}
+
+	if movies && a.MovieID != nil {
+		c.addMovieIDs, ch = addMissing(c.movieIDs, c.addMovieIDs, []int{*a.MovieID})
+		changed = changed || ch
+	}
+
+	if a.StudioID != nil && (c.studioID == nil || *c.studioID != *a.StudioID) {
+		id := *a.StudioID
+		c.studioID = &id
+		c.setStudio = true
+		changed = true
+	}
+
+	if a.Organized != nil && c.organized != *a.Organized {
+		c.organized = *a.Organized
+		c.setOrganized = true
+		changed = true
+	}
+
+	if changed {
+		c.rules = append(c.rules, r)
+	}
+}
+
+func (s *RuleSet) changes(subject ruleSubject, c *ruleChanges, movies bool, galleries bool) {
+	for _, r := range s.rules {
+		if r.matches(subject) {
+			c.apply(r.AutoTagRule, movies, galleries)
+		}
+	}
+}
+
+func addIDs(ids []int) *models.UpdateIDs {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return &models.UpdateIDs{
+		IDs:  ids,
+		Mode: models.RelationshipUpdateModeAdd,
+	}
+}
+
+func (c *ruleChanges) logChanges(objectType string, name string) {
+	for _, r := range c.rules {
+		logger.Infof("Applied auto-tag rule %q to %s '%s'", r.Name, objectType, name)
+	}
+}
+
+func (s *RuleSet) sceneChanges(ctx context.Context, o *models.Scene, r SceneRuleReader, fileGetter models.FileGetter) (*ruleChanges, error) {
+	if err := o.LoadPrimaryFile(ctx, fileGetter); err != nil {
+		return nil, err
+	}
+	if err := o.LoadTagIDs(ctx, r); err != nil {
+		return nil, err
+	}
+	if err := o.LoadPerformerIDs(ctx, r); err != nil {
+		return nil, err
+	}
+	if err := o.LoadGalleryIDs(ctx, r); err != nil {
+		return nil, err
+	}
+	if err := o.LoadMovies(ctx, r); err != nil {
+		return nil, err
+	}
+
+	subject := ruleSubject{
+		path:     o.Path,
+		tagIDs:   o.TagIDs.List(),
+		studioID: o.StudioID,
+	}
+	if f := o.Files.Primary(); f != nil {
+		subject.setFile(f)
+	}
+
+	c := &ruleChanges{
+		tagIDs:       o.TagIDs.List(),
+		performerIDs: o.PerformerIDs.List(),
+		galleryIDs:   o.GalleryIDs.List(),
+		studioID:     o.StudioID,
+		organized:    o.Organized,
+	}
+	for _, m := range o.Movies.List() {
+		c.movieIDs = append(c.movieIDs, m.MovieID)
+	}
+
+	s.changes(subject, c, true, true)
+	return c, nil
+}
+
+// PreviewScene returns the rules that would change the scene.
+func (s *RuleSet) PreviewScene(ctx context.Context, o *models.Scene, r SceneRuleReader, fileGetter models.FileGetter) ([]*models.AutoTagRule, error) {
+	c, err := s.sceneChanges(ctx, o, r, fileGetter)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.rules, nil
+}
+
+// ApplyScene applies the matching rules to the scene.
+func (s *RuleSet) ApplyScene(ctx context.Context, o *models.Scene, rw SceneRuleUpdater, fileGetter models.FileGetter) error {
+	c, err := s.sceneChanges(ctx, o, rw, fileGetter)
+	if err != nil {
+		return err
+	}
+
+	if len(c.rules) == 0 {
+		return nil
+	}
+
+	partial := models.NewScenePartial()
+	partial.TagIDs = addIDs(c.addTagIDs)
+	partial.PerformerIDs = addIDs(c.addPerformerIDs)
+	partial.GalleryIDs = addIDs(c.addGalleryIDs)
+	if len(c.addMovieIDs) > 0 {
+		partial.MovieIDs = &models.UpdateMovieIDs{
+			Mode: models.RelationshipUpdateModeAdd,
+		}
+		for _, id := range c.addMovieIDs {
+			partial.MovieIDs.Movies = append(partial.MovieIDs.Movies, models.MoviesScenes{MovieID: id})
+		}
+	}
+	if c.setStudio {
+		partial.StudioID = models.NewOptionalInt(*c.studioID)
+	}
+	if c.setOrganized {
+		partial.Organized = models.NewOptionalBool(c.organized)
+	}
+
+	if _, err := rw.UpdatePartial(ctx, o.ID, partial); err != nil {
+		return fmt.Errorf("updating scene: %w", err)
+	}
+
+	c.logChanges("scene", o.DisplayName())
+	return nil
+}
+
+func (s *RuleSet) imageChanges(ctx context.Context, o *models.Image, r ImageRuleReader, fileGetter models.FileGetter) (*ruleChanges, error) {
+	if err := o.LoadPrimaryFile(ctx, fileGetter); err != nil {
+		return nil, err
+	}
+	if err := o.LoadTagIDs(ctx, r); err != nil {
+		return nil, err
+	}
+	if err := o.LoadPerformerIDs(ctx, r); err != nil {
+		return nil, err
+	}
+	if err := o.LoadGalleryIDs(ctx, r); err != nil {
+		return nil, err
+	}
+
+	subject := ruleSubject{
+		path:     o.Path,
+		tagIDs:   o.TagIDs.List(),
+		studioID: o.StudioID,
+	}
+	if f := o.Files.Primary(); f != nil {
+		subject.setFile(f)
+	}
+
+	c := &ruleChanges{
+		tagIDs:       o.TagIDs.List(),
+		performerIDs: o.PerformerIDs.List(),
+		galleryIDs:   o.GalleryIDs.List(),
+		studioID:     o.StudioID,
+		organized:    o.Organized,
+	}
+
+	s.changes(subject, c, false, true)
+	return c, nil
+}
+
+// PreviewImage returns the rules that would change the image.
+func (s *RuleSet) PreviewImage(ctx context.Context, o *models.Image, r ImageRuleReader, fileGetter models.FileGetter) ([]*models.AutoTagRule, error) {
+	c, err := s.imageChanges(ctx, o, r, fileGetter)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.rules, nil
+}
+
+// ApplyImage applies the matching rules to the image.
+func (s *RuleSet) ApplyImage(ctx context.Context, o *models.Image, rw ImageRuleUpdater, fileGetter models.FileGetter) error {
+	c, err := s.imageChanges(ctx, o, rw, fileGetter)
+	if err != nil {
+		return err
+	}
+
+	if len(c.rules) == 0 {
+		return nil
+	}
+
+	partial := models.NewImagePartial()
+	partial.TagIDs = addIDs(c.addTagIDs)
+	partial.PerformerIDs = addIDs(c.addPerformerIDs)
+	partial.GalleryIDs = addIDs(c.addGalleryIDs)
+	if c.setStudio {
+		partial.StudioID = models.NewOptionalInt(*c.studioID)
+	}
+	if c.setOrganized {
+		partial.Organized = models.NewOptionalBool(c.organized)
+	}
+
+	if _, err := rw.UpdatePartial(ctx, o.ID, partial); err != nil {
+		return fmt.Errorf("updating image: %w", err)
+	}
+
+	c.logChanges("image", o.DisplayName())
+	return nil
+}
+
+func (s *RuleSet) galleryChanges(ctx context.Context, o *models.Gallery, r GalleryRuleReader) (*ruleChanges, error) {
+	if err := o.LoadTagIDs(ctx, r); err != nil {
+		return nil, err
+	}
+	if err := o.LoadPerformerIDs(ctx, r); err != nil {
+		return nil, err
+	}
+
+	subject := ruleSubject{
+		path:     o.Path,
+		tagIDs:   o.TagIDs.List(),
+		studioID: o.StudioID,
+	}
+
+	c := &ruleChanges{
+		tagIDs:       o.TagIDs.List(),
+		performerIDs: o.PerformerIDs.List(),
+		studioID:     o.StudioID,
+		organized:    o.Organized,
+	}
+
+	s.changes(subject, c, false, false)
+	return c, nil
+}
+
+// PreviewGallery returns the rules that would change the gallery.
+func (s *RuleSet) PreviewGallery(ctx context.Context, o *models.Gallery, r GalleryRuleReader) ([]*models.AutoTagRule, error) {
+	c, err := s.galleryChanges(ctx, o, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.rules, nil
+}
+
+// ApplyGallery applies the matching rules to the gallery.
+func (s *RuleSet) ApplyGallery(ctx context.Context, o *models.Gallery, rw GalleryRuleUpdater) error {
+	c, err := s.galleryChanges(ctx, o, rw)
+	if err != nil {
+		return err
+	}
+
+	if len(c.rules) == 0 {
+		return nil
+	}
+
+	partial := models.NewGalleryPartial()
+	partial.TagIDs = addIDs(c.addTagIDs)
+	partial.PerformerIDs = addIDs(c.addPerformerIDs)
+	if c.setStudio {
+		partial.StudioID = models.NewOptionalInt(*c.studioID)
+	}
+	if c.setOrganized {
+		partial.Organized = models.NewOptionalBool(c.organized)
+	}
+
+	if _, err := rw.UpdatePartial(ctx, o.ID, partial); err != nil {
+		return fmt.Errorf("updating gallery: %w", err)
+	}
+
+	c.logChanges("gallery", o.DisplayName())
+	return nil
+}
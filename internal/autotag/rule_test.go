@@ -0,0 +1,264 @@
+package autotag
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestValidateRule(t *testing.T) {
+	t.Parallel()
+
+	tagID := 1
+	badRegex := "["
+	minDuration := 60
+	maxDuration := 30
+	minResolution := models.ResolutionEnumFullHd
+	maxResolution := models.ResolutionEnumStandard
+
+	validActions := models.AutoTagRuleActions{AddTagIDs: []int{tagID}}
+
+	tests := []struct {
+		name    string
+		rule    models.AutoTagRule
+		wantErr bool
+	}{
+		{
+			"valid",
+			models.AutoTagRule{Name: "rule", Actions: validActions},
+			false,
+		},
+		{
+			"blank name",
+			models.AutoTagRule{Name: " ", Actions: validActions},
+			true,
+		},
+		{
+			"invalid regex",
+			models.AutoTagRule{
+				Name:       "rule",
+				Conditions: models.AutoTagRuleConditions{PathRegex: &badRegex},
+				Actions:    validActions,
+			},
+			true,
+		},
+		{
+			"invalid duration",
+			models.AutoTagRule{
+				Name: "rule",
+				Conditions: models.AutoTagRuleConditions{
+					MinDuration: &minDuration,
+					MaxDuration: &maxDuration,
+				},
+				Actions: validActions,
+			},
+			true,
+		},
+		{
+			"invalid resolution",
+			models.AutoTagRule{
+				Name: "rule",
+				Conditions: models.AutoTagRuleConditions{
+					MinResolution: &minResolution,
+					MaxResolution: &maxResolution,
+				},
+				Actions: validActions,
+			},
+			true,
+		},
+		{
+			"no actions",
+			models.AutoTagRule{Name: "rule"},
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateRule(&tt.rule); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRuleSetApplyScene(t *testing.T) {
+	t.Parallel()
+
+	const (
+		sceneID       = 1
+		existingTagID = 2
+		addedTagID    = 3
+		excludedTagID = 4
+		studioID      = 5
+	)
+
+	folder := filepath.Join("videos", "long")
+	minDuration := 60
+	minResolution := models.ResolutionEnumFullHd
+	organized := true
+	studio := studioID
+
+	rules := []*models.AutoTagRule{
+		{
+			ID:   1,
+			Name: "long videos",
+			Conditions: models.AutoTagRuleConditions{
+				Folder:      &folder,
+				MinDuration: &minDuration,
+			},
+			Actions: models.AutoTagRuleActions{
+				AddTagIDs: []int{existingTagID, addedTagID},
+				StudioID:  &studio,
+			},
+		},
+		{
+			// does not match - scene resolution is too low
+			ID:   2,
+			Name: "full hd",
+			Conditions: models.AutoTagRuleConditions{
+				MinResolution: &minResolution,
+			},
+			Actions: models.AutoTagRuleActions{
+				AddTagIDs: []int{excludedTagID},
+			},
+		},
+		{
+			// does not match - conditions are matched against the original tags
+			ID:   3,
+			Name: "tagged",
+			Conditions: models.AutoTagRuleConditions{
+				TagIDs: []int{addedTagID},
+			},
+			Actions: models.AutoTagRuleActions{
+				Organized: &organized,
+			},
+		},
+		{
+			// matches but does not change the scene
+			ID:   4,
+			Name: "existing",
+			Actions: models.AutoTagRuleActions{
+				AddTagIDs: []int{existingTagID},
+			},
+		},
+	}
+
+	ruleSet, err := NewRuleSet(rules)
+	if err != nil {
+		t.Fatalf("NewRuleSet() error = %v", err)
+	}
+
+	path := filepath.Join(folder, "scene.mp4")
+	newScene := func() *models.Scene {
+		return &models.Scene{
+			ID:   sceneID,
+			Path: path,
+			Files: models.NewRelatedVideoFiles([]*models.VideoFile{
+				{
+					BaseFile: &models.BaseFile{Path: path},
+					Width:    1280,
+					Height:   720,
+					Duration: 120,
+				},
+			}),
+			TagIDs:       models.NewRelatedIDs([]int{existingTagID}),
+			PerformerIDs: models.NewRelatedIDs([]int{}),
+			GalleryIDs:   models.NewRelatedIDs([]int{}),
+			Movies:       models.NewRelatedMovies([]models.MoviesScenes{}),
+		}
+	}
+
+	db := mocks.NewDatabase()
+
+	changed, err := ruleSet.PreviewScene(testCtx, newScene(), db.Scene, db.File)
+	if err != nil {
+		t.Errorf("RuleSet.PreviewScene() error = %v", err)
+	}
+	if assert.Len(t, changed, 1) {
+		assert.Equal(t, rules[0], changed[0])
+	}
+
+	matchPartial := mock.MatchedBy(func(got models.ScenePartial) bool {
+		expected := models.ScenePartial{
+			TagIDs: &models.UpdateIDs{
+				IDs:  []int{addedTagID},
+				Mode: models.RelationshipUpdateModeAdd,
+			},
+			StudioID: models.NewOptionalInt(studioID),
+		}
+
+		return scenePartialsEqual(got, expected)
+	})
+	db.Scene.On("UpdatePartial", testCtx, sceneID, matchPartial).Return(nil, nil).Once()
+
+	if err := ruleSet.ApplyScene(testCtx, newScene(), db.Scene, db.File); err != nil {
+		t.Errorf("RuleSet.ApplyScene() error = %v", err)
+	}
+
+	db.AssertExpectations(t)
+}
+
+func TestRuleSetPreviewGallery(t *testing.T) {
+	t.Parallel()
+
+	const excludedTagID = 1
+
+	pathRegex := `(?i)holiday`
+	organized := true
+
+	rules := []*models.AutoTagRule{
+		{
+			ID:   1,
+			Name: "holidays",
+			Conditions: models.AutoTagRuleConditions{
+				PathRegex:      &pathRegex,
+				ExcludedTagIDs: []int{excludedTagID},
+			},
+			Actions: models.AutoTagRuleActions{
+				Organized: &organized,
+			},
+		},
+	}
+
+	ruleSet, err := NewRuleSet(rules)
+	if err != nil {
+		t.Fatalf("NewRuleSet() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		tagIDs    []int
+		organized bool
+		want      int
+	}{
+		{"matching", filepath.Join("photos", "Holiday 2020"), []int{}, false, 1},
+		{"not matching path", filepath.Join("photos", "work"), []int{}, false, 0},
+		{"excluded tag", filepath.Join("photos", "holiday"), []int{excludedTagID}, false, 0},
+		{"already organized", filepath.Join("photos", "holiday"), []int{}, true, 0},
+	}
+
+	db := mocks.NewDatabase()
+
+	for _, tt := range tests {
+		g := &models.Gallery{
+			Path:         tt.path,
+			Organized:    tt.organized,
+			TagIDs:       models.NewRelatedIDs(tt.tagIDs),
+			PerformerIDs: models.NewRelatedIDs([]int{}),
+		}
+
+		got, err := ruleSet.PreviewGallery(testCtx, g, db.Gallery)
+		if err != nil {
+			t.Errorf("%s: RuleSet.PreviewGallery() error = %v", tt.name, err)
+			continue
+		}
+
+		assert.Len(t, got, tt.want, tt.name)
+	}
+}
@@ -325,6 +325,15 @@ func (me *contentDirectoryService) handleBrowseDirectChildren(obj object, host s
 		objs = me.getMovieScenes(childPath(paths), host)
 	}
 
+	// Playlists
+	if obj.Path == "playlists" {
+		objs = me.getPlaylists()
+	}
+
+	if strings.HasPrefix(obj.Path, "playlists/") {
+		objs = me.getPlaylistScenes(childPath(paths), host)
+	}
+
 	// Rating
 	if obj.Path == "rating" {
 		objs = me.getRating()
@@ -434,11 +443,21 @@ func getRootObjects() []interface{} {
 	objs = append(objs, makeStorageFolder("tags", "tags", rootID))
 	objs = append(objs, makeStorageFolder("studios", "studios", rootID))
 	objs = append(objs, makeStorageFolder("movies", "movies", rootID))
+	objs = append(objs, makeStorageFolder("playlists", "playlists", rootID))
 	objs = append(objs, makeStorageFolder("rating", "rating", rootID))
 
 	return objs
 }
 
+// filterQuery returns the configured filter query, or nil if not set.
+func (me *contentDirectoryService) filterQuery() *string {
+	if me.FilterQuery == "" {
+		return nil
+	}
+
+	return &me.FilterQuery
+}
+
 func getSortDirection(sceneFilter *models.SceneFilterType, sort string) models.SortDirectionEnum {
 	direction := models.SortDirectionEnumDesc
 	if sort == "title" {
@@ -456,9 +475,10 @@ func (me *contentDirectoryService) getVideos(sceneFilter *models.SceneFilterType
 		sort := me.VideoSortOrder
 		direction := getSortDirection(sceneFilter, sort)
 		findFilter := &models.FindFilterType{
-			PerPage:   &pageSize,
-			Sort:      &sort,
-			Direction: &direction,
+			FilterQuery: me.filterQuery(),
+			PerPage:     &pageSize,
+			Sort:        &sort,
+			Direction:   &direction,
 		}
 
 		scenes, total, err := scene.QueryWithCount(ctx, r.SceneFinder, sceneFilter, findFilter)
@@ -469,6 +489,7 @@ func (me *contentDirectoryService) getVideos(sceneFilter *models.SceneFilterType
 		if total > pageSize {
 			pager := scenePager{
 				sceneFilter: sceneFilter,
+				filterQuery: me.filterQuery(),
 				parentID:    parentID,
 			}
 
@@ -501,6 +522,7 @@ func (me *contentDirectoryService) getPageVideos(sceneFilter *models.SceneFilter
 	if err := r.WithReadTxn(context.TODO(), func(ctx context.Context) error {
 		pager := scenePager{
 			sceneFilter: sceneFilter,
+			filterQuery: me.filterQuery(),
 			parentID:    parentID,
 		}
 
@@ -698,6 +720,113 @@ func (me *contentDirectoryService) getMovieScenes(paths []string, host string) [
 	return me.getVideos(sceneFilter, parentID, host)
 }
 
+func (me *contentDirectoryService) getPlaylists() []interface{} {
+	var objs []interface{}
+
+	r := me.repository
+	if err := r.WithReadTxn(context.TODO(), func(ctx context.Context) error {
+		playlists, err := r.PlaylistFinder.All(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range playlists {
+			objs = append(objs, makeStorageFolder("playlists/"+strconv.Itoa(s.ID), s.Name, "playlists"))
+		}
+
+		return nil
+	}); err != nil {
+		logger.Errorf(err.Error())
+	}
+
+	return objs
+}
+
+// getPlaylistScenes returns the scenes of the playlist in order, excluding
+// scenes that do not match the filter query. Scene marker and image items
+// cannot be served and are omitted.
+func (me *contentDirectoryService) getPlaylistScenes(paths []string, host string) []interface{} {
+	var objs []interface{}
+
+	playlistID, err := strconv.Atoi(paths[0])
+	if err != nil {
+		return nil
+	}
+
+	parentID := "playlists/" + strings.Join(paths, "/")
+
+	r := me.repository
+	if err := r.WithReadTxn(context.TODO(), func(ctx context.Context) error {
+		items, err := r.PlaylistFinder.GetItems(ctx, playlistID)
+		if err != nil {
+			return err
+		}
+
+		matching, err := me.filterQuerySceneIDs(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, i := range items {
+			if i.SceneID == nil {
+				continue
+			}
+
+			if matching != nil && !matching[*i.SceneID] {
+				continue
+			}
+
+			s, err := r.SceneFinder.Find(ctx, *i.SceneID)
+			if err != nil {
+				return err
+			}
+
+			if s == nil {
+				continue
+			}
+
+			if err := s.LoadPrimaryFile(ctx, r.FileGetter); err != nil {
+				return err
+			}
+
+			objs = append(objs, sceneToContainer(s, parentID, host))
+		}
+
+		return nil
+	}); err != nil {
+		logger.Errorf(err.Error())
+	}
+
+	return objs
+}
+
+// filterQuerySceneIDs returns the ids of the scenes matching the configured
+// filter query, or nil if the filter query is not set.
+func (me *contentDirectoryService) filterQuerySceneIDs(ctx context.Context) (map[int]bool, error) {
+	filterQuery := me.filterQuery()
+	if filterQuery == nil {
+		return nil, nil
+	}
+
+	perPage := -1
+	findFilter := &models.FindFilterType{
+		FilterQuery: filterQuery,
+		PerPage:     &perPage,
+	}
+
+	result, err := me.repository.SceneFinder.Query(ctx, scene.QueryOptions(&models.SceneFilterType{}, findFilter, false))
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[int]bool)
+	for _, id := range result.IDs {
+		ret[id] = true
+	}
+
+	return ret, nil
+}
+
 func (me *contentDirectoryService) getRating() []interface{} {
 	var objs []interface{}
 
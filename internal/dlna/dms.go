@@ -71,6 +71,11 @@ type MovieFinder interface {
 	All(ctx context.Context) ([]*models.Movie, error)
 }
 
+type PlaylistFinder interface {
+	All(ctx context.Context) ([]*models.Playlist, error)
+	GetItems(ctx context.Context, playlistID int) ([]models.PlaylistItem, error)
+}
+
 const (
 	serverField                 = "Linux/3.4 DLNADOC/1.50 UPnP/1.0 DMS/1.0"
 	rootDeviceType              = "urn:schemas-upnp-org:device:MediaServer:1"
@@ -274,6 +279,8 @@ type Server struct {
 	sceneServer        sceneServer
 	ipWhitelistManager *ipWhitelistManager
 	VideoSortOrder     string
+	// FilterQuery restricts the scenes that are served
+	FilterQuery string
 }
 
 // UPnP SOAP service.
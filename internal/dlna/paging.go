@@ -12,6 +12,7 @@ import (
 
 type scenePager struct {
 	sceneFilter *models.SceneFilterType
+	filterQuery *string
 	parentID    string
 }
 
@@ -28,8 +29,9 @@ func (p *scenePager) getPages(ctx context.Context, r models.SceneQueryer, total
 	singlePageSize := 1
 	sort := "title"
 	findFilter := &models.FindFilterType{
-		PerPage: &singlePageSize,
-		Sort:    &sort,
+		FilterQuery: p.filterQuery,
+		PerPage:     &singlePageSize,
+		Sort:        &sort,
 	}
 
 	for page := 1; page <= pages; page++ {
@@ -63,10 +65,11 @@ func (p *scenePager) getPageVideos(ctx context.Context, r SceneFinder, f models.
 	var objs []interface{}
 
 	findFilter := &models.FindFilterType{
-		PerPage:   &pageSize,
-		Page:      &page,
-		Sort:      &sort,
-		Direction: &direction,
+		FilterQuery: p.filterQuery,
+		PerPage:     &pageSize,
+		Page:        &page,
+		Sort:        &sort,
+		Direction:   &direction,
 	}
 
 	scenes, err := scene.Query(ctx, r, p.sceneFilter, findFilter)
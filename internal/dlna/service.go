@@ -23,6 +23,7 @@ type Repository struct {
 	TagFinder       TagFinder
 	PerformerFinder PerformerFinder
 	MovieFinder     MovieFinder
+	PlaylistFinder  PlaylistFinder
 }
 
 func NewRepository(repo models.Repository) Repository {
@@ -34,6 +35,7 @@ func NewRepository(repo models.Repository) Repository {
 		TagFinder:       repo.Tag,
 		PerformerFinder: repo.Performer,
 		MovieFinder:     repo.Movie,
+		PlaylistFinder:  repo.Playlist,
 	}
 }
 
@@ -64,6 +66,7 @@ type dmsConfig struct {
 	StallEventSubscribe bool
 	NotifyInterval      time.Duration
 	VideoSortOrder      string
+	FilterQuery         string
 }
 
 type sceneServer interface {
@@ -76,6 +79,7 @@ type Config interface {
 	GetDLNAServerName() string
 	GetDLNADefaultIPWhitelist() []string
 	GetVideoSortOrder() string
+	GetDLNAFilterQuery() string
 	GetDLNAPortAsString() string
 }
 
@@ -144,6 +148,7 @@ func (s *Service) init() error {
 		LogHeaders:     false,
 		NotifyInterval: 30 * time.Second,
 		VideoSortOrder: s.config.GetVideoSortOrder(),
+		FilterQuery:    s.config.GetDLNAFilterQuery(),
 	}
 
 	interfaces, err := s.getInterfaces()
@@ -185,6 +190,7 @@ func (s *Service) init() error {
 		StallEventSubscribe: dmsConfig.StallEventSubscribe,
 		NotifyInterval:      dmsConfig.NotifyInterval,
 		VideoSortOrder:      dmsConfig.VideoSortOrder,
+		FilterQuery:         dmsConfig.FilterQuery,
 	}
 
 	return nil
@@ -0,0 +1,281 @@
+package identify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/gallery"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/plugin/hook"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stashapp/stash/pkg/txn"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// GalleryScraper is implemented by sources that are able to scrape galleries.
+// Sources that do not implement this interface are skipped when identifying
+// galleries.
+type GalleryScraper interface {
+	ScrapeGalleries(ctx context.Context, galleryID int) ([]*scraper.ScrapedGallery, error)
+}
+
+type GalleryReaderUpdater interface {
+	models.GalleryUpdater
+	models.PerformerIDLoader
+	models.TagIDLoader
+	models.URLLoader
+}
+
+type GalleryIdentifier struct {
+	TxnManager           txn.Manager
+	GalleryReaderUpdater GalleryReaderUpdater
+	StudioReaderWriter   models.StudioReaderWriter
+	PerformerCreator     PerformerCreator
+	TagFinderCreator     models.TagFinderCreator
+
+	DefaultOptions   *MetadataOptions
+	Sources          []ScraperSource
+	PostHookExecutor PostHookExecutor
+}
+
+type galleryScrapeResult struct {
+	result *scraper.ScrapedGallery
+	source ScraperSource
+}
+
+func (t *GalleryIdentifier) Identify(ctx context.Context, g *models.Gallery) error {
+	result, err := t.scrapeGallery(ctx, g)
+	var multipleMatchErr *MultipleMatchesFoundError
+	if err != nil {
+		if !errors.As(err, &multipleMatchErr) {
+			return err
+		}
+	}
+
+	if result == nil {
+		if multipleMatchErr != nil {
+			logger.Debugf("Identify skipped because multiple results returned for %s", g.DisplayName())
+
+			// find if the gallery should be tagged for multiple results
+			tagID, err := skipMultipleMatchTag(t.DefaultOptions, multipleMatchErr)
+			if err != nil {
+				return err
+			}
+			if tagID != nil {
+				return t.addTagToGallery(ctx, g, *tagID)
+			}
+		} else {
+			logger.Debugf("Unable to identify %s", g.DisplayName())
+		}
+		return nil
+	}
+
+	// results were found, modify the gallery
+	if err := t.modifyGallery(ctx, g, result); err != nil {
+		return fmt.Errorf("error modifying gallery: %v", err)
+	}
+
+	return nil
+}
+
+func (t *GalleryIdentifier) scrapeGallery(ctx context.Context, g *models.Gallery) (*galleryScrapeResult, error) {
+	result, source, err := scrapeFirst(t.Sources, t.DefaultOptions, func(source ScraperSource) ([]*scraper.ScrapedGallery, bool, error) {
+		s, ok := source.Scraper.(GalleryScraper)
+		if !ok {
+			// source does not support galleries
+			return nil, false, nil
+		}
+
+		results, err := s.ScrapeGalleries(ctx, g.ID)
+		return results, true, err
+	})
+	if err != nil || source == nil {
+		return nil, err
+	}
+
+	return &galleryScrapeResult{
+		result: result,
+		source: *source,
+	}, nil
+}
+
+func (t *GalleryIdentifier) getGalleryPartial(ctx context.Context, g *models.Gallery, result *galleryScrapeResult) (*models.GalleryPartial, error) {
+	options, fieldOptions := sourceOptions(t.DefaultOptions, result.source)
+	scraped := result.result
+
+	partial := getGalleryPartial(g, scraped, fieldOptions, utils.IsTrue(options.SetOrganized))
+
+	r := relationships{
+		studioReaderWriter: t.StudioReaderWriter,
+		performerCreator:   t.PerformerCreator,
+		tagCreator:         t.TagFinderCreator,
+		endpoint:           result.source.RemoteSite,
+		options:            options,
+		fieldOptions:       fieldOptions,
+	}
+
+	studioID, err := r.studio(ctx, scraped.Studio, g.StudioID)
+	if err != nil {
+		return nil, err
+	}
+	if studioID != nil {
+		partial.StudioID = models.NewOptionalInt(*studioID)
+	}
+
+	performerIDs, skippedSingleName, err := r.performers(ctx, scraped.Performers, g.PerformerIDs.List())
+	if err != nil {
+		return nil, err
+	}
+	if performerIDs != nil {
+		partial.PerformerIDs = &models.UpdateIDs{
+			IDs:  performerIDs,
+			Mode: models.RelationshipUpdateModeSet,
+		}
+	}
+
+	tagIDs, err := r.tags(ctx, scraped.Tags, g.TagIDs.List(), skippedSingleName)
+	if err != nil {
+		return nil, err
+	}
+	if tagIDs != nil {
+		partial.TagIDs = &models.UpdateIDs{
+			IDs:  tagIDs,
+			Mode: models.RelationshipUpdateModeSet,
+		}
+	}
+
+	return &partial, nil
+}
+
+func (t *GalleryIdentifier) modifyGallery(ctx context.Context, g *models.Gallery, result *galleryScrapeResult) error {
+	ctx = result.source.changeOrigin(ctx)
+
+	updated := false
+	if err := txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
+		// load gallery relationships
+		if err := g.LoadURLs(ctx, t.GalleryReaderUpdater); err != nil {
+			return err
+		}
+		if err := g.LoadPerformerIDs(ctx, t.GalleryReaderUpdater); err != nil {
+			return err
+		}
+		if err := g.LoadTagIDs(ctx, t.GalleryReaderUpdater); err != nil {
+			return err
+		}
+
+		partial, err := t.getGalleryPartial(ctx, g, result)
+		if err != nil {
+			return err
+		}
+
+		// don't update anything if nothing was set
+		if galleryPartialIsEmpty(*partial) {
+			logger.Debugf("Nothing to set for %s", g.DisplayName())
+			return nil
+		}
+
+		partial.UpdatedAt = models.NewOptionalTime(time.Now())
+		if _, err := t.GalleryReaderUpdater.UpdatePartial(ctx, g.ID, *partial); err != nil {
+			return fmt.Errorf("error updating gallery: %w", err)
+		}
+		updated = true
+
+		as := ""
+		if partial.Title.Ptr() != nil {
+			as = fmt.Sprintf(" as %s", partial.Title.Value)
+		}
+		logger.Infof("Successfully identified %s%s using %s", g.DisplayName(), as, result.source.Name)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// fire post-update hooks
+	if updated && t.PostHookExecutor != nil {
+		t.PostHookExecutor.ExecutePostHooks(ctx, g.ID, hook.GalleryUpdatePost, nil, nil)
+	}
+
+	return nil
+}
+
+func (t *GalleryIdentifier) addTagToGallery(ctx context.Context, g *models.Gallery, tagID int) error {
+	return txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
+		if err := g.LoadTagIDs(ctx, t.GalleryReaderUpdater); err != nil {
+			return err
+		}
+
+		if sliceutil.Contains(g.TagIDs.List(), tagID) {
+			// skip if the gallery was already tagged
+			return nil
+		}
+
+		if err := gallery.AddTag(ctx, t.GalleryReaderUpdater, g, tagID); err != nil {
+			return err
+		}
+
+		ret, err := t.TagFinderCreator.Find(ctx, tagID)
+		if err != nil {
+			logger.Infof("Added tag id %d to skipped gallery %s", tagID, g.DisplayName())
+		} else {
+			logger.Infof("Added tag %s to skipped gallery %s", ret.Name, g.DisplayName())
+		}
+
+		return nil
+	})
+}
+
+func galleryPartialIsEmpty(p models.GalleryPartial) bool {
+	return !p.Title.Set && !p.Code.Set && !p.Date.Set && !p.Details.Set &&
+		!p.Photographer.Set && !p.Organized.Set && !p.StudioID.Set &&
+		p.URLs == nil && p.PerformerIDs == nil && p.TagIDs == nil
+}
+
+func getGalleryPartial(g *models.Gallery, scraped *scraper.ScrapedGallery, fieldOptions map[string]*FieldOptions, setOrganized bool) models.GalleryPartial {
+	partial := models.GalleryPartial{}
+
+	if scraped.Title != nil && (g.Title != *scraped.Title) {
+		if shouldSetSingleValueField(fieldOptions["title"], g.Title != "") {
+			partial.Title = models.NewOptionalString(*scraped.Title)
+		}
+	}
+	if scraped.Date != nil && (g.Date == nil || g.Date.String() != *scraped.Date) {
+		if shouldSetSingleValueField(fieldOptions["date"], g.Date != nil) {
+			d, err := models.ParseDate(*scraped.Date)
+			if err == nil {
+				partial.Date = models.NewOptionalDate(d)
+			}
+		}
+	}
+	if scraped.Details != nil && (g.Details != *scraped.Details) {
+		if shouldSetSingleValueField(fieldOptions["details"], g.Details != "") {
+			partial.Details = models.NewOptionalString(*scraped.Details)
+		}
+	}
+	if scraped.Photographer != nil && (g.Photographer != *scraped.Photographer) {
+		if shouldSetSingleValueField(fieldOptions["photographer"], g.Photographer != "") {
+			partial.Photographer = models.NewOptionalString(*scraped.Photographer)
+		}
+	}
+	if scraped.Code != nil && (g.Code != *scraped.Code) {
+		if shouldSetSingleValueField(fieldOptions["code"], g.Code != "") {
+			partial.Code = models.NewOptionalString(*scraped.Code)
+		}
+	}
+	if urls := stringsToSet(fieldOptions, "url", g.URLs.List(), scraped.URLs); urls != nil {
+		partial.URLs = &models.UpdateStrings{
+			Values: urls,
+			Mode:   models.RelationshipUpdateModeSet,
+		}
+	}
+
+	if setOrganized && !g.Organized {
+		partial.Organized = models.NewOptionalBool(true)
+	}
+
+	return partial
+}
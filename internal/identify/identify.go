@@ -36,12 +36,24 @@ type SceneUpdatePostHookExecutor interface {
 }
 
 type ScraperSource struct {
+	// ID identifies the source in field priorities. It is the scraper ID
+	// for scrapers, and the endpoint for stash-box instances.
+	ID         string
 	Name       string
 	Options    *MetadataOptions
 	Scraper    SceneScraper
 	RemoteSite string
 }
 
+// changeOrigin returns a context that attributes changes to the source.
+func (s ScraperSource) changeOrigin(ctx context.Context) context.Context {
+	id := s.ID
+	return models.WithChangeOrigin(ctx, models.ChangeOrigin{
+		Type: models.ChangeOriginTypeIdentify,
+		ID:   &id,
+	})
+}
+
 type SceneIdentifier struct {
 	TxnManager         txn.Manager
 	SceneReaderUpdater SceneReaderUpdater
@@ -52,6 +64,16 @@ type SceneIdentifier struct {
 	DefaultOptions              *MetadataOptions
 	Sources                     []ScraperSource
 	SceneUpdatePostHookExecutor SceneUpdatePostHookExecutor
+
+	// If true, results from all sources are merged instead of using the
+	// first source that finds a match.
+	MergeResults    bool
+	FieldPriorities []*FieldPriority
+
+	// If true, matches are stored as proposals for review instead of being
+	// applied to the scene.
+	DryRun          bool
+	ProposalCreator ProposalCreator
 }
 
 func (t *SceneIdentifier) Identify(ctx context.Context, scene *models.Scene) error {
@@ -69,7 +91,7 @@ func (t *SceneIdentifier) Identify(ctx context.Context, scene *models.Scene) err
 
 			// find if the scene should be tagged for multiple results
 			options := t.getOptions(multipleMatchErr.Source)
-			if options.SkipMultipleMatchTag != nil && len(*options.SkipMultipleMatchTag) > 0 {
+			if !t.DryRun && options.SkipMultipleMatchTag != nil && len(*options.SkipMultipleMatchTag) > 0 {
 				// Tag it with the multiple results tag
 				err := t.addTagToScene(ctx, scene, *options.SkipMultipleMatchTag)
 				if err != nil {
@@ -83,20 +105,89 @@ func (t *SceneIdentifier) Identify(ctx context.Context, scene *models.Scene) err
 		return nil
 	}
 
+	logger.Infof("Match for %s from %s scored %.2f", scene.Path, result.source.Name, result.confidence)
+
+	if t.DryRun {
+		// store the match for review instead of modifying the scene
+		if err := t.proposeScene(ctx, scene, result); err != nil {
+			return fmt.Errorf("error storing proposal: %v", err)
+		}
+		return nil
+	}
+
 	// results were found, modify the scene
 	if err := t.modifyScene(ctx, scene, result); err != nil {
 		return fmt.Errorf("error modifying scene: %v", err)
 	}
 
+	// tag low scoring matches for review
+	options := t.getOptions(result.source)
+	if options.LowScoreThreshold != nil && result.confidence < *options.LowScoreThreshold &&
+		options.LowScoreTag != nil && len(*options.LowScoreTag) > 0 {
+		if err := t.addTagToScene(ctx, scene, *options.LowScoreTag); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 type scrapeResult struct {
 	result *scraper.ScrapedScene
 	source ScraperSource
+	// score of the match, from 0 to 1
+	confidence float64
+
+	// the following are only set when results from multiple sources are merged
+
+	studioSource     *ScraperSource
+	performerSources map[*models.ScrapedPerformer]ScraperSource
+	stashIDs         []models.StashID
+	// names of the sources that supplied each field
+	provenance map[string][]string
+}
+
+// studioEndpoint returns the stash-box endpoint of the source that supplied the studio.
+func (r *scrapeResult) studioEndpoint() string {
+	if r.studioSource != nil {
+		return r.studioSource.RemoteSite
+	}
+
+	return r.source.RemoteSite
+}
+
+// performerEndpoint returns the stash-box endpoint of the source that supplied p.
+func (r *scrapeResult) performerEndpoint(p *models.ScrapedPerformer) string {
+	if source, found := r.performerSources[p]; found {
+		return source.RemoteSite
+	}
+
+	return r.source.RemoteSite
+}
+
+// remoteSiteIDs returns the stash ids of the scraped scene.
+func (r *scrapeResult) remoteSiteIDs() []models.StashID {
+	if r.stashIDs != nil {
+		return r.stashIDs
+	}
+
+	if r.result.RemoteSiteID == nil || r.source.RemoteSite == "" {
+		return nil
+	}
+
+	return []models.StashID{
+		{
+			StashID:  *r.result.RemoteSiteID,
+			Endpoint: r.source.RemoteSite,
+		},
+	}
 }
 
 func (t *SceneIdentifier) scrapeScene(ctx context.Context, scene *models.Scene) (*scrapeResult, error) {
+	if t.MergeResults {
+		return t.scrapeAndMergeScene(ctx, scene)
+	}
+
 	// iterate through the input sources
 	for _, source := range t.Sources {
 		// scrape using the source
@@ -108,15 +199,22 @@ func (t *SceneIdentifier) scrapeScene(ctx context.Context, scene *models.Scene)
 
 		if len(results) > 0 {
 			options := t.getOptions(source)
-			if len(results) > 1 && utils.IsTrue(options.SkipMultipleMatches) {
+			scored := scoreResults(scene, results, options.MinimumScore)
+			if len(scored) == 0 {
+				logger.Infof("Ignored %d matches from %s with a score below %v for %s", len(results), source.Name, *options.MinimumScore, scene.Path)
+				continue
+			}
+
+			if len(scored) > 1 && utils.IsTrue(options.SkipMultipleMatches) {
 				return nil, &MultipleMatchesFoundError{
 					Source: source,
 				}
 			} else {
-				// if results were found then return
+				// if results were found then return the best match
 				return &scrapeResult{
-					result: results[0],
-					source: source,
+					result:     scored[0].result,
+					source:     source,
+					confidence: scored[0].score,
 				}, nil
 			}
 		}
@@ -156,6 +254,15 @@ func (t *SceneIdentifier) getOptions(source ScraperSource) MetadataOptions {
 	if source.Options.SkipSingleNamePerformerTag != nil && len(*source.Options.SkipSingleNamePerformerTag) > 0 {
 		options.SkipSingleNamePerformerTag = source.Options.SkipSingleNamePerformerTag
 	}
+	if source.Options.MinimumScore != nil {
+		options.MinimumScore = source.Options.MinimumScore
+	}
+	if source.Options.LowScoreThreshold != nil {
+		options.LowScoreThreshold = source.Options.LowScoreThreshold
+	}
+	if source.Options.LowScoreTag != nil && len(*source.Options.LowScoreTag) > 0 {
+		options.LowScoreTag = source.Options.LowScoreTag
+	}
 
 	return options
 }
@@ -264,19 +371,11 @@ func (t *SceneIdentifier) getSceneUpdater(ctx context.Context, s *models.Scene,
 }
 
 func (t *SceneIdentifier) modifyScene(ctx context.Context, s *models.Scene, result *scrapeResult) error {
+	ctx = result.source.changeOrigin(ctx)
+
 	var updater *scene.UpdateSet
 	if err := txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
-		// load scene relationships
-		if err := s.LoadURLs(ctx, t.SceneReaderUpdater); err != nil {
-			return err
-		}
-		if err := s.LoadPerformerIDs(ctx, t.SceneReaderUpdater); err != nil {
-			return err
-		}
-		if err := s.LoadTagIDs(ctx, t.SceneReaderUpdater); err != nil {
-			return err
-		}
-		if err := s.LoadStashIDs(ctx, t.SceneReaderUpdater); err != nil {
+		if err := t.loadSceneRelationships(ctx, s); err != nil {
 			return err
 		}
 
@@ -301,7 +400,11 @@ func (t *SceneIdentifier) modifyScene(ctx context.Context, s *models.Scene, resu
 		if title.Ptr() != nil {
 			as = fmt.Sprintf(" as %s", title.Value)
 		}
-		logger.Infof("Successfully identified %s%s using %s", s.Path, as, result.source.Name)
+		if result.provenance != nil {
+			logger.Infof("Successfully identified %s%s by merging results: %s", s.Path, as, result.provenanceString())
+		} else {
+			logger.Infof("Successfully identified %s%s using %s", s.Path, as, result.source.Name)
+		}
 
 		return nil
 	}); err != nil {
@@ -318,6 +421,19 @@ func (t *SceneIdentifier) modifyScene(ctx context.Context, s *models.Scene, resu
 	return nil
 }
 
+func (t *SceneIdentifier) loadSceneRelationships(ctx context.Context, s *models.Scene) error {
+	if err := s.LoadURLs(ctx, t.SceneReaderUpdater); err != nil {
+		return err
+	}
+	if err := s.LoadPerformerIDs(ctx, t.SceneReaderUpdater); err != nil {
+		return err
+	}
+	if err := s.LoadTagIDs(ctx, t.SceneReaderUpdater); err != nil {
+		return err
+	}
+	return s.LoadStashIDs(ctx, t.SceneReaderUpdater)
+}
+
 func (t *SceneIdentifier) addTagToScene(ctx context.Context, s *models.Scene, tagToAdd string) error {
 	if err := txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
 		tagID, err := strconv.Atoi(tagToAdd)
@@ -341,9 +457,9 @@ func (t *SceneIdentifier) addTagToScene(ctx context.Context, s *models.Scene, ta
 
 		ret, err := t.TagFinderCreator.Find(ctx, tagID)
 		if err != nil {
-			logger.Infof("Added tag id %s to skipped scene %s", tagToAdd, s.Path)
+			logger.Infof("Added tag id %s to scene %s", tagToAdd, s.Path)
 		} else {
-			logger.Infof("Added tag %s to skipped scene %s", ret.Name, s.Path)
+			logger.Infof("Added tag %s to scene %s", ret.Name, s.Path)
 		}
 
 		return nil
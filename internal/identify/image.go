@@ -0,0 +1,285 @@
+package identify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/plugin/hook"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stashapp/stash/pkg/txn"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// ImageScraper is implemented by sources that are able to scrape images.
+// Sources that do not implement this interface are skipped when identifying
+// images.
+type ImageScraper interface {
+	ScrapeImages(ctx context.Context, imageID int) ([]*scraper.ScrapedImage, error)
+}
+
+type ImageReaderUpdater interface {
+	models.ImageUpdater
+	models.PerformerIDLoader
+	models.TagIDLoader
+	models.URLLoader
+}
+
+type PostHookExecutor interface {
+	ExecutePostHooks(ctx context.Context, id int, hookType hook.TriggerEnum, input interface{}, inputFields []string)
+}
+
+type ImageIdentifier struct {
+	TxnManager         txn.Manager
+	ImageReaderUpdater ImageReaderUpdater
+	StudioReaderWriter models.StudioReaderWriter
+	PerformerCreator   PerformerCreator
+	TagFinderCreator   models.TagFinderCreator
+
+	DefaultOptions   *MetadataOptions
+	Sources          []ScraperSource
+	PostHookExecutor PostHookExecutor
+}
+
+type imageScrapeResult struct {
+	result *scraper.ScrapedImage
+	source ScraperSource
+}
+
+func (t *ImageIdentifier) Identify(ctx context.Context, i *models.Image) error {
+	result, err := t.scrapeImage(ctx, i)
+	var multipleMatchErr *MultipleMatchesFoundError
+	if err != nil {
+		if !errors.As(err, &multipleMatchErr) {
+			return err
+		}
+	}
+
+	if result == nil {
+		if multipleMatchErr != nil {
+			logger.Debugf("Identify skipped because multiple results returned for %s", i.Path)
+
+			// find if the image should be tagged for multiple results
+			tagID, err := skipMultipleMatchTag(t.DefaultOptions, multipleMatchErr)
+			if err != nil {
+				return err
+			}
+			if tagID != nil {
+				return t.addTagToImage(ctx, i, *tagID)
+			}
+		} else {
+			logger.Debugf("Unable to identify %s", i.Path)
+		}
+		return nil
+	}
+
+	// results were found, modify the image
+	if err := t.modifyImage(ctx, i, result); err != nil {
+		return fmt.Errorf("error modifying image: %v", err)
+	}
+
+	return nil
+}
+
+func (t *ImageIdentifier) scrapeImage(ctx context.Context, i *models.Image) (*imageScrapeResult, error) {
+	result, source, err := scrapeFirst(t.Sources, t.DefaultOptions, func(source ScraperSource) ([]*scraper.ScrapedImage, bool, error) {
+		s, ok := source.Scraper.(ImageScraper)
+		if !ok {
+			// source does not support images
+			return nil, false, nil
+		}
+
+		results, err := s.ScrapeImages(ctx, i.ID)
+		return results, true, err
+	})
+	if err != nil || source == nil {
+		return nil, err
+	}
+
+	return &imageScrapeResult{
+		result: result,
+		source: *source,
+	}, nil
+}
+
+func (t *ImageIdentifier) getImagePartial(ctx context.Context, i *models.Image, result *imageScrapeResult) (*models.ImagePartial, error) {
+	options, fieldOptions := sourceOptions(t.DefaultOptions, result.source)
+	scraped := result.result
+
+	partial := getImagePartial(i, scraped, fieldOptions, utils.IsTrue(options.SetOrganized))
+
+	r := relationships{
+		studioReaderWriter: t.StudioReaderWriter,
+		performerCreator:   t.PerformerCreator,
+		tagCreator:         t.TagFinderCreator,
+		endpoint:           result.source.RemoteSite,
+		options:            options,
+		fieldOptions:       fieldOptions,
+	}
+
+	studioID, err := r.studio(ctx, scraped.Studio, i.StudioID)
+	if err != nil {
+		return nil, err
+	}
+	if studioID != nil {
+		partial.StudioID = models.NewOptionalInt(*studioID)
+	}
+
+	performerIDs, skippedSingleName, err := r.performers(ctx, scraped.Performers, i.PerformerIDs.List())
+	if err != nil {
+		return nil, err
+	}
+	if performerIDs != nil {
+		partial.PerformerIDs = &models.UpdateIDs{
+			IDs:  performerIDs,
+			Mode: models.RelationshipUpdateModeSet,
+		}
+	}
+
+	tagIDs, err := r.tags(ctx, scraped.Tags, i.TagIDs.List(), skippedSingleName)
+	if err != nil {
+		return nil, err
+	}
+	if tagIDs != nil {
+		partial.TagIDs = &models.UpdateIDs{
+			IDs:  tagIDs,
+			Mode: models.RelationshipUpdateModeSet,
+		}
+	}
+
+	return &partial, nil
+}
+
+func (t *ImageIdentifier) modifyImage(ctx context.Context, i *models.Image, result *imageScrapeResult) error {
+	ctx = result.source.changeOrigin(ctx)
+
+	updated := false
+	if err := txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
+		// load image relationships
+		if err := i.LoadURLs(ctx, t.ImageReaderUpdater); err != nil {
+			return err
+		}
+		if err := i.LoadPerformerIDs(ctx, t.ImageReaderUpdater); err != nil {
+			return err
+		}
+		if err := i.LoadTagIDs(ctx, t.ImageReaderUpdater); err != nil {
+			return err
+		}
+
+		partial, err := t.getImagePartial(ctx, i, result)
+		if err != nil {
+			return err
+		}
+
+		// don't update anything if nothing was set
+		if imagePartialIsEmpty(*partial) {
+			logger.Debugf("Nothing to set for %s", i.Path)
+			return nil
+		}
+
+		partial.UpdatedAt = models.NewOptionalTime(time.Now())
+		if _, err := t.ImageReaderUpdater.UpdatePartial(ctx, i.ID, *partial); err != nil {
+			return fmt.Errorf("error updating image: %w", err)
+		}
+		updated = true
+
+		as := ""
+		if partial.Title.Ptr() != nil {
+			as = fmt.Sprintf(" as %s", partial.Title.Value)
+		}
+		logger.Infof("Successfully identified %s%s using %s", i.Path, as, result.source.Name)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// fire post-update hooks
+	if updated && t.PostHookExecutor != nil {
+		t.PostHookExecutor.ExecutePostHooks(ctx, i.ID, hook.ImageUpdatePost, nil, nil)
+	}
+
+	return nil
+}
+
+func (t *ImageIdentifier) addTagToImage(ctx context.Context, i *models.Image, tagID int) error {
+	return txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
+		if err := i.LoadTagIDs(ctx, t.ImageReaderUpdater); err != nil {
+			return err
+		}
+
+		if sliceutil.Contains(i.TagIDs.List(), tagID) {
+			// skip if the image was already tagged
+			return nil
+		}
+
+		if err := image.AddTag(ctx, t.ImageReaderUpdater, i, tagID); err != nil {
+			return err
+		}
+
+		ret, err := t.TagFinderCreator.Find(ctx, tagID)
+		if err != nil {
+			logger.Infof("Added tag id %d to skipped image %s", tagID, i.Path)
+		} else {
+			logger.Infof("Added tag %s to skipped image %s", ret.Name, i.Path)
+		}
+
+		return nil
+	})
+}
+
+func imagePartialIsEmpty(p models.ImagePartial) bool {
+	return !p.Title.Set && !p.Code.Set && !p.Date.Set && !p.Details.Set &&
+		!p.Photographer.Set && !p.Organized.Set && !p.StudioID.Set &&
+		p.URLs == nil && p.PerformerIDs == nil && p.TagIDs == nil
+}
+
+func getImagePartial(i *models.Image, scraped *scraper.ScrapedImage, fieldOptions map[string]*FieldOptions, setOrganized bool) models.ImagePartial {
+	partial := models.ImagePartial{}
+
+	if scraped.Title != nil && (i.Title != *scraped.Title) {
+		if shouldSetSingleValueField(fieldOptions["title"], i.Title != "") {
+			partial.Title = models.NewOptionalString(*scraped.Title)
+		}
+	}
+	if scraped.Date != nil && (i.Date == nil || i.Date.String() != *scraped.Date) {
+		if shouldSetSingleValueField(fieldOptions["date"], i.Date != nil) {
+			d, err := models.ParseDate(*scraped.Date)
+			if err == nil {
+				partial.Date = models.NewOptionalDate(d)
+			}
+		}
+	}
+	if scraped.Details != nil && (i.Details != *scraped.Details) {
+		if shouldSetSingleValueField(fieldOptions["details"], i.Details != "") {
+			partial.Details = models.NewOptionalString(*scraped.Details)
+		}
+	}
+	if scraped.Photographer != nil && (i.Photographer != *scraped.Photographer) {
+		if shouldSetSingleValueField(fieldOptions["photographer"], i.Photographer != "") {
+			partial.Photographer = models.NewOptionalString(*scraped.Photographer)
+		}
+	}
+	if scraped.Code != nil && (i.Code != *scraped.Code) {
+		if shouldSetSingleValueField(fieldOptions["code"], i.Code != "") {
+			partial.Code = models.NewOptionalString(*scraped.Code)
+		}
+	}
+	if urls := stringsToSet(fieldOptions, "url", i.URLs.List(), scraped.URLs); urls != nil {
+		partial.URLs = &models.UpdateStrings{
+			Values: urls,
+			Mode:   models.RelationshipUpdateModeSet,
+		}
+	}
+
+	if setOrganized && !i.Organized {
+		partial.Organized = models.NewOptionalBool(true)
+	}
+
+	return partial
+}
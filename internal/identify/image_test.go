@@ -0,0 +1,129 @@
+package identify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+)
+
+func Test_getImagePartial(t *testing.T) {
+	var (
+		originalTitle        = "originalTitle"
+		originalPhotographer = "originalPhotographer"
+		originalURL          = "originalURL"
+	)
+
+	var (
+		scrapedTitle        = "scrapedTitle"
+		scrapedPhotographer = "scrapedPhotographer"
+		scrapedURL          = "scrapedURL"
+	)
+
+	originalImage := &models.Image{
+		Title:        originalTitle,
+		Photographer: originalPhotographer,
+		URLs:         models.NewRelatedStrings([]string{originalURL}),
+	}
+
+	emptyImage := &models.Image{
+		URLs: models.NewRelatedStrings([]string{}),
+	}
+
+	scrapedImage := &scraper.ScrapedImage{
+		Title:        &scrapedTitle,
+		Photographer: &scrapedPhotographer,
+		URLs:         []string{scrapedURL},
+	}
+
+	makeFieldOptions := func(input *FieldOptions) map[string]*FieldOptions {
+		return map[string]*FieldOptions{
+			"title":        input,
+			"photographer": input,
+			"url":          input,
+		}
+	}
+
+	overwriteAll := makeFieldOptions(&FieldOptions{
+		Strategy: FieldStrategyOverwrite,
+	})
+	ignoreAll := makeFieldOptions(&FieldOptions{
+		Strategy: FieldStrategyIgnore,
+	})
+	mergeAll := makeFieldOptions(&FieldOptions{
+		Strategy: FieldStrategyMerge,
+	})
+
+	tests := []struct {
+		name         string
+		image        *models.Image
+		fieldOptions map[string]*FieldOptions
+		setOrganized bool
+		want         models.ImagePartial
+	}{
+		{
+			"overwrite",
+			originalImage,
+			overwriteAll,
+			false,
+			models.ImagePartial{
+				Title:        models.NewOptionalString(scrapedTitle),
+				Photographer: models.NewOptionalString(scrapedPhotographer),
+				URLs: &models.UpdateStrings{
+					Values: []string{scrapedURL},
+					Mode:   models.RelationshipUpdateModeSet,
+				},
+			},
+		},
+		{
+			"ignore",
+			originalImage,
+			ignoreAll,
+			false,
+			models.ImagePartial{},
+		},
+		{
+			"merge existing",
+			originalImage,
+			mergeAll,
+			false,
+			models.ImagePartial{
+				URLs: &models.UpdateStrings{
+					Values: []string{originalURL, scrapedURL},
+					Mode:   models.RelationshipUpdateModeSet,
+				},
+			},
+		},
+		{
+			"merge empty",
+			emptyImage,
+			mergeAll,
+			false,
+			models.ImagePartial{
+				Title:        models.NewOptionalString(scrapedTitle),
+				Photographer: models.NewOptionalString(scrapedPhotographer),
+				URLs: &models.UpdateStrings{
+					Values: []string{scrapedURL},
+					Mode:   models.RelationshipUpdateModeSet,
+				},
+			},
+		},
+		{
+			"set organized",
+			originalImage,
+			ignoreAll,
+			true,
+			models.ImagePartial{
+				Organized: models.NewOptionalBool(true),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getImagePartial(tt.image, scrapedImage, tt.fieldOptions, tt.setOrganized); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getImagePartial() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
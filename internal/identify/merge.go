@@ -0,0 +1,301 @@
+package identify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"sort"
+	"strings"
+
+	// register image decoders used to determine cover resolution
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// scrapeAndMergeScene scrapes the scene using all sources and merges the
+// results into a single result.
+func (t *SceneIdentifier) scrapeAndMergeScene(ctx context.Context, scene *models.Scene) (*scrapeResult, error) {
+	var results []*scrapeResult
+	var multipleMatchErr *MultipleMatchesFoundError
+
+	for _, source := range t.Sources {
+		scraped, err := source.Scraper.ScrapeScenes(ctx, scene.ID)
+		if err != nil {
+			logger.Errorf("error scraping from %v: %v", source.Scraper, err)
+			continue
+		}
+
+		if len(scraped) == 0 {
+			continue
+		}
+
+		options := t.getOptions(source)
+		scored := scoreResults(scene, scraped, options.MinimumScore)
+		if len(scored) == 0 {
+			logger.Infof("Ignored %d matches from %s with a score below %v for %s", len(scraped), source.Name, *options.MinimumScore, scene.Path)
+			continue
+		}
+
+		if len(scored) > 1 && utils.IsTrue(options.SkipMultipleMatches) {
+			// skip this source, but keep going with the others
+			if multipleMatchErr == nil {
+				multipleMatchErr = &MultipleMatchesFoundError{
+					Source: source,
+				}
+			}
+			continue
+		}
+
+		results = append(results, &scrapeResult{
+			result:     scored[0].result,
+			source:     source,
+			confidence: scored[0].score,
+		})
+	}
+
+	if len(results) == 0 {
+		if multipleMatchErr != nil {
+			return nil, multipleMatchErr
+		}
+		return nil, nil
+	}
+
+	return mergeSceneResults(ctx, results, t.FieldPriorities), nil
+}
+
+// mergeSceneResults combines the results from multiple sources field by field.
+//
+// If a priority is set for a field, only the listed sources are used for that
+// field, in the order given. Otherwise, all sources are used in source order.
+// Single-value fields are taken from the first source with a value, and
+// multi-value fields are combined from all sources. If no priority is set for
+// the cover image, the highest resolution image is used.
+func mergeSceneResults(ctx context.Context, results []*scrapeResult, priorities []*FieldPriority) *scrapeResult {
+	priorityMap := make(map[string][]string)
+	for _, p := range priorities {
+		priorityMap[p.Field] = p.Sources
+	}
+
+	fieldResults := func(field string) []*scrapeResult {
+		return orderResults(results, priorityMap[field])
+	}
+
+	ret := &scrapeResult{
+		result:           &scraper.ScrapedScene{},
+		source:           results[0].source,
+		performerSources: make(map[*models.ScrapedPerformer]ScraperSource),
+		stashIDs:         []models.StashID{},
+		provenance:       make(map[string][]string),
+		confidence:       1,
+	}
+	merged := ret.result
+
+	// the merged result is only as certain as its least certain source
+	for _, r := range results {
+		if r.confidence < ret.confidence {
+			ret.confidence = r.confidence
+		}
+	}
+
+	mergeString := func(field string, get func(s *scraper.ScrapedScene) *string) *string {
+		for _, r := range fieldResults(field) {
+			if v := get(r.result); v != nil && *v != "" {
+				ret.addProvenance(field, r.source)
+				return v
+			}
+		}
+		return nil
+	}
+
+	merged.Title = mergeString("title", func(s *scraper.ScrapedScene) *string { return s.Title })
+	merged.Code = mergeString("code", func(s *scraper.ScrapedScene) *string { return s.Code })
+	merged.Details = mergeString("details", func(s *scraper.ScrapedScene) *string { return s.Details })
+	merged.Director = mergeString("director", func(s *scraper.ScrapedScene) *string { return s.Director })
+	merged.Date = mergeString("date", func(s *scraper.ScrapedScene) *string { return s.Date })
+
+	for _, r := range fieldResults("url") {
+		n := len(merged.URLs)
+		merged.URLs = sliceutil.AppendUniques(merged.URLs, r.result.URLs)
+		if len(merged.URLs) > n {
+			ret.addProvenance("url", r.source)
+		}
+	}
+
+	for _, r := range fieldResults("studio") {
+		if r.result.Studio != nil {
+			merged.Studio = r.result.Studio
+			source := r.source
+			ret.studioSource = &source
+			ret.addProvenance("studio", r.source)
+			break
+		}
+	}
+
+	for _, r := range fieldResults("performers") {
+		for _, p := range r.result.Performers {
+			if containsPerformer(merged.Performers, p) {
+				continue
+			}
+
+			merged.Performers = append(merged.Performers, p)
+			ret.performerSources[p] = r.source
+			ret.addProvenance("performers", r.source)
+		}
+	}
+
+	for _, r := range fieldResults("tags") {
+		for _, t := range r.result.Tags {
+			if containsTag(merged.Tags, t) {
+				continue
+			}
+
+			merged.Tags = append(merged.Tags, t)
+			ret.addProvenance("tags", r.source)
+		}
+	}
+
+	for _, r := range fieldResults("stash_ids") {
+		if r.result.RemoteSiteID != nil && r.source.RemoteSite != "" {
+			ret.stashIDs = append(ret.stashIDs, models.StashID{
+				StashID:  *r.result.RemoteSiteID,
+				Endpoint: r.source.RemoteSite,
+			})
+			ret.addProvenance("stash_ids", r.source)
+		}
+	}
+
+	_, coverPrioritised := priorityMap["cover_image"]
+	if coverPrioritised {
+		merged.Image = mergeString("cover_image", func(s *scraper.ScrapedScene) *string { return s.Image })
+	} else {
+		merged.Image = ret.mergeCover(ctx, results)
+	}
+
+	// fields not used by identify are taken from the first result
+	first := results[0].result
+	merged.Movies = first.Movies
+	merged.Duration = first.Duration
+	merged.Fingerprints = first.Fingerprints
+	merged.File = first.File
+	merged.RemoteSiteID = first.RemoteSiteID
+
+	return ret
+}
+
+// mergeCover returns the highest resolution cover image from results.
+// Images that cannot be decoded are only used if no other image is found.
+func (r *scrapeResult) mergeCover(ctx context.Context, results []*scrapeResult) *string {
+	var ret *string
+	var retSource ScraperSource
+	bestArea := -1
+
+	for _, rr := range results {
+		img := rr.result.Image
+		if img == nil || *img == "" {
+			continue
+		}
+
+		area := 0
+		data, err := utils.ProcessImageInput(ctx, *img)
+		if err != nil {
+			logger.Warnf("error reading cover image from %s: %v", rr.source.Name, err)
+		} else if cfg, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+			area = cfg.Width * cfg.Height
+		}
+
+		if area > bestArea {
+			ret = img
+			retSource = rr.source
+			bestArea = area
+		}
+	}
+
+	if ret != nil {
+		r.addProvenance("cover_image", retSource)
+	}
+
+	return ret
+}
+
+// orderResults returns the results from the sources in priority, in the
+// order given. If priority is empty, results is returned.
+func orderResults(results []*scrapeResult, priority []string) []*scrapeResult {
+	if len(priority) == 0 {
+		return results
+	}
+
+	var ret []*scrapeResult
+	for _, id := range priority {
+		for _, r := range results {
+			if r.source.ID == id {
+				ret = append(ret, r)
+			}
+		}
+	}
+
+	return ret
+}
+
+func containsPerformer(performers []*models.ScrapedPerformer, p *models.ScrapedPerformer) bool {
+	for _, pp := range performers {
+		if pp.StoredID != nil && p.StoredID != nil {
+			if *pp.StoredID == *p.StoredID {
+				return true
+			}
+			continue
+		}
+
+		if pp.Name != nil && p.Name != nil && strings.EqualFold(*pp.Name, *p.Name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsTag(tags []*models.ScrapedTag, t *models.ScrapedTag) bool {
+	for _, tt := range tags {
+		if tt.StoredID != nil && t.StoredID != nil {
+			if *tt.StoredID == *t.StoredID {
+				return true
+			}
+			continue
+		}
+
+		if strings.EqualFold(tt.Name, t.Name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *scrapeResult) addProvenance(field string, source ScraperSource) {
+	r.provenance[field] = sliceutil.AppendUnique(r.provenance[field], source.Name)
+}
+
+// provenanceString returns a description of the sources that supplied each field.
+func (r *scrapeResult) provenanceString() string {
+	fields := make([]string, 0, len(r.provenance))
+	for f := range r.provenance {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+
+	var parts []string
+	for _, f := range fields {
+		parts = append(parts, fmt.Sprintf("%s from %s", f, strings.Join(r.provenance[f], ", ")))
+	}
+
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,149 @@
+package identify
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"reflect"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+func testPNGDataURL(t *testing.T, width, height int) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("error encoding png: %v", err)
+	}
+
+	return "data:image/png;base64," + utils.GetBase64StringFromData(buf.Bytes())
+}
+
+func Test_mergeSceneResults(t *testing.T) {
+	var (
+		titleA    = "titleA"
+		titleB    = "titleB"
+		detailsB  = "detailsB"
+		urlA      = "urlA"
+		urlB      = "urlB"
+		studioB   = &models.ScrapedStudio{Name: "studioB"}
+		tagID     = "1"
+		tagA      = &models.ScrapedTag{Name: "tagA", StoredID: &tagID}
+		tagB      = &models.ScrapedTag{Name: "TAGA"}
+		tagC      = &models.ScrapedTag{Name: "tagC"}
+		nameA     = "performerA"
+		nameB     = "performerB"
+		performA  = &models.ScrapedPerformer{Name: &nameA}
+		performB  = &models.ScrapedPerformer{Name: &nameB}
+		remoteB   = "remoteB"
+		endpointB = "endpointB"
+	)
+
+	smallCover := testPNGDataURL(t, 10, 10)
+	largeCover := testPNGDataURL(t, 20, 20)
+
+	sourceA := ScraperSource{ID: "a", Name: "A"}
+	sourceB := ScraperSource{ID: "b", Name: "B", RemoteSite: endpointB}
+
+	results := []*scrapeResult{
+		{
+			source: sourceA,
+			result: &scraper.ScrapedScene{
+				Title:      &titleA,
+				URLs:       []string{urlA},
+				Tags:       []*models.ScrapedTag{tagA},
+				Performers: []*models.ScrapedPerformer{performA},
+				Image:      &smallCover,
+			},
+		},
+		{
+			source: sourceB,
+			result: &scraper.ScrapedScene{
+				Title:        &titleB,
+				Details:      &detailsB,
+				URLs:         []string{urlA, urlB},
+				Studio:       studioB,
+				Tags:         []*models.ScrapedTag{tagB, tagC},
+				Performers:   []*models.ScrapedPerformer{performB},
+				RemoteSiteID: &remoteB,
+				Image:        &largeCover,
+			},
+		},
+	}
+
+	t.Run("default priority", func(t *testing.T) {
+		got := mergeSceneResults(testCtx, results, nil)
+		merged := got.result
+
+		if merged.Title == nil || *merged.Title != titleA {
+			t.Errorf("Title = %v, want %s", merged.Title, titleA)
+		}
+		if merged.Details == nil || *merged.Details != detailsB {
+			t.Errorf("Details = %v, want %s", merged.Details, detailsB)
+		}
+		if !reflect.DeepEqual(merged.URLs, []string{urlA, urlB}) {
+			t.Errorf("URLs = %v, want %v", merged.URLs, []string{urlA, urlB})
+		}
+		if merged.Studio != studioB {
+			t.Errorf("Studio = %v, want %v", merged.Studio, studioB)
+		}
+		if !reflect.DeepEqual(merged.Tags, []*models.ScrapedTag{tagA, tagC}) {
+			t.Errorf("Tags = %v, want %v", merged.Tags, []*models.ScrapedTag{tagA, tagC})
+		}
+		if !reflect.DeepEqual(merged.Performers, []*models.ScrapedPerformer{performA, performB}) {
+			t.Errorf("Performers = %v, want %v", merged.Performers, []*models.ScrapedPerformer{performA, performB})
+		}
+		if merged.Image == nil || *merged.Image != largeCover {
+			t.Error("expected highest resolution cover image")
+		}
+
+		if got.studioEndpoint() != endpointB {
+			t.Errorf("studioEndpoint() = %s, want %s", got.studioEndpoint(), endpointB)
+		}
+		if got.performerEndpoint(performA) != "" || got.performerEndpoint(performB) != endpointB {
+			t.Error("performer endpoints not set from the supplying source")
+		}
+
+		wantStashIDs := []models.StashID{{StashID: remoteB, Endpoint: endpointB}}
+		if !reflect.DeepEqual(got.remoteSiteIDs(), wantStashIDs) {
+			t.Errorf("remoteSiteIDs() = %v, want %v", got.remoteSiteIDs(), wantStashIDs)
+		}
+
+		wantProvenance := map[string][]string{
+			"title":       {"A"},
+			"details":     {"B"},
+			"url":         {"A", "B"},
+			"studio":      {"B"},
+			"tags":        {"A", "B"},
+			"performers":  {"A", "B"},
+			"stash_ids":   {"B"},
+			"cover_image": {"B"},
+		}
+		if !reflect.DeepEqual(got.provenance, wantProvenance) {
+			t.Errorf("provenance = %v, want %v", got.provenance, wantProvenance)
+		}
+	})
+
+	t.Run("field priority", func(t *testing.T) {
+		got := mergeSceneResults(testCtx, results, []*FieldPriority{
+			{Field: "title", Sources: []string{"b", "a"}},
+			{Field: "tags", Sources: []string{"b"}},
+			{Field: "cover_image", Sources: []string{"a"}},
+		})
+		merged := got.result
+
+		if merged.Title == nil || *merged.Title != titleB {
+			t.Errorf("Title = %v, want %s", merged.Title, titleB)
+		}
+		if !reflect.DeepEqual(merged.Tags, []*models.ScrapedTag{tagB, tagC}) {
+			t.Errorf("Tags = %v, want %v", merged.Tags, []*models.ScrapedTag{tagB, tagC})
+		}
+		if merged.Image == nil || *merged.Image != smallCover {
+			t.Error("expected cover image from prioritised source")
+		}
+	})
+}
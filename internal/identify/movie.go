@@ -0,0 +1,337 @@
+package identify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/plugin/hook"
+	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stashapp/stash/pkg/txn"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// MovieScraper is implemented by sources that are able to scrape movies.
+// Sources that do not implement this interface are skipped when identifying
+// movies.
+type MovieScraper interface {
+	ScrapeMovies(ctx context.Context, movieID int) ([]*models.ScrapedMovie, error)
+}
+
+type MovieReaderUpdater interface {
+	models.MovieUpdater
+	models.URLLoader
+	models.TagIDLoader
+	HasFrontImage(ctx context.Context, movieID int) (bool, error)
+	HasBackImage(ctx context.Context, movieID int) (bool, error)
+}
+
+type MovieIdentifier struct {
+	TxnManager         txn.Manager
+	MovieReaderUpdater MovieReaderUpdater
+	StudioReaderWriter models.StudioReaderWriter
+	TagFinderCreator   models.TagFinderCreator
+
+	DefaultOptions   *MetadataOptions
+	Sources          []ScraperSource
+	PostHookExecutor PostHookExecutor
+}
+
+type movieScrapeResult struct {
+	result *models.ScrapedMovie
+	source ScraperSource
+}
+
+func (t *MovieIdentifier) Identify(ctx context.Context, m *models.Movie) error {
+	result, err := t.scrapeMovie(ctx, m)
+	var multipleMatchErr *MultipleMatchesFoundError
+	if err != nil {
+		if !errors.As(err, &multipleMatchErr) {
+			return err
+		}
+	}
+
+	if result == nil {
+		if multipleMatchErr != nil {
+			logger.Debugf("Identify skipped because multiple results returned for %s", m.Name)
+
+			// find if the movie should be tagged for multiple results
+			tagID, err := skipMultipleMatchTag(t.DefaultOptions, multipleMatchErr)
+			if err != nil {
+				return err
+			}
+			if tagID != nil {
+				return t.addTagToMovie(ctx, m, *tagID)
+			}
+		} else {
+			logger.Debugf("Unable to identify %s", m.Name)
+		}
+		return nil
+	}
+
+	// results were found, modify the movie
+	if err := t.modifyMovie(ctx, m, result); err != nil {
+		return fmt.Errorf("error modifying movie: %v", err)
+	}
+
+	return nil
+}
+
+func (t *MovieIdentifier) scrapeMovie(ctx context.Context, m *models.Movie) (*movieScrapeResult, error) {
+	result, source, err := scrapeFirst(t.Sources, t.DefaultOptions, func(source ScraperSource) ([]*models.ScrapedMovie, bool, error) {
+		s, ok := source.Scraper.(MovieScraper)
+		if !ok {
+			// source does not support movies
+			return nil, false, nil
+		}
+
+		results, err := s.ScrapeMovies(ctx, m.ID)
+		return results, true, err
+	})
+	if err != nil || source == nil {
+		return nil, err
+	}
+
+	return &movieScrapeResult{
+		result: result,
+		source: *source,
+	}, nil
+}
+
+func (t *MovieIdentifier) getMoviePartial(ctx context.Context, m *models.Movie, result *movieScrapeResult) (*models.MoviePartial, error) {
+	options, fieldOptions := sourceOptions(t.DefaultOptions, result.source)
+	scraped := result.result
+
+	partial := getMoviePartial(m, scraped, fieldOptions)
+
+	r := relationships{
+		studioReaderWriter: t.StudioReaderWriter,
+		tagCreator:         t.TagFinderCreator,
+		endpoint:           result.source.RemoteSite,
+		options:            options,
+		fieldOptions:       fieldOptions,
+	}
+
+	studioID, err := r.studio(ctx, scraped.Studio, m.StudioID)
+	if err != nil {
+		return nil, err
+	}
+	if studioID != nil {
+		partial.StudioID = models.NewOptionalInt(*studioID)
+	}
+
+	tagIDs, err := r.tags(ctx, scraped.Tags, m.TagIDs.List(), false)
+	if err != nil {
+		return nil, err
+	}
+	if tagIDs != nil {
+		partial.TagIDs = &models.UpdateIDs{
+			IDs:  tagIDs,
+			Mode: models.RelationshipUpdateModeSet,
+		}
+	}
+
+	return &partial, nil
+}
+
+// getImage returns the scraped movie image for field if it should be set.
+func (t *MovieIdentifier) getImage(ctx context.Context, m *models.Movie, result *movieScrapeResult, field string, scraped *string, hasImage func(ctx context.Context, movieID int) (bool, error)) ([]byte, error) {
+	if scraped == nil || *scraped == "" {
+		return nil, nil
+	}
+
+	_, fieldOptions := sourceOptions(t.DefaultOptions, result.source)
+
+	exists, err := hasImage(ctx, m.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !shouldSetSingleValueField(fieldOptions[field], exists) {
+		return nil, nil
+	}
+
+	img, err := utils.ProcessImageInput(ctx, *scraped)
+	if err != nil {
+		// don't fail the identify if the image could not be downloaded
+		logger.Errorf("Error getting %s for movie %s: %v", field, m.Name, err)
+		return nil, nil
+	}
+
+	return img, nil
+}
+
+func (t *MovieIdentifier) modifyMovie(ctx context.Context, m *models.Movie, result *movieScrapeResult) error {
+	ctx = result.source.changeOrigin(ctx)
+
+	updated := false
+	if err := txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
+		// load movie relationships
+		if err := m.LoadURLs(ctx, t.MovieReaderUpdater); err != nil {
+			return err
+		}
+		if err := m.LoadTagIDs(ctx, t.MovieReaderUpdater); err != nil {
+			return err
+		}
+
+		partial, err := t.getMoviePartial(ctx, m, result)
+		if err != nil {
+			return err
+		}
+
+		frontImage, err := t.getImage(ctx, m, result, "front_image", result.result.FrontImage, t.MovieReaderUpdater.HasFrontImage)
+		if err != nil {
+			return err
+		}
+		backImage, err := t.getImage(ctx, m, result, "back_image", result.result.BackImage, t.MovieReaderUpdater.HasBackImage)
+		if err != nil {
+			return err
+		}
+
+		// don't update anything if nothing was set
+		if moviePartialIsEmpty(*partial) && len(frontImage) == 0 && len(backImage) == 0 {
+			logger.Debugf("Nothing to set for %s", m.Name)
+			return nil
+		}
+
+		partial.UpdatedAt = models.NewOptionalTime(time.Now())
+		if _, err := t.MovieReaderUpdater.UpdatePartial(ctx, m.ID, *partial); err != nil {
+			return fmt.Errorf("error updating movie: %w", err)
+		}
+
+		if len(frontImage) > 0 {
+			if err := t.MovieReaderUpdater.UpdateFrontImage(ctx, m.ID, frontImage); err != nil {
+				return fmt.Errorf("error updating movie front image: %w", err)
+			}
+		}
+		if len(backImage) > 0 {
+			if err := t.MovieReaderUpdater.UpdateBackImage(ctx, m.ID, backImage); err != nil {
+				return fmt.Errorf("error updating movie back image: %w", err)
+			}
+		}
+		updated = true
+
+		logger.Infof("Successfully identified %s using %s", m.Name, result.source.Name)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// fire post-update hooks
+	if updated && t.PostHookExecutor != nil {
+		t.PostHookExecutor.ExecutePostHooks(ctx, m.ID, hook.MovieUpdatePost, nil, nil)
+	}
+
+	return nil
+}
+
+func (t *MovieIdentifier) addTagToMovie(ctx context.Context, m *models.Movie, tagID int) error {
+	return txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
+		if err := m.LoadTagIDs(ctx, t.MovieReaderUpdater); err != nil {
+			return err
+		}
+
+		if sliceutil.Contains(m.TagIDs.List(), tagID) {
+			// skip if the movie was already tagged
+			return nil
+		}
+
+		partial := models.NewMoviePartial()
+		partial.TagIDs = &models.UpdateIDs{
+			IDs:  []int{tagID},
+			Mode: models.RelationshipUpdateModeAdd,
+		}
+		if _, err := t.MovieReaderUpdater.UpdatePartial(ctx, m.ID, partial); err != nil {
+			return err
+		}
+
+		ret, err := t.TagFinderCreator.Find(ctx, tagID)
+		if err != nil {
+			logger.Infof("Added tag id %d to skipped movie %s", tagID, m.Name)
+		} else {
+			logger.Infof("Added tag %s to skipped movie %s", ret.Name, m.Name)
+		}
+
+		return nil
+	})
+}
+
+func moviePartialIsEmpty(p models.MoviePartial) bool {
+	return !p.Name.Set && !p.Aliases.Set && !p.Duration.Set && !p.Date.Set &&
+		!p.Director.Set && !p.Synopsis.Set && !p.StudioID.Set &&
+		p.URLs == nil && p.TagIDs == nil
+}
+
+func getMoviePartial(m *models.Movie, scraped *models.ScrapedMovie, fieldOptions map[string]*FieldOptions) models.MoviePartial {
+	partial := models.MoviePartial{}
+
+	// name is always set, so it is only changed when overwriting
+	if scraped.Name != nil && (m.Name != *scraped.Name) {
+		if shouldSetSingleValueField(fieldOptions["name"], m.Name != "") {
+			partial.Name = models.NewOptionalString(*scraped.Name)
+		}
+	}
+	if scraped.Aliases != nil && (m.Aliases != *scraped.Aliases) {
+		if shouldSetSingleValueField(fieldOptions["aliases"], m.Aliases != "") {
+			partial.Aliases = models.NewOptionalString(*scraped.Aliases)
+		}
+	}
+	if scraped.Duration != nil && shouldSetSingleValueField(fieldOptions["duration"], m.Duration != nil) {
+		d, err := parseMovieDuration(*scraped.Duration)
+		if err == nil && (m.Duration == nil || *m.Duration != d) {
+			partial.Duration = models.NewOptionalInt(d)
+		}
+	}
+	if scraped.Date != nil && (m.Date == nil || m.Date.String() != *scraped.Date) {
+		if shouldSetSingleValueField(fieldOptions["date"], m.Date != nil) {
+			d, err := models.ParseDate(*scraped.Date)
+			if err == nil {
+				partial.Date = models.NewOptionalDate(d)
+			}
+		}
+	}
+	if scraped.Director != nil && (m.Director != *scraped.Director) {
+		if shouldSetSingleValueField(fieldOptions["director"], m.Director != "") {
+			partial.Director = models.NewOptionalString(*scraped.Director)
+		}
+	}
+	if scraped.Synopsis != nil && (m.Synopsis != *scraped.Synopsis) {
+		if shouldSetSingleValueField(fieldOptions["synopsis"], m.Synopsis != "") {
+			partial.Synopsis = models.NewOptionalString(*scraped.Synopsis)
+		}
+	}
+
+	scrapedURLs := scraped.URLs
+	if len(scrapedURLs) == 0 && scraped.URL != nil {
+		scrapedURLs = []string{*scraped.URL}
+	}
+	if urls := stringsToSet(fieldOptions, "url", m.URLs.List(), scrapedURLs); urls != nil {
+		partial.URLs = &models.UpdateStrings{
+			Values: urls,
+			Mode:   models.RelationshipUpdateModeSet,
+		}
+	}
+
+	return partial
+}
+
+// parseMovieDuration parses a scraped duration in seconds, or in the form
+// [[HH:]MM:]SS.
+func parseMovieDuration(v string) (int, error) {
+	ret := 0
+	for _, part := range strings.Split(strings.TrimSpace(v), ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", v, err)
+		}
+		ret = ret*60 + n
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,145 @@
+package identify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func Test_getMoviePartial(t *testing.T) {
+	var (
+		originalName     = "originalName"
+		originalDirector = "originalDirector"
+		originalURL      = "originalURL"
+	)
+
+	var (
+		scrapedName     = "scrapedName"
+		scrapedDirector = "scrapedDirector"
+		scrapedDuration = "1:30:00"
+		scrapedURL      = "scrapedURL"
+	)
+
+	originalMovie := &models.Movie{
+		Name:     originalName,
+		Director: originalDirector,
+		URLs:     models.NewRelatedStrings([]string{originalURL}),
+	}
+
+	emptyMovie := &models.Movie{
+		Name: originalName,
+		URLs: models.NewRelatedStrings([]string{}),
+	}
+
+	scrapedMovie := &models.ScrapedMovie{
+		Name:     &scrapedName,
+		Director: &scrapedDirector,
+		Duration: &scrapedDuration,
+		URLs:     []string{scrapedURL},
+	}
+
+	makeFieldOptions := func(input *FieldOptions) map[string]*FieldOptions {
+		return map[string]*FieldOptions{
+			"name":     input,
+			"director": input,
+			"duration": input,
+			"url":      input,
+		}
+	}
+
+	overwriteAll := makeFieldOptions(&FieldOptions{
+		Strategy: FieldStrategyOverwrite,
+	})
+	ignoreAll := makeFieldOptions(&FieldOptions{
+		Strategy: FieldStrategyIgnore,
+	})
+	mergeAll := makeFieldOptions(&FieldOptions{
+		Strategy: FieldStrategyMerge,
+	})
+
+	tests := []struct {
+		name         string
+		movie        *models.Movie
+		fieldOptions map[string]*FieldOptions
+		want         models.MoviePartial
+	}{
+		{
+			"overwrite",
+			originalMovie,
+			overwriteAll,
+			models.MoviePartial{
+				Name:     models.NewOptionalString(scrapedName),
+				Director: models.NewOptionalString(scrapedDirector),
+				Duration: models.NewOptionalInt(5400),
+				URLs: &models.UpdateStrings{
+					Values: []string{scrapedURL},
+					Mode:   models.RelationshipUpdateModeSet,
+				},
+			},
+		},
+		{
+			"ignore",
+			originalMovie,
+			ignoreAll,
+			models.MoviePartial{},
+		},
+		{
+			"merge existing",
+			originalMovie,
+			mergeAll,
+			models.MoviePartial{
+				Duration: models.NewOptionalInt(5400),
+				URLs: &models.UpdateStrings{
+					Values: []string{originalURL, scrapedURL},
+					Mode:   models.RelationshipUpdateModeSet,
+				},
+			},
+		},
+		{
+			"merge empty",
+			emptyMovie,
+			mergeAll,
+			models.MoviePartial{
+				Director: models.NewOptionalString(scrapedDirector),
+				Duration: models.NewOptionalInt(5400),
+				URLs: &models.UpdateStrings{
+					Values: []string{scrapedURL},
+					Mode:   models.RelationshipUpdateModeSet,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getMoviePartial(tt.movie, scrapedMovie, tt.fieldOptions); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getMoviePartial() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseMovieDuration(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"90", 90, false},
+		{"1:30", 90, false},
+		{"1:30:00", 5400, false},
+		{"invalid", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseMovieDuration(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseMovieDuration() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseMovieDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -15,7 +15,8 @@ type Source struct {
 }
 
 type Options struct {
-	// An ordered list of sources to identify items with. Only the first source that finds a match is used.
+	// An ordered list of sources to identify items with. Only the first source that finds a match is used,
+	// unless MergeResults is true.
 	Sources []*Source `json:"sources"`
 	// Options defined here override the configured defaults
 	Options *MetadataOptions `json:"options"`
@@ -23,6 +24,27 @@ type Options struct {
 	SceneIDs []string `json:"sceneIDs"`
 	// paths of scenes to identify - ignored if scene ids are set
 	Paths []string `json:"paths"`
+	// image ids to identify
+	ImageIDs []string `json:"imageIDs"`
+	// gallery ids to identify
+	GalleryIDs []string `json:"galleryIDs"`
+	// performer ids to identify
+	PerformerIDs []string `json:"performerIDs"`
+	// movie ids to identify
+	MovieIDs []string `json:"movieIDs"`
+	// If true, scenes are scraped using all sources and the results are merged field by field
+	MergeResults *bool `json:"mergeResults"`
+	// Sources to use for specific fields when merging results
+	FieldPriorities []*FieldPriority `json:"fieldPriorities"`
+	// If true, scene matches are stored as proposals for review instead of being applied
+	DryRun *bool `json:"dryRun"`
+}
+
+type FieldPriority struct {
+	Field string `json:"field"`
+	// IDs of the sources to use for the field, in order of priority.
+	// Scrapers are identified by scraper ID, and stash-box instances by endpoint.
+	Sources []string `json:"sources"`
 }
 
 type MetadataOptions struct {
@@ -41,6 +63,12 @@ type MetadataOptions struct {
 	SkipSingleNamePerformers *bool `json:"skipSingleNamePerformers"`
 	// ID of tag to tag skipped single name performers with
 	SkipSingleNamePerformerTag *string `json:"skipSingleNamePerformerTag"`
+	// scene matches with a score below this value are ignored
+	MinimumScore *float64 `json:"minimumScore"`
+	// scene matches with a score below this value are tagged with LowScoreTag
+	LowScoreThreshold *float64 `json:"lowScoreThreshold"`
+	// ID of tag to tag low scoring scene matches with
+	LowScoreTag *string `json:"lowScoreTag"`
 }
 
 type FieldOptions struct {
@@ -2,14 +2,22 @@ package identify
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/plugin/hook"
+	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+	"github.com/stashapp/stash/pkg/txn"
 )
 
 type PerformerCreator interface {
+	models.PerformerGetter
 	models.PerformerCreator
 	UpdateImage(ctx context.Context, performerID int, image []byte) error
 }
@@ -55,3 +63,372 @@ func createMissingPerformer(ctx context.Context, endpoint string, w PerformerCre
 
 	return &newPerformer.ID, nil
 }
+
+// PerformerScraper is implemented by sources that are able to scrape
+// performers. Sources that do not implement this interface are skipped when
+// identifying performers.
+type PerformerScraper interface {
+	ScrapePerformers(ctx context.Context, performerID int) ([]*models.ScrapedPerformer, error)
+}
+
+type PerformerReaderUpdater interface {
+	models.PerformerUpdater
+	models.AliasLoader
+	models.URLLoader
+	models.TagIDLoader
+	models.StashIDLoader
+	HasImage(ctx context.Context, performerID int) (bool, error)
+}
+
+type PerformerIdentifier struct {
+	TxnManager             txn.Manager
+	PerformerReaderUpdater PerformerReaderUpdater
+	TagFinderCreator       models.TagFinderCreator
+
+	DefaultOptions   *MetadataOptions
+	Sources          []ScraperSource
+	PostHookExecutor PostHookExecutor
+}
+
+type performerScrapeResult struct {
+	result *models.ScrapedPerformer
+	source ScraperSource
+}
+
+func (t *PerformerIdentifier) Identify(ctx context.Context, p *models.Performer) error {
+	result, err := t.scrapePerformer(ctx, p)
+	var multipleMatchErr *MultipleMatchesFoundError
+	if err != nil {
+		if !errors.As(err, &multipleMatchErr) {
+			return err
+		}
+	}
+
+	if result == nil {
+		if multipleMatchErr != nil {
+			logger.Debugf("Identify skipped because multiple results returned for %s", p.Name)
+
+			// find if the performer should be tagged for multiple results
+			tagID, err := skipMultipleMatchTag(t.DefaultOptions, multipleMatchErr)
+			if err != nil {
+				return err
+			}
+			if tagID != nil {
+				return t.addTagToPerformer(ctx, p, *tagID)
+			}
+		} else {
+			logger.Debugf("Unable to identify %s", p.Name)
+		}
+		return nil
+	}
+
+	// results were found, modify the performer
+	if err := t.modifyPerformer(ctx, p, result); err != nil {
+		return fmt.Errorf("error modifying performer: %v", err)
+	}
+
+	return nil
+}
+
+func (t *PerformerIdentifier) scrapePerformer(ctx context.Context, p *models.Performer) (*performerScrapeResult, error) {
+	result, source, err := scrapeFirst(t.Sources, t.DefaultOptions, func(source ScraperSource) ([]*models.ScrapedPerformer, bool, error) {
+		s, ok := source.Scraper.(PerformerScraper)
+		if !ok {
+			// source does not support performers
+			return nil, false, nil
+		}
+
+		results, err := s.ScrapePerformers(ctx, p.ID)
+		return results, true, err
+	})
+	if err != nil || source == nil {
+		return nil, err
+	}
+
+	return &performerScrapeResult{
+		result: result,
+		source: *source,
+	}, nil
+}
+
+func (t *PerformerIdentifier) getPerformerPartial(ctx context.Context, p *models.Performer, result *performerScrapeResult) (*models.PerformerPartial, error) {
+	options, fieldOptions := sourceOptions(t.DefaultOptions, result.source)
+	scraped := result.result
+
+	partial := getPerformerPartial(p, scraped, result.source.RemoteSite, fieldOptions)
+
+	r := relationships{
+		tagCreator:   t.TagFinderCreator,
+		endpoint:     result.source.RemoteSite,
+		options:      options,
+		fieldOptions: fieldOptions,
+	}
+
+	tagIDs, err := r.tags(ctx, scraped.Tags, p.TagIDs.List(), false)
+	if err != nil {
+		return nil, err
+	}
+	if tagIDs != nil {
+		partial.TagIDs = &models.UpdateIDs{
+			IDs:  tagIDs,
+			Mode: models.RelationshipUpdateModeSet,
+		}
+	}
+
+	return &partial, nil
+}
+
+// getImage returns the scraped performer image if it should be set.
+func (t *PerformerIdentifier) getImage(ctx context.Context, p *models.Performer, result *performerScrapeResult) ([]byte, error) {
+	_, fieldOptions := sourceOptions(t.DefaultOptions, result.source)
+	if len(result.result.Images) == 0 {
+		return nil, nil
+	}
+
+	hasImage, err := t.PerformerReaderUpdater.HasImage(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !shouldSetSingleValueField(fieldOptions["image"], hasImage) {
+		return nil, nil
+	}
+
+	img, err := result.result.GetImage(ctx, nil)
+	if err != nil {
+		// don't fail the identify if the image could not be downloaded
+		logger.Errorf("Error getting image for performer %s: %v", p.Name, err)
+		return nil, nil
+	}
+
+	return img, nil
+}
+
+func (t *PerformerIdentifier) modifyPerformer(ctx context.Context, p *models.Performer, result *performerScrapeResult) error {
+	ctx = result.source.changeOrigin(ctx)
+
+	updated := false
+	if err := txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
+		// load performer relationships
+		if err := p.LoadAliases(ctx, t.PerformerReaderUpdater); err != nil {
+			return err
+		}
+		if err := p.LoadURLs(ctx, t.PerformerReaderUpdater); err != nil {
+			return err
+		}
+		if err := p.LoadTagIDs(ctx, t.PerformerReaderUpdater); err != nil {
+			return err
+		}
+		if err := p.LoadStashIDs(ctx, t.PerformerReaderUpdater); err != nil {
+			return err
+		}
+
+		partial, err := t.getPerformerPartial(ctx, p, result)
+		if err != nil {
+			return err
+		}
+
+		img, err := t.getImage(ctx, p, result)
+		if err != nil {
+			return err
+		}
+
+		// don't update anything if nothing was set
+		if performerPartialIsEmpty(*partial) && len(img) == 0 {
+			logger.Debugf("Nothing to set for %s", p.Name)
+			return nil
+		}
+
+		partial.UpdatedAt = models.NewOptionalTime(time.Now())
+		if _, err := t.PerformerReaderUpdater.UpdatePartial(ctx, p.ID, *partial); err != nil {
+			return fmt.Errorf("error updating performer: %w", err)
+		}
+
+		if len(img) > 0 {
+			if err := t.PerformerReaderUpdater.UpdateImage(ctx, p.ID, img); err != nil {
+				return fmt.Errorf("error updating performer image: %w", err)
+			}
+		}
+		updated = true
+
+		logger.Infof("Successfully identified %s using %s", p.Name, result.source.Name)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// fire post-update hooks
+	if updated && t.PostHookExecutor != nil {
+		t.PostHookExecutor.ExecutePostHooks(ctx, p.ID, hook.PerformerUpdatePost, nil, nil)
+	}
+
+	return nil
+}
+
+func (t *PerformerIdentifier) addTagToPerformer(ctx context.Context, p *models.Performer, tagID int) error {
+	return txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
+		if err := p.LoadTagIDs(ctx, t.PerformerReaderUpdater); err != nil {
+			return err
+		}
+
+		if sliceutil.Contains(p.TagIDs.List(), tagID) {
+			// skip if the performer was already tagged
+			return nil
+		}
+
+		partial := models.NewPerformerPartial()
+		partial.TagIDs = &models.UpdateIDs{
+			IDs:  []int{tagID},
+			Mode: models.RelationshipUpdateModeAdd,
+		}
+		if _, err := t.PerformerReaderUpdater.UpdatePartial(ctx, p.ID, partial); err != nil {
+			return err
+		}
+
+		ret, err := t.TagFinderCreator.Find(ctx, tagID)
+		if err != nil {
+			logger.Infof("Added tag id %d to skipped performer %s", tagID, p.Name)
+		} else {
+			logger.Infof("Added tag %s to skipped performer %s", ret.Name, p.Name)
+		}
+
+		return nil
+	})
+}
+
+func performerPartialIsEmpty(p models.PerformerPartial) bool {
+	return !p.Name.Set && !p.Disambiguation.Set && !p.Gender.Set && !p.Birthdate.Set &&
+		!p.DeathDate.Set && !p.Ethnicity.Set && !p.Country.Set && !p.EyeColor.Set &&
+		!p.HairColor.Set && !p.Height.Set && !p.Weight.Set && !p.Measurements.Set &&
+		!p.FakeTits.Set && !p.PenisLength.Set && !p.Circumcised.Set && !p.CareerLength.Set &&
+		!p.Tattoos.Set && !p.Piercings.Set && !p.Details.Set &&
+		p.Aliases == nil && p.URLs == nil && p.TagIDs == nil && p.StashIDs == nil
+}
+
+func getPerformerPartial(p *models.Performer, scraped *models.ScrapedPerformer, endpoint string, fieldOptions map[string]*FieldOptions) models.PerformerPartial {
+	partial := models.PerformerPartial{}
+
+	setString := func(field string, existing string, value *string) models.OptionalString {
+		if value != nil && existing != *value && shouldSetSingleValueField(fieldOptions[field], existing != "") {
+			return models.NewOptionalString(*value)
+		}
+		return models.OptionalString{}
+	}
+
+	setDate := func(field string, existing *models.Date, value *string) models.OptionalDate {
+		if value != nil && (existing == nil || existing.String() != *value) && shouldSetSingleValueField(fieldOptions[field], existing != nil) {
+			d, err := models.ParseDate(*value)
+			if err == nil {
+				return models.NewOptionalDate(d)
+			}
+		}
+		return models.OptionalDate{}
+	}
+
+	setInt := func(field string, existing *int, value *string) models.OptionalInt {
+		if value != nil && shouldSetSingleValueField(fieldOptions[field], existing != nil) {
+			v, err := strconv.Atoi(*value)
+			if err == nil && (existing == nil || *existing != v) {
+				return models.NewOptionalInt(v)
+			}
+		}
+		return models.OptionalInt{}
+	}
+
+	// name is always set, so it is only changed when overwriting
+	partial.Name = setString("name", p.Name, scraped.Name)
+	partial.Disambiguation = setString("disambiguation", p.Disambiguation, scraped.Disambiguation)
+	partial.Ethnicity = setString("ethnicity", p.Ethnicity, scraped.Ethnicity)
+	partial.Country = setString("country", p.Country, scraped.Country)
+	partial.EyeColor = setString("eye_color", p.EyeColor, scraped.EyeColor)
+	partial.HairColor = setString("hair_color", p.HairColor, scraped.HairColor)
+	partial.Measurements = setString("measurements", p.Measurements, scraped.Measurements)
+	partial.FakeTits = setString("fake_tits", p.FakeTits, scraped.FakeTits)
+	partial.CareerLength = setString("career_length", p.CareerLength, scraped.CareerLength)
+	partial.Tattoos = setString("tattoos", p.Tattoos, scraped.Tattoos)
+	partial.Piercings = setString("piercings", p.Piercings, scraped.Piercings)
+	partial.Details = setString("details", p.Details, scraped.Details)
+
+	partial.Birthdate = setDate("birthdate", p.Birthdate, scraped.Birthdate)
+	partial.DeathDate = setDate("death_date", p.DeathDate, scraped.DeathDate)
+
+	partial.Height = setInt("height", p.Height, scraped.Height)
+	partial.Weight = setInt("weight", p.Weight, scraped.Weight)
+
+	if scraped.Gender != nil && shouldSetSingleValueField(fieldOptions["gender"], p.Gender != nil) {
+		v := models.GenderEnum(*scraped.Gender)
+		if v.IsValid() && (p.Gender == nil || *p.Gender != v) {
+			partial.Gender = models.NewOptionalString(v.String())
+		}
+	}
+	if scraped.Circumcised != nil && shouldSetSingleValueField(fieldOptions["circumcised"], p.Circumcised != nil) {
+		v := models.CircumisedEnum(*scraped.Circumcised)
+		if v.IsValid() && (p.Circumcised == nil || *p.Circumcised != v) {
+			partial.Circumcised = models.NewOptionalString(v.String())
+		}
+	}
+	if scraped.PenisLength != nil && shouldSetSingleValueField(fieldOptions["penis_length"], p.PenisLength != nil) {
+		v, err := strconv.ParseFloat(*scraped.PenisLength, 64)
+		if err == nil && (p.PenisLength == nil || *p.PenisLength != v) {
+			partial.PenisLength = models.NewOptionalFloat64(v)
+		}
+	}
+
+	if scraped.Aliases != nil {
+		aliases := stringslice.FromString(*scraped.Aliases, ",")
+		if v := stringsToSet(fieldOptions, "aliases", p.Aliases.List(), aliases); v != nil {
+			partial.Aliases = &models.UpdateStrings{
+				Values: v,
+				Mode:   models.RelationshipUpdateModeSet,
+			}
+		}
+	}
+
+	if v := stringsToSet(fieldOptions, "url", p.URLs.List(), scrapedPerformerURLs(scraped)); v != nil {
+		partial.URLs = &models.UpdateStrings{
+			Values: v,
+			Mode:   models.RelationshipUpdateModeSet,
+		}
+	}
+
+	if scraped.RemoteSiteID != nil && endpoint != "" && shouldSetSingleValueField(fieldOptions["stash_ids"], false) {
+		stashID := models.StashID{
+			Endpoint: endpoint,
+			StashID:  *scraped.RemoteSiteID,
+		}
+
+		originalStashIDs := p.StashIDs.List()
+		var stashIDs []models.StashID
+		if getFieldStrategy(fieldOptions["stash_ids"]) == FieldStrategyMerge {
+			stashIDs = append(stashIDs, originalStashIDs...)
+		}
+		stashIDs = setStashID(stashIDs, stashID)
+
+		if !sliceutil.SliceSame(originalStashIDs, stashIDs) {
+			partial.StashIDs = &models.UpdateStashIDs{
+				StashIDs: stashIDs,
+				Mode:     models.RelationshipUpdateModeSet,
+			}
+		}
+	}
+
+	return partial
+}
+
+// scrapedPerformerURLs returns the URLs of the scraped performer, including
+// the deprecated URL fields if no URLs were scraped.
+func scrapedPerformerURLs(p *models.ScrapedPerformer) []string {
+	if len(p.URLs) > 0 {
+		return p.URLs
+	}
+
+	var urls []string
+	for _, u := range []*string{p.URL, p.Twitter, p.Instagram} {
+		if u != nil {
+			urls = append(urls, *u)
+		}
+	}
+
+	return urls
+}
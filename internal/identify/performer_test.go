@@ -225,3 +225,140 @@ func Test_createMissingPerformer(t *testing.T) {
 		})
 	}
 }
+
+func Test_getPerformerPartial(t *testing.T) {
+	const endpoint = "endpoint"
+
+	var (
+		originalName    = "originalName"
+		originalCountry = "originalCountry"
+		originalURL     = "originalURL"
+		originalStashID = models.StashID{Endpoint: endpoint, StashID: "originalStashID"}
+	)
+
+	var (
+		scrapedName         = "scrapedName"
+		scrapedCountry      = "scrapedCountry"
+		scrapedHeight       = "180"
+		scrapedURL          = "scrapedURL"
+		scrapedRemoteSiteID = "scrapedStashID"
+	)
+
+	originalPerformer := &models.Performer{
+		Name:     originalName,
+		Country:  originalCountry,
+		Aliases:  models.NewRelatedStrings([]string{}),
+		URLs:     models.NewRelatedStrings([]string{originalURL}),
+		StashIDs: models.NewRelatedStashIDs([]models.StashID{originalStashID}),
+	}
+
+	emptyPerformer := &models.Performer{
+		Name:     originalName,
+		Aliases:  models.NewRelatedStrings([]string{}),
+		URLs:     models.NewRelatedStrings([]string{}),
+		StashIDs: models.NewRelatedStashIDs([]models.StashID{}),
+	}
+
+	scrapedPerformer := &models.ScrapedPerformer{
+		Name:         &scrapedName,
+		Country:      &scrapedCountry,
+		Height:       &scrapedHeight,
+		URLs:         []string{scrapedURL},
+		RemoteSiteID: &scrapedRemoteSiteID,
+	}
+
+	scrapedStashID := models.StashID{Endpoint: endpoint, StashID: scrapedRemoteSiteID}
+
+	makeFieldOptions := func(input *FieldOptions) map[string]*FieldOptions {
+		return map[string]*FieldOptions{
+			"name":      input,
+			"country":   input,
+			"height":    input,
+			"url":       input,
+			"stash_ids": input,
+		}
+	}
+
+	overwriteAll := makeFieldOptions(&FieldOptions{
+		Strategy: FieldStrategyOverwrite,
+	})
+	ignoreAll := makeFieldOptions(&FieldOptions{
+		Strategy: FieldStrategyIgnore,
+	})
+	mergeAll := makeFieldOptions(&FieldOptions{
+		Strategy: FieldStrategyMerge,
+	})
+
+	tests := []struct {
+		name         string
+		performer    *models.Performer
+		fieldOptions map[string]*FieldOptions
+		want         models.PerformerPartial
+	}{
+		{
+			"overwrite",
+			originalPerformer,
+			overwriteAll,
+			models.PerformerPartial{
+				Name:    models.NewOptionalString(scrapedName),
+				Country: models.NewOptionalString(scrapedCountry),
+				Height:  models.NewOptionalInt(180),
+				URLs: &models.UpdateStrings{
+					Values: []string{scrapedURL},
+					Mode:   models.RelationshipUpdateModeSet,
+				},
+				StashIDs: &models.UpdateStashIDs{
+					StashIDs: []models.StashID{scrapedStashID},
+					Mode:     models.RelationshipUpdateModeSet,
+				},
+			},
+		},
+		{
+			"ignore",
+			originalPerformer,
+			ignoreAll,
+			models.PerformerPartial{},
+		},
+		{
+			"merge existing",
+			originalPerformer,
+			mergeAll,
+			models.PerformerPartial{
+				Height: models.NewOptionalInt(180),
+				URLs: &models.UpdateStrings{
+					Values: []string{originalURL, scrapedURL},
+					Mode:   models.RelationshipUpdateModeSet,
+				},
+				// stash id for the same endpoint is replaced
+				StashIDs: &models.UpdateStashIDs{
+					StashIDs: []models.StashID{scrapedStashID},
+					Mode:     models.RelationshipUpdateModeSet,
+				},
+			},
+		},
+		{
+			"merge empty",
+			emptyPerformer,
+			mergeAll,
+			models.PerformerPartial{
+				Country: models.NewOptionalString(scrapedCountry),
+				Height:  models.NewOptionalInt(180),
+				URLs: &models.UpdateStrings{
+					Values: []string{scrapedURL},
+					Mode:   models.RelationshipUpdateModeSet,
+				},
+				StashIDs: &models.UpdateStashIDs{
+					StashIDs: []models.StashID{scrapedStashID},
+					Mode:     models.RelationshipUpdateModeSet,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getPerformerPartial(tt.performer, scrapedPerformer, endpoint, tt.fieldOptions); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getPerformerPartial() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package identify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+// ProposalCreator stores the scene matches found by dry runs.
+type ProposalCreator interface {
+	Create(ctx context.Context, newProposal *models.IdentifyProposal) error
+}
+
+// errDryRun is used to roll back the transaction used to find the changes
+// a match would make.
+var errDryRun = errors.New("dry run")
+
+type proposalSource struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	RemoteSite string           `json:"remote_site"`
+	Options    *MetadataOptions `json:"options"`
+}
+
+// proposalData is the information needed to apply a proposal.
+type proposalData struct {
+	Source         proposalSource        `json:"source"`
+	DefaultOptions *MetadataOptions      `json:"default_options"`
+	Result         *scraper.ScrapedScene `json:"result"`
+
+	// the following are only set when results from multiple sources are merged
+
+	StudioEndpoint *string `json:"studio_endpoint,omitempty"`
+	// endpoint of the source of each scraped performer
+	PerformerEndpoints []string            `json:"performer_endpoints,omitempty"`
+	StashIDs           []models.StashID    `json:"stash_ids,omitempty"`
+	Provenance         map[string][]string `json:"provenance,omitempty"`
+}
+
+func newProposalData(result *scrapeResult, defaultOptions *MetadataOptions) proposalData {
+	ret := proposalData{
+		Source: proposalSource{
+			ID:         result.source.ID,
+			Name:       result.source.Name,
+			RemoteSite: result.source.RemoteSite,
+			Options:    result.source.Options,
+		},
+		DefaultOptions: defaultOptions,
+		Result:         result.result,
+		StashIDs:       result.stashIDs,
+		Provenance:     result.provenance,
+	}
+
+	if result.studioSource != nil {
+		ret.StudioEndpoint = &result.studioSource.RemoteSite
+	}
+
+	if result.performerSources != nil {
+		for _, p := range result.result.Performers {
+			ret.PerformerEndpoints = append(ret.PerformerEndpoints, result.performerEndpoint(p))
+		}
+	}
+
+	return ret
+}
+
+func (d proposalData) scrapeResult() *scrapeResult {
+	ret := &scrapeResult{
+		result: d.Result,
+		source: ScraperSource{
+			ID:         d.Source.ID,
+			Name:       d.Source.Name,
+			RemoteSite: d.Source.RemoteSite,
+			Options:    d.Source.Options,
+		},
+		stashIDs:   d.StashIDs,
+		provenance: d.Provenance,
+	}
+
+	if d.StudioEndpoint != nil {
+		ret.studioSource = &ScraperSource{RemoteSite: *d.StudioEndpoint}
+	}
+
+	if len(d.PerformerEndpoints) == len(d.Result.Performers) && len(d.PerformerEndpoints) > 0 {
+		ret.performerSources = make(map[*models.ScrapedPerformer]ScraperSource)
+		for i, p := range d.Result.Performers {
+			ret.performerSources[p] = ScraperSource{RemoteSite: d.PerformerEndpoints[i]}
+		}
+	}
+
+	return ret
+}
+
+// proposeScene stores the changes that the result would make to the scene
+// as a pending proposal.
+func (t *SceneIdentifier) proposeScene(ctx context.Context, s *models.Scene, result *scrapeResult) error {
+	diffs, err := t.getSceneDiffs(ctx, s, result)
+	if err != nil {
+		return err
+	}
+
+	if len(diffs) == 0 {
+		logger.Debugf("Nothing to propose for %s", s.Path)
+		return nil
+	}
+
+	data, err := json.Marshal(newProposalData(result, t.DefaultOptions))
+	if err != nil {
+		return fmt.Errorf("encoding proposal: %w", err)
+	}
+
+	confidence := result.confidence
+	now := time.Now()
+	proposal := &models.IdentifyProposal{
+		SceneID:    s.ID,
+		SourceID:   result.source.ID,
+		SourceName: result.source.Name,
+		Confidence: &confidence,
+		Diffs:      diffs,
+		Data:       string(data),
+		Status:     models.IdentifyProposalStatusPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
+		return t.ProposalCreator.Create(ctx, proposal)
+	}); err != nil {
+		return err
+	}
+
+	logger.Infof("Proposed %d changes for %s using %s", len(diffs), s.Path, result.source.Name)
+	return nil
+}
+
+// getSceneDiffs returns the changes the result would make to the scene.
+// The scene is updated in a transaction that is rolled back, so that the
+// changes are found using the same field strategies as when applying them.
+func (t *SceneIdentifier) getSceneDiffs(ctx context.Context, s *models.Scene, result *scrapeResult) ([]*models.IdentifyFieldDiff, error) {
+	var ret []*models.IdentifyFieldDiff
+
+	err := txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
+		if err := t.loadSceneRelationships(ctx, s); err != nil {
+			return err
+		}
+
+		updater, err := t.getSceneUpdater(ctx, s, result)
+		if err != nil {
+			return err
+		}
+
+		if updater.IsEmpty() {
+			return errDryRun
+		}
+
+		before, err := t.diffValues(ctx, s)
+		if err != nil {
+			return err
+		}
+
+		updated, err := updater.Update(ctx, t.SceneReaderUpdater)
+		if err != nil {
+			return fmt.Errorf("error updating scene: %w", err)
+		}
+		if err := t.loadSceneRelationships(ctx, updated); err != nil {
+			return err
+		}
+
+		after, err := t.diffValues(ctx, updated)
+		if err != nil {
+			return err
+		}
+
+		changes, err := before.Diff(after)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range changes {
+			ret = append(ret, &models.IdentifyFieldDiff{
+				Field:    c.Field,
+				OldValue: c.OldValue,
+				NewValue: c.NewValue,
+			})
+		}
+
+		if len(updater.CoverImage) > 0 {
+			ret = append(ret, &models.IdentifyFieldDiff{
+				Field:    "cover_image",
+				OldValue: "null",
+				NewValue: "true",
+			})
+		}
+
+		return errDryRun
+	})
+
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// diffValues returns the values of the scene fields that identify may set.
+// Related objects are referred to by name, since objects created during a
+// dry run do not exist once it is rolled back.
+func (t *SceneIdentifier) diffValues(ctx context.Context, s *models.Scene) (models.ChangeValues, error) {
+	values := s.ChangeValues()
+	ret := models.ChangeValues{}
+	for _, field := range []string{"title", "code", "details", "director", "date", "organized", "urls", "stash_ids"} {
+		ret[field] = values[field]
+	}
+
+	var studio *string
+	if s.StudioID != nil {
+		st, err := t.StudioReaderWriter.Find(ctx, *s.StudioID)
+		if err != nil {
+			return nil, err
+		}
+		if st != nil {
+			studio = &st.Name
+		}
+	}
+	ret["studio"] = studio
+
+	performers, err := t.PerformerCreator.FindMany(ctx, s.PerformerIDs.List())
+	if err != nil {
+		return nil, err
+	}
+	performerNames := []string{}
+	for _, p := range performers {
+		performerNames = append(performerNames, p.Name)
+	}
+	sort.Strings(performerNames)
+	ret["performers"] = performerNames
+
+	tags, err := t.TagFinderCreator.FindMany(ctx, s.TagIDs.List())
+	if err != nil {
+		return nil, err
+	}
+	tagNames := []string{}
+	for _, tag := range tags {
+		tagNames = append(tagNames, tag.Name)
+	}
+	sort.Strings(tagNames)
+	ret["tags"] = tagNames
+
+	return ret, nil
+}
+
+// ApplyProposal applies a proposal to the scene using the field strategies
+// that were in effect when the proposal was created. The changes are made
+// against the current state of the scene, so they may differ from the
+// changes shown in the proposal.
+func (t *SceneIdentifier) ApplyProposal(ctx context.Context, s *models.Scene, proposal *models.IdentifyProposal) error {
+	var data proposalData
+	if err := json.Unmarshal([]byte(proposal.Data), &data); err != nil {
+		return fmt.Errorf("decoding proposal: %w", err)
+	}
+
+	if data.Result == nil {
+		return errors.New("proposal has no result")
+	}
+
+	t.DefaultOptions = data.DefaultOptions
+
+	if err := t.modifyScene(ctx, s, data.scrapeResult()); err != nil {
+		return fmt.Errorf("error modifying scene: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package identify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// sourceOptions returns the metadata options and field options to use for
+// results from the provided source.
+func sourceOptions(defaultOptions *MetadataOptions, source ScraperSource) (MetadataOptions, map[string]*FieldOptions) {
+	allOptions := []MetadataOptions{}
+	if source.Options != nil {
+		allOptions = append(allOptions, *source.Options)
+	}
+	if defaultOptions != nil {
+		allOptions = append(allOptions, *defaultOptions)
+	}
+
+	t := &SceneIdentifier{
+		DefaultOptions: defaultOptions,
+	}
+
+	return t.getOptions(source), getFieldOptions(allOptions)
+}
+
+// scrapeFirst returns the first result from the first source that finds a
+// match. scrape returns false if the source does not support the object type.
+// A MultipleMatchesFoundError is returned if the source found multiple
+// matches and is configured to skip them.
+func scrapeFirst[T any](sources []ScraperSource, defaultOptions *MetadataOptions, scrape func(source ScraperSource) ([]T, bool, error)) (ret T, source *ScraperSource, err error) {
+	// iterate through the input sources
+	for i := range sources {
+		s := sources[i]
+
+		results, supported, err := scrape(s)
+		if !supported {
+			continue
+		}
+		if err != nil {
+			logger.Errorf("error scraping from %v: %v", s.Scraper, err)
+			continue
+		}
+
+		if len(results) > 0 {
+			options, _ := sourceOptions(defaultOptions, s)
+			if len(results) > 1 && utils.IsTrue(options.SkipMultipleMatches) {
+				return ret, nil, &MultipleMatchesFoundError{
+					Source: s,
+				}
+			}
+
+			// if results were found then return
+			return results[0], &s, nil
+		}
+	}
+
+	return ret, nil, nil
+}
+
+// relationships resolves scraped studios, performers and tags to the IDs of
+// existing objects, creating missing objects where configured.
+type relationships struct {
+	studioReaderWriter models.StudioReaderWriter
+	performerCreator   PerformerCreator
+	tagCreator         models.TagCreator
+
+	endpoint     string
+	options      MetadataOptions
+	fieldOptions map[string]*FieldOptions
+}
+
+// studio returns the ID of the studio to set, or nil if the studio should not
+// be changed.
+func (r relationships) studio(ctx context.Context, scraped *models.ScrapedStudio, existingID *int) (*int, error) {
+	studioOptions := r.fieldOptions["studio"]
+	if scraped == nil || !shouldSetSingleValueField(studioOptions, existingID != nil) {
+		return nil, nil
+	}
+
+	var studioID *int
+	if scraped.StoredID != nil {
+		id, err := strconv.Atoi(*scraped.StoredID)
+		if err != nil {
+			return nil, fmt.Errorf("error converting studio ID %s: %w", *scraped.StoredID, err)
+		}
+		studioID = &id
+	} else if studioOptions != nil && utils.IsTrue(studioOptions.CreateMissing) {
+		var err error
+		studioID, err = createMissingStudio(ctx, r.endpoint, r.studioReaderWriter, scraped)
+		if err != nil {
+			return nil, fmt.Errorf("error getting studio: %w", err)
+		}
+	}
+
+	// only return value if different to current
+	if studioID != nil && (existingID == nil || *existingID != *studioID) {
+		return studioID, nil
+	}
+
+	return nil, nil
+}
+
+// performers returns the performer IDs to set, or nil if the performers
+// should not be changed. skippedSingleName is true if a single name performer
+// was skipped.
+func (r relationships) performers(ctx context.Context, scraped []*models.ScrapedPerformer, originalIDs []int) (ids []int, skippedSingleName bool, err error) {
+	performerOptions := r.fieldOptions["performers"]
+	if len(scraped) == 0 || !shouldSetSingleValueField(performerOptions, false) {
+		return nil, false, nil
+	}
+
+	includeMalePerformers := true
+	if r.options.IncludeMalePerformers != nil {
+		includeMalePerformers = *r.options.IncludeMalePerformers
+	}
+
+	createMissing := performerOptions != nil && utils.IsTrue(performerOptions.CreateMissing)
+
+	var performerIDs []int
+	if getFieldStrategy(performerOptions) == FieldStrategyMerge {
+		performerIDs = originalIDs
+	}
+
+	for _, p := range scraped {
+		if !includeMalePerformers && p.Gender != nil && strings.EqualFold(*p.Gender, models.GenderEnumMale.String()) {
+			continue
+		}
+
+		performerID, err := getPerformerID(ctx, r.endpoint, r.performerCreator, p, createMissing, utils.IsTrue(r.options.SkipSingleNamePerformers))
+		if err != nil {
+			if errors.Is(err, ErrSkipSingleNamePerformer) {
+				skippedSingleName = true
+				continue
+			}
+			return nil, false, err
+		}
+
+		if performerID != nil {
+			performerIDs = sliceutil.AppendUnique(performerIDs, *performerID)
+		}
+	}
+
+	if sliceutil.SliceSame(originalIDs, performerIDs) {
+		return nil, skippedSingleName, nil
+	}
+
+	return performerIDs, skippedSingleName, nil
+}
+
+// tags returns the tag IDs to set, or nil if the tags should not be changed.
+// If skippedSingleName is true, the configured single name performer tag is
+// added.
+func (r relationships) tags(ctx context.Context, scraped []*models.ScrapedTag, originalIDs []int, skippedSingleName bool) ([]int, error) {
+	tagOptions := r.fieldOptions["tags"]
+	tagIDs := originalIDs
+
+	if len(scraped) > 0 && shouldSetSingleValueField(tagOptions, false) {
+		createMissing := tagOptions != nil && utils.IsTrue(tagOptions.CreateMissing)
+		if getFieldStrategy(tagOptions) == FieldStrategyOverwrite {
+			tagIDs = nil
+		}
+
+		for _, tag := range scraped {
+			if tag.StoredID != nil {
+				tagID, err := strconv.Atoi(*tag.StoredID)
+				if err != nil {
+					return nil, fmt.Errorf("error converting tag ID %s: %w", *tag.StoredID, err)
+				}
+
+				tagIDs = sliceutil.AppendUnique(tagIDs, tagID)
+			} else if createMissing {
+				newTag := models.NewTag()
+				newTag.Name = tag.Name
+
+				if err := r.tagCreator.Create(ctx, &newTag); err != nil {
+					return nil, fmt.Errorf("error creating tag: %w", err)
+				}
+
+				tagIDs = append(tagIDs, newTag.ID)
+			}
+		}
+	}
+
+	if skippedSingleName && r.options.SkipSingleNamePerformerTag != nil {
+		tagID, err := strconv.Atoi(*r.options.SkipSingleNamePerformerTag)
+		if err != nil {
+			return nil, fmt.Errorf("error converting tag ID %s: %w", *r.options.SkipSingleNamePerformerTag, err)
+		}
+
+		tagIDs = sliceutil.AppendUnique(tagIDs, tagID)
+	}
+
+	if sliceutil.SliceSame(originalIDs, tagIDs) {
+		return nil, nil
+	}
+
+	return tagIDs, nil
+}
+
+// stringsToSet returns the values to set for a multi-value string field, or
+// nil if the field should not be changed.
+func stringsToSet(fieldOptions map[string]*FieldOptions, field string, existing []string, scraped []string) []string {
+	if len(scraped) == 0 || !shouldSetSingleValueField(fieldOptions[field], false) {
+		return nil
+	}
+
+	switch getFieldStrategy(fieldOptions[field]) {
+	case FieldStrategyOverwrite:
+		// only overwrite if not equal
+		if len(sliceutil.Exclude(scraped, existing)) != 0 {
+			return scraped
+		}
+	case FieldStrategyMerge:
+		// if merge, add if not already present
+		values := sliceutil.AppendUniques(existing, scraped)
+
+		if len(values) != len(existing) {
+			return values
+		}
+	}
+
+	return nil
+}
+
+// skipMultipleMatchTag returns the ID of the tag to add to objects skipped
+// because of multiple matches, or nil if no tag is configured.
+func skipMultipleMatchTag(defaultOptions *MetadataOptions, err *MultipleMatchesFoundError) (*int, error) {
+	options, _ := sourceOptions(defaultOptions, err.Source)
+	if options.SkipMultipleMatchTag == nil || len(*options.SkipMultipleMatchTag) == 0 {
+		return nil, nil
+	}
+
+	tagID, convErr := strconv.Atoi(*options.SkipMultipleMatchTag)
+	if convErr != nil {
+		return nil, fmt.Errorf("error converting tag ID %s: %w", *options.SkipMultipleMatchTag, convErr)
+	}
+
+	return &tagID, nil
+}
@@ -44,7 +44,7 @@ func (g sceneRelationships) studio(ctx context.Context) (*int, error) {
 	createMissing := fieldStrategy != nil && utils.IsTrue(fieldStrategy.CreateMissing)
 
 	scraped := g.result.result.Studio
-	endpoint := g.result.source.RemoteSite
+	endpoint := g.result.studioEndpoint()
 
 	if scraped == nil || !shouldSetSingleValueField(fieldStrategy, existingID != nil) {
 		return nil, nil
@@ -83,8 +83,6 @@ func (g sceneRelationships) performers(ctx context.Context, ignoreMale bool) ([]
 		strategy = fieldStrategy.Strategy
 	}
 
-	endpoint := g.result.source.RemoteSite
-
 	var performerIDs []int
 	originalPerformerIDs := g.scene.PerformerIDs.List()
 
@@ -100,6 +98,7 @@ func (g sceneRelationships) performers(ctx context.Context, ignoreMale bool) ([]
 			continue
 		}
 
+		endpoint := g.result.performerEndpoint(p)
 		performerID, err := getPerformerID(ctx, endpoint, g.performerCreator, p, createMissing, g.skipSingleNamePerformers)
 		if err != nil {
 			if errors.Is(err, ErrSkipSingleNamePerformer) {
@@ -183,14 +182,12 @@ func (g sceneRelationships) tags(ctx context.Context) ([]int, error) {
 }
 
 func (g sceneRelationships) stashIDs(ctx context.Context) ([]models.StashID, error) {
-	remoteSiteID := g.result.result.RemoteSiteID
+	remoteSiteIDs := g.result.remoteSiteIDs()
 	fieldStrategy := g.fieldOptions["stash_ids"]
 	target := g.scene
 
-	endpoint := g.result.source.RemoteSite
-
 	// just check if ignored
-	if remoteSiteID == nil || endpoint == "" || !shouldSetSingleValueField(fieldStrategy, false) {
+	if len(remoteSiteIDs) == 0 || !shouldSetSingleValueField(fieldStrategy, false) {
 		return nil, nil
 	}
 
@@ -208,26 +205,10 @@ func (g sceneRelationships) stashIDs(ctx context.Context) ([]models.StashID, err
 		stashIDs = append(stashIDs, originalStashIDs...)
 	}
 
-	for i, stashID := range stashIDs {
-		if endpoint == stashID.Endpoint {
-			// if stashID is the same, then don't set
-			if stashID.StashID == *remoteSiteID {
-				return nil, nil
-			}
-
-			// replace the stash id and return
-			stashID.StashID = *remoteSiteID
-			stashIDs[i] = stashID
-			return stashIDs, nil
-		}
+	for _, remote := range remoteSiteIDs {
+		stashIDs = setStashID(stashIDs, remote)
 	}
 
-	// not found, create new entry
-	stashIDs = append(stashIDs, models.StashID{
-		StashID:  *remoteSiteID,
-		Endpoint: endpoint,
-	})
-
 	if sliceutil.SliceSame(originalStashIDs, stashIDs) {
 		return nil, nil
 	}
@@ -235,6 +216,19 @@ func (g sceneRelationships) stashIDs(ctx context.Context) ([]models.StashID, err
 	return stashIDs, nil
 }
 
+// setStashID replaces the stash id for the endpoint of v, or appends v if
+// there is no stash id for the endpoint.
+func setStashID(stashIDs []models.StashID, v models.StashID) []models.StashID {
+	for i, stashID := range stashIDs {
+		if v.Endpoint == stashID.Endpoint {
+			stashIDs[i] = v
+			return stashIDs
+		}
+	}
+
+	return append(stashIDs, v)
+}
+
 func (g sceneRelationships) cover(ctx context.Context) ([]byte, error) {
 	scraped := g.result.result.Image
 
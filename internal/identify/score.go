@@ -0,0 +1,282 @@
+package identify
+
+import (
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/corona10/goimagehash"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// Weights of the signals used to score scene matches. Signals that cannot be
+// compared for a scene and result are left out of the score.
+const (
+	fingerprintWeight = 0.35
+	phashWeight       = 0.2
+	durationWeight    = 0.15
+	titleWeight       = 0.15
+	performerWeight   = 0.15
+
+	// duration difference in seconds at which the duration score is 0
+	maxDurationDelta = 30
+	// phash distance at which the phash score is 0
+	maxPhashDistance = 12
+)
+
+// stash-box fingerprint algorithms
+const (
+	fingerprintAlgorithmMD5    = "MD5"
+	fingerprintAlgorithmOshash = "OSHASH"
+	fingerprintAlgorithmPhash  = "PHASH"
+)
+
+type matchScore struct {
+	total  float64
+	weight float64
+}
+
+func (s *matchScore) add(weight float64, value float64) {
+	s.total += weight * value
+	s.weight += weight
+}
+
+func (s matchScore) value() float64 {
+	if s.weight == 0 {
+		return 0
+	}
+
+	return s.total / s.weight
+}
+
+// scoreSceneMatch returns how likely it is that the scraped scene matches the
+// scene, from 0 to 1. The score combines the number of matching fingerprint
+// submissions, the phash distance, the duration difference, the title
+// similarity and the performer overlap. Scene files and performer IDs are
+// only used if they are loaded. Results that cannot be compared to the scene
+// score 0.
+func scoreSceneMatch(s *models.Scene, scraped *scraper.ScrapedScene) float64 {
+	var files []*models.VideoFile
+	if s.Files.Loaded() {
+		files = s.Files.List()
+	}
+
+	score := matchScore{}
+
+	if v, ok := fingerprintScore(files, scraped.Fingerprints); ok {
+		score.add(fingerprintWeight, v)
+	}
+	if v, ok := phashScore(files, scraped.Fingerprints); ok {
+		score.add(phashWeight, v)
+	}
+	if v, ok := durationScore(files, scraped); ok {
+		score.add(durationWeight, v)
+	}
+	if v, ok := titleScore(s, scraped.Title); ok {
+		score.add(titleWeight, v)
+	}
+	if v, ok := performerScore(s, scraped.Performers); ok {
+		score.add(performerWeight, v)
+	}
+
+	return score.value()
+}
+
+// fingerprintScore scores the number of submissions of the scraped md5 and
+// oshash fingerprints that match the scene files. Each matching submission
+// halves the remaining uncertainty.
+func fingerprintScore(files []*models.VideoFile, fingerprints []*models.StashBoxFingerprint) (float64, bool) {
+	hashes := make(map[string]bool)
+	for _, f := range files {
+		if v := f.Fingerprints.GetString(models.FingerprintTypeMD5); v != "" {
+			hashes[fingerprintAlgorithmMD5+v] = true
+		}
+		if v := f.Fingerprints.GetString(models.FingerprintTypeOshash); v != "" {
+			hashes[fingerprintAlgorithmOshash+v] = true
+		}
+	}
+
+	compared := false
+	submissions := 0
+	for _, fp := range fingerprints {
+		if fp.Algorithm != fingerprintAlgorithmMD5 && fp.Algorithm != fingerprintAlgorithmOshash {
+			continue
+		}
+
+		compared = true
+		if hashes[fp.Algorithm+fp.Hash] {
+			// fingerprints from sources without submission counts count once
+			submissions += max(fp.Submissions, 1)
+		}
+	}
+
+	if !compared || len(hashes) == 0 {
+		return 0, false
+	}
+
+	return 1 - math.Pow(0.5, float64(submissions)), true
+}
+
+// phashScore scores the smallest distance between the scene file phashes and
+// the scraped phash fingerprints.
+func phashScore(files []*models.VideoFile, fingerprints []*models.StashBoxFingerprint) (float64, bool) {
+	var phashes []*goimagehash.ImageHash
+	for _, f := range files {
+		if v := f.Fingerprints.GetInt64(models.FingerprintTypePhash); v != 0 {
+			phashes = append(phashes, goimagehash.NewImageHash(uint64(v), goimagehash.PHash))
+		}
+	}
+
+	if len(phashes) == 0 {
+		return 0, false
+	}
+
+	minDistance := -1
+	for _, fp := range fingerprints {
+		if fp.Algorithm != fingerprintAlgorithmPhash {
+			continue
+		}
+
+		v, err := utils.StringToPhash(fp.Hash)
+		if err != nil {
+			continue
+		}
+
+		scrapedHash := goimagehash.NewImageHash(uint64(v), goimagehash.PHash)
+		for _, h := range phashes {
+			d, err := h.Distance(scrapedHash)
+			if err == nil && (minDistance == -1 || d < minDistance) {
+				minDistance = d
+			}
+		}
+	}
+
+	if minDistance == -1 {
+		return 0, false
+	}
+
+	return math.Max(0, 1-float64(minDistance)/maxPhashDistance), true
+}
+
+// durationScore scores the difference between the scene duration and the
+// scraped duration. The duration of the scraped fingerprints is used if the
+// scraped scene has no duration.
+func durationScore(files []*models.VideoFile, scraped *scraper.ScrapedScene) (float64, bool) {
+	if len(files) == 0 || files[0].Duration == 0 {
+		return 0, false
+	}
+
+	scrapedDuration := 0
+	if scraped.Duration != nil {
+		scrapedDuration = *scraped.Duration
+	}
+	for _, fp := range scraped.Fingerprints {
+		if scrapedDuration != 0 {
+			break
+		}
+		scrapedDuration = fp.Duration
+	}
+
+	if scrapedDuration == 0 {
+		return 0, false
+	}
+
+	delta := math.Abs(files[0].Duration - float64(scrapedDuration))
+	return math.Max(0, 1-delta/maxDurationDelta), true
+}
+
+// titleScore scores the similarity of the words in the scene title and the
+// scraped title. The file name is used if the scene has no title.
+func titleScore(s *models.Scene, scraped *string) (float64, bool) {
+	if scraped == nil {
+		return 0, false
+	}
+
+	title := s.Title
+	if title == "" && s.Path != "" {
+		base := filepath.Base(s.Path)
+		title = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	sceneWords := titleWords(title)
+	scrapedWords := titleWords(*scraped)
+	if len(sceneWords) == 0 || len(scrapedWords) == 0 {
+		return 0, false
+	}
+
+	common := 0
+	for w := range scrapedWords {
+		if sceneWords[w] {
+			common++
+		}
+	}
+
+	return 2 * float64(common) / float64(len(sceneWords)+len(scrapedWords)), true
+}
+
+func titleWords(s string) map[string]bool {
+	ret := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		ret[w] = true
+	}
+
+	return ret
+}
+
+// performerScore scores the portion of the scraped performers that are
+// already set on the scene.
+func performerScore(s *models.Scene, scraped []*models.ScrapedPerformer) (float64, bool) {
+	if !s.PerformerIDs.Loaded() || len(s.PerformerIDs.List()) == 0 || len(scraped) == 0 {
+		return 0, false
+	}
+
+	existing := make(map[string]bool)
+	for _, id := range s.PerformerIDs.List() {
+		existing[strconv.Itoa(id)] = true
+	}
+
+	matched := 0
+	for _, p := range scraped {
+		if p.StoredID != nil && existing[*p.StoredID] {
+			matched++
+		}
+	}
+
+	return float64(matched) / float64(len(scraped)), true
+}
+
+type scoredScene struct {
+	result *scraper.ScrapedScene
+	score  float64
+}
+
+// scoreResults scores the results against the scene and returns the results
+// scoring at least minimum, ordered by descending score.
+func scoreResults(s *models.Scene, results []*scraper.ScrapedScene, minimum *float64) []scoredScene {
+	var ret []scoredScene
+	for _, r := range results {
+		score := scoreSceneMatch(s, r)
+		if minimum != nil && score < *minimum {
+			continue
+		}
+
+		ret = append(ret, scoredScene{
+			result: r,
+			score:  score,
+		})
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].score > ret[j].score
+	})
+
+	return ret
+}
@@ -0,0 +1,165 @@
+package identify
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_scoreSceneMatch(t *testing.T) {
+	const (
+		md5    = "md5"
+		oshash = "oshash"
+		phash  = int64(0x0f0f0f0f0f0f0f0f)
+
+		// phash differing by 6 bits
+		nearPhash = int64(0x0f0f0f0f0f0f0ff3)
+
+		duration = 600
+	)
+
+	newScene := func(title string, performerIDs []int) *models.Scene {
+		f := &models.VideoFile{
+			BaseFile: &models.BaseFile{
+				Fingerprints: models.Fingerprints{
+					{Type: models.FingerprintTypeMD5, Fingerprint: md5},
+					{Type: models.FingerprintTypeOshash, Fingerprint: oshash},
+					{Type: models.FingerprintTypePhash, Fingerprint: phash},
+				},
+			},
+			Duration: duration,
+		}
+
+		return &models.Scene{
+			Title:        title,
+			Path:         "/path/to/file.mp4",
+			Files:        models.NewRelatedVideoFiles([]*models.VideoFile{f}),
+			PerformerIDs: models.NewRelatedIDs(performerIDs),
+		}
+	}
+
+	var (
+		title      = "A Scene Title"
+		otherTitle = "something else"
+		storedID1  = "1"
+		storedID2  = "2"
+
+		scrapedDuration = duration + 15
+	)
+
+	tests := []struct {
+		name    string
+		scene   *models.Scene
+		scraped *scraper.ScrapedScene
+		want    float64
+	}{
+		{
+			"nothing to compare",
+			&models.Scene{},
+			&scraper.ScrapedScene{},
+			0,
+		},
+		{
+			"title only",
+			newScene("a scene title", nil),
+			&scraper.ScrapedScene{
+				Title: &title,
+			},
+			1,
+		},
+		{
+			"title from filename",
+			newScene("", nil),
+			&scraper.ScrapedScene{
+				Title: &otherTitle,
+			},
+			0,
+		},
+		{
+			"fingerprint submissions",
+			newScene("", nil),
+			&scraper.ScrapedScene{
+				Fingerprints: []*models.StashBoxFingerprint{
+					{Algorithm: fingerprintAlgorithmMD5, Hash: md5, Submissions: 1},
+					{Algorithm: fingerprintAlgorithmOshash, Hash: oshash, Submissions: 2},
+					{Algorithm: fingerprintAlgorithmOshash, Hash: "other", Submissions: 5},
+				},
+			},
+			0.875,
+		},
+		{
+			"phash distance and duration",
+			newScene("", nil),
+			&scraper.ScrapedScene{
+				Duration: &scrapedDuration,
+				Fingerprints: []*models.StashBoxFingerprint{
+					{Algorithm: fingerprintAlgorithmPhash, Hash: utils.PhashToString(nearPhash), Duration: duration},
+				},
+			},
+			// (0.2 * 0.5 + 0.15 * 0.5) / 0.35
+			0.5,
+		},
+		{
+			"performer overlap",
+			newScene("", []int{1, 3}),
+			&scraper.ScrapedScene{
+				Performers: []*models.ScrapedPerformer{
+					{StoredID: &storedID1},
+					{StoredID: &storedID2},
+				},
+			},
+			0.5,
+		},
+		{
+			"combined",
+			newScene(title, []int{1}),
+			&scraper.ScrapedScene{
+				Title: &otherTitle,
+				Fingerprints: []*models.StashBoxFingerprint{
+					{Algorithm: fingerprintAlgorithmMD5, Hash: md5, Submissions: 1},
+				},
+				Performers: []*models.ScrapedPerformer{
+					{StoredID: &storedID1},
+				},
+			},
+			// (0.35 * 0.5 + 0.15 * 0 + 0.15 * 1) / 0.65
+			0.5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scoreSceneMatch(tt.scene, tt.scraped)
+			assert.InDelta(t, tt.want, got, 0.001)
+		})
+	}
+}
+
+func Test_scoreResults(t *testing.T) {
+	var (
+		title      = "title"
+		otherTitle = "other"
+		minimum    = 0.5
+	)
+
+	s := &models.Scene{
+		Title: title,
+	}
+
+	low := &scraper.ScrapedScene{Title: &otherTitle}
+	high := &scraper.ScrapedScene{Title: &title}
+
+	got := scoreResults(s, []*scraper.ScrapedScene{low, high}, nil)
+	if assert.Len(t, got, 2) {
+		assert.Same(t, high, got[0].result)
+		assert.Same(t, low, got[1].result)
+	}
+
+	got = scoreResults(s, []*scraper.ScrapedScene{low, high}, &minimum)
+	if assert.Len(t, got, 1) {
+		assert.Same(t, high, got[0].result)
+		assert.InDelta(t, 1, got[0].score, 0.001)
+	}
+}
@@ -0,0 +1,192 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/internal/autotag"
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+// default number of items of each type returned by a rule preview
+const autoTagRulePreviewLimit = 40
+
+type AutoTagRulePreviewInput struct {
+	// IDs of the rules to preview. Previews all enabled rules if empty.
+	RuleIds []string `json:"rule_ids"`
+	// Paths to preview, null for all files
+	Paths []string `json:"paths"`
+	// Maximum number of scenes, images and galleries to return for each rule
+	Limit *int `json:"limit"`
+}
+
+// AutoTagRulePreview lists the items that an auto-tag rule would change.
+type AutoTagRulePreview struct {
+	Rule         *models.AutoTagRule `json:"rule"`
+	SceneCount   int                 `json:"scene_count"`
+	ImageCount   int                 `json:"image_count"`
+	GalleryCount int                 `json:"gallery_count"`
+
+	// IDs of the first items that would be changed
+	SceneIDs   []int `json:"scene_ids"`
+	ImageIDs   []int `json:"image_ids"`
+	GalleryIDs []int `json:"gallery_ids"`
+}
+
+type autoTagRulePreviewer struct {
+	repository models.Repository
+	paths      []string
+	limit      int
+
+	rules    *autotag.RuleSet
+	previews map[int]*AutoTagRulePreview
+}
+
+// PreviewAutoTagRules returns the items that the rules would change if the
+// auto-tag task was run on the paths. Items that the rules match but would
+// not change are not included. Must be called in a read transaction.
+func PreviewAutoTagRules(ctx context.Context, r models.Repository, rules []*models.AutoTagRule, paths []string, limit *int) ([]*AutoTagRulePreview, error) {
+	ruleSet, err := autotag.NewRuleSet(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &autoTagRulePreviewer{
+		repository: r,
+		paths:      paths,
+		limit:      autoTagRulePreviewLimit,
+		rules:      ruleSet,
+		previews:   make(map[int]*AutoTagRulePreview),
+	}
+	if limit != nil {
+		p.limit = *limit
+	}
+
+	ret := make([]*AutoTagRulePreview, len(rules))
+	for i, rule := range rules {
+		ret[i] = &AutoTagRulePreview{
+			Rule:       rule,
+			SceneIDs:   []int{},
+			ImageIDs:   []int{},
+			GalleryIDs: []int{},
+		}
+		p.previews[rule.ID] = ret[i]
+	}
+
+	if len(rules) == 0 {
+		return ret, nil
+	}
+
+	if err := p.previewScenes(ctx); err != nil {
+		return nil, err
+	}
+	if err := p.previewImages(ctx); err != nil {
+		return nil, err
+	}
+	if err := p.previewGalleries(ctx); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (p *autoTagRulePreviewer) addIDs(ids []int, id int) []int {
+	if len(ids) < p.limit {
+		return append(ids, id)
+	}
+	return ids
+}
+
+// the same items are previewed as are tagged by the auto-tag task
+func (p *autoTagRulePreviewer) filesTask() *autoTagFilesTask {
+	return &autoTagFilesTask{paths: p.paths}
+}
+
+func (p *autoTagRulePreviewer) previewScenes(ctx context.Context) error {
+	r := p.repository
+	sceneFilter := p.filesTask().makeSceneFilter()
+
+	return scene.BatchProcess(ctx, r.Scene, sceneFilter, &models.FindFilterType{}, func(s *models.Scene) error {
+		if s.Path == "" {
+			return nil
+		}
+
+		rules, err := p.rules.PreviewScene(ctx, s, r.Scene, r.File)
+		if err != nil {
+			return fmt.Errorf("previewing rules for scene %s: %w", s.DisplayName(), err)
+		}
+
+		for _, rule := range rules {
+			preview := p.previews[rule.ID]
+			preview.SceneCount++
+			preview.SceneIDs = p.addIDs(preview.SceneIDs, s.ID)
+		}
+		return nil
+	})
+}
+
+func (p *autoTagRulePreviewer) previewImages(ctx context.Context) error {
+	const batchSize = 1000
+	r := p.repository
+	imageFilter := p.filesTask().makeImageFilter()
+	findFilter := models.BatchFindFilter(batchSize)
+
+	for more := true; more; {
+		images, err := image.Query(ctx, r.Image, imageFilter, findFilter)
+		if err != nil {
+			return fmt.Errorf("querying images: %w", err)
+		}
+
+		for _, i := range images {
+			rules, err := p.rules.PreviewImage(ctx, i, r.Image, r.File)
+			if err != nil {
+				return fmt.Errorf("previewing rules for image %s: %w", i.DisplayName(), err)
+			}
+
+			for _, rule := range rules {
+				preview := p.previews[rule.ID]
+				preview.ImageCount++
+				preview.ImageIDs = p.addIDs(preview.ImageIDs, i.ID)
+			}
+		}
+
+		more = len(images) == batchSize
+		*findFilter.Page++
+	}
+
+	return nil
+}
+
+func (p *autoTagRulePreviewer) previewGalleries(ctx context.Context) error {
+	const batchSize = 1000
+	r := p.repository
+	galleryFilter := p.filesTask().makeGalleryFilter()
+	findFilter := models.BatchFindFilter(batchSize)
+
+	for more := true; more; {
+		galleries, _, err := r.Gallery.Query(ctx, galleryFilter, findFilter)
+		if err != nil {
+			return fmt.Errorf("querying galleries: %w", err)
+		}
+
+		for _, g := range galleries {
+			rules, err := p.rules.PreviewGallery(ctx, g, r.Gallery)
+			if err != nil {
+				return fmt.Errorf("previewing rules for gallery %s: %w", g.DisplayName(), err)
+			}
+
+			for _, rule := range rules {
+				preview := p.previews[rule.ID]
+				preview.GalleryCount++
+				preview.GalleryIDs = p.addIDs(preview.GalleryIDs, g.ID)
+			}
+		}
+
+		more = len(galleries) == batchSize
+		*findFilter.Page++
+	}
+
+	return nil
+}
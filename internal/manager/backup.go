@@ -0,0 +1,663 @@
+package manager
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/sqlite"
+)
+
+const (
+	// interval at which the scheduler checks whether a backup is due
+	databaseBackupsInterval = 15 * time.Minute
+
+	backupTimeFormat = "20060102_150405"
+	backupArchiveExt = ".zip"
+
+	// name of the database file within backup archives
+	backupArchiveDatabase = "database.sqlite"
+	// directory of the blobs within backup archives
+	backupArchiveBlobs = "blobs"
+
+	scheduledBackupKind  = "scheduled"
+	preRestoreBackupKind = "pre_restore"
+)
+
+type DatabaseBackupsConfig interface {
+	GetBackupDirectoryPathOrDefault() string
+	GetBackupInterval() time.Duration
+	GetBackupKeepDaily() int
+	GetBackupKeepWeekly() int
+	GetBackupCompress() bool
+	GetBackupIncludeBlobs() bool
+	GetBlobsStorage() config.BlobsStorageType
+	GetBlobsPath() string
+}
+
+// DatabaseBackup is a backup of the database in the backup directory.
+type DatabaseBackup struct {
+	Name          string
+	Size          int64
+	CreatedAt     time.Time
+	SchemaVersion int
+	// Scheduled is true if the backup was created by the backup schedule.
+	// Only scheduled backups are removed by the retention rules.
+	Scheduled bool
+	// Archive is true if the backup is a zip archive, which may also contain
+	// the blobs directory.
+	Archive bool
+
+	path string
+}
+
+// backupNameRE returns a regular expression matching the names of backups of
+// the database file named dbBase.
+func backupNameRE(dbBase string) *regexp.Regexp {
+	return regexp.MustCompile(`^` + regexp.QuoteMeta(dbBase) + `\.(?:(` + scheduledBackupKind + `|` + preRestoreBackupKind + `)\.)?(\d+)\.(\d{8}_\d{6})(` + regexp.QuoteMeta(backupArchiveExt) + `)?$`)
+}
+
+// parseBackupName returns the backup with the provided file name, or nil if
+// the name is not that of a backup of the database file named dbBase.
+func parseBackupName(re *regexp.Regexp, name string) *DatabaseBackup {
+	m := re.FindStringSubmatch(name)
+	if m == nil {
+		return nil
+	}
+
+	version, err := strconv.Atoi(m[2])
+	if err != nil {
+		return nil
+	}
+
+	createdAt, err := time.ParseInLocation(backupTimeFormat, m[3], time.Local)
+	if err != nil {
+		return nil
+	}
+
+	return &DatabaseBackup{
+		Name:          name,
+		CreatedAt:     createdAt,
+		SchemaVersion: version,
+		Scheduled:     m[1] == scheduledBackupKind,
+		Archive:       m[4] != "",
+	}
+}
+
+// scheduledBackupsToRemove returns the scheduled backups that are not kept by
+// the retention rules. The newest backup of each of the last keepDaily days
+// and of each of the last keepWeekly weeks that have backups is kept. The
+// newest backup is always kept. Backups that were not scheduled are never
+// removed.
+func scheduledBackupsToRemove(backups []*DatabaseBackup, keepDaily int, keepWeekly int) []*DatabaseBackup {
+	var scheduled []*DatabaseBackup
+	for _, b := range backups {
+		if b.Scheduled {
+			scheduled = append(scheduled, b)
+		}
+	}
+
+	sort.SliceStable(scheduled, func(i, j int) bool {
+		return scheduled[i].CreatedAt.After(scheduled[j].CreatedAt)
+	})
+
+	days := make(map[string]bool)
+	weeks := make(map[string]bool)
+
+	var ret []*DatabaseBackup
+	for i, b := range scheduled {
+		keep := i == 0
+
+		day := b.CreatedAt.Format("2006-01-02")
+		if !days[day] && len(days) < keepDaily {
+			days[day] = true
+			keep = true
+		}
+
+		year, week := b.CreatedAt.ISOWeek()
+		weekKey := fmt.Sprintf("%d-%d", year, week)
+		if !weeks[weekKey] && len(weeks) < keepWeekly {
+			weeks[weekKey] = true
+			keep = true
+		}
+
+		if !keep {
+			ret = append(ret, b)
+		}
+	}
+
+	return ret
+}
+
+// DatabaseBackups creates scheduled backups of the database, removes old
+// scheduled backups according to the retention rules, and restores the
+// database from backups.
+type DatabaseBackups struct {
+	database *sqlite.Database
+	config   DatabaseBackupsConfig
+
+	trigger   chan struct{}
+	startOnce sync.Once
+	// ensures that only one backup or restore is performed at a time
+	mutex sync.Mutex
+}
+
+func NewDatabaseBackups(database *sqlite.Database, config DatabaseBackupsConfig) *DatabaseBackups {
+	return &DatabaseBackups{
+		database: database,
+		config:   config,
+		trigger:  make(chan struct{}, 1),
+	}
+}
+
+// Start starts creating scheduled backups in the background, until ctx is
+// cancelled.
+func (b *DatabaseBackups) Start(ctx context.Context) {
+	b.startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(databaseBackupsInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				case <-b.trigger:
+				}
+
+				b.runScheduled()
+			}
+		}()
+	})
+}
+
+// Trigger causes a scheduled backup to be created if one is due.
+func (b *DatabaseBackups) Trigger() {
+	select {
+	case b.trigger <- struct{}{}:
+	default:
+		// already triggered
+	}
+}
+
+func (b *DatabaseBackups) runScheduled() {
+	interval := b.config.GetBackupInterval()
+	if interval <= 0 {
+		return
+	}
+
+	// database is not ready until setup and migrations are complete
+	if err := b.database.Ready(); err != nil {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	backups, err := b.list()
+	if err != nil {
+		logger.Errorf("Error listing database backups: %v", err)
+		return
+	}
+
+	var last time.Time
+	for _, backup := range backups {
+		if backup.Scheduled && backup.CreatedAt.After(last) {
+			last = backup.CreatedAt
+		}
+	}
+
+	if time.Since(last) < interval {
+		return
+	}
+
+	created, err := b.create(scheduledBackupKind, b.config.GetBackupCompress(), b.config.GetBackupIncludeBlobs())
+	if err != nil {
+		logger.Errorf("Error creating scheduled database backup: %v", err)
+		return
+	}
+	logger.Infof("Created scheduled database backup: %s", created)
+
+	// list again to include the new backup
+	backups, err = b.list()
+	if err != nil {
+		logger.Errorf("Error listing database backups: %v", err)
+		return
+	}
+
+	for _, backup := range scheduledBackupsToRemove(backups, b.config.GetBackupKeepDaily(), b.config.GetBackupKeepWeekly()) {
+		logger.Infof("Removing old database backup: %s", backup.Name)
+		if err := os.Remove(backup.path); err != nil {
+			logger.Errorf("Error removing database backup %s: %v", backup.Name, err)
+		}
+	}
+}
+
+// List returns the backups of the database in the backup directory, newest
+// first.
+func (b *DatabaseBackups) List() ([]*DatabaseBackup, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.list()
+}
+
+func (b *DatabaseBackups) list() ([]*DatabaseBackup, error) {
+	dir := b.config.GetBackupDirectoryPathOrDefault()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	re := backupNameRE(filepath.Base(b.database.DatabasePath()))
+
+	var ret []*DatabaseBackup
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		backup := parseBackupName(re, e.Name())
+		if backup == nil {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			// removed since reading the directory
+			continue
+		}
+
+		backup.Size = info.Size()
+		backup.path = filepath.Join(dir, e.Name())
+		ret = append(ret, backup)
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].CreatedAt.After(ret[j].CreatedAt)
+	})
+
+	return ret, nil
+}
+
+// filesystemBlobsPath returns the blobs directory if blobs are stored on the
+// filesystem, or an empty string otherwise.
+func (b *DatabaseBackups) filesystemBlobsPath() string {
+	if b.config.GetBlobsStorage() != config.BlobStorageTypeFilesystem {
+		return ""
+	}
+	return b.config.GetBlobsPath()
+}
+
+// create creates a backup of the database in the backup directory and returns
+// its name. The backup is checked for integrity before it is kept.
+func (b *DatabaseBackups) create(kind string, compress bool, includeBlobs bool) (string, error) {
+	dir := b.config.GetBackupDirectoryPathOrDefault()
+	if err := fsutil.EnsureDir(dir); err != nil {
+		return "", fmt.Errorf("could not create backup directory %v: %w", dir, err)
+	}
+
+	f, err := os.CreateTemp(dir, ".backup*.sqlite")
+	if err != nil {
+		return "", err
+	}
+	tmpPath := f.Name()
+	f.Close()
+	defer os.Remove(tmpPath)
+
+	if err := b.database.Backup(tmpPath); err != nil {
+		return "", err
+	}
+
+	if err := sqlite.CheckIntegrity(tmpPath); err != nil {
+		return "", fmt.Errorf("verifying backup: %w", err)
+	}
+
+	blobsPath := ""
+	if includeBlobs {
+		blobsPath = b.filesystemBlobsPath()
+	}
+
+	name := fmt.Sprintf("%s.%s.%d.%s", filepath.Base(b.database.DatabasePath()), kind, b.database.Version(), time.Now().Format(backupTimeFormat))
+	if !compress && blobsPath == "" {
+		if err := os.Rename(tmpPath, filepath.Join(dir, name)); err != nil {
+			return "", err
+		}
+		return name, nil
+	}
+
+	name += backupArchiveExt
+	if err := writeBackupArchive(filepath.Join(dir, name), tmpPath, blobsPath, compress); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// writeBackupArchive writes a zip archive containing the database file and,
+// if blobsPath is not empty, the blobs directory. The archive is written to a
+// temporary file first so that incomplete archives are not left behind.
+func writeBackupArchive(archivePath string, dbPath string, blobsPath string, compress bool) (err error) {
+	tmpPath := archivePath + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	w := zip.NewWriter(f)
+
+	method := zip.Store
+	if compress {
+		method = zip.Deflate
+	}
+
+	if err = addFileToArchive(w, dbPath, backupArchiveDatabase, method); err != nil {
+		return fmt.Errorf("adding database to backup: %w", err)
+	}
+
+	if blobsPath != "" {
+		// blobs are mostly images, which do not compress well
+		err = filepath.WalkDir(blobsPath, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+
+			rel, err := filepath.Rel(blobsPath, p)
+			if err != nil {
+				return err
+			}
+
+			return addFileToArchive(w, p, path.Join(backupArchiveBlobs, filepath.ToSlash(rel)), zip.Store)
+		})
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("adding blobs to backup: %w", err)
+		}
+	}
+
+	if err = w.Close(); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, archivePath)
+}
+
+func addFileToArchive(w *zip.Writer, fn string, name string, method uint16) error {
+	src, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	header.Method = method
+
+	dst, err := w.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+func extractArchiveFile(zf *zip.File, dstPath string) error {
+	src, err := zf.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+
+	return dst.Close()
+}
+
+// extractBackupArchive extracts the database from the backup archive to
+// dbPath. If blobsPath is not empty, the blobs in the archive are extracted
+// to it. Returns true if the archive contains blobs.
+func extractBackupArchive(archivePath string, dbPath string, blobsPath string) (bool, error) {
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return false, err
+	}
+	defer r.Close()
+
+	foundDatabase := false
+	hasBlobs := false
+	for _, zf := range r.File {
+		switch {
+		case zf.Name == backupArchiveDatabase:
+			if err := extractArchiveFile(zf, dbPath); err != nil {
+				return false, fmt.Errorf("extracting database: %w", err)
+			}
+			foundDatabase = true
+		case strings.HasPrefix(zf.Name, backupArchiveBlobs+"/") && !zf.FileInfo().IsDir():
+			hasBlobs = true
+			if blobsPath == "" {
+				continue
+			}
+
+			rel := strings.TrimPrefix(zf.Name, backupArchiveBlobs+"/")
+			dst := filepath.Join(blobsPath, filepath.FromSlash(rel))
+			if !fsutil.IsPathInDir(blobsPath, dst) {
+				return false, fmt.Errorf("invalid blob path %q in backup", zf.Name)
+			}
+
+			if err := fsutil.EnsureDirAll(filepath.Dir(dst)); err != nil {
+				return false, err
+			}
+			if err := extractArchiveFile(zf, dst); err != nil {
+				return false, fmt.Errorf("extracting blob %s: %w", rel, err)
+			}
+		}
+	}
+
+	if !foundDatabase {
+		return false, fmt.Errorf("backup %s does not contain a database", filepath.Base(archivePath))
+	}
+
+	return hasBlobs, nil
+}
+
+// replaceBlobs moves the blobs directory to oldBlobsPath, and the restored
+// blobs into its place. It returns true if there was an existing blobs
+// directory. The existing blobs are moved back if the restored blobs cannot
+// be moved into place.
+func replaceBlobs(blobsPath string, restoreBlobsPath string, oldBlobsPath string) (bool, error) {
+	if err := os.RemoveAll(oldBlobsPath); err != nil {
+		return false, err
+	}
+
+	hadBlobs := true
+	if err := os.Rename(blobsPath, oldBlobsPath); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return false, err
+		}
+		hadBlobs = false
+	}
+
+	if err := os.Rename(restoreBlobsPath, blobsPath); err != nil {
+		if hadBlobs {
+			if err := os.Rename(oldBlobsPath, blobsPath); err != nil {
+				logger.Errorf("Error moving blobs directory back from %s: %v", oldBlobsPath, err)
+			}
+		}
+		return false, err
+	}
+
+	return hadBlobs, nil
+}
+
+// revertBlobs moves the blobs replaced by replaceBlobs back into place.
+func revertBlobs(blobsPath string, oldBlobsPath string, hadBlobs bool) error {
+	if err := os.RemoveAll(blobsPath); err != nil {
+		return err
+	}
+
+	if !hadBlobs {
+		return nil
+	}
+
+	return os.Rename(oldBlobsPath, blobsPath)
+}
+
+// Restore replaces the database with the backup with the provided name. The
+// backup is verified before the database is replaced, and a backup of the
+// current database is created first. If the backup contains blobs and blobs
+// are stored on the filesystem, the blobs directory is replaced as well, and
+// is reverted if the database cannot be restored. The restored database may
+// need to be migrated before it can be used.
+func (b *DatabaseBackups) Restore(name string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	backups, err := b.list()
+	if err != nil {
+		return err
+	}
+
+	var backup *DatabaseBackup
+	for _, candidate := range backups {
+		if candidate.Name == name {
+			backup = candidate
+			break
+		}
+	}
+	if backup == nil {
+		return fmt.Errorf("database backup %q not found", name)
+	}
+
+	dbPath := b.database.DatabasePath()
+	f, err := os.CreateTemp(filepath.Dir(dbPath), ".restore*.sqlite")
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+	f.Close()
+	defer os.Remove(tmpPath)
+
+	blobsPath := b.filesystemBlobsPath()
+	restoreBlobsPath := ""
+	hasBlobs := false
+
+	if backup.Archive {
+		if blobsPath != "" {
+			restoreBlobsPath = blobsPath + ".restore"
+			if err := os.RemoveAll(restoreBlobsPath); err != nil {
+				return err
+			}
+			defer os.RemoveAll(restoreBlobsPath)
+		}
+
+		hasBlobs, err = extractBackupArchive(backup.path, tmpPath, restoreBlobsPath)
+		if err != nil {
+			return err
+		}
+	} else {
+		// CopyFile does not overwrite existing files
+		if err := os.Remove(tmpPath); err != nil {
+			return err
+		}
+		if err := fsutil.CopyFile(backup.path, tmpPath); err != nil {
+			return err
+		}
+	}
+
+	if err := sqlite.CheckIntegrity(tmpPath); err != nil {
+		return fmt.Errorf("verifying backup: %w", err)
+	}
+
+	version, err := sqlite.DatabaseFileSchemaVersion(tmpPath)
+	if err != nil {
+		return err
+	}
+	if version > b.database.AppSchemaVersion() {
+		return fmt.Errorf("backup schema version %d is newer than the supported schema version %d", version, b.database.AppSchemaVersion())
+	}
+
+	restoreBlobs := hasBlobs && restoreBlobsPath != ""
+
+	safetyBackup, err := b.create(preRestoreBackupKind, b.config.GetBackupCompress(), restoreBlobs)
+	if err != nil {
+		return fmt.Errorf("backing up current database: %w", err)
+	}
+	logger.Infof("Backed up current database to: %s", safetyBackup)
+
+	// the blobs are replaced first, so that they can be reverted if the
+	// database cannot be restored
+	oldBlobsPath := blobsPath + ".old"
+	hadBlobs := false
+	if restoreBlobs {
+		hadBlobs, err = replaceBlobs(blobsPath, restoreBlobsPath, oldBlobsPath)
+		if err != nil {
+			return fmt.Errorf("replacing blobs (current blobs were backed up to %s): %w", safetyBackup, err)
+		}
+	}
+
+	var migrationNeeded *sqlite.MigrationNeededError
+	if err := b.database.Restore(tmpPath); err != nil && !errors.As(err, &migrationNeeded) {
+		if restoreBlobs {
+			if err := revertBlobs(blobsPath, oldBlobsPath, hadBlobs); err != nil {
+				logger.Errorf("Error reverting blobs directory from %s: %v", oldBlobsPath, err)
+			}
+		}
+		return fmt.Errorf("restoring database (current database was backed up to %s): %w", safetyBackup, err)
+	}
+
+	if restoreBlobs {
+		if err := os.RemoveAll(oldBlobsPath); err != nil {
+			logger.Warnf("Error removing old blobs directory %s: %v", oldBlobsPath, err)
+		}
+	}
+
+	logger.Infof("Restored database from backup: %s", name)
+	return nil
+}
@@ -0,0 +1,216 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBackupName(t *testing.T) {
+	re := backupNameRE("stash-go.sqlite")
+	createdAt := time.Date(2024, 3, 13, 12, 30, 5, 0, time.Local)
+
+	tests := []struct {
+		name string
+		want *DatabaseBackup
+	}{
+		{"stash-go.sqlite.74.20240313_123005", &DatabaseBackup{SchemaVersion: 74, CreatedAt: createdAt}},
+		{"stash-go.sqlite.scheduled.74.20240313_123005", &DatabaseBackup{SchemaVersion: 74, CreatedAt: createdAt, Scheduled: true}},
+		{"stash-go.sqlite.scheduled.74.20240313_123005.zip", &DatabaseBackup{SchemaVersion: 74, CreatedAt: createdAt, Scheduled: true, Archive: true}},
+		{"stash-go.sqlite.pre_restore.73.20240313_123005.zip", &DatabaseBackup{SchemaVersion: 73, CreatedAt: createdAt, Archive: true}},
+		{"stash-go.sqlite.anonymous.74.20240313_123005", nil},
+		{"other.sqlite.74.20240313_123005", nil},
+		{"stash-go.sqlite.74.20240313_123005.zip.tmp", nil},
+		{"stash-go.sqlite", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.want != nil {
+				tt.want.Name = tt.name
+			}
+			assert.Equal(t, tt.want, parseBackupName(re, tt.name))
+		})
+	}
+}
+
+func TestScheduledBackupsToRemove(t *testing.T) {
+	// Wednesday
+	now := time.Date(2024, 3, 13, 12, 0, 0, 0, time.Local)
+
+	backup := func(name string, createdAt time.Time, scheduled bool) *DatabaseBackup {
+		return &DatabaseBackup{Name: name, CreatedAt: createdAt, Scheduled: scheduled}
+	}
+
+	var backups []*DatabaseBackup
+	for i := 0; i < 20; i++ {
+		backups = append(backups, backup(now.AddDate(0, 0, -i).Format(backupTimeFormat), now.AddDate(0, 0, -i), true))
+	}
+	earlier := backup("earlier", now.Add(-6*time.Hour), true)
+	manual := backup("manual", now.AddDate(0, 0, -30), false)
+	backups = append(backups, earlier, manual)
+
+	names := func(backups []*DatabaseBackup) []string {
+		var ret []string
+		for _, b := range backups {
+			ret = append(ret, b.Name)
+		}
+		return ret
+	}
+
+	t.Run("daily and weekly", func(t *testing.T) {
+		removed := scheduledBackupsToRemove(backups, 3, 2)
+
+		// the three newest days are kept, and the 10th is the newest of the
+		// previous week
+		var want []string
+		want = append(want, earlier.Name)
+		for i := 4; i < 20; i++ {
+			want = append(want, now.AddDate(0, 0, -i).Format(backupTimeFormat))
+		}
+
+		assert.ElementsMatch(t, want, names(removed))
+	})
+
+	t.Run("weekly only", func(t *testing.T) {
+		removed := scheduledBackupsToRemove(backups, 0, 3)
+
+		// newest of the weeks of the 13th, 10th and 3rd
+		kept := map[string]bool{
+			now.Format(backupTimeFormat):                    true,
+			now.AddDate(0, 0, -3).Format(backupTimeFormat):  true,
+			now.AddDate(0, 0, -10).Format(backupTimeFormat): true,
+		}
+
+		for _, b := range removed {
+			assert.False(t, kept[b.Name], b.Name)
+			assert.True(t, b.Scheduled, b.Name)
+		}
+		assert.Len(t, removed, 21-len(kept))
+	})
+
+	t.Run("newest always kept", func(t *testing.T) {
+		removed := scheduledBackupsToRemove(backups, 0, 0)
+
+		assert.Len(t, removed, 20)
+		assert.NotContains(t, names(removed), now.Format(backupTimeFormat))
+		assert.NotContains(t, names(removed), manual.Name)
+	})
+}
+
+func TestBackupArchive(t *testing.T) {
+	dir := t.TempDir()
+
+	dbPath := filepath.Join(dir, "db.sqlite")
+	blobsPath := filepath.Join(dir, "blobs")
+	if err := os.WriteFile(dbPath, []byte("database"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(blobsPath, "ab", "cd"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(blobsPath, "ab", "cd", "abcdef"), []byte("blob"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	archivePath := filepath.Join(dir, "backup.zip")
+	if err := writeBackupArchive(archivePath, dbPath, blobsPath, true); err != nil {
+		t.Fatalf("writeBackupArchive: %v", err)
+	}
+
+	restoredDBPath := filepath.Join(dir, "restored.sqlite")
+	restoredBlobsPath := filepath.Join(dir, "restored_blobs")
+	hasBlobs, err := extractBackupArchive(archivePath, restoredDBPath, restoredBlobsPath)
+	if err != nil {
+		t.Fatalf("extractBackupArchive: %v", err)
+	}
+
+	assert.True(t, hasBlobs)
+
+	got, err := os.ReadFile(restoredDBPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "database", string(got))
+
+	got, err = os.ReadFile(filepath.Join(restoredBlobsPath, "ab", "cd", "abcdef"))
+	assert.NoError(t, err)
+	assert.Equal(t, "blob", string(got))
+}
+
+func TestReplaceBlobs(t *testing.T) {
+	writeBlob := func(t *testing.T, dir string, content string) {
+		t.Helper()
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "blob"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	readBlob := func(t *testing.T, dir string) string {
+		t.Helper()
+		got, err := os.ReadFile(filepath.Join(dir, "blob"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(got)
+	}
+
+	tests := []struct {
+		name     string
+		hasBlobs bool
+	}{
+		{"existing blobs", true},
+		{"no existing blobs", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			blobsPath := filepath.Join(dir, "blobs")
+			restoreBlobsPath := filepath.Join(dir, "blobs.restore")
+			oldBlobsPath := filepath.Join(dir, "blobs.old")
+
+			if tt.hasBlobs {
+				writeBlob(t, blobsPath, "current")
+			}
+			writeBlob(t, restoreBlobsPath, "restored")
+
+			hadBlobs, err := replaceBlobs(blobsPath, restoreBlobsPath, oldBlobsPath)
+			if err != nil {
+				t.Fatalf("replaceBlobs: %v", err)
+			}
+
+			assert.Equal(t, tt.hasBlobs, hadBlobs)
+			assert.Equal(t, "restored", readBlob(t, blobsPath))
+
+			if err := revertBlobs(blobsPath, oldBlobsPath, hadBlobs); err != nil {
+				t.Fatalf("revertBlobs: %v", err)
+			}
+
+			if tt.hasBlobs {
+				assert.Equal(t, "current", readBlob(t, blobsPath))
+			} else {
+				assert.NoDirExists(t, blobsPath)
+			}
+			assert.NoDirExists(t, oldBlobsPath)
+		})
+	}
+
+	t.Run("restored blobs missing", func(t *testing.T) {
+		dir := t.TempDir()
+		blobsPath := filepath.Join(dir, "blobs")
+		oldBlobsPath := filepath.Join(dir, "blobs.old")
+
+		writeBlob(t, blobsPath, "current")
+
+		// the current blobs are moved back into place
+		_, err := replaceBlobs(blobsPath, filepath.Join(dir, "missing"), oldBlobsPath)
+		assert.Error(t, err)
+		assert.Equal(t, "current", readBlob(t, blobsPath))
+		assert.NoDirExists(t, oldBlobsPath)
+	})
+}
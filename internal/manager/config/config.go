@@ -9,6 +9,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"sync"
 	// "github.com/sasha-s/go-deadlock" // if you have deadlock issues
@@ -47,6 +48,28 @@ const (
 
 	BlobsStorage = "blobs_storage"
 
+	// FullTextSearch enables the full-text search indexes, if full-text
+	// search is available in the build.
+	FullTextSearch        = "full_text_search"
+	fullTextSearchDefault = false
+
+	// BackupInterval is the number of hours between scheduled database
+	// backups. Scheduled backups are disabled if zero.
+	BackupInterval        = "backup_interval"
+	backupIntervalDefault = 0
+
+	BackupKeepDaily        = "backup_keep_daily"
+	backupKeepDailyDefault = 7
+
+	BackupKeepWeekly        = "backup_keep_weekly"
+	backupKeepWeeklyDefault = 4
+
+	BackupCompress        = "backup_compress"
+	backupCompressDefault = false
+
+	BackupIncludeBlobs        = "backup_include_blobs"
+	backupIncludeBlobsDefault = true
+
 	DefaultMaxSessionAge = 60 * 60 * 1 // 1 hours
 
 	Database = "database"
@@ -134,6 +157,9 @@ const (
 	ScraperCDPPath            = "scraper_cdp_path"
 	ScraperExcludeTagPatterns = "scraper_exclude_tag_patterns"
 
+	// embedded file metadata options
+	EmbeddedMetadataMappings = "embedded_metadata_mappings"
+
 	// stash-box options
 	StashBoxes = "stash_boxes"
 
@@ -244,6 +270,8 @@ const (
 	DLNAVideoSortOrder        = "dlna.video_sort_order"
 	dlnaVideoSortOrderDefault = "title"
 
+	DLNAFilterQuery = "dlna.filter_query"
+
 	DLNAPort        = "dlna.port"
 	DLNAPortDefault = 1338
 
@@ -656,6 +684,16 @@ func (i *Config) GetStashPaths() StashConfigs {
 	return ret
 }
 
+// GetLibraryPaths returns the paths of the configured stash libraries.
+func (i *Config) GetLibraryPaths() []string {
+	var ret []string
+	for _, s := range i.GetStashPaths() {
+		ret = append(ret, s.Path)
+	}
+
+	return ret
+}
+
 func (i *Config) GetCachePath() string {
 	return i.getString(Cache)
 }
@@ -707,6 +745,39 @@ func (i *Config) GetBackupDirectoryPathOrDefault() string {
 	return ret
 }
 
+// GetBackupInterval returns the interval between scheduled database backups.
+// Returns zero if scheduled backups are disabled.
+func (i *Config) GetBackupInterval() time.Duration {
+	ret := i.getInt(BackupInterval)
+	if ret < 0 {
+		return 0
+	}
+	return time.Duration(ret) * time.Hour
+}
+
+// GetBackupKeepDaily returns the number of days for which the newest
+// scheduled backup of each day is kept.
+func (i *Config) GetBackupKeepDaily() int {
+	return i.getInt(BackupKeepDaily)
+}
+
+// GetBackupKeepWeekly returns the number of weeks for which the newest
+// scheduled backup of each week is kept.
+func (i *Config) GetBackupKeepWeekly() int {
+	return i.getInt(BackupKeepWeekly)
+}
+
+// GetBackupCompress returns true if scheduled backups should be compressed.
+func (i *Config) GetBackupCompress() bool {
+	return i.getBool(BackupCompress)
+}
+
+// GetBackupIncludeBlobs returns true if scheduled backups should include the
+// blobs directory when blobs are stored on the filesystem.
+func (i *Config) GetBackupIncludeBlobs() bool {
+	return i.getBool(BackupIncludeBlobs)
+}
+
 // GetFFMpegPath returns the path to the FFMpeg executable.
 // If empty, stash will attempt to resolve it from the path.
 func (i *Config) GetFFMpegPath() string {
@@ -804,6 +875,10 @@ func (i *Config) GetSequentialScanning() bool {
 	return i.getBool(SequentialScanning)
 }
 
+func (i *Config) GetFullTextSearch() bool {
+	return i.getBool(FullTextSearch)
+}
+
 func (i *Config) GetGalleryCoverRegex() string {
 	var regexString = i.getString(GalleryCoverRegex)
 
@@ -840,6 +915,18 @@ func (i *Config) GetScraperExcludeTagPatterns() []string {
 	return i.getStringSlice(ScraperExcludeTagPatterns)
 }
 
+// GetEmbeddedMetadataMappings returns the mappings used to populate objects
+// from the embedded metadata of their files. Unset fields use the default
+// mappings.
+func (i *Config) GetEmbeddedMetadataMappings() models.EmbeddedMetadataMappings {
+	var ret models.EmbeddedMetadataMappings
+	if err := i.unmarshalKey(EmbeddedMetadataMappings, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	return ret.WithDefaults()
+}
+
 func (i *Config) GetStashBoxes() []*models.StashBox {
 	var boxes []*models.StashBox
 	if err := i.unmarshalKey(StashBoxes, &boxes); err != nil {
@@ -1589,6 +1676,12 @@ func (i *Config) GetVideoSortOrder() string {
 	return ret
 }
 
+// GetDLNAFilterQuery returns the filter query used to restrict the scenes
+// served by DLNA. If empty, all scenes are served.
+func (i *Config) GetDLNAFilterQuery() string {
+	return i.getString(DLNAFilterQuery)
+}
+
 // GetLogFile returns the filename of the file to output logs to.
 // An empty string means that file logging will be disabled.
 func (i *Config) GetLogFile() string {
@@ -1762,7 +1855,13 @@ func (i *Config) setDefaultValues() {
 	i.setDefault(Port, portDefault)
 
 	i.setDefault(ParallelTasks, parallelTasksDefault)
+	i.setDefault(BackupInterval, backupIntervalDefault)
+	i.setDefault(BackupKeepDaily, backupKeepDailyDefault)
+	i.setDefault(BackupKeepWeekly, backupKeepWeeklyDefault)
+	i.setDefault(BackupCompress, backupCompressDefault)
+	i.setDefault(BackupIncludeBlobs, backupIncludeBlobsDefault)
 	i.setDefault(SequentialScanning, SequentialScanningDefault)
+	i.setDefault(FullTextSearch, fullTextSearchDefault)
 	i.setDefault(PreviewSegmentDuration, previewSegmentDurationDefault)
 	i.setDefault(PreviewSegments, previewSegmentsDefault)
 	i.setDefault(PreviewExcludeStart, previewExcludeStartDefault)
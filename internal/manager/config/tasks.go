@@ -17,6 +17,10 @@ type ScanMetadataOptions struct {
 	ScanGenerateThumbnails bool `json:"scanGenerateThumbnails"`
 	// Generate image thumbnails during scan
 	ScanGenerateClipPreviews bool `json:"scanGenerateClipPreviews"`
+	// Apply enabled auto-tag rules to scanned scenes and images
+	ScanApplyAutoTagRules bool `json:"scanApplyAutoTagRules"`
+	// Import metadata from NFO and JSON sidecar files during scan
+	ScanSidecarFiles bool `json:"scanSidecarFiles"`
 }
 
 type AutoTagMetadataOptions struct {
@@ -26,4 +30,8 @@ type AutoTagMetadataOptions struct {
 	Studios []string `json:"studios"`
 	// IDs of tags to tag files with, or "*" for all
 	Tags []string `json:"tags"`
+	// IDs of auto-tag rules to apply, or "*" for all enabled rules
+	Rules []string `json:"rules"`
+	// Populate scenes and images from the metadata embedded in their files
+	EmbeddedMetadata bool `json:"embeddedMetadata"`
 }
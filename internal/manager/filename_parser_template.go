@@ -0,0 +1,212 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+type ApplyFilenameParserTemplatesInput struct {
+	// IDs of the templates to apply. Applies all enabled templates if empty.
+	Ids []string `json:"ids"`
+	// Paths of the scenes to parse, null for all scenes
+	Paths []string `json:"paths"`
+}
+
+// filenameParserTemplates applies saved filename parser templates to scenes.
+// Templates are tried in order, and the first template that applies to the
+// scene path and matches it is used.
+type filenameParserTemplates struct {
+	parsers []*scene.TemplateParser
+}
+
+// getFilenameParserTemplates returns the templates with the provided IDs, or
+// all enabled templates if ids is empty. It returns nil if there are no
+// templates.
+func getFilenameParserTemplates(ctx context.Context, repo models.Repository, ids []int) (*filenameParserTemplates, error) {
+	var templates []*models.FilenameParserTemplate
+	if err := repo.WithReadTxn(ctx, func(ctx context.Context) error {
+		qb := repo.FilenameParserTemplate
+		if len(ids) > 0 {
+			var err error
+			templates, err = qb.FindMany(ctx, ids)
+			return err
+		}
+
+		all, err := qb.All(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, t := range all {
+			if t.Enabled {
+				templates = append(templates, t)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("getting filename parser templates: %w", err)
+	}
+
+	if len(templates) == 0 {
+		return nil, nil
+	}
+
+	parserRepo := scene.NewFilenameParserRepository(repo)
+	ret := &filenameParserTemplates{}
+	for _, t := range templates {
+		p, err := scene.NewTemplateParser(t, parserRepo)
+		if err != nil {
+			return nil, err
+		}
+		ret.parsers = append(ret.parsers, p)
+	}
+
+	return ret, nil
+}
+
+// appliesTo returns true if any of the templates apply to the path.
+func (t *filenameParserTemplates) appliesTo(path string) bool {
+	for _, p := range t.parsers {
+		if p.AppliesTo(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply parses the scene path and updates the scene with the result of the
+// first matching template. It returns false if no template matched the path.
+// Must be called within a transaction.
+func (t *filenameParserTemplates) apply(ctx context.Context, s *models.Scene, path string, w models.SceneUpdater) (bool, error) {
+	for _, p := range t.parsers {
+		if !p.AppliesTo(path) {
+			continue
+		}
+
+		result := p.Parse(ctx, s, path)
+		if result == nil {
+			continue
+		}
+
+		if p.Skips(s) {
+			return true, nil
+		}
+
+		partial, err := scene.ParserResultPartial(s, result)
+		if err != nil {
+			return true, err
+		}
+
+		if partial != nil {
+			if _, err := w.UpdatePartial(ctx, s.ID, *partial); err != nil {
+				return true, fmt.Errorf("updating scene: %w", err)
+			}
+			logger.Infof("Parsed scene %s using filename parser template %q", path, p.Template.Name)
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// applyScene applies the templates to the scene with the provided ID in a new
+// transaction.
+func (t *filenameParserTemplates) applyScene(ctx context.Context, repo models.Repository, sceneID int, path string) {
+	if !t.appliesTo(path) {
+		return
+	}
+
+	if err := repo.WithTxn(ctx, func(ctx context.Context) error {
+		s, err := repo.Scene.Find(ctx, sceneID)
+		if err != nil || s == nil {
+			return err
+		}
+
+		matched, err := t.apply(ctx, s, path, repo.Scene)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			logger.Infof("Scene %s did not match any filename parser template", path)
+		}
+		return nil
+	}); err != nil {
+		logger.Errorf("Error applying filename parser templates to %s: %v", path, err)
+	}
+}
+
+type applyFilenameParserTemplatesJob struct {
+	repository  models.Repository
+	templateIDs []int
+	paths       []string
+}
+
+func (j *applyFilenameParserTemplatesJob) Execute(ctx context.Context, progress *job.Progress) error {
+	start := time.Now()
+	r := j.repository
+
+	templates, err := getFilenameParserTemplates(ctx, r, j.templateIDs)
+	if err != nil {
+		return err
+	}
+	if templates == nil {
+		logger.Info("No filename parser templates to apply")
+		return nil
+	}
+
+	// the scenes are updated in separate transactions, so find them first
+	var scenes []*models.Scene
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		return scene.BatchProcess(ctx, r.Scene, scene.FilterFromPaths(j.paths), nil, func(s *models.Scene) error {
+			if s.Path != "" && templates.appliesTo(s.Path) {
+				scenes = append(scenes, s)
+			}
+			return nil
+		})
+	}); err != nil {
+		return fmt.Errorf("finding scenes: %w", err)
+	}
+
+	progress.SetTotal(len(scenes))
+
+	parsed := 0
+	var failures []string
+	for _, s := range scenes {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return nil
+		}
+
+		if err := r.WithTxn(ctx, func(ctx context.Context) error {
+			matched, err := templates.apply(ctx, s, s.Path, r.Scene)
+			if err != nil {
+				return err
+			}
+
+			if matched {
+				parsed++
+			} else {
+				failures = append(failures, s.Path)
+			}
+			return nil
+		}); err != nil {
+			logger.Errorf("Error applying filename parser templates to %s: %v", s.Path, err)
+		}
+
+		progress.Increment()
+	}
+
+	for _, path := range failures {
+		logger.Warnf("Scene %s did not match any filename parser template", path)
+	}
+
+	logger.Infof("Parsed %d scenes using filename parser templates, %d scenes did not match (%s)", parsed, len(failures), time.Since(start))
+	return nil
+}
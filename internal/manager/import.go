@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
 )
 
 type ImportDuplicateEnum string
@@ -62,6 +63,8 @@ type importer interface {
 }
 
 func performImport(ctx context.Context, i importer, duplicateBehaviour ImportDuplicateEnum) error {
+	ctx = models.WithChangeOrigin(ctx, models.ChangeOrigin{Type: models.ChangeOriginTypeImport})
+
 	if err := i.PreImport(ctx); err != nil {
 		return err
 	}
@@ -40,10 +40,11 @@ func Initialize(cfg *config.Config, l *log.Logger) (*Manager, error) {
 	// start with empty paths
 	mgrPaths := &paths.Paths{}
 
-	scraperRepository := scraper.NewRepository(repo)
-	scraperCache := scraper.NewCache(cfg, scraperRepository)
-
 	pluginCache := plugin.NewCache(cfg)
+	pluginCache.RegisterStorage(repo.TxnManager, db.PluginStorage)
+
+	scraperRepository := scraper.NewRepository(repo)
+	scraperCache := scraper.NewCache(cfg, scraperRepository, pluginCache)
 
 	sceneService := &scene.Service{
 		File:             db.File,
@@ -93,6 +94,9 @@ func Initialize(cfg *config.Config, l *log.Logger) (*Manager, error) {
 
 		DLNAService: dlnaService,
 
+		StashBoxOutbox:  NewStashBoxOutbox(repo, cfg, db.Ready),
+		DatabaseBackups: NewDatabaseBackups(db, cfg),
+
 		Database:   db,
 		Repository: repo,
 
@@ -103,6 +107,11 @@ func Initialize(cfg *config.Config, l *log.Logger) (*Manager, error) {
 		scanSubs: &subscriptionManager{},
 	}
 
+	// queued submissions are only sent once the database is ready
+	mgr.startStashBoxOutbox()
+	// scheduled backups are only created once the database is ready
+	mgr.DatabaseBackups.Start(context.Background())
+
 	if !cfg.IsNewSystem() {
 		logger.Infof("using config file: %s", cfg.GetConfigFile())
 
@@ -195,6 +204,7 @@ func (s *Manager) postInit(ctx context.Context) error {
 	s.RefreshDLNA()
 
 	s.SetBlobStoreOptions()
+	s.Database.SetFullTextSearch(s.Config.GetFullTextSearch())
 
 	s.writeStashIcon()
 
@@ -58,6 +58,12 @@ type Manager struct {
 
 	DLNAService *dlna.Service
 
+	StashBoxOutbox  *StashBoxOutbox
+	DatabaseBackups *DatabaseBackups
+
+	// stops the stash-box outbox started by startStashBoxOutbox
+	stopStashBoxOutboxFunc context.CancelFunc
+
 	Database   *sqlite.Database
 	Repository models.Repository
 
@@ -338,6 +344,51 @@ func (s *Manager) BackupDatabase(download bool) (string, string, error) {
 	return backupPath, backupName, nil
 }
 
+// startStashBoxOutbox starts sending queued stash-box submissions in the
+// background, until stopStashBoxOutbox is called.
+func (s *Manager) startStashBoxOutbox() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopStashBoxOutboxFunc = cancel
+	s.StashBoxOutbox.Start(ctx)
+}
+
+// stopStashBoxOutbox stops sending queued stash-box submissions, and waits
+// for any submission being sent to finish.
+func (s *Manager) stopStashBoxOutbox() {
+	if s.stopStashBoxOutboxFunc == nil {
+		return
+	}
+
+	s.stopStashBoxOutboxFunc()
+	s.stopStashBoxOutboxFunc = nil
+	s.StashBoxOutbox.Wait()
+}
+
+// RestoreDatabase replaces the database with the backup with the provided
+// name. The database cannot be restored while jobs are queued or running,
+// and jobs are not started until the restore is complete. Plugin services
+// are stopped and queued stash-box submissions are not sent during the
+// restore. Scheduled backups are not created during the restore, since
+// DatabaseBackups performs one backup or restore at a time.
+func (s *Manager) RestoreDatabase(name string) error {
+	err := s.JobManager.RunExclusive(func() error {
+		if s.PluginCache != nil {
+			s.PluginCache.StopServices()
+			defer s.PluginCache.StartServices()
+		}
+
+		s.stopStashBoxOutbox()
+		defer s.startStashBoxOutbox()
+
+		return s.DatabaseBackups.Restore(name)
+	})
+	if errors.Is(err, job.ErrJobsRunning) {
+		return errors.New("cannot restore the database while jobs are running")
+	}
+
+	return err
+}
+
 func (s *Manager) AnonymiseDatabase(download bool) (string, string, error) {
 	var outPath string
 	var outName string
@@ -424,6 +475,12 @@ func (s *Manager) Shutdown() {
 		s.StreamManager = nil
 	}
 
+	if s.PluginCache != nil {
+		s.PluginCache.StopServices()
+	}
+
+	s.stopStashBoxOutbox()
+
 	err := s.Database.Close()
 	if err != nil {
 		logger.Errorf("Error closing database: %s", err)
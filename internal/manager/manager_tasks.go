@@ -16,6 +16,8 @@ import (
 	"github.com/stashapp/stash/pkg/job"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 )
 
 func useAsVideo(pathname string) bool {
@@ -270,6 +272,10 @@ type AutoTagMetadataInput struct {
 	Studios []string `json:"studios"`
 	// IDs of tags to tag files with, or "*" for all
 	Tags []string `json:"tags"`
+	// IDs of auto-tag rules to apply, or "*" for all enabled rules
+	Rules []string `json:"rules"`
+	// Populate scenes and images from the metadata embedded in their files
+	EmbeddedMetadata bool `json:"embeddedMetadata"`
 }
 
 func (s *Manager) AutoTag(ctx context.Context, input AutoTagMetadataInput) int {
@@ -281,6 +287,43 @@ func (s *Manager) AutoTag(ctx context.Context, input AutoTagMetadataInput) int {
 	return s.JobManager.Add(ctx, "Auto-tagging...", &j)
 }
 
+type ExportSidecarsInput struct {
+	// IDs of scenes to export. If not set, all scenes are exported
+	IDs []string `json:"ids"`
+	// Overwrite existing NFO files
+	Overwrite *bool `json:"overwrite"`
+}
+
+func (s *Manager) ExportSidecars(ctx context.Context, input ExportSidecarsInput) (int, error) {
+	ids, err := stringslice.StringSliceToIntSlice(input.IDs)
+	if err != nil {
+		return 0, err
+	}
+
+	j := exportSidecarsJob{
+		repository: s.Repository,
+		sceneIDs:   ids,
+		overwrite:  input.Overwrite != nil && *input.Overwrite,
+	}
+
+	return s.JobManager.Add(ctx, "Exporting sidecar files...", &j), nil
+}
+
+func (s *Manager) ApplyFilenameParserTemplates(ctx context.Context, input ApplyFilenameParserTemplatesInput) (int, error) {
+	ids, err := stringslice.StringSliceToIntSlice(input.Ids)
+	if err != nil {
+		return 0, err
+	}
+
+	j := applyFilenameParserTemplatesJob{
+		repository:  s.Repository,
+		templateIDs: ids,
+		paths:       input.Paths,
+	}
+
+	return s.JobManager.Add(ctx, "Applying filename parser templates...", &j), nil
+}
+
 type CleanMetadataInput struct {
 	Paths []string `json:"paths"`
 	// Do a dry run. Don't delete any files
@@ -641,3 +684,106 @@ func (s *Manager) StashBoxBatchStudioTag(ctx context.Context, box *models.StashB
 
 	return s.JobManager.Add(ctx, "Batch stash-box studio tag...", j)
 }
+
+func (s *Manager) StashBoxBatchSceneTag(ctx context.Context, box *models.StashBox, input StashBoxBatchTagInput) int {
+	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) error {
+		logger.Infof("Initiating stash-box batch scene tag")
+
+		var tasks []StashBoxBatchTagTask
+		addTask := func(sc *models.Scene) {
+			tasks = append(tasks, StashBoxBatchTagTask{
+				scene:          sc,
+				refresh:        input.Refresh,
+				box:            box,
+				excludedFields: input.ExcludeFields,
+				taskType:       Scene,
+			})
+		}
+
+		if len(input.Ids) > 0 {
+			// The user has chosen only to tag the items on the current page
+			if err := s.Repository.WithTxn(ctx, func(ctx context.Context) error {
+				sceneQuery := s.Repository.Scene
+
+				for _, sceneID := range input.Ids {
+					id, err := strconv.Atoi(sceneID)
+					if err != nil {
+						continue
+					}
+
+					sc, err := sceneQuery.Find(ctx, id)
+					if err != nil {
+						return err
+					}
+					if sc == nil {
+						continue
+					}
+
+					if err := sc.LoadStashIDs(ctx, sceneQuery); err != nil {
+						return fmt.Errorf("loading scene stash ids: %w", err)
+					}
+
+					// Check if the user wants to refresh existing or new items
+					hasStashID := sc.StashIDs.ForEndpoint(box.Endpoint) != nil
+					if input.Refresh == hasStashID {
+						addTask(sc)
+					}
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+		} else {
+			// The user has chosen to tag every item in their database
+			modifier := models.CriterionModifierIsNull
+			if input.Refresh {
+				modifier = models.CriterionModifierNotNull
+			}
+
+			sceneFilter := &models.SceneFilterType{
+				StashIDEndpoint: &models.StashIDCriterionInput{
+					Endpoint: &box.Endpoint,
+					Modifier: modifier,
+				},
+			}
+
+			sort := "path"
+			findFilter := &models.FindFilterType{
+				Sort: &sort,
+			}
+
+			if err := s.Repository.WithDB(ctx, func(ctx context.Context) error {
+				return scene.BatchProcess(ctx, s.Repository.Scene, sceneFilter, findFilter, func(sc *models.Scene) error {
+					addTask(sc)
+					return nil
+				})
+			}); err != nil {
+				return fmt.Errorf("error querying scenes: %v", err)
+			}
+		}
+
+		if len(tasks) == 0 {
+			return nil
+		}
+
+		progress.SetTotal(len(tasks))
+
+		logger.Infof("Starting stash-box batch operation for %d scenes", len(tasks))
+
+		for _, task := range tasks {
+			if job.IsCancelled(ctx) {
+				break
+			}
+
+			progress.ExecuteTask(task.Description(), func() {
+				task.Start(ctx)
+			})
+
+			progress.Increment()
+		}
+
+		return nil
+	})
+
+	return s.JobManager.Add(ctx, "Batch stash-box scene tag...", j)
+}
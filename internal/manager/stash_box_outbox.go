@@ -0,0 +1,387 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper/stashbox"
+)
+
+const (
+	// interval at which the outbox checks for submissions to retry
+	stashBoxOutboxInterval = time.Minute
+
+	stashBoxSubmissionMaxAttempts = 10
+	stashBoxSubmissionMinBackoff  = time.Minute
+	stashBoxSubmissionMaxBackoff  = 6 * time.Hour
+)
+
+var errNoFingerprints = errors.New("scene has no fingerprints to submit for this endpoint")
+
+type StashBoxConfig interface {
+	GetStashBoxes() []*models.StashBox
+}
+
+// StashBoxOutbox sends queued submissions to stash-box instances.
+// Submissions that fail because the instance is unreachable are retried
+// with exponential backoff. Fingerprints that have already been submitted
+// to an instance are not submitted again.
+type StashBoxOutbox struct {
+	repository models.Repository
+	config     StashBoxConfig
+	// returns an error if the database is not ready to be used
+	ready func() error
+
+	trigger chan struct{}
+	// tracks the background goroutine started by Start
+	running sync.WaitGroup
+	// ensures that submissions are only attempted by one goroutine at a time
+	mutex sync.Mutex
+}
+
+func NewStashBoxOutbox(repository models.Repository, config StashBoxConfig, ready func() error) *StashBoxOutbox {
+	return &StashBoxOutbox{
+		repository: repository,
+		config:     config,
+		ready:      ready,
+		trigger:    make(chan struct{}, 1),
+	}
+}
+
+// Start starts sending queued submissions in the background, until ctx is
+// cancelled. It must not be called again until Wait has returned.
+func (o *StashBoxOutbox) Start(ctx context.Context) {
+	o.running.Add(1)
+	go func() {
+		defer o.running.Done()
+
+		ticker := time.NewTicker(stashBoxOutboxInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-o.trigger:
+			}
+
+			o.processDue(ctx)
+		}
+	}()
+}
+
+// Wait waits for the background sending to stop once the context passed to
+// Start is cancelled.
+func (o *StashBoxOutbox) Wait() {
+	o.running.Wait()
+}
+
+// Trigger causes the queued submissions that are due to be sent.
+func (o *StashBoxOutbox) Trigger() {
+	select {
+	case o.trigger <- struct{}{}:
+	default:
+		// already triggered
+	}
+}
+
+// Queue adds the submissions to the queue, to be sent in the background.
+func (o *StashBoxOutbox) Queue(ctx context.Context, submissions []*models.StashBoxSubmission) error {
+	r := o.repository
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		for _, s := range submissions {
+			if err := r.StashBoxSubmission.Create(ctx, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	o.Trigger()
+	return nil
+}
+
+// Submit adds the submission to the queue and attempts to send it
+// immediately. If the attempt fails because stash-box is unreachable, the
+// submission remains queued and no error is returned.
+func (o *StashBoxOutbox) Submit(ctx context.Context, s *models.StashBoxSubmission) error {
+	r := o.repository
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		return r.StashBoxSubmission.Create(ctx, s)
+	}); err != nil {
+		return err
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	err := o.attempt(ctx, s)
+	if err != nil && s.Status == models.StashBoxSubmissionStatusPending {
+		logger.Warnf("Submission to %s failed and will be retried: %v", s.Endpoint, err)
+		return nil
+	}
+
+	return err
+}
+
+// Retry resets the submissions to be sent again immediately.
+func (o *StashBoxOutbox) Retry(ctx context.Context, ids []int) error {
+	r := o.repository
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		submissions, err := r.StashBoxSubmission.FindMany(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, s := range submissions {
+			if s.Status == models.StashBoxSubmissionStatusSubmitted {
+				return fmt.Errorf("submission %d has already been submitted", s.ID)
+			}
+
+			s.Status = models.StashBoxSubmissionStatusPending
+			s.Attempts = 0
+			s.NextAttemptAt = &now
+			s.UpdatedAt = now
+			if err := r.StashBoxSubmission.Update(ctx, s); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	o.Trigger()
+	return nil
+}
+
+func (o *StashBoxOutbox) processDue(ctx context.Context) {
+	if o.ready() != nil {
+		return
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	var due []*models.StashBoxSubmission
+	r := o.repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		due, err = r.StashBoxSubmission.FindDue(ctx, time.Now())
+		return err
+	}); err != nil {
+		logger.Errorf("Error finding queued stash-box submissions: %v", err)
+		return
+	}
+
+	for _, s := range due {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err := o.attempt(ctx, s); err != nil {
+			logger.Warnf("Error sending %s submission to %s: %v", s.Type, s.Endpoint, err)
+		}
+	}
+}
+
+// attempt sends the submission and stores the result.
+func (o *StashBoxOutbox) attempt(ctx context.Context, s *models.StashBoxSubmission) error {
+	remoteID, err := o.send(ctx, s)
+	if ctx.Err() != nil {
+		// stopped while sending, the submission is attempted again later
+		return ctx.Err()
+	}
+
+	now := time.Now()
+	s.Attempts++
+	s.UpdatedAt = now
+	s.NextAttemptAt = nil
+
+	switch {
+	case err == nil:
+		s.Status = models.StashBoxSubmissionStatusSubmitted
+		s.LastError = nil
+		s.RemoteID = remoteID
+	case stashbox.IsRetryableError(err) && s.Attempts < stashBoxSubmissionMaxAttempts:
+		next := now.Add(stashBoxSubmissionBackoff(s.Attempts))
+		s.NextAttemptAt = &next
+	default:
+		s.Status = models.StashBoxSubmissionStatusFailed
+	}
+
+	if err != nil {
+		msg := err.Error()
+		s.LastError = &msg
+	}
+
+	r := o.repository
+	if updateErr := r.WithTxn(ctx, func(ctx context.Context) error {
+		return r.StashBoxSubmission.Update(ctx, s)
+	}); updateErr != nil {
+		return fmt.Errorf("updating submission: %w", updateErr)
+	}
+
+	return err
+}
+
+// stashBoxSubmissionBackoff returns the time to wait before the next attempt,
+// after the given number of failed attempts.
+func stashBoxSubmissionBackoff(attempts int) time.Duration {
+	ret := stashBoxSubmissionMinBackoff
+	for i := 1; i < attempts; i++ {
+		ret *= 2
+		if ret >= stashBoxSubmissionMaxBackoff {
+			return stashBoxSubmissionMaxBackoff
+		}
+	}
+
+	return ret
+}
+
+func (o *StashBoxOutbox) getStashBox(endpoint string) *models.StashBox {
+	for _, b := range o.config.GetStashBoxes() {
+		if b.Endpoint == endpoint {
+			return b
+		}
+	}
+
+	return nil
+}
+
+// send sends the submission to stash-box, returning the ID of the created
+// draft for draft submissions.
+func (o *StashBoxOutbox) send(ctx context.Context, s *models.StashBoxSubmission) (*string, error) {
+	box := o.getStashBox(s.Endpoint)
+	if box == nil {
+		return nil, fmt.Errorf("stash-box endpoint %s is not configured", s.Endpoint)
+	}
+
+	client := stashbox.NewClient(*box, stashbox.NewRepository(o.repository))
+
+	switch s.Type {
+	case models.StashBoxSubmissionTypeFingerprints:
+		if s.SceneID == nil {
+			return nil, errors.New("fingerprint submission has no scene")
+		}
+		return nil, o.sendFingerprints(ctx, client, s.Endpoint, *s.SceneID)
+	case models.StashBoxSubmissionTypeSceneDraft:
+		if s.SceneID == nil {
+			return nil, errors.New("scene draft has no scene")
+		}
+		return o.sendSceneDraft(ctx, client, *s.SceneID)
+	case models.StashBoxSubmissionTypePerformerDraft:
+		if s.PerformerID == nil {
+			return nil, errors.New("performer draft has no performer")
+		}
+		return o.sendPerformerDraft(ctx, client, *s.PerformerID)
+	}
+
+	return nil, fmt.Errorf("unknown submission type %s", s.Type)
+}
+
+func (o *StashBoxOutbox) sendFingerprints(ctx context.Context, client *stashbox.Client, endpoint string, sceneID int) error {
+	fingerprints, err := client.GetSceneFingerprints(ctx, sceneID)
+	if err != nil {
+		return err
+	}
+
+	if len(fingerprints) == 0 {
+		return errNoFingerprints
+	}
+
+	r := o.repository
+	for _, fp := range fingerprints {
+		submitted := models.SubmittedFingerprint{
+			Endpoint:  endpoint,
+			StashID:   fp.StashID,
+			Algorithm: fp.Algorithm,
+			Hash:      fp.Hash,
+		}
+
+		var exists bool
+		if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+			var err error
+			exists, err = r.StashBoxSubmission.IsFingerprintSubmitted(ctx, submitted)
+			return err
+		}); err != nil {
+			return err
+		}
+
+		if exists {
+			continue
+		}
+
+		if err := client.SubmitFingerprint(ctx, fp); err != nil {
+			return err
+		}
+
+		if err := r.WithTxn(ctx, func(ctx context.Context) error {
+			return r.StashBoxSubmission.AddSubmittedFingerprint(ctx, submitted)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *StashBoxOutbox) sendSceneDraft(ctx context.Context, client *stashbox.Client, sceneID int) (*string, error) {
+	var ret *string
+	r := o.repository
+	err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		qb := r.Scene
+		scene, err := qb.Find(ctx, sceneID)
+		if err != nil {
+			return err
+		}
+
+		if scene == nil {
+			return fmt.Errorf("scene with id %d not found", sceneID)
+		}
+
+		cover, err := qb.GetCover(ctx, sceneID)
+		if err != nil {
+			logger.Errorf("Error getting scene cover: %v", err)
+		}
+
+		if err := scene.LoadURLs(ctx, qb); err != nil {
+			return fmt.Errorf("loading scene URLs: %w", err)
+		}
+
+		ret, err = client.SubmitSceneDraft(ctx, scene, cover)
+		return err
+	})
+
+	return ret, err
+}
+
+func (o *StashBoxOutbox) sendPerformerDraft(ctx context.Context, client *stashbox.Client, performerID int) (*string, error) {
+	var ret *string
+	r := o.repository
+	err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		performer, err := r.Performer.Find(ctx, performerID)
+		if err != nil {
+			return err
+		}
+
+		if performer == nil {
+			return fmt.Errorf("performer with id %d not found", performerID)
+		}
+
+		ret, err = client.SubmitPerformerDraft(ctx, performer)
+		return err
+	})
+
+	return ret, err
+}
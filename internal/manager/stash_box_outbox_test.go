@@ -0,0 +1,48 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func TestStashBoxOutboxStop(t *testing.T) {
+	var checks atomic.Int32
+	ready := func() error {
+		checks.Add(1)
+		return errors.New("not ready")
+	}
+
+	o := NewStashBoxOutbox(models.Repository{}, nil, ready)
+
+	waitForChecks := func(n int32) {
+		deadline := time.Now().Add(5 * time.Second)
+		for checks.Load() < n && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	for i := int32(1); i <= 2; i++ {
+		// the outbox can be started again once stopped
+		ctx, cancel := context.WithCancel(context.Background())
+		o.Start(ctx)
+
+		o.Trigger()
+		waitForChecks(i)
+		assert.Equal(t, i, checks.Load())
+
+		cancel()
+		o.Wait()
+	}
+
+	// triggers are not processed once stopped
+	o.Trigger()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(2), checks.Load())
+}
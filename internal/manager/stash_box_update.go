@@ -0,0 +1,329 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/plugin/hook"
+	"github.com/stashapp/stash/pkg/scraper"
+)
+
+// errStashBoxDryRun is used to roll back the transaction used to find the
+// changes a stash-box update would make.
+var errStashBoxDryRun = errors.New("dry run")
+
+// ApplyStashBoxUpdate applies the changes of a pending stash-box update to
+// its object, and marks the update as applied. If fields is not empty, only
+// the changes to those fields are applied. Must be called in a transaction.
+func ApplyStashBoxUpdate(ctx context.Context, r models.Repository, u *models.StashBoxUpdate, fields []string) error {
+	if u.Status != models.StashBoxUpdateStatusPending {
+		return fmt.Errorf("update %d is %s", u.ID, u.Status)
+	}
+
+	diffs := u.Diffs
+	if len(fields) > 0 {
+		include := make(map[string]bool)
+		for _, f := range fields {
+			include[f] = true
+		}
+
+		diffs = nil
+		for _, d := range u.Diffs {
+			if include[d.Field] {
+				diffs = append(diffs, d)
+			}
+		}
+	}
+
+	if err := applyStashBoxDiffs(ctx, r, u.ObjectType, u.ObjectID, u.Endpoint, diffs); err != nil {
+		return err
+	}
+
+	return r.StashBoxUpdate.UpdateStatus(ctx, u.ID, models.StashBoxUpdateStatusApplied)
+}
+
+// applyStashBoxDiffs sets the fields of the object to the stash-box values.
+// Must be called in a transaction.
+func applyStashBoxDiffs(ctx context.Context, r models.Repository, objectType models.ChangeObjectType, id int, endpoint string, diffs []*models.StashBoxFieldDiff) error {
+	if len(diffs) == 0 {
+		return nil
+	}
+
+	ctx = models.WithChangeOrigin(ctx, models.ChangeOrigin{
+		Type: models.ChangeOriginTypeStashBox,
+		ID:   &endpoint,
+	})
+
+	var fields []string
+	for _, d := range diffs {
+		fields = append(fields, d.Field)
+	}
+
+	var hookType hook.TriggerEnum
+
+	switch objectType {
+	case models.ChangeObjectTypeScene:
+		partial := models.NewScenePartial()
+		for _, d := range diffs {
+			if err := partial.SetChangeValue(d.Field, d.NewValue); err != nil {
+				return err
+			}
+		}
+		if _, err := r.Scene.UpdatePartial(ctx, id, partial); err != nil {
+			return err
+		}
+		hookType = hook.SceneUpdatePost
+	case models.ChangeObjectTypePerformer:
+		partial := models.NewPerformerPartial()
+		for _, d := range diffs {
+			if err := partial.SetChangeValue(d.Field, d.NewValue); err != nil {
+				return err
+			}
+		}
+		if _, err := r.Performer.UpdatePartial(ctx, id, partial); err != nil {
+			return err
+		}
+		hookType = hook.PerformerUpdatePost
+	case models.ChangeObjectTypeStudio:
+		partial := models.NewStudioPartial()
+		partial.ID = id
+		for _, d := range diffs {
+			if err := partial.SetChangeValue(d.Field, d.NewValue); err != nil {
+				return err
+			}
+		}
+		if _, err := r.Studio.UpdatePartial(ctx, partial); err != nil {
+			return err
+		}
+		hookType = hook.StudioUpdatePost
+	default:
+		return fmt.Errorf("invalid object type %s", objectType)
+	}
+
+	instance.PluginCache.RegisterPostHooks(ctx, id, hookType, nil, fields)
+	return nil
+}
+
+// stashBoxChangeValues returns the values of the fields of the object that
+// a stash-box update may change.
+func stashBoxChangeValues(ctx context.Context, r models.Repository, objectType models.ChangeObjectType, id int) (models.ChangeValues, error) {
+	switch objectType {
+	case models.ChangeObjectTypeScene:
+		s, err := r.Scene.Find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			return nil, fmt.Errorf("scene with id %d not found", id)
+		}
+		if err := s.LoadRelationships(ctx, r.Scene); err != nil {
+			return nil, err
+		}
+		return s.ChangeValues(), nil
+	case models.ChangeObjectTypePerformer:
+		p, err := r.Performer.Find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if p == nil {
+			return nil, fmt.Errorf("performer with id %d not found", id)
+		}
+		if err := p.LoadRelationships(ctx, r.Performer); err != nil {
+			return nil, err
+		}
+		return p.ChangeValues(), nil
+	case models.ChangeObjectTypeStudio:
+		s, err := r.Studio.Find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			return nil, fmt.Errorf("studio with id %d not found", id)
+		}
+		if err := s.LoadRelationships(ctx, r.Performer); err != nil {
+			return nil, err
+		}
+		return s.ChangeValues(), nil
+	}
+
+	return nil, fmt.Errorf("invalid object type %s", objectType)
+}
+
+// dryRunStashBoxDiffs returns the changes that update makes to the object.
+// The update is made in a transaction that is rolled back.
+func dryRunStashBoxDiffs(ctx context.Context, r models.Repository, objectType models.ChangeObjectType, id int, update func(ctx context.Context) error) ([]*models.StashBoxFieldDiff, error) {
+	var ret []*models.StashBoxFieldDiff
+
+	err := r.WithTxn(ctx, func(ctx context.Context) error {
+		before, err := stashBoxChangeValues(ctx, r, objectType, id)
+		if err != nil {
+			return err
+		}
+
+		if err := update(ctx); err != nil {
+			return err
+		}
+
+		after, err := stashBoxChangeValues(ctx, r, objectType, id)
+		if err != nil {
+			return err
+		}
+
+		changes, err := before.Diff(after)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range changes {
+			// the update time always changes
+			if c.Field == "updated_at" {
+				continue
+			}
+
+			ret = append(ret, &models.StashBoxFieldDiff{
+				Field:    c.Field,
+				OldValue: c.OldValue,
+				NewValue: c.NewValue,
+			})
+		}
+
+		return errStashBoxDryRun
+	})
+
+	if err != nil && !errors.Is(err, errStashBoxDryRun) {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// withoutStashID returns the stash IDs without the stash ID for the endpoint.
+func withoutStashID(stashIDs []models.StashID, endpoint string) []models.StashID {
+	ret := []models.StashID{}
+	for _, id := range stashIDs {
+		if id.Endpoint != endpoint {
+			ret = append(ret, id)
+		}
+	}
+
+	return ret
+}
+
+// stashBoxScenePartial returns the changes that make the scene match the
+// stash-box scene. Studios, performers and tags that do not exist locally
+// are ignored. Performers that are not linked to the stash-box instance are
+// kept, and existing tags are never removed. The relationships of the scene
+// must be loaded.
+func stashBoxScenePartial(ctx context.Context, r models.Repository, s *models.Scene, endpoint string, upstream *scraper.ScrapedScene) (models.ScenePartial, error) {
+	ret := models.NewScenePartial()
+
+	optionalString := func(v *string) models.OptionalString {
+		if v == nil {
+			return models.NewOptionalString("")
+		}
+		return models.NewOptionalString(*v)
+	}
+
+	ret.Title = optionalString(upstream.Title)
+	ret.Code = optionalString(upstream.Code)
+	ret.Details = optionalString(upstream.Details)
+	ret.Director = optionalString(upstream.Director)
+
+	ret.Date = models.OptionalDate{Set: true, Null: true}
+	if upstream.Date != nil {
+		d, err := models.ParseDate(*upstream.Date)
+		if err == nil {
+			ret.Date = models.NewOptionalDate(d)
+		}
+	}
+
+	urls := upstream.URLs
+	if urls == nil {
+		urls = []string{}
+	}
+	ret.URLs = &models.UpdateStrings{
+		Values: urls,
+		Mode:   models.RelationshipUpdateModeSet,
+	}
+
+	if upstream.Studio == nil {
+		ret.StudioID = models.OptionalInt{Set: true, Null: true}
+	} else if upstream.Studio.StoredID != nil {
+		studioID, err := strconv.Atoi(*upstream.Studio.StoredID)
+		if err != nil {
+			return ret, fmt.Errorf("converting studio id: %w", err)
+		}
+		ret.StudioID = models.NewOptionalInt(studioID)
+	}
+
+	// keep the performers that are not linked to the stash-box instance
+	var performerIDs []int
+	for _, id := range s.PerformerIDs.List() {
+		stashIDs, err := r.Performer.GetStashIDs(ctx, id)
+		if err != nil {
+			return ret, err
+		}
+
+		linked := false
+		for _, stashID := range stashIDs {
+			if stashID.Endpoint == endpoint {
+				linked = true
+			}
+		}
+
+		if !linked {
+			performerIDs = append(performerIDs, id)
+		}
+	}
+
+	for _, p := range upstream.Performers {
+		if p.StoredID == nil {
+			continue
+		}
+
+		id, err := strconv.Atoi(*p.StoredID)
+		if err != nil {
+			return ret, fmt.Errorf("converting performer id: %w", err)
+		}
+		performerIDs = append(performerIDs, id)
+	}
+
+	ret.PerformerIDs = &models.UpdateIDs{
+		IDs:  performerIDs,
+		Mode: models.RelationshipUpdateModeSet,
+	}
+
+	var tagIDs []int
+	for _, t := range upstream.Tags {
+		if t.StoredID == nil {
+			continue
+		}
+
+		id, err := strconv.Atoi(*t.StoredID)
+		if err != nil {
+			return ret, fmt.Errorf("converting tag id: %w", err)
+		}
+		tagIDs = append(tagIDs, id)
+	}
+
+	ret.TagIDs = &models.UpdateIDs{
+		IDs:  tagIDs,
+		Mode: models.RelationshipUpdateModeAdd,
+	}
+
+	if upstream.RemoteSiteID != nil {
+		ret.StashIDs = &models.UpdateStashIDs{
+			StashIDs: s.StashIDs.List(),
+			Mode:     models.RelationshipUpdateModeSet,
+		}
+		ret.StashIDs.Set(models.StashID{
+			Endpoint: endpoint,
+			StashID:  *upstream.RemoteSiteID,
+		})
+	}
+
+	return ret, nil
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/stashapp/stash/pkg/match"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 )
 
 type autoTagJob struct {
@@ -27,14 +29,29 @@ type autoTagJob struct {
 
 func (j *autoTagJob) Execute(ctx context.Context, progress *job.Progress) error {
 	begin := time.Now()
+	ctx = models.WithChangeOrigin(ctx, models.ChangeOrigin{Type: models.ChangeOriginTypeAutoTag})
 
 	input := j.input
+
+	rules, err := j.getRules(ctx)
+	if err != nil {
+		logger.Errorf("auto-tag error: %v", err)
+		return nil
+	}
+
+	embedded := j.getEmbeddedMetadata()
+
 	if j.isFileBasedAutoTag(input) {
 		// doing file-based auto-tag
-		j.autoTagFiles(ctx, progress, input.Paths, len(input.Performers) > 0, len(input.Studios) > 0, len(input.Tags) > 0)
+		j.autoTagFiles(ctx, progress, input.Paths, len(input.Performers) > 0, len(input.Studios) > 0, len(input.Tags) > 0, rules, embedded)
 	} else {
 		// doing specific performer/studio/tag auto-tag
 		j.autoTagSpecific(ctx, progress)
+
+		// rules and embedded metadata are applied to files
+		if (rules != nil || embedded != nil) && !job.IsCancelled(ctx) {
+			j.autoTagFiles(ctx, progress, input.Paths, false, false, false, rules, embedded)
+		}
 	}
 
 	logger.Infof("Finished auto-tag after %s", time.Since(begin).String())
@@ -50,12 +67,79 @@ func (j *autoTagJob) isFileBasedAutoTag(input AutoTagMetadataInput) bool {
 	return (len(performerIds) == 0 || performerIds[0] == wildcard) && (len(studioIds) == 0 || studioIds[0] == wildcard) && (len(tagIds) == 0 || tagIds[0] == wildcard)
 }
 
-func (j *autoTagJob) autoTagFiles(ctx context.Context, progress *job.Progress, paths []string, performers, studios, tags bool) {
+// getRules returns the auto-tag rules to apply, or nil if there are none.
+func (j *autoTagJob) getRules(ctx context.Context) (*autotag.RuleSet, error) {
+	const wildcard = "*"
+	ruleIDs := j.input.Rules
+	if len(ruleIDs) == 0 {
+		return nil, nil
+	}
+
+	var rules []*models.AutoTagRule
+	r := j.repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		if ruleIDs[0] == wildcard {
+			rules, err = getEnabledAutoTagRules(ctx, r.AutoTagRule)
+			return err
+		}
+
+		ids, err := stringslice.StringSliceToIntSlice(ruleIDs)
+		if err != nil {
+			return fmt.Errorf("converting rule ids: %w", err)
+		}
+
+		rules, err = r.AutoTagRule.FindMany(ctx, ids)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("getting auto-tag rules: %w", err)
+	}
+
+	ret, err := autotag.NewRuleSet(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	if ret.Empty() {
+		return nil, nil
+	}
+
+	return ret, nil
+}
+
+// getEmbeddedMetadata returns the embedded metadata tagger, or nil if
+// objects should not be populated from embedded file metadata.
+func (j *autoTagJob) getEmbeddedMetadata() *autotag.EmbeddedMetadata {
+	if !j.input.EmbeddedMetadata {
+		return nil
+	}
+
+	return &autotag.EmbeddedMetadata{
+		Mappings:        instance.Config.GetEmbeddedMetadataMappings(),
+		PerformerFinder: j.repository.Performer,
+		TagFinder:       j.repository.Tag,
+	}
+}
+
+func getEnabledAutoTagRules(ctx context.Context, r models.AutoTagRuleReader) ([]*models.AutoTagRule, error) {
+	rules, err := r.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return sliceutil.Filter(rules, func(r *models.AutoTagRule) bool {
+		return r.Enabled
+	}), nil
+}
+
+func (j *autoTagJob) autoTagFiles(ctx context.Context, progress *job.Progress, paths []string, performers, studios, tags bool, rules *autotag.RuleSet, embedded *autotag.EmbeddedMetadata) {
 	t := autoTagFilesTask{
 		paths:      paths,
 		performers: performers,
 		studios:    studios,
 		tags:       tags,
+		rules:      rules,
+		embedded:   embedded,
 		progress:   progress,
 		repository: j.repository,
 		cache:      &j.cache,
@@ -416,6 +500,8 @@ type autoTagFilesTask struct {
 	performers bool
 	studios    bool
 	tags       bool
+	rules      *autotag.RuleSet
+	embedded   *autotag.EmbeddedMetadata
 
 	progress   *job.Progress
 	repository models.Repository
@@ -580,6 +666,8 @@ func (t *autoTagFilesTask) processScenes(ctx context.Context) {
 				performers: t.performers,
 				studios:    t.studios,
 				tags:       t.tags,
+				rules:      t.rules,
+				embedded:   t.embedded,
 				cache:      t.cache,
 			}
 
@@ -643,6 +731,8 @@ func (t *autoTagFilesTask) processImages(ctx context.Context) {
 				performers: t.performers,
 				studios:    t.studios,
 				tags:       t.tags,
+				rules:      t.rules,
+				embedded:   t.embedded,
 				cache:      t.cache,
 			}
 
@@ -706,6 +796,7 @@ func (t *autoTagFilesTask) processGalleries(ctx context.Context) {
 				performers: t.performers,
 				studios:    t.studios,
 				tags:       t.tags,
+				rules:      t.rules,
 				cache:      t.cache,
 			}
 
@@ -759,6 +850,8 @@ type autoTagSceneTask struct {
 	performers bool
 	studios    bool
 	tags       bool
+	rules      *autotag.RuleSet
+	embedded   *autotag.EmbeddedMetadata
 
 	cache *match.Cache
 }
@@ -787,6 +880,16 @@ func (t *autoTagSceneTask) Start(ctx context.Context, wg *sync.WaitGroup) {
 				return fmt.Errorf("tagging scene tags for %s: %v", t.scene.DisplayName(), err)
 			}
 		}
+		if t.embedded != nil {
+			if err := t.embedded.ApplyScene(ctx, t.scene, r.Scene, r.File); err != nil {
+				return fmt.Errorf("populating scene %s from embedded metadata: %v", t.scene.DisplayName(), err)
+			}
+		}
+		if t.rules != nil {
+			if err := t.rules.ApplyScene(ctx, t.scene, r.Scene, r.File); err != nil {
+				return fmt.Errorf("applying auto-tag rules to scene %s: %v", t.scene.DisplayName(), err)
+			}
+		}
 
 		return nil
 	}); err != nil {
@@ -803,6 +906,8 @@ type autoTagImageTask struct {
 	performers bool
 	studios    bool
 	tags       bool
+	rules      *autotag.RuleSet
+	embedded   *autotag.EmbeddedMetadata
 
 	cache *match.Cache
 }
@@ -826,6 +931,16 @@ func (t *autoTagImageTask) Start(ctx context.Context, wg *sync.WaitGroup) {
 				return fmt.Errorf("tagging image tags for %s: %v", t.image.DisplayName(), err)
 			}
 		}
+		if t.embedded != nil {
+			if err := t.embedded.ApplyImage(ctx, t.image, r.Image, r.File); err != nil {
+				return fmt.Errorf("populating image %s from embedded metadata: %v", t.image.DisplayName(), err)
+			}
+		}
+		if t.rules != nil {
+			if err := t.rules.ApplyImage(ctx, t.image, r.Image, r.File); err != nil {
+				return fmt.Errorf("applying auto-tag rules to image %s: %v", t.image.DisplayName(), err)
+			}
+		}
 
 		return nil
 	}); err != nil {
@@ -842,6 +957,7 @@ type autoTagGalleryTask struct {
 	performers bool
 	studios    bool
 	tags       bool
+	rules      *autotag.RuleSet
 
 	cache *match.Cache
 }
@@ -865,6 +981,11 @@ func (t *autoTagGalleryTask) Start(ctx context.Context, wg *sync.WaitGroup) {
 				return fmt.Errorf("tagging gallery tags for %s: %v", t.gallery.DisplayName(), err)
 			}
 		}
+		if t.rules != nil {
+			if err := t.rules.ApplyGallery(ctx, t.gallery, r.Gallery); err != nil {
+				return fmt.Errorf("applying auto-tag rules to gallery %s: %v", t.gallery.DisplayName(), err)
+			}
+		}
 
 		return nil
 	}); err != nil {
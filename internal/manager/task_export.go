@@ -23,6 +23,7 @@ import (
 	"github.com/stashapp/stash/pkg/models/paths"
 	"github.com/stashapp/stash/pkg/movie"
 	"github.com/stashapp/stash/pkg/performer"
+	"github.com/stashapp/stash/pkg/playlist"
 	"github.com/stashapp/stash/pkg/scene"
 	"github.com/stashapp/stash/pkg/sliceutil"
 	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
@@ -46,6 +47,7 @@ type ExportTask struct {
 	tags       *exportSpec
 	studios    *exportSpec
 	galleries  *exportSpec
+	playlists  *exportSpec
 
 	includeDependencies bool
 
@@ -65,6 +67,7 @@ type ExportObjectsInput struct {
 	Tags                *ExportObjectTypeInput `json:"tags"`
 	Movies              *ExportObjectTypeInput `json:"movies"`
 	Galleries           *ExportObjectTypeInput `json:"galleries"`
+	Playlists           *ExportObjectTypeInput `json:"playlists"`
 	IncludeDependencies *bool                  `json:"includeDependencies"`
 }
 
@@ -107,6 +110,7 @@ func CreateExportTask(a models.HashAlgorithm, input ExportObjectsInput) *ExportT
 		tags:                newExportSpec(input.Tags),
 		studios:             newExportSpec(input.Studios),
 		galleries:           newExportSpec(input.Galleries),
+		playlists:           newExportSpec(input.Playlists),
 		includeDependencies: includeDeps,
 	}
 }
@@ -156,6 +160,12 @@ func (t *ExportTask) Start(ctx context.Context, wg *sync.WaitGroup) {
 				t.populateMovieScenes(ctx)
 			}
 
+			// only include playlist scenes and images if includeDependencies
+			// is also set
+			if t.includeDependencies {
+				t.populatePlaylistObjects(ctx)
+			}
+
 			// always export gallery images
 			if !t.images.all {
 				t.populateGalleryImages(ctx)
@@ -169,6 +179,7 @@ func (t *ExportTask) Start(ctx context.Context, wg *sync.WaitGroup) {
 		t.ExportPerformers(ctx, workerCount)
 		t.ExportStudios(ctx, workerCount)
 		t.ExportTags(ctx, workerCount)
+		t.ExportPlaylists(ctx)
 
 		return nil
 	})
@@ -225,6 +236,7 @@ func (t *ExportTask) zipFiles(w io.Writer) error {
 	walkWarn(t.json.json.Movies, t.zipWalkFunc(u.json.Movies, z))
 	walkWarn(t.json.json.Scenes, t.zipWalkFunc(u.json.Scenes, z))
 	walkWarn(t.json.json.Images, t.zipWalkFunc(u.json.Images, z))
+	walkWarn(t.json.json.Playlists, t.zipWalkFunc(u.json.Playlists, z))
 
 	return nil
 }
@@ -306,6 +318,56 @@ func (t *ExportTask) populateMovieScenes(ctx context.Context) {
 	}
 }
 
+func (t *ExportTask) getPlaylists(ctx context.Context) ([]*models.Playlist, error) {
+	reader := t.repository.Playlist
+
+	all := t.full || (t.playlists != nil && t.playlists.all)
+	if all {
+		return reader.All(ctx)
+	} else if t.playlists != nil && len(t.playlists.IDs) > 0 {
+		return reader.FindMany(ctx, t.playlists.IDs)
+	}
+
+	return nil, nil
+}
+
+func (t *ExportTask) populatePlaylistObjects(ctx context.Context) {
+	r := t.repository
+	reader := r.Playlist
+	markerReader := r.SceneMarker
+
+	playlists, err := t.getPlaylists(ctx)
+	if err != nil {
+		logger.Errorf("[playlists] failed to fetch playlists: %v", err)
+	}
+
+	for _, p := range playlists {
+		items, err := reader.GetItems(ctx, p.ID)
+		if err != nil {
+			logger.Errorf("[playlists] <%s> failed to fetch items for playlist: %v", p.Name, err)
+			continue
+		}
+
+		for _, i := range items {
+			switch {
+			case i.SceneID != nil:
+				t.scenes.IDs = sliceutil.AppendUnique(t.scenes.IDs, *i.SceneID)
+			case i.SceneMarkerID != nil:
+				marker, err := markerReader.Find(ctx, *i.SceneMarkerID)
+				if err != nil {
+					logger.Errorf("[playlists] <%s> failed to fetch scene marker for playlist: %v", p.Name, err)
+					continue
+				}
+				if marker != nil {
+					t.scenes.IDs = sliceutil.AppendUnique(t.scenes.IDs, marker.SceneID)
+				}
+			case i.ImageID != nil:
+				t.images.IDs = sliceutil.AppendUnique(t.images.IDs, *i.ImageID)
+			}
+		}
+	}
+}
+
 func (t *ExportTask) populateGalleryImages(ctx context.Context) {
 	r := t.repository
 	reader := r.Gallery
@@ -1156,3 +1218,41 @@ func (t *ExportTask) exportMovie(ctx context.Context, wg *sync.WaitGroup, jobCha
 		}
 	}
 }
+
+func (t *ExportTask) ExportPlaylists(ctx context.Context) {
+	r := t.repository
+
+	playlists, err := t.getPlaylists(ctx)
+	if err != nil {
+		logger.Errorf("[playlists] failed to fetch playlists: %v", err)
+	}
+
+	logger.Info("[playlists] exporting")
+	startTime := time.Now()
+
+	readers := playlist.ExportReaders{
+		Items:        r.Playlist,
+		Scenes:       r.Scene,
+		SceneMarkers: r.SceneMarker,
+		Images:       r.Image,
+	}
+
+	for i, p := range playlists {
+		index := i + 1
+		logger.Progressf("[playlists] %d of %d", index, len(playlists))
+
+		newPlaylistJSON, err := playlist.ToJSON(ctx, readers, p)
+		if err != nil {
+			logger.Errorf("[playlists] <%s> error getting playlist JSON: %v", p.Name, err)
+			continue
+		}
+
+		fn := newPlaylistJSON.Filename()
+
+		if err := t.json.savePlaylist(fn, newPlaylistJSON); err != nil {
+			logger.Errorf("[playlists] <%s> failed to save json: %v", p.Name, err)
+		}
+	}
+
+	logger.Infof("[playlists] export complete in %s.", time.Since(startTime))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/stashapp/stash/internal/identify"
@@ -14,6 +15,7 @@ import (
 	"github.com/stashapp/stash/pkg/scraper"
 	"github.com/stashapp/stash/pkg/scraper/stashbox"
 	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 var ErrInput = errors.New("invalid request input")
@@ -47,12 +49,13 @@ func (j *IdentifyJob) Execute(ctx context.Context, progress *job.Progress) error
 		return err
 	}
 
-	// if scene ids provided, use those
+	// if scene, image, gallery, performer or movie ids provided, use those
 	// otherwise, batch query for all scenes - ordering by path
 	// don't use a transaction to query scenes
 	r := instance.Repository
 	if err := r.WithDB(ctx, func(ctx context.Context) error {
-		if len(j.input.SceneIDs) == 0 {
+		if len(j.input.SceneIDs) == 0 && len(j.input.ImageIDs) == 0 && len(j.input.GalleryIDs) == 0 &&
+			len(j.input.PerformerIDs) == 0 && len(j.input.MovieIDs) == 0 {
 			return j.identifyAllScenes(ctx, sources)
 		}
 
@@ -61,7 +64,37 @@ func (j *IdentifyJob) Execute(ctx context.Context, progress *job.Progress) error
 			return fmt.Errorf("invalid scene IDs: %w", err)
 		}
 
-		progress.SetTotal(len(sceneIDs))
+		imageIDs, err := stringslice.StringSliceToIntSlice(j.input.ImageIDs)
+		if err != nil {
+			return fmt.Errorf("invalid image IDs: %w", err)
+		}
+
+		if utils.IsTrue(j.input.DryRun) && (len(imageIDs) > 0 || len(j.input.GalleryIDs) > 0 ||
+			len(j.input.PerformerIDs) > 0 || len(j.input.MovieIDs) > 0) {
+			// proposals are only supported for scenes
+			logger.Warn("Dry run only identifies scenes. Other objects will not be identified.")
+			imageIDs = nil
+			j.input.GalleryIDs = nil
+			j.input.PerformerIDs = nil
+			j.input.MovieIDs = nil
+		}
+
+		galleryIDs, err := stringslice.StringSliceToIntSlice(j.input.GalleryIDs)
+		if err != nil {
+			return fmt.Errorf("invalid gallery IDs: %w", err)
+		}
+
+		performerIDs, err := stringslice.StringSliceToIntSlice(j.input.PerformerIDs)
+		if err != nil {
+			return fmt.Errorf("invalid performer IDs: %w", err)
+		}
+
+		movieIDs, err := stringslice.StringSliceToIntSlice(j.input.MovieIDs)
+		if err != nil {
+			return fmt.Errorf("invalid movie IDs: %w", err)
+		}
+
+		progress.SetTotal(len(sceneIDs) + len(imageIDs) + len(galleryIDs) + len(performerIDs) + len(movieIDs))
 		for _, id := range sceneIDs {
 			if job.IsCancelled(ctx) {
 				break
@@ -81,9 +114,77 @@ func (j *IdentifyJob) Execute(ctx context.Context, progress *job.Progress) error
 			j.identifyScene(ctx, scene, sources)
 		}
 
+		for _, id := range imageIDs {
+			if job.IsCancelled(ctx) {
+				break
+			}
+
+			image, err := r.Image.Find(ctx, id)
+			if err != nil {
+				return fmt.Errorf("finding image id %d: %w", id, err)
+			}
+
+			if image == nil {
+				return fmt.Errorf("image with id %d not found", id)
+			}
+
+			j.identifyImage(ctx, image, sources)
+		}
+
+		for _, id := range galleryIDs {
+			if job.IsCancelled(ctx) {
+				break
+			}
+
+			gallery, err := r.Gallery.Find(ctx, id)
+			if err != nil {
+				return fmt.Errorf("finding gallery id %d: %w", id, err)
+			}
+
+			if gallery == nil {
+				return fmt.Errorf("gallery with id %d not found", id)
+			}
+
+			j.identifyGallery(ctx, gallery, sources)
+		}
+
+		for _, id := range performerIDs {
+			if job.IsCancelled(ctx) {
+				break
+			}
+
+			performer, err := r.Performer.Find(ctx, id)
+			if err != nil {
+				return fmt.Errorf("finding performer id %d: %w", id, err)
+			}
+
+			if performer == nil {
+				return fmt.Errorf("performer with id %d not found", id)
+			}
+
+			j.identifyPerformer(ctx, performer, sources)
+		}
+
+		for _, id := range movieIDs {
+			if job.IsCancelled(ctx) {
+				break
+			}
+
+			movie, err := r.Movie.Find(ctx, id)
+			if err != nil {
+				return fmt.Errorf("finding movie id %d: %w", id, err)
+			}
+
+			if movie == nil {
+				return fmt.Errorf("movie with id %d not found", id)
+			}
+
+			j.identifyMovie(ctx, movie, sources)
+		}
+
 		return nil
 	}); err != nil {
-		return fmt.Errorf("error encountered while identifying scenes: %w", err)
+		return fmt.Errorf("error encountered while identifying: %w", err)
 	}
 
 	return nil
@@ -133,9 +234,18 @@ func (j *IdentifyJob) identifyScene(ctx context.Context, s *models.Scene, source
 		return
 	}
 
+	r := instance.Repository
+
+	// files and performers are used to score matches
+	if err := s.LoadFiles(ctx, r.Scene); err != nil {
+		logger.Errorf("Error loading files for %s: %v", s.Path, err)
+	}
+	if err := s.LoadPerformerIDs(ctx, r.Scene); err != nil {
+		logger.Errorf("Error loading performers for %s: %v", s.Path, err)
+	}
+
 	var taskError error
 	j.progress.ExecuteTask("Identifying "+s.Path, func() {
-		r := instance.Repository
 		task := identify.SceneIdentifier{
 			TxnManager:         r.TxnManager,
 			SceneReaderUpdater: r.Scene,
@@ -146,6 +256,12 @@ func (j *IdentifyJob) identifyScene(ctx context.Context, s *models.Scene, source
 			DefaultOptions:              j.input.Options,
 			Sources:                     sources,
 			SceneUpdatePostHookExecutor: j.postHookExecutor,
+
+			MergeResults:    utils.IsTrue(j.input.MergeResults),
+			FieldPriorities: j.input.FieldPriorities,
+
+			DryRun:          utils.IsTrue(j.input.DryRun),
+			ProposalCreator: r.IdentifyProposal,
 		}
 
 		taskError = task.Identify(ctx, s)
@@ -158,6 +274,123 @@ func (j *IdentifyJob) identifyScene(ctx context.Context, s *models.Scene, source
 	j.progress.Increment()
 }
 
+func (j *IdentifyJob) identifyImage(ctx context.Context, i *models.Image, sources []identify.ScraperSource) {
+	if job.IsCancelled(ctx) {
+		return
+	}
+
+	var taskError error
+	j.progress.ExecuteTask("Identifying "+i.Path, func() {
+		r := instance.Repository
+		task := identify.ImageIdentifier{
+			TxnManager:         r.TxnManager,
+			ImageReaderUpdater: r.Image,
+			StudioReaderWriter: r.Studio,
+			PerformerCreator:   r.Performer,
+			TagFinderCreator:   r.Tag,
+
+			DefaultOptions:   j.input.Options,
+			Sources:          sources,
+			PostHookExecutor: instance.PluginCache,
+		}
+
+		taskError = task.Identify(ctx, i)
+	})
+
+	if taskError != nil {
+		logger.Errorf("Error encountered identifying %s: %v", i.Path, taskError)
+	}
+
+	j.progress.Increment()
+}
+
+func (j *IdentifyJob) identifyGallery(ctx context.Context, g *models.Gallery, sources []identify.ScraperSource) {
+	if job.IsCancelled(ctx) {
+		return
+	}
+
+	var taskError error
+	j.progress.ExecuteTask("Identifying "+g.DisplayName(), func() {
+		r := instance.Repository
+		task := identify.GalleryIdentifier{
+			TxnManager:           r.TxnManager,
+			GalleryReaderUpdater: r.Gallery,
+			StudioReaderWriter:   r.Studio,
+			PerformerCreator:     r.Performer,
+			TagFinderCreator:     r.Tag,
+
+			DefaultOptions:   j.input.Options,
+			Sources:          sources,
+			PostHookExecutor: instance.PluginCache,
+		}
+
+		taskError = task.Identify(ctx, g)
+	})
+
+	if taskError != nil {
+		logger.Errorf("Error encountered identifying %s: %v", g.DisplayName(), taskError)
+	}
+
+	j.progress.Increment()
+}
+
+func (j *IdentifyJob) identifyPerformer(ctx context.Context, p *models.Performer, sources []identify.ScraperSource) {
+	if job.IsCancelled(ctx) {
+		return
+	}
+
+	var taskError error
+	j.progress.ExecuteTask("Identifying "+p.Name, func() {
+		r := instance.Repository
+		task := identify.PerformerIdentifier{
+			TxnManager:             r.TxnManager,
+			PerformerReaderUpdater: r.Performer,
+			TagFinderCreator:       r.Tag,
+
+			DefaultOptions:   j.input.Options,
+			Sources:          sources,
+			PostHookExecutor: instance.PluginCache,
+		}
+
+		taskError = task.Identify(ctx, p)
+	})
+
+	if taskError != nil {
+		logger.Errorf("Error encountered identifying %s: %v", p.Name, taskError)
+	}
+
+	j.progress.Increment()
+}
+
+func (j *IdentifyJob) identifyMovie(ctx context.Context, m *models.Movie, sources []identify.ScraperSource) {
+	if job.IsCancelled(ctx) {
+		return
+	}
+
+	var taskError error
+	j.progress.ExecuteTask("Identifying "+m.Name, func() {
+		r := instance.Repository
+		task := identify.MovieIdentifier{
+			TxnManager:         r.TxnManager,
+			MovieReaderUpdater: r.Movie,
+			StudioReaderWriter: r.Studio,
+			TagFinderCreator:   r.Tag,
+
+			DefaultOptions:   j.input.Options,
+			Sources:          sources,
+			PostHookExecutor: instance.PluginCache,
+		}
+
+		taskError = task.Identify(ctx, m)
+	})
+
+	if taskError != nil {
+		logger.Errorf("Error encountered identifying %s: %v", m.Name, taskError)
+	}
+
+	j.progress.Increment()
+}
+
 func (j *IdentifyJob) getSources() ([]identify.ScraperSource, error) {
 	var ret []identify.ScraperSource
 	for _, source := range j.input.Sources {
@@ -171,6 +404,7 @@ func (j *IdentifyJob) getSources() ([]identify.ScraperSource, error) {
 		if stashBox != nil {
 			stashboxRepository := stashbox.NewRepository(instance.Repository)
 			src = identify.ScraperSource{
+				ID:   stashBox.Endpoint,
 				Name: "stash-box: " + stashBox.Endpoint,
 				Scraper: stashboxSource{
 					stashbox.NewClient(*stashBox, stashboxRepository),
@@ -185,6 +419,7 @@ func (j *IdentifyJob) getSources() ([]identify.ScraperSource, error) {
 				return nil, fmt.Errorf("%w: scraper with id %q", models.ErrNotFound, scraperID)
 			}
 			src = identify.ScraperSource{
+				ID:   scraperID,
 				Name: s.Name,
 				Scraper: scraperSource{
 					cache:     instance.ScraperCache,
@@ -262,6 +497,62 @@ func (s stashboxSource) ScrapeScenes(ctx context.Context, sceneID int) ([]*scrap
 	return nil, nil
 }
 
+// ScrapePerformers finds the performer on stash-box using its stash ID for the
+// endpoint. Performers without a stash ID are searched for by name, and only
+// exact name matches are returned.
+func (s stashboxSource) ScrapePerformers(ctx context.Context, performerID int) ([]*models.ScrapedPerformer, error) {
+	var performer *models.Performer
+	r := instance.Repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		performer, err = r.Performer.Find(ctx, performerID)
+		if err != nil {
+			return err
+		}
+
+		if performer == nil {
+			return fmt.Errorf("performer with id %d not found", performerID)
+		}
+
+		return performer.LoadStashIDs(ctx, r.Performer)
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, stashID := range performer.StashIDs.List() {
+		if stashID.Endpoint != s.endpoint {
+			continue
+		}
+
+		result, err := s.FindStashBoxPerformerByID(ctx, stashID.StashID)
+		if err != nil {
+			return nil, fmt.Errorf("error querying stash-box using performer ID %d: %w", performerID, err)
+		}
+
+		if result != nil {
+			return []*models.ScrapedPerformer{result}, nil
+		}
+
+		return nil, nil
+	}
+
+	results, err := s.FindStashBoxPerformersByPerformerNames(ctx, []string{strconv.Itoa(performerID)})
+	if err != nil {
+		return nil, fmt.Errorf("error querying stash-box using performer ID %d: %w", performerID, err)
+	}
+
+	var ret []*models.ScrapedPerformer
+	for _, result := range results {
+		for _, p := range result {
+			if p.Name != nil && strings.EqualFold(*p.Name, performer.Name) {
+				ret = append(ret, p)
+			}
+		}
+	}
+
+	return ret, nil
+}
+
 func (s stashboxSource) String() string {
 	return fmt.Sprintf("stash-box %s", s.endpoint)
 }
@@ -289,6 +580,94 @@ func (s scraperSource) ScrapeScenes(ctx context.Context, sceneID int) ([]*scrape
 	return nil, errors.New("could not convert content to scene")
 }
 
+func (s scraperSource) ScrapeImages(ctx context.Context, imageID int) ([]*scraper.ScrapedImage, error) {
+	content, err := s.cache.ScrapeID(ctx, s.scraperID, imageID, scraper.ScrapeContentTypeImage)
+	if err != nil {
+		// scrapers that don't support images are skipped
+		if errors.Is(err, scraper.ErrNotSupported) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// don't try to convert nil return value
+	if content == nil {
+		return nil, nil
+	}
+
+	if image, ok := content.(scraper.ScrapedImage); ok {
+		return []*scraper.ScrapedImage{&image}, nil
+	}
+
+	return nil, errors.New("could not convert content to image")
+}
+
+func (s scraperSource) ScrapeGalleries(ctx context.Context, galleryID int) ([]*scraper.ScrapedGallery, error) {
+	content, err := s.cache.ScrapeID(ctx, s.scraperID, galleryID, scraper.ScrapeContentTypeGallery)
+	if err != nil {
+		// scrapers that don't support galleries are skipped
+		if errors.Is(err, scraper.ErrNotSupported) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// don't try to convert nil return value
+	if content == nil {
+		return nil, nil
+	}
+
+	if gallery, ok := content.(scraper.ScrapedGallery); ok {
+		return []*scraper.ScrapedGallery{&gallery}, nil
+	}
+
+	return nil, errors.New("could not convert content to gallery")
+}
+
+func (s scraperSource) ScrapePerformers(ctx context.Context, performerID int) ([]*models.ScrapedPerformer, error) {
+	content, err := s.cache.ScrapeID(ctx, s.scraperID, performerID, scraper.ScrapeContentTypePerformer)
+	if err != nil {
+		// scrapers that don't support performers are skipped
+		if errors.Is(err, scraper.ErrNotSupported) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// don't try to convert nil return value
+	if content == nil {
+		return nil, nil
+	}
+
+	if performer, ok := content.(models.ScrapedPerformer); ok {
+		return []*models.ScrapedPerformer{&performer}, nil
+	}
+
+	return nil, errors.New("could not convert content to performer")
+}
+
+func (s scraperSource) ScrapeMovies(ctx context.Context, movieID int) ([]*models.ScrapedMovie, error) {
+	content, err := s.cache.ScrapeID(ctx, s.scraperID, movieID, scraper.ScrapeContentTypeMovie)
+	if err != nil {
+		// scrapers that don't support movies are skipped
+		if errors.Is(err, scraper.ErrNotSupported) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// don't try to convert nil return value
+	if content == nil {
+		return nil, nil
+	}
+
+	if movie, ok := content.(models.ScrapedMovie); ok {
+		return []*models.ScrapedMovie{&movie}, nil
+	}
+
+	return nil, errors.New("could not convert content to movie")
+}
+
 func (s scraperSource) String() string {
 	return fmt.Sprintf("scraper %s", s.scraperID)
 }
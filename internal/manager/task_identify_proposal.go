@@ -0,0 +1,92 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/internal/identify"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// AcceptIdentifyProposalsJob applies pending identify proposals to their
+// scenes.
+type AcceptIdentifyProposalsJob struct {
+	ProposalIDs []int
+}
+
+func (j *AcceptIdentifyProposalsJob) Execute(ctx context.Context, progress *job.Progress) error {
+	r := instance.Repository
+
+	progress.SetTotal(len(j.ProposalIDs))
+	for _, id := range j.ProposalIDs {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return nil
+		}
+
+		progress.ExecuteTask(fmt.Sprintf("Applying proposal %d", id), func() {
+			if err := j.acceptProposal(ctx, r, id); err != nil {
+				logger.Errorf("Error applying identify proposal %d: %v", id, err)
+			}
+		})
+
+		progress.Increment()
+	}
+
+	return nil
+}
+
+func (j *AcceptIdentifyProposalsJob) acceptProposal(ctx context.Context, r models.Repository, id int) error {
+	var (
+		proposal *models.IdentifyProposal
+		scene    *models.Scene
+	)
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		proposal, err = r.IdentifyProposal.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if proposal == nil {
+			return fmt.Errorf("proposal with id %d not found", id)
+		}
+
+		if proposal.Status != models.IdentifyProposalStatusPending {
+			return fmt.Errorf("proposal is %s", proposal.Status)
+		}
+
+		scene, err = r.Scene.Find(ctx, proposal.SceneID)
+		if err != nil {
+			return err
+		}
+
+		if scene == nil {
+			return fmt.Errorf("scene with id %d not found", proposal.SceneID)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	task := identify.SceneIdentifier{
+		TxnManager:         r.TxnManager,
+		SceneReaderUpdater: r.Scene,
+		StudioReaderWriter: r.Studio,
+		PerformerCreator:   r.Performer,
+		TagFinderCreator:   r.Tag,
+
+		SceneUpdatePostHookExecutor: instance.PluginCache,
+	}
+
+	if err := task.ApplyProposal(ctx, scene, proposal); err != nil {
+		return err
+	}
+
+	return r.WithTxn(ctx, func(ctx context.Context) error {
+		return r.IdentifyProposal.UpdateStatus(ctx, id, models.IdentifyProposalStatusAccepted)
+	})
+}
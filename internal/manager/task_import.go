@@ -20,6 +20,7 @@ import (
 	"github.com/stashapp/stash/pkg/models/paths"
 	"github.com/stashapp/stash/pkg/movie"
 	"github.com/stashapp/stash/pkg/performer"
+	"github.com/stashapp/stash/pkg/playlist"
 	"github.com/stashapp/stash/pkg/scene"
 	"github.com/stashapp/stash/pkg/studio"
 	"github.com/stashapp/stash/pkg/tag"
@@ -133,6 +134,7 @@ func (t *ImportTask) Start(ctx context.Context) {
 
 	t.ImportScenes(ctx)
 	t.ImportImages(ctx)
+	t.ImportPlaylists(ctx)
 }
 
 func (t *ImportTask) unzipFile() error {
@@ -730,3 +732,49 @@ func (t *ImportTask) ImportImages(ctx context.Context) {
 
 	logger.Info("[images] import complete")
 }
+
+func (t *ImportTask) ImportPlaylists(ctx context.Context) {
+	logger.Info("[playlists] importing")
+
+	path := t.json.json.Playlists
+	files, err := os.ReadDir(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Errorf("[playlists] failed to read playlists directory: %v", err)
+		}
+
+		return
+	}
+
+	r := t.repository
+
+	for i, fi := range files {
+		index := i + 1
+		playlistJSON, err := jsonschema.LoadPlaylistFile(filepath.Join(path, fi.Name()))
+		if err != nil {
+			logger.Errorf("[playlists] failed to read json: %v", err)
+			continue
+		}
+
+		logger.Progressf("[playlists] %d of %d", index, len(files))
+
+		if err := r.WithTxn(ctx, func(ctx context.Context) error {
+			playlistImporter := &playlist.Importer{
+				ReaderWriter:        r.Playlist,
+				FileFinder:          r.File,
+				SceneFinder:         r.Scene,
+				SceneMarkerFinder:   r.SceneMarker,
+				ImageFinder:         r.Image,
+				Input:               *playlistJSON,
+				MissingRefBehaviour: t.MissingRefBehaviour,
+			}
+
+			return performImport(ctx, playlistImporter, t.DuplicateBehaviour)
+		}); err != nil {
+			logger.Errorf("[playlists] <%s> import failed: %v", fi.Name(), err)
+			continue
+		}
+	}
+
+	logger.Info("[playlists] import complete")
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/stashapp/stash/internal/autotag"
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/file/video"
@@ -21,6 +22,7 @@ import (
 	"github.com/stashapp/stash/pkg/models/paths"
 	"github.com/stashapp/stash/pkg/scene"
 	"github.com/stashapp/stash/pkg/scene/generate"
+	"github.com/stashapp/stash/pkg/sidecar"
 	"github.com/stashapp/stash/pkg/txn"
 )
 
@@ -43,6 +45,9 @@ func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) error {
 		return nil
 	}
 
+	// changes made by the scan itself are not recorded in the change log
+	ctx = models.WithChangeOrigin(ctx, models.ChangeOrigin{Type: models.ChangeOriginTypeScan})
+
 	sp := getScanPaths(input.Paths)
 	paths := make([]string, len(sp))
 	for i, p := range sp {
@@ -63,9 +68,32 @@ func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) error {
 		minModTime = *j.input.Filter.MinModTime
 	}
 
-	j.scanner.Scan(ctx, getScanHandlers(j.input, taskQueue, progress), file.ScanOptions{
+	var rules *autotag.RuleSet
+	if input.ScanApplyAutoTagRules {
+		var err error
+		rules, err = getScanAutoTagRules(ctx, repo)
+		if err != nil {
+			logger.Errorf("Error getting auto-tag rules: %v", err)
+		}
+	}
+
+	// enabled filename parser templates are applied to new scenes
+	templates, err := getFilenameParserTemplates(ctx, repo, nil)
+	if err != nil {
+		logger.Errorf("Error getting filename parser templates: %v", err)
+	}
+
+	var sidecars *sidecarCollector
+	if input.ScanSidecarFiles {
+		sidecars = &sidecarCollector{}
+	}
+
+	scanFilter := newScanFilter(c, repo, minModTime)
+	scanFilter.sidecars = sidecars
+
+	j.scanner.Scan(ctx, getScanHandlers(j.input, taskQueue, progress, rules, templates, start), file.ScanOptions{
 		Paths:                  paths,
-		ScanFilters:            []file.PathFilter{newScanFilter(c, repo, minModTime)},
+		ScanFilters:            []file.PathFilter{scanFilter},
 		ZipFileExtensions:      cfg.GetGalleryExtensions(),
 		ParallelTasks:          cfg.GetParallelTasksWithAutoDetection(),
 		HandlerRequiredFilters: []file.Filter{newHandlerRequiredFilter(cfg, repo)},
@@ -79,6 +107,14 @@ func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) error {
 		return nil
 	}
 
+	if sidecars != nil && len(sidecars.paths) > 0 {
+		// timestamps are stored with second precision
+		applier := newSidecarApplier(repo, start.Truncate(time.Second), input.Rescan)
+		progress.ExecuteTask("Applying sidecar files", func() {
+			applier.apply(ctx, sidecars.paths)
+		})
+	}
+
 	elapsed := time.Since(start)
 	logger.Info(fmt.Sprintf("Scan finished (%s)", elapsed))
 
@@ -248,6 +284,8 @@ type scanFilter struct {
 	txnManager     txn.Manager
 	FileFinder     models.FileFinder
 	CaptionUpdater video.CaptionUpdater
+	// sidecars records the sidecar files found. Sidecar files are ignored if nil.
+	sidecars *sidecarCollector
 
 	stashPaths        config.StashConfigs
 	generatedPath     string
@@ -300,6 +338,14 @@ func (f *scanFilter) Accept(ctx context.Context, path string, info fs.FileInfo)
 		return false
 	}
 
+	// handle sidecar files
+	if f.sidecars != nil && !info.IsDir() && sidecar.IsSidecar(path) {
+		// sidecar files are not included in the file scan. They are
+		// applied once the scan is complete.
+		f.sidecars.add(path)
+		return false
+	}
+
 	if !info.IsDir() && !isVideoFile && !isImageFile && !isZipFile {
 		logger.Debugf("Skipping %s as it does not match any known file extensions", path)
 		return false
@@ -353,7 +399,26 @@ func galleryFileFilter(ctx context.Context, f models.File) bool {
 	return isZip(f.Base().Basename)
 }
 
-func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progress *job.Progress) []file.Handler {
+// getScanAutoTagRules returns the enabled auto-tag rules, or nil if there are
+// none.
+func getScanAutoTagRules(ctx context.Context, repo models.Repository) (*autotag.RuleSet, error) {
+	var rules []*models.AutoTagRule
+	if err := repo.WithReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		rules, err = getEnabledAutoTagRules(ctx, repo.AutoTagRule)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	return autotag.NewRuleSet(rules)
+}
+
+func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progress *job.Progress, rules *autotag.RuleSet, templates *filenameParserTemplates, scanStart time.Time) []file.Handler {
 	mgr := GetInstance()
 	c := mgr.Config
 	r := mgr.Repository
@@ -367,6 +432,7 @@ func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progre
 				GalleryFinder:  r.Gallery,
 				ScanGenerator: &imageGenerators{
 					input:              options,
+					rules:              rules,
 					taskQueue:          taskQueue,
 					progress:           progress,
 					paths:              mgr.Paths,
@@ -398,6 +464,9 @@ func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progre
 				PluginCache:    pluginCache,
 				ScanGenerator: &sceneGenerators{
 					input:               options,
+					rules:               rules,
+					templates:           templates,
+					scanStart:           scanStart,
 					taskQueue:           taskQueue,
 					progress:            progress,
 					paths:               mgr.Paths,
@@ -413,6 +482,7 @@ func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progre
 
 type imageGenerators struct {
 	input     ScanMetadataInput
+	rules     *autotag.RuleSet
 	taskQueue *job.TaskQueue
 	progress  *job.Progress
 
@@ -432,6 +502,10 @@ func (g *imageGenerators) Generate(ctx context.Context, i *models.Image, f model
 	ii := *i
 	ii.Files = models.NewRelatedFiles([]models.File{f})
 
+	if g.rules != nil {
+		g.applyAutoTagRules(ctx, i.ID)
+	}
+
 	if t.ScanGenerateThumbnails {
 		// this should be quick, so always generate sequentially
 		taskThumbnail := GenerateImageThumbnailTask{
@@ -468,6 +542,10 @@ func (g *imageGenerators) Generate(ctx context.Context, i *models.Image, f model
 
 type sceneGenerators struct {
 	input     ScanMetadataInput
+	rules     *autotag.RuleSet
+	templates *filenameParserTemplates
+	// scenes created after scanStart are new scenes
+	scanStart time.Time
 	taskQueue *job.TaskQueue
 	progress  *job.Progress
 
@@ -476,6 +554,21 @@ type sceneGenerators struct {
 	sequentialScanning  bool
 }
 
+func (g *imageGenerators) applyAutoTagRules(ctx context.Context, imageID int) {
+	r := GetInstance().Repository
+	ctx = models.WithChangeOrigin(ctx, models.ChangeOrigin{Type: models.ChangeOriginTypeAutoTag})
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		i, err := r.Image.Find(ctx, imageID)
+		if err != nil || i == nil {
+			return err
+		}
+
+		return g.rules.ApplyImage(ctx, i, r.Image, r.File)
+	}); err != nil {
+		logger.Errorf("Error applying auto-tag rules to image %d: %v", imageID, err)
+	}
+}
+
 func (g *sceneGenerators) Generate(ctx context.Context, s *models.Scene, f *models.VideoFile) error {
 	const overwrite = false
 
@@ -485,6 +578,14 @@ func (g *sceneGenerators) Generate(ctx context.Context, s *models.Scene, f *mode
 
 	mgr := GetInstance()
 
+	if g.templates != nil && !s.CreatedAt.Before(g.scanStart) {
+		g.templates.applyScene(ctx, mgr.Repository, s.ID, path)
+	}
+
+	if g.rules != nil {
+		g.applyAutoTagRules(ctx, s.ID)
+	}
+
 	if t.ScanGenerateSprites {
 		progress.AddTotal(1)
 		spriteFn := func(ctx context.Context) {
@@ -572,3 +673,18 @@ func (g *sceneGenerators) Generate(ctx context.Context, s *models.Scene, f *mode
 
 	return nil
 }
+
+func (g *sceneGenerators) applyAutoTagRules(ctx context.Context, sceneID int) {
+	r := GetInstance().Repository
+	ctx = models.WithChangeOrigin(ctx, models.ChangeOrigin{Type: models.ChangeOriginTypeAutoTag})
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		s, err := r.Scene.Find(ctx, sceneID)
+		if err != nil || s == nil {
+			return err
+		}
+
+		return g.rules.ApplyScene(ctx, s, r.Scene, r.File)
+	}); err != nil {
+		logger.Errorf("Error applying auto-tag rules to scene %d: %v", sceneID, err)
+	}
+}
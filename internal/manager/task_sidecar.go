@@ -0,0 +1,301 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sidecar"
+)
+
+// sidecarCollector records the sidecar files found during a scan. Sidecar
+// files are applied after the scan, once the scenes, images and galleries
+// they apply to have been created.
+type sidecarCollector struct {
+	mutex sync.Mutex
+	paths []string
+}
+
+func (c *sidecarCollector) add(path string) {
+	// files in zip files are not read
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.paths = append(c.paths, path)
+}
+
+// sidecarApplier applies the sidecar files found during a scan. A sidecar
+// file is applied to an object if the object was created during the scan, or
+// if the sidecar file was modified after the object was last updated.
+type sidecarApplier struct {
+	repository models.Repository
+	importer   *sidecar.Importer
+	// scanStart is the time that the scan started
+	scanStart time.Time
+	// force applies the sidecar files regardless of modification time
+	force bool
+}
+
+func newSidecarApplier(repo models.Repository, scanStart time.Time, force bool) *sidecarApplier {
+	return &sidecarApplier{
+		repository: repo,
+		importer: &sidecar.Importer{
+			PerformerWriter: repo.Performer,
+			StudioWriter:    repo.Studio,
+			TagWriter:       repo.Tag,
+		},
+		scanStart: scanStart,
+		force:     force,
+	}
+}
+
+func (a *sidecarApplier) apply(ctx context.Context, paths []string) {
+	ctx = models.WithChangeOrigin(ctx, models.ChangeOrigin{Type: models.ChangeOriginTypeImport})
+
+	for _, path := range paths {
+		if job.IsCancelled(ctx) {
+			return
+		}
+
+		if err := a.applyFile(ctx, path); err != nil {
+			logger.Errorf("Error applying sidecar file %s: %v", path, err)
+		}
+	}
+}
+
+func (a *sidecarApplier) required(modTime time.Time, createdAt time.Time, updatedAt time.Time) bool {
+	return a.force || !createdAt.Before(a.scanStart) || modTime.After(updatedAt)
+}
+
+func (a *sidecarApplier) applyFile(ctx context.Context, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	m, err := sidecar.Read(path)
+	if err != nil {
+		return err
+	}
+
+	modTime := info.ModTime()
+	r := a.repository
+
+	return r.WithTxn(ctx, func(ctx context.Context) error {
+		if sidecar.IsGallerySidecar(path) {
+			return a.applyFolderGallery(ctx, path, modTime, m)
+		}
+
+		// find the media files with the same basename in the same folder
+		dir := filepath.Dir(path)
+		basename := sidecar.MediaBasename(path)
+		files, err := r.File.FindAllByPath(ctx, filepath.Join(dir, basename)+".*")
+		if err != nil {
+			return fmt.Errorf("finding files for %s: %w", path, err)
+		}
+
+		for _, f := range files {
+			fp := f.Base().Path
+			if filepath.Dir(fp) != dir || sidecar.MediaBasename(fp) != basename || sidecar.IsSidecar(fp) {
+				continue
+			}
+
+			if err := a.applyMediaFile(ctx, f, modTime, m); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (a *sidecarApplier) applyFolderGallery(ctx context.Context, path string, modTime time.Time, m *sidecar.Metadata) error {
+	r := a.repository
+	folder, err := r.Folder.FindByPath(ctx, filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("finding folder for %s: %w", path, err)
+	}
+	if folder == nil {
+		return nil
+	}
+
+	galleries, err := r.Gallery.FindByFolderID(ctx, folder.ID)
+	if err != nil {
+		return fmt.Errorf("finding galleries for %s: %w", path, err)
+	}
+
+	return a.applyGalleries(ctx, galleries, modTime, m)
+}
+
+func (a *sidecarApplier) applyMediaFile(ctx context.Context, f models.File, modTime time.Time, m *sidecar.Metadata) error {
+	r := a.repository
+	fileID := f.Base().ID
+
+	if _, isVideo := f.(*models.VideoFile); isVideo {
+		scenes, err := r.Scene.FindByFileID(ctx, fileID)
+		if err != nil {
+			return fmt.Errorf("finding scenes for file %s: %w", f.Base().Path, err)
+		}
+
+		for _, s := range scenes {
+			if !a.required(modTime, s.CreatedAt, s.UpdatedAt) {
+				continue
+			}
+
+			if err := a.importer.ApplyScene(ctx, s.ID, m, r.Scene); err != nil {
+				return err
+			}
+			logger.Infof("Applied sidecar metadata to scene %s", s.DisplayName())
+		}
+	}
+
+	images, err := r.Image.FindByFileID(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("finding images for file %s: %w", f.Base().Path, err)
+	}
+
+	for _, i := range images {
+		if !a.required(modTime, i.CreatedAt, i.UpdatedAt) {
+			continue
+		}
+
+		if err := a.importer.ApplyImage(ctx, i.ID, m, r.Image); err != nil {
+			return err
+		}
+		logger.Infof("Applied sidecar metadata to image %s", i.DisplayName())
+	}
+
+	galleries, err := r.Gallery.FindByFileID(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("finding galleries for file %s: %w", f.Base().Path, err)
+	}
+
+	return a.applyGalleries(ctx, galleries, modTime, m)
+}
+
+func (a *sidecarApplier) applyGalleries(ctx context.Context, galleries []*models.Gallery, modTime time.Time, m *sidecar.Metadata) error {
+	r := a.repository
+	for _, g := range galleries {
+		if !a.required(modTime, g.CreatedAt, g.UpdatedAt) {
+			continue
+		}
+
+		if err := a.importer.ApplyGallery(ctx, g.ID, m, r.Gallery); err != nil {
+			return err
+		}
+		logger.Infof("Applied sidecar metadata to gallery %s", g.DisplayName())
+	}
+
+	return nil
+}
+
+type exportSidecarsJob struct {
+	repository models.Repository
+	// sceneIDs are the scenes to export. All scenes are exported if empty.
+	sceneIDs  []int
+	overwrite bool
+}
+
+func (j *exportSidecarsJob) Execute(ctx context.Context, progress *job.Progress) error {
+	r := j.repository
+
+	var scenes []*models.Scene
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		if len(j.sceneIDs) > 0 {
+			scenes, err = r.Scene.FindMany(ctx, j.sceneIDs)
+		} else {
+			scenes, err = r.Scene.All(ctx)
+		}
+		return err
+	}); err != nil {
+		return fmt.Errorf("finding scenes: %w", err)
+	}
+
+	progress.SetTotal(len(scenes))
+
+	written := 0
+	for _, s := range scenes {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return nil
+		}
+
+		progress.ExecuteTask("Exporting sidecar for "+s.DisplayName(), func() {
+			ok, err := j.exportScene(ctx, s)
+			if err != nil {
+				logger.Errorf("Error exporting sidecar for scene %s: %v", s.DisplayName(), err)
+			} else if ok {
+				written++
+			}
+		})
+
+		progress.Increment()
+	}
+
+	logger.Infof("Wrote %d NFO sidecar files", written)
+	return nil
+}
+
+// exportScene writes the NFO sidecar file of the scene next to its primary
+// file. It returns false if the file was not written.
+func (j *exportSidecarsJob) exportScene(ctx context.Context, s *models.Scene) (bool, error) {
+	r := j.repository
+
+	var (
+		path string
+		m    *sidecar.Metadata
+	)
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		if err := s.LoadPrimaryFile(ctx, r.File); err != nil {
+			return err
+		}
+
+		f := s.Files.Primary()
+		// files in zip files cannot have sidecar files
+		if f == nil || f.ZipFileID != nil {
+			return nil
+		}
+		path = sidecar.Path(f.Path, sidecar.NFOExt)
+
+		var err error
+		m, err = sidecar.SceneMetadata(ctx, s, r.Scene, r.Studio, r.Performer, r.Tag)
+		return err
+	}); err != nil {
+		return false, err
+	}
+
+	if path == "" {
+		return false, nil
+	}
+
+	if !j.overwrite {
+		exists, _ := fsutil.FileExists(path)
+		if exists {
+			logger.Debugf("Skipping existing sidecar file %s", path)
+			return false, nil
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := sidecar.WriteNFO(&buf, m); err != nil {
+		return false, err
+	}
+
+	if err := fsutil.WriteFile(path, buf.Bytes()); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/stashapp/stash/internal/identify"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/performer"
+	"github.com/stashapp/stash/pkg/scraper"
 	"github.com/stashapp/stash/pkg/scraper/stashbox"
 	"github.com/stashapp/stash/pkg/studio"
 )
@@ -17,6 +19,7 @@ type StashBoxTagTaskType int
 const (
 	Performer StashBoxTagTaskType = iota
 	Studio
+	Scene
 )
 
 type StashBoxBatchTagTask struct {
@@ -24,6 +27,7 @@ type StashBoxBatchTagTask struct {
 	name           *string
 	performer      *models.Performer
 	studio         *models.Studio
+	scene          *models.Scene
 	refresh        bool
 	createParent   bool
 	excludedFields []string
@@ -36,6 +40,8 @@ func (t *StashBoxBatchTagTask) Start(ctx context.Context) {
 		t.stashBoxPerformerTag(ctx)
 	case Studio:
 		t.stashBoxStudioTag(ctx)
+	case Scene:
+		t.stashBoxSceneTag(ctx)
 	default:
 		logger.Errorf("Error starting batch task, unknown task_type %d", t.taskType)
 	}
@@ -58,6 +64,8 @@ func (t *StashBoxBatchTagTask) Description() string {
 			name = t.studio.Name
 		}
 		return fmt.Sprintf("Tagging studio %s from stash-box", name)
+	} else if t.taskType == Scene {
+		return fmt.Sprintf("Tagging scene %s from stash-box", t.scene.Path)
 	}
 	return fmt.Sprintf("Unknown tagging task type %d from stash-box", t.taskType)
 }
@@ -461,3 +469,140 @@ func (t *StashBoxBatchTagTask) processParentStudio(ctx context.Context, parent *
 		return err
 	}
 }
+
+// stashBoxSceneTagFields are the scene fields set when tagging scenes.
+var stashBoxSceneTagFields = []string{"title", "code", "details", "director", "date", "url", "studio", "performers", "tags", "stash_ids"}
+
+// stashBoxSceneResults is an identify source that returns scenes that have
+// already been found on stash-box.
+type stashBoxSceneResults []*scraper.ScrapedScene
+
+func (r stashBoxSceneResults) ScrapeScenes(ctx context.Context, sceneID int) ([]*scraper.ScrapedScene, error) {
+	return r, nil
+}
+
+func (t *StashBoxBatchTagTask) stashBoxSceneTag(ctx context.Context) {
+	results, err := t.findStashBoxScene(ctx)
+	if err != nil {
+		logger.Errorf("Error fetching scene data from stash-box: %v", err)
+		return
+	}
+
+	if len(results) == 0 {
+		logger.Infof("No match found for %s", t.scene.Path)
+		return
+	}
+
+	r := instance.Repository
+	task := identify.SceneIdentifier{
+		TxnManager:         r.TxnManager,
+		SceneReaderUpdater: r.Scene,
+		StudioReaderWriter: r.Studio,
+		PerformerCreator:   r.Performer,
+		TagFinderCreator:   r.Tag,
+
+		DefaultOptions: t.sceneTagOptions(),
+		Sources: []identify.ScraperSource{
+			{
+				ID:         t.box.Endpoint,
+				Name:       t.box.Endpoint,
+				Scraper:    stashBoxSceneResults(results),
+				RemoteSite: t.box.Endpoint,
+			},
+		},
+		SceneUpdatePostHookExecutor: instance.PluginCache,
+	}
+
+	if err := task.Identify(ctx, t.scene); err != nil {
+		logger.Errorf("Failed to update scene %s: %v", t.scene.Path, err)
+	}
+}
+
+// findStashBoxScene finds the scene on stash-box using its stash ID when
+// refreshing, and using its fingerprints otherwise.
+func (t *StashBoxBatchTagTask) findStashBoxScene(ctx context.Context) ([]*scraper.ScrapedScene, error) {
+	r := instance.Repository
+	client := stashbox.NewClient(*t.box, stashbox.NewRepository(r))
+
+	var remoteID string
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		qb := r.Scene
+
+		// files and performers are used to score the match
+		if err := t.scene.LoadFiles(ctx, qb); err != nil {
+			return err
+		}
+		if err := t.scene.LoadPerformerIDs(ctx, qb); err != nil {
+			return err
+		}
+		if err := t.scene.LoadStashIDs(ctx, qb); err != nil {
+			return err
+		}
+
+		if stashID := t.scene.StashIDs.ForEndpoint(t.box.Endpoint); stashID != nil {
+			remoteID = stashID.StashID
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if !t.refresh {
+		return client.FindStashBoxSceneByFingerprints(ctx, t.scene.ID)
+	}
+
+	if remoteID == "" {
+		return nil, nil
+	}
+
+	scene, err := client.FindStashBoxSceneByID(ctx, remoteID)
+	if err != nil || scene == nil {
+		return nil, err
+	}
+
+	return []*scraper.ScrapedScene{scene}, nil
+}
+
+// sceneTagOptions returns the identify options used to update scenes.
+// Existing values are overwritten when refreshing, and excluded fields are
+// not set.
+func (t *StashBoxBatchTagTask) sceneTagOptions() *identify.MetadataOptions {
+	excluded := map[string]bool{}
+	for _, field := range t.excludedFields {
+		excluded[field] = true
+	}
+
+	strategy := identify.FieldStrategyMerge
+	if t.refresh {
+		strategy = identify.FieldStrategyOverwrite
+	}
+
+	createMissing := true
+	var fieldOptions []*identify.FieldOptions
+	for _, field := range stashBoxSceneTagFields {
+		o := &identify.FieldOptions{
+			Field:    field,
+			Strategy: strategy,
+		}
+		if excluded[field] {
+			o.Strategy = identify.FieldStrategyIgnore
+		}
+		if field == "studio" || field == "performers" || field == "tags" {
+			o.CreateMissing = &createMissing
+		}
+		fieldOptions = append(fieldOptions, o)
+	}
+
+	setCoverImage := !excluded["cover_image"]
+	boolTrue := true
+	boolFalse := false
+
+	return &identify.MetadataOptions{
+		FieldOptions:             fieldOptions,
+		SetCoverImage:            &setCoverImage,
+		SetOrganized:             &boolFalse,
+		IncludeMalePerformers:    &boolTrue,
+		SkipMultipleMatches:      &boolTrue,
+		SkipSingleNamePerformers: &boolFalse,
+	}
+}
@@ -0,0 +1,446 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/scraper/stashbox"
+)
+
+type StashBoxFieldStrategyInput struct {
+	Field    string                       `json:"field"`
+	Strategy models.StashBoxFieldStrategy `json:"strategy"`
+}
+
+type StashBoxUpdateCheckInput struct {
+	// Endpoint of the stash-box instance to check
+	StashBoxEndpoint string `json:"stash_box_endpoint"`
+	// Types of objects to check. Checks scenes, performers and studios if empty.
+	ObjectTypes []models.ChangeObjectType `json:"object_types"`
+	// Check objects that have not changed upstream since the last check
+	Force bool `json:"force"`
+	// Strategy for fields without a strategy. Defaults to REVIEW.
+	DefaultStrategy *models.StashBoxFieldStrategy `json:"default_strategy"`
+	FieldStrategies []*StashBoxFieldStrategyInput `json:"field_strategies"`
+}
+
+func (i StashBoxUpdateCheckInput) objectTypes() ([]models.ChangeObjectType, error) {
+	if len(i.ObjectTypes) == 0 {
+		return []models.ChangeObjectType{
+			models.ChangeObjectTypeScene,
+			models.ChangeObjectTypePerformer,
+			models.ChangeObjectTypeStudio,
+		}, nil
+	}
+
+	for _, t := range i.ObjectTypes {
+		switch t {
+		case models.ChangeObjectTypeScene, models.ChangeObjectTypePerformer, models.ChangeObjectTypeStudio:
+		default:
+			return nil, fmt.Errorf("stash-box update check is not supported for %s objects", t)
+		}
+	}
+
+	return i.ObjectTypes, nil
+}
+
+func (i StashBoxUpdateCheckInput) strategy(field string) models.StashBoxFieldStrategy {
+	for _, s := range i.FieldStrategies {
+		if s.Field == field {
+			return s.Strategy
+		}
+	}
+
+	if i.DefaultStrategy != nil {
+		return *i.DefaultStrategy
+	}
+
+	return models.StashBoxFieldStrategyReview
+}
+
+// StashBoxUpdateCheck queues a job that checks the linked stash-box objects
+// for upstream changes.
+func (s *Manager) StashBoxUpdateCheck(ctx context.Context, box *models.StashBox, input StashBoxUpdateCheckInput) (int, error) {
+	objectTypes, err := input.objectTypes()
+	if err != nil {
+		return 0, err
+	}
+
+	j := &StashBoxUpdateCheckJob{
+		Repository:  s.Repository,
+		Box:         box,
+		Input:       input,
+		objectTypes: objectTypes,
+	}
+
+	return s.JobManager.Add(ctx, "Checking stash-box for updates...", j), nil
+}
+
+// StashBoxUpdateCheckJob finds the changes made on a stash-box instance to
+// the objects linked to it. Changes are applied or stored for review
+// according to the field strategies of the input.
+type StashBoxUpdateCheckJob struct {
+	Repository models.Repository
+	Box        *models.StashBox
+	Input      StashBoxUpdateCheckInput
+
+	objectTypes []models.ChangeObjectType
+	client      *stashbox.Client
+}
+
+type stashBoxLinkedObject struct {
+	objectType models.ChangeObjectType
+	id         int
+}
+
+func (j *StashBoxUpdateCheckJob) Execute(ctx context.Context, progress *job.Progress) error {
+	j.client = stashbox.NewClient(*j.Box, stashbox.NewRepository(j.Repository))
+
+	objects, err := j.linkedObjects(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Checking %d objects for stash-box updates from %s", len(objects), j.Box.Endpoint)
+	progress.SetTotal(len(objects))
+
+	for _, o := range objects {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return nil
+		}
+
+		progress.ExecuteTask(fmt.Sprintf("Checking %s %d", o.objectType, o.id), func() {
+			if err := j.check(ctx, o); err != nil {
+				logger.Errorf("Error checking %s %d for stash-box updates: %v", o.objectType, o.id, err)
+			}
+		})
+
+		progress.Increment()
+	}
+
+	return nil
+}
+
+func (j *StashBoxUpdateCheckJob) linkedObjects(ctx context.Context) ([]stashBoxLinkedObject, error) {
+	var ret []stashBoxLinkedObject
+	r := j.Repository
+	endpoint := j.Box.Endpoint
+
+	for _, t := range j.objectTypes {
+		t := t
+		add := func(id int) {
+			ret = append(ret, stashBoxLinkedObject{objectType: t, id: id})
+		}
+
+		if err := r.WithDB(ctx, func(ctx context.Context) error {
+			switch t {
+			case models.ChangeObjectTypeScene:
+				sceneFilter := &models.SceneFilterType{
+					StashIDEndpoint: &models.StashIDCriterionInput{
+						Endpoint: &endpoint,
+						Modifier: models.CriterionModifierNotNull,
+					},
+				}
+
+				return scene.BatchProcess(ctx, r.Scene, sceneFilter, &models.FindFilterType{}, func(s *models.Scene) error {
+					add(s.ID)
+					return nil
+				})
+			case models.ChangeObjectTypePerformer:
+				performers, err := r.Performer.FindByStashIDStatus(ctx, true, endpoint)
+				if err != nil {
+					return err
+				}
+				for _, p := range performers {
+					add(p.ID)
+				}
+			case models.ChangeObjectTypeStudio:
+				studios, err := r.Studio.FindByStashIDStatus(ctx, true, endpoint)
+				if err != nil {
+					return err
+				}
+				for _, s := range studios {
+					add(s.ID)
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("finding linked %s objects: %w", t, err)
+		}
+	}
+
+	return ret, nil
+}
+
+func (j *StashBoxUpdateCheckJob) getStashIDs(ctx context.Context, o stashBoxLinkedObject) ([]models.StashID, error) {
+	r := j.Repository
+	switch o.objectType {
+	case models.ChangeObjectTypeScene:
+		return r.Scene.GetStashIDs(ctx, o.id)
+	case models.ChangeObjectTypePerformer:
+		return r.Performer.GetStashIDs(ctx, o.id)
+	case models.ChangeObjectTypeStudio:
+		return r.Studio.GetStashIDs(ctx, o.id)
+	}
+
+	return nil, fmt.Errorf("invalid object type %s", o.objectType)
+}
+
+func (j *StashBoxUpdateCheckJob) getStatus(ctx context.Context, objectType models.ChangeObjectType, stashID string) (*stashbox.UpstreamStatus, error) {
+	switch objectType {
+	case models.ChangeObjectTypeScene:
+		return j.client.GetSceneStatus(ctx, stashID)
+	case models.ChangeObjectTypePerformer:
+		return j.client.GetPerformerStatus(ctx, stashID)
+	case models.ChangeObjectTypeStudio:
+		return j.client.GetStudioStatus(ctx, stashID)
+	}
+
+	return nil, fmt.Errorf("invalid object type %s", objectType)
+}
+
+func (j *StashBoxUpdateCheckJob) check(ctx context.Context, o stashBoxLinkedObject) error {
+	r := j.Repository
+	endpoint := j.Box.Endpoint
+
+	var (
+		stashID  string
+		previous *models.StashBoxUpstreamState
+	)
+
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		stashIDs, err := j.getStashIDs(ctx, o)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range stashIDs {
+			if id.Endpoint == endpoint {
+				stashID = id.StashID
+			}
+		}
+
+		previous, err = r.StashBoxUpdate.GetUpstreamState(ctx, o.objectType, o.id, endpoint)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if stashID == "" {
+		// unlinked since the job started
+		return nil
+	}
+
+	status, err := j.getStatus(ctx, o.objectType, stashID)
+	if err != nil {
+		return err
+	}
+
+	state := models.StashBoxUpstreamState{
+		ObjectType: o.objectType,
+		ObjectID:   o.id,
+		Endpoint:   endpoint,
+		StashID:    stashID,
+		Deleted:    status == nil || status.Deleted,
+		CheckedAt:  time.Now(),
+	}
+
+	updateType := models.StashBoxUpdateTypeUpdated
+	var mergedStashID *string
+	switch {
+	case state.Deleted:
+		updateType = models.StashBoxUpdateTypeDeleted
+	case status.ID != stashID:
+		updateType = models.StashBoxUpdateTypeMerged
+		mergedStashID = &status.ID
+	}
+
+	if status != nil {
+		// stored timestamps do not have sub-second precision
+		updated := status.Updated.Local().Truncate(time.Second)
+		state.UpstreamUpdatedAt = &updated
+	}
+
+	if !j.Input.Force && !upstreamChanged(previous, state) {
+		return r.WithTxn(ctx, func(ctx context.Context) error {
+			return r.StashBoxUpdate.SetUpstreamState(ctx, state)
+		})
+	}
+
+	update, err := j.updateFunc(ctx, o, updateType, status)
+	if err != nil {
+		return err
+	}
+
+	var diffs []*models.StashBoxFieldDiff
+	if update != nil {
+		diffs, err = dryRunStashBoxDiffs(ctx, r, o.objectType, o.id, update)
+		if err != nil {
+			return err
+		}
+	}
+
+	var apply, review []*models.StashBoxFieldDiff
+	for _, d := range diffs {
+		switch j.Input.strategy(d.Field) {
+		case models.StashBoxFieldStrategyApply:
+			apply = append(apply, d)
+		case models.StashBoxFieldStrategyReview:
+			review = append(review, d)
+		}
+	}
+
+	return r.WithTxn(ctx, func(ctx context.Context) error {
+		if err := applyStashBoxDiffs(ctx, r, o.objectType, o.id, endpoint, apply); err != nil {
+			return err
+		}
+
+		// merges and deletions are always reported
+		if len(review) > 0 || updateType != models.StashBoxUpdateTypeUpdated {
+			now := time.Now()
+			if err := r.StashBoxUpdate.Create(ctx, &models.StashBoxUpdate{
+				ObjectType:        o.objectType,
+				ObjectID:          o.id,
+				Endpoint:          endpoint,
+				StashID:           stashID,
+				Type:              updateType,
+				MergedStashID:     mergedStashID,
+				Diffs:             review,
+				Status:            models.StashBoxUpdateStatusPending,
+				UpstreamUpdatedAt: state.UpstreamUpdatedAt,
+				CreatedAt:         now,
+				UpdatedAt:         now,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return r.StashBoxUpdate.SetUpstreamState(ctx, state)
+	})
+}
+
+// upstreamChanged returns true if the stash-box object may have changed
+// since the previous check.
+func upstreamChanged(previous *models.StashBoxUpstreamState, current models.StashBoxUpstreamState) bool {
+	if previous == nil {
+		return true
+	}
+
+	if previous.StashID != current.StashID || previous.Deleted != current.Deleted {
+		return true
+	}
+
+	if previous.UpstreamUpdatedAt == nil || current.UpstreamUpdatedAt == nil {
+		return previous.UpstreamUpdatedAt != current.UpstreamUpdatedAt
+	}
+
+	return current.UpstreamUpdatedAt.After(*previous.UpstreamUpdatedAt)
+}
+
+// updateFunc returns a function that updates the object to match the
+// stash-box object. Returns nil if there is nothing to update.
+func (j *StashBoxUpdateCheckJob) updateFunc(ctx context.Context, o stashBoxLinkedObject, updateType models.StashBoxUpdateType, status *stashbox.UpstreamStatus) (func(ctx context.Context) error, error) {
+	r := j.Repository
+	endpoint := j.Box.Endpoint
+
+	if updateType == models.StashBoxUpdateTypeDeleted {
+		// unlink the object from the stash-box instance
+		return func(ctx context.Context) error {
+			stashIDs, err := j.getStashIDs(ctx, o)
+			if err != nil {
+				return err
+			}
+
+			update := &models.UpdateStashIDs{
+				StashIDs: withoutStashID(stashIDs, endpoint),
+				Mode:     models.RelationshipUpdateModeSet,
+			}
+
+			switch o.objectType {
+			case models.ChangeObjectTypeScene:
+				partial := models.NewScenePartial()
+				partial.StashIDs = update
+				_, err = r.Scene.UpdatePartial(ctx, o.id, partial)
+			case models.ChangeObjectTypePerformer:
+				partial := models.NewPerformerPartial()
+				partial.StashIDs = update
+				_, err = r.Performer.UpdatePartial(ctx, o.id, partial)
+			case models.ChangeObjectTypeStudio:
+				partial := models.NewStudioPartial()
+				partial.ID = o.id
+				partial.StashIDs = update
+				_, err = r.Studio.UpdatePartial(ctx, partial)
+			}
+			return err
+		}, nil
+	}
+
+	switch o.objectType {
+	case models.ChangeObjectTypeScene:
+		upstream, err := j.client.FindStashBoxSceneByID(ctx, status.ID)
+		if err != nil || upstream == nil {
+			return nil, err
+		}
+
+		return func(ctx context.Context) error {
+			s, err := r.Scene.Find(ctx, o.id)
+			if err != nil {
+				return err
+			}
+			if s == nil {
+				return fmt.Errorf("scene with id %d not found", o.id)
+			}
+			if err := s.LoadRelationships(ctx, r.Scene); err != nil {
+				return err
+			}
+
+			partial, err := stashBoxScenePartial(ctx, r, s, endpoint, upstream)
+			if err != nil {
+				return err
+			}
+
+			_, err = r.Scene.UpdatePartial(ctx, o.id, partial)
+			return err
+		}, nil
+	case models.ChangeObjectTypePerformer:
+		upstream, err := j.client.FindStashBoxPerformerByID(ctx, status.ID)
+		if err != nil || upstream == nil {
+			return nil, err
+		}
+
+		return func(ctx context.Context) error {
+			stashIDs, err := r.Performer.GetStashIDs(ctx, o.id)
+			if err != nil {
+				return err
+			}
+
+			partial := upstream.ToPartial(endpoint, nil, stashIDs)
+			_, err = r.Performer.UpdatePartial(ctx, o.id, partial)
+			return err
+		}, nil
+	case models.ChangeObjectTypeStudio:
+		upstream, err := j.client.FindStashBoxStudio(ctx, status.ID)
+		if err != nil || upstream == nil {
+			return nil, err
+		}
+
+		return func(ctx context.Context) error {
+			stashIDs, err := r.Studio.GetStashIDs(ctx, o.id)
+			if err != nil {
+				return err
+			}
+
+			partial := upstream.ToPartial(fmt.Sprint(o.id), endpoint, nil, stashIDs)
+			_, err = r.Studio.UpdatePartial(ctx, partial)
+			return err
+		}, nil
+	}
+
+	return nil, fmt.Errorf("invalid object type %s", o.objectType)
+}
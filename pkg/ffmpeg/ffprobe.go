@@ -89,6 +89,8 @@ type VideoFile struct {
 	Title     string
 	Comment   string
 	Container string
+	// Tags are the container level tags of the file, such as title and artist.
+	Tags map[string]string
 	// FileDuration is the declared (meta-data) duration of the *file*.
 	// In most cases (sprites, previews, etc.) we actually care about the duration of the video stream specifically,
 	// because those two can differ slightly (e.g. audio stream longer than the video stream, making the whole file
@@ -160,7 +162,38 @@ func (f *FFProbe) NewVideoFile(videoPath string) (*VideoFile, error) {
 		return nil, fmt.Errorf("error unmarshalling video data for <%s>: %s", videoPath, err.Error())
 	}
 
-	return parse(videoPath, probeJSON)
+	ret, err := parse(videoPath, probeJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Tags = parseFormatTags(out)
+	return ret, nil
+}
+
+// parseFormatTags returns all of the container level tags of the ffprobe
+// output. FFProbeJSON only includes the tags that are used by stash.
+func parseFormatTags(out []byte) map[string]string {
+	var probeJSON struct {
+		Format struct {
+			Tags map[string]interface{} `json:"tags"`
+		} `json:"format"`
+	}
+
+	if err := json.Unmarshal(out, &probeJSON); err != nil {
+		return nil
+	}
+
+	ret := make(map[string]string)
+	for k, v := range probeJSON.Format.Tags {
+		if s, ok := v.(string); ok {
+			ret[k] = s
+		} else {
+			ret[k] = fmt.Sprint(v)
+		}
+	}
+
+	return ret
 }
 
 // GetReadFrameCount counts the actual frames of the video file.
@@ -0,0 +1,479 @@
+package image
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// maximum number of bytes read from the start of an image file when
+// searching for embedded metadata
+const metadataReadLimit = 4 << 20
+
+// separator used to join metadata values that are repeated
+const metadataValueSeparator = "; "
+
+var (
+	jpegSOI = []byte{0xff, 0xd8}
+
+	exifHeader      = []byte("Exif\x00\x00")
+	xmpHeader       = []byte("http://ns.adobe.com/xap/1.0/\x00")
+	photoshopHeader = []byte("Photoshop 3.0\x00")
+
+	xmpPacketStart = []byte("<x:xmpmeta")
+	xmpPacketEnd   = []byte("</x:xmpmeta>")
+)
+
+// extractMetadata returns the EXIF, IPTC and XMP metadata embedded in the
+// image file. Keys are prefixed with the metadata type, for example
+// exif:artist or xmp:title.
+func extractMetadata(fs models.FS, path string) (map[string]string, error) {
+	r, err := fs.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading image file %q: %w", path, err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(io.LimitReader(r, metadataReadLimit))
+	if err != nil {
+		return nil, fmt.Errorf("reading image file %q: %w", path, err)
+	}
+
+	ret := make(map[string]string)
+	if bytes.HasPrefix(data, jpegSOI) {
+		parseJPEGMetadata(data, ret)
+	} else if packet := findXMPPacket(data); packet != nil {
+		// other formats store XMP as a plain text packet
+		parseXMP(packet, ret)
+	}
+
+	return ret, nil
+}
+
+func setMetadata(m map[string]string, key string, value string) {
+	value = strings.TrimSpace(value)
+	if value != "" {
+		m[key] = value
+	}
+}
+
+func appendMetadata(m map[string]string, key string, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+
+	if existing := m[key]; existing != "" {
+		value = existing + metadataValueSeparator + value
+	}
+	m[key] = value
+}
+
+// parseJPEGMetadata reads the APP segments of a JPEG file, up to the start
+// of the image data.
+func parseJPEGMetadata(data []byte, m map[string]string) {
+	const (
+		markerAPP1  = 0xe1
+		markerAPP13 = 0xed
+		markerSOS   = 0xda
+	)
+
+	pos := len(jpegSOI)
+	for pos+4 <= len(data) {
+		if data[pos] != 0xff {
+			return
+		}
+
+		marker := data[pos+1]
+		if marker == markerSOS {
+			return
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		start := pos + 4
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return
+		}
+
+		segment := data[start:end]
+		switch {
+		case marker == markerAPP1 && bytes.HasPrefix(segment, exifHeader):
+			parseExif(segment[len(exifHeader):], m)
+		case marker == markerAPP1 && bytes.HasPrefix(segment, xmpHeader):
+			parseXMP(segment[len(xmpHeader):], m)
+		case marker == markerAPP13 && bytes.HasPrefix(segment, photoshopHeader):
+			parsePhotoshopResources(segment[len(photoshopHeader):], m)
+		}
+
+		pos = end
+	}
+}
+
+func findXMPPacket(data []byte) []byte {
+	start := bytes.Index(data, xmpPacketStart)
+	if start == -1 {
+		return nil
+	}
+
+	end := bytes.Index(data[start:], xmpPacketEnd)
+	if end == -1 {
+		return nil
+	}
+
+	return data[start : start+end+len(xmpPacketEnd)]
+}
+
+// EXIF tags that are stored as file metadata
+var exifTags = map[uint16]string{
+	0x010e: "exif:image_description",
+	0x010f: "exif:make",
+	0x0110: "exif:model",
+	0x0132: "exif:date_time",
+	0x013b: "exif:artist",
+	0x8298: "exif:copyright",
+	0x9003: "exif:date_time_original",
+	0x9004: "exif:date_time_digitized",
+	0x9286: "exif:user_comment",
+	0x9c9b: "exif:xp_title",
+	0x9c9c: "exif:xp_comment",
+	0x9c9d: "exif:xp_author",
+	0x9c9e: "exif:xp_keywords",
+	0x9c9f: "exif:xp_subject",
+}
+
+const (
+	exifIFDPointerTag = 0x8769
+	exifUserComment   = 0x9286
+)
+
+type exifReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+// parseExif reads the tags of the first IFD and the EXIF IFD of TIFF
+// formatted EXIF data.
+func parseExif(data []byte, m map[string]string) {
+	if len(data) < 8 {
+		return
+	}
+
+	r := exifReader{data: data}
+	switch string(data[:2]) {
+	case "II":
+		r.order = binary.LittleEndian
+	case "MM":
+		r.order = binary.BigEndian
+	default:
+		return
+	}
+
+	if r.order.Uint16(data[2:]) != 42 {
+		return
+	}
+
+	exifIFD := r.readIFD(int(r.order.Uint32(data[4:])), m)
+	if exifIFD > 0 {
+		r.readIFD(exifIFD, m)
+	}
+}
+
+// readIFD reads the tags of the IFD at offset into m. Returns the offset of
+// the EXIF IFD if the IFD points to it.
+func (r exifReader) readIFD(offset int, m map[string]string) int {
+	const entrySize = 12
+
+	if offset <= 0 || offset+2 > len(r.data) {
+		return 0
+	}
+
+	exifIFD := 0
+	count := int(r.order.Uint16(r.data[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*entrySize
+		if entry+entrySize > len(r.data) {
+			break
+		}
+
+		tag := r.order.Uint16(r.data[entry:])
+		if tag == exifIFDPointerTag {
+			exifIFD = int(r.order.Uint32(r.data[entry+8:]))
+			continue
+		}
+
+		key, ok := exifTags[tag]
+		if !ok {
+			continue
+		}
+
+		value := r.value(entry)
+		if value == nil {
+			continue
+		}
+
+		switch {
+		case tag == exifUserComment:
+			setMetadata(m, key, r.userComment(value))
+		case tag >= 0x9c9b && tag <= 0x9c9f:
+			// Windows XP tags are UTF-16LE regardless of the byte order
+			setMetadata(m, key, decodeUTF16(value, binary.LittleEndian))
+		default:
+			setMetadata(m, key, string(bytes.TrimRight(value, "\x00")))
+		}
+	}
+
+	return exifIFD
+}
+
+// value returns the raw value of the IFD entry. Only byte sized types are
+// supported.
+func (r exifReader) value(entry int) []byte {
+	const (
+		typeByte      = 1
+		typeASCII     = 2
+		typeUndefined = 7
+	)
+
+	typ := r.order.Uint16(r.data[entry+2:])
+	if typ != typeByte && typ != typeASCII && typ != typeUndefined {
+		return nil
+	}
+
+	count := int(r.order.Uint32(r.data[entry+4:]))
+	if count <= 4 {
+		return r.data[entry+8 : entry+8+count]
+	}
+
+	offset := int(r.order.Uint32(r.data[entry+8:]))
+	if offset < 0 || offset+count > len(r.data) {
+		return nil
+	}
+
+	return r.data[offset : offset+count]
+}
+
+// userComment decodes the EXIF user comment, which starts with an eight
+// byte character code.
+func (r exifReader) userComment(value []byte) string {
+	if len(value) < 8 {
+		return ""
+	}
+
+	code := string(bytes.TrimRight(value[:8], "\x00 "))
+	text := value[8:]
+	switch code {
+	case "ASCII", "":
+		return string(bytes.TrimRight(text, "\x00 "))
+	case "UNICODE":
+		return decodeUTF16(text, r.order)
+	}
+
+	return ""
+}
+
+func decodeUTF16(b []byte, order binary.ByteOrder) string {
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		u = append(u, order.Uint16(b[i:]))
+	}
+
+	return strings.TrimRight(string(utf16.Decode(u)), "\x00")
+}
+
+// parsePhotoshopResources reads the IPTC data from Photoshop image resource
+// blocks.
+func parsePhotoshopResources(data []byte, m map[string]string) {
+	const iptcResourceID = 0x0404
+
+	pos := 0
+	for pos+12 <= len(data) {
+		if !bytes.Equal(data[pos:pos+4], []byte("8BIM")) {
+			return
+		}
+
+		id := binary.BigEndian.Uint16(data[pos+4:])
+
+		// the name is a pascal string padded to an even length
+		nameLen := int(data[pos+6])
+		nameSize := nameLen + 1
+		if nameSize%2 != 0 {
+			nameSize++
+		}
+
+		sizePos := pos + 6 + nameSize
+		if sizePos+4 > len(data) {
+			return
+		}
+
+		size := int(binary.BigEndian.Uint32(data[sizePos:]))
+		start := sizePos + 4
+		if size < 0 || start+size > len(data) {
+			return
+		}
+
+		if id == iptcResourceID {
+			parseIPTC(data[start:start+size], m)
+		}
+
+		pos = start + size
+		if size%2 != 0 {
+			pos++
+		}
+	}
+}
+
+// IPTC application record datasets that are stored as file metadata
+var iptcDatasets = map[byte]string{
+	5:   "iptc:object_name",
+	25:  "iptc:keywords",
+	55:  "iptc:date_created",
+	80:  "iptc:by_line",
+	105: "iptc:headline",
+	116: "iptc:copyright_notice",
+	120: "iptc:caption",
+}
+
+// parseIPTC reads IPTC IIM datasets. Repeated datasets such as keywords are
+// joined.
+func parseIPTC(data []byte, m map[string]string) {
+	const (
+		tagMarker         = 0x1c
+		applicationRecord = 2
+		dateCreated       = 55
+	)
+
+	pos := 0
+	for pos+5 <= len(data) {
+		if data[pos] != tagMarker {
+			return
+		}
+
+		record := data[pos+1]
+		dataset := data[pos+2]
+		size := int(binary.BigEndian.Uint16(data[pos+3:]))
+
+		// extended datasets are not used for text values
+		if size&0x8000 != 0 {
+			return
+		}
+
+		start := pos + 5
+		if start+size > len(data) {
+			return
+		}
+
+		value := string(data[start : start+size])
+		pos = start + size
+
+		key, ok := iptcDatasets[dataset]
+		if record != applicationRecord || !ok {
+			continue
+		}
+
+		// dates are stored as CCYYMMDD
+		if dataset == dateCreated && len(value) == 8 {
+			value = value[:4] + "-" + value[4:6] + "-" + value[6:]
+		}
+
+		appendMetadata(m, key, value)
+	}
+}
+
+const (
+	rdfNamespace       = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	dcNamespace        = "http://purl.org/dc/elements/1.1/"
+	xmpNamespace       = "http://ns.adobe.com/xap/1.0/"
+	photoshopNamespace = "http://ns.adobe.com/photoshop/1.0/"
+)
+
+// XMP properties that are stored as file metadata
+var xmpProperties = map[xml.Name]string{
+	{Space: dcNamespace, Local: "title"}:              "xmp:title",
+	{Space: dcNamespace, Local: "description"}:        "xmp:description",
+	{Space: dcNamespace, Local: "creator"}:            "xmp:creator",
+	{Space: dcNamespace, Local: "subject"}:            "xmp:subject",
+	{Space: dcNamespace, Local: "rights"}:             "xmp:rights",
+	{Space: photoshopNamespace, Local: "DateCreated"}: "xmp:date_created",
+	{Space: xmpNamespace, Local: "CreateDate"}:        "xmp:create_date",
+}
+
+// parseXMP reads the XMP properties from an XMP packet. Properties may be
+// simple values, attributes of rdf:Description elements or arrays of
+// rdf:li values. Only the first value of language alternatives is used.
+func parseXMP(data []byte, m map[string]string) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = false
+
+	var (
+		property string
+		isAlt    bool
+		values   []string
+		text     strings.Builder
+	)
+
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if property == "" {
+				if key, ok := xmpProperties[t.Name]; ok {
+					property = key
+					isAlt = false
+					values = nil
+					text.Reset()
+					continue
+				}
+
+				for _, attr := range t.Attr {
+					if key, ok := xmpProperties[attr.Name]; ok {
+						setMetadata(m, key, attr.Value)
+					}
+				}
+				continue
+			}
+
+			switch {
+			case t.Name.Space == rdfNamespace && t.Name.Local == "Alt":
+				isAlt = true
+			case t.Name.Space == rdfNamespace && t.Name.Local == "li":
+				text.Reset()
+			}
+		case xml.CharData:
+			if property != "" {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			if property == "" {
+				continue
+			}
+
+			if t.Name.Space == rdfNamespace && t.Name.Local == "li" {
+				if v := strings.TrimSpace(text.String()); v != "" && !(isAlt && len(values) > 0) {
+					values = append(values, v)
+				}
+				text.Reset()
+				continue
+			}
+
+			if key, ok := xmpProperties[t.Name]; ok && key == property {
+				if len(values) == 0 {
+					setMetadata(m, key, text.String())
+				} else {
+					setMetadata(m, key, strings.Join(values, metadataValueSeparator))
+				}
+				property = ""
+			}
+		}
+	}
+}
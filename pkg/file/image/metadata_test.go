@@ -0,0 +1,180 @@
+package image
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func jpegSegment(marker byte, payload []byte) []byte {
+	var b bytes.Buffer
+	b.Write([]byte{0xff, marker})
+	_ = binary.Write(&b, binary.BigEndian, uint16(len(payload)+2))
+	b.Write(payload)
+	return b.Bytes()
+}
+
+// makeExif returns little endian TIFF data with an artist tag in IFD0 and a
+// date tag in the EXIF IFD.
+func makeExif(artist string, date string) []byte {
+	const (
+		ifd0Offset   = 8
+		ifdEntries   = 2
+		ifdSize      = 2 + ifdEntries*12 + 4
+		exifIFDStart = ifd0Offset + ifdSize
+		exifIFDSize  = 2 + 12 + 4
+		dataStart    = exifIFDStart + exifIFDSize
+	)
+
+	artistData := append([]byte(artist), 0)
+	dateData := append([]byte(date), 0)
+
+	var b bytes.Buffer
+	le := binary.LittleEndian
+	write := func(v interface{}) { _ = binary.Write(&b, le, v) }
+
+	b.WriteString("II")
+	write(uint16(42))
+	write(uint32(ifd0Offset))
+
+	// IFD0
+	write(uint16(ifdEntries))
+	write(uint16(0x013b))
+	write(uint16(2))
+	write(uint32(len(artistData)))
+	write(uint32(dataStart))
+	write(uint16(exifIFDPointerTag))
+	write(uint16(4))
+	write(uint32(1))
+	write(uint32(exifIFDStart))
+	write(uint32(0))
+
+	// EXIF IFD
+	write(uint16(1))
+	write(uint16(0x9003))
+	write(uint16(2))
+	write(uint32(len(dateData)))
+	write(uint32(dataStart + len(artistData)))
+	write(uint32(0))
+
+	b.Write(artistData)
+	b.Write(dateData)
+
+	return b.Bytes()
+}
+
+func makeIPTC(datasets map[byte][]string) []byte {
+	var iptc bytes.Buffer
+	for _, dataset := range []byte{5, 25, 55} {
+		for _, v := range datasets[dataset] {
+			iptc.Write([]byte{0x1c, 2, dataset})
+			_ = binary.Write(&iptc, binary.BigEndian, uint16(len(v)))
+			iptc.WriteString(v)
+		}
+	}
+
+	var b bytes.Buffer
+	b.Write(photoshopHeader)
+	b.WriteString("8BIM")
+	_ = binary.Write(&b, binary.BigEndian, uint16(0x0404))
+	// empty name, padded to an even length
+	b.Write([]byte{0, 0})
+	_ = binary.Write(&b, binary.BigEndian, uint32(iptc.Len()))
+	b.Write(iptc.Bytes())
+	if iptc.Len()%2 != 0 {
+		b.WriteByte(0)
+	}
+
+	return b.Bytes()
+}
+
+const testXMP = `<x:xmpmeta xmlns:x="adobe:ns:meta/">
+ <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+  <rdf:Description rdf:about=""
+    xmlns:dc="http://purl.org/dc/elements/1.1/"
+    xmlns:photoshop="http://ns.adobe.com/photoshop/1.0/"
+    photoshop:DateCreated="2021-03-04">
+   <dc:title>
+    <rdf:Alt>
+     <rdf:li xml:lang="x-default">XMP Title</rdf:li>
+     <rdf:li xml:lang="de">XMP Titel</rdf:li>
+    </rdf:Alt>
+   </dc:title>
+   <dc:subject>
+    <rdf:Bag>
+     <rdf:li>beach</rdf:li>
+     <rdf:li>sunset</rdf:li>
+    </rdf:Bag>
+   </dc:subject>
+   <dc:creator>
+    <rdf:Seq>
+     <rdf:li>Jane Doe</rdf:li>
+    </rdf:Seq>
+   </dc:creator>
+  </rdf:Description>
+ </rdf:RDF>
+</x:xmpmeta>`
+
+func TestParseJPEGMetadata(t *testing.T) {
+	var data bytes.Buffer
+	data.Write(jpegSOI)
+	data.Write(jpegSegment(0xe1, append(append([]byte{}, exifHeader...), makeExif("Photographer", "2020:01:02 03:04:05")...)))
+	data.Write(jpegSegment(0xe1, append(append([]byte{}, xmpHeader...), []byte(testXMP)...)))
+	data.Write(jpegSegment(0xed, makeIPTC(map[byte][]string{
+		5:  {"IPTC Title"},
+		25: {"one", "two"},
+		55: {"20200102"},
+	})))
+	// metadata after the start of the image data is ignored
+	data.Write([]byte{0xff, 0xda})
+	data.Write(jpegSegment(0xe1, append(append([]byte{}, xmpHeader...), []byte("<x:xmpmeta/>")...)))
+
+	got := make(map[string]string)
+	parseJPEGMetadata(data.Bytes(), got)
+
+	assert.Equal(t, map[string]string{
+		"exif:artist":             "Photographer",
+		"exif:date_time_original": "2020:01:02 03:04:05",
+		"xmp:title":               "XMP Title",
+		"xmp:subject":             "beach; sunset",
+		"xmp:creator":             "Jane Doe",
+		"xmp:date_created":        "2021-03-04",
+		"iptc:object_name":        "IPTC Title",
+		"iptc:keywords":           "one; two",
+		"iptc:date_created":       "2020-01-02",
+	}, got)
+}
+
+func TestParseJPEGMetadata_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"truncated segment", append(append([]byte{}, jpegSOI...), 0xff, 0xe1, 0xff, 0xff, 'E')},
+		{"invalid exif", append(append([]byte{}, jpegSOI...), jpegSegment(0xe1, append(append([]byte{}, exifHeader...), "II*\x00\xff\xff\xff\xff"...))...)},
+		{"invalid xmp", append(append([]byte{}, jpegSOI...), jpegSegment(0xe1, append(append([]byte{}, xmpHeader...), "<x:xmpmeta><dc:title>"...))...)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]string)
+			parseJPEGMetadata(tt.data, got)
+			assert.Empty(t, got)
+		})
+	}
+}
+
+func TestFindXMPPacket(t *testing.T) {
+	data := []byte("\x89PNG\r\n\x1a\n...iTXtXML:com.adobe.xmp\x00\x00\x00\x00\x00" + testXMP + "\x00...")
+
+	packet := findXMPPacket(data)
+	if !assert.NotNil(t, packet) {
+		return
+	}
+
+	got := make(map[string]string)
+	parseXMP(packet, got)
+	assert.Equal(t, "XMP Title", got["xmp:title"])
+}
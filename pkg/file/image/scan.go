@@ -36,6 +36,8 @@ func (d *Decorator) Decorate(ctx context.Context, fs models.FS, f models.File) (
 		if err != nil {
 			return f, fmt.Errorf("decoding image file %q: %w", base.Path, err)
 		}
+
+		d.setMetadata(fs, base)
 		return &models.ImageFile{
 			BaseFile: base,
 			Format:   format,
@@ -59,6 +61,7 @@ func (d *Decorator) Decorate(ctx context.Context, fs models.FS, f models.File) (
 
 	// Fallback to catch non-animated avif images that FFProbe detects as video files
 	if probe.Bitrate == 0 && probe.VideoCodec == "av1" {
+		d.setMetadata(fs, base)
 		return &models.ImageFile{
 			BaseFile: base,
 			Format:   "avif",
@@ -79,6 +82,7 @@ func (d *Decorator) Decorate(ctx context.Context, fs models.FS, f models.File) (
 		return videoFileDecorator.Decorate(ctx, fs, f)
 	}
 
+	d.setMetadata(fs, base)
 	return &models.ImageFile{
 		BaseFile: base,
 		Format:   probe.VideoCodec,
@@ -87,6 +91,19 @@ func (d *Decorator) Decorate(ctx context.Context, fs models.FS, f models.File) (
 	}, nil
 }
 
+// setMetadata sets the embedded metadata of the file. Files that cannot be
+// read are given empty metadata, so that they are not read again on every
+// scan.
+func (d *Decorator) setMetadata(fs models.FS, base *models.BaseFile) {
+	metadata, err := extractMetadata(fs, base.Path)
+	if err != nil {
+		logger.Warnf("Could not read embedded metadata of %q: %v", base.Path, err)
+		metadata = make(map[string]string)
+	}
+
+	base.Metadata = metadata
+}
+
 func (d *Decorator) IsMissingMetadata(ctx context.Context, fs models.FS, f models.File) bool {
 	const (
 		unsetString = "unset"
@@ -98,7 +115,8 @@ func (d *Decorator) IsMissingMetadata(ctx context.Context, fs models.FS, f model
 
 	switch {
 	case isImage:
-		return imf.Format == unsetString || imf.Width == unsetNumber || imf.Height == unsetNumber
+		return imf.Format == unsetString || imf.Width == unsetNumber || imf.Height == unsetNumber ||
+			imf.Metadata == nil
 	case isVideo:
 		videoFileDecorator := video.Decorator{FFProbe: d.FFProbe}
 		return videoFileDecorator.IsMissingMetadata(ctx, fs, vf)
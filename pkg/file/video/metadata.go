@@ -0,0 +1,35 @@
+package video
+
+import (
+	"strings"
+
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+// technical container tags that are not stored as file metadata
+var ignoredContainerTags = []string{
+	"major_brand",
+	"minor_version",
+	"compatible_brands",
+	"encoder",
+	"handler_name",
+	"vendor_id",
+}
+
+// containerMetadata returns the file metadata for the container tags of a
+// video file. Keys are lower case, so that MP4 and MKV tags can be mapped in
+// the same way.
+func containerMetadata(tags map[string]string) map[string]string {
+	ret := make(map[string]string)
+	for k, v := range tags {
+		k = strings.ToLower(k)
+		v = strings.TrimSpace(v)
+		if v == "" || sliceutil.Contains(ignoredContainerTags, k) {
+			continue
+		}
+
+		ret[k] = v
+	}
+
+	return ret
+}
@@ -43,6 +43,8 @@ func (d *Decorator) Decorate(ctx context.Context, fs models.FS, f models.File) (
 		interactive = true
 	}
 
+	base.Metadata = containerMetadata(videoFile.Tags)
+
 	return &models.VideoFile{
 		BaseFile:    base,
 		Format:      string(container),
@@ -77,5 +79,6 @@ func (d *Decorator) IsMissingMetadata(ctx context.Context, fs models.FS, f model
 		vf.Format == unsetString || vf.Width == unsetNumber ||
 		vf.Height == unsetNumber || vf.FrameRate == unsetNumber ||
 		vf.Duration == unsetNumber ||
-		vf.BitRate == unsetNumber || interactive != vf.Interactive
+		vf.BitRate == unsetNumber || interactive != vf.Interactive ||
+		vf.Metadata == nil
 }
@@ -0,0 +1,193 @@
+package javascript
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+)
+
+// maxReadFileSize is the maximum size of a file that may be read.
+const maxReadFileSize = 10 * 1024 * 1024
+
+var (
+	ErrPathNotAllowed = errors.New("path not allowed")
+	ErrFileTooLarge   = errors.New("file too large")
+)
+
+// FS provides read-only access to files and directories within the root
+// directories. Symlinks are resolved before checking that a path is within
+// a root directory. Operations fail once Context is done.
+type FS struct {
+	Context context.Context
+	Roots   []string
+}
+
+type fileInfo struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	IsDir   bool      `json:"isDir"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+func newFileInfo(path string, info os.FileInfo) fileInfo {
+	return fileInfo{
+		Name:    info.Name(),
+		Path:    path,
+		IsDir:   info.IsDir(),
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+}
+
+func (f *FS) roots() []string {
+	var ret []string
+	for _, r := range f.Roots {
+		abs, err := filepath.Abs(r)
+		if err != nil {
+			continue
+		}
+
+		ret = append(ret, abs)
+
+		// include the resolved path in case the root is a symlink
+		if resolved, err := filepath.EvalSymlinks(abs); err == nil && resolved != abs {
+			ret = append(ret, resolved)
+		}
+	}
+
+	return ret
+}
+
+// resolvePath returns the absolute path of p, returning an error if the
+// resolved path is not within one of the root directories.
+func (f *FS) resolvePath(p string) (string, error) {
+	if f.Context != nil {
+		if err := f.Context.Err(); err != nil {
+			return "", err
+		}
+	}
+
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+
+	roots := f.roots()
+	if !fsutil.IsPathInDirs(roots, abs) {
+		return "", fmt.Errorf("%w: %s", ErrPathNotAllowed, p)
+	}
+
+	// check the resolved path so that symlinks cannot escape the roots
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return "", err
+	}
+
+	if !fsutil.IsPathInDirs(roots, resolved) {
+		return "", fmt.Errorf("%w: %s", ErrPathNotAllowed, p)
+	}
+
+	return abs, nil
+}
+
+func (f *FS) readFile(p string) (string, error) {
+	path, err := f.resolvePath(p)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	// read one byte past the limit to detect files that are too large
+	b, err := io.ReadAll(io.LimitReader(file, maxReadFileSize+1))
+	if err != nil {
+		return "", err
+	}
+
+	if len(b) > maxReadFileSize {
+		return "", fmt.Errorf("%w: %s exceeds %d bytes", ErrFileTooLarge, p, maxReadFileSize)
+	}
+
+	return string(b), nil
+}
+
+func (f *FS) readDir(p string) ([]fileInfo, error) {
+	path, err := f.resolvePath(p)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []fileInfo{}
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+
+		ret = append(ret, newFileInfo(filepath.Join(path, e.Name()), info))
+	}
+
+	return ret, nil
+}
+
+func (f *FS) stat(p string) (*fileInfo, error) {
+	path, err := f.resolvePath(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := newFileInfo(path, info)
+	return &ret, nil
+}
+
+func (f *FS) exists(p string) (bool, error) {
+	info, err := f.stat(p)
+	if err != nil {
+		return false, err
+	}
+
+	return info != nil, nil
+}
+
+func (f *FS) AddToVM(globalName string, vm *VM) error {
+	obj := vm.NewObject()
+
+	if err := SetAll(obj,
+		ObjectValueDef{"ReadFile", f.readFile},
+		ObjectValueDef{"ReadDir", f.readDir},
+		ObjectValueDef{"Stat", f.stat},
+		ObjectValueDef{"Exists", f.exists},
+	); err != nil {
+		return err
+	}
+
+	if err := vm.Set(globalName, obj); err != nil {
+		return fmt.Errorf("unable to set %s: %w", globalName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package javascript
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFSReadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	small := filepath.Join(dir, "small.txt")
+	if err := os.WriteFile(small, []byte("contents"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	atLimit := filepath.Join(dir, "at_limit.txt")
+	if err := os.WriteFile(atLimit, make([]byte, maxReadFileSize), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tooLarge := filepath.Join(dir, "too_large.txt")
+	if err := os.WriteFile(tooLarge, make([]byte, maxReadFileSize+1), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := &FS{Roots: []string{dir}}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantLen int
+		wantErr error
+	}{
+		{"small", small, len("contents"), nil},
+		{"at limit", atLimit, maxReadFileSize, nil},
+		{"too large", tooLarge, 0, ErrFileTooLarge},
+		{"outside roots", filepath.Join(dir, "..", "other.txt"), 0, ErrPathNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fs.readFile(tt.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("FS.readFile() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != tt.wantLen {
+				t.Errorf("FS.readFile() len = %d, want %d", len(got), tt.wantLen)
+			}
+		})
+	}
+}
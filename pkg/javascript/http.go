@@ -0,0 +1,321 @@
+package javascript
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+const (
+	// httpTimeout is the timeout for HTTP requests. Includes transfer time.
+	httpTimeout = 60 * time.Second
+
+	// maxHTTPResponseSize is the maximum size of a response body that will be read.
+	maxHTTPResponseSize = 10 * 1024 * 1024
+)
+
+var ErrURLNotAllowed = errors.New("url not allowed")
+
+// HTTP provides functions to perform HTTP requests. Requests may only be
+// made to URLs that match the allowed sources.
+//
+// Sources follow a subset of the Content Security Policy source syntax:
+//   - * matches any http or https URL
+//   - scheme-only sources such as https: match any URL with that scheme
+//   - host sources such as https://*.example.com:8080/api/ match the
+//     scheme, host, port and path of the URL. The scheme, port and path
+//     are optional. A leading wildcard in the host matches any subdomain.
+//     A path ending in / matches any path with that prefix.
+type HTTP struct {
+	Context context.Context
+	Client  *http.Client
+
+	AllowedSources []string
+}
+
+type httpResponse struct {
+	Status     int               `json:"status"`
+	StatusText string            `json:"statusText"`
+	Headers    map[string]string `json:"headers"`
+	Body       string            `json:"body"`
+}
+
+type httpRequestOptions struct {
+	Headers map[string]string `json:"headers"`
+	Body    *string           `json:"body"`
+}
+
+func defaultPort(scheme string) string {
+	switch scheme {
+	case "http":
+		return "80"
+	case "https":
+		return "443"
+	}
+
+	return ""
+}
+
+func sourceMatchesHost(sourceHost string, host string) bool {
+	if strings.HasPrefix(sourceHost, "*.") {
+		return strings.HasSuffix(host, sourceHost[1:])
+	}
+
+	return sourceHost == host
+}
+
+// sourceMatchesURL returns true if the URL matches the source.
+func sourceMatchesURL(source string, u *url.URL) bool {
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return false
+	}
+
+	source = strings.ToLower(strings.TrimSpace(source))
+	if source == "*" {
+		return true
+	}
+
+	// scheme-only source
+	if strings.HasSuffix(source, ":") && !strings.Contains(source, "/") {
+		return strings.TrimSuffix(source, ":") == scheme
+	}
+
+	sourceScheme := ""
+	if i := strings.Index(source, "://"); i != -1 {
+		sourceScheme = source[:i]
+		source = source[i+3:]
+	}
+
+	if sourceScheme != "" && sourceScheme != scheme {
+		return false
+	}
+
+	sourceHost := source
+	sourcePath := ""
+	if i := strings.Index(source, "/"); i != -1 {
+		sourceHost = source[:i]
+		sourcePath = source[i:]
+	}
+
+	sourcePort := ""
+	if i := strings.LastIndex(sourceHost, ":"); i != -1 {
+		sourcePort = sourceHost[i+1:]
+		sourceHost = sourceHost[:i]
+	}
+
+	if !sourceMatchesHost(sourceHost, strings.ToLower(u.Hostname())) {
+		return false
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = defaultPort(scheme)
+	}
+
+	switch {
+	case sourcePort == "*":
+	case sourcePort == "":
+		if sourceScheme != "" {
+			if port != defaultPort(sourceScheme) {
+				return false
+			}
+		} else if port != defaultPort(scheme) {
+			return false
+		}
+	case sourcePort != port:
+		return false
+	}
+
+	if sourcePath == "" || sourcePath == "/" {
+		return true
+	}
+
+	path := u.EscapedPath()
+	if strings.HasSuffix(sourcePath, "/") {
+		return strings.HasPrefix(path, sourcePath)
+	}
+
+	return path == sourcePath
+}
+
+func (h *HTTP) urlAllowed(u *url.URL) bool {
+	for _, s := range h.AllowedSources {
+		if sourceMatchesURL(s, u) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *HTTP) checkURL(u *url.URL) error {
+	if !h.urlAllowed(u) {
+		return fmt.Errorf("%w: %s", ErrURLNotAllowed, u.String())
+	}
+
+	return nil
+}
+
+func (h *HTTP) client() *http.Client {
+	var ret http.Client
+	if h.Client != nil {
+		ret = *h.Client
+	}
+
+	if ret.Timeout == 0 {
+		ret.Timeout = httpTimeout
+	}
+
+	// ensure that redirects do not escape the allowed sources
+	checkRedirect := ret.CheckRedirect
+	ret.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := h.checkURL(req.URL); err != nil {
+			return err
+		}
+
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+
+		return nil
+	}
+
+	return &ret
+}
+
+func (h *HTTP) do(method string, urlStr string, options *httpRequestOptions) (*httpResponse, error) {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url %q: %w", urlStr, err)
+	}
+
+	if err := h.checkURL(u); err != nil {
+		return nil, err
+	}
+
+	ctx := h.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var body io.Reader
+	if options != nil && options.Body != nil {
+		body = strings.NewReader(*options.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(method), u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if options != nil {
+		for k, v := range options.Headers {
+			req.Header.Set(k, v)
+		}
+	}
+
+	resp, err := h.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	ret := &httpResponse{
+		Status:     resp.StatusCode,
+		StatusText: http.StatusText(resp.StatusCode),
+		Headers:    make(map[string]string),
+		Body:       string(b),
+	}
+
+	for k := range resp.Header {
+		ret.Headers[k] = resp.Header.Get(k)
+	}
+
+	return ret, nil
+}
+
+func (h *HTTP) parseOptions(vm *VM, v goja.Value) (*httpRequestOptions, error) {
+	if v == nil || goja.IsUndefined(v) || goja.IsNull(v) {
+		return nil, nil
+	}
+
+	var ret httpRequestOptions
+	if err := vm.ExportTo(v, &ret); err != nil {
+		return nil, fmt.Errorf("invalid request options: %w", err)
+	}
+
+	return &ret, nil
+}
+
+func (h *HTTP) requestFunc(vm *VM) func(method string, url string, options goja.Value) (*httpResponse, error) {
+	return func(method string, url string, options goja.Value) (*httpResponse, error) {
+		o, err := h.parseOptions(vm, options)
+		if err != nil {
+			return nil, err
+		}
+
+		return h.do(method, url, o)
+	}
+}
+
+func (h *HTTP) getFunc(vm *VM) func(url string, options goja.Value) (*httpResponse, error) {
+	return func(url string, options goja.Value) (*httpResponse, error) {
+		o, err := h.parseOptions(vm, options)
+		if err != nil {
+			return nil, err
+		}
+
+		return h.do(http.MethodGet, url, o)
+	}
+}
+
+func (h *HTTP) postFunc(vm *VM) func(url string, body string, options goja.Value) (*httpResponse, error) {
+	return func(url string, body string, options goja.Value) (*httpResponse, error) {
+		o, err := h.parseOptions(vm, options)
+		if err != nil {
+			return nil, err
+		}
+
+		if o == nil {
+			o = &httpRequestOptions{}
+		}
+		o.Body = &body
+
+		return h.do(http.MethodPost, url, o)
+	}
+}
+
+func (h *HTTP) AddToVM(globalName string, vm *VM) error {
+	obj := vm.NewObject()
+
+	if err := SetAll(obj,
+		ObjectValueDef{"Request", h.requestFunc(vm)},
+		ObjectValueDef{"Get", h.getFunc(vm)},
+		ObjectValueDef{"Post", h.postFunc(vm)},
+	); err != nil {
+		return err
+	}
+
+	if err := vm.Set(globalName, obj); err != nil {
+		return fmt.Errorf("unable to set %s: %w", globalName, err)
+	}
+
+	return nil
+}
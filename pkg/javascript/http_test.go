@@ -0,0 +1,49 @@
+package javascript
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestSourceMatchesURL(t *testing.T) {
+	tests := []struct {
+		source string
+		url    string
+		want   bool
+	}{
+		{"*", "https://example.com/foo", true},
+		{"*", "ftp://example.com/foo", false},
+		{"https:", "https://example.com/foo", true},
+		{"https:", "http://example.com/foo", false},
+		{"example.com", "http://example.com/foo", true},
+		{"example.com", "https://example.com/foo", true},
+		{"example.com", "https://www.example.com/foo", false},
+		{"example.com", "https://example.com:8080/foo", false},
+		{"https://example.com", "http://example.com/foo", false},
+		{"https://EXAMPLE.com", "https://example.com", true},
+		{"https://example.com:8080", "https://example.com:8080/foo", true},
+		{"https://example.com:8080", "https://example.com/foo", false},
+		{"https://example.com:*", "https://example.com:1234/foo", true},
+		{"https://*.example.com", "https://api.example.com/foo", true},
+		{"https://*.example.com", "https://a.b.example.com/foo", true},
+		{"https://*.example.com", "https://example.com/foo", false},
+		{"https://*.example.com", "https://badexample.com/foo", false},
+		{"https://example.com/api/", "https://example.com/api/foo", true},
+		{"https://example.com/api/", "https://example.com/apifoo", false},
+		{"https://example.com/api", "https://example.com/api", true},
+		{"https://example.com/api", "https://example.com/api/foo", false},
+		{"https://example.com/", "https://example.com/anything", true},
+		{"example.com", "https://example.com.evil.com/", false},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatalf("invalid url %s: %v", tt.url, err)
+		}
+
+		if got := sourceMatchesURL(tt.source, u); got != tt.want {
+			t.Errorf("sourceMatchesURL(%q, %q) = %v, want %v", tt.source, tt.url, got, tt.want)
+		}
+	}
+}
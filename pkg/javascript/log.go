@@ -65,6 +65,11 @@ func (l *Log) logError(call goja.FunctionCall) goja.Value {
 // between 0 and 1.0 inclusively, with 1 representing that the task is
 // complete. Values outside of this range will be clamp to be within it.
 func (l *Log) logProgress(value float64) {
+	// progress is not reported for hooks and services
+	if l.ProgressChan == nil {
+		return
+	}
+
 	value = math.Min(math.Max(0, value), 1)
 	l.ProgressChan <- value
 }
@@ -0,0 +1,103 @@
+package javascript
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dop251/goja"
+)
+
+// KeyValueStore persists string values by key.
+type KeyValueStore interface {
+	// Get returns the value for the key, or nil if the key is not set.
+	Get(ctx context.Context, key string) (*string, error)
+	Set(ctx context.Context, key string, value string) error
+	Delete(ctx context.Context, key string) error
+	Keys(ctx context.Context) ([]string, error)
+}
+
+// Storage provides functions to persist values. Values are stored in the
+// store using their JSON representation.
+type Storage struct {
+	Context context.Context
+	Store   KeyValueStore
+}
+
+func (s *Storage) context() context.Context {
+	if s.Context == nil {
+		return context.Background()
+	}
+
+	return s.Context
+}
+
+func (s *Storage) getFunc(vm *VM) func(key string) (goja.Value, error) {
+	return func(key string) (goja.Value, error) {
+		v, err := s.Store.Get(s.context(), key)
+		if err != nil {
+			return nil, err
+		}
+
+		if v == nil {
+			return goja.Null(), nil
+		}
+
+		var ret interface{}
+		if err := json.Unmarshal([]byte(*v), &ret); err != nil {
+			return nil, fmt.Errorf("could not decode value for key %s: %w", key, err)
+		}
+
+		return vm.ToValue(ret), nil
+	}
+}
+
+func (s *Storage) set(key string, value goja.Value) error {
+	var v interface{}
+	if value != nil {
+		v = value.Export()
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("could not encode value for key %s: %w", key, err)
+	}
+
+	return s.Store.Set(s.context(), key, string(encoded))
+}
+
+func (s *Storage) delete(key string) error {
+	return s.Store.Delete(s.context(), key)
+}
+
+func (s *Storage) keys() ([]string, error) {
+	ret, err := s.Store.Keys(s.context())
+	if err != nil {
+		return nil, err
+	}
+
+	if ret == nil {
+		ret = []string{}
+	}
+
+	return ret, nil
+}
+
+func (s *Storage) AddToVM(globalName string, vm *VM) error {
+	obj := vm.NewObject()
+
+	if err := SetAll(obj,
+		ObjectValueDef{"Get", s.getFunc(vm)},
+		ObjectValueDef{"Set", s.set},
+		ObjectValueDef{"Delete", s.delete},
+		ObjectValueDef{"Keys", s.keys},
+	); err != nil {
+		return err
+	}
+
+	if err := vm.Set(globalName, obj); err != nil {
+		return fmt.Errorf("unable to set %s: %w", globalName, err)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package job
 
 import (
 	"context"
+	"errors"
 	"runtime/debug"
 	"sync"
 	"time"
@@ -13,6 +14,9 @@ import (
 const maxGraveyardSize = 10
 const defaultThrottleLimit = 100 * time.Millisecond
 
+// ErrJobsRunning is returned by RunExclusive if jobs are queued or running.
+var ErrJobsRunning = errors.New("jobs are queued or running")
+
 // Manager maintains a queue of jobs. Jobs are executed one at a time.
 type Manager struct {
 	queue     []*Job
@@ -24,6 +28,12 @@ type Manager struct {
 
 	lastID int
 
+	// exclusive is true while a function passed to RunExclusive is running.
+	// Jobs are not started while it is set. Jobs passed to Start are held
+	// in deferred until it is cleared.
+	exclusive bool
+	deferred  []*Job
+
 	subscriptions       []*ManagerSubscription
 	updateThrottleLimit time.Duration
 }
@@ -96,11 +106,50 @@ func (m *Manager) Start(ctx context.Context, description string, e JobExec) int
 
 	m.queue = append(m.queue, &j)
 
-	m.dispatch(ctx, &j)
+	if m.exclusive {
+		m.deferred = append(m.deferred, &j)
+		m.notifyNewJob(&j)
+	} else {
+		m.dispatch(ctx, &j)
+	}
 
 	return j.ID
 }
 
+// RunExclusive runs fn while no jobs are running. It returns ErrJobsRunning
+// without running fn if any jobs are queued or running, or if another
+// function is being run by RunExclusive. Jobs added while fn is running are
+// not started until fn returns.
+func (m *Manager) RunExclusive(fn func() error) error {
+	m.mutex.Lock()
+	if m.exclusive || len(m.queue) > 0 {
+		m.mutex.Unlock()
+		return ErrJobsRunning
+	}
+	m.exclusive = true
+	m.mutex.Unlock()
+
+	defer func() {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		m.exclusive = false
+
+		for _, j := range m.deferred {
+			// may have been cancelled
+			if j.Status == StatusReady {
+				m.dispatch(j.outerCtx, j)
+			}
+		}
+		m.deferred = nil
+
+		// start any jobs added in the meantime
+		m.notEmpty.Broadcast()
+	}()
+
+	return fn()
+}
+
 func (m *Manager) notifyNewJob(j *Job) {
 	// assumes lock held
 	for _, s := range m.subscriptions {
@@ -119,6 +168,10 @@ func (m *Manager) nextID() int {
 
 func (m *Manager) getReadyJob() *Job {
 	// assumes lock held
+	if m.exclusive {
+		return nil
+	}
+
 	for _, j := range m.queue {
 		if j.Status == StatusReady {
 			return j
@@ -343,3 +343,74 @@ func TestSubscribe(t *testing.T) {
 
 	cancel()
 }
+
+func TestRunExclusive(t *testing.T) {
+	m := NewManager()
+
+	assert := assert.New(t)
+
+	// add a running job
+	exec1 := newTestExec(make(chan struct{}))
+	m.Add(context.Background(), "test job", exec1)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	// expect not to run while a job is running
+	ran := false
+	err := m.RunExclusive(func() error {
+		ran = true
+		return nil
+	})
+	assert.ErrorIs(err, ErrJobsRunning)
+	assert.False(ran)
+
+	// allow job to finish
+	close(exec1.finish)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	exec2 := newTestExec(nil)
+	exec3 := newTestExec(nil)
+	var job2ID, job3ID int
+
+	err = m.RunExclusive(func() error {
+		ran = true
+
+		// expect not to run concurrently
+		assert.ErrorIs(m.RunExclusive(func() error { return nil }), ErrJobsRunning)
+
+		// expect jobs added while running not to be started
+		job2ID = m.Add(context.Background(), "queued job", exec2)
+		job3ID = m.Start(context.Background(), "started job", exec3)
+
+		// wait a tiny bit
+		time.Sleep(sleepTime)
+
+		select {
+		case <-exec2.started:
+			t.Error("queued exec was started")
+		case <-exec3.started:
+			t.Error("started exec was started")
+		default:
+		}
+
+		assert.Equal(StatusReady, m.GetJob(job2ID).Status)
+		assert.Equal(StatusReady, m.GetJob(job3ID).Status)
+
+		return nil
+	})
+	assert.NoError(err)
+	assert.True(ran)
+
+	// expect jobs to be started once finished
+	for _, e := range []*testExec{exec2, exec3} {
+		select {
+		case <-e.started:
+			// ok
+		case <-time.After(time.Second):
+			t.Error("exec was not started")
+		}
+	}
+}
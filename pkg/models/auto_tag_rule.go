@@ -0,0 +1,21 @@
+package models
+
+import "context"
+
+type AutoTagRuleReader interface {
+	Find(ctx context.Context, id int) (*AutoTagRule, error)
+	FindMany(ctx context.Context, ids []int) ([]*AutoTagRule, error)
+	// All returns all rules, in the order that they are applied.
+	All(ctx context.Context) ([]*AutoTagRule, error)
+}
+
+type AutoTagRuleWriter interface {
+	Create(ctx context.Context, newRule *AutoTagRule) error
+	Update(ctx context.Context, updatedRule *AutoTagRule) error
+	Destroy(ctx context.Context, id int) error
+}
+
+type AutoTagRuleReaderWriter interface {
+	AutoTagRuleReader
+	AutoTagRuleWriter
+}
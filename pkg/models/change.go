@@ -0,0 +1,9 @@
+package models
+
+import "context"
+
+type ChangeReader interface {
+	Find(ctx context.Context, id int) (*Change, error)
+	// Query returns the changes matching the filter, newest first.
+	Query(ctx context.Context, changeFilter *ChangeFilterType, findFilter *FindFilterType) ([]*Change, int, error)
+}
@@ -0,0 +1,803 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrUnknownChangeField is returned when reverting a field that is not audited.
+var ErrUnknownChangeField = errors.New("unknown change field")
+
+func dateChangeValue(d *Date) *string {
+	if d == nil {
+		return nil
+	}
+
+	s := d.String()
+	return &s
+}
+
+func idsChangeValue(ids []int) []int {
+	ret := make([]int, len(ids))
+	copy(ret, ids)
+	sort.Ints(ret)
+	return ret
+}
+
+func stringsChangeValue(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func stashIDsChangeValue(s []StashID) []StashID {
+	if s == nil {
+		return []StashID{}
+	}
+	return s
+}
+
+// changeValue is a JSON-encoded field value from a Change.
+type changeValue string
+
+func (v changeValue) decode(out interface{}) error {
+	return json.Unmarshal([]byte(v), out)
+}
+
+func (v changeValue) optionalString() (OptionalString, error) {
+	var s *string
+	if err := v.decode(&s); err != nil {
+		return OptionalString{}, err
+	}
+	if s == nil {
+		return OptionalString{Set: true, Null: true}, nil
+	}
+	return NewOptionalString(*s), nil
+}
+
+func (v changeValue) optionalInt() (OptionalInt, error) {
+	var i *int
+	if err := v.decode(&i); err != nil {
+		return OptionalInt{}, err
+	}
+	if i == nil {
+		return OptionalInt{Set: true, Null: true}, nil
+	}
+	return NewOptionalInt(*i), nil
+}
+
+func (v changeValue) optionalFloat64() (OptionalFloat64, error) {
+	var f *float64
+	if err := v.decode(&f); err != nil {
+		return OptionalFloat64{}, err
+	}
+	if f == nil {
+		return OptionalFloat64{Set: true, Null: true}, nil
+	}
+	return NewOptionalFloat64(*f), nil
+}
+
+func (v changeValue) optionalBool() (OptionalBool, error) {
+	var b bool
+	if err := v.decode(&b); err != nil {
+		return OptionalBool{}, err
+	}
+	return NewOptionalBool(b), nil
+}
+
+func (v changeValue) optionalDate() (OptionalDate, error) {
+	var s *string
+	if err := v.decode(&s); err != nil {
+		return OptionalDate{}, err
+	}
+	if s == nil {
+		return OptionalDate{Set: true, Null: true}, nil
+	}
+
+	d, err := ParseDate(*s)
+	if err != nil {
+		return OptionalDate{}, err
+	}
+	return NewOptionalDate(d), nil
+}
+
+func (v changeValue) updateStrings() (*UpdateStrings, error) {
+	var s []string
+	if err := v.decode(&s); err != nil {
+		return nil, err
+	}
+	return &UpdateStrings{Values: s, Mode: RelationshipUpdateModeSet}, nil
+}
+
+func (v changeValue) updateIDs() (*UpdateIDs, error) {
+	var ids []int
+	if err := v.decode(&ids); err != nil {
+		return nil, err
+	}
+	return &UpdateIDs{IDs: ids, Mode: RelationshipUpdateModeSet}, nil
+}
+
+func (v changeValue) updateStashIDs() (*UpdateStashIDs, error) {
+	var s []StashID
+	if err := v.decode(&s); err != nil {
+		return nil, err
+	}
+	return &UpdateStashIDs{StashIDs: s, Mode: RelationshipUpdateModeSet}, nil
+}
+
+func (v changeValue) updateMovieIDs() (*UpdateMovieIDs, error) {
+	var m []MoviesScenes
+	if err := v.decode(&m); err != nil {
+		return nil, err
+	}
+	return &UpdateMovieIDs{Movies: m, Mode: RelationshipUpdateModeSet}, nil
+}
+
+// ChangeValues returns the audited fields of the scene.
+// Relationships that have not been loaded are omitted.
+func (s *Scene) ChangeValues() ChangeValues {
+	ret := ChangeValues{
+		"title":     s.Title,
+		"code":      s.Code,
+		"details":   s.Details,
+		"director":  s.Director,
+		"date":      dateChangeValue(s.Date),
+		"rating":    s.Rating,
+		"organized": s.Organized,
+		"studio_id": s.StudioID,
+	}
+
+	if s.URLs.Loaded() {
+		ret["urls"] = stringsChangeValue(s.URLs.List())
+	}
+	if s.GalleryIDs.Loaded() {
+		ret["gallery_ids"] = idsChangeValue(s.GalleryIDs.List())
+	}
+	if s.TagIDs.Loaded() {
+		ret["tag_ids"] = idsChangeValue(s.TagIDs.List())
+	}
+	if s.PerformerIDs.Loaded() {
+		ret["performer_ids"] = idsChangeValue(s.PerformerIDs.List())
+	}
+	if s.Movies.Loaded() {
+		movies := s.Movies.List()
+		if movies == nil {
+			movies = []MoviesScenes{}
+		}
+		ret["movies"] = movies
+	}
+	if s.StashIDs.Loaded() {
+		ret["stash_ids"] = stashIDsChangeValue(s.StashIDs.List())
+	}
+
+	return ret
+}
+
+// ChangeRelationships returns the audited relationships of the scene that
+// are loaded, and so are replaced when it is updated.
+func (s *Scene) ChangeRelationships() []string {
+	var ret []string
+	if s.URLs.Loaded() {
+		ret = append(ret, "urls")
+	}
+	if s.GalleryIDs.Loaded() {
+		ret = append(ret, "gallery_ids")
+	}
+	if s.TagIDs.Loaded() {
+		ret = append(ret, "tag_ids")
+	}
+	if s.PerformerIDs.Loaded() {
+		ret = append(ret, "performer_ids")
+	}
+	if s.Movies.Loaded() {
+		ret = append(ret, "movies")
+	}
+	if s.StashIDs.Loaded() {
+		ret = append(ret, "stash_ids")
+	}
+	return ret
+}
+
+// ChangeRelationships returns the audited relationships updated by the partial.
+func (p ScenePartial) ChangeRelationships() []string {
+	var ret []string
+	if p.URLs != nil {
+		ret = append(ret, "urls")
+	}
+	if p.GalleryIDs != nil {
+		ret = append(ret, "gallery_ids")
+	}
+	if p.TagIDs != nil {
+		ret = append(ret, "tag_ids")
+	}
+	if p.PerformerIDs != nil {
+		ret = append(ret, "performer_ids")
+	}
+	if p.MovieIDs != nil {
+		ret = append(ret, "movies")
+	}
+	if p.StashIDs != nil {
+		ret = append(ret, "stash_ids")
+	}
+	return ret
+}
+
+// SetChangeValue sets the field in the partial to the JSON-encoded value
+// from a scene change.
+func (p *ScenePartial) SetChangeValue(field string, value string) error {
+	v := changeValue(value)
+	var err error
+	switch field {
+	case "title":
+		p.Title, err = v.optionalString()
+	case "code":
+		p.Code, err = v.optionalString()
+	case "details":
+		p.Details, err = v.optionalString()
+	case "director":
+		p.Director, err = v.optionalString()
+	case "date":
+		p.Date, err = v.optionalDate()
+	case "rating":
+		p.Rating, err = v.optionalInt()
+	case "organized":
+		p.Organized, err = v.optionalBool()
+	case "studio_id":
+		p.StudioID, err = v.optionalInt()
+	case "urls":
+		p.URLs, err = v.updateStrings()
+	case "gallery_ids":
+		p.GalleryIDs, err = v.updateIDs()
+	case "tag_ids":
+		p.TagIDs, err = v.updateIDs()
+	case "performer_ids":
+		p.PerformerIDs, err = v.updateIDs()
+	case "movies":
+		p.MovieIDs, err = v.updateMovieIDs()
+	case "stash_ids":
+		p.StashIDs, err = v.updateStashIDs()
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownChangeField, field)
+	}
+
+	return err
+}
+
+// ChangeValues returns the audited fields of the image.
+// Relationships that have not been loaded are omitted.
+func (i *Image) ChangeValues() ChangeValues {
+	ret := ChangeValues{
+		"title":        i.Title,
+		"code":         i.Code,
+		"details":      i.Details,
+		"photographer": i.Photographer,
+		"date":         dateChangeValue(i.Date),
+		"rating":       i.Rating,
+		"organized":    i.Organized,
+		"studio_id":    i.StudioID,
+	}
+
+	if i.URLs.Loaded() {
+		ret["urls"] = stringsChangeValue(i.URLs.List())
+	}
+	if i.GalleryIDs.Loaded() {
+		ret["gallery_ids"] = idsChangeValue(i.GalleryIDs.List())
+	}
+	if i.TagIDs.Loaded() {
+		ret["tag_ids"] = idsChangeValue(i.TagIDs.List())
+	}
+	if i.PerformerIDs.Loaded() {
+		ret["performer_ids"] = idsChangeValue(i.PerformerIDs.List())
+	}
+
+	return ret
+}
+
+// ChangeRelationships returns the audited relationships of the image that
+// are loaded, and so are replaced when it is updated.
+func (i *Image) ChangeRelationships() []string {
+	var ret []string
+	if i.URLs.Loaded() {
+		ret = append(ret, "urls")
+	}
+	if i.GalleryIDs.Loaded() {
+		ret = append(ret, "gallery_ids")
+	}
+	if i.TagIDs.Loaded() {
+		ret = append(ret, "tag_ids")
+	}
+	if i.PerformerIDs.Loaded() {
+		ret = append(ret, "performer_ids")
+	}
+	return ret
+}
+
+// ChangeRelationships returns the audited relationships updated by the partial.
+func (p ImagePartial) ChangeRelationships() []string {
+	var ret []string
+	if p.URLs != nil {
+		ret = append(ret, "urls")
+	}
+	if p.GalleryIDs != nil {
+		ret = append(ret, "gallery_ids")
+	}
+	if p.TagIDs != nil {
+		ret = append(ret, "tag_ids")
+	}
+	if p.PerformerIDs != nil {
+		ret = append(ret, "performer_ids")
+	}
+	return ret
+}
+
+// SetChangeValue sets the field in the partial to the JSON-encoded value
+// from an image change.
+func (p *ImagePartial) SetChangeValue(field string, value string) error {
+	v := changeValue(value)
+	var err error
+	switch field {
+	case "title":
+		p.Title, err = v.optionalString()
+	case "code":
+		p.Code, err = v.optionalString()
+	case "details":
+		p.Details, err = v.optionalString()
+	case "photographer":
+		p.Photographer, err = v.optionalString()
+	case "date":
+		p.Date, err = v.optionalDate()
+	case "rating":
+		p.Rating, err = v.optionalInt()
+	case "organized":
+		p.Organized, err = v.optionalBool()
+	case "studio_id":
+		p.StudioID, err = v.optionalInt()
+	case "urls":
+		p.URLs, err = v.updateStrings()
+	case "gallery_ids":
+		p.GalleryIDs, err = v.updateIDs()
+	case "tag_ids":
+		p.TagIDs, err = v.updateIDs()
+	case "performer_ids":
+		p.PerformerIDs, err = v.updateIDs()
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownChangeField, field)
+	}
+
+	return err
+}
+
+// ChangeValues returns the audited fields of the gallery.
+// Relationships that have not been loaded are omitted.
+func (g *Gallery) ChangeValues() ChangeValues {
+	ret := ChangeValues{
+		"title":        g.Title,
+		"code":         g.Code,
+		"details":      g.Details,
+		"photographer": g.Photographer,
+		"date":         dateChangeValue(g.Date),
+		"rating":       g.Rating,
+		"organized":    g.Organized,
+		"studio_id":    g.StudioID,
+	}
+
+	if g.URLs.Loaded() {
+		ret["urls"] = stringsChangeValue(g.URLs.List())
+	}
+	if g.SceneIDs.Loaded() {
+		ret["scene_ids"] = idsChangeValue(g.SceneIDs.List())
+	}
+	if g.TagIDs.Loaded() {
+		ret["tag_ids"] = idsChangeValue(g.TagIDs.List())
+	}
+	if g.PerformerIDs.Loaded() {
+		ret["performer_ids"] = idsChangeValue(g.PerformerIDs.List())
+	}
+
+	return ret
+}
+
+// ChangeRelationships returns the audited relationships of the gallery that
+// are loaded, and so are replaced when it is updated.
+func (g *Gallery) ChangeRelationships() []string {
+	var ret []string
+	if g.URLs.Loaded() {
+		ret = append(ret, "urls")
+	}
+	if g.SceneIDs.Loaded() {
+		ret = append(ret, "scene_ids")
+	}
+	if g.TagIDs.Loaded() {
+		ret = append(ret, "tag_ids")
+	}
+	if g.PerformerIDs.Loaded() {
+		ret = append(ret, "performer_ids")
+	}
+	return ret
+}
+
+// ChangeRelationships returns the audited relationships updated by the partial.
+func (p GalleryPartial) ChangeRelationships() []string {
+	var ret []string
+	if p.URLs != nil {
+		ret = append(ret, "urls")
+	}
+	if p.SceneIDs != nil {
+		ret = append(ret, "scene_ids")
+	}
+	if p.TagIDs != nil {
+		ret = append(ret, "tag_ids")
+	}
+	if p.PerformerIDs != nil {
+		ret = append(ret, "performer_ids")
+	}
+	return ret
+}
+
+// SetChangeValue sets the field in the partial to the JSON-encoded value
+// from a gallery change.
+func (p *GalleryPartial) SetChangeValue(field string, value string) error {
+	v := changeValue(value)
+	var err error
+	switch field {
+	case "title":
+		p.Title, err = v.optionalString()
+	case "code":
+		p.Code, err = v.optionalString()
+	case "details":
+		p.Details, err = v.optionalString()
+	case "photographer":
+		p.Photographer, err = v.optionalString()
+	case "date":
+		p.Date, err = v.optionalDate()
+	case "rating":
+		p.Rating, err = v.optionalInt()
+	case "organized":
+		p.Organized, err = v.optionalBool()
+	case "studio_id":
+		p.StudioID, err = v.optionalInt()
+	case "urls":
+		p.URLs, err = v.updateStrings()
+	case "scene_ids":
+		p.SceneIDs, err = v.updateIDs()
+	case "tag_ids":
+		p.TagIDs, err = v.updateIDs()
+	case "performer_ids":
+		p.PerformerIDs, err = v.updateIDs()
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownChangeField, field)
+	}
+
+	return err
+}
+
+// ChangeValues returns the audited fields of the performer.
+// Relationships that have not been loaded are omitted.
+func (p *Performer) ChangeValues() ChangeValues {
+	var gender, circumcised *string
+	if p.Gender != nil {
+		s := p.Gender.String()
+		gender = &s
+	}
+	if p.Circumcised != nil {
+		s := p.Circumcised.String()
+		circumcised = &s
+	}
+
+	ret := ChangeValues{
+		"name":            p.Name,
+		"disambiguation":  p.Disambiguation,
+		"gender":          gender,
+		"birthdate":       dateChangeValue(p.Birthdate),
+		"death_date":      dateChangeValue(p.DeathDate),
+		"ethnicity":       p.Ethnicity,
+		"country":         p.Country,
+		"eye_color":       p.EyeColor,
+		"hair_color":      p.HairColor,
+		"height":          p.Height,
+		"weight":          p.Weight,
+		"measurements":    p.Measurements,
+		"fake_tits":       p.FakeTits,
+		"penis_length":    p.PenisLength,
+		"circumcised":     circumcised,
+		"career_length":   p.CareerLength,
+		"tattoos":         p.Tattoos,
+		"piercings":       p.Piercings,
+		"favorite":        p.Favorite,
+		"rating":          p.Rating,
+		"details":         p.Details,
+		"ignore_auto_tag": p.IgnoreAutoTag,
+	}
+
+	if p.Aliases.Loaded() {
+		ret["aliases"] = stringsChangeValue(p.Aliases.List())
+	}
+	if p.URLs.Loaded() {
+		ret["urls"] = stringsChangeValue(p.URLs.List())
+	}
+	if p.TagIDs.Loaded() {
+		ret["tag_ids"] = idsChangeValue(p.TagIDs.List())
+	}
+	if p.StashIDs.Loaded() {
+		ret["stash_ids"] = stashIDsChangeValue(p.StashIDs.List())
+	}
+
+	return ret
+}
+
+// ChangeRelationships returns the audited relationships of the performer that
+// are loaded, and so are replaced when it is updated.
+func (p *Performer) ChangeRelationships() []string {
+	var ret []string
+	if p.Aliases.Loaded() {
+		ret = append(ret, "aliases")
+	}
+	if p.URLs.Loaded() {
+		ret = append(ret, "urls")
+	}
+	if p.TagIDs.Loaded() {
+		ret = append(ret, "tag_ids")
+	}
+	if p.StashIDs.Loaded() {
+		ret = append(ret, "stash_ids")
+	}
+	return ret
+}
+
+// ChangeRelationships returns the audited relationships updated by the partial.
+func (p PerformerPartial) ChangeRelationships() []string {
+	var ret []string
+	if p.Aliases != nil {
+		ret = append(ret, "aliases")
+	}
+	if p.URLs != nil {
+		ret = append(ret, "urls")
+	}
+	if p.TagIDs != nil {
+		ret = append(ret, "tag_ids")
+	}
+	if p.StashIDs != nil {
+		ret = append(ret, "stash_ids")
+	}
+	return ret
+}
+
+// SetChangeValue sets the field in the partial to the JSON-encoded value
+// from a performer change.
+func (p *PerformerPartial) SetChangeValue(field string, value string) error {
+	v := changeValue(value)
+	var err error
+	switch field {
+	case "name":
+		p.Name, err = v.optionalString()
+	case "disambiguation":
+		p.Disambiguation, err = v.optionalString()
+	case "gender":
+		p.Gender, err = v.optionalString()
+	case "birthdate":
+		p.Birthdate, err = v.optionalDate()
+	case "death_date":
+		p.DeathDate, err = v.optionalDate()
+	case "ethnicity":
+		p.Ethnicity, err = v.optionalString()
+	case "country":
+		p.Country, err = v.optionalString()
+	case "eye_color":
+		p.EyeColor, err = v.optionalString()
+	case "hair_color":
+		p.HairColor, err = v.optionalString()
+	case "height":
+		p.Height, err = v.optionalInt()
+	case "weight":
+		p.Weight, err = v.optionalInt()
+	case "measurements":
+		p.Measurements, err = v.optionalString()
+	case "fake_tits":
+		p.FakeTits, err = v.optionalString()
+	case "penis_length":
+		p.PenisLength, err = v.optionalFloat64()
+	case "circumcised":
+		p.Circumcised, err = v.optionalString()
+	case "career_length":
+		p.CareerLength, err = v.optionalString()
+	case "tattoos":
+		p.Tattoos, err = v.optionalString()
+	case "piercings":
+		p.Piercings, err = v.optionalString()
+	case "favorite":
+		p.Favorite, err = v.optionalBool()
+	case "rating":
+		p.Rating, err = v.optionalInt()
+	case "details":
+		p.Details, err = v.optionalString()
+	case "ignore_auto_tag":
+		p.IgnoreAutoTag, err = v.optionalBool()
+	case "aliases":
+		p.Aliases, err = v.updateStrings()
+	case "urls":
+		p.URLs, err = v.updateStrings()
+	case "tag_ids":
+		p.TagIDs, err = v.updateIDs()
+	case "stash_ids":
+		p.StashIDs, err = v.updateStashIDs()
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownChangeField, field)
+	}
+
+	return err
+}
+
+// ChangeValues returns the audited fields of the studio.
+// Relationships that have not been loaded are omitted.
+func (s *Studio) ChangeValues() ChangeValues {
+	ret := ChangeValues{
+		"name":            s.Name,
+		"url":             s.URL,
+		"parent_id":       s.ParentID,
+		"rating":          s.Rating,
+		"favorite":        s.Favorite,
+		"details":         s.Details,
+		"ignore_auto_tag": s.IgnoreAutoTag,
+	}
+
+	if s.Aliases.Loaded() {
+		ret["aliases"] = stringsChangeValue(s.Aliases.List())
+	}
+	if s.TagIDs.Loaded() {
+		ret["tag_ids"] = idsChangeValue(s.TagIDs.List())
+	}
+	if s.StashIDs.Loaded() {
+		ret["stash_ids"] = stashIDsChangeValue(s.StashIDs.List())
+	}
+
+	return ret
+}
+
+// ChangeRelationships returns the audited relationships of the studio that
+// are loaded, and so are replaced when it is updated.
+func (s *Studio) ChangeRelationships() []string {
+	var ret []string
+	if s.Aliases.Loaded() {
+		ret = append(ret, "aliases")
+	}
+	if s.TagIDs.Loaded() {
+		ret = append(ret, "tag_ids")
+	}
+	if s.StashIDs.Loaded() {
+		ret = append(ret, "stash_ids")
+	}
+	return ret
+}
+
+// ChangeRelationships returns the audited relationships updated by the partial.
+func (p StudioPartial) ChangeRelationships() []string {
+	var ret []string
+	if p.Aliases != nil {
+		ret = append(ret, "aliases")
+	}
+	if p.TagIDs != nil {
+		ret = append(ret, "tag_ids")
+	}
+	if p.StashIDs != nil {
+		ret = append(ret, "stash_ids")
+	}
+	return ret
+}
+
+// SetChangeValue sets the field in the partial to the JSON-encoded value
+// from a studio change.
+func (p *StudioPartial) SetChangeValue(field string, value string) error {
+	v := changeValue(value)
+	var err error
+	switch field {
+	case "name":
+		p.Name, err = v.optionalString()
+	case "url":
+		p.URL, err = v.optionalString()
+	case "parent_id":
+		p.ParentID, err = v.optionalInt()
+	case "rating":
+		p.Rating, err = v.optionalInt()
+	case "favorite":
+		p.Favorite, err = v.optionalBool()
+	case "details":
+		p.Details, err = v.optionalString()
+	case "ignore_auto_tag":
+		p.IgnoreAutoTag, err = v.optionalBool()
+	case "aliases":
+		p.Aliases, err = v.updateStrings()
+	case "tag_ids":
+		p.TagIDs, err = v.updateIDs()
+	case "stash_ids":
+		p.StashIDs, err = v.updateStashIDs()
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownChangeField, field)
+	}
+
+	return err
+}
+
+// ChangeValues returns the audited fields of the movie.
+// Relationships that have not been loaded are omitted.
+func (m *Movie) ChangeValues() ChangeValues {
+	ret := ChangeValues{
+		"name":      m.Name,
+		"aliases":   m.Aliases,
+		"duration":  m.Duration,
+		"date":      dateChangeValue(m.Date),
+		"rating":    m.Rating,
+		"studio_id": m.StudioID,
+		"director":  m.Director,
+		"synopsis":  m.Synopsis,
+	}
+
+	if m.URLs.Loaded() {
+		ret["urls"] = stringsChangeValue(m.URLs.List())
+	}
+	if m.TagIDs.Loaded() {
+		ret["tag_ids"] = idsChangeValue(m.TagIDs.List())
+	}
+
+	return ret
+}
+
+// ChangeRelationships returns the audited relationships of the movie that
+// are loaded, and so are replaced when it is updated.
+func (m *Movie) ChangeRelationships() []string {
+	var ret []string
+	if m.URLs.Loaded() {
+		ret = append(ret, "urls")
+	}
+	if m.TagIDs.Loaded() {
+		ret = append(ret, "tag_ids")
+	}
+	return ret
+}
+
+// ChangeRelationships returns the audited relationships updated by the partial.
+func (p MoviePartial) ChangeRelationships() []string {
+	var ret []string
+	if p.URLs != nil {
+		ret = append(ret, "urls")
+	}
+	if p.TagIDs != nil {
+		ret = append(ret, "tag_ids")
+	}
+	return ret
+}
+
+// SetChangeValue sets the field in the partial to the JSON-encoded value
+// from a movie change.
+func (p *MoviePartial) SetChangeValue(field string, value string) error {
+	v := changeValue(value)
+	var err error
+	switch field {
+	case "name":
+		p.Name, err = v.optionalString()
+	case "aliases":
+		p.Aliases, err = v.optionalString()
+	case "duration":
+		p.Duration, err = v.optionalInt()
+	case "date":
+		p.Date, err = v.optionalDate()
+	case "rating":
+		p.Rating, err = v.optionalInt()
+	case "studio_id":
+		p.StudioID, err = v.optionalInt()
+	case "director":
+		p.Director, err = v.optionalString()
+	case "synopsis":
+		p.Synopsis, err = v.optionalString()
+	case "urls":
+		p.URLs, err = v.updateStrings()
+	case "tag_ids":
+		p.TagIDs, err = v.updateIDs()
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownChangeField, field)
+	}
+
+	return err
+}
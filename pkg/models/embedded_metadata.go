@@ -0,0 +1,126 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// EmbeddedMetadataMappings maps object fields to the keys of the embedded
+// file metadata that they are populated from. Keys are tried in order, and
+// the first non-empty value is used.
+type EmbeddedMetadataMappings struct {
+	Title        []string `json:"title"`
+	Date         []string `json:"date"`
+	Details      []string `json:"details"`
+	Performers   []string `json:"performers"`
+	Tags         []string `json:"tags"`
+	Photographer []string `json:"photographer"`
+}
+
+// DefaultEmbeddedMetadataMappings returns the default field mappings.
+// Container tags of video files are not prefixed, while image metadata keys
+// are prefixed with exif:, iptc: or xmp:.
+func DefaultEmbeddedMetadataMappings() EmbeddedMetadataMappings {
+	return EmbeddedMetadataMappings{
+		Title:        []string{"title", "xmp:title", "iptc:object_name", "exif:xp_title"},
+		Date:         []string{"date", "date_released", "xmp:date_created", "iptc:date_created", "exif:date_time_original"},
+		Details:      []string{"description", "synopsis", "comment", "xmp:description", "iptc:caption", "exif:image_description", "exif:xp_comment"},
+		Performers:   []string{"artist", "actor"},
+		Tags:         []string{"genre", "keywords", "xmp:subject", "iptc:keywords", "exif:xp_keywords"},
+		Photographer: []string{"xmp:creator", "iptc:by_line", "exif:artist"},
+	}
+}
+
+// WithDefaults returns a copy of the mappings with the unset fields set to
+// the default mappings.
+func (m EmbeddedMetadataMappings) WithDefaults() EmbeddedMetadataMappings {
+	def := DefaultEmbeddedMetadataMappings()
+	withDefault := func(v []string, d []string) []string {
+		if v == nil {
+			return d
+		}
+		return v
+	}
+
+	return EmbeddedMetadataMappings{
+		Title:        withDefault(m.Title, def.Title),
+		Date:         withDefault(m.Date, def.Date),
+		Details:      withDefault(m.Details, def.Details),
+		Performers:   withDefault(m.Performers, def.Performers),
+		Tags:         withDefault(m.Tags, def.Tags),
+		Photographer: withDefault(m.Photographer, def.Photographer),
+	}
+}
+
+// EmbeddedMetadataValues are the field values mapped from embedded file
+// metadata. Empty fields were not found in the metadata.
+type EmbeddedMetadataValues struct {
+	Title string
+	// Date in YYYY-MM-DD format
+	Date         string
+	Details      string
+	Performers   []string
+	Tags         []string
+	Photographer string
+}
+
+// IsEmpty returns true if none of the fields have values.
+func (v EmbeddedMetadataValues) IsEmpty() bool {
+	return v.Title == "" && v.Date == "" && v.Details == "" && len(v.Performers) == 0 && len(v.Tags) == 0 && v.Photographer == ""
+}
+
+// Values returns the field values of the embedded file metadata.
+func (m EmbeddedMetadataMappings) Values(metadata map[string]string) EmbeddedMetadataValues {
+	return EmbeddedMetadataValues{
+		Title:        firstMetadataValue(metadata, m.Title),
+		Date:         metadataDate(metadata, m.Date),
+		Details:      firstMetadataValue(metadata, m.Details),
+		Performers:   metadataList(firstMetadataValue(metadata, m.Performers)),
+		Tags:         metadataList(firstMetadataValue(metadata, m.Tags)),
+		Photographer: firstMetadataValue(metadata, m.Photographer),
+	}
+}
+
+func firstMetadataValue(metadata map[string]string, keys []string) string {
+	for _, k := range keys {
+		if v := strings.TrimSpace(metadata[strings.ToLower(k)]); v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// metadataDate returns the first value of the keys that is a valid date.
+// EXIF dates use colons as date separators.
+func metadataDate(metadata map[string]string, keys []string) string {
+	const dateLen = len("2006-01-02")
+
+	for _, k := range keys {
+		v := strings.TrimSpace(metadata[strings.ToLower(k)])
+		if len(v) < dateLen {
+			continue
+		}
+
+		v = strings.ReplaceAll(v[:dateLen], ":", "-")
+		if _, err := time.Parse("2006-01-02", v); err == nil {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// metadataList splits a list of values separated by semicolons or commas.
+func metadataList(v string) []string {
+	var ret []string
+	for _, s := range strings.FieldsFunc(v, func(r rune) bool {
+		return r == ';' || r == ','
+	}) {
+		if s = strings.TrimSpace(s); s != "" {
+			ret = append(ret, s)
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbeddedMetadataMappings_Values(t *testing.T) {
+	mappings := DefaultEmbeddedMetadataMappings()
+
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		want     EmbeddedMetadataValues
+	}{
+		{
+			"video",
+			map[string]string{
+				"title":       "Title",
+				"date":        "2020-01-02",
+				"comment":     "Comment",
+				"description": "Description",
+				"artist":      "Jane Doe; John Doe",
+				"genre":       "one, two,",
+			},
+			EmbeddedMetadataValues{
+				Title:      "Title",
+				Date:       "2020-01-02",
+				Details:    "Description",
+				Performers: []string{"Jane Doe", "John Doe"},
+				Tags:       []string{"one", "two"},
+			},
+		},
+		{
+			"image",
+			map[string]string{
+				"exif:artist":             "Photographer",
+				"exif:date_time_original": "2020:01:02 03:04:05",
+				"iptc:object_name":        " ",
+				"exif:xp_title":           "Title",
+			},
+			EmbeddedMetadataValues{
+				Title:        "Title",
+				Date:         "2020-01-02",
+				Photographer: "Photographer",
+			},
+		},
+		{
+			"invalid date",
+			map[string]string{
+				"date":              "2020",
+				"xmp:date_created":  "not a date",
+				"iptc:date_created": "2021-05-06",
+			},
+			EmbeddedMetadataValues{
+				Date: "2021-05-06",
+			},
+		},
+		{
+			"empty",
+			nil,
+			EmbeddedMetadataValues{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, mappings.Values(tt.metadata))
+		})
+	}
+}
+
+func TestEmbeddedMetadataMappings_WithDefaults(t *testing.T) {
+	m := EmbeddedMetadataMappings{
+		Title: []string{"custom"},
+		Tags:  []string{},
+	}.WithDefaults()
+
+	def := DefaultEmbeddedMetadataMappings()
+	assert.Equal(t, []string{"custom"}, m.Title)
+	assert.Equal(t, def.Date, m.Date)
+	// empty mappings disable the field
+	assert.Empty(t, m.Tags)
+	assert.True(t, m.Values(map[string]string{"genre": "tag"}).IsEmpty())
+}
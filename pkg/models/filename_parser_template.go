@@ -0,0 +1,21 @@
+package models
+
+import "context"
+
+type FilenameParserTemplateReader interface {
+	Find(ctx context.Context, id int) (*FilenameParserTemplate, error)
+	FindMany(ctx context.Context, ids []int) ([]*FilenameParserTemplate, error)
+	// All returns all templates, in the order that they are applied.
+	All(ctx context.Context) ([]*FilenameParserTemplate, error)
+}
+
+type FilenameParserTemplateWriter interface {
+	Create(ctx context.Context, newTemplate *FilenameParserTemplate) error
+	Update(ctx context.Context, updatedTemplate *FilenameParserTemplate) error
+	Destroy(ctx context.Context, id int) error
+}
+
+type FilenameParserTemplateReaderWriter interface {
+	FilenameParserTemplateReader
+	FilenameParserTemplateWriter
+}
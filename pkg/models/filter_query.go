@@ -0,0 +1,1332 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter queries are a compact text syntax for object filters. A filter query
+// consists of terms in the form field<operator>value, for example:
+//
+//	tag:"outdoor" -studio:X rating>=80 duration>20m performer_count:2
+//
+// Fields are the names of the filter type fields, as used in the graphql
+// schema. Singular forms of the field names are also accepted, for example
+// tag for tags, and rating for rating100.
+//
+// Supported operators are : (includes, or equals for numbers), = (equals),
+// != (not equals), >, >=, <, <= and ~ (matches regex). Numeric ranges are
+// expressed using min..max. Lists of related objects are separated using
+// commas, and related objects are referred to by name or id. The value null
+// matches missing values.
+//
+// Terms are combined using AND by default. OR (or |) and grouping using
+// parentheses are supported, and terms may be negated using - or NOT. Terms
+// without a field are free text, which is added to the search query. Free
+// text is only supported at the top level of the query.
+
+// FilterQueryResolver resolves the names of related objects in filter queries.
+type FilterQueryResolver interface {
+	// FindIDsByName returns the ids of the objects with the provided name or
+	// alias. objectType is one of tag, studio, performer, movie, gallery or
+	// scene.
+	FindIDsByName(ctx context.Context, objectType string, name string) ([]string, error)
+}
+
+// ErrFilterQueryTooComplex is returned when a filter query cannot be expressed
+// using the AND, OR and NOT sub-filters of the filter type.
+var ErrFilterQueryTooComplex = errors.New("query cannot be represented as a filter")
+
+const (
+	fqOpIncludes  = ":"
+	fqOpEquals    = "="
+	fqOpNotEquals = "!="
+	fqOpGreater   = ">"
+	fqOpGreaterEq = ">="
+	fqOpLess      = "<"
+	fqOpLessEq    = "<="
+	fqOpRegex     = "~"
+
+	fqRangeSeparator = ".."
+	fqListSeparator  = ','
+	fqNull           = "null"
+
+	fqMinDate = "0001-01-01"
+)
+
+var fqTermRE = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)(>=|<=|!=|:|=|>|<|~)(.*)$`)
+
+// fqRelatedObjectTypes maps the fields that refer to related objects to the
+// type of the related objects. parents and children refer to objects of the
+// same type as the filter.
+var fqRelatedObjectTypes = map[string]string{
+	"tags":           "tag",
+	"performer_tags": "tag",
+	"scene_tags":     "tag",
+	"studios":        "studio",
+	"performers":     "performer",
+	"movies":         "movie",
+	"galleries":      "gallery",
+	"scenes":         "scene",
+}
+
+type fqTokenType int
+
+const (
+	fqTokenWord fqTokenType = iota
+	fqTokenOpen
+	fqTokenClose
+	fqTokenOr
+	fqTokenNot
+)
+
+type fqToken struct {
+	typ  fqTokenType
+	word string
+}
+
+// tokenizeFilterQuery splits the query into tokens. Quoted values may contain
+// whitespace and parentheses.
+func tokenizeFilterQuery(q string) ([]fqToken, error) {
+	var ret []fqToken
+	var word strings.Builder
+	inQuote := false
+	escaped := false
+
+	flush := func() {
+		if word.Len() == 0 {
+			return
+		}
+
+		w := word.String()
+		word.Reset()
+
+		switch {
+		case strings.EqualFold(w, "OR") || w == "|":
+			ret = append(ret, fqToken{typ: fqTokenOr})
+		case strings.EqualFold(w, "AND"):
+			// terms are ANDed by default
+		case strings.EqualFold(w, "NOT"):
+			ret = append(ret, fqToken{typ: fqTokenNot})
+		default:
+			ret = append(ret, fqToken{typ: fqTokenWord, word: w})
+		}
+	}
+
+	for _, r := range q {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case inQuote && r == '\\':
+			word.WriteRune(r)
+			escaped = true
+		case r == '"':
+			word.WriteRune(r)
+			inQuote = !inQuote
+		case inQuote:
+			word.WriteRune(r)
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			flush()
+		case r == '(' || r == ')':
+			flush()
+			typ := fqTokenOpen
+			if r == ')' {
+				typ = fqTokenClose
+			}
+			ret = append(ret, fqToken{typ: typ})
+		case r == '-' && word.Len() == 0:
+			ret = append(ret, fqToken{typ: fqTokenNot})
+		default:
+			word.WriteRune(r)
+		}
+	}
+
+	if inQuote {
+		return nil, errors.New("unterminated quote")
+	}
+
+	flush()
+	return ret, nil
+}
+
+// unquoteFilterQueryValue removes the quotes and escape characters from a
+// value.
+func unquoteFilterQueryValue(v string) string {
+	var ret strings.Builder
+	escaped := false
+	inQuote := false
+	for _, r := range v {
+		switch {
+		case escaped:
+			ret.WriteRune(r)
+			escaped = false
+		case inQuote && r == '\\':
+			escaped = true
+		case r == '"':
+			inQuote = !inQuote
+		default:
+			ret.WriteRune(r)
+		}
+	}
+	return ret.String()
+}
+
+// splitFilterQueryList splits a value on commas outside of quotes, and
+// unquotes the values.
+func splitFilterQueryList(v string) []string {
+	var ret []string
+	start := 0
+	inQuote := false
+	escaped := false
+	for i, r := range v {
+		switch {
+		case escaped:
+			escaped = false
+		case inQuote && r == '\\':
+			escaped = true
+		case r == '"':
+			inQuote = !inQuote
+		case r == fqListSeparator && !inQuote:
+			ret = append(ret, unquoteFilterQueryValue(v[start:i]))
+			start = i + 1
+		}
+	}
+
+	return append(ret, unquoteFilterQueryValue(v[start:]))
+}
+
+type fqNodeType int
+
+const (
+	fqNodeTerm fqNodeType = iota
+	fqNodeAnd
+	fqNodeOr
+	fqNodeNot
+)
+
+type fqNode struct {
+	typ      fqNodeType
+	children []*fqNode
+
+	// field, op and value are set for term nodes. field is empty for free
+	// text.
+	field string
+	op    string
+	value string
+}
+
+func (n *fqNode) isText() bool {
+	return n.typ == fqNodeTerm && n.field == ""
+}
+
+type fqParser struct {
+	tokens []fqToken
+	pos    int
+}
+
+func (p *fqParser) peek() *fqToken {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos]
+}
+
+func (p *fqParser) parseOr() (*fqNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := left
+	for t := p.peek(); t != nil && t.typ == fqTokenOr; t = p.peek() {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		if ret.typ != fqNodeOr || ret == left {
+			ret = &fqNode{typ: fqNodeOr, children: []*fqNode{left}}
+		}
+		ret.children = append(ret.children, right)
+	}
+
+	return ret, nil
+}
+
+func (p *fqParser) parseAnd() (*fqNode, error) {
+	var children []*fqNode
+	for t := p.peek(); t != nil && t.typ != fqTokenOr && t.typ != fqTokenClose; t = p.peek() {
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, n)
+	}
+
+	switch len(children) {
+	case 0:
+		return nil, errors.New("expected term")
+	case 1:
+		return children[0], nil
+	}
+
+	return &fqNode{typ: fqNodeAnd, children: children}, nil
+}
+
+func (p *fqParser) parseUnary() (*fqNode, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, errors.New("expected term")
+	}
+	p.pos++
+
+	switch t.typ {
+	case fqTokenNot:
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &fqNode{typ: fqNodeNot, children: []*fqNode{n}}, nil
+	case fqTokenOpen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.peek(); t == nil || t.typ != fqTokenClose {
+			return nil, errors.New("expected )")
+		}
+		p.pos++
+		return n, nil
+	case fqTokenWord:
+		return parseFilterQueryTerm(t.word), nil
+	}
+
+	return nil, errors.New("unexpected )")
+}
+
+func parseFilterQueryTerm(w string) *fqNode {
+	m := fqTermRE.FindStringSubmatch(w)
+	if m == nil {
+		return &fqNode{typ: fqNodeTerm, value: unquoteFilterQueryValue(w)}
+	}
+
+	return &fqNode{
+		typ:   fqNodeTerm,
+		field: strings.ToLower(m[1]),
+		op:    m[2],
+		value: m[3],
+	}
+}
+
+// filterQueryBuilder builds filters of a filter type from parsed filter
+// queries.
+type filterQueryBuilder struct {
+	typ        reflect.Type
+	objectType string
+	resolver   FilterQueryResolver
+	// hasOperators is true if the filter type supports AND, OR and NOT
+	// sub-filters
+	hasOperators bool
+	// fields maps the json names of the criterion fields to the field
+	// indexes
+	fields map[string]int
+}
+
+func newFilterQueryBuilder(typ reflect.Type, resolver FilterQueryResolver) *filterQueryBuilder {
+	ret := &filterQueryBuilder{
+		typ:        typ,
+		objectType: strings.ToLower(strings.TrimSuffix(typ.Name(), "FilterType")),
+		resolver:   resolver,
+		fields:     make(map[string]int),
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.Anonymous {
+			ret.hasOperators = true
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name != "" && f.Type.Kind() == reflect.Pointer {
+			ret.fields[name] = i
+		}
+	}
+
+	return ret
+}
+
+// field returns the index of the field with the provided name, accepting
+// singular forms of plural field names.
+func (b *filterQueryBuilder) field(name string) (int, bool) {
+	candidates := []string{
+		name,
+		name + "s",
+		strings.TrimSuffix(name, "y") + "ies",
+		name + "100",
+	}
+
+	for _, c := range candidates {
+		if i, ok := b.fields[c]; ok {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// isMultiField returns true if the field refers to related objects.
+func (b *filterQueryBuilder) isMultiField(name string) bool {
+	i, ok := b.field(name)
+	if !ok {
+		return false
+	}
+
+	switch b.typ.Field(i).Type {
+	case reflect.TypeOf(&MultiCriterionInput{}), reflect.TypeOf(&HierarchicalMultiCriterionInput{}):
+		return true
+	}
+
+	return false
+}
+
+func (b *filterQueryBuilder) operator(f reflect.Value, name string) reflect.Value {
+	return f.Elem().FieldByName(name)
+}
+
+func (b *filterQueryBuilder) subFilter(f reflect.Value) (string, reflect.Value) {
+	if !b.hasOperators {
+		return "", reflect.Value{}
+	}
+
+	for _, op := range []string{"And", "Or", "Not"} {
+		if v := b.operator(f, op); !v.IsNil() {
+			return op, v
+		}
+	}
+
+	return "", reflect.Value{}
+}
+
+// criteria returns the indexes of the criterion fields that are set.
+func (b *filterQueryBuilder) criteria(f reflect.Value) []int {
+	var ret []int
+	for _, i := range b.fields {
+		if !f.Elem().Field(i).IsNil() {
+			ret = append(ret, i)
+		}
+	}
+	return ret
+}
+
+// normalise removes redundant sub-filters. A filter without criteria and with
+// an AND or OR sub-filter is equivalent to the sub-filter.
+func (b *filterQueryBuilder) normalise(f reflect.Value) reflect.Value {
+	for {
+		op, sub := b.subFilter(f)
+		if (op != "And" && op != "Or") || len(b.criteria(f)) > 0 {
+			return f
+		}
+		f = sub
+	}
+}
+
+// mergeFilterQueryMulti combines the values and excludes of two multi
+// criteria, so that both are required. Returns false if the criteria cannot
+// be combined.
+func mergeFilterQueryMulti(lValue []string, lModifier CriterionModifier, lExcludes []string, rValue []string, rModifier CriterionModifier, rExcludes []string) ([]string, CriterionModifier, []string, bool) {
+	normalise := func(v []string, m CriterionModifier, e []string) ([]string, []string, bool) {
+		switch {
+		case m == CriterionModifierExcludes:
+			return nil, append(e, v...), true
+		case m == CriterionModifierIncludesAll, m == CriterionModifierIncludes && len(v) <= 1:
+			return v, e, true
+		}
+		return nil, nil, false
+	}
+
+	lv, le, lok := normalise(lValue, lModifier, lExcludes)
+	rv, re, rok := normalise(rValue, rModifier, rExcludes)
+	if !lok || !rok {
+		return nil, "", nil, false
+	}
+
+	value := append(append([]string(nil), lv...), rv...)
+	excludes := append(append([]string(nil), le...), re...)
+	if len(value) == 0 {
+		return excludes, CriterionModifierExcludes, nil, true
+	}
+
+	return value, CriterionModifierIncludesAll, excludes, true
+}
+
+// mergeFilterQueryCriteria combines two criteria for the same field using
+// AND. Criteria for related objects cannot be repeated in sub-filters, so they
+// are combined where possible.
+func mergeFilterQueryCriteria(l, r reflect.Value) (reflect.Value, bool) {
+	switch lc := l.Interface().(type) {
+	case *MultiCriterionInput:
+		rc := r.Interface().(*MultiCriterionInput)
+		value, modifier, excludes, ok := mergeFilterQueryMulti(lc.Value, lc.Modifier, lc.Excludes, rc.Value, rc.Modifier, rc.Excludes)
+		if !ok {
+			return reflect.Value{}, false
+		}
+
+		return reflect.ValueOf(&MultiCriterionInput{
+			Value:    value,
+			Modifier: modifier,
+			Excludes: excludes,
+		}), true
+	case *HierarchicalMultiCriterionInput:
+		rc := r.Interface().(*HierarchicalMultiCriterionInput)
+		if !reflect.DeepEqual(lc.Depth, rc.Depth) {
+			return reflect.Value{}, false
+		}
+
+		value, modifier, excludes, ok := mergeFilterQueryMulti(lc.Value, lc.Modifier, lc.Excludes, rc.Value, rc.Modifier, rc.Excludes)
+		if !ok {
+			return reflect.Value{}, false
+		}
+
+		return reflect.ValueOf(&HierarchicalMultiCriterionInput{
+			Value:    value,
+			Modifier: modifier,
+			Depth:    lc.Depth,
+			Excludes: excludes,
+		}), true
+	}
+
+	return reflect.Value{}, false
+}
+
+// mergeFilterQueryAnyCriteria combines two criteria for the same field using
+// OR. Returns false if the criteria cannot be combined.
+func mergeFilterQueryAnyCriteria(l, r reflect.Value) (reflect.Value, bool) {
+	values := func(v []string, m CriterionModifier, e []string) ([]string, bool) {
+		if len(e) > 0 || len(v) == 0 {
+			return nil, false
+		}
+		return v, m == CriterionModifierIncludes || m == CriterionModifierIncludesAll && len(v) == 1
+	}
+
+	switch lc := l.Interface().(type) {
+	case *MultiCriterionInput:
+		rc := r.Interface().(*MultiCriterionInput)
+		lv, lok := values(lc.Value, lc.Modifier, lc.Excludes)
+		rv, rok := values(rc.Value, rc.Modifier, rc.Excludes)
+		if !lok || !rok {
+			return reflect.Value{}, false
+		}
+
+		return reflect.ValueOf(&MultiCriterionInput{
+			Value:    append(append([]string(nil), lv...), rv...),
+			Modifier: CriterionModifierIncludes,
+		}), true
+	case *HierarchicalMultiCriterionInput:
+		rc := r.Interface().(*HierarchicalMultiCriterionInput)
+		lv, lok := values(lc.Value, lc.Modifier, lc.Excludes)
+		rv, rok := values(rc.Value, rc.Modifier, rc.Excludes)
+		if !lok || !rok || !reflect.DeepEqual(lc.Depth, rc.Depth) {
+			return reflect.Value{}, false
+		}
+
+		return reflect.ValueOf(&HierarchicalMultiCriterionInput{
+			Value:    append(append([]string(nil), lv...), rv...),
+			Modifier: CriterionModifierIncludes,
+			Depth:    lc.Depth,
+		}), true
+	}
+
+	return reflect.Value{}, false
+}
+
+// validateRelatedFields returns an error if a criterion for related objects
+// is used in both a filter and its sub-filters. The criteria are joined using
+// the same table, so they cannot be evaluated independently.
+func (b *filterQueryBuilder) validateRelatedFields(f reflect.Value, used map[int]bool) error {
+	if !f.IsValid() || f.IsNil() {
+		return nil
+	}
+
+	subUsed := make(map[int]bool, len(used))
+	for i := range used {
+		subUsed[i] = true
+	}
+
+	for _, i := range b.criteria(f) {
+		field := b.typ.Field(i)
+		switch field.Type {
+		case reflect.TypeOf(&MultiCriterionInput{}), reflect.TypeOf(&HierarchicalMultiCriterionInput{}):
+		default:
+			continue
+		}
+
+		if used[i] {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			return fmt.Errorf("%s cannot be used in more than one group: %w", name, ErrFilterQueryTooComplex)
+		}
+		subUsed[i] = true
+	}
+
+	if !b.hasOperators {
+		return nil
+	}
+
+	for _, op := range []string{"And", "Or", "Not"} {
+		if err := b.validateRelatedFields(b.operator(f, op), subUsed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *filterQueryBuilder) and(l, r reflect.Value) (reflect.Value, error) {
+	l = b.normalise(l)
+	r = b.normalise(r)
+
+	lOp, lSub := b.subFilter(l)
+	rOp, _ := b.subFilter(r)
+
+	// merge the criteria if possible
+	if lOp == "" && (rOp == "" || rOp == "And" || rOp == "Not") {
+		merge := true
+		merged := make(map[int]reflect.Value)
+		for _, i := range b.criteria(r) {
+			if l.Elem().Field(i).IsNil() {
+				continue
+			}
+
+			m, ok := mergeFilterQueryCriteria(l.Elem().Field(i), r.Elem().Field(i))
+			if !ok {
+				merge = false
+				break
+			}
+			merged[i] = m
+		}
+
+		if merge {
+			for _, i := range b.criteria(r) {
+				if m, ok := merged[i]; ok {
+					l.Elem().Field(i).Set(m)
+				} else {
+					l.Elem().Field(i).Set(r.Elem().Field(i))
+				}
+			}
+			if rOp != "" {
+				b.operator(l, rOp).Set(b.operator(r, rOp))
+			}
+			return l, nil
+		}
+	}
+
+	if !b.hasOperators {
+		return reflect.Value{}, ErrFilterQueryTooComplex
+	}
+
+	switch {
+	case lOp == "":
+		b.operator(l, "And").Set(r)
+		return l, nil
+	case lOp == "And":
+		sub, err := b.and(lSub, r)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		b.operator(l, "And").Set(sub)
+		return l, nil
+	case rOp == "" || rOp == "And":
+		return b.and(r, l)
+	}
+
+	return reflect.Value{}, ErrFilterQueryTooComplex
+}
+
+func (b *filterQueryBuilder) or(l, r reflect.Value) (reflect.Value, error) {
+	l = b.normalise(l)
+	r = b.normalise(r)
+
+	lOp, lSub := b.subFilter(l)
+	rOp, _ := b.subFilter(r)
+
+	// combine criteria for the same related objects
+	if lCriteria, rCriteria := b.criteria(l), b.criteria(r); lOp == "" && rOp == "" && len(lCriteria) == 1 && len(rCriteria) == 1 && lCriteria[0] == rCriteria[0] {
+		i := lCriteria[0]
+		if m, ok := mergeFilterQueryAnyCriteria(l.Elem().Field(i), r.Elem().Field(i)); ok {
+			l.Elem().Field(i).Set(m)
+			return l, nil
+		}
+	}
+
+	if !b.hasOperators {
+		return reflect.Value{}, ErrFilterQueryTooComplex
+	}
+
+	switch {
+	case lOp == "":
+		b.operator(l, "Or").Set(r)
+		return l, nil
+	case lOp == "Or":
+		sub, err := b.or(lSub, r)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		b.operator(l, "Or").Set(sub)
+		return l, nil
+	case rOp == "" || rOp == "Or":
+		return b.or(r, l)
+	}
+
+	return reflect.Value{}, ErrFilterQueryTooComplex
+}
+
+func (b *filterQueryBuilder) build(ctx context.Context, n *fqNode) (reflect.Value, error) {
+	switch n.typ {
+	case fqNodeTerm:
+		if n.field == "" {
+			return reflect.Value{}, fmt.Errorf("free text %q must not be combined using OR or NOT", n.value)
+		}
+		return b.buildTerm(ctx, n)
+	case fqNodeNot:
+		// negated related objects are excluded using the criterion, so that
+		// they can be merged with other criteria for the same field
+		if c := n.children[0]; c.typ == fqNodeTerm && c.op == fqOpIncludes && b.isMultiField(c.field) {
+			return b.buildTerm(ctx, &fqNode{
+				typ:   fqNodeTerm,
+				field: c.field,
+				op:    fqOpNotEquals,
+				value: c.value,
+			})
+		}
+
+		if !b.hasOperators {
+			return reflect.Value{}, ErrFilterQueryTooComplex
+		}
+
+		sub, err := b.build(ctx, n.children[0])
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		ret := reflect.New(b.typ)
+		b.operator(ret, "Not").Set(sub)
+		return ret, nil
+	}
+
+	var ret reflect.Value
+	for _, c := range n.children {
+		f, err := b.build(ctx, c)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		switch {
+		case !ret.IsValid():
+			ret = f
+		case n.typ == fqNodeAnd:
+			ret, err = b.and(ret, f)
+		default:
+			ret, err = b.or(ret, f)
+		}
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	return ret, nil
+}
+
+func (b *filterQueryBuilder) buildTerm(ctx context.Context, n *fqNode) (reflect.Value, error) {
+	i, ok := b.field(n.field)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("unknown field %q", n.field)
+	}
+
+	field := b.typ.Field(i)
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+	v := reflect.New(field.Type.Elem())
+	if err := b.setValue(ctx, name, v.Interface(), n.op, n.value); err != nil {
+		return reflect.Value{}, fmt.Errorf("%s: %w", n.field, err)
+	}
+
+	ret := reflect.New(b.typ)
+	ret.Elem().Field(i).Set(v)
+	return ret, nil
+}
+
+func errFilterQueryOperator(op string) error {
+	return fmt.Errorf("operator %s is not supported", op)
+}
+
+// fqModifier returns the criterion modifier for comparison operators.
+func fqModifier(op string) (CriterionModifier, bool) {
+	switch op {
+	case fqOpIncludes, fqOpEquals:
+		return CriterionModifierEquals, true
+	case fqOpNotEquals:
+		return CriterionModifierNotEquals, true
+	case fqOpGreater:
+		return CriterionModifierGreaterThan, true
+	case fqOpLess:
+		return CriterionModifierLessThan, true
+	}
+
+	return "", false
+}
+
+// fqNullModifier returns the IS_NULL or NOT_NULL modifier if the value is
+// null.
+func fqNullModifier(op string, value string) (CriterionModifier, bool, error) {
+	if !strings.EqualFold(value, fqNull) {
+		return "", false, nil
+	}
+
+	switch op {
+	case fqOpIncludes, fqOpEquals:
+		return CriterionModifierIsNull, true, nil
+	case fqOpNotEquals:
+		return CriterionModifierNotNull, true, nil
+	}
+
+	return "", false, errFilterQueryOperator(op)
+}
+
+func parseFilterQueryBool(v string) (bool, error) {
+	switch strings.ToLower(v) {
+	case "true", "yes", "1":
+		return true, nil
+	case "false", "no", "0":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("invalid boolean value %q", v)
+}
+
+// parseFilterQueryInt parses an integer value, accepting durations such as
+// 20m or 1h30m, which are converted to seconds.
+func parseFilterQueryInt(v string) (int, error) {
+	if i, err := strconv.Atoi(v); err == nil {
+		return i, nil
+	}
+
+	if d, err := time.ParseDuration(v); err == nil {
+		return int(d.Seconds()), nil
+	}
+
+	return 0, fmt.Errorf("invalid number %q", v)
+}
+
+func fqRange(v string) (string, string, bool) {
+	return strings.Cut(v, fqRangeSeparator)
+}
+
+func fqBetweenModifier(op string) (CriterionModifier, error) {
+	switch op {
+	case fqOpIncludes, fqOpEquals:
+		return CriterionModifierBetween, nil
+	case fqOpNotEquals:
+		return CriterionModifierNotBetween, nil
+	}
+
+	return "", errFilterQueryOperator(op)
+}
+
+func (b *filterQueryBuilder) setInt(c *IntCriterionInput, op string, value string) error {
+	if lower, upper, isRange := fqRange(value); isRange {
+		var err error
+		c.Modifier, err = fqBetweenModifier(op)
+		if err != nil {
+			return err
+		}
+		if c.Value, err = parseFilterQueryInt(lower); err != nil {
+			return err
+		}
+		v2, err := parseFilterQueryInt(upper)
+		if err != nil {
+			return err
+		}
+		c.Value2 = &v2
+		return nil
+	}
+
+	v, err := parseFilterQueryInt(value)
+	if err != nil {
+		return err
+	}
+	c.Value = v
+
+	switch op {
+	case fqOpGreaterEq:
+		c.Modifier = CriterionModifierGreaterThan
+		c.Value = v - 1
+	case fqOpLessEq:
+		c.Modifier = CriterionModifierLessThan
+		c.Value = v + 1
+	default:
+		var ok bool
+		if c.Modifier, ok = fqModifier(op); !ok {
+			return errFilterQueryOperator(op)
+		}
+	}
+
+	return nil
+}
+
+func (b *filterQueryBuilder) setFloat(c *FloatCriterionInput, op string, value string) error {
+	parse := func(v string) (float64, error) {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", v)
+		}
+		return f, nil
+	}
+
+	if lower, upper, isRange := fqRange(value); isRange {
+		var err error
+		c.Modifier, err = fqBetweenModifier(op)
+		if err != nil {
+			return err
+		}
+		if c.Value, err = parse(lower); err != nil {
+			return err
+		}
+		v2, err := parse(upper)
+		if err != nil {
+			return err
+		}
+		c.Value2 = &v2
+		return nil
+	}
+
+	v, err := parse(value)
+	if err != nil {
+		return err
+	}
+	c.Value = v
+
+	switch op {
+	case fqOpGreaterEq:
+		c.Modifier = CriterionModifierBetween
+		v2 := math.MaxFloat64
+		c.Value2 = &v2
+	case fqOpLessEq:
+		c.Modifier = CriterionModifierBetween
+		c.Value = -math.MaxFloat64
+		c.Value2 = &v
+	default:
+		var ok bool
+		if c.Modifier, ok = fqModifier(op); !ok {
+			return errFilterQueryOperator(op)
+		}
+	}
+
+	return nil
+}
+
+// setDate sets the values of date and timestamp criteria.
+func (b *filterQueryBuilder) setDate(value *string, value2 **string, modifier *CriterionModifier, op string, v string) error {
+	if lower, upper, isRange := fqRange(v); isRange {
+		var err error
+		*modifier, err = fqBetweenModifier(op)
+		if err != nil {
+			return err
+		}
+		*value = lower
+		*value2 = &upper
+		return nil
+	}
+
+	*value = v
+
+	switch op {
+	case fqOpGreaterEq:
+		// the upper bound defaults to the current date
+		*modifier = CriterionModifierBetween
+	case fqOpLessEq:
+		*modifier = CriterionModifierBetween
+		*value = fqMinDate
+		*value2 = &v
+	default:
+		var ok bool
+		if *modifier, ok = fqModifier(op); !ok {
+			return errFilterQueryOperator(op)
+		}
+	}
+
+	return nil
+}
+
+// resolveIDs returns the ids of the related objects with the provided names.
+// Values that do not match any names are treated as ids.
+func (b *filterQueryBuilder) resolveIDs(ctx context.Context, field string, values []string) ([]string, error) {
+	objectType, ok := fqRelatedObjectTypes[field]
+	if !ok {
+		objectType = b.objectType
+	}
+
+	var ret []string
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+
+		var ids []string
+		if b.resolver != nil {
+			var err error
+			ids, err = b.resolver.FindIDsByName(ctx, objectType, v)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if len(ids) == 0 {
+			if _, err := strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("%s %q not found", objectType, v)
+			}
+			ids = []string{v}
+		}
+
+		ret = append(ret, ids...)
+	}
+
+	return ret, nil
+}
+
+func fqMultiModifier(op string, values []string) (CriterionModifier, error) {
+	switch op {
+	case fqOpIncludes:
+		if len(values) == 1 {
+			return CriterionModifierIncludesAll, nil
+		}
+		return CriterionModifierIncludes, nil
+	case fqOpEquals:
+		return CriterionModifierEquals, nil
+	case fqOpNotEquals:
+		return CriterionModifierExcludes, nil
+	}
+
+	return "", errFilterQueryOperator(op)
+}
+
+// fqEnumValue returns the enum value for the provided string, ignoring case
+// and accepting spaces and hyphens in place of underscores.
+func fqEnumValue(typ reflect.Type, v string) (reflect.Value, error) {
+	s := strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_").Replace(v))
+	ret := reflect.New(typ).Elem()
+	ret.SetString(s)
+
+	if e, ok := ret.Interface().(interface{ IsValid() bool }); ok && !e.IsValid() {
+		return reflect.Value{}, fmt.Errorf("invalid value %q", v)
+	}
+
+	return ret, nil
+}
+
+// fqEnumList sets the slice pointed to by list to the enum values.
+func fqEnumList(list any, values []string) error {
+	l := reflect.ValueOf(list).Elem()
+	for _, v := range values {
+		e, err := fqEnumValue(l.Type().Elem(), v)
+		if err != nil {
+			return err
+		}
+		l.Set(reflect.Append(l, e))
+	}
+	return nil
+}
+
+func fqEnumListModifier(op string) (CriterionModifier, error) {
+	switch op {
+	case fqOpIncludes, fqOpEquals:
+		return CriterionModifierIncludes, nil
+	case fqOpNotEquals:
+		return CriterionModifierExcludes, nil
+	}
+
+	return "", errFilterQueryOperator(op)
+}
+
+// fqResolution returns the resolution for the provided value. Heights such as
+// 1080p are accepted in addition to the resolution names.
+func fqResolution(v string) (ResolutionEnum, error) {
+	switch strings.ToLower(v) {
+	case "4k":
+		return ResolutionEnumFourK, nil
+	case "5k":
+		return ResolutionEnumFiveK, nil
+	case "6k":
+		return ResolutionEnumSixK, nil
+	case "7k":
+		return ResolutionEnumSevenK, nil
+	case "8k":
+		return ResolutionEnumEightK, nil
+	}
+
+	if h, err := strconv.Atoi(strings.TrimSuffix(strings.ToLower(v), "p")); err == nil {
+		for r, rr := range resolutionRanges {
+			if rr.min == h {
+				return r, nil
+			}
+		}
+	}
+
+	e, err := fqEnumValue(reflect.TypeOf(ResolutionEnum("")), v)
+	if err != nil {
+		return "", err
+	}
+
+	return e.Interface().(ResolutionEnum), nil
+}
+
+func (b *filterQueryBuilder) setValue(ctx context.Context, field string, ptr any, op string, value string) error {
+	switch c := ptr.(type) {
+	case *bool:
+		if op != fqOpIncludes && op != fqOpEquals {
+			return errFilterQueryOperator(op)
+		}
+		v, err := parseFilterQueryBool(value)
+		if err != nil {
+			return err
+		}
+		*c = v
+	case *string:
+		if op != fqOpIncludes && op != fqOpEquals {
+			return errFilterQueryOperator(op)
+		}
+		*c = unquoteFilterQueryValue(value)
+	case *int:
+		if op != fqOpIncludes && op != fqOpEquals {
+			return errFilterQueryOperator(op)
+		}
+		v, err := parseFilterQueryInt(value)
+		if err != nil {
+			return err
+		}
+		*c = v
+	case *StringCriterionInput:
+		if m, isNull, err := fqNullModifier(op, value); isNull || err != nil {
+			c.Modifier = m
+			return err
+		}
+
+		c.Value = unquoteFilterQueryValue(value)
+		switch op {
+		case fqOpIncludes:
+			c.Modifier = CriterionModifierIncludes
+		case fqOpEquals:
+			c.Modifier = CriterionModifierEquals
+		case fqOpNotEquals:
+			c.Modifier = CriterionModifierNotEquals
+		case fqOpRegex:
+			c.Modifier = CriterionModifierMatchesRegex
+		default:
+			return errFilterQueryOperator(op)
+		}
+	case *IntCriterionInput:
+		if m, isNull, err := fqNullModifier(op, value); isNull || err != nil {
+			c.Modifier = m
+			return err
+		}
+		return b.setInt(c, op, value)
+	case *FloatCriterionInput:
+		if m, isNull, err := fqNullModifier(op, value); isNull || err != nil {
+			c.Modifier = m
+			return err
+		}
+		return b.setFloat(c, op, value)
+	case *DateCriterionInput:
+		if m, isNull, err := fqNullModifier(op, value); isNull || err != nil {
+			c.Modifier = m
+			return err
+		}
+		return b.setDate(&c.Value, &c.Value2, &c.Modifier, op, unquoteFilterQueryValue(value))
+	case *TimestampCriterionInput:
+		if m, isNull, err := fqNullModifier(op, value); isNull || err != nil {
+			c.Modifier = m
+			return err
+		}
+		return b.setDate(&c.Value, &c.Value2, &c.Modifier, op, unquoteFilterQueryValue(value))
+	case *ResolutionCriterionInput:
+		var ok bool
+		if c.Modifier, ok = fqModifier(op); !ok {
+			return errFilterQueryOperator(op)
+		}
+		var err error
+		c.Value, err = fqResolution(unquoteFilterQueryValue(value))
+		return err
+	case *OrientationCriterionInput:
+		if op != fqOpIncludes && op != fqOpEquals {
+			return errFilterQueryOperator(op)
+		}
+		return fqEnumList(&c.Value, splitFilterQueryList(value))
+	case *GenderCriterionInput:
+		if m, isNull, err := fqNullModifier(op, value); isNull || err != nil {
+			c.Modifier = m
+			return err
+		}
+		var err error
+		if c.Modifier, err = fqEnumListModifier(op); err != nil {
+			return err
+		}
+		return fqEnumList(&c.ValueList, splitFilterQueryList(value))
+	case *CircumcisionCriterionInput:
+		if m, isNull, err := fqNullModifier(op, value); isNull || err != nil {
+			c.Modifier = m
+			return err
+		}
+		var err error
+		if c.Modifier, err = fqEnumListModifier(op); err != nil {
+			return err
+		}
+		return fqEnumList(&c.Value, splitFilterQueryList(value))
+	case *PHashDuplicationCriterionInput:
+		if op != fqOpIncludes && op != fqOpEquals {
+			return errFilterQueryOperator(op)
+		}
+		v, err := parseFilterQueryBool(value)
+		if err != nil {
+			return err
+		}
+		c.Duplicated = &v
+	case *MultiCriterionInput:
+		if m, isNull, err := fqNullModifier(op, value); isNull || err != nil {
+			c.Modifier = m
+			return err
+		}
+		var err error
+		if c.Value, err = b.resolveIDs(ctx, field, splitFilterQueryList(value)); err != nil {
+			return err
+		}
+		c.Modifier, err = fqMultiModifier(op, c.Value)
+		return err
+	case *HierarchicalMultiCriterionInput:
+		if m, isNull, err := fqNullModifier(op, value); isNull || err != nil {
+			c.Modifier = m
+			return err
+		}
+		var err error
+		if c.Value, err = b.resolveIDs(ctx, field, splitFilterQueryList(value)); err != nil {
+			return err
+		}
+		c.Modifier, err = fqMultiModifier(op, c.Value)
+		return err
+	default:
+		return errors.New("field is not supported in filter queries")
+	}
+
+	return nil
+}
+
+// ParseFilterQuery parses the filter query and combines the result with the
+// provided filter using AND. filter is not modified. T must be one of the
+// object filter types. The free text in the query is returned, to be used as
+// the search query.
+func ParseFilterQuery[T any](ctx context.Context, q string, filter *T, resolver FilterQueryResolver) (*T, string, error) {
+	ret, text, err := parseFilterQuery(ctx, q, filter, resolver)
+	if err != nil {
+		return nil, "", fmt.Errorf("parsing filter query: %w", err)
+	}
+
+	return ret, text, nil
+}
+
+func parseFilterQuery[T any](ctx context.Context, q string, filter *T, resolver FilterQueryResolver) (*T, string, error) {
+	tokens, err := tokenizeFilterQuery(q)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(tokens) == 0 {
+		return filter, "", nil
+	}
+
+	p := fqParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, "", err
+	}
+	if p.peek() != nil {
+		return nil, "", errors.New("unexpected )")
+	}
+
+	// extract the top-level free text
+	var text []string
+	nodes := []*fqNode{root}
+	if root.typ == fqNodeAnd {
+		nodes = root.children
+	}
+
+	var filterNodes []*fqNode
+	for _, n := range nodes {
+		switch {
+		case n.isText():
+			text = append(text, quoteSearchTerm(n.value))
+		case n.typ == fqNodeNot && n.children[0].isText():
+			text = append(text, "-"+quoteSearchTerm(n.children[0].value))
+		default:
+			filterNodes = append(filterNodes, n)
+		}
+	}
+
+	b := newFilterQueryBuilder(reflect.TypeOf((*T)(nil)).Elem(), resolver)
+
+	// copy the filter so that it is not modified
+	var ret reflect.Value
+	if filter != nil {
+		ret = reflect.New(b.typ)
+		data, err := json.Marshal(filter)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := json.Unmarshal(data, ret.Interface()); err != nil {
+			return nil, "", err
+		}
+	}
+
+	for _, n := range filterNodes {
+		f, err := b.build(ctx, n)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if !ret.IsValid() {
+			ret = f
+			continue
+		}
+
+		if ret, err = b.and(ret, f); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if !ret.IsValid() {
+		return filter, strings.Join(text, " "), nil
+	}
+
+	if err := b.validateRelatedFields(ret, nil); err != nil {
+		return nil, "", err
+	}
+
+	return ret.Interface().(*T), strings.Join(text, " "), nil
+}
+
+// quoteSearchTerm quotes the term if it contains whitespace, so that it is
+// treated as a phrase by ParseSearchString.
+func quoteSearchTerm(t string) string {
+	if strings.ContainsAny(t, " \t") {
+		return `"` + t + `"`
+	}
+	return t
+}
@@ -0,0 +1,314 @@
+package models
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testFilterQueryResolver map[string][]string
+
+func (r testFilterQueryResolver) FindIDsByName(ctx context.Context, objectType string, name string) ([]string, error) {
+	return r[objectType+":"+strings.ToLower(name)], nil
+}
+
+var filterQueryResolver = testFilterQueryResolver{
+	"tag:outdoor":    {"1"},
+	"tag:indoor":     {"2"},
+	"studio:x":       {"3"},
+	"performer:jane": {"4", "5"},
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+func TestParseFilterQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		q        string
+		want     *SceneFilterType
+		wantText string
+	}{
+		{
+			"empty",
+			"",
+			nil,
+			"",
+		},
+		{
+			"free text",
+			`foo "bar baz" -qux`,
+			nil,
+			`foo "bar baz" -qux`,
+		},
+		{
+			"combined",
+			`tag:"outdoor" -studio:X rating>=80 duration>20m performer_count:2`,
+			&SceneFilterType{
+				Tags: &HierarchicalMultiCriterionInput{
+					Value:    []string{"1"},
+					Modifier: CriterionModifierIncludesAll,
+				},
+				Rating100: &IntCriterionInput{
+					Value:    79,
+					Modifier: CriterionModifierGreaterThan,
+				},
+				Duration: &IntCriterionInput{
+					Value:    1200,
+					Modifier: CriterionModifierGreaterThan,
+				},
+				PerformerCount: &IntCriterionInput{
+					Value:    2,
+					Modifier: CriterionModifierEquals,
+				},
+				Studios: &HierarchicalMultiCriterionInput{
+					Value:    []string{"3"},
+					Modifier: CriterionModifierExcludes,
+				},
+			},
+			"",
+		},
+		{
+			"list",
+			`tags:outdoor,indoor performers=jane`,
+			&SceneFilterType{
+				Tags: &HierarchicalMultiCriterionInput{
+					Value:    []string{"1", "2"},
+					Modifier: CriterionModifierIncludes,
+				},
+				Performers: &MultiCriterionInput{
+					Value:    []string{"4", "5"},
+					Modifier: CriterionModifierEquals,
+				},
+			},
+			"",
+		},
+		{
+			"id",
+			`tag!=10`,
+			&SceneFilterType{
+				Tags: &HierarchicalMultiCriterionInput{
+					Value:    []string{"10"},
+					Modifier: CriterionModifierExcludes,
+				},
+			},
+			"",
+		},
+		{
+			"null and range",
+			`tag:null rating=20..60 date>=2020-01-01`,
+			&SceneFilterType{
+				Tags: &HierarchicalMultiCriterionInput{
+					Modifier: CriterionModifierIsNull,
+				},
+				Rating100: &IntCriterionInput{
+					Value:    20,
+					Value2:   intPtr(60),
+					Modifier: CriterionModifierBetween,
+				},
+				Date: &DateCriterionInput{
+					Value:    "2020-01-01",
+					Modifier: CriterionModifierBetween,
+				},
+			},
+			"",
+		},
+		{
+			"scalars",
+			`organized:yes title~"^a b" resolution:1080p`,
+			&SceneFilterType{
+				Organized: boolPtr(true),
+				Title: &StringCriterionInput{
+					Value:    "^a b",
+					Modifier: CriterionModifierMatchesRegex,
+				},
+				Resolution: &ResolutionCriterionInput{
+					Value:    ResolutionEnumFullHd,
+					Modifier: CriterionModifierEquals,
+				},
+			},
+			"",
+		},
+		{
+			"or",
+			`foo (tag:outdoor | tag:indoor) organized:true`,
+			&SceneFilterType{
+				Organized: boolPtr(true),
+				Tags: &HierarchicalMultiCriterionInput{
+					Value:    []string{"1", "2"},
+					Modifier: CriterionModifierIncludes,
+				},
+			},
+			"foo",
+		},
+		{
+			"or different fields",
+			`tag:outdoor | organized:true`,
+			&SceneFilterType{
+				Tags: &HierarchicalMultiCriterionInput{
+					Value:    []string{"1"},
+					Modifier: CriterionModifierIncludesAll,
+				},
+				OperatorFilter: OperatorFilter[SceneFilterType]{
+					Or: &SceneFilterType{
+						Organized: boolPtr(true),
+					},
+				},
+			},
+			"",
+		},
+		{
+			"repeated field",
+			`tag:outdoor tag:indoor`,
+			&SceneFilterType{
+				Tags: &HierarchicalMultiCriterionInput{
+					Value:    []string{"1", "2"},
+					Modifier: CriterionModifierIncludesAll,
+				},
+			},
+			"",
+		},
+		{
+			"repeated excluded field",
+			`tag:outdoor -tag:indoor`,
+			&SceneFilterType{
+				Tags: &HierarchicalMultiCriterionInput{
+					Value:    []string{"1"},
+					Modifier: CriterionModifierIncludesAll,
+					Excludes: []string{"2"},
+				},
+			},
+			"",
+		},
+		{
+			"repeated field not merged",
+			`rating>20 rating<80`,
+			&SceneFilterType{
+				Rating100: &IntCriterionInput{
+					Value:    20,
+					Modifier: CriterionModifierGreaterThan,
+				},
+				OperatorFilter: OperatorFilter[SceneFilterType]{
+					And: &SceneFilterType{
+						Rating100: &IntCriterionInput{
+							Value:    80,
+							Modifier: CriterionModifierLessThan,
+						},
+					},
+				},
+			},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, text, err := ParseFilterQuery[SceneFilterType](context.Background(), tt.q, nil, filterQueryResolver)
+			if err != nil {
+				t.Errorf("ParseFilterQuery() error = %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantText, text)
+		})
+	}
+}
+
+func TestParseFilterQueryFloat(t *testing.T) {
+	got, _, err := ParseFilterQuery[PerformerFilterType](context.Background(), "weight<=60", nil, filterQueryResolver)
+	if err != nil {
+		t.Errorf("ParseFilterQuery() error = %v", err)
+		return
+	}
+
+	assert.Equal(t, &IntCriterionInput{
+		Value:    61,
+		Modifier: CriterionModifierLessThan,
+	}, got.Weight)
+
+	got, _, err = ParseFilterQuery[PerformerFilterType](context.Background(), "penis_length>=15", nil, filterQueryResolver)
+	if err != nil {
+		t.Errorf("ParseFilterQuery() error = %v", err)
+		return
+	}
+
+	v2 := math.MaxFloat64
+	assert.Equal(t, &FloatCriterionInput{
+		Value:    15,
+		Value2:   &v2,
+		Modifier: CriterionModifierBetween,
+	}, got.PenisLength)
+}
+
+func TestParseFilterQueryCombinesFilter(t *testing.T) {
+	filter := &SceneFilterType{
+		Organized: boolPtr(true),
+		OperatorFilter: OperatorFilter[SceneFilterType]{
+			Or: &SceneFilterType{
+				Interactive: boolPtr(true),
+			},
+		},
+	}
+
+	got, _, err := ParseFilterQuery(context.Background(), "tag:outdoor", filter, filterQueryResolver)
+	if err != nil {
+		t.Errorf("ParseFilterQuery() error = %v", err)
+		return
+	}
+
+	assert.Equal(t, &SceneFilterType{
+		Tags: &HierarchicalMultiCriterionInput{
+			Value:    []string{"1"},
+			Modifier: CriterionModifierIncludesAll,
+		},
+		OperatorFilter: OperatorFilter[SceneFilterType]{
+			And: &SceneFilterType{
+				Organized: boolPtr(true),
+				OperatorFilter: OperatorFilter[SceneFilterType]{
+					Or: &SceneFilterType{
+						Interactive: boolPtr(true),
+					},
+				},
+			},
+		},
+	}, got)
+
+	// the provided filter is not modified
+	assert.Nil(t, filter.And)
+	assert.Nil(t, filter.Tags)
+}
+
+func TestParseFilterQueryErrors(t *testing.T) {
+	tests := []string{
+		"unknown:1",
+		"tag:nonexistent",
+		`tag:"outdoor`,
+		"(tag:outdoor",
+		"tag:outdoor)",
+		"foo | tag:outdoor",
+		"rating>abc",
+		"organized>true",
+		"resolution:huge-ish",
+		"tags_filter:1",
+		"(tag:outdoor | rating:20) (studio:x | rating:40)",
+		"(tag:outdoor | rating:20) tag:indoor",
+	}
+	for _, q := range tests {
+		t.Run(q, func(t *testing.T) {
+			_, _, err := ParseFilterQuery[SceneFilterType](context.Background(), q, nil, filterQueryResolver)
+			assert.Error(t, err)
+		})
+	}
+
+	// scene markers do not support sub-filters
+	_, _, err := ParseFilterQuery[SceneMarkerFilterType](context.Background(), "tag:outdoor | scene_tag:indoor", nil, filterQueryResolver)
+	assert.ErrorIs(t, err, ErrFilterQueryTooComplex)
+}
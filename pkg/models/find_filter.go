@@ -52,8 +52,11 @@ func (e SortDirectionEnum) MarshalGQL(w io.Writer) {
 }
 
 type FindFilterType struct {
-	Q    *string `json:"q"`
-	Page *int    `json:"page"`
+	Q *string `json:"q"`
+	// FilterQuery is parsed using ParseFilterQuery and combined with the
+	// object filter.
+	FilterQuery *string `json:"filter_query"`
+	Page        *int    `json:"page"`
 	// use per_page = -1 to indicate all results. Defaults to 25.
 	PerPage   *int               `json:"per_page"`
 	Sort      *string            `json:"sort"`
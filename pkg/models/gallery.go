@@ -63,6 +63,8 @@ type GalleryFilterType struct {
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
 	UpdatedAt *TimestampCriterionInput `json:"updated_at"`
+	// Filter to only include galleries matching these saved filters
+	SavedFilters *MultiCriterionInput `json:"saved_filters"`
 }
 
 type GalleryUpdateInput struct {
@@ -0,0 +1,22 @@
+package models
+
+import "context"
+
+type IdentifyProposalReader interface {
+	Find(ctx context.Context, id int) (*IdentifyProposal, error)
+	FindMany(ctx context.Context, ids []int) ([]*IdentifyProposal, error)
+	// Query returns the proposals matching the filter, newest first.
+	Query(ctx context.Context, proposalFilter *IdentifyProposalFilterType, findFilter *FindFilterType) ([]*IdentifyProposal, int, error)
+}
+
+type IdentifyProposalWriter interface {
+	// Create stores a new pending proposal, replacing any pending proposal
+	// for the same scene.
+	Create(ctx context.Context, newProposal *IdentifyProposal) error
+	UpdateStatus(ctx context.Context, id int, status IdentifyProposalStatus) error
+}
+
+type IdentifyProposalReaderWriter interface {
+	IdentifyProposalReader
+	IdentifyProposalWriter
+}
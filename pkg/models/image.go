@@ -61,6 +61,8 @@ type ImageFilterType struct {
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
 	UpdatedAt *TimestampCriterionInput `json:"updated_at"`
+	// Filter to only include images matching these saved filters
+	SavedFilters *MultiCriterionInput `json:"saved_filters"`
 }
 
 type ImageDestroyInput struct {
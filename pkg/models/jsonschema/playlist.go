@@ -0,0 +1,62 @@
+package jsonschema
+
+import (
+	"fmt"
+	"os"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/models/json"
+)
+
+// PlaylistSceneMarker refers to a scene marker by the path of the primary
+// file of its scene, its position and title.
+type PlaylistSceneMarker struct {
+	Scene   string `json:"scene,omitempty"`
+	Title   string `json:"title,omitempty"`
+	Seconds string `json:"seconds,omitempty"`
+}
+
+// PlaylistItem refers to exactly one of a scene or image, by the path of its
+// primary file, or a scene marker.
+type PlaylistItem struct {
+	Scene       string               `json:"scene,omitempty"`
+	SceneMarker *PlaylistSceneMarker `json:"scene_marker,omitempty"`
+	Image       string               `json:"image,omitempty"`
+	EndSeconds  string               `json:"end_seconds,omitempty"`
+}
+
+type Playlist struct {
+	Name        string         `json:"name,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Items       []PlaylistItem `json:"items,omitempty"`
+	CreatedAt   json.JSONTime  `json:"created_at,omitempty"`
+	UpdatedAt   json.JSONTime  `json:"updated_at,omitempty"`
+}
+
+func (s Playlist) Filename() string {
+	return fsutil.SanitiseBasename(s.Name) + ".json"
+}
+
+func LoadPlaylistFile(filePath string) (*Playlist, error) {
+	var playlist Playlist
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	jsonParser := json.NewDecoder(file)
+	err = jsonParser.Decode(&playlist)
+	if err != nil {
+		return nil, err
+	}
+	return &playlist, nil
+}
+
+func SavePlaylistFile(filePath string, playlist *Playlist) error {
+	if playlist == nil {
+		return fmt.Errorf("playlist must not be nil")
+	}
+	return marshalToFile(filePath, playlist)
+}
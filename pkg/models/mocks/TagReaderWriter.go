@@ -630,6 +630,27 @@ func (_m *TagReaderWriter) Query(ctx context.Context, tagFilter *models.TagFilte
 	return r0, r1, r2
 }
 
+// QueryCount provides a mock function with given fields: ctx, tagFilter, findFilter
+func (_m *TagReaderWriter) QueryCount(ctx context.Context, tagFilter *models.TagFilterType, findFilter *models.FindFilterType) (int, error) {
+	ret := _m.Called(ctx, tagFilter, findFilter)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, *models.TagFilterType, *models.FindFilterType) int); ok {
+		r0 = rf(ctx, tagFilter, findFilter)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *models.TagFilterType, *models.FindFilterType) error); ok {
+		r1 = rf(ctx, tagFilter, findFilter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // QueryForAutoTag provides a mock function with given fields: ctx, words
 func (_m *TagReaderWriter) QueryForAutoTag(ctx context.Context, words []string) ([]*models.Tag, error) {
 	ret := _m.Called(ctx, words)
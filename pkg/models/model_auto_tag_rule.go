@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// AutoTagRuleConditions are the conditions that an item must match for an
+// auto-tag rule to be applied to it. Conditions that are not set are
+// ignored. An item must match all of the conditions that are set.
+type AutoTagRuleConditions struct {
+	// Regular expression matched against the path of the item
+	PathRegex *string `json:"path_regex,omitempty"`
+	// Folder that the item must be in, including subfolders
+	Folder *string `json:"folder,omitempty"`
+	// Duration range in seconds. Items without a duration do not match.
+	MinDuration *int `json:"min_duration,omitempty"`
+	MaxDuration *int `json:"max_duration,omitempty"`
+	// Resolution range. Items without a resolution do not match.
+	MinResolution *ResolutionEnum `json:"min_resolution,omitempty"`
+	MaxResolution *ResolutionEnum `json:"max_resolution,omitempty"`
+	// The item must have all of these tags
+	TagIDs []int `json:"tag_ids,omitempty"`
+	// The item must have none of these tags
+	ExcludedTagIDs []int `json:"excluded_tag_ids,omitempty"`
+	// The studio of the item must be one of these studios
+	StudioIDs []int `json:"studio_ids,omitempty"`
+}
+
+// AutoTagRuleActions are the changes made to the items that match an
+// auto-tag rule.
+type AutoTagRuleActions struct {
+	AddTagIDs       []int `json:"add_tag_ids,omitempty"`
+	AddPerformerIDs []int `json:"add_performer_ids,omitempty"`
+	StudioID        *int  `json:"studio_id,omitempty"`
+	Organized       *bool `json:"organized,omitempty"`
+	// Movie to add scenes to. Not applied to images or galleries.
+	MovieID *int `json:"movie_id,omitempty"`
+	// Gallery to add scenes and images to. Not applied to galleries.
+	GalleryID *int `json:"gallery_id,omitempty"`
+}
+
+// AutoTagRule is a user-defined rule applied by the auto-tag task.
+type AutoTagRule struct {
+	ID         int                   `json:"id"`
+	Name       string                `json:"name"`
+	Enabled    bool                  `json:"enabled"`
+	Conditions AutoTagRuleConditions `json:"conditions"`
+	Actions    AutoTagRuleActions    `json:"actions"`
+	CreatedAt  time.Time             `json:"created_at"`
+	UpdatedAt  time.Time             `json:"updated_at"`
+}
+
+func NewAutoTagRule() AutoTagRule {
+	currentTime := time.Now()
+	return AutoTagRule{
+		Enabled:   true,
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+	}
+}
@@ -0,0 +1,206 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type ChangeObjectType string
+
+const (
+	ChangeObjectTypeScene     ChangeObjectType = "SCENE"
+	ChangeObjectTypeImage     ChangeObjectType = "IMAGE"
+	ChangeObjectTypeGallery   ChangeObjectType = "GALLERY"
+	ChangeObjectTypePerformer ChangeObjectType = "PERFORMER"
+	ChangeObjectTypeStudio    ChangeObjectType = "STUDIO"
+	ChangeObjectTypeMovie     ChangeObjectType = "MOVIE"
+)
+
+var AllChangeObjectType = []ChangeObjectType{
+	ChangeObjectTypeScene,
+	ChangeObjectTypeImage,
+	ChangeObjectTypeGallery,
+	ChangeObjectTypePerformer,
+	ChangeObjectTypeStudio,
+	ChangeObjectTypeMovie,
+}
+
+func (e ChangeObjectType) IsValid() bool {
+	switch e {
+	case ChangeObjectTypeScene, ChangeObjectTypeImage, ChangeObjectTypeGallery, ChangeObjectTypePerformer, ChangeObjectTypeStudio, ChangeObjectTypeMovie:
+		return true
+	}
+	return false
+}
+
+func (e ChangeObjectType) String() string {
+	return string(e)
+}
+
+func (e *ChangeObjectType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ChangeObjectType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ChangeObjectType", str)
+	}
+	return nil
+}
+
+func (e ChangeObjectType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ChangeOriginType string
+
+const (
+	// ChangeOriginTypeUser is a change made directly by the user.
+	ChangeOriginTypeUser ChangeOriginType = "USER"
+	// ChangeOriginTypeIdentify is a change made by the identify task.
+	// The origin ID is the ID of the scraper source.
+	ChangeOriginTypeIdentify ChangeOriginType = "IDENTIFY"
+	// ChangeOriginTypeAutoTag is a change made by the auto-tag task.
+	ChangeOriginTypeAutoTag ChangeOriginType = "AUTO_TAG"
+	// ChangeOriginTypePlugin is a change made by a plugin.
+	// The origin ID is the ID of the plugin.
+	ChangeOriginTypePlugin ChangeOriginType = "PLUGIN"
+	// ChangeOriginTypeImport is a change made by importing metadata.
+	ChangeOriginTypeImport ChangeOriginType = "IMPORT"
+	// ChangeOriginTypeStashBox is a change applied from a stash-box update.
+	// The origin ID is the stash-box endpoint.
+	ChangeOriginTypeStashBox ChangeOriginType = "STASH_BOX"
+	// ChangeOriginTypeScan is a change made by the scan task.
+	// Scan changes are not recorded, so it is not a valid origin type.
+	ChangeOriginTypeScan ChangeOriginType = "SCAN"
+)
+
+var AllChangeOriginType = []ChangeOriginType{
+	ChangeOriginTypeUser,
+	ChangeOriginTypeIdentify,
+	ChangeOriginTypeAutoTag,
+	ChangeOriginTypePlugin,
+	ChangeOriginTypeImport,
+	ChangeOriginTypeStashBox,
+}
+
+func (e ChangeOriginType) IsValid() bool {
+	switch e {
+	case ChangeOriginTypeUser, ChangeOriginTypeIdentify, ChangeOriginTypeAutoTag, ChangeOriginTypePlugin, ChangeOriginTypeImport, ChangeOriginTypeStashBox:
+		return true
+	}
+	return false
+}
+
+func (e ChangeOriginType) String() string {
+	return string(e)
+}
+
+func (e *ChangeOriginType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ChangeOriginType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ChangeOriginType", str)
+	}
+	return nil
+}
+
+func (e ChangeOriginType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// ChangeOrigin describes what made a change.
+type ChangeOrigin struct {
+	Type ChangeOriginType
+	// ID of the identify source or plugin that made the change.
+	ID *string
+}
+
+type changeOriginKey struct{}
+
+// WithChangeOrigin returns a context that attributes changes made with it to origin.
+func WithChangeOrigin(ctx context.Context, origin ChangeOrigin) context.Context {
+	return context.WithValue(ctx, changeOriginKey{}, origin)
+}
+
+// ChangeOriginFromContext returns the origin of changes made with ctx.
+// Changes are attributed to the user if no origin is set.
+func ChangeOriginFromContext(ctx context.Context) ChangeOrigin {
+	if ret, ok := ctx.Value(changeOriginKey{}).(ChangeOrigin); ok {
+		return ret
+	}
+
+	return ChangeOrigin{Type: ChangeOriginTypeUser}
+}
+
+// Change is a single field change made to an object.
+type Change struct {
+	ID         int              `json:"id"`
+	ObjectType ChangeObjectType `json:"object_type"`
+	ObjectID   int              `json:"object_id"`
+	Field      string           `json:"field"`
+	// JSON-encoded value of the field before the change
+	OldValue string `json:"old_value"`
+	// JSON-encoded value of the field after the change
+	NewValue   string           `json:"new_value"`
+	OriginType ChangeOriginType `json:"origin_type"`
+	OriginID   *string          `json:"origin_id"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+// ChangeValues maps the audited fields of an object to their values.
+type ChangeValues map[string]interface{}
+
+// Diff returns a change for each field with a different value in other.
+// The returned changes only have the field and values set.
+func (v ChangeValues) Diff(other ChangeValues) ([]*Change, error) {
+	fields := make([]string, 0, len(v))
+	for f := range v {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+
+	var ret []*Change
+	for _, f := range fields {
+		oldValue, err := json.Marshal(v[f])
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s: %w", f, err)
+		}
+		newValue, err := json.Marshal(other[f])
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s: %w", f, err)
+		}
+
+		if bytes.Equal(oldValue, newValue) {
+			continue
+		}
+
+		ret = append(ret, &Change{
+			Field:    f,
+			OldValue: string(oldValue),
+			NewValue: string(newValue),
+		})
+	}
+
+	return ret, nil
+}
+
+type ChangeFilterType struct {
+	ObjectType *ChangeObjectType `json:"object_type"`
+	ObjectID   *int              `json:"object_id"`
+	Field      *string           `json:"field"`
+	OriginType *ChangeOriginType `json:"origin_type"`
+	OriginID   *string           `json:"origin_id"`
+}
@@ -130,6 +130,10 @@ type BaseFile struct {
 
 	Size int64 `json:"size"`
 
+	// Metadata embedded in the file, such as container tags and EXIF data.
+	// Nil if the metadata has not been extracted.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// FilenameParserTemplate is a saved scene filename parser pattern. Enabled
+// templates are applied to new scenes during the scan.
+type FilenameParserTemplate struct {
+	ID      int              `json:"id"`
+	Name    string           `json:"name"`
+	Pattern string           `json:"pattern"`
+	Options SceneParserInput `json:"options"`
+	// Folders that the template applies to, including subfolders. The
+	// template applies to all scenes if empty.
+	Paths     []string  `json:"paths"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewFilenameParserTemplate() FilenameParserTemplate {
+	currentTime := time.Now()
+	return FilenameParserTemplate{
+		Enabled:   true,
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+	}
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type IdentifyProposalStatus string
+
+const (
+	// IdentifyProposalStatusPending is a proposal waiting for review.
+	IdentifyProposalStatusPending IdentifyProposalStatus = "PENDING"
+	// IdentifyProposalStatusAccepted is a proposal that has been applied.
+	IdentifyProposalStatusAccepted IdentifyProposalStatus = "ACCEPTED"
+	// IdentifyProposalStatusRejected is a proposal that was not applied.
+	IdentifyProposalStatusRejected IdentifyProposalStatus = "REJECTED"
+)
+
+var AllIdentifyProposalStatus = []IdentifyProposalStatus{
+	IdentifyProposalStatusPending,
+	IdentifyProposalStatusAccepted,
+	IdentifyProposalStatusRejected,
+}
+
+func (e IdentifyProposalStatus) IsValid() bool {
+	switch e {
+	case IdentifyProposalStatusPending, IdentifyProposalStatusAccepted, IdentifyProposalStatusRejected:
+		return true
+	}
+	return false
+}
+
+func (e IdentifyProposalStatus) String() string {
+	return string(e)
+}
+
+func (e *IdentifyProposalStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = IdentifyProposalStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid IdentifyProposalStatus", str)
+	}
+	return nil
+}
+
+func (e IdentifyProposalStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// IdentifyFieldDiff is a change to a single field proposed by identify.
+type IdentifyFieldDiff struct {
+	Field string `json:"field"`
+	// JSON-encoded value of the field before the change
+	OldValue string `json:"old_value"`
+	// JSON-encoded value of the field after the change
+	NewValue string `json:"new_value"`
+}
+
+// IdentifyProposal is a scene match found by a dry run of the identify task.
+type IdentifyProposal struct {
+	ID         int      `json:"id"`
+	SceneID    int      `json:"scene_id"`
+	SourceID   string   `json:"source_id"`
+	SourceName string   `json:"source_name"`
+	Confidence *float64 `json:"confidence"`
+	// changes that would have been made when the proposal was created
+	Diffs []*IdentifyFieldDiff `json:"diffs"`
+	// Data is the encoded scrape result used to apply the proposal.
+	Data      string                 `json:"data"`
+	Status    IdentifyProposalStatus `json:"status"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+type IdentifyProposalFilterType struct {
+	Status   *IdentifyProposalStatus `json:"status"`
+	SceneID  *int                    `json:"scene_id"`
+	SourceID *string                 `json:"source_id"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Playlist is a user-curated, ordered list of scenes, scene markers and
+// images.
+type Playlist struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func NewPlaylist() Playlist {
+	currentTime := time.Now()
+	return Playlist{
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+	}
+}
+
+// PlaylistItem is an entry in a playlist. Exactly one of SceneID,
+// SceneMarkerID and ImageID is set.
+type PlaylistItem struct {
+	ID            int  `json:"id"`
+	SceneID       *int `json:"scene_id"`
+	SceneMarkerID *int `json:"scene_marker_id"`
+	ImageID       *int `json:"image_id"`
+	// EndSeconds is the end of the clip for scene marker items. The clip
+	// starts at the marker, and plays to the end of the scene if EndSeconds
+	// is nil.
+	EndSeconds *float64 `json:"end_seconds"`
+}
+
+var (
+	ErrPlaylistItemObject     = errors.New("playlist item must have exactly one of scene, scene marker or image")
+	ErrPlaylistItemEndSeconds = errors.New("end seconds may only be set for scene marker items")
+)
+
+func (i PlaylistItem) Validate() error {
+	set := 0
+	for _, id := range []*int{i.SceneID, i.SceneMarkerID, i.ImageID} {
+		if id != nil {
+			set++
+		}
+	}
+
+	if set != 1 {
+		return ErrPlaylistItemObject
+	}
+
+	if i.EndSeconds != nil && i.SceneMarkerID == nil {
+		return ErrPlaylistItemEndSeconds
+	}
+
+	return nil
+}
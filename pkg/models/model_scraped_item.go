@@ -16,6 +16,8 @@ type ScrapedStudio struct {
 	Parent       *ScrapedStudio `json:"parent"`
 	Image        *string        `json:"image"`
 	Images       []string       `json:"images"`
+	Details      *string        `json:"details"`
+	Aliases      *string        `json:"aliases"`
 	RemoteSiteID *string        `json:"remote_site_id"`
 }
 
@@ -385,8 +387,12 @@ func (p *ScrapedPerformer) ToPartial(endpoint string, excluded map[string]bool,
 
 type ScrapedTag struct {
 	// Set if tag matched
-	StoredID *string `json:"stored_id"`
-	Name     string  `json:"name"`
+	StoredID    *string       `json:"stored_id"`
+	Name        string        `json:"name"`
+	Description *string       `json:"description"`
+	Aliases     *string       `json:"aliases"`
+	Image       *string       `json:"image"`
+	Parents     []*ScrapedTag `json:"parents"`
 }
 
 func (ScrapedTag) IsScrapedContent() {}
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type StashBoxSubmissionType string
+
+const (
+	// StashBoxSubmissionTypeFingerprints submits the fingerprints of a scene.
+	StashBoxSubmissionTypeFingerprints StashBoxSubmissionType = "FINGERPRINTS"
+	// StashBoxSubmissionTypeSceneDraft submits a scene as a draft.
+	StashBoxSubmissionTypeSceneDraft StashBoxSubmissionType = "SCENE_DRAFT"
+	// StashBoxSubmissionTypePerformerDraft submits a performer as a draft.
+	StashBoxSubmissionTypePerformerDraft StashBoxSubmissionType = "PERFORMER_DRAFT"
+)
+
+var AllStashBoxSubmissionType = []StashBoxSubmissionType{
+	StashBoxSubmissionTypeFingerprints,
+	StashBoxSubmissionTypeSceneDraft,
+	StashBoxSubmissionTypePerformerDraft,
+}
+
+func (e StashBoxSubmissionType) IsValid() bool {
+	switch e {
+	case StashBoxSubmissionTypeFingerprints, StashBoxSubmissionTypeSceneDraft, StashBoxSubmissionTypePerformerDraft:
+		return true
+	}
+	return false
+}
+
+func (e StashBoxSubmissionType) String() string {
+	return string(e)
+}
+
+func (e *StashBoxSubmissionType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StashBoxSubmissionType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StashBoxSubmissionType", str)
+	}
+	return nil
+}
+
+func (e StashBoxSubmissionType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type StashBoxSubmissionStatus string
+
+const (
+	// StashBoxSubmissionStatusPending is a submission waiting to be sent.
+	StashBoxSubmissionStatusPending StashBoxSubmissionStatus = "PENDING"
+	// StashBoxSubmissionStatusSubmitted is a submission accepted by stash-box.
+	StashBoxSubmissionStatusSubmitted StashBoxSubmissionStatus = "SUBMITTED"
+	// StashBoxSubmissionStatusFailed is a submission rejected by stash-box, or
+	// that could not be sent after the maximum number of attempts.
+	StashBoxSubmissionStatusFailed StashBoxSubmissionStatus = "FAILED"
+)
+
+var AllStashBoxSubmissionStatus = []StashBoxSubmissionStatus{
+	StashBoxSubmissionStatusPending,
+	StashBoxSubmissionStatusSubmitted,
+	StashBoxSubmissionStatusFailed,
+}
+
+func (e StashBoxSubmissionStatus) IsValid() bool {
+	switch e {
+	case StashBoxSubmissionStatusPending, StashBoxSubmissionStatusSubmitted, StashBoxSubmissionStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e StashBoxSubmissionStatus) String() string {
+	return string(e)
+}
+
+func (e *StashBoxSubmissionStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StashBoxSubmissionStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StashBoxSubmissionStatus", str)
+	}
+	return nil
+}
+
+func (e StashBoxSubmissionStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// StashBoxSubmission is a queued submission to a stash-box instance.
+type StashBoxSubmission struct {
+	ID          int                      `json:"id"`
+	Type        StashBoxSubmissionType   `json:"type"`
+	Endpoint    string                   `json:"endpoint"`
+	SceneID     *int                     `json:"scene_id"`
+	PerformerID *int                     `json:"performer_id"`
+	Status      StashBoxSubmissionStatus `json:"status"`
+	Attempts    int                      `json:"attempts"`
+	LastError   *string                  `json:"last_error"`
+	// RemoteID is the ID of the draft created on stash-box.
+	RemoteID *string `json:"remote_id"`
+	// NextAttemptAt is when a pending submission is next attempted.
+	NextAttemptAt *time.Time `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func NewStashBoxSubmission(submissionType StashBoxSubmissionType, endpoint string) StashBoxSubmission {
+	currentTime := time.Now()
+	return StashBoxSubmission{
+		Type:          submissionType,
+		Endpoint:      endpoint,
+		Status:        StashBoxSubmissionStatusPending,
+		NextAttemptAt: &currentTime,
+		CreatedAt:     currentTime,
+		UpdatedAt:     currentTime,
+	}
+}
+
+type StashBoxSubmissionFilterType struct {
+	Type        *StashBoxSubmissionType   `json:"type"`
+	Status      *StashBoxSubmissionStatus `json:"status"`
+	Endpoint    *string                   `json:"endpoint"`
+	SceneID     *int                      `json:"scene_id"`
+	PerformerID *int                      `json:"performer_id"`
+}
+
+// SubmittedFingerprint is a scene fingerprint that has been submitted to a
+// stash-box instance.
+type SubmittedFingerprint struct {
+	Endpoint  string `json:"endpoint"`
+	StashID   string `json:"stash_id"`
+	Algorithm string `json:"algorithm"`
+	Hash      string `json:"hash"`
+}
@@ -0,0 +1,195 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type StashBoxUpdateType string
+
+const (
+	// StashBoxUpdateTypeUpdated is an object that was changed on stash-box.
+	StashBoxUpdateTypeUpdated StashBoxUpdateType = "UPDATED"
+	// StashBoxUpdateTypeMerged is an object that was merged into another object on stash-box.
+	StashBoxUpdateTypeMerged StashBoxUpdateType = "MERGED"
+	// StashBoxUpdateTypeDeleted is an object that was deleted from stash-box.
+	StashBoxUpdateTypeDeleted StashBoxUpdateType = "DELETED"
+)
+
+var AllStashBoxUpdateType = []StashBoxUpdateType{
+	StashBoxUpdateTypeUpdated,
+	StashBoxUpdateTypeMerged,
+	StashBoxUpdateTypeDeleted,
+}
+
+func (e StashBoxUpdateType) IsValid() bool {
+	switch e {
+	case StashBoxUpdateTypeUpdated, StashBoxUpdateTypeMerged, StashBoxUpdateTypeDeleted:
+		return true
+	}
+	return false
+}
+
+func (e StashBoxUpdateType) String() string {
+	return string(e)
+}
+
+func (e *StashBoxUpdateType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StashBoxUpdateType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StashBoxUpdateType", str)
+	}
+	return nil
+}
+
+func (e StashBoxUpdateType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type StashBoxUpdateStatus string
+
+const (
+	// StashBoxUpdateStatusPending is an update waiting for review.
+	StashBoxUpdateStatusPending StashBoxUpdateStatus = "PENDING"
+	// StashBoxUpdateStatusApplied is an update that has been applied.
+	StashBoxUpdateStatusApplied StashBoxUpdateStatus = "APPLIED"
+	// StashBoxUpdateStatusDismissed is an update that was not applied.
+	StashBoxUpdateStatusDismissed StashBoxUpdateStatus = "DISMISSED"
+)
+
+var AllStashBoxUpdateStatus = []StashBoxUpdateStatus{
+	StashBoxUpdateStatusPending,
+	StashBoxUpdateStatusApplied,
+	StashBoxUpdateStatusDismissed,
+}
+
+func (e StashBoxUpdateStatus) IsValid() bool {
+	switch e {
+	case StashBoxUpdateStatusPending, StashBoxUpdateStatusApplied, StashBoxUpdateStatusDismissed:
+		return true
+	}
+	return false
+}
+
+func (e StashBoxUpdateStatus) String() string {
+	return string(e)
+}
+
+func (e *StashBoxUpdateStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StashBoxUpdateStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StashBoxUpdateStatus", str)
+	}
+	return nil
+}
+
+func (e StashBoxUpdateStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type StashBoxFieldStrategy string
+
+const (
+	// StashBoxFieldStrategyIgnore leaves the field unchanged.
+	StashBoxFieldStrategyIgnore StashBoxFieldStrategy = "IGNORE"
+	// StashBoxFieldStrategyReview stores the change to the field for review.
+	StashBoxFieldStrategyReview StashBoxFieldStrategy = "REVIEW"
+	// StashBoxFieldStrategyApply applies the change to the field immediately.
+	StashBoxFieldStrategyApply StashBoxFieldStrategy = "APPLY"
+)
+
+var AllStashBoxFieldStrategy = []StashBoxFieldStrategy{
+	StashBoxFieldStrategyIgnore,
+	StashBoxFieldStrategyReview,
+	StashBoxFieldStrategyApply,
+}
+
+func (e StashBoxFieldStrategy) IsValid() bool {
+	switch e {
+	case StashBoxFieldStrategyIgnore, StashBoxFieldStrategyReview, StashBoxFieldStrategyApply:
+		return true
+	}
+	return false
+}
+
+func (e StashBoxFieldStrategy) String() string {
+	return string(e)
+}
+
+func (e *StashBoxFieldStrategy) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StashBoxFieldStrategy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StashBoxFieldStrategy", str)
+	}
+	return nil
+}
+
+func (e StashBoxFieldStrategy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// StashBoxFieldDiff is a difference between the value of a local field and
+// the value from stash-box.
+type StashBoxFieldDiff struct {
+	Field string `json:"field"`
+	// JSON-encoded local value of the field
+	OldValue string `json:"old_value"`
+	// JSON-encoded value of the field from stash-box
+	NewValue string `json:"new_value"`
+}
+
+// StashBoxUpdate is a change to a linked stash-box object that was found by
+// the stash-box update check.
+type StashBoxUpdate struct {
+	ID         int                `json:"id"`
+	ObjectType ChangeObjectType   `json:"object_type"`
+	ObjectID   int                `json:"object_id"`
+	Endpoint   string             `json:"endpoint"`
+	StashID    string             `json:"stash_id"`
+	Type       StashBoxUpdateType `json:"type"`
+	// ID of the stash-box object that the object was merged into
+	MergedStashID *string `json:"merged_stash_id"`
+	// changes to review, as of when the update was found
+	Diffs             []*StashBoxFieldDiff `json:"diffs"`
+	Status            StashBoxUpdateStatus `json:"status"`
+	UpstreamUpdatedAt *time.Time           `json:"upstream_updated_at"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+}
+
+// StashBoxUpstreamState is the state of a linked stash-box object as of the
+// last update check.
+type StashBoxUpstreamState struct {
+	ObjectType        ChangeObjectType `json:"object_type"`
+	ObjectID          int              `json:"object_id"`
+	Endpoint          string           `json:"endpoint"`
+	StashID           string           `json:"stash_id"`
+	Deleted           bool             `json:"deleted"`
+	UpstreamUpdatedAt *time.Time       `json:"upstream_updated_at"`
+	CheckedAt         time.Time        `json:"checked_at"`
+}
+
+type StashBoxUpdateFilterType struct {
+	ObjectType *ChangeObjectType     `json:"object_type"`
+	ObjectID   *int                  `json:"object_id"`
+	Endpoint   *string               `json:"endpoint"`
+	Type       *StashBoxUpdateType   `json:"type"`
+	Status     *StashBoxUpdateStatus `json:"status"`
+}
@@ -31,4 +31,6 @@ type MovieFilterType struct {
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
 	UpdatedAt *TimestampCriterionInput `json:"updated_at"`
+	// Filter to only include movies matching these saved filters
+	SavedFilters *MultiCriterionInput `json:"saved_filters"`
 }
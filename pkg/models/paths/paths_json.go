@@ -20,6 +20,7 @@ type JSONPaths struct {
 	Tags       string
 	Movies     string
 	Files      string
+	Playlists  string
 }
 
 func newJSONPaths(baseDir string) *JSONPaths {
@@ -34,6 +35,7 @@ func newJSONPaths(baseDir string) *JSONPaths {
 	jp.Movies = filepath.Join(baseDir, "movies")
 	jp.Tags = filepath.Join(baseDir, "tags")
 	jp.Files = filepath.Join(baseDir, "files")
+	jp.Playlists = filepath.Join(baseDir, "playlists")
 	return &jp
 }
 
@@ -52,6 +54,7 @@ func EmptyJSONDirs(baseDir string) {
 	_ = fsutil.EmptyDir(jsonPaths.Movies)
 	_ = fsutil.EmptyDir(jsonPaths.Tags)
 	_ = fsutil.EmptyDir(jsonPaths.Files)
+	_ = fsutil.EmptyDir(jsonPaths.Playlists)
 }
 
 func EnsureJSONDirs(baseDir string) {
@@ -83,4 +86,7 @@ func EnsureJSONDirs(baseDir string) {
 	if err := fsutil.EnsureDir(jsonPaths.Files); err != nil {
 		logger.Warnf("couldn't create directories for Files: %v", err)
 	}
+	if err := fsutil.EnsureDir(jsonPaths.Playlists); err != nil {
+		logger.Warnf("couldn't create directories for Playlists: %v", err)
+	}
 }
@@ -0,0 +1,30 @@
+package models
+
+import "context"
+
+type PlaylistReader interface {
+	Find(ctx context.Context, id int) (*Playlist, error)
+	FindMany(ctx context.Context, ids []int) ([]*Playlist, error)
+	FindByName(ctx context.Context, name string, nocase bool) (*Playlist, error)
+	All(ctx context.Context) ([]*Playlist, error)
+	// Query returns the playlists with names matching the query of the find
+	// filter, and the total number of matching playlists.
+	Query(ctx context.Context, findFilter *FindFilterType) ([]*Playlist, int, error)
+	// GetItems returns the items of the playlist, in order.
+	GetItems(ctx context.Context, playlistID int) ([]PlaylistItem, error)
+}
+
+type PlaylistWriter interface {
+	Create(ctx context.Context, newPlaylist *Playlist) error
+	Update(ctx context.Context, updatedPlaylist *Playlist) error
+	Destroy(ctx context.Context, id int) error
+	// UpdateItems sets the items of the playlist, in order. Items are matched
+	// to the existing items by ID. Items without an ID are created, and
+	// existing items that are not provided are removed.
+	UpdateItems(ctx context.Context, playlistID int, items []PlaylistItem) error
+}
+
+type PlaylistReaderWriter interface {
+	PlaylistReader
+	PlaylistWriter
+}
@@ -14,19 +14,26 @@ type TxnManager interface {
 type Repository struct {
 	TxnManager TxnManager
 
-	Blob           BlobReader
-	File           FileReaderWriter
-	Folder         FolderReaderWriter
-	Gallery        GalleryReaderWriter
-	GalleryChapter GalleryChapterReaderWriter
-	Image          ImageReaderWriter
-	Movie          MovieReaderWriter
-	Performer      PerformerReaderWriter
-	Scene          SceneReaderWriter
-	SceneMarker    SceneMarkerReaderWriter
-	Studio         StudioReaderWriter
-	Tag            TagReaderWriter
-	SavedFilter    SavedFilterReaderWriter
+	Blob                   BlobReader
+	File                   FileReaderWriter
+	Folder                 FolderReaderWriter
+	Gallery                GalleryReaderWriter
+	GalleryChapter         GalleryChapterReaderWriter
+	Image                  ImageReaderWriter
+	Movie                  MovieReaderWriter
+	Performer              PerformerReaderWriter
+	Scene                  SceneReaderWriter
+	SceneMarker            SceneMarkerReaderWriter
+	Studio                 StudioReaderWriter
+	Tag                    TagReaderWriter
+	SavedFilter            SavedFilterReaderWriter
+	Change                 ChangeReader
+	IdentifyProposal       IdentifyProposalReaderWriter
+	StashBoxSubmission     StashBoxSubmissionReaderWriter
+	StashBoxUpdate         StashBoxUpdateReaderWriter
+	AutoTagRule            AutoTagRuleReaderWriter
+	FilenameParserTemplate FilenameParserTemplateReaderWriter
+	Playlist               PlaylistReaderWriter
 }
 
 func (r *Repository) WithTxn(ctx context.Context, fn txn.TxnFunc) error {
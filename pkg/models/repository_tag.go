@@ -30,6 +30,7 @@ type TagFinder interface {
 // TagQueryer provides methods to query tags.
 type TagQueryer interface {
 	Query(ctx context.Context, tagFilter *TagFilterType, findFilter *FindFilterType) ([]*Tag, int, error)
+	QueryCount(ctx context.Context, tagFilter *TagFilterType, findFilter *FindFilterType) (int, error)
 }
 
 type TagAutoTagQueryer interface {
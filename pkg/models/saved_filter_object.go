@@ -0,0 +1,342 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// savedCriterion is a criterion of a saved object filter. Saved filters
+// created by the UI store criterion values in the format used by the UI, for
+// example, labelled ids for related objects, and nested values for ranges.
+type savedCriterion map[string]interface{}
+
+func (c savedCriterion) value() interface{} {
+	return c["value"]
+}
+
+// nested returns the value of the criterion as a map, if it is one.
+func (c savedCriterion) nested() (map[string]interface{}, bool) {
+	v, ok := c.value().(map[string]interface{})
+	return v, ok
+}
+
+// savedCriterionIDs returns the ids of a list of labelled ids, or of a list of
+// ids.
+func savedCriterionIDs(v interface{}) ([]string, error) {
+	l, ok := v.([]interface{})
+	if !ok {
+		if v == nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("invalid list value %v", v)
+	}
+
+	ret := make([]string, len(l))
+	for i, item := range l {
+		switch item := item.(type) {
+		case string:
+			ret[i] = item
+		case float64:
+			ret[i] = strconv.Itoa(int(item))
+		case map[string]interface{}:
+			id, ok := item["id"].(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid labelled id %v", item)
+			}
+			ret[i] = id
+		default:
+			return nil, fmt.Errorf("invalid list value %v", item)
+		}
+	}
+
+	return ret, nil
+}
+
+func savedCriterionBool(v interface{}) (bool, error) {
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(v)
+	}
+
+	return false, fmt.Errorf("invalid boolean value %v", v)
+}
+
+// savedCriterionEnums converts the enum labels to enum values of the provided
+// type.
+func savedCriterionEnums(typ reflect.Type, v interface{}) ([]string, error) {
+	var values []interface{}
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		// older saved filters store single values
+		values = []interface{}{v}
+	case []interface{}:
+		values = v
+	default:
+		return nil, fmt.Errorf("invalid list value %v", v)
+	}
+
+	ret := make([]string, len(values))
+	for i, item := range values {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid value %v", item)
+		}
+
+		e, err := fqEnumValue(typ, s)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = e.String()
+	}
+
+	return ret, nil
+}
+
+// convertMultiCriterion converts labelled ids, and items, excluded and depth
+// values.
+func (c savedCriterion) convertMultiCriterion(hierarchical bool) (interface{}, error) {
+	nested, isNested := c.nested()
+	if !isNested {
+		ids, err := savedCriterionIDs(c.value())
+		if err != nil {
+			return nil, err
+		}
+		c["value"] = ids
+		return c, nil
+	}
+
+	var err error
+	if c["value"], err = savedCriterionIDs(nested["items"]); err != nil {
+		return nil, err
+	}
+	if c["excludes"], err = savedCriterionIDs(nested["excluded"]); err != nil {
+		return nil, err
+	}
+	if hierarchical {
+		c["depth"] = nested["depth"]
+	}
+
+	return c, nil
+}
+
+// flatten moves the values of a nested value map to the criterion.
+func (c savedCriterion) flatten(keys map[string]string) interface{} {
+	nested, ok := c.nested()
+	if !ok {
+		return c
+	}
+
+	delete(c, "value")
+	for from, to := range keys {
+		if v, ok := nested[from]; ok {
+			c[to] = v
+		}
+	}
+
+	return c
+}
+
+// savedCaptionLanguages maps the caption language names used by the UI to
+// language codes.
+var savedCaptionLanguages = map[string]string{
+	"Deutsche":  "de",
+	"English":   "en",
+	"Español":   "es",
+	"Français":  "fr",
+	"Italiano":  "it",
+	"日本":        "ja",
+	"한국인":       "ko",
+	"Holandés":  "nl",
+	"Português": "pt",
+	"Русский":   "ru",
+	"Unknown":   "00",
+}
+
+var savedCriterionRangeKeys = map[string]string{
+	"value":  "value",
+	"value2": "value2",
+}
+
+func (c savedCriterion) convert(typ reflect.Type) (interface{}, error) {
+	modifier, _ := c["modifier"].(string)
+	isNull := modifier == string(CriterionModifierIsNull) || modifier == string(CriterionModifierNotNull)
+
+	switch typ {
+	case reflect.TypeOf(false):
+		return savedCriterionBool(c.value())
+	case reflect.TypeOf(""), reflect.TypeOf(0):
+		return c.value(), nil
+	}
+
+	if isNull {
+		delete(c, "value")
+		return c, nil
+	}
+
+	switch typ {
+	case reflect.TypeOf(MultiCriterionInput{}):
+		return c.convertMultiCriterion(false)
+	case reflect.TypeOf(HierarchicalMultiCriterionInput{}):
+		return c.convertMultiCriterion(true)
+	case reflect.TypeOf(IntCriterionInput{}), reflect.TypeOf(FloatCriterionInput{}),
+		reflect.TypeOf(DateCriterionInput{}), reflect.TypeOf(TimestampCriterionInput{}):
+		return c.flatten(savedCriterionRangeKeys), nil
+	case reflect.TypeOf(PhashDistanceCriterionInput{}):
+		return c.flatten(map[string]string{
+			"value":    "value",
+			"distance": "distance",
+		}), nil
+	case reflect.TypeOf(StashIDCriterionInput{}):
+		return c.flatten(map[string]string{
+			"endpoint": "endpoint",
+			"stashID":  "stash_id",
+		}), nil
+	case reflect.TypeOf(PHashDuplicationCriterionInput{}):
+		if v, ok := c["value"]; ok {
+			duplicated, err := savedCriterionBool(v)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"duplicated": duplicated}, nil
+		}
+	case reflect.TypeOf(ResolutionCriterionInput{}):
+		if s, ok := c.value().(string); ok {
+			r, err := fqResolution(s)
+			if err != nil {
+				return nil, err
+			}
+			c["value"] = r
+		}
+	case reflect.TypeOf(OrientationCriterionInput{}):
+		var err error
+		c["value"], err = savedCriterionEnums(reflect.TypeOf(OrientationEnum("")), c.value())
+		return c, err
+	case reflect.TypeOf(CircumcisionCriterionInput{}):
+		var err error
+		c["value"], err = savedCriterionEnums(reflect.TypeOf(CircumisedEnum("")), c.value())
+		return c, err
+	case reflect.TypeOf(GenderCriterionInput{}):
+		if _, ok := c["value_list"]; ok {
+			return c, nil
+		}
+
+		values, err := savedCriterionEnums(reflect.TypeOf(GenderEnum("")), c.value())
+		if err != nil {
+			return nil, err
+		}
+		delete(c, "value")
+		c["value_list"] = values
+	}
+
+	return c, nil
+}
+
+// DecodeSavedObjectFilter converts the object filter of a saved filter to the
+// filter type T. Criteria in the format saved by the UI are converted to the
+// corresponding filter inputs. Returns an error if the object filter contains
+// unknown criteria.
+func DecodeSavedObjectFilter[T any](objectFilter map[string]interface{}) (*T, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.Anonymous {
+			// sub-filters are stored in the filter input format
+			for j := 0; j < f.Type.NumField(); j++ {
+				name, _, _ := strings.Cut(f.Type.Field(j).Tag.Get("json"), ",")
+				fields[name] = nil
+			}
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name != "" && f.Type.Kind() == reflect.Pointer {
+			fields[name] = f.Type.Elem()
+		}
+	}
+
+	converted := make(map[string]interface{}, len(objectFilter))
+	for name, v := range objectFilter {
+		fieldType, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown criterion %q", name)
+		}
+
+		m, isCriterion := v.(map[string]interface{})
+		if fieldType == nil || fieldType.Kind() == reflect.Struct && strings.HasSuffix(fieldType.Name(), "FilterType") || !isCriterion {
+			// already in the filter input format
+			converted[name] = v
+			continue
+		}
+
+		// copy the criterion so that the saved filter is not modified
+		c := make(savedCriterion, len(m))
+		for k, v := range m {
+			c[k] = v
+		}
+
+		if name == "captions" {
+			if code, ok := savedCaptionLanguages[fmt.Sprint(c.value())]; ok {
+				c["value"] = code
+			}
+		}
+
+		var err error
+		if converted[name], err = c.convert(fieldType); err != nil {
+			return nil, fmt.Errorf("converting criterion %q: %w", name, err)
+		}
+	}
+
+	data, err := json.Marshal(converted)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret T
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, fmt.Errorf("decoding object filter: %w", err)
+	}
+
+	return &ret, nil
+}
+
+// GetFindFilter returns the find filter of the saved filter, with the
+// non-nil fields of override taking precedence. The search query is taken
+// from override only, since the query of the saved filter is part of the
+// saved filter criterion.
+func (f SavedFilter) GetFindFilter(override *FindFilterType) *FindFilterType {
+	ret := &FindFilterType{}
+	if f.FindFilter != nil {
+		ret.Page = f.FindFilter.Page
+		ret.PerPage = f.FindFilter.PerPage
+		ret.Sort = f.FindFilter.Sort
+		ret.Direction = f.FindFilter.Direction
+	}
+
+	if override != nil {
+		ret.Q = override.Q
+		ret.FilterQuery = override.FilterQuery
+		if override.Page != nil {
+			ret.Page = override.Page
+		}
+		if override.PerPage != nil {
+			ret.PerPage = override.PerPage
+		}
+		if override.Sort != nil {
+			ret.Sort = override.Sort
+		}
+		if override.Direction != nil {
+			ret.Direction = override.Direction
+		}
+	}
+
+	return ret
+}
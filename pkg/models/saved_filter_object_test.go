@@ -0,0 +1,183 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeSavedObjectFilter(t *testing.T) {
+	const savedFilter = `{
+		"organized": {"modifier": "EQUALS", "value": "true"},
+		"title": {"modifier": "INCLUDES", "value": "beach"},
+		"rating100": {"modifier": "BETWEEN", "value": {"value": 20, "value2": 60}},
+		"studios": {"modifier": "INCLUDES", "value": {"items": [{"id": "1", "label": "Studio"}], "excluded": [{"id": "2", "label": "Other"}], "depth": -1}},
+		"movies": {"modifier": "INCLUDES", "value": [{"id": "3", "label": "Movie"}]},
+		"resolution": {"modifier": "EQUALS", "value": "1080p"},
+		"orientation": {"modifier": "INCLUDES", "value": ["Landscape"]},
+		"duplicated": {"modifier": "EQUALS", "value": "true"},
+		"stash_id_endpoint": {"modifier": "EQUALS", "value": {"endpoint": "https://stashdb.org", "stashID": "abc"}},
+		"captions": {"modifier": "INCLUDES", "value": "English"},
+		"performer_count": {"modifier": "GREATER_THAN", "value": {"value": 2}},
+		"AND": {"tags": {"modifier": "INCLUDES", "value": ["4"]}}
+	}`
+
+	var objectFilter map[string]interface{}
+	if err := json.Unmarshal([]byte(savedFilter), &objectFilter); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := DecodeSavedObjectFilter[SceneFilterType](objectFilter)
+	if err != nil {
+		t.Fatalf("DecodeSavedObjectFilter() error = %v", err)
+	}
+
+	depth := -1
+	endpoint := "https://stashdb.org"
+	stashID := "abc"
+	want := &SceneFilterType{
+		OperatorFilter: OperatorFilter[SceneFilterType]{
+			And: &SceneFilterType{
+				Tags: &HierarchicalMultiCriterionInput{
+					Value:    []string{"4"},
+					Modifier: CriterionModifierIncludes,
+				},
+			},
+		},
+		Organized: boolPtr(true),
+		Title: &StringCriterionInput{
+			Value:    "beach",
+			Modifier: CriterionModifierIncludes,
+		},
+		Rating100: &IntCriterionInput{
+			Value:    20,
+			Value2:   intPtr(60),
+			Modifier: CriterionModifierBetween,
+		},
+		Studios: &HierarchicalMultiCriterionInput{
+			Value:    []string{"1"},
+			Excludes: []string{"2"},
+			Depth:    &depth,
+			Modifier: CriterionModifierIncludes,
+		},
+		Movies: &MultiCriterionInput{
+			Value:    []string{"3"},
+			Modifier: CriterionModifierIncludes,
+		},
+		Resolution: &ResolutionCriterionInput{
+			Value:    ResolutionEnumFullHd,
+			Modifier: CriterionModifierEquals,
+		},
+		Orientation: &OrientationCriterionInput{
+			Value: []OrientationEnum{OrientationLandscape},
+		},
+		Duplicated: &PHashDuplicationCriterionInput{
+			Duplicated: boolPtr(true),
+		},
+		StashIDEndpoint: &StashIDCriterionInput{
+			Endpoint: &endpoint,
+			StashID:  &stashID,
+			Modifier: CriterionModifierEquals,
+		},
+		Captions: &StringCriterionInput{
+			Value:    "en",
+			Modifier: CriterionModifierIncludes,
+		},
+		PerformerCount: &IntCriterionInput{
+			Value:    2,
+			Modifier: CriterionModifierGreaterThan,
+		},
+	}
+
+	assert.Equal(t, want, got)
+
+	// decoding a filter in the filter input format should not change it
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	objectFilter = nil
+	if err := json.Unmarshal(data, &objectFilter); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = DecodeSavedObjectFilter[SceneFilterType](objectFilter)
+	if err != nil {
+		t.Fatalf("DecodeSavedObjectFilter() error = %v", err)
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestDecodeSavedObjectFilterPerformer(t *testing.T) {
+	objectFilter := map[string]interface{}{
+		"gender": map[string]interface{}{
+			"modifier": "INCLUDES",
+			"value":    []interface{}{"Transgender Female", "Non-Binary"},
+		},
+	}
+
+	got, err := DecodeSavedObjectFilter[PerformerFilterType](objectFilter)
+	if err != nil {
+		t.Fatalf("DecodeSavedObjectFilter() error = %v", err)
+	}
+
+	assert.Equal(t, &PerformerFilterType{
+		Gender: &GenderCriterionInput{
+			ValueList: []GenderEnum{GenderEnumTransgenderFemale, GenderEnumNonBinary},
+			Modifier:  CriterionModifierIncludes,
+		},
+	}, got)
+}
+
+func TestDecodeSavedObjectFilterErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		objectFilter map[string]interface{}
+	}{
+		{"unknown criterion", map[string]interface{}{
+			"unknown": map[string]interface{}{"modifier": "EQUALS", "value": "x"},
+		}},
+		{"invalid boolean", map[string]interface{}{
+			"organized": map[string]interface{}{"modifier": "EQUALS", "value": "maybe"},
+		}},
+		{"invalid enum", map[string]interface{}{
+			"orientation": map[string]interface{}{"modifier": "INCLUDES", "value": []interface{}{"Round"}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeSavedObjectFilter[SceneFilterType](tt.objectFilter); err == nil {
+				t.Errorf("DecodeSavedObjectFilter() expected error")
+			}
+		})
+	}
+}
+
+func TestSavedFilterGetFindFilter(t *testing.T) {
+	sort := "date"
+	otherSort := "title"
+	q := "beach"
+	savedQ := "saved"
+	perPage := 40
+	direction := SortDirectionEnumDesc
+
+	f := SavedFilter{
+		FindFilter: &FindFilterType{
+			Q:         &savedQ,
+			PerPage:   &perPage,
+			Sort:      &sort,
+			Direction: &direction,
+		},
+	}
+
+	assert.Equal(t, &FindFilterType{
+		Q:         &q,
+		PerPage:   &perPage,
+		Sort:      &otherSort,
+		Direction: &direction,
+	}, f.GetFindFilter(&FindFilterType{
+		Q:    &q,
+		Sort: &otherSort,
+	}))
+}
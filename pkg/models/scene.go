@@ -111,6 +111,10 @@ type SceneFilterType struct {
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
 	UpdatedAt *TimestampCriterionInput `json:"updated_at"`
+	// Filter by the time the linked stash-box object was last updated
+	UpstreamUpdatedAt *TimestampCriterionInput `json:"upstream_updated_at"`
+	// Filter to only include scenes matching these saved filters
+	SavedFilters *MultiCriterionInput `json:"saved_filters"`
 }
 
 type SceneQueryOptions struct {
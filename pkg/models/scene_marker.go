@@ -21,6 +21,8 @@ type SceneMarkerFilterType struct {
 	SceneUpdatedAt *TimestampCriterionInput `json:"scene_updated_at"`
 	// Filter by related scenes that meet this criteria
 	SceneFilter *SceneFilterType `json:"scene_filter"`
+	// Filter to only include scene markers matching these saved filters
+	SavedFilters *MultiCriterionInput `json:"saved_filters"`
 }
 
 type MarkerStringsResultType struct {
@@ -4,6 +4,8 @@ type StashBoxFingerprint struct {
 	Algorithm string `json:"algorithm"`
 	Hash      string `json:"hash"`
 	Duration  int    `json:"duration"`
+	// number of times the fingerprint was submitted for the scene
+	Submissions int `json:"submissions"`
 }
 
 type StashBox struct {
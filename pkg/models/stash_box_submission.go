@@ -0,0 +1,33 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+type StashBoxSubmissionReader interface {
+	Find(ctx context.Context, id int) (*StashBoxSubmission, error)
+	FindMany(ctx context.Context, ids []int) ([]*StashBoxSubmission, error)
+	// FindBySceneID returns the submissions for the scene, newest first.
+	FindBySceneID(ctx context.Context, sceneID int) ([]*StashBoxSubmission, error)
+	// FindDue returns the pending submissions to attempt at or before t,
+	// oldest first.
+	FindDue(ctx context.Context, t time.Time) ([]*StashBoxSubmission, error)
+	// Query returns the submissions matching the filter, newest first.
+	Query(ctx context.Context, submissionFilter *StashBoxSubmissionFilterType, findFilter *FindFilterType) ([]*StashBoxSubmission, int, error)
+	IsFingerprintSubmitted(ctx context.Context, fingerprint SubmittedFingerprint) (bool, error)
+}
+
+type StashBoxSubmissionWriter interface {
+	// Create queues a new submission, replacing any pending submission of the
+	// same type for the same object and endpoint.
+	Create(ctx context.Context, newSubmission *StashBoxSubmission) error
+	Update(ctx context.Context, updatedSubmission *StashBoxSubmission) error
+	Destroy(ctx context.Context, id int) error
+	AddSubmittedFingerprint(ctx context.Context, fingerprint SubmittedFingerprint) error
+}
+
+type StashBoxSubmissionReaderWriter interface {
+	StashBoxSubmissionReader
+	StashBoxSubmissionWriter
+}
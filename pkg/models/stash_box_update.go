@@ -0,0 +1,26 @@
+package models
+
+import "context"
+
+type StashBoxUpdateReader interface {
+	Find(ctx context.Context, id int) (*StashBoxUpdate, error)
+	FindMany(ctx context.Context, ids []int) ([]*StashBoxUpdate, error)
+	// Query returns the updates matching the filter, newest first.
+	Query(ctx context.Context, updateFilter *StashBoxUpdateFilterType, findFilter *FindFilterType) ([]*StashBoxUpdate, int, error)
+	// GetUpstreamState returns the state of the stash-box object linked to
+	// the object as of the last check, or nil if it has not been checked.
+	GetUpstreamState(ctx context.Context, objectType ChangeObjectType, objectID int, endpoint string) (*StashBoxUpstreamState, error)
+}
+
+type StashBoxUpdateWriter interface {
+	// Create stores a new pending update, replacing any pending update for
+	// the same object and endpoint.
+	Create(ctx context.Context, newUpdate *StashBoxUpdate) error
+	UpdateStatus(ctx context.Context, id int, status StashBoxUpdateStatus) error
+	SetUpstreamState(ctx context.Context, state StashBoxUpstreamState) error
+}
+
+type StashBoxUpdateReaderWriter interface {
+	StashBoxUpdateReader
+	StashBoxUpdateWriter
+}
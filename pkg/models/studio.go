@@ -44,6 +44,10 @@ type StudioFilterType struct {
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
 	UpdatedAt *TimestampCriterionInput `json:"updated_at"`
+	// Filter by the time the linked stash-box object was last updated
+	UpstreamUpdatedAt *TimestampCriterionInput `json:"upstream_updated_at"`
+	// Filter to only include studios matching these saved filters
+	SavedFilters *MultiCriterionInput `json:"saved_filters"`
 }
 
 type StudioCreateInput struct {
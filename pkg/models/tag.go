@@ -46,4 +46,6 @@ type TagFilterType struct {
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
 	UpdatedAt *TimestampCriterionInput `json:"updated_at"`
+	// Filter to only include tags matching these saved filters
+	SavedFilters *MultiCriterionInput `json:"saved_filters"`
 }
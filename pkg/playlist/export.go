@@ -0,0 +1,98 @@
+package playlist
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/json"
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+)
+
+type ItemGetter interface {
+	GetItems(ctx context.Context, playlistID int) ([]models.PlaylistItem, error)
+}
+
+// ExportReaders are the readers used to export the items of playlists.
+type ExportReaders struct {
+	Items        ItemGetter
+	Scenes       models.SceneGetter
+	SceneMarkers models.SceneMarkerGetter
+	Images       models.ImageGetter
+}
+
+func formatSeconds(s float64) string {
+	return strconv.FormatFloat(s, 'f', -1, 64)
+}
+
+// ToJSON converts a Playlist into its JSON equivalent. Scenes and images are
+// referred to by the path of their primary file. Items of scenes and images
+// without files cannot be referred to and are omitted.
+func ToJSON(ctx context.Context, r ExportReaders, playlist *models.Playlist) (*jsonschema.Playlist, error) {
+	ret := jsonschema.Playlist{
+		Name:        playlist.Name,
+		Description: playlist.Description,
+		CreatedAt:   json.JSONTime{Time: playlist.CreatedAt},
+		UpdatedAt:   json.JSONTime{Time: playlist.UpdatedAt},
+	}
+
+	items, err := r.Items.GetItems(ctx, playlist.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting playlist items: %w", err)
+	}
+
+	for _, item := range items {
+		var itemJSON jsonschema.PlaylistItem
+
+		switch {
+		case item.SceneID != nil:
+			scene, err := r.Scenes.Find(ctx, *item.SceneID)
+			if err != nil {
+				return nil, fmt.Errorf("getting scene %d: %w", *item.SceneID, err)
+			}
+			if scene == nil || scene.Path == "" {
+				continue
+			}
+			itemJSON.Scene = scene.Path
+		case item.SceneMarkerID != nil:
+			marker, err := r.SceneMarkers.Find(ctx, *item.SceneMarkerID)
+			if err != nil {
+				return nil, fmt.Errorf("getting scene marker %d: %w", *item.SceneMarkerID, err)
+			}
+			if marker == nil {
+				continue
+			}
+
+			scene, err := r.Scenes.Find(ctx, marker.SceneID)
+			if err != nil {
+				return nil, fmt.Errorf("getting scene %d: %w", marker.SceneID, err)
+			}
+			if scene == nil || scene.Path == "" {
+				continue
+			}
+
+			itemJSON.SceneMarker = &jsonschema.PlaylistSceneMarker{
+				Scene:   scene.Path,
+				Title:   marker.Title,
+				Seconds: formatSeconds(marker.Seconds),
+			}
+			if item.EndSeconds != nil {
+				itemJSON.EndSeconds = formatSeconds(*item.EndSeconds)
+			}
+		case item.ImageID != nil:
+			image, err := r.Images.Find(ctx, *item.ImageID)
+			if err != nil {
+				return nil, fmt.Errorf("getting image %d: %w", *item.ImageID, err)
+			}
+			if image == nil || image.Path == "" {
+				continue
+			}
+			itemJSON.Image = image.Path
+		}
+
+		ret.Items = append(ret.Items, itemJSON)
+	}
+
+	return &ret, nil
+}
@@ -0,0 +1,222 @@
+package playlist
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+)
+
+type ImporterReaderWriter interface {
+	Create(ctx context.Context, newPlaylist *models.Playlist) error
+	Update(ctx context.Context, updatedPlaylist *models.Playlist) error
+	UpdateItems(ctx context.Context, playlistID int, items []models.PlaylistItem) error
+	FindByName(ctx context.Context, name string, nocase bool) (*models.Playlist, error)
+}
+
+type ImporterSceneFinder interface {
+	FindByPath(ctx context.Context, p string) ([]*models.Scene, error)
+}
+
+type ImporterSceneMarkerFinder interface {
+	FindBySceneID(ctx context.Context, sceneID int) ([]*models.SceneMarker, error)
+}
+
+type ImporterImageFinder interface {
+	FindByFileID(ctx context.Context, fileID models.FileID) ([]*models.Image, error)
+}
+
+type Importer struct {
+	ReaderWriter        ImporterReaderWriter
+	FileFinder          models.FileFinder
+	SceneFinder         ImporterSceneFinder
+	SceneMarkerFinder   ImporterSceneMarkerFinder
+	ImageFinder         ImporterImageFinder
+	Input               jsonschema.Playlist
+	MissingRefBehaviour models.ImportMissingRefEnum
+
+	playlist models.Playlist
+	items    []models.PlaylistItem
+}
+
+func (i *Importer) PreImport(ctx context.Context) error {
+	i.playlist = models.Playlist{
+		Name:        i.Input.Name,
+		Description: i.Input.Description,
+		CreatedAt:   i.Input.CreatedAt.GetTime(),
+		UpdatedAt:   i.Input.UpdatedAt.GetTime(),
+	}
+
+	for n, itemJSON := range i.Input.Items {
+		item, err := i.itemJSONToItem(ctx, itemJSON)
+		if err != nil {
+			if i.MissingRefBehaviour == models.ImportMissingRefEnumFail {
+				return fmt.Errorf("item %d: %w", n, err)
+			}
+
+			// missing items cannot be created, so skip them
+			logger.Warnf("[playlists] <%s> skipping item %d: %v", i.Name(), n, err)
+			continue
+		}
+
+		i.items = append(i.items, *item)
+	}
+
+	return nil
+}
+
+func (i *Importer) findScene(ctx context.Context, path string) (*models.Scene, error) {
+	scenes, err := i.SceneFinder.FindByPath(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("error finding scene: %w", err)
+	}
+
+	if len(scenes) == 0 {
+		return nil, fmt.Errorf("scene '%s' not found", path)
+	}
+
+	return scenes[0], nil
+}
+
+func (i *Importer) findSceneMarker(ctx context.Context, ref jsonschema.PlaylistSceneMarker) (*models.SceneMarker, error) {
+	scene, err := i.findScene(ctx, ref.Scene)
+	if err != nil {
+		return nil, err
+	}
+
+	seconds, _ := strconv.ParseFloat(ref.Seconds, 64)
+
+	markers, err := i.SceneMarkerFinder.FindBySceneID(ctx, scene.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding scene markers: %w", err)
+	}
+
+	var ret *models.SceneMarker
+	for _, m := range markers {
+		if m.Seconds != seconds {
+			continue
+		}
+
+		// prefer the marker with the same title if there are multiple
+		// markers at the same position
+		if ret == nil || (m.Title == ref.Title && ret.Title != ref.Title) {
+			ret = m
+		}
+	}
+
+	if ret == nil {
+		return nil, fmt.Errorf("scene marker at %s seconds of scene '%s' not found", ref.Seconds, ref.Scene)
+	}
+
+	return ret, nil
+}
+
+func (i *Importer) findImage(ctx context.Context, path string) (*models.Image, error) {
+	f, err := i.FileFinder.FindByPath(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("error finding file: %w", err)
+	}
+
+	if f != nil {
+		images, err := i.ImageFinder.FindByFileID(ctx, f.Base().ID)
+		if err != nil {
+			return nil, fmt.Errorf("error finding image: %w", err)
+		}
+
+		if len(images) > 0 {
+			return images[0], nil
+		}
+	}
+
+	return nil, fmt.Errorf("image '%s' not found", path)
+}
+
+func (i *Importer) itemJSONToItem(ctx context.Context, itemJSON jsonschema.PlaylistItem) (*models.PlaylistItem, error) {
+	var ret models.PlaylistItem
+
+	switch {
+	case itemJSON.Scene != "":
+		scene, err := i.findScene(ctx, itemJSON.Scene)
+		if err != nil {
+			return nil, err
+		}
+		ret.SceneID = &scene.ID
+	case itemJSON.SceneMarker != nil:
+		marker, err := i.findSceneMarker(ctx, *itemJSON.SceneMarker)
+		if err != nil {
+			return nil, err
+		}
+		ret.SceneMarkerID = &marker.ID
+
+		if itemJSON.EndSeconds != "" {
+			endSeconds, err := strconv.ParseFloat(itemJSON.EndSeconds, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid end_seconds: %w", err)
+			}
+			ret.EndSeconds = &endSeconds
+		}
+	case itemJSON.Image != "":
+		image, err := i.findImage(ctx, itemJSON.Image)
+		if err != nil {
+			return nil, err
+		}
+		ret.ImageID = &image.ID
+	}
+
+	if err := ret.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (i *Importer) PostImport(ctx context.Context, id int) error {
+	if err := i.ReaderWriter.UpdateItems(ctx, id, i.items); err != nil {
+		return fmt.Errorf("error setting playlist items: %v", err)
+	}
+
+	return nil
+}
+
+func (i *Importer) Name() string {
+	return i.Input.Name
+}
+
+func (i *Importer) FindExistingID(ctx context.Context) (*int, error) {
+	const nocase = false
+	existing, err := i.ReaderWriter.FindByName(ctx, i.Name(), nocase)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		id := existing.ID
+		return &id, nil
+	}
+
+	return nil, nil
+}
+
+func (i *Importer) Create(ctx context.Context) (*int, error) {
+	err := i.ReaderWriter.Create(ctx, &i.playlist)
+	if err != nil {
+		return nil, fmt.Errorf("error creating playlist: %v", err)
+	}
+
+	id := i.playlist.ID
+	return &id, nil
+}
+
+func (i *Importer) Update(ctx context.Context, id int) error {
+	playlist := i.playlist
+	playlist.ID = id
+	err := i.ReaderWriter.Update(ctx, &playlist)
+	if err != nil {
+		return fmt.Errorf("error updating existing playlist: %v", err)
+	}
+
+	return nil
+}
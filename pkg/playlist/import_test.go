@@ -0,0 +1,182 @@
+package playlist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+	"github.com/stashapp/stash/pkg/models/mocks"
+)
+
+const (
+	playlistName = "playlistName"
+
+	scenePath        = "scenePath"
+	missingScenePath = "missingScenePath"
+	imagePath        = "imagePath"
+	missingImagePath = "missingImagePath"
+
+	sceneID       = 1
+	markerID      = 2
+	otherMarkerID = 3
+	imageID       = 4
+	imageFileID   = 5
+
+	markerTitle = "markerTitle"
+)
+
+var testCtx = context.Background()
+
+func newImporterDatabase() *mocks.Database {
+	db := mocks.NewDatabase()
+
+	db.Scene.On("FindByPath", testCtx, scenePath).Return([]*models.Scene{{ID: sceneID}}, nil)
+	db.Scene.On("FindByPath", testCtx, missingScenePath).Return(nil, nil)
+	db.SceneMarker.On("FindBySceneID", testCtx, sceneID).Return([]*models.SceneMarker{
+		{ID: otherMarkerID, Title: "other", Seconds: 12.5, SceneID: sceneID},
+		{ID: markerID, Title: markerTitle, Seconds: 12.5, SceneID: sceneID},
+	}, nil)
+	db.File.On("FindByPath", testCtx, imagePath).Return(&models.ImageFile{
+		BaseFile: &models.BaseFile{ID: imageFileID},
+	}, nil)
+	db.File.On("FindByPath", testCtx, missingImagePath).Return(nil, nil)
+	db.Image.On("FindByFileID", testCtx, models.FileID(imageFileID)).Return([]*models.Image{{ID: imageID}}, nil)
+
+	return db
+}
+
+func newImporter(db *mocks.Database, items []jsonschema.PlaylistItem, missingRefBehaviour models.ImportMissingRefEnum) *Importer {
+	return &Importer{
+		FileFinder:        db.File,
+		SceneFinder:       db.Scene,
+		SceneMarkerFinder: db.SceneMarker,
+		ImageFinder:       db.Image,
+		Input: jsonschema.Playlist{
+			Name:  playlistName,
+			Items: items,
+		},
+		MissingRefBehaviour: missingRefBehaviour,
+	}
+}
+
+func TestImporterName(t *testing.T) {
+	i := Importer{
+		Input: jsonschema.Playlist{
+			Name: playlistName,
+		},
+	}
+
+	assert.Equal(t, playlistName, i.Name())
+}
+
+func TestImporterPreImport(t *testing.T) {
+	db := newImporterDatabase()
+
+	i := newImporter(db, []jsonschema.PlaylistItem{
+		{Scene: scenePath},
+		{
+			SceneMarker: &jsonschema.PlaylistSceneMarker{
+				Scene:   scenePath,
+				Title:   markerTitle,
+				Seconds: "12.5",
+			},
+			EndSeconds: "30",
+		},
+		{Image: imagePath},
+	}, models.ImportMissingRefEnumFail)
+
+	err := i.PreImport(testCtx)
+	assert.NoError(t, err)
+
+	sceneIDPtr := sceneID
+	markerIDPtr := markerID
+	imageIDPtr := imageID
+	endSeconds := 30.0
+
+	assert.Equal(t, []models.PlaylistItem{
+		{SceneID: &sceneIDPtr},
+		{SceneMarkerID: &markerIDPtr, EndSeconds: &endSeconds},
+		{ImageID: &imageIDPtr},
+	}, i.items)
+}
+
+func TestImporterPreImportMissing(t *testing.T) {
+	items := []jsonschema.PlaylistItem{
+		{Scene: missingScenePath},
+		{
+			SceneMarker: &jsonschema.PlaylistSceneMarker{
+				Scene:   scenePath,
+				Seconds: "99",
+			},
+		},
+		{Image: missingImagePath},
+		{Scene: scenePath},
+	}
+
+	for _, item := range items[:3] {
+		db := newImporterDatabase()
+		i := newImporter(db, []jsonschema.PlaylistItem{item}, models.ImportMissingRefEnumFail)
+		assert.Error(t, i.PreImport(testCtx))
+	}
+
+	db := newImporterDatabase()
+	i := newImporter(db, items, models.ImportMissingRefEnumIgnore)
+	err := i.PreImport(testCtx)
+	assert.NoError(t, err)
+
+	sceneIDPtr := sceneID
+	assert.Equal(t, []models.PlaylistItem{
+		{SceneID: &sceneIDPtr},
+	}, i.items)
+}
+
+type mockPlaylistReaderWriter struct {
+	mock.Mock
+}
+
+func (m *mockPlaylistReaderWriter) Create(ctx context.Context, newPlaylist *models.Playlist) error {
+	return m.Called(ctx, newPlaylist).Error(0)
+}
+
+func (m *mockPlaylistReaderWriter) Update(ctx context.Context, updatedPlaylist *models.Playlist) error {
+	return m.Called(ctx, updatedPlaylist).Error(0)
+}
+
+func (m *mockPlaylistReaderWriter) UpdateItems(ctx context.Context, playlistID int, items []models.PlaylistItem) error {
+	return m.Called(ctx, playlistID, items).Error(0)
+}
+
+func (m *mockPlaylistReaderWriter) FindByName(ctx context.Context, name string, nocase bool) (*models.Playlist, error) {
+	args := m.Called(ctx, name, nocase)
+	ret, _ := args.Get(0).(*models.Playlist)
+	return ret, args.Error(1)
+}
+
+func TestImporterFindExistingID(t *testing.T) {
+	const existingID = 10
+
+	rw := &mockPlaylistReaderWriter{}
+	rw.On("FindByName", testCtx, playlistName, false).Return(&models.Playlist{ID: existingID}, nil).Once()
+	rw.On("FindByName", testCtx, playlistName, false).Return(nil, nil).Once()
+
+	i := Importer{
+		ReaderWriter: rw,
+		Input: jsonschema.Playlist{
+			Name: playlistName,
+		},
+	}
+
+	id, err := i.FindExistingID(testCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, existingID, *id)
+
+	id, err = i.FindExistingID(testCtx)
+	assert.NoError(t, err)
+	assert.Nil(t, id)
+
+	rw.AssertExpectations(t)
+}
@@ -0,0 +1,73 @@
+package playlist
+
+import (
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// InsertItems returns the items with newItems inserted at index. The items are
+// appended if index is nil or out of range.
+func InsertItems(items []models.PlaylistItem, newItems []models.PlaylistItem, index *int) []models.PlaylistItem {
+	i := len(items)
+	if index != nil && *index >= 0 && *index < len(items) {
+		i = *index
+	}
+
+	ret := make([]models.PlaylistItem, 0, len(items)+len(newItems))
+	ret = append(ret, items[:i]...)
+	ret = append(ret, newItems...)
+	ret = append(ret, items[i:]...)
+	return ret
+}
+
+// RemoveItems returns the items without the items with the provided ids. It
+// returns an error if an id is not one of the items.
+func RemoveItems(items []models.PlaylistItem, ids []int) ([]models.PlaylistItem, error) {
+	remove := make(map[int]bool)
+	for _, id := range ids {
+		remove[id] = true
+	}
+
+	var ret []models.PlaylistItem
+	for _, i := range items {
+		if remove[i.ID] {
+			delete(remove, i.ID)
+			continue
+		}
+		ret = append(ret, i)
+	}
+
+	for _, id := range ids {
+		if remove[id] {
+			return nil, fmt.Errorf("playlist item %d not found", id)
+		}
+	}
+
+	return ret, nil
+}
+
+// ReorderItems returns the items in the order of the provided ids. The ids
+// must contain the id of each item exactly once.
+func ReorderItems(items []models.PlaylistItem, ids []int) ([]models.PlaylistItem, error) {
+	if len(ids) != len(items) {
+		return nil, fmt.Errorf("expected %d item ids, got %d", len(items), len(ids))
+	}
+
+	byID := make(map[int]models.PlaylistItem)
+	for _, i := range items {
+		byID[i.ID] = i
+	}
+
+	ret := make([]models.PlaylistItem, len(ids))
+	for n, id := range ids {
+		i, found := byID[id]
+		if !found {
+			return nil, fmt.Errorf("playlist item %d not found or duplicated", id)
+		}
+		ret[n] = i
+		delete(byID, id)
+	}
+
+	return ret, nil
+}
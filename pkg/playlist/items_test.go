@@ -0,0 +1,66 @@
+package playlist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func makeItems(ids ...int) []models.PlaylistItem {
+	var ret []models.PlaylistItem
+	for _, id := range ids {
+		sceneID := id * 10
+		ret = append(ret, models.PlaylistItem{ID: id, SceneID: &sceneID})
+	}
+	return ret
+}
+
+func TestInsertItems(t *testing.T) {
+	zero := 0
+	one := 1
+	outOfRange := 5
+
+	tests := []struct {
+		name  string
+		index *int
+		want  []models.PlaylistItem
+	}{
+		{"append", nil, makeItems(1, 2, 3, 4)},
+		{"start", &zero, makeItems(3, 4, 1, 2)},
+		{"middle", &one, makeItems(1, 3, 4, 2)},
+		{"out of range", &outOfRange, makeItems(1, 2, 3, 4)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := InsertItems(makeItems(1, 2), makeItems(3, 4), tt.index)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRemoveItems(t *testing.T) {
+	got, err := RemoveItems(makeItems(1, 2, 3), []int{1, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, makeItems(2), got)
+
+	_, err = RemoveItems(makeItems(1, 2, 3), []int{4})
+	assert.Error(t, err)
+}
+
+func TestReorderItems(t *testing.T) {
+	got, err := ReorderItems(makeItems(1, 2, 3), []int{3, 1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, makeItems(3, 1, 2), got)
+
+	_, err = ReorderItems(makeItems(1, 2, 3), []int{3, 1})
+	assert.Error(t, err, "missing item")
+
+	_, err = ReorderItems(makeItems(1, 2, 3), []int{3, 1, 1})
+	assert.Error(t, err, "duplicated item")
+
+	_, err = ReorderItems(makeItems(1, 2, 3), []int{3, 1, 4})
+	assert.Error(t, err, "unknown item")
+}
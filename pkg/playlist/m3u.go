@@ -0,0 +1,53 @@
+package playlist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// M3UEntry is an entry of an extended M3U playlist.
+type M3UEntry struct {
+	Title string
+	// Duration is the duration in seconds, or nil if unknown
+	Duration *float64
+	URL      string
+	// Start and Stop are the start and end of a clip of the entry, in seconds.
+	// Players that do not support the VLC options play the whole entry.
+	Start *float64
+	Stop  *float64
+}
+
+// m3uText returns s without line breaks, which would end the directive.
+func m3uText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// WriteM3U writes the entries to w as an extended M3U playlist named name.
+func WriteM3U(w io.Writer, name string, entries []M3UEntry) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "#EXTM3U")
+	if name != "" {
+		fmt.Fprintf(bw, "#PLAYLIST:%s\n", m3uText(name))
+	}
+
+	for _, e := range entries {
+		duration := -1
+		if e.Duration != nil {
+			duration = int(*e.Duration)
+		}
+
+		fmt.Fprintf(bw, "#EXTINF:%d,%s\n", duration, m3uText(e.Title))
+		if e.Start != nil {
+			fmt.Fprintf(bw, "#EXTVLCOPT:start-time=%s\n", formatSeconds(*e.Start))
+		}
+		if e.Stop != nil {
+			fmt.Fprintf(bw, "#EXTVLCOPT:stop-time=%s\n", formatSeconds(*e.Stop))
+		}
+		fmt.Fprintln(bw, e.URL)
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,48 @@
+package playlist
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteM3U(t *testing.T) {
+	duration := 125.7
+	start := 12.5
+	stop := 30.0
+
+	entries := []M3UEntry{
+		{
+			Title:    "Scene",
+			Duration: &duration,
+			URL:      "http://localhost/scene/1/stream",
+		},
+		{
+			Title: "Scene - Marker\nwith break",
+			URL:   "http://localhost/scene/2/stream",
+			Start: &start,
+			Stop:  &stop,
+		},
+		{
+			Title: "Image",
+			URL:   "http://localhost/image/3/image",
+		},
+	}
+
+	var sb strings.Builder
+	err := WriteM3U(&sb, "My playlist", entries)
+	assert.NoError(t, err)
+
+	assert.Equal(t, `#EXTM3U
+#PLAYLIST:My playlist
+#EXTINF:125,Scene
+http://localhost/scene/1/stream
+#EXTINF:-1,Scene - Marker with break
+#EXTVLCOPT:start-time=12.5
+#EXTVLCOPT:stop-time=30
+http://localhost/scene/2/stream
+#EXTINF:-1,Image
+http://localhost/image/3/image
+`, sb.String())
+}
@@ -3,7 +3,9 @@ package common
 import "net/http"
 
 const (
-	HookContextKey = "hookContext"
+	HookContextKey    = "hookContext"
+	ScrapeContextKey  = "scrapeContext"
+	ServiceContextKey = "serviceContext"
 )
 
 // StashServerConnection represents the connection details needed for a
@@ -110,6 +112,13 @@ func (o *PluginOutput) SetError(err error) {
 	o.Error = &errStr
 }
 
+// ScrapeContext is passed as a PluginArgValue and indicates the scrape
+// operation that this plugin task should perform.
+type ScrapeContext struct {
+	Type  string      `json:"type"`
+	Input interface{} `json:"input"`
+}
+
 // HookContext is passed as a PluginArgValue and indicates what hook triggered
 // this plugin task.
 type HookContext struct {
@@ -118,3 +127,12 @@ type HookContext struct {
 	Input       interface{} `json:"input"`
 	InputFields []string    `json:"inputFields,omitempty"`
 }
+
+// ServiceContext is passed as a PluginArgValue to plugin services and
+// contains the details needed by the service to serve HTTP requests.
+type ServiceContext struct {
+	// The port on the loopback interface that the service should listen
+	// on to receive HTTP requests. Requests made to /plugin/{id}/api are
+	// proxied to this port. Not set for javascript services.
+	Port int `json:"port,omitempty"`
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/stashapp/stash/pkg/plugin/hook"
 	"github.com/stashapp/stash/pkg/python"
@@ -61,6 +62,15 @@ type Config struct {
 	// The hooks configurations for hooks registered by this plugin.
 	Hooks []*HookConfig `yaml:"hooks"`
 
+	// The scrape operations provided by this plugin.
+	Scraper *ScraperConfig `yaml:"scraper"`
+
+	// The long-running service provided by this plugin.
+	Service *ServiceConfig `yaml:"service"`
+
+	// The additional APIs that javascript plugin tasks may use.
+	Permissions PermissionsConfig `yaml:"permissions"`
+
 	// Javascript files that will be injected into the stash UI.
 	UI UIConfig `yaml:"ui"`
 
@@ -68,6 +78,24 @@ type Config struct {
 	Settings map[string]SettingConfig `yaml:"settings"`
 }
 
+// PermissionsConfig describes the additional APIs that are made available
+// to javascript plugin tasks. No additional APIs are available by default.
+type PermissionsConfig struct {
+	// Sources that the http API may make requests to. Uses a subset of the
+	// Content Security Policy source syntax, for example
+	// https://*.example.com or https://example.com/api/.
+	// The http API is not available if empty.
+	HTTP []string `yaml:"http"`
+
+	// If true, the fs API provides read-only access to files within the
+	// library paths.
+	Filesystem bool `yaml:"filesystem"`
+
+	// If true, the storage API provides a key-value store persisted in the
+	// database. Keys are scoped to the plugin.
+	Storage bool `yaml:"storage"`
+}
+
 type PluginCSP struct {
 	ScriptSrc  []string `json:"script-src" yaml:"script-src"`
 	StyleSrc   []string `json:"style-src" yaml:"style-src"`
@@ -330,6 +358,18 @@ func (c Config) valid() error {
 		return fmt.Errorf("invalid interface type %s", c.Interface)
 	}
 
+	if c.Scraper != nil {
+		if err := c.Scraper.validate(); err != nil {
+			return fmt.Errorf("invalid scraper configuration: %w", err)
+		}
+	}
+
+	if c.Service != nil {
+		if err := c.Service.validate(c.Interface); err != nil {
+			return fmt.Errorf("invalid service configuration: %w", err)
+		}
+	}
+
 	for k, o := range c.Settings {
 		if o.Type != "" && !o.Type.IsValid() {
 			return fmt.Errorf("invalid type %s for setting %s", k, o.Type)
@@ -404,6 +444,20 @@ type HookConfig struct {
 
 	// A list of stash operations that will be used to trigger this hook operation.
 	TriggeredBy []hook.TriggerEnum `yaml:"triggeredBy"`
+
+	// The maximum number of seconds that the hook operation may run for when
+	// triggered by a pre-hook. If the operation does not complete in time, it
+	// is stopped and the triggering stash operation fails.
+	// Defaults to 30 seconds if not set. Not used for post-hooks.
+	Timeout int `yaml:"timeout"`
+}
+
+func (c HookConfig) getTimeout() time.Duration {
+	if c.Timeout <= 0 {
+		return defaultPreHookTimeout
+	}
+
+	return time.Duration(c.Timeout) * time.Second
 }
 
 func loadPluginFromYAML(reader io.Reader) (*Config, error) {
@@ -6,38 +6,74 @@ type TriggerEnum string
 // integrated.
 
 const (
+	SceneMarkerCreatePre  TriggerEnum = "SceneMarker.Create.Pre"
+	SceneMarkerUpdatePre  TriggerEnum = "SceneMarker.Update.Pre"
+	SceneMarkerDestroyPre TriggerEnum = "SceneMarker.Destroy.Pre"
+
 	SceneMarkerCreatePost  TriggerEnum = "SceneMarker.Create.Post"
 	SceneMarkerUpdatePost  TriggerEnum = "SceneMarker.Update.Post"
 	SceneMarkerDestroyPost TriggerEnum = "SceneMarker.Destroy.Post"
 
+	SceneCreatePre  TriggerEnum = "Scene.Create.Pre"
+	SceneUpdatePre  TriggerEnum = "Scene.Update.Pre"
+	SceneDestroyPre TriggerEnum = "Scene.Destroy.Pre"
+
 	SceneCreatePost  TriggerEnum = "Scene.Create.Post"
 	SceneUpdatePost  TriggerEnum = "Scene.Update.Post"
 	SceneDestroyPost TriggerEnum = "Scene.Destroy.Post"
 
+	ImageCreatePre  TriggerEnum = "Image.Create.Pre"
+	ImageUpdatePre  TriggerEnum = "Image.Update.Pre"
+	ImageDestroyPre TriggerEnum = "Image.Destroy.Pre"
+
 	ImageCreatePost  TriggerEnum = "Image.Create.Post"
 	ImageUpdatePost  TriggerEnum = "Image.Update.Post"
 	ImageDestroyPost TriggerEnum = "Image.Destroy.Post"
 
+	GalleryCreatePre  TriggerEnum = "Gallery.Create.Pre"
+	GalleryUpdatePre  TriggerEnum = "Gallery.Update.Pre"
+	GalleryDestroyPre TriggerEnum = "Gallery.Destroy.Pre"
+
 	GalleryCreatePost  TriggerEnum = "Gallery.Create.Post"
 	GalleryUpdatePost  TriggerEnum = "Gallery.Update.Post"
 	GalleryDestroyPost TriggerEnum = "Gallery.Destroy.Post"
 
+	GalleryChapterCreatePre  TriggerEnum = "GalleryChapter.Create.Pre"
+	GalleryChapterUpdatePre  TriggerEnum = "GalleryChapter.Update.Pre"
+	GalleryChapterDestroyPre TriggerEnum = "GalleryChapter.Destroy.Pre"
+
 	GalleryChapterCreatePost  TriggerEnum = "GalleryChapter.Create.Post"
 	GalleryChapterUpdatePost  TriggerEnum = "GalleryChapter.Update.Post"
 	GalleryChapterDestroyPost TriggerEnum = "GalleryChapter.Destroy.Post"
 
+	MovieCreatePre  TriggerEnum = "Movie.Create.Pre"
+	MovieUpdatePre  TriggerEnum = "Movie.Update.Pre"
+	MovieDestroyPre TriggerEnum = "Movie.Destroy.Pre"
+
 	MovieCreatePost  TriggerEnum = "Movie.Create.Post"
 	MovieUpdatePost  TriggerEnum = "Movie.Update.Post"
 	MovieDestroyPost TriggerEnum = "Movie.Destroy.Post"
 
+	PerformerCreatePre  TriggerEnum = "Performer.Create.Pre"
+	PerformerUpdatePre  TriggerEnum = "Performer.Update.Pre"
+	PerformerDestroyPre TriggerEnum = "Performer.Destroy.Pre"
+
 	PerformerCreatePost  TriggerEnum = "Performer.Create.Post"
 	PerformerUpdatePost  TriggerEnum = "Performer.Update.Post"
 	PerformerDestroyPost TriggerEnum = "Performer.Destroy.Post"
 
+	StudioCreatePre  TriggerEnum = "Studio.Create.Pre"
+	StudioUpdatePre  TriggerEnum = "Studio.Update.Pre"
+	StudioDestroyPre TriggerEnum = "Studio.Destroy.Pre"
+
 	StudioCreatePost  TriggerEnum = "Studio.Create.Post"
 	StudioUpdatePost  TriggerEnum = "Studio.Update.Post"
 	StudioDestroyPost TriggerEnum = "Studio.Destroy.Post"
 
+	TagCreatePre  TriggerEnum = "Tag.Create.Pre"
+	TagUpdatePre  TriggerEnum = "Tag.Update.Pre"
+	TagDestroyPre TriggerEnum = "Tag.Destroy.Pre"
+
 	TagCreatePost  TriggerEnum = "Tag.Create.Post"
 	TagUpdatePost  TriggerEnum = "Tag.Update.Post"
 	TagMergePost   TriggerEnum = "Tag.Merge.Post"
@@ -45,38 +81,74 @@ const (
 )
 
 var AllHookTriggerEnum = []TriggerEnum{
+	SceneMarkerCreatePre,
+	SceneMarkerUpdatePre,
+	SceneMarkerDestroyPre,
+
 	SceneMarkerCreatePost,
 	SceneMarkerUpdatePost,
 	SceneMarkerDestroyPost,
 
+	SceneCreatePre,
+	SceneUpdatePre,
+	SceneDestroyPre,
+
 	SceneCreatePost,
 	SceneUpdatePost,
 	SceneDestroyPost,
 
+	ImageCreatePre,
+	ImageUpdatePre,
+	ImageDestroyPre,
+
 	ImageCreatePost,
 	ImageUpdatePost,
 	ImageDestroyPost,
 
+	GalleryCreatePre,
+	GalleryUpdatePre,
+	GalleryDestroyPre,
+
 	GalleryCreatePost,
 	GalleryUpdatePost,
 	GalleryDestroyPost,
 
+	GalleryChapterCreatePre,
+	GalleryChapterUpdatePre,
+	GalleryChapterDestroyPre,
+
 	GalleryChapterCreatePost,
 	GalleryChapterUpdatePost,
 	GalleryChapterDestroyPost,
 
+	MovieCreatePre,
+	MovieUpdatePre,
+	MovieDestroyPre,
+
 	MovieCreatePost,
 	MovieUpdatePost,
 	MovieDestroyPost,
 
+	PerformerCreatePre,
+	PerformerUpdatePre,
+	PerformerDestroyPre,
+
 	PerformerCreatePost,
 	PerformerUpdatePost,
 	PerformerDestroyPost,
 
+	StudioCreatePre,
+	StudioUpdatePre,
+	StudioDestroyPre,
+
 	StudioCreatePost,
 	StudioUpdatePost,
 	StudioDestroyPost,
 
+	TagCreatePre,
+	TagUpdatePre,
+	TagDestroyPre,
+
 	TagCreatePost,
 	TagUpdatePost,
 	TagMergePost,
@@ -85,6 +157,131 @@ var AllHookTriggerEnum = []TriggerEnum{
 
 func (e TriggerEnum) IsValid() bool {
 
+	switch e {
+	case SceneMarkerCreatePre,
+		SceneMarkerUpdatePre,
+		SceneMarkerDestroyPre,
+
+		SceneMarkerCreatePost,
+		SceneMarkerUpdatePost,
+		SceneMarkerDestroyPost,
+
+		SceneCreatePre,
+		SceneUpdatePre,
+		SceneDestroyPre,
+
+		SceneCreatePost,
+		SceneUpdatePost,
+		SceneDestroyPost,
+
+		ImageCreatePre,
+		ImageUpdatePre,
+		ImageDestroyPre,
+
+		ImageCreatePost,
+		ImageUpdatePost,
+		ImageDestroyPost,
+
+		GalleryCreatePre,
+		GalleryUpdatePre,
+		GalleryDestroyPre,
+
+		GalleryCreatePost,
+		GalleryUpdatePost,
+		GalleryDestroyPost,
+
+		GalleryChapterCreatePre,
+		GalleryChapterUpdatePre,
+		GalleryChapterDestroyPre,
+
+		GalleryChapterCreatePost,
+		GalleryChapterUpdatePost,
+		GalleryChapterDestroyPost,
+
+		MovieCreatePre,
+		MovieUpdatePre,
+		MovieDestroyPre,
+
+		MovieCreatePost,
+		MovieUpdatePost,
+		MovieDestroyPost,
+
+		PerformerCreatePre,
+		PerformerUpdatePre,
+		PerformerDestroyPre,
+
+		PerformerCreatePost,
+		PerformerUpdatePost,
+		PerformerDestroyPost,
+
+		StudioCreatePre,
+		StudioUpdatePre,
+		StudioDestroyPre,
+
+		StudioCreatePost,
+		StudioUpdatePost,
+		StudioDestroyPost,
+
+		TagCreatePre,
+		TagUpdatePre,
+		TagDestroyPre,
+
+		TagCreatePost,
+		TagUpdatePost,
+		TagMergePost,
+		TagDestroyPost:
+		return true
+	}
+	return false
+}
+
+// IsPre returns true if the hook is executed before the operation is
+// performed.
+func (e TriggerEnum) IsPre() bool {
+	switch e {
+	case SceneMarkerCreatePre,
+		SceneMarkerUpdatePre,
+		SceneMarkerDestroyPre,
+
+		SceneCreatePre,
+		SceneUpdatePre,
+		SceneDestroyPre,
+
+		ImageCreatePre,
+		ImageUpdatePre,
+		ImageDestroyPre,
+
+		GalleryCreatePre,
+		GalleryUpdatePre,
+		GalleryDestroyPre,
+
+		GalleryChapterCreatePre,
+		GalleryChapterUpdatePre,
+		GalleryChapterDestroyPre,
+
+		MovieCreatePre,
+		MovieUpdatePre,
+		MovieDestroyPre,
+
+		PerformerCreatePre,
+		PerformerUpdatePre,
+		PerformerDestroyPre,
+
+		StudioCreatePre,
+		StudioUpdatePre,
+		StudioDestroyPre,
+
+		TagCreatePre,
+		TagUpdatePre,
+		TagDestroyPre:
+		return true
+	}
+	return false
+}
+
+// IsPost returns true if the hook is executed after the operation has been
+// performed.
+func (e TriggerEnum) IsPost() bool {
 	switch e {
 	case SceneMarkerCreatePost,
 		SceneMarkerUpdatePost,
@@ -120,6 +317,7 @@ func (e TriggerEnum) IsValid() bool {
 
 		TagCreatePost,
 		TagUpdatePost,
+		TagMergePost,
 		TagDestroyPost:
 		return true
 	}
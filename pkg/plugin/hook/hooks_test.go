@@ -0,0 +1,25 @@
+package hook
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTriggerEnum_IsPreIsPost(t *testing.T) {
+	for _, e := range AllHookTriggerEnum {
+		wantPre := strings.HasSuffix(e.String(), ".Pre")
+		wantPost := strings.HasSuffix(e.String(), ".Post")
+
+		if got := e.IsPre(); got != wantPre {
+			t.Errorf("%s.IsPre() = %v, want %v", e, got, wantPre)
+		}
+		if got := e.IsPost(); got != wantPost {
+			t.Errorf("%s.IsPost() = %v, want %v", e, got, wantPost)
+		}
+	}
+
+	invalid := TriggerEnum("Scene.Invalid.Post")
+	if invalid.IsPre() || invalid.IsPost() {
+		t.Errorf("invalid trigger %s reported as Pre or Post", invalid)
+	}
+}
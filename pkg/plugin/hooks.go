@@ -1,6 +1,11 @@
 package plugin
 
 import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/dop251/goja"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/plugin/common"
 )
@@ -16,6 +21,44 @@ func addHookContext(argsMap common.ArgsMap, hookContext common.HookContext) {
 	argsMap[common.HookContextKey] = hookContext
 }
 
+// applyHookOutput applies the output of a pre-hook to input, which must be a
+// pointer. Output is only applied if it is an object. The object is applied
+// using its json representation, so only the fields present in the object
+// are changed. Returns the names of the fields that were set.
+func applyHookOutput(input interface{}, output interface{}) ([]string, error) {
+	// javascript plugins return their output as a javascript value
+	if v, ok := output.(goja.Value); ok {
+		output = v.Export()
+	}
+
+	m, ok := output.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return nil, nil
+	}
+
+	v := reflect.ValueOf(input)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, input); err != nil {
+		return nil, err
+	}
+
+	var ret []string
+	for k := range m {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+
+	return ret, nil
+}
+
 // types for destroy hooks, to provide a little more information
 type SceneDestroyInput struct {
 	models.SceneDestroyInput
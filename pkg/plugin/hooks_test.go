@@ -0,0 +1,256 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/plugin/hook"
+	"github.com/stashapp/stash/pkg/session"
+)
+
+type testHookInput struct {
+	Title   string   `json:"title,omitempty"`
+	Details string   `json:"details,omitempty"`
+	Rating  *int     `json:"rating100,omitempty"`
+	URLs    []string `json:"urls,omitempty"`
+}
+
+func TestApplyHookOutput(t *testing.T) {
+	rating := 50
+
+	tests := []struct {
+		name       string
+		input      testHookInput
+		output     interface{}
+		want       testHookInput
+		wantFields []string
+		wantErr    bool
+	}{
+		{
+			"nil output",
+			testHookInput{Title: "title"},
+			nil,
+			testHookInput{Title: "title"},
+			nil,
+			false,
+		},
+		{
+			"non-object output",
+			testHookInput{Title: "title"},
+			"ignored",
+			testHookInput{Title: "title"},
+			nil,
+			false,
+		},
+		{
+			"empty object",
+			testHookInput{Title: "title"},
+			map[string]interface{}{},
+			testHookInput{Title: "title"},
+			nil,
+			false,
+		},
+		{
+			"merged",
+			testHookInput{Title: "title", Details: "details"},
+			map[string]interface{}{
+				"title":     "new title",
+				"rating100": 50,
+				"urls":      []interface{}{"https://example.com"},
+			},
+			testHookInput{Title: "new title", Details: "details", Rating: &rating, URLs: []string{"https://example.com"}},
+			[]string{"rating100", "title", "urls"},
+			false,
+		},
+		{
+			"invalid type",
+			testHookInput{Title: "title"},
+			map[string]interface{}{
+				"title": 1,
+			},
+			testHookInput{Title: "title"},
+			nil,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.input
+			fields, err := applyHookOutput(&input, tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("applyHookOutput() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			assert.Equal(t, tt.want, input)
+			assert.Equal(t, tt.wantFields, fields)
+		})
+	}
+
+	t.Run("non-pointer input", func(t *testing.T) {
+		input := testHookInput{Title: "title"}
+		fields, err := applyHookOutput(input, map[string]interface{}{"title": "new title"})
+		assert.NoError(t, err)
+		assert.Nil(t, fields)
+		assert.Equal(t, "title", input.Title)
+	})
+}
+
+var testSessionStoreKey = []byte("0123456789abcdef0123456789abcdef")
+
+type testServerConfig struct {
+	pluginsPath     string
+	disabledPlugins []string
+}
+
+func (c testServerConfig) GetHost() string              { return "localhost" }
+func (c testServerConfig) GetPort() int                 { return 9999 }
+func (c testServerConfig) GetConfigPathAbs() string     { return c.pluginsPath }
+func (c testServerConfig) HasTLSConfig() bool           { return false }
+func (c testServerConfig) GetPluginsPath() string       { return c.pluginsPath }
+func (c testServerConfig) GetDisabledPlugins() []string { return c.disabledPlugins }
+func (c testServerConfig) GetPythonPath() string        { return "" }
+func (c testServerConfig) GetLibraryPaths() []string    { return nil }
+func (c testServerConfig) GetUsername() string          { return "" }
+func (c testServerConfig) GetAPIKey() string            { return "" }
+func (c testServerConfig) GetSessionStoreKey() []byte   { return testSessionStoreKey }
+func (c testServerConfig) GetMaxSessionAge() int        { return 0 }
+
+func (c testServerConfig) ValidateCredentials(username string, password string) bool {
+	return false
+}
+
+const testPreHookYAML = `name: %s
+exec:
+  - %s.js
+interface: js
+hooks:
+  - name: pre hook
+    triggeredBy:
+      - Scene.Update.Pre
+    timeout: 1
+`
+
+// newTestHookCache returns a Cache with a javascript plugin for each of the
+// scripts provided. Plugins are loaded in the order of their IDs.
+func newTestHookCache(t *testing.T, scripts map[string]string) *Cache {
+	dir := t.TempDir()
+
+	for id, script := range scripts {
+		yml := []byte(fmt.Sprintf(testPreHookYAML, id, id))
+		if err := os.WriteFile(filepath.Join(dir, id+".yml"), yml, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, id+".js"), []byte(script), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := testServerConfig{pluginsPath: dir}
+	c := NewCache(config)
+	c.RegisterSessionStore(session.NewStore(config))
+	c.ReloadPlugins()
+
+	return c
+}
+
+func TestExecutePreHooks(t *testing.T) {
+	const (
+		setTitle = `({ Output: { title: "hooked " + input.Args.hookContext.input.title } })`
+		// returns the fields set so far, and the current title
+		echoFields = `(function() {
+	var ctx = input.Args.hookContext;
+	return { Output: { details: ctx.inputFields.join(",") + ":" + ctx.input.title } };
+})()`
+		reject    = `({ Error: "rejected" })`
+		noOutput  = `log.Debug("no output")`
+		neverEnds = `while (true) {}`
+	)
+
+	tests := []struct {
+		name        string
+		scripts     map[string]string
+		inputFields []string
+		want        testHookInput
+		wantFields  []string
+		wantErr     string
+	}{
+		{
+			"no output",
+			map[string]string{"a": noOutput},
+			[]string{"title"},
+			testHookInput{Title: "title"},
+			nil,
+			"",
+		},
+		{
+			"output merged",
+			map[string]string{"a": setTitle},
+			[]string{"title"},
+			testHookInput{Title: "hooked title"},
+			[]string{"title"},
+			"",
+		},
+		{
+			"input fields propagated",
+			map[string]string{"a": setTitle, "b": echoFields},
+			[]string{"id"},
+			testHookInput{Title: "hooked title", Details: "id,title:hooked title"},
+			[]string{"title", "details"},
+			"",
+		},
+		{
+			"error aborts",
+			map[string]string{"a": reject, "b": setTitle},
+			[]string{"title"},
+			testHookInput{Title: "title"},
+			nil,
+			"Scene.Update.Pre [a]: rejected",
+		},
+		{
+			"timeout",
+			map[string]string{"a": neverEnds, "b": setTitle},
+			[]string{"title"},
+			testHookInput{Title: "title"},
+			nil,
+			"Scene.Update.Pre [a]: timed out after 1s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestHookCache(t, tt.scripts)
+
+			input := testHookInput{Title: "title"}
+			fields, err := c.ExecutePreHooks(context.Background(), 1, hook.SceneUpdatePre, &input, tt.inputFields)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, input)
+			assert.Equal(t, tt.wantFields, fields)
+		})
+	}
+
+	t.Run("other trigger", func(t *testing.T) {
+		c := newTestHookCache(t, map[string]string{"a": setTitle})
+
+		input := testHookInput{Title: "title"}
+		fields, err := c.ExecutePreHooks(context.Background(), 1, hook.SceneDestroyPre, &input, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, fields)
+		assert.Equal(t, "title", input.Title)
+	})
+}
@@ -29,6 +29,7 @@ type jsPluginTask struct {
 	started   bool
 	waitGroup sync.WaitGroup
 	vm        *javascript.VM
+	gql       *javascript.GQL
 }
 
 func (t *jsPluginTask) onError(err error) {
@@ -93,15 +94,57 @@ func (t *jsPluginTask) initVM() error {
 		return fmt.Errorf("error adding util API: %w", err)
 	}
 
-	gql := &javascript.GQL{
+	t.gql = &javascript.GQL{
 		Context:    context.TODO(),
 		Cookie:     t.input.ServerConnection.SessionCookie,
 		GQLHandler: t.gqlHandler,
 	}
-	if err := gql.AddToVM("gql", t.vm); err != nil {
+	if err := t.gql.AddToVM("gql", t.vm); err != nil {
 		return fmt.Errorf("error adding GraphQL API: %w", err)
 	}
 
+	return t.addPermittedAPIs()
+}
+
+// addPermittedAPIs adds the APIs that the plugin has been granted
+// permission to use in its configuration.
+func (t *jsPluginTask) addPermittedAPIs() error {
+	permissions := t.plugin.Permissions
+
+	if len(permissions.HTTP) > 0 {
+		h := &javascript.HTTP{
+			Context:        t.context(),
+			AllowedSources: permissions.HTTP,
+		}
+		if err := h.AddToVM("http", t.vm); err != nil {
+			return fmt.Errorf("error adding http API: %w", err)
+		}
+	}
+
+	if permissions.Filesystem {
+		f := &javascript.FS{
+			Context: t.context(),
+			Roots:   t.serverConfig.GetLibraryPaths(),
+		}
+		if err := f.AddToVM("fs", t.vm); err != nil {
+			return fmt.Errorf("error adding fs API: %w", err)
+		}
+	}
+
+	if permissions.Storage {
+		if t.storage == nil {
+			return errors.New("plugin storage is not available")
+		}
+
+		s := &javascript.Storage{
+			Context: t.context(),
+			Store:   t.storage.forPlugin(t.plugin.id),
+		}
+		if err := s.AddToVM("storage", t.vm); err != nil {
+			return fmt.Errorf("error adding storage API: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,273 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+
+	"github.com/dop251/goja"
+	"github.com/stashapp/stash/pkg/javascript"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/plugin/common"
+)
+
+// maxServiceRequestSize is the maximum size of a request body that will be
+// passed to a javascript service.
+const maxServiceRequestSize = 10 * 1024 * 1024
+
+type jsServiceRequest struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Query   string            `json:"query"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+
+	cookie *http.Cookie
+	reply  chan jsServiceResponse
+}
+
+type jsServiceResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+}
+
+// jsService is a service instance running in a javascript VM.
+//
+// The script is run once when the service starts, and registers handlers
+// using the service API. The handlers are subsequently called for each hook
+// event and HTTP request. The VM is only accessed from the run goroutine.
+type jsService struct {
+	task     *jsPluginTask
+	events   <-chan serviceEvent
+	requests chan *jsServiceRequest
+	stopChan chan struct{}
+	done     chan struct{}
+	err      error
+
+	// cancel cancels the context used by the APIs of the service
+	cancel context.CancelFunc
+
+	onHook goja.Callable
+	onHTTP goja.Callable
+}
+
+func startJSService(s *service) (*jsService, error) {
+	if len(s.plugin.Exec) == 0 {
+		return nil, errors.New("no script specified in exec")
+	}
+
+	scriptFile := s.plugin.Exec[0]
+	script, err := javascript.Compile(filepath.Join(s.plugin.getConfigPath(), scriptFile))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(s.context())
+	input := s.makeInput(s.cache.makeServerConnection(ctx), common.ServiceContext{}, nil)
+	t := &jsPluginTask{
+		pluginTask: s.newTask(ctx, input),
+		vm:         javascript.NewVM(),
+	}
+
+	if err := t.initVM(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	ret := &jsService{
+		task:     t,
+		events:   s.events,
+		requests: make(chan *jsServiceRequest),
+		stopChan: make(chan struct{}),
+		done:     make(chan struct{}),
+		cancel:   cancel,
+	}
+
+	if err := ret.addServiceAPI(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go ret.run(script)
+
+	return ret, nil
+}
+
+func (j *jsService) setCallback(name string, dest *goja.Callable) func(v goja.Value) error {
+	return func(v goja.Value) error {
+		fn, ok := goja.AssertFunction(v)
+		if !ok {
+			return fmt.Errorf("%s requires a function", name)
+		}
+
+		*dest = fn
+		return nil
+	}
+}
+
+func (j *jsService) addServiceAPI() error {
+	vm := j.task.vm
+	obj := vm.NewObject()
+
+	if err := javascript.SetAll(obj,
+		javascript.ObjectValueDef{Name: "OnHook", Value: j.setCallback("OnHook", &j.onHook)},
+		javascript.ObjectValueDef{Name: "OnHTTP", Value: j.setCallback("OnHTTP", &j.onHTTP)},
+	); err != nil {
+		return err
+	}
+
+	if err := vm.Set("service", obj); err != nil {
+		return fmt.Errorf("error adding service API: %w", err)
+	}
+
+	return nil
+}
+
+func (j *jsService) stopped() bool {
+	select {
+	case <-j.stopChan:
+		return true
+	default:
+		return false
+	}
+}
+
+func (j *jsService) run(script *goja.Program) {
+	defer close(j.done)
+	defer j.cancel()
+	defer func() {
+		if caught := recover(); caught != nil {
+			j.err = fmt.Errorf("%v", caught)
+		}
+	}()
+
+	if _, err := j.task.vm.RunProgram(script); err != nil {
+		j.err = err
+		return
+	}
+
+	for !j.stopped() {
+		select {
+		case <-j.stopChan:
+		case e := <-j.events:
+			j.handleHook(e)
+		case req := <-j.requests:
+			req.reply <- j.handleHTTP(req)
+		}
+	}
+}
+
+func (j *jsService) handleHook(e serviceEvent) {
+	if j.onHook == nil {
+		return
+	}
+
+	j.task.gql.Cookie = e.serverConnection.SessionCookie
+
+	vm := j.task.vm
+	if _, err := j.onHook(goja.Undefined(), vm.ToValue(e.hookContext)); err != nil {
+		logger.Errorf("%s [%s]: service returned error: %v", e.hookContext.Type, j.task.plugin.Name, err)
+	}
+}
+
+func (j *jsService) handleHTTP(req *jsServiceRequest) jsServiceResponse {
+	if j.onHTTP == nil {
+		return jsServiceResponse{
+			Status: http.StatusNotFound,
+		}
+	}
+
+	j.task.gql.Cookie = req.cookie
+
+	vm := j.task.vm
+	v, err := j.onHTTP(goja.Undefined(), vm.ToValue(req))
+	if err != nil {
+		logger.Errorf("[Plugin / %s] service returned error handling %s %s: %v", j.task.plugin.Name, req.Method, req.Path, err)
+		return jsServiceResponse{
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	ret := jsServiceResponse{}
+	if v != nil && !goja.IsUndefined(v) && !goja.IsNull(v) {
+		if err := vm.ExportTo(v, &ret); err != nil {
+			logger.Errorf("[Plugin / %s] service returned invalid response: %v", j.task.plugin.Name, err)
+			return jsServiceResponse{
+				Status: http.StatusInternalServerError,
+			}
+		}
+	}
+
+	if ret.Status == 0 {
+		ret.Status = http.StatusOK
+	}
+
+	return ret
+}
+
+func (j *jsService) wait() error {
+	<-j.done
+	return j.err
+}
+
+func (j *jsService) stop() {
+	close(j.stopChan)
+	j.cancel()
+	// interrupt any handler that is currently running
+	j.task.vm.Interrupt(errStop)
+}
+
+func (j *jsService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxServiceRequestSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req := &jsServiceRequest{
+		Method:  r.Method,
+		Path:    r.URL.Path,
+		Query:   r.URL.RawQuery,
+		Headers: make(map[string]string),
+		Body:    string(body),
+		cookie:  j.task.input.ServerConnection.SessionCookie,
+		reply:   make(chan jsServiceResponse, 1),
+	}
+
+	for k := range r.Header {
+		req.Headers[k] = r.Header.Get(k)
+	}
+
+	ctx := r.Context()
+
+	select {
+	case j.requests <- req:
+	case <-j.done:
+		http.Error(w, errServiceNotRunning.Error(), http.StatusServiceUnavailable)
+		return
+	case <-ctx.Done():
+		return
+	}
+
+	var resp jsServiceResponse
+	select {
+	case resp = <-req.reply:
+	case <-j.done:
+		http.Error(w, errServiceNotRunning.Error(), http.StatusServiceUnavailable)
+		return
+	case <-ctx.Done():
+		return
+	}
+
+	for k, v := range resp.Headers {
+		w.Header().Set(k, v)
+	}
+	w.WriteHeader(resp.Status)
+	if _, err := io.WriteString(w, resp.Body); err != nil {
+		logger.Warnf("error writing plugin service response: %v", err)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
@@ -96,6 +97,8 @@ type ServerConfig interface {
 	GetPluginsPath() string
 	GetDisabledPlugins() []string
 	GetPythonPath() string
+	GetLibraryPaths() []string
+	GetUsername() string
 }
 
 // Cache stores plugin details.
@@ -104,6 +107,8 @@ type Cache struct {
 	plugins      []Config
 	sessionStore *session.Store
 	gqlHandler   http.Handler
+	storage      *storageProvider
+	services     *serviceManager
 }
 
 // NewCache returns a new Cache.
@@ -115,7 +120,8 @@ type Cache struct {
 // loaded explicitly using ReloadPlugins.
 func NewCache(config ServerConfig) *Cache {
 	return &Cache{
-		config: config,
+		config:   config,
+		services: newServiceManager(),
 	}
 }
 
@@ -127,6 +133,15 @@ func (c *Cache) RegisterSessionStore(sessionStore *session.Store) {
 	c.sessionStore = sessionStore
 }
 
+// RegisterStorage sets the store used to persist values for plugins
+// using the storage API.
+func (c *Cache) RegisterStorage(txnManager txn.Manager, store StorageReaderWriter) {
+	c.storage = &storageProvider{
+		txnManager: txnManager,
+		store:      store,
+	}
+}
+
 // ReloadPlugins clears the plugin cache and loads from the plugin path.
 // If a plugin cannot be loaded, an error is logged and the plugin is skipped.
 func (c *Cache) ReloadPlugins() {
@@ -161,6 +176,9 @@ func (c *Cache) ReloadPlugins() {
 	}
 
 	c.plugins = plugins
+
+	// restart services in case their configuration has changed
+	c.restartServices()
 }
 
 func (c Cache) enabledPlugins() []Config {
@@ -241,6 +259,15 @@ func buildPluginInput(plugin *Config, operation *OperationConfig, serverConnecti
 	}
 }
 
+// withChangeOrigin returns a context that attributes changes made by the
+// plugin to it.
+func withChangeOrigin(ctx context.Context, pluginID string) context.Context {
+	return models.WithChangeOrigin(ctx, models.ChangeOrigin{
+		Type: models.ChangeOriginTypePlugin,
+		ID:   &pluginID,
+	})
+}
+
 func (c Cache) makeServerConnection(ctx context.Context) common.StashServerConnection {
 	cookie := c.sessionStore.MakePluginCookie(ctx)
 
@@ -263,6 +290,7 @@ func (c Cache) makeServerConnection(ctx context.Context) common.StashServerConne
 // name provided. Returns an error if the plugin or the operation could not be
 // resolved.
 func (c Cache) CreateTask(ctx context.Context, pluginID string, operationName *string, args OperationInput, progress chan float64) (Task, error) {
+	ctx = withChangeOrigin(ctx, pluginID)
 	serverConnection := c.makeServerConnection(ctx)
 
 	if c.pluginDisabled(pluginID) {
@@ -285,17 +313,20 @@ func (c Cache) CreateTask(ctx context.Context, pluginID string, operationName *s
 	}
 
 	task := pluginTask{
+		ctx:          ctx,
 		plugin:       plugin,
 		operation:    operation,
 		input:        buildPluginInput(plugin, operation, serverConnection, args),
 		progress:     progress,
 		gqlHandler:   c.gqlHandler,
 		serverConfig: c.config,
+		storage:      c.storage,
 	}
 	return task.createTask(), nil
 }
 
 func (c Cache) RunPlugin(ctx context.Context, pluginID string, args OperationInput) (interface{}, error) {
+	ctx = withChangeOrigin(ctx, pluginID)
 	serverConnection := c.makeServerConnection(ctx)
 
 	if c.pluginDisabled(pluginID) {
@@ -308,10 +339,12 @@ func (c Cache) RunPlugin(ctx context.Context, pluginID string, args OperationInp
 	pluginInput := buildPluginInput(plugin, nil, serverConnection, args)
 
 	pt := pluginTask{
+		ctx:          ctx,
 		plugin:       plugin,
 		input:        pluginInput,
 		gqlHandler:   c.gqlHandler,
 		serverConfig: c.config,
+		storage:      c.storage,
 	}
 
 	task := pt.createTask()
@@ -388,7 +421,13 @@ func (c Cache) ExecuteSceneUpdatePostHooks(ctx context.Context, input models.Sce
 // that should not be hit under normal circumstances.
 const maxCyclicLoopDepth = 10
 
+// defaultPreHookTimeout is the maximum time that a pre-hook operation may run
+// for if a timeout is not set in the hook configuration.
+const defaultPreHookTimeout = 30 * time.Second
+
 func (c Cache) executePostHooks(ctx context.Context, hookType hook.TriggerEnum, hookContext common.HookContext) error {
+	c.sendServiceEvents(ctx, hookType, hookContext)
+
 	visitedPluginHookCounts := getVisitedPluginHookCounts(ctx)
 
 	for _, p := range c.enabledPlugins() {
@@ -401,30 +440,11 @@ func (c Cache) executePostHooks(ctx context.Context, hookType hook.TriggerEnum,
 		}
 
 		for _, h := range hooks {
-			newCtx := session.AddVisitedPluginHook(ctx, p.id, hookType)
-			serverConnection := c.makeServerConnection(newCtx)
-
-			pluginInput := buildPluginInput(&p, &h.OperationConfig, serverConnection, nil)
-			addHookContext(pluginInput.Args, hookContext)
-
-			pt := pluginTask{
-				plugin:       &p,
-				operation:    &h.OperationConfig,
-				input:        pluginInput,
-				gqlHandler:   c.gqlHandler,
-				serverConfig: c.config,
-			}
-
-			task := pt.createTask()
-			if err := task.Start(); err != nil {
-				return err
-			}
-
-			if err := waitForTask(ctx, task); err != nil {
+			output, err := c.runHook(ctx, &p, h, hookType, hookContext)
+			if err != nil {
 				return err
 			}
 
-			output := task.GetResult()
 			if output == nil {
 				logger.Debugf("%s [%s]: returned no result", hookType.String(), p.Name)
 			} else {
@@ -440,6 +460,106 @@ func (c Cache) executePostHooks(ctx context.Context, hookType hook.TriggerEnum,
 	return nil
 }
 
+// ExecutePreHooks synchronously executes the pre-hooks registered for
+// hookType. It is intended to be called within the transaction of the
+// triggering operation, before the operation is performed.
+//
+// If a hook returns an error, or does not complete within its timeout,
+// then the remaining hooks are not executed and an error is returned.
+// The caller is expected to abort the operation in this case.
+//
+// If input is a pointer and a hook returns an object as its output, then
+// the object is applied to the value that input points to. Subsequent hooks
+// are passed the modified input. The names of the fields set by the hooks
+// are returned so that the caller may include them in the operation.
+func (c Cache) ExecutePreHooks(ctx context.Context, id int, hookType hook.TriggerEnum, input interface{}, inputFields []string) ([]string, error) {
+	visitedPluginHookCounts := getVisitedPluginHookCounts(ctx)
+
+	var setFields []string
+	for _, p := range c.enabledPlugins() {
+		hooks := p.getHooks(hookType)
+		// don't revisit a plugin we've already visited
+		// only log if there's hooks that we're skipping
+		if len(hooks) > 0 && visitedPluginHookCounts.For(p.id, hookType) >= maxCyclicLoopDepth {
+			logger.Debugf("cyclic loop detected: plugin ID '%s' hook %s, not re-triggering", p.id, hookType)
+			continue
+		}
+
+		for _, h := range hooks {
+			hookContext := common.HookContext{
+				ID:          id,
+				Type:        hookType.String(),
+				Input:       input,
+				InputFields: sliceutil.AppendUniques(inputFields, setFields),
+			}
+
+			timeout := h.getTimeout()
+			hookCtx, cancel := context.WithTimeout(ctx, timeout)
+			output, err := c.runHook(hookCtx, &p, h, hookType, hookContext)
+			timedOut := errors.Is(hookCtx.Err(), context.DeadlineExceeded)
+			cancel()
+
+			if timedOut {
+				return nil, fmt.Errorf("%s [%s]: timed out after %v", hookType.String(), p.Name, timeout)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s [%s]: %w", hookType.String(), p.Name, err)
+			}
+
+			if output == nil {
+				logger.Debugf("%s [%s]: returned no result", hookType.String(), p.Name)
+				continue
+			}
+
+			if output.Error != nil {
+				return nil, fmt.Errorf("%s [%s]: %s", hookType.String(), p.Name, *output.Error)
+			}
+
+			fields, err := applyHookOutput(input, output.Output)
+			if err != nil {
+				return nil, fmt.Errorf("%s [%s]: applying output: %w", hookType.String(), p.Name, err)
+			}
+
+			if len(fields) > 0 {
+				logger.Debugf("%s [%s]: set fields: %v", hookType.String(), p.Name, fields)
+				setFields = sliceutil.AppendUniques(setFields, fields)
+			}
+		}
+	}
+
+	return setFields, nil
+}
+
+// runHook runs a single hook operation and waits for it to complete.
+func (c Cache) runHook(ctx context.Context, p *Config, h *HookConfig, hookType hook.TriggerEnum, hookContext common.HookContext) (*common.PluginOutput, error) {
+	newCtx := withChangeOrigin(session.AddVisitedPluginHook(ctx, p.id, hookType), p.id)
+	serverConnection := c.makeServerConnection(newCtx)
+
+	pluginInput := buildPluginInput(p, &h.OperationConfig, serverConnection, nil)
+	addHookContext(pluginInput.Args, hookContext)
+
+	pt := pluginTask{
+		ctx:          newCtx,
+		plugin:       p,
+		operation:    &h.OperationConfig,
+		input:        pluginInput,
+		gqlHandler:   c.gqlHandler,
+		serverConfig: c.config,
+		storage:      c.storage,
+	}
+
+	task := pt.createTask()
+	if err := task.Start(); err != nil {
+		return nil, err
+	}
+
+	if err := waitForTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	return task.GetResult(), nil
+}
+
 type visitedPluginHookCount struct {
 	session.VisitedPluginHook
 	Count int
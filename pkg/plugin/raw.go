@@ -25,28 +25,17 @@ func (*rawTaskBuilder) build(task pluginTask) Task {
 	}
 }
 
-type rawPluginTask struct {
-	pluginTask
-
-	started   bool
-	waitGroup sync.WaitGroup
-	cmd       *exec.Cmd
-	done      chan bool
-}
-
-func (t *rawPluginTask) Start() error {
-	if t.started {
-		return errors.New("task already started")
-	}
-
-	command := t.plugin.getExecCommand(t.operation)
+// makeExecCommand returns the command to execute for the plugin operation.
+// Python commands are run using the configured python path.
+func makeExecCommand(plugin *Config, operation *OperationConfig, serverConfig ServerConfig) (*exec.Cmd, error) {
+	command := plugin.getExecCommand(operation)
 	if len(command) == 0 {
-		return fmt.Errorf("empty exec value")
+		return nil, fmt.Errorf("empty exec value")
 	}
 
 	var cmd *exec.Cmd
 	if python.IsPythonCommand(command[0]) {
-		pythonPath := t.serverConfig.GetPythonPath()
+		pythonPath := serverConfig.GetPythonPath()
 		p, err := python.Resolve(pythonPath)
 
 		if err != nil {
@@ -54,7 +43,7 @@ func (t *rawPluginTask) Start() error {
 		} else {
 			cmd = p.Command(context.TODO(), command[1:])
 
-			envVariable, _ := filepath.Abs(filepath.Dir(filepath.Dir(t.plugin.path)))
+			envVariable, _ := filepath.Abs(filepath.Dir(filepath.Dir(plugin.path)))
 			python.AppendPythonPath(cmd, envVariable)
 		}
 	}
@@ -64,6 +53,28 @@ func (t *rawPluginTask) Start() error {
 		cmd = stashExec.Command(command[0], command[1:]...)
 	}
 
+	return cmd, nil
+}
+
+type rawPluginTask struct {
+	pluginTask
+
+	started   bool
+	waitGroup sync.WaitGroup
+	cmd       *exec.Cmd
+	done      chan bool
+}
+
+func (t *rawPluginTask) Start() error {
+	if t.started {
+		return errors.New("task already started")
+	}
+
+	cmd, err := makeExecCommand(t.plugin, t.operation, t.serverConfig)
+	if err != nil {
+		return err
+	}
+
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
 		return fmt.Errorf("error getting plugin process stdin: %v", err)
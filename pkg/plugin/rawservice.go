@@ -0,0 +1,142 @@
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/plugin/common"
+)
+
+// processService is a service instance running as a separate process.
+//
+// The common.PluginInput is encoded as a single line of json and written to
+// the process's stdin when it starts. Each hook event is subsequently
+// written as a line containing a common.PluginInput with the hook context
+// set. HTTP requests are proxied to the port set in the service context.
+type processService struct {
+	cmd   *exec.Cmd
+	proxy *httputil.ReverseProxy
+	done  chan struct{}
+	err   error
+}
+
+// getFreePort returns a port on the loopback interface that is not in use.
+func getFreePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+func startProcessService(s *service) (*processService, error) {
+	port, err := getFreePort()
+	if err != nil {
+		return nil, fmt.Errorf("error getting service port: %w", err)
+	}
+
+	serviceContext := common.ServiceContext{
+		Port: port,
+	}
+
+	cmd, err := makeExecCommand(&s.plugin, s.config(), s.cache.config)
+	if err != nil {
+		return nil, err
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("error getting plugin process stdin: %v", err)
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("plugin stderr not available: %v", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("error running plugin service: %v", err)
+	}
+
+	ctx := s.context()
+	input := s.makeInput(s.cache.makeServerConnection(ctx), serviceContext, nil)
+	pt := s.newTask(ctx, input)
+	go pt.handlePluginStderr(s.plugin.Name, stderr)
+
+	logger.Debugf("Plugin %s service started: %s", s.plugin.Name, strings.Join(cmd.Args, " "))
+
+	target := &url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort("127.0.0.1", strconv.Itoa(port)),
+	}
+
+	ret := &processService{
+		cmd:   cmd,
+		proxy: httputil.NewSingleHostReverseProxy(target),
+		done:  make(chan struct{}),
+	}
+
+	go func() {
+		defer close(ret.done)
+		ret.err = cmd.Wait()
+	}()
+
+	go ret.writeInput(stdin, input, s.events, func(e serviceEvent) common.PluginInput {
+		return s.makeInput(e.serverConnection, serviceContext, &e.hookContext)
+	})
+
+	return ret, nil
+}
+
+// writeInput writes the initial input and subsequent events to the
+// process's stdin until the process exits.
+func (p *processService) writeInput(stdin io.WriteCloser, input common.PluginInput, events <-chan serviceEvent, makeInput func(e serviceEvent) common.PluginInput) {
+	defer stdin.Close()
+
+	// Encode terminates each value with a newline
+	encoder := json.NewEncoder(stdin)
+	if err := encoder.Encode(input); err != nil {
+		logger.Warnf("error writing input to plugin service stdin: %v", err)
+		return
+	}
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case e := <-events:
+			if err := encoder.Encode(makeInput(e)); err != nil {
+				logger.Warnf("error writing %s event to plugin service stdin: %v", e.hookContext.Type, err)
+				return
+			}
+		}
+	}
+}
+
+func (p *processService) wait() error {
+	<-p.done
+	return p.err
+}
+
+func (p *processService) stop() {
+	if err := p.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		logger.Warnf("error stopping plugin service: %v", err)
+	}
+}
+
+func (p *processService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.proxy.ServeHTTP(w, r)
+}
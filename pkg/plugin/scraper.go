@@ -0,0 +1,184 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dop251/goja"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/plugin/common"
+)
+
+// ScraperConfig describes the scrape operations provided by a plugin.
+// The keys mirror those of the scraper configuration files. Each scrape
+// operation is performed by running the plugin with a scrapeContext argument.
+type ScraperConfig struct {
+	// Configuration for querying performers by name
+	PerformerByName *OperationConfig `yaml:"performerByName"`
+
+	// Configuration for querying performers by a Performer fragment
+	PerformerByFragment *OperationConfig `yaml:"performerByFragment"`
+
+	// Configuration for querying a performer by a URL
+	PerformerByURL []*ScrapeByURLConfig `yaml:"performerByURL"`
+
+	// Configuration for querying scenes by a Scene fragment
+	SceneByFragment *OperationConfig `yaml:"sceneByFragment"`
+
+	// Configuration for querying scenes by name
+	SceneByName *OperationConfig `yaml:"sceneByName"`
+
+	// Configuration for querying scenes by query fragment
+	SceneByQueryFragment *OperationConfig `yaml:"sceneByQueryFragment"`
+
+	// Configuration for querying a scene by a URL
+	SceneByURL []*ScrapeByURLConfig `yaml:"sceneByURL"`
+
+	// Configuration for querying gallery by a Gallery fragment
+	GalleryByFragment *OperationConfig `yaml:"galleryByFragment"`
+
+	// Configuration for querying a gallery by a URL
+	GalleryByURL []*ScrapeByURLConfig `yaml:"galleryByURL"`
+
+	// Configuration for querying a movie by a URL
+	MovieByURL []*ScrapeByURLConfig `yaml:"movieByURL"`
+
+	// Configuration for querying an image by an Image fragment
+	ImageByFragment *OperationConfig `yaml:"imageByFragment"`
+
+	// Configuration for querying an image by a URL
+	ImageByURL []*ScrapeByURLConfig `yaml:"imageByURL"`
+
+	// Configuration for querying studios by name
+	StudioByName *OperationConfig `yaml:"studioByName"`
+
+	// Configuration for querying a studio by a Studio fragment
+	StudioByFragment *OperationConfig `yaml:"studioByFragment"`
+
+	// Configuration for querying a studio by a URL
+	StudioByURL []*ScrapeByURLConfig `yaml:"studioByURL"`
+
+	// Configuration for querying tags by name
+	TagByName *OperationConfig `yaml:"tagByName"`
+
+	// Configuration for querying a tag by a Tag fragment
+	TagByFragment *OperationConfig `yaml:"tagByFragment"`
+
+	// Configuration for querying a tag by a URL
+	TagByURL []*ScrapeByURLConfig `yaml:"tagByURL"`
+}
+
+func (c ScraperConfig) validate() error {
+	urlConfigs := [][]*ScrapeByURLConfig{
+		c.PerformerByURL,
+		c.SceneByURL,
+		c.GalleryByURL,
+		c.MovieByURL,
+		c.ImageByURL,
+		c.StudioByURL,
+		c.TagByURL,
+	}
+
+	for _, l := range urlConfigs {
+		for _, s := range l {
+			if len(s.URL) == 0 {
+				return errors.New("url is mandatory for scrape by url operations")
+			}
+		}
+	}
+
+	return nil
+}
+
+// ScrapeByURLConfig describes a scrape by URL operation provided by a plugin.
+type ScrapeByURLConfig struct {
+	OperationConfig `yaml:",inline"`
+
+	// The URL substrings that this operation supports.
+	URL []string `yaml:"url,flow"`
+}
+
+func addScrapeContext(argsMap common.ArgsMap, scrapeContext common.ScrapeContext) {
+	argsMap[common.ScrapeContextKey] = scrapeContext
+}
+
+// ScraperPlugin is an enabled plugin that provides scrape operations.
+type ScraperPlugin struct {
+	ID      string
+	Name    string
+	Scraper ScraperConfig
+}
+
+// ListScraperPlugins returns the enabled plugins that provide scrape operations.
+func (c Cache) ListScraperPlugins() []ScraperPlugin {
+	var ret []ScraperPlugin
+	for _, p := range c.enabledPlugins() {
+		if p.Scraper == nil {
+			continue
+		}
+
+		ret = append(ret, ScraperPlugin{
+			ID:      p.id,
+			Name:    p.getName(),
+			Scraper: *p.Scraper,
+		})
+	}
+
+	return ret
+}
+
+// RunScraper runs a scrape operation of the plugin with the given ID. The
+// scrape context is passed to the plugin in the scrapeContext argument.
+// Returns the output of the plugin, or an error if the plugin returned an error.
+func (c Cache) RunScraper(ctx context.Context, pluginID string, operation *OperationConfig, scrapeContext common.ScrapeContext) (interface{}, error) {
+	if c.pluginDisabled(pluginID) {
+		return nil, fmt.Errorf("plugin %s is disabled", pluginID)
+	}
+
+	plugin := c.getPlugin(pluginID)
+	if plugin == nil {
+		return nil, fmt.Errorf("no plugin with ID %s", pluginID)
+	}
+
+	serverConnection := c.makeServerConnection(ctx)
+
+	pluginInput := buildPluginInput(plugin, operation, serverConnection, nil)
+	addScrapeContext(pluginInput.Args, scrapeContext)
+
+	pt := pluginTask{
+		ctx:          ctx,
+		plugin:       plugin,
+		operation:    operation,
+		input:        pluginInput,
+		gqlHandler:   c.gqlHandler,
+		serverConfig: c.config,
+		storage:      c.storage,
+	}
+
+	task := pt.createTask()
+	if err := task.Start(); err != nil {
+		return nil, err
+	}
+
+	if err := waitForTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	output := task.GetResult()
+	if output == nil {
+		logger.Debugf("%s [%s]: returned no result", scrapeContext.Type, plugin.Name)
+		return nil, nil
+	}
+
+	if output.Error != nil {
+		return nil, errors.New(*output.Error)
+	}
+
+	// javascript plugins return their output as a javascript value
+	if v, ok := output.Output.(goja.Value); ok {
+		return v.Export(), nil
+	}
+
+	return output.Output, nil
+}
@@ -0,0 +1,402 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/plugin/common"
+	"github.com/stashapp/stash/pkg/plugin/hook"
+	"github.com/stashapp/stash/pkg/session"
+)
+
+const (
+	// minServiceBackoff is the time to wait before restarting a service
+	// that has exited. The wait time is doubled each time the service
+	// exits, up to maxServiceBackoff.
+	minServiceBackoff = time.Second
+	maxServiceBackoff = 5 * time.Minute
+
+	// serviceStableDuration is the time that a service must run for before
+	// the restart wait time is reset.
+	serviceStableDuration = time.Minute
+
+	// serviceEventBufferSize is the number of hook events that may be
+	// queued for a service. Events are dropped if the queue is full.
+	serviceEventBufferSize = 100
+)
+
+var errServiceNotRunning = errors.New("service not running")
+
+// ServiceConfig describes the configuration for a long-running service
+// provided by a plugin. Services are started when stash starts, and are
+// restarted if they exit.
+type ServiceConfig struct {
+	OperationConfig `yaml:",inline"`
+
+	// A list of post-hook operations that will be sent to the service.
+	TriggeredBy []hook.TriggerEnum `yaml:"triggeredBy"`
+}
+
+func (c ServiceConfig) validate(i interfaceEnum) error {
+	if i != InterfaceEnumRaw && i != InterfaceEnumJS {
+		return fmt.Errorf("interface %s does not support services", i)
+	}
+
+	for _, t := range c.TriggeredBy {
+		if !t.IsValid() || !t.IsPost() {
+			return fmt.Errorf("invalid service trigger %s: only post-hooks are supported", t)
+		}
+	}
+
+	return nil
+}
+
+func (c ServiceConfig) triggeredBy(hookType hook.TriggerEnum) bool {
+	for _, t := range c.TriggeredBy {
+		if t == hookType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// serviceEvent is a hook event sent to a running service.
+type serviceEvent struct {
+	hookContext      common.HookContext
+	serverConnection common.StashServerConnection
+}
+
+// serviceInstance is a single run of a plugin service.
+type serviceInstance interface {
+	http.Handler
+
+	// wait blocks until the instance exits, returning the reason it exited.
+	wait() error
+
+	// stop instructs the instance to exit and returns immediately.
+	stop()
+}
+
+// service supervises the instances of a plugin service, restarting the
+// service when it exits.
+type service struct {
+	cache  Cache
+	plugin Config
+
+	events   chan serviceEvent
+	stopChan chan struct{}
+	done     chan struct{}
+
+	mutex    sync.RWMutex
+	instance serviceInstance
+}
+
+func newService(c Cache, plugin Config) *service {
+	return &service{
+		cache:    c,
+		plugin:   plugin,
+		events:   make(chan serviceEvent, serviceEventBufferSize),
+		stopChan: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+func (s *service) config() *OperationConfig {
+	return &s.plugin.Service.OperationConfig
+}
+
+// context returns the context used to authenticate the service when it
+// is not handling a specific event or request.
+func (s *service) context() context.Context {
+	ctx := context.Background()
+	if username := s.cache.config.GetUsername(); username != "" {
+		ctx = session.SetCurrentUserID(ctx, username)
+	}
+
+	return withChangeOrigin(ctx, s.plugin.id)
+}
+
+func (s *service) newTask(ctx context.Context, input common.PluginInput) pluginTask {
+	return pluginTask{
+		ctx:          ctx,
+		plugin:       &s.plugin,
+		operation:    s.config(),
+		input:        input,
+		gqlHandler:   s.cache.gqlHandler,
+		serverConfig: s.cache.config,
+		storage:      s.cache.storage,
+	}
+}
+
+func (s *service) makeInput(serverConnection common.StashServerConnection, serviceContext common.ServiceContext, hookContext *common.HookContext) common.PluginInput {
+	input := buildPluginInput(&s.plugin, s.config(), serverConnection, nil)
+	input.Args[common.ServiceContextKey] = serviceContext
+	if hookContext != nil {
+		addHookContext(input.Args, *hookContext)
+	}
+
+	return input
+}
+
+func (s *service) logPrefix() string {
+	return fmt.Sprintf("[Plugin / %s] ", s.plugin.Name)
+}
+
+func (s *service) setInstance(instance serviceInstance) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.instance = instance
+}
+
+func (s *service) getInstance() serviceInstance {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.instance
+}
+
+func (s *service) startInstance() (serviceInstance, error) {
+	switch s.plugin.Interface {
+	case InterfaceEnumRaw:
+		return startProcessService(s)
+	case InterfaceEnumJS:
+		return startJSService(s)
+	}
+
+	return nil, fmt.Errorf("interface %s does not support services", s.plugin.Interface)
+}
+
+// run starts the service and restarts it whenever it exits, until the
+// service is stopped.
+func (s *service) run() {
+	defer close(s.done)
+
+	var backoff time.Duration
+	for {
+		started := time.Now()
+
+		instance, err := s.startInstance()
+		if err == nil {
+			logger.Infof("%sservice started", s.logPrefix())
+			s.setInstance(instance)
+
+			exited := make(chan error, 1)
+			go func() {
+				exited <- instance.wait()
+			}()
+
+			select {
+			case <-s.stopChan:
+				instance.stop()
+				<-exited
+				s.setInstance(nil)
+				logger.Infof("%sservice stopped", s.logPrefix())
+				return
+			case err = <-exited:
+				s.setInstance(nil)
+			}
+		}
+
+		backoff = nextServiceBackoff(backoff, time.Since(started))
+
+		if err == nil {
+			err = errors.New("exited unexpectedly")
+		}
+		logger.Errorf("%sservice error: %v. Restarting in %v", s.logPrefix(), err, backoff)
+
+		select {
+		case <-s.stopChan:
+			return
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// nextServiceBackoff returns the time to wait before restarting a service
+// that ran for ranFor, given the previous wait time.
+func nextServiceBackoff(previous time.Duration, ranFor time.Duration) time.Duration {
+	if previous == 0 || ranFor >= serviceStableDuration {
+		return minServiceBackoff
+	}
+
+	ret := previous * 2
+	if ret > maxServiceBackoff {
+		ret = maxServiceBackoff
+	}
+
+	return ret
+}
+
+// stop stops the service and waits for it to exit.
+func (s *service) stop() {
+	close(s.stopChan)
+	<-s.done
+}
+
+// send queues the event for the service. The event is dropped if the
+// queue is full.
+func (s *service) send(e serviceEvent) {
+	select {
+	case s.events <- e:
+	default:
+		logger.Warnf("%sservice event queue is full, dropping %s event", s.logPrefix(), e.hookContext.Type)
+	}
+}
+
+func (s *service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	instance := s.getInstance()
+	if instance == nil {
+		http.Error(w, errServiceNotRunning.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	instance.ServeHTTP(w, r)
+}
+
+// serviceManager maintains the running plugin services.
+type serviceManager struct {
+	mutex    sync.Mutex
+	started  bool
+	services map[string]*service
+}
+
+func newServiceManager() *serviceManager {
+	return &serviceManager{
+		services: make(map[string]*service),
+	}
+}
+
+// refresh starts the services of enabled plugins that are not running,
+// and stops the services of plugins that are no longer enabled.
+// The mutex must be held by the caller.
+func (m *serviceManager) refresh(c Cache) {
+	enabled := make(map[string]Config)
+	for _, p := range c.enabledPlugins() {
+		if p.Service != nil {
+			enabled[p.id] = p
+		}
+	}
+
+	for id, s := range m.services {
+		if _, found := enabled[id]; !found {
+			s.stop()
+			delete(m.services, id)
+		}
+	}
+
+	for id, p := range enabled {
+		if _, found := m.services[id]; !found {
+			s := newService(c, p)
+			m.services[id] = s
+			go s.run()
+		}
+	}
+}
+
+// stopAll stops all running services. The mutex must be held by the caller.
+func (m *serviceManager) stopAll() {
+	for id, s := range m.services {
+		s.stop()
+		delete(m.services, id)
+	}
+}
+
+func (m *serviceManager) get(pluginID string) *service {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.services[pluginID]
+}
+
+// StartServices starts the services provided by enabled plugins. Services
+// are subsequently restarted when plugins are reloaded, and started or
+// stopped when plugins are enabled or disabled using RefreshServices.
+func (c *Cache) StartServices() {
+	m := c.services
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.started = true
+	m.refresh(*c)
+}
+
+// RefreshServices starts the services of enabled plugins and stops the
+// services of disabled plugins. Does nothing if services have not been
+// started.
+func (c Cache) RefreshServices() {
+	m := c.services
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.started {
+		m.refresh(c)
+	}
+}
+
+// restartServices stops all running services and, if services have been
+// started, starts the services of enabled plugins.
+func (c Cache) restartServices() {
+	m := c.services
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.stopAll()
+	if m.started {
+		m.refresh(c)
+	}
+}
+
+// StopServices stops all running services. Services will not be started
+// again until StartServices is called.
+func (c Cache) StopServices() {
+	m := c.services
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.started = false
+	m.stopAll()
+}
+
+// ServeServiceHTTP passes the HTTP request to the service of the plugin.
+// The request path should be relative to the plugin's API path.
+func (c Cache) ServeServiceHTTP(pluginID string, w http.ResponseWriter, r *http.Request) {
+	s := c.services.get(pluginID)
+	if s == nil {
+		http.Error(w, errServiceNotRunning.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	s.ServeHTTP(w, r)
+}
+
+// sendServiceEvents sends the hook event to the running services that are
+// triggered by hookType.
+func (c Cache) sendServiceEvents(ctx context.Context, hookType hook.TriggerEnum, hookContext common.HookContext) {
+	m := c.services
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	visitedPluginHookCounts := getVisitedPluginHookCounts(ctx)
+
+	for id, s := range m.services {
+		if !s.plugin.Service.triggeredBy(hookType) {
+			continue
+		}
+
+		// don't resend events to a service that caused them
+		if visitedPluginHookCounts.For(id, hookType) >= maxCyclicLoopDepth {
+			logger.Debugf("cyclic loop detected: plugin ID '%s' service %s, not re-triggering", id, hookType)
+			continue
+		}
+
+		newCtx := session.AddVisitedPluginHook(ctx, id, hookType)
+		s.send(serviceEvent{
+			hookContext:      hookContext,
+			serverConnection: c.makeServerConnection(withChangeOrigin(newCtx, id)),
+		})
+	}
+}
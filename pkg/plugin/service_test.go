@@ -0,0 +1,322 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/plugin/common"
+	"github.com/stashapp/stash/pkg/plugin/hook"
+	"github.com/stashapp/stash/pkg/session"
+)
+
+func TestNextServiceBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous time.Duration
+		ranFor   time.Duration
+		want     time.Duration
+	}{
+		{"first exit", 0, 0, minServiceBackoff},
+		{"doubled", minServiceBackoff, time.Second, 2 * minServiceBackoff},
+		{"doubled again", 2 * minServiceBackoff, time.Second, 4 * minServiceBackoff},
+		{"capped", maxServiceBackoff - time.Second, time.Second, maxServiceBackoff},
+		{"at max", maxServiceBackoff, time.Second, maxServiceBackoff},
+		{"reset when stable", maxServiceBackoff, serviceStableDuration, minServiceBackoff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextServiceBackoff(tt.previous, tt.ranFor))
+		})
+	}
+}
+
+// writeTestServicePlugin writes a plugin configuration with the service
+// section provided to dir. If script is not empty, it is written to the
+// javascript file executed by the plugin.
+func writeTestServicePlugin(t *testing.T, dir string, id string, yml string, script string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, id+".yml"), []byte(yml), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if script != "" {
+		if err := os.WriteFile(filepath.Join(dir, id+".js"), []byte(script), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func newTestServiceCache(t *testing.T, config *testServerConfig) *Cache {
+	t.Helper()
+
+	c := NewCache(config)
+	c.RegisterSessionStore(session.NewStore(config))
+	c.ReloadPlugins()
+	c.StartServices()
+	t.Cleanup(c.StopServices)
+
+	return c
+}
+
+// serveService returns the response of the plugin's service to a GET
+// request to path.
+func serveService(c *Cache, pluginID string, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c.ServeServiceHTTP(pluginID, w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+// waitForService waits for the plugin's service to be running and to
+// respond to a request to path with a non-empty body.
+func waitForService(t *testing.T, c *Cache, pluginID string, path string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		w := serveService(c, pluginID, path)
+		if (w.Code != http.StatusServiceUnavailable && w.Body.Len() > 0) || time.Now().After(deadline) {
+			return w
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+const testJSServiceYAML = `name: %s
+exec:
+  - %s.js
+interface: js
+service:
+  triggeredBy:
+    - Scene.Update.Post
+`
+
+// testJSService responds to HTTP requests with the request method and path,
+// or with the type of the last hook event received for /hook.
+const testJSService = `var lastHook = "";
+service.OnHook(function(ctx) { lastHook = ctx.type + " " + ctx.id; });
+service.OnHTTP(function(req) {
+	if (req.path === "/hook") {
+		return { body: lastHook };
+	}
+	return { status: 201, headers: { "X-Test": "js" }, body: req.method + " " + req.path };
+});
+`
+
+func TestJSService(t *testing.T) {
+	dir := t.TempDir()
+	writeTestServicePlugin(t, dir, "js", fmt.Sprintf(testJSServiceYAML, "js", "js"), testJSService)
+
+	config := &testServerConfig{pluginsPath: dir}
+	c := newTestServiceCache(t, config)
+
+	t.Run("request", func(t *testing.T) {
+		w := waitForService(t, c, "js", "/foo/bar?x=1")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "js", w.Header().Get("X-Test"))
+		assert.Equal(t, "GET /foo/bar", w.Body.String())
+	})
+
+	t.Run("hook event", func(t *testing.T) {
+		c.ExecutePostHooks(context.Background(), 1, hook.SceneUpdatePost, nil, nil)
+		c.ExecutePostHooks(context.Background(), 2, hook.SceneDestroyPost, nil, nil)
+
+		// only events for the triggers of the service are sent to it
+		w := waitForService(t, c, "js", "/hook")
+		assert.Equal(t, "Scene.Update.Post 1", w.Body.String())
+	})
+
+	t.Run("unknown plugin", func(t *testing.T) {
+		w := serveService(c, "unknown", "/")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+
+	t.Run("disabled plugin", func(t *testing.T) {
+		config.disabledPlugins = []string{"js"}
+		c.RefreshServices()
+		w := serveService(c, "js", "/")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		config.disabledPlugins = nil
+		c.RefreshServices()
+		w = waitForService(t, c, "js", "/")
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		c.StopServices()
+
+		w := serveService(c, "js", "/")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		// services are not started by a refresh once stopped
+		c.RefreshServices()
+		w = serveService(c, "js", "/")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+}
+
+func TestServiceRestart(t *testing.T) {
+	var starts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		starts.Add(1)
+	}))
+	defer server.Close()
+
+	const yml = `name: failing
+exec:
+  - failing.js
+interface: js
+service: {}
+permissions:
+  http:
+    - "*"
+`
+
+	// the service exits immediately after starting
+	script := fmt.Sprintf(`http.Get(%q); throw new Error("failed");`, server.URL)
+
+	dir := t.TempDir()
+	writeTestServicePlugin(t, dir, "failing", yml, script)
+
+	c := newTestServiceCache(t, &testServerConfig{pluginsPath: dir})
+
+	deadline := time.Now().Add(minServiceBackoff + 5*time.Second)
+	for starts.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	assert.GreaterOrEqual(t, starts.Load(), int32(2), "service was not restarted")
+
+	c.StopServices()
+	stopped := starts.Load()
+
+	// the service must not be restarted once stopped
+	time.Sleep(minServiceBackoff + 500*time.Millisecond)
+	assert.Equal(t, stopped, starts.Load())
+}
+
+// testServiceHelperEnv is set when the test binary is run as a raw plugin
+// service by TestProcessService.
+const testServiceHelperEnv = "STASH_TEST_PLUGIN_SERVICE"
+
+// TestServiceHelperProcess is not a real test. It is run as the plugin
+// service process by TestProcessService. It serves HTTP requests on the
+// port provided in the service context, responding with the request method
+// and path, or with the type of the last hook event received for /hook.
+func TestServiceHelperProcess(t *testing.T) {
+	if os.Getenv(testServiceHelperEnv) != "1" {
+		return
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		os.Exit(1)
+	}
+
+	var input struct {
+		Args struct {
+			ServiceContext common.ServiceContext `json:"serviceContext"`
+		} `json:"args"`
+	}
+	if err := json.Unmarshal(line, &input); err != nil {
+		os.Exit(1)
+	}
+
+	var (
+		mutex    sync.Mutex
+		lastHook string
+	)
+
+	go func() {
+		decoder := json.NewDecoder(reader)
+		for {
+			var e struct {
+				Args struct {
+					HookContext common.HookContext `json:"hookContext"`
+				} `json:"args"`
+			}
+			if err := decoder.Decode(&e); err != nil {
+				// stdin is closed when the service is stopped
+				os.Exit(0)
+			}
+
+			mutex.Lock()
+			lastHook = e.Args.HookContext.Type + " " + strconv.Itoa(e.Args.HookContext.ID)
+			mutex.Unlock()
+		}
+	}()
+
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(input.Args.ServiceContext.Port))
+	err = http.ListenAndServe(addr, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/hook" {
+			mutex.Lock()
+			defer mutex.Unlock()
+			_, _ = io.WriteString(w, lastHook)
+			return
+		}
+
+		_, _ = io.WriteString(w, r.Method+" "+r.URL.Path)
+	}))
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+func TestProcessService(t *testing.T) {
+	t.Setenv(testServiceHelperEnv, "1")
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	yml := strings.Join([]string{
+		"name: raw",
+		"exec:",
+		fmt.Sprintf("  - %q", exe),
+		`  - "-test.run=^TestServiceHelperProcess$"`,
+		"interface: raw",
+		"service:",
+		"  triggeredBy:",
+		"    - Scene.Update.Post",
+	}, "\n")
+
+	dir := t.TempDir()
+	writeTestServicePlugin(t, dir, "raw", yml, "")
+
+	c := newTestServiceCache(t, &testServerConfig{pluginsPath: dir})
+
+	w := waitForService(t, c, "raw", "/foo")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "GET /foo", w.Body.String())
+
+	c.ExecutePostHooks(context.Background(), 1, hook.SceneUpdatePost, nil, nil)
+
+	w = waitForService(t, c, "raw", "/hook")
+	assert.Equal(t, "Scene.Update.Post 1", w.Body.String())
+
+	c.StopServices()
+
+	w = serveService(c, "raw", "/foo")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
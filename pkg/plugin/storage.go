@@ -0,0 +1,92 @@
+package plugin
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+// StorageReaderWriter persists values on behalf of plugins.
+// Keys are scoped to the plugin ID.
+type StorageReaderWriter interface {
+	Get(ctx context.Context, pluginID string, key string) (*string, error)
+	Set(ctx context.Context, pluginID string, key string, value string) error
+	Delete(ctx context.Context, pluginID string, key string) error
+	Keys(ctx context.Context, pluginID string) ([]string, error)
+}
+
+type storageProvider struct {
+	txnManager txn.Manager
+	store      StorageReaderWriter
+}
+
+// forPlugin returns a key-value store scoped to the plugin.
+func (p *storageProvider) forPlugin(pluginID string) *pluginStorage {
+	return &pluginStorage{
+		storageProvider: p,
+		pluginID:        pluginID,
+	}
+}
+
+// pluginStorage is a key-value store scoped to a single plugin.
+// Each operation is performed in its own transaction, unless the plugin is
+// run from within a transaction, such as from a Pre hook. In that case the
+// operation is part of the existing transaction.
+type pluginStorage struct {
+	*storageProvider
+	pluginID string
+}
+
+func (s *pluginStorage) withTxn(ctx context.Context, fn txn.TxnFunc) error {
+	if txn.InTxn(ctx) {
+		return fn(ctx)
+	}
+
+	return txn.WithTxn(ctx, s.txnManager, fn)
+}
+
+func (s *pluginStorage) withReadTxn(ctx context.Context, fn txn.TxnFunc) error {
+	if txn.InTxn(ctx) {
+		return fn(ctx)
+	}
+
+	return txn.WithReadTxn(ctx, s.txnManager, fn)
+}
+
+func (s *pluginStorage) Get(ctx context.Context, key string) (*string, error) {
+	var ret *string
+	if err := s.withReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		ret, err = s.store.Get(ctx, s.pluginID, key)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (s *pluginStorage) Set(ctx context.Context, key string, value string) error {
+	return s.withTxn(ctx, func(ctx context.Context) error {
+		return s.store.Set(ctx, s.pluginID, key, value)
+	})
+}
+
+func (s *pluginStorage) Delete(ctx context.Context, key string) error {
+	return s.withTxn(ctx, func(ctx context.Context) error {
+		return s.store.Delete(ctx, s.pluginID, key)
+	})
+}
+
+func (s *pluginStorage) Keys(ctx context.Context) ([]string, error) {
+	var ret []string
+	if err := s.withReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		ret, err = s.store.Keys(ctx, s.pluginID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/stashapp/stash/pkg/plugin/common"
@@ -36,16 +37,28 @@ type taskBuilder interface {
 }
 
 type pluginTask struct {
+	// ctx is the context of the operation that the task is run for. The
+	// APIs available to the plugin are bound to it.
+	ctx          context.Context
 	plugin       *Config
 	operation    *OperationConfig
 	input        common.PluginInput
 	gqlHandler   http.Handler
 	serverConfig ServerConfig
+	storage      *storageProvider
 
 	progress chan float64
 	result   *common.PluginOutput
 }
 
+func (t *pluginTask) context() context.Context {
+	if t.ctx == nil {
+		return context.Background()
+	}
+
+	return t.ctx
+}
+
 func (t *pluginTask) GetResult() *common.PluginOutput {
 	return t.result
 }
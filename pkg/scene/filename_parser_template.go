@@ -0,0 +1,208 @@
+package scene
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+// ValidateFilenameParserTemplate returns an error if the template is invalid.
+func ValidateFilenameParserTemplate(t *models.FilenameParserTemplate) error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("name must be set")
+	}
+
+	if strings.TrimSpace(t.Pattern) == "" {
+		return errors.New("pattern must be set")
+	}
+
+	compileREs()
+	if _, err := newParseMapper(t.Pattern, t.Options.IgnoreWords); err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	return nil
+}
+
+// TemplateParser parses scene filenames using a saved filename parser
+// template.
+type TemplateParser struct {
+	Template *models.FilenameParserTemplate
+	parser   *FilenameParser
+	mapper   *parseMapper
+
+	// the parser caches are not safe for concurrent use
+	mutex sync.Mutex
+}
+
+func NewTemplateParser(t *models.FilenameParserTemplate, repo FilenameParserRepository) (*TemplateParser, error) {
+	// the parser must be created first, since it initialises the regular
+	// expressions used by the mapper
+	pattern := t.Pattern
+	parser := NewFilenameParser(&models.FindFilterType{Q: &pattern}, t.Options, repo)
+
+	mapper, err := newParseMapper(t.Pattern, t.Options.IgnoreWords)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern for template %q: %w", t.Name, err)
+	}
+
+	return &TemplateParser{
+		Template: t,
+		parser:   parser,
+		mapper:   mapper,
+	}, nil
+}
+
+// AppliesTo returns true if the file path is in one of the template paths.
+func (p *TemplateParser) AppliesTo(path string) bool {
+	if len(p.Template.Paths) == 0 {
+		return true
+	}
+
+	for _, dir := range p.Template.Paths {
+		if fsutil.IsPathInDir(dir, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Skips returns true if the scene should not be changed by the template.
+func (p *TemplateParser) Skips(s *models.Scene) bool {
+	ignoreOrganized := p.Template.Options.IgnoreOrganized
+	return ignoreOrganized != nil && *ignoreOrganized && s.Organized
+}
+
+// FailuresFilter returns a filter for the scenes in the template paths with
+// a path that does not match the template pattern.
+func (p *TemplateParser) FailuresFilter() *models.SceneFilterType {
+	ret := &models.SceneFilterType{
+		Path: &models.StringCriterionInput{
+			Modifier: models.CriterionModifierNotMatchesRegex,
+			Value:    "(?i)" + p.mapper.regexString,
+		},
+	}
+
+	if len(p.Template.Paths) > 0 {
+		ret.And = FilterFromPaths(p.Template.Paths)
+	}
+
+	if ignoreOrganized := p.Template.Options.IgnoreOrganized; ignoreOrganized != nil && *ignoreOrganized {
+		organized := false
+		ret.Organized = &organized
+	}
+
+	return ret
+}
+
+// Parse parses the file path of the scene. It returns nil if the path does
+// not match the template pattern.
+func (p *TemplateParser) Parse(ctx context.Context, s *models.Scene, path string) *models.SceneParserResult {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	sc := *s
+	sc.Path = path
+
+	h := p.mapper.parse(&sc)
+	if h == nil {
+		return nil
+	}
+
+	ret := &models.SceneParserResult{
+		Scene: s,
+	}
+	p.parser.setParserResult(ctx, *h, ret)
+
+	return ret
+}
+
+// ParserResultPartial returns the changes to make to the scene from the
+// parser result. Title, date, rating and studio are only set if they are not
+// already set on the scene. Performers, tags and movies are added to the
+// existing values. It returns nil if there are no changes.
+func ParserResultPartial(s *models.Scene, r *models.SceneParserResult) (*models.ScenePartial, error) {
+	partial := models.NewScenePartial()
+	changed := false
+
+	if r.Title != nil && s.Title == "" {
+		partial.Title = models.NewOptionalString(*r.Title)
+		changed = true
+	}
+
+	if r.Date != nil && s.Date == nil {
+		d, err := models.ParseDate(*r.Date)
+		if err == nil {
+			partial.Date = models.NewOptionalDate(d)
+			changed = true
+		}
+	}
+
+	if r.Rating != nil && s.Rating == nil {
+		partial.Rating = models.NewOptionalInt(*r.Rating)
+		changed = true
+	}
+
+	if r.StudioID != nil && s.StudioID == nil {
+		studioID, err := strconv.Atoi(*r.StudioID)
+		if err != nil {
+			return nil, fmt.Errorf("converting studio id: %w", err)
+		}
+		partial.StudioID = models.NewOptionalInt(studioID)
+		changed = true
+	}
+
+	if len(r.PerformerIds) > 0 {
+		ids, err := stringslice.StringSliceToIntSlice(r.PerformerIds)
+		if err != nil {
+			return nil, fmt.Errorf("converting performer ids: %w", err)
+		}
+		partial.PerformerIDs = &models.UpdateIDs{
+			IDs:  ids,
+			Mode: models.RelationshipUpdateModeAdd,
+		}
+		changed = true
+	}
+
+	if len(r.TagIds) > 0 {
+		ids, err := stringslice.StringSliceToIntSlice(r.TagIds)
+		if err != nil {
+			return nil, fmt.Errorf("converting tag ids: %w", err)
+		}
+		partial.TagIDs = &models.UpdateIDs{
+			IDs:  ids,
+			Mode: models.RelationshipUpdateModeAdd,
+		}
+		changed = true
+	}
+
+	if len(r.Movies) > 0 {
+		var movies []models.MoviesScenes
+		for _, m := range r.Movies {
+			movieID, err := strconv.Atoi(m.MovieID)
+			if err != nil {
+				return nil, fmt.Errorf("converting movie id: %w", err)
+			}
+			movies = append(movies, models.MoviesScenes{MovieID: movieID})
+		}
+		partial.MovieIDs = &models.UpdateMovieIDs{
+			Movies: movies,
+			Mode:   models.RelationshipUpdateModeAdd,
+		}
+		changed = true
+	}
+
+	if !changed {
+		return nil, nil
+	}
+
+	return &partial, nil
+}
@@ -0,0 +1,132 @@
+package scene
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func TestValidateFilenameParserTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template models.FilenameParserTemplate
+		wantErr  bool
+	}{
+		{"valid", models.FilenameParserTemplate{Name: "name", Pattern: "{title}.{ext}"}, false},
+		{"missing name", models.FilenameParserTemplate{Pattern: "{title}.{ext}"}, true},
+		{"missing pattern", models.FilenameParserTemplate{Name: "name"}, true},
+		{"invalid field", models.FilenameParserTemplate{Name: "name", Pattern: "{invalid}.{ext}"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateFilenameParserTemplate(&tt.template); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFilenameParserTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTemplateParser(t *testing.T) {
+	whitespace := "."
+	capitalize := true
+	template := &models.FilenameParserTemplate{
+		Name:    "releases",
+		Pattern: "{yyyy}.{mm}.{dd}.{title}.{i}.{ext}",
+		Options: models.SceneParserInput{
+			IgnoreWords:          []string{"1080p"},
+			WhitespaceCharacters: &whitespace,
+			CapitalizeTitle:      &capitalize,
+		},
+		Paths: []string{"/videos/releases"},
+	}
+
+	p, err := NewTemplateParser(template, FilenameParserRepository{})
+	if err != nil {
+		t.Fatalf("NewTemplateParser() error = %v", err)
+	}
+
+	assert.True(t, p.AppliesTo("/videos/releases/sub/file.mp4"))
+	assert.False(t, p.AppliesTo("/videos/other/file.mp4"))
+
+	ctx := context.Background()
+	s := &models.Scene{ID: 1}
+
+	got := p.Parse(ctx, s, "/videos/releases/2021.02.03.some.title.1080p.mp4")
+	if assert.NotNil(t, got) {
+		assert.Equal(t, "Some Title", *got.Title)
+		assert.Equal(t, "2021-02-03", *got.Date)
+	}
+
+	assert.Nil(t, p.Parse(ctx, s, "/videos/releases/some title.mp4"))
+}
+
+func TestParserResultPartial(t *testing.T) {
+	title := "Title"
+	date := "2021-02-03"
+	studioID := "2"
+	result := &models.SceneParserResult{
+		Title:        &title,
+		Date:         &date,
+		StudioID:     &studioID,
+		PerformerIds: []string{"3"},
+	}
+
+	parseDate := func(s string) models.Date {
+		ret, _ := models.ParseDate(s)
+		return ret
+	}
+
+	existingDate := parseDate("2020-01-01")
+	existingStudio := 1
+
+	tests := []struct {
+		name  string
+		scene *models.Scene
+		want  *models.ScenePartial
+	}{
+		{
+			"empty scene",
+			&models.Scene{},
+			&models.ScenePartial{
+				Title:    models.NewOptionalString(title),
+				Date:     models.NewOptionalDate(parseDate(date)),
+				StudioID: models.NewOptionalInt(2),
+				PerformerIDs: &models.UpdateIDs{
+					IDs:  []int{3},
+					Mode: models.RelationshipUpdateModeAdd,
+				},
+			},
+		},
+		{
+			"existing values",
+			&models.Scene{Title: "Existing", Date: &existingDate, StudioID: &existingStudio},
+			&models.ScenePartial{
+				PerformerIDs: &models.UpdateIDs{
+					IDs:  []int{3},
+					Mode: models.RelationshipUpdateModeAdd,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParserResultPartial(tt.scene, result)
+			if err != nil {
+				t.Fatalf("ParserResultPartial() error = %v", err)
+			}
+
+			// ignore updated at
+			got.UpdatedAt = models.OptionalTime{}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	got, err := ParserResultPartial(&models.Scene{Title: "Existing"}, &models.SceneParserResult{Title: &title})
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
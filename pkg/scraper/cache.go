@@ -40,6 +40,8 @@ type GlobalConfig interface {
 	GetScraperCertCheck() bool
 	GetPythonPath() string
 	GetProxy() string
+	GetCachePath() string
+	GetEmbeddedMetadataMappings() models.EmbeddedMetadataMappings
 }
 
 func isCDPPathHTTP(c GlobalConfig) bool {
@@ -57,18 +59,30 @@ type SceneFinder interface {
 }
 
 type PerformerFinder interface {
+	models.PerformerGetter
 	models.PerformerAutoTagQueryer
+	models.AliasLoader
+	models.URLLoader
 	match.PerformerFinder
 }
 
+type MovieFinder interface {
+	models.MovieGetter
+	models.URLLoader
+	match.MovieNamesFinder
+}
+
 type StudioFinder interface {
+	models.StudioGetter
 	models.StudioAutoTagQueryer
+	models.AliasLoader
 	FindByStashID(ctx context.Context, stashID models.StashID) ([]*models.Studio, error)
 }
 
 type TagFinder interface {
 	models.TagGetter
 	models.TagAutoTagQueryer
+	models.AliasLoader
 }
 
 type GalleryFinder interface {
@@ -77,14 +91,21 @@ type GalleryFinder interface {
 	models.URLLoader
 }
 
+type ImageFinder interface {
+	models.ImageGetter
+	models.FileLoader
+	models.URLLoader
+}
+
 type Repository struct {
 	TxnManager models.TxnManager
 
 	SceneFinder     SceneFinder
 	GalleryFinder   GalleryFinder
+	ImageFinder     ImageFinder
 	TagFinder       TagFinder
 	PerformerFinder PerformerFinder
-	MovieFinder     match.MovieNamesFinder
+	MovieFinder     MovieFinder
 	StudioFinder    StudioFinder
 }
 
@@ -93,6 +114,7 @@ func NewRepository(repo models.Repository) Repository {
 		TxnManager:      repo.TxnManager,
 		SceneFinder:     repo.Scene,
 		GalleryFinder:   repo.Gallery,
+		ImageFinder:     repo.Image,
 		TagFinder:       repo.Tag,
 		PerformerFinder: repo.Performer,
 		MovieFinder:     repo.Movie,
@@ -109,6 +131,7 @@ type Cache struct {
 	client       *http.Client
 	scrapers     map[string]scraper // Scraper ID -> Scraper
 	globalConfig GlobalConfig
+	pluginCache  PluginCache
 
 	repository Repository
 }
@@ -139,15 +162,19 @@ func newClient(gc GlobalConfig) *http.Client {
 // Scraper configurations are loaded from yml files in the scrapers
 // directory in the config and any subdirectories.
 //
+// Scrapers provided by enabled plugins are obtained from pluginCache
+// when needed. pluginCache may be nil.
+//
 // Does not load scrapers. Scrapers will need to be
 // loaded explicitly using ReloadScrapers.
-func NewCache(globalConfig GlobalConfig, repo Repository) *Cache {
+func NewCache(globalConfig GlobalConfig, repo Repository, pluginCache PluginCache) *Cache {
 	// HTTP Client setup
 	client := newClient(globalConfig)
 
 	return &Cache{
 		client:       client,
 		globalConfig: globalConfig,
+		pluginCache:  pluginCache,
 		repository:   repo,
 	}
 }
@@ -164,6 +191,9 @@ func (c *Cache) ReloadScrapers() {
 	scrapers[freeOnes.spec().ID] = freeOnes
 	scrapers[autoTag.spec().ID] = autoTag
 
+	embeddedMetadata := getEmbeddedMetadataScraper(c.repository, c.globalConfig)
+	scrapers[embeddedMetadata.spec().ID] = embeddedMetadata
+
 	logger.Debugf("Reading scraper configs from %s", path)
 
 	err := fsutil.SymWalk(path, func(fp string, f os.FileInfo, err error) error {
@@ -186,11 +216,41 @@ func (c *Cache) ReloadScrapers() {
 	c.scrapers = scrapers
 }
 
+// pluginScrapers returns the scrapers provided by the enabled plugins.
+// Plugin scrapers with the same ID as a loaded scraper are skipped.
+func (c Cache) pluginScrapers() []scraper {
+	if c.pluginCache == nil {
+		return nil
+	}
+
+	var ret []scraper
+	for _, p := range c.pluginCache.ListScraperPlugins() {
+		if _, exists := c.scrapers[p.ID]; exists {
+			logger.Debugf("Scraper with ID %s already exists, skipping scraper provided by plugin", p.ID)
+			continue
+		}
+
+		ret = append(ret, newPluginScraper(p, c.pluginCache))
+	}
+
+	return ret
+}
+
+// allScrapers returns the loaded scrapers and the scrapers provided by plugins.
+func (c Cache) allScrapers() []scraper {
+	ret := make([]scraper, 0, len(c.scrapers))
+	for _, s := range c.scrapers {
+		ret = append(ret, s)
+	}
+
+	return append(ret, c.pluginScrapers()...)
+}
+
 // ListScrapers lists scrapers matching one of the given types.
 // Returns a list of scrapers, sorted by their name.
 func (c Cache) ListScrapers(tys []ScrapeContentType) []*Scraper {
 	var ret []*Scraper
-	for _, s := range c.scrapers {
+	for _, s := range c.allScrapers() {
 		for _, t := range tys {
 			if s.supports(t) {
 				spec := s.spec()
@@ -224,6 +284,12 @@ func (c Cache) findScraper(scraperID string) scraper {
 		return s
 	}
 
+	for _, s := range c.pluginScrapers() {
+		if s.spec().ID == scraperID {
+			return s
+		}
+	}
+
 	return nil
 }
 
@@ -233,6 +299,13 @@ func (c Cache) ScrapeName(ctx context.Context, id, query string, ty ScrapeConten
 	if s == nil {
 		return nil, fmt.Errorf("%w: id %s", ErrNotFound, id)
 	}
+
+	return c.scrapeName(ctx, s, query, ty)
+}
+
+// scrapeName scrapes query with s and post-processes the results.
+func (c Cache) scrapeName(ctx context.Context, s scraper, query string, ty ScrapeContentType) ([]ScrapedContent, error) {
+	id := s.spec().ID
 	if !s.supports(ty) {
 		return nil, fmt.Errorf("%w: cannot use scraper %s as a %v scraper", ErrNotSupported, id, ty)
 	}
@@ -284,22 +357,9 @@ func (c Cache) ScrapeFragment(ctx context.Context, id string, input Input) (Scra
 // and picks the first scraper capable of scraping the given url into the desired
 // content. Returns the scraped content or an error if the scrape fails.
 func (c Cache) ScrapeURL(ctx context.Context, url string, ty ScrapeContentType) (ScrapedContent, error) {
-	for _, s := range c.scrapers {
+	for _, s := range c.allScrapers() {
 		if s.supportsURL(url, ty) {
-			ul, ok := s.(urlScraper)
-			if !ok {
-				return nil, fmt.Errorf("%w: cannot use scraper %s as an url scraper", ErrNotSupported, s.spec().ID)
-			}
-			ret, err := ul.viaURL(ctx, c.client, url, ty)
-			if err != nil {
-				return nil, err
-			}
-
-			if ret == nil {
-				return ret, nil
-			}
-
-			return c.postScrape(ctx, ret)
+			return c.scrapeURL(ctx, s, url, ty)
 		}
 	}
 
@@ -360,6 +420,106 @@ func (c Cache) ScrapeID(ctx context.Context, scraperID string, id int, ty Scrape
 		if scraped != nil {
 			ret = scraped
 		}
+	case ScrapeContentTypeImage:
+		is, ok := s.(imageScraper)
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot use scraper %s as an image scraper", ErrNotSupported, scraperID)
+		}
+
+		image, err := c.getImage(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("scraper %s: unable to load image id %v: %w", scraperID, id, err)
+		}
+
+		// don't assign nil concrete pointer to ret interface, otherwise nil
+		// detection is harder
+		scraped, err := is.viaImage(ctx, c.client, image)
+		if err != nil {
+			return nil, fmt.Errorf("scraper %s: %w", scraperID, err)
+		}
+
+		if scraped != nil {
+			ret = scraped
+		}
+	case ScrapeContentTypeMovie:
+		// existing movies are scraped using their urls
+		ul, ok := s.(urlScraper)
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot use scraper %s as an url scraper", ErrNotSupported, scraperID)
+		}
+
+		movie, err := c.getMovie(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("scraper %s: unable to load movie id %v: %w", scraperID, id, err)
+		}
+
+		for _, url := range movie.URLs.List() {
+			if !s.supportsURL(url, ty) {
+				continue
+			}
+
+			scraped, err := ul.viaURL(ctx, c.client, url, ty)
+			if err != nil {
+				return nil, fmt.Errorf("scraper %s: %w", scraperID, err)
+			}
+
+			if scraped != nil {
+				ret = scraped
+				break
+			}
+		}
+	case ScrapeContentTypePerformer, ScrapeContentTypeStudio, ScrapeContentTypeTag:
+		// existing performers, studios and tags are scraped using their fragment
+		fs, ok := s.(fragmentScraper)
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot use scraper %s as a fragment scraper", ErrNotSupported, scraperID)
+		}
+
+		var input Input
+		switch ty {
+		case ScrapeContentTypePerformer:
+			performer, err := c.getPerformer(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("scraper %s: unable to load performer id %v: %w", scraperID, id, err)
+			}
+
+			performerInput := performerInputFromPerformer(performer)
+
+			// prefer an url that the scraper supports if falling back to
+			// scraping by url
+			for i, url := range performerInput.URLs {
+				if s.supportsURL(url, ty) {
+					performerInput.URL = &performerInput.URLs[i]
+					break
+				}
+			}
+
+			input.Performer = &performerInput
+			input.populateURL()
+		case ScrapeContentTypeStudio:
+			studio, err := c.getStudio(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("scraper %s: unable to load studio id %v: %w", scraperID, id, err)
+			}
+
+			studioInput := studioInputFromStudio(studio)
+			input.Studio = &studioInput
+		default:
+			tag, err := c.getTag(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("scraper %s: unable to load tag id %v: %w", scraperID, id, err)
+			}
+
+			tagInput := tagInputFromTag(tag)
+			input.Tag = &tagInput
+		}
+
+		scraped, err := fs.viaFragment(ctx, c.client, input)
+		if err != nil {
+			return nil, fmt.Errorf("scraper %s: %w", scraperID, err)
+		}
+
+		ret = scraped
 	}
 
 	return c.postScrape(ctx, ret)
@@ -426,3 +586,130 @@ func (c Cache) getGallery(ctx context.Context, galleryID int) (*models.Gallery,
 	}
 	return ret, nil
 }
+
+func (c Cache) getImage(ctx context.Context, imageID int) (*models.Image, error) {
+	var ret *models.Image
+	r := c.repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		qb := r.ImageFinder
+
+		var err error
+		ret, err = qb.Find(ctx, imageID)
+		if err != nil {
+			return err
+		}
+
+		if ret == nil {
+			return fmt.Errorf("image with id %d not found", imageID)
+		}
+
+		if err := ret.LoadURLs(ctx, qb); err != nil {
+			return err
+		}
+
+		if err := ret.LoadFiles(ctx, qb); err != nil {
+			return err
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (c Cache) getPerformer(ctx context.Context, performerID int) (*models.Performer, error) {
+	var ret *models.Performer
+	r := c.repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		qb := r.PerformerFinder
+
+		var err error
+		ret, err = qb.Find(ctx, performerID)
+		if err != nil {
+			return err
+		}
+
+		if ret == nil {
+			return fmt.Errorf("performer with id %d not found", performerID)
+		}
+
+		if err := ret.LoadAliases(ctx, qb); err != nil {
+			return err
+		}
+
+		return ret.LoadURLs(ctx, qb)
+	}); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (c Cache) getMovie(ctx context.Context, movieID int) (*models.Movie, error) {
+	var ret *models.Movie
+	r := c.repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		qb := r.MovieFinder
+
+		var err error
+		ret, err = qb.Find(ctx, movieID)
+		if err != nil {
+			return err
+		}
+
+		if ret == nil {
+			return fmt.Errorf("movie with id %d not found", movieID)
+		}
+
+		return ret.LoadURLs(ctx, qb)
+	}); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (c Cache) getStudio(ctx context.Context, studioID int) (*models.Studio, error) {
+	var ret *models.Studio
+	r := c.repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		qb := r.StudioFinder
+
+		var err error
+		ret, err = qb.Find(ctx, studioID)
+		if err != nil {
+			return err
+		}
+
+		if ret == nil {
+			return fmt.Errorf("studio with id %d not found", studioID)
+		}
+
+		return ret.LoadAliases(ctx, qb)
+	}); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (c Cache) getTag(ctx context.Context, tagID int) (*models.Tag, error) {
+	var ret *models.Tag
+	r := c.repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		qb := r.TagFinder
+
+		var err error
+		ret, err = qb.Find(ctx, tagID)
+		if err != nil {
+			return err
+		}
+
+		if ret == nil {
+			return fmt.Errorf("tag with id %d not found", tagID)
+		}
+
+		return ret.LoadAliases(ctx, qb)
+	}); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -48,6 +48,30 @@ type config struct {
 	// Configuration for querying a movie by a URL
 	MovieByURL []*scrapeByURLConfig `yaml:"movieByURL"`
 
+	// Configuration for querying an image by an Image fragment
+	ImageByFragment *scraperTypeConfig `yaml:"imageByFragment"`
+
+	// Configuration for querying an image by a URL
+	ImageByURL []*scrapeByURLConfig `yaml:"imageByURL"`
+
+	// Configuration for querying studios by name
+	StudioByName *scraperTypeConfig `yaml:"studioByName"`
+
+	// Configuration for querying a studio by a Studio fragment
+	StudioByFragment *scraperTypeConfig `yaml:"studioByFragment"`
+
+	// Configuration for querying a studio by a URL
+	StudioByURL []*scrapeByURLConfig `yaml:"studioByURL"`
+
+	// Configuration for querying tags by name
+	TagByName *scraperTypeConfig `yaml:"tagByName"`
+
+	// Configuration for querying a tag by a Tag fragment
+	TagByFragment *scraperTypeConfig `yaml:"tagByFragment"`
+
+	// Configuration for querying a tag by a URL
+	TagByURL []*scrapeByURLConfig `yaml:"tagByURL"`
+
 	// Scraper debugging options
 	DebugOptions *scraperDebugOptions `yaml:"debug"`
 
@@ -69,6 +93,12 @@ func (c config) validate() error {
 		return errors.New("name must not be empty")
 	}
 
+	if c.DriverOptions != nil {
+		if err := c.DriverOptions.validate(); err != nil {
+			return fmt.Errorf("driver: %w", err)
+		}
+	}
+
 	if c.PerformerByName != nil {
 		if err := c.PerformerByName.validate(); err != nil {
 			return err
@@ -105,6 +135,54 @@ func (c config) validate() error {
 		}
 	}
 
+	if c.ImageByFragment != nil {
+		if err := c.ImageByFragment.validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range c.ImageByURL {
+		if err := s.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.StudioByName != nil {
+		if err := c.StudioByName.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.StudioByFragment != nil {
+		if err := c.StudioByFragment.validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range c.StudioByURL {
+		if err := s.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.TagByName != nil {
+		if err := c.TagByName.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.TagByFragment != nil {
+		if err := c.TagByFragment.validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range c.TagByURL {
+		if err := s.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -190,6 +268,13 @@ type scraperDriverOptions struct {
 	Clicks  []*clickOptions  `yaml:"clicks"`
 	Cookies []*cookieOptions `yaml:"cookies"`
 	Headers []*header        `yaml:"headers"`
+
+	RateLimit *rateLimitOptions     `yaml:"rateLimit"`
+	Retry     *retryOptions         `yaml:"retry"`
+	Cache     *responseCacheOptions `yaml:"cache"`
+
+	// limiter is shared by all requests made by the scraper
+	limiter *requestLimiter
 }
 
 func loadConfigFromYAML(id string, reader io.Reader) (*config, error) {
@@ -208,6 +293,10 @@ func loadConfigFromYAML(id string, reader io.Reader) (*config, error) {
 		return nil, err
 	}
 
+	if ret.DriverOptions != nil {
+		ret.DriverOptions.limiter = newRequestLimiter(ret.DriverOptions.RateLimit)
+	}
+
 	return ret, nil
 }
 
@@ -289,6 +378,21 @@ func (c config) spec() Scraper {
 		ret.Gallery = &gallery
 	}
 
+	image := ScraperSpec{}
+	if c.ImageByFragment != nil {
+		image.SupportedScrapes = append(image.SupportedScrapes, ScrapeTypeFragment)
+	}
+	if len(c.ImageByURL) > 0 {
+		image.SupportedScrapes = append(image.SupportedScrapes, ScrapeTypeURL)
+		for _, v := range c.ImageByURL {
+			image.Urls = append(image.Urls, v.URL...)
+		}
+	}
+
+	if len(image.SupportedScrapes) > 0 {
+		ret.Image = &image
+	}
+
 	movie := ScraperSpec{}
 	if len(c.MovieByURL) > 0 {
 		movie.SupportedScrapes = append(movie.SupportedScrapes, ScrapeTypeURL)
@@ -301,6 +405,42 @@ func (c config) spec() Scraper {
 		ret.Movie = &movie
 	}
 
+	studio := ScraperSpec{}
+	if c.StudioByName != nil {
+		studio.SupportedScrapes = append(studio.SupportedScrapes, ScrapeTypeName)
+	}
+	if c.StudioByFragment != nil {
+		studio.SupportedScrapes = append(studio.SupportedScrapes, ScrapeTypeFragment)
+	}
+	if len(c.StudioByURL) > 0 {
+		studio.SupportedScrapes = append(studio.SupportedScrapes, ScrapeTypeURL)
+		for _, v := range c.StudioByURL {
+			studio.Urls = append(studio.Urls, v.URL...)
+		}
+	}
+
+	if len(studio.SupportedScrapes) > 0 {
+		ret.Studio = &studio
+	}
+
+	tag := ScraperSpec{}
+	if c.TagByName != nil {
+		tag.SupportedScrapes = append(tag.SupportedScrapes, ScrapeTypeName)
+	}
+	if c.TagByFragment != nil {
+		tag.SupportedScrapes = append(tag.SupportedScrapes, ScrapeTypeFragment)
+	}
+	if len(c.TagByURL) > 0 {
+		tag.SupportedScrapes = append(tag.SupportedScrapes, ScrapeTypeURL)
+		for _, v := range c.TagByURL {
+			tag.Urls = append(tag.Urls, v.URL...)
+		}
+	}
+
+	if len(tag.SupportedScrapes) > 0 {
+		ret.Tag = &tag
+	}
+
 	return ret
 }
 
@@ -312,8 +452,14 @@ func (c config) supports(ty ScrapeContentType) bool {
 		return (c.SceneByName != nil && c.SceneByQueryFragment != nil) || c.SceneByFragment != nil || len(c.SceneByURL) > 0
 	case ScrapeContentTypeGallery:
 		return c.GalleryByFragment != nil || len(c.GalleryByURL) > 0
+	case ScrapeContentTypeImage:
+		return c.ImageByFragment != nil || len(c.ImageByURL) > 0
 	case ScrapeContentTypeMovie:
 		return len(c.MovieByURL) > 0
+	case ScrapeContentTypeStudio:
+		return c.StudioByName != nil || c.StudioByFragment != nil || len(c.StudioByURL) > 0
+	case ScrapeContentTypeTag:
+		return c.TagByName != nil || c.TagByFragment != nil || len(c.TagByURL) > 0
 	}
 
 	panic("Unhandled ScrapeContentType")
@@ -339,12 +485,30 @@ func (c config) matchesURL(url string, ty ScrapeContentType) bool {
 				return true
 			}
 		}
+	case ScrapeContentTypeImage:
+		for _, scraper := range c.ImageByURL {
+			if scraper.matchesURL(url) {
+				return true
+			}
+		}
 	case ScrapeContentTypeMovie:
 		for _, scraper := range c.MovieByURL {
 			if scraper.matchesURL(url) {
 				return true
 			}
 		}
+	case ScrapeContentTypeStudio:
+		for _, scraper := range c.StudioByURL {
+			if scraper.matchesURL(url) {
+				return true
+			}
+		}
+	case ScrapeContentTypeTag:
+		for _, scraper := range c.TagByURL {
+			if scraper.matchesURL(url) {
+				return true
+			}
+		}
 	}
 
 	return false
@@ -0,0 +1,159 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+// embeddedMetadataScraperID is the scraper ID for the built-in embedded
+// metadata scraper
+const (
+	embeddedMetadataScraperID   = "builtin_embedded_metadata"
+	embeddedMetadataScraperName = "Embedded Metadata"
+)
+
+// embeddedMetadataScraper scrapes scenes and images from the metadata
+// embedded in their primary file, using the configured field mappings.
+type embeddedMetadataScraper struct {
+	txnManager  txn.Manager
+	sceneReader SceneFinder
+	imageReader ImageFinder
+
+	globalConfig GlobalConfig
+}
+
+func embeddedMetadataString(v string) *string {
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+func embeddedMetadataPerformers(names []string) []*models.ScrapedPerformer {
+	var ret []*models.ScrapedPerformer
+	for _, name := range names {
+		name := name
+		ret = append(ret, &models.ScrapedPerformer{Name: &name})
+	}
+	return ret
+}
+
+func embeddedMetadataTags(names []string) []*models.ScrapedTag {
+	var ret []*models.ScrapedTag
+	for _, name := range names {
+		ret = append(ret, &models.ScrapedTag{Name: name})
+	}
+	return ret
+}
+
+func (s embeddedMetadataScraper) values(f models.File) models.EmbeddedMetadataValues {
+	if f == nil {
+		return models.EmbeddedMetadataValues{}
+	}
+
+	return s.globalConfig.GetEmbeddedMetadataMappings().Values(f.Base().Metadata)
+}
+
+func (s embeddedMetadataScraper) viaScene(ctx context.Context, _client *http.Client, scene *models.Scene) (*ScrapedScene, error) {
+	var values models.EmbeddedMetadataValues
+
+	if err := txn.WithReadTxn(ctx, s.txnManager, func(ctx context.Context) error {
+		if err := scene.LoadFiles(ctx, s.sceneReader); err != nil {
+			return fmt.Errorf("embedded metadata scraper viaScene: %w", err)
+		}
+
+		if f := scene.Files.Primary(); f != nil {
+			values = s.values(f)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if values.IsEmpty() {
+		return nil, nil
+	}
+
+	return &ScrapedScene{
+		Title:      embeddedMetadataString(values.Title),
+		Date:       embeddedMetadataString(values.Date),
+		Details:    embeddedMetadataString(values.Details),
+		Performers: embeddedMetadataPerformers(values.Performers),
+		Tags:       embeddedMetadataTags(values.Tags),
+	}, nil
+}
+
+func (s embeddedMetadataScraper) viaImage(ctx context.Context, _client *http.Client, image *models.Image) (*ScrapedImage, error) {
+	var values models.EmbeddedMetadataValues
+
+	if err := txn.WithReadTxn(ctx, s.txnManager, func(ctx context.Context) error {
+		if err := image.LoadFiles(ctx, s.imageReader); err != nil {
+			return fmt.Errorf("embedded metadata scraper viaImage: %w", err)
+		}
+
+		if f := image.Files.Primary(); f != nil {
+			values = s.values(f)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if values.IsEmpty() {
+		return nil, nil
+	}
+
+	return &ScrapedImage{
+		Title:        embeddedMetadataString(values.Title),
+		Date:         embeddedMetadataString(values.Date),
+		Details:      embeddedMetadataString(values.Details),
+		Photographer: embeddedMetadataString(values.Photographer),
+		Performers:   embeddedMetadataPerformers(values.Performers),
+		Tags:         embeddedMetadataTags(values.Tags),
+	}, nil
+}
+
+func (s embeddedMetadataScraper) supports(ty ScrapeContentType) bool {
+	switch ty {
+	case ScrapeContentTypeScene:
+		return true
+	case ScrapeContentTypeImage:
+		return true
+	}
+
+	return false
+}
+
+func (s embeddedMetadataScraper) supportsURL(url string, ty ScrapeContentType) bool {
+	return false
+}
+
+func (s embeddedMetadataScraper) spec() Scraper {
+	supportedScrapes := []ScrapeType{
+		ScrapeTypeFragment,
+	}
+
+	return Scraper{
+		ID:   embeddedMetadataScraperID,
+		Name: embeddedMetadataScraperName,
+		Scene: &ScraperSpec{
+			SupportedScrapes: supportedScrapes,
+		},
+		Image: &ScraperSpec{
+			SupportedScrapes: supportedScrapes,
+		},
+	}
+}
+
+func getEmbeddedMetadataScraper(repo Repository, globalConfig GlobalConfig) scraper {
+	return embeddedMetadataScraper{
+		txnManager:   repo.TxnManager,
+		sceneReader:  repo.SceneFinder,
+		imageReader:  repo.ImageFinder,
+		globalConfig: globalConfig,
+	}
+}
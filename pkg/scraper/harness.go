@@ -0,0 +1,350 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v2"
+)
+
+// ScraperTestInput is the input for running a scraper test.
+type ScraperTestInput struct {
+	// ID of a loaded scraper to run. Exactly one of ScraperID and Definition
+	// must be set.
+	ScraperID *string `json:"scraper_id"`
+	// Scraper definition in YAML to run instead of a loaded scraper.
+	Definition *string           `json:"definition"`
+	Type       ScrapeContentType `json:"type"`
+	// URL to scrape. Takes precedence over Query.
+	URL *string `json:"url"`
+	// Query to scrape by name.
+	Query *string `json:"query"`
+	// Recorded fixture in YAML to use instead of live requests.
+	Fixture *string `json:"fixture"`
+	// If true, live responses are recorded and returned as a fixture.
+	Record bool `json:"record"`
+	// Expected result in YAML to compare the scraped result against.
+	Expected *string `json:"expected"`
+}
+
+// ScraperTestResult is the result of running a scraper test.
+type ScraperTestResult struct {
+	// Post-processed scraped content in YAML.
+	Result string `json:"result"`
+	// Recorded fixture in YAML. Only set if Record was true.
+	Fixture *string `json:"fixture"`
+	// Unified diff between the expected and actual result. Only set if
+	// Expected was set and the results differ.
+	Diff *string `json:"diff"`
+	// Whether the result matches the expected result. Only set if Expected was set.
+	Passed *bool `json:"passed"`
+}
+
+// Fixture is a set of recorded responses used to run a scraper offline.
+type Fixture struct {
+	Responses []FixtureResponse `yaml:"responses"`
+}
+
+// FixtureResponse is a single recorded response.
+type FixtureResponse struct {
+	URL  string `yaml:"url"`
+	Body string `yaml:"body"`
+}
+
+var ErrNoFixture = errors.New("no fixture response for url")
+
+type fixtureContextKey struct{}
+
+// fixtureRecorder replaces or records the responses loaded by scrapers.
+type fixtureRecorder struct {
+	mutex   sync.Mutex
+	fixture Fixture
+	record  bool
+}
+
+func fixturesFromContext(ctx context.Context) *fixtureRecorder {
+	ret, _ := ctx.Value(fixtureContextKey{}).(*fixtureRecorder)
+	return ret
+}
+
+func (r *fixtureRecorder) get(url string) (string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, resp := range r.fixture.Responses {
+		if resp.URL == url {
+			return resp.Body, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrNoFixture, url)
+}
+
+func (r *fixtureRecorder) add(url string, body string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, resp := range r.fixture.Responses {
+		if resp.URL == url {
+			r.fixture.Responses[i].Body = body
+			return
+		}
+	}
+
+	r.fixture.Responses = append(r.fixture.Responses, FixtureResponse{
+		URL:  url,
+		Body: body,
+	})
+}
+
+// testScraperID is the ID given to scraper definitions passed to TestScraper.
+const testScraperID = "test"
+
+// TestScraper runs a scraper by URL or name, returning the post-processed
+// result in YAML. The scraper is either a loaded scraper or a definition
+// provided in the input. If a fixture is provided, responses are loaded from
+// the fixture instead of making live requests. Scraped images are not
+// downloaded when using or recording fixtures.
+func (c Cache) TestScraper(ctx context.Context, input ScraperTestInput) (*ScraperTestResult, error) {
+	if input.Fixture != nil && input.Record {
+		return nil, errors.New("fixture and record cannot both be set")
+	}
+
+	s, err := c.testScraper(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures *fixtureRecorder
+	switch {
+	case input.Fixture != nil:
+		fixtures = &fixtureRecorder{}
+		if err := yaml.Unmarshal([]byte(*input.Fixture), &fixtures.fixture); err != nil {
+			return nil, fmt.Errorf("error parsing fixture: %w", err)
+		}
+	case input.Record:
+		fixtures = &fixtureRecorder{record: true}
+	}
+
+	if fixtures != nil {
+		if err := checkFixtureSupport(s, input); err != nil {
+			return nil, err
+		}
+
+		ctx = context.WithValue(ctx, fixtureContextKey{}, fixtures)
+	}
+
+	var result interface{}
+	switch {
+	case input.URL != nil:
+		if !s.supportsURL(*input.URL, input.Type) {
+			return nil, fmt.Errorf("%w: scraper %s cannot scrape %v from url %s", ErrNotSupported, s.spec().ID, input.Type, *input.URL)
+		}
+
+		content, err := c.scrapeURL(ctx, s, *input.URL, input.Type)
+		if err != nil {
+			return nil, err
+		}
+		result = content
+	case input.Query != nil:
+		content, err := c.scrapeName(ctx, s, *input.Query, input.Type)
+		if err != nil {
+			return nil, err
+		}
+		result = content
+	default:
+		return nil, errors.New("url or query must be set")
+	}
+
+	resultYAML, err := contentToYAML(result)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding result: %w", err)
+	}
+
+	ret := &ScraperTestResult{
+		Result: resultYAML,
+	}
+
+	if input.Record {
+		data, err := yaml.Marshal(fixtures.fixture)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding fixture: %w", err)
+		}
+		fixture := string(data)
+		ret.Fixture = &fixture
+	}
+
+	if input.Expected != nil {
+		expected, err := normalizeYAML(*input.Expected)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing expected result: %w", err)
+		}
+
+		passed := expected == resultYAML
+		ret.Passed = &passed
+
+		if !passed {
+			diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+				A:        difflib.SplitLines(expected),
+				B:        difflib.SplitLines(resultYAML),
+				FromFile: "expected",
+				ToFile:   "actual",
+				Context:  3,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error generating diff: %w", err)
+			}
+			ret.Diff = &diff
+		}
+	}
+
+	return ret, nil
+}
+
+// testScraper returns the scraper to run for input.
+func (c Cache) testScraper(input ScraperTestInput) (scraper, error) {
+	switch {
+	case input.ScraperID != nil && input.Definition != nil:
+		return nil, errors.New("scraper_id and definition cannot both be set")
+	case input.ScraperID != nil:
+		s := c.findScraper(*input.ScraperID)
+		if s == nil {
+			return nil, fmt.Errorf("%w: id %s", ErrNotFound, *input.ScraperID)
+		}
+		return s, nil
+	case input.Definition != nil:
+		conf, err := loadConfigFromYAML(testScraperID, strings.NewReader(*input.Definition))
+		if err != nil {
+			return nil, fmt.Errorf("error loading scraper definition: %w", err)
+		}
+		return newGroupScraper(*conf, c.globalConfig), nil
+	default:
+		return nil, errors.New("scraper_id or definition must be set")
+	}
+}
+
+// checkFixtureSupport returns an ErrNotSupported error if running input with
+// s would not load its content through the requests that fixtures replace.
+// Only scrapeXPath and scrapeJson scrapers are supported. Script and stash
+// scrapers, and scrapers provided by plugins, make their own requests.
+func checkFixtureSupport(s scraper, input ScraperTestInput) error {
+	g, ok := s.(group)
+	if !ok {
+		return fmt.Errorf("%w: fixtures cannot be used with scraper %s", ErrNotSupported, s.spec().ID)
+	}
+
+	var stc *scraperTypeConfig
+	switch {
+	case input.URL != nil:
+		for _, c := range loadUrlCandidates(g.config, input.Type) {
+			if c.matchesURL(*input.URL) {
+				stc = &c.scraperTypeConfig
+				break
+			}
+		}
+	case input.Query != nil:
+		switch input.Type {
+		case ScrapeContentTypePerformer:
+			stc = g.config.PerformerByName
+		case ScrapeContentTypeScene:
+			stc = g.config.SceneByName
+		case ScrapeContentTypeStudio:
+			stc = g.config.StudioByName
+		case ScrapeContentTypeTag:
+			stc = g.config.TagByName
+		}
+	}
+
+	if stc != nil && stc.Action != scraperActionXPath && stc.Action != scraperActionJson {
+		return fmt.Errorf("%w: fixtures cannot be used with %s scrapers", ErrNotSupported, stc.Action)
+	}
+
+	return nil
+}
+
+// scrapeURL scrapes url with s and post-processes the result.
+func (c Cache) scrapeURL(ctx context.Context, s scraper, url string, ty ScrapeContentType) (ScrapedContent, error) {
+	ul, ok := s.(urlScraper)
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot use scraper %s as an url scraper", ErrNotSupported, s.spec().ID)
+	}
+
+	ret, err := ul.viaURL(ctx, c.client, url, ty)
+	if err != nil {
+		return nil, err
+	}
+
+	if ret == nil {
+		return nil, nil
+	}
+
+	return c.postScrape(ctx, ret)
+}
+
+// contentToYAML encodes v as YAML using its json field names, omitting
+// empty fields so that the output is stable and readable.
+func contentToYAML(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return "", err
+	}
+
+	out, err := yaml.Marshal(pruneEmpty(generic))
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// normalizeYAML re-encodes a YAML document so that it can be compared with
+// the output of contentToYAML.
+func normalizeYAML(s string) (string, error) {
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+		return "", err
+	}
+
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+func pruneEmpty(v interface{}) interface{} {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for k, e := range vv {
+			e = pruneEmpty(e)
+			if e == nil {
+				delete(vv, k)
+			} else {
+				vv[k] = e
+			}
+		}
+		if len(vv) == 0 {
+			return nil
+		}
+	case []interface{}:
+		if len(vv) == 0 {
+			return nil
+		}
+		for i, e := range vv {
+			vv[i] = pruneEmpty(e)
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,270 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const harnessTestConfig = `name: Test
+studioByURL:
+  - action: scrapeJson
+    url:
+      - %URL%
+    scraper: studioScraper
+jsonScrapers:
+  studioScraper:
+    studio:
+      Name: data.name
+      Details: data.description
+      Image: data.image
+`
+
+const harnessTestResponse = `{"data": {"name": "Studio", "description": "Details", "image": "https://example.com/logo.png"}}`
+
+func newHarnessTestCache(t *testing.T, url string) Cache {
+	t.Helper()
+
+	c, err := loadConfigFromYAML("test", strings.NewReader(strings.ReplaceAll(harnessTestConfig, "%URL%", url)))
+	if err != nil {
+		t.Fatalf("Error loading yaml: %s", err.Error())
+	}
+
+	return Cache{
+		client:       http.DefaultClient,
+		globalConfig: mockGlobalConfig{},
+		scrapers: map[string]scraper{
+			"test": newGroupScraper(*c, mockGlobalConfig{}),
+		},
+	}
+}
+
+func TestCache_TestScraper(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(harnessTestResponse))
+	}))
+	defer ts.Close()
+
+	url := ts.URL + "/studio"
+	cache := newHarnessTestCache(t, ts.URL)
+	ctx := context.Background()
+	id := "test"
+
+	const wantResult = `details: Details
+image: https://example.com/logo.png
+images:
+- https://example.com/logo.png
+name: Studio
+`
+
+	// record a fixture from the live server
+	recorded, err := cache.TestScraper(ctx, ScraperTestInput{
+		ScraperID: &id,
+		Type:      ScrapeContentTypeStudio,
+		URL:       &url,
+		Record:    true,
+	})
+	if err != nil {
+		t.Fatalf("TestScraper() error = %v", err)
+	}
+
+	if recorded.Result != wantResult {
+		t.Errorf("TestScraper() result = %q, want %q", recorded.Result, wantResult)
+	}
+
+	if recorded.Fixture == nil || !strings.Contains(*recorded.Fixture, url) {
+		t.Fatalf("TestScraper() fixture = %v, want recorded response for %s", recorded.Fixture, url)
+	}
+
+	// replay the fixture with the server closed
+	ts.Close()
+
+	expected := "name: Studio\ndetails: Details\nimage: https://example.com/logo.png\nimages: [https://example.com/logo.png]\n"
+	replayed, err := cache.TestScraper(ctx, ScraperTestInput{
+		ScraperID: &id,
+		Type:      ScrapeContentTypeStudio,
+		URL:       &url,
+		Fixture:   recorded.Fixture,
+		Expected:  &expected,
+	})
+	if err != nil {
+		t.Fatalf("TestScraper() error = %v", err)
+	}
+
+	if replayed.Passed == nil || !*replayed.Passed {
+		t.Errorf("TestScraper() passed = %v, diff = %v", replayed.Passed, replayed.Diff)
+	}
+
+	// mismatched expectations produce a diff
+	expected = "name: Other Studio\n"
+	replayed, err = cache.TestScraper(ctx, ScraperTestInput{
+		ScraperID: &id,
+		Type:      ScrapeContentTypeStudio,
+		URL:       &url,
+		Fixture:   recorded.Fixture,
+		Expected:  &expected,
+	})
+	if err != nil {
+		t.Fatalf("TestScraper() error = %v", err)
+	}
+
+	if replayed.Passed == nil || *replayed.Passed {
+		t.Error("TestScraper() expected test to fail")
+	}
+
+	if replayed.Diff == nil || !strings.Contains(*replayed.Diff, "-name: Other Studio") {
+		t.Errorf("TestScraper() diff = %v", replayed.Diff)
+	}
+
+	// urls missing from the fixture are not fetched
+	otherURL := ts.URL + "/other"
+	if _, err := cache.TestScraper(ctx, ScraperTestInput{
+		ScraperID: &id,
+		Type:      ScrapeContentTypeStudio,
+		URL:       &otherURL,
+		Fixture:   recorded.Fixture,
+	}); err == nil {
+		t.Error("TestScraper() expected error for url missing from fixture")
+	}
+}
+
+const harnessUnsupportedConfig = `name: Unsupported
+performerByName:
+  action: stash
+studioByURL:
+  - action: script
+    url:
+      - https://example.com/studio
+    script:
+      - echo
+tagByName:
+  action: scrapeJson
+  queryURL: https://example.com/tag/{}
+  scraper: tagScraper
+jsonScrapers:
+  tagScraper:
+    tag:
+      Name: name
+`
+
+func TestCache_TestScraperFixtureNotSupported(t *testing.T) {
+	c, err := loadConfigFromYAML("unsupported", strings.NewReader(harnessUnsupportedConfig))
+	if err != nil {
+		t.Fatalf("Error loading yaml: %s", err.Error())
+	}
+
+	cache := Cache{
+		client:       http.DefaultClient,
+		globalConfig: mockGlobalConfig{},
+		scrapers: map[string]scraper{
+			"unsupported": newGroupScraper(*c, mockGlobalConfig{}),
+		},
+	}
+
+	url := "https://example.com/studio"
+	name := "name"
+	fixture := "responses: []\n"
+
+	tests := []struct {
+		name    string
+		input   ScraperTestInput
+		wantErr error
+	}{
+		{
+			"script by url",
+			ScraperTestInput{Type: ScrapeContentTypeStudio, URL: &url, Fixture: &fixture},
+			ErrNotSupported,
+		},
+		{
+			"stash by name",
+			ScraperTestInput{Type: ScrapeContentTypePerformer, Query: &name, Record: true},
+			ErrNotSupported,
+		},
+		{
+			// the fixture is used, but has no response for the url
+			"json by name",
+			ScraperTestInput{Type: ScrapeContentTypeTag, Query: &name, Fixture: &fixture},
+			ErrNoFixture,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := "unsupported"
+			tt.input.ScraperID = &id
+			_, err := cache.TestScraper(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("TestScraper() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCache_TestScraperDefinition(t *testing.T) {
+	url := "https://example.com/studio"
+	definition := strings.ReplaceAll(harnessTestConfig, "%URL%", url)
+	fixture := "responses:\n- url: " + url + "\n  body: '" + harnessTestResponse + "'\n"
+	expected := "name: Studio\ndetails: Details\nimage: https://example.com/logo.png\nimages: [https://example.com/logo.png]\n"
+	invalid := "name: Invalid\nunknownField: true\n"
+	id := "test"
+
+	// no scrapers are loaded, so the definition must be used
+	cache := Cache{
+		client:       http.DefaultClient,
+		globalConfig: mockGlobalConfig{},
+		scrapers:     map[string]scraper{},
+	}
+
+	tests := []struct {
+		name    string
+		input   ScraperTestInput
+		wantErr bool
+	}{
+		{
+			"definition",
+			ScraperTestInput{Definition: &definition},
+			false,
+		},
+		{
+			"invalid definition",
+			ScraperTestInput{Definition: &invalid},
+			true,
+		},
+		{
+			"id and definition",
+			ScraperTestInput{ScraperID: &id, Definition: &definition},
+			true,
+		},
+		{
+			"neither id nor definition",
+			ScraperTestInput{},
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.input.Type = ScrapeContentTypeStudio
+			tt.input.URL = &url
+			tt.input.Fixture = &fixture
+			tt.input.Expected = &expected
+
+			got, err := cache.TestScraper(context.Background(), tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TestScraper() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got.Passed == nil || !*got.Passed {
+				t.Errorf("TestScraper() passed = %v, diff = %v", got.Passed, got.Diff)
+			}
+		})
+	}
+}
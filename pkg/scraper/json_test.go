@@ -116,3 +116,98 @@ jsonScrapers:
 		t.Errorf("expected nil scraped performer when not found, got %v", scrapedPerformer)
 	}
 }
+
+func TestJsonStudioTagScraper(t *testing.T) {
+	const yamlStr = `name: Test
+jsonScrapers:
+  studioScraper:
+    studio:
+      Name: data.name
+      URL: data.url
+      Details: data.description
+      Aliases:
+        selector: data.aliases
+        concat: ", "
+      Parent:
+        Name: data.network
+  tagScraper:
+    tag:
+      Name: data.name
+      Description: data.description
+      Aliases: data.aliases
+      Parents:
+        Name: data.parents
+`
+
+	const studioJson = `
+{
+	"data": {
+		"name": "Studio",
+		"url": "https://example.com/studio",
+		"description": "Studio details",
+		"aliases": ["Alias 1", "Alias 2"],
+		"network": "Network"
+	}
+}
+`
+
+	const tagJson = `
+{
+	"data": {
+		"name": "Tag",
+		"description": "Tag description",
+		"aliases": ["Alias"],
+		"parents": ["Parent 1", "Parent 2"]
+	}
+}
+`
+
+	c := &config{}
+	err := yaml.Unmarshal([]byte(yamlStr), &c)
+
+	if err != nil {
+		t.Fatalf("Error loading yaml: %s", err.Error())
+	}
+
+	studioScraper := c.JsonScrapers["studioScraper"]
+	scrapedStudio, err := studioScraper.scrapeStudio(context.Background(), &jsonQuery{doc: studioJson})
+	if err != nil {
+		t.Fatalf("Error scraping studio: %s", err.Error())
+	}
+
+	if scrapedStudio == nil {
+		t.Fatal("expected scraped studio, got nil")
+	}
+
+	verifyField(t, "Studio", &scrapedStudio.Name, "Name")
+	verifyField(t, "https://example.com/studio", scrapedStudio.URL, "URL")
+	verifyField(t, "Studio details", scrapedStudio.Details, "Details")
+	verifyField(t, "Alias 1, Alias 2", scrapedStudio.Aliases, "Aliases")
+
+	if scrapedStudio.Parent == nil {
+		t.Error("expected parent studio, got nil")
+	} else {
+		verifyField(t, "Network", &scrapedStudio.Parent.Name, "Parent.Name")
+	}
+
+	tagScraper := c.JsonScrapers["tagScraper"]
+	scrapedTag, err := tagScraper.scrapeTag(context.Background(), &jsonQuery{doc: tagJson})
+	if err != nil {
+		t.Fatalf("Error scraping tag: %s", err.Error())
+	}
+
+	if scrapedTag == nil {
+		t.Fatal("expected scraped tag, got nil")
+	}
+
+	verifyField(t, "Tag", &scrapedTag.Name, "Name")
+	verifyField(t, "Tag description", scrapedTag.Description, "Description")
+	verifyField(t, "Alias", scrapedTag.Aliases, "Aliases")
+
+	if len(scrapedTag.Parents) != 2 {
+		t.Errorf("expected 2 parent tags, got %d", len(scrapedTag.Parents))
+	} else {
+		verifyField(t, "Parent 1", &scrapedTag.Parents[0].Name, "Parents[0].Name")
+		verifyField(t, "Parent 2", &scrapedTag.Parents[1].Name, "Parents[1].Name")
+	}
+}
@@ -228,6 +228,60 @@ func (s *mappedGalleryScraperConfig) UnmarshalYAML(unmarshal func(interface{}) e
 	return nil
 }
 
+type mappedImageScraperConfig struct {
+	mappedConfig
+
+	Tags       mappedConfig `yaml:"Tags"`
+	Performers mappedConfig `yaml:"Performers"`
+	Studio     mappedConfig `yaml:"Studio"`
+}
+type _mappedImageScraperConfig mappedImageScraperConfig
+
+func (s *mappedImageScraperConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	// HACK - unmarshal to map first, then remove known scene sub-fields, then
+	// remarshal to yaml and pass that down to the base map
+	parentMap := make(map[string]interface{})
+	if err := unmarshal(parentMap); err != nil {
+		return err
+	}
+
+	// move the known sub-fields to a separate map
+	thisMap := make(map[string]interface{})
+
+	thisMap[mappedScraperConfigSceneTags] = parentMap[mappedScraperConfigSceneTags]
+	thisMap[mappedScraperConfigScenePerformers] = parentMap[mappedScraperConfigScenePerformers]
+	thisMap[mappedScraperConfigSceneStudio] = parentMap[mappedScraperConfigSceneStudio]
+
+	delete(parentMap, mappedScraperConfigSceneTags)
+	delete(parentMap, mappedScraperConfigScenePerformers)
+	delete(parentMap, mappedScraperConfigSceneStudio)
+
+	// re-unmarshal the sub-fields
+	yml, err := yaml.Marshal(thisMap)
+	if err != nil {
+		return err
+	}
+
+	// needs to be a different type to prevent infinite recursion
+	c := _mappedImageScraperConfig{}
+	if err := yaml.Unmarshal(yml, &c); err != nil {
+		return err
+	}
+
+	*s = mappedImageScraperConfig(c)
+
+	yml, err = yaml.Marshal(parentMap)
+	if err != nil {
+		return err
+	}
+
+	if err := yaml.Unmarshal(yml, &s.mappedConfig); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 type mappedPerformerScraperConfig struct {
 	mappedConfig
 
@@ -336,6 +390,108 @@ func (s *mappedMovieScraperConfig) UnmarshalYAML(unmarshal func(interface{}) err
 	return nil
 }
 
+type mappedStudioScraperConfig struct {
+	mappedConfig
+
+	Parent mappedConfig `yaml:"Parent"`
+}
+type _mappedStudioScraperConfig mappedStudioScraperConfig
+
+const (
+	mappedScraperConfigStudioParent = "Parent"
+)
+
+func (s *mappedStudioScraperConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	// HACK - unmarshal to map first, then remove known studio sub-fields, then
+	// remarshal to yaml and pass that down to the base map
+	parentMap := make(map[string]interface{})
+	if err := unmarshal(parentMap); err != nil {
+		return err
+	}
+
+	// move the known sub-fields to a separate map
+	thisMap := make(map[string]interface{})
+
+	thisMap[mappedScraperConfigStudioParent] = parentMap[mappedScraperConfigStudioParent]
+	delete(parentMap, mappedScraperConfigStudioParent)
+
+	// re-unmarshal the sub-fields
+	yml, err := yaml.Marshal(thisMap)
+	if err != nil {
+		return err
+	}
+
+	// needs to be a different type to prevent infinite recursion
+	c := _mappedStudioScraperConfig{}
+	if err := yaml.Unmarshal(yml, &c); err != nil {
+		return err
+	}
+
+	*s = mappedStudioScraperConfig(c)
+
+	yml, err = yaml.Marshal(parentMap)
+	if err != nil {
+		return err
+	}
+
+	if err := yaml.Unmarshal(yml, &s.mappedConfig); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type mappedTagScraperConfig struct {
+	mappedConfig
+
+	Parents mappedConfig `yaml:"Parents"`
+}
+type _mappedTagScraperConfig mappedTagScraperConfig
+
+const (
+	mappedScraperConfigTagParents = "Parents"
+)
+
+func (s *mappedTagScraperConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	// HACK - unmarshal to map first, then remove known tag sub-fields, then
+	// remarshal to yaml and pass that down to the base map
+	parentMap := make(map[string]interface{})
+	if err := unmarshal(parentMap); err != nil {
+		return err
+	}
+
+	// move the known sub-fields to a separate map
+	thisMap := make(map[string]interface{})
+
+	thisMap[mappedScraperConfigTagParents] = parentMap[mappedScraperConfigTagParents]
+	delete(parentMap, mappedScraperConfigTagParents)
+
+	// re-unmarshal the sub-fields
+	yml, err := yaml.Marshal(thisMap)
+	if err != nil {
+		return err
+	}
+
+	// needs to be a different type to prevent infinite recursion
+	c := _mappedTagScraperConfig{}
+	if err := yaml.Unmarshal(yml, &c); err != nil {
+		return err
+	}
+
+	*s = mappedTagScraperConfig(c)
+
+	yml, err = yaml.Marshal(parentMap)
+	if err != nil {
+		return err
+	}
+
+	if err := yaml.Unmarshal(yml, &s.mappedConfig); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 type mappedRegexConfig struct {
 	Regex string `yaml:"regex"`
 	With  string `yaml:"with"`
@@ -785,8 +941,11 @@ type mappedScraper struct {
 	Common    commonMappedConfig            `yaml:"common"`
 	Scene     *mappedSceneScraperConfig     `yaml:"scene"`
 	Gallery   *mappedGalleryScraperConfig   `yaml:"gallery"`
+	Image     *mappedImageScraperConfig     `yaml:"image"`
 	Performer *mappedPerformerScraperConfig `yaml:"performer"`
 	Movie     *mappedMovieScraperConfig     `yaml:"movie"`
+	Studio    *mappedStudioScraperConfig    `yaml:"studio"`
+	Tag       *mappedTagScraperConfig       `yaml:"tag"`
 }
 
 type mappedResult map[string]string
@@ -803,12 +962,16 @@ func (r mappedResult) apply(dest interface{}) {
 
 		if field.IsValid() {
 			var reflectValue reflect.Value
-			if field.Kind() == reflect.Ptr {
+			switch field.Kind() {
+			case reflect.Ptr:
 				// need to copy the value, otherwise everything is set to the
 				// same pointer
 				localValue := value
 				reflectValue = reflect.ValueOf(&localValue)
-			} else {
+			case reflect.Slice:
+				// set slice fields such as URLs to a single element
+				reflectValue = reflect.ValueOf([]string{value})
+			default:
 				reflectValue = reflect.ValueOf(value)
 			}
 
@@ -1079,6 +1242,69 @@ func (s mappedScraper) scrapeGallery(ctx context.Context, q mappedQuery) (*Scrap
 	return &ret, nil
 }
 
+func (s mappedScraper) scrapeImage(ctx context.Context, q mappedQuery) (*ScrapedImage, error) {
+	var ret ScrapedImage
+
+	imageScraperConfig := s.Image
+	if imageScraperConfig == nil {
+		return nil, nil
+	}
+
+	imageMap := imageScraperConfig.mappedConfig
+
+	imagePerformersMap := imageScraperConfig.Performers
+	imageTagsMap := imageScraperConfig.Tags
+	imageStudioMap := imageScraperConfig.Studio
+
+	logger.Debug(`Processing image:`)
+	results := imageMap.process(ctx, q, s.Common)
+
+	// now apply the performers and tags
+	if imagePerformersMap != nil {
+		logger.Debug(`Processing image performers:`)
+		performerResults := imagePerformersMap.process(ctx, q, s.Common)
+
+		for _, p := range performerResults {
+			performer := &models.ScrapedPerformer{}
+			p.apply(performer)
+			ret.Performers = append(ret.Performers, performer)
+		}
+	}
+
+	if imageTagsMap != nil {
+		logger.Debug(`Processing image tags:`)
+		tagResults := imageTagsMap.process(ctx, q, s.Common)
+
+		for _, p := range tagResults {
+			tag := &models.ScrapedTag{}
+			p.apply(tag)
+			ret.Tags = append(ret.Tags, tag)
+		}
+	}
+
+	if imageStudioMap != nil {
+		logger.Debug(`Processing image studio:`)
+		studioResults := imageStudioMap.process(ctx, q, s.Common)
+
+		if len(studioResults) > 0 {
+			studio := &models.ScrapedStudio{}
+			studioResults[0].apply(studio)
+			ret.Studio = studio
+		}
+	}
+
+	// if no basic fields are populated, and no relationships, then return nil
+	if len(results) == 0 && len(ret.Performers) == 0 && len(ret.Tags) == 0 && ret.Studio == nil {
+		return nil, nil
+	}
+
+	if len(results) > 0 {
+		results[0].apply(&ret)
+	}
+
+	return &ret, nil
+}
+
 func (s mappedScraper) scrapeMovie(ctx context.Context, q mappedQuery) (*models.ScrapedMovie, error) {
 	var ret models.ScrapedMovie
 
@@ -1127,3 +1353,103 @@ func (s mappedScraper) scrapeMovie(ctx context.Context, q mappedQuery) (*models.
 
 	return &ret, nil
 }
+
+func (s mappedScraper) scrapeStudio(ctx context.Context, q mappedQuery) (*models.ScrapedStudio, error) {
+	studioScraperConfig := s.Studio
+	if studioScraperConfig == nil {
+		return nil, nil
+	}
+
+	logger.Debug(`Processing studio:`)
+	results := studioScraperConfig.process(ctx, q, s.Common)
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	var ret models.ScrapedStudio
+	results[0].apply(&ret)
+	s.processStudioParent(ctx, q, 0, &ret)
+
+	return &ret, nil
+}
+
+func (s mappedScraper) scrapeStudios(ctx context.Context, q mappedQuery) ([]*models.ScrapedStudio, error) {
+	var ret []*models.ScrapedStudio
+
+	studioScraperConfig := s.Studio
+	if studioScraperConfig == nil {
+		return nil, nil
+	}
+
+	logger.Debug(`Processing studios:`)
+	results := studioScraperConfig.process(ctx, q, s.Common)
+	for i, r := range results {
+		var studio models.ScrapedStudio
+		r.apply(&studio)
+		s.processStudioParent(ctx, q, i, &studio)
+		ret = append(ret, &studio)
+	}
+
+	return ret, nil
+}
+
+func (s mappedScraper) processStudioParent(ctx context.Context, q mappedQuery, resultIndex int, ret *models.ScrapedStudio) {
+	parentMap := s.Studio.Parent
+	if parentMap == nil {
+		return
+	}
+
+	logger.Debug(`Processing studio parent:`)
+	parentResults := parentMap.process(ctx, q, s.Common)
+
+	if resultIndex < len(parentResults) {
+		parent := &models.ScrapedStudio{}
+		// when doing a `search` scrape get the related parent
+		parentResults[resultIndex].apply(parent)
+		ret.Parent = parent
+	}
+}
+
+func (s mappedScraper) scrapeTag(ctx context.Context, q mappedQuery) (*models.ScrapedTag, error) {
+	tagScraperConfig := s.Tag
+	if tagScraperConfig == nil {
+		return nil, nil
+	}
+
+	logger.Debug(`Processing tag:`)
+	results := tagScraperConfig.process(ctx, q, s.Common)
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	var ret models.ScrapedTag
+	results[0].apply(&ret)
+
+	if tagScraperConfig.Parents != nil {
+		logger.Debug(`Processing tag parents:`)
+		ret.Parents = processRelationships[models.ScrapedTag](ctx, s, tagScraperConfig.Parents, q)
+	}
+
+	return &ret, nil
+}
+
+func (s mappedScraper) scrapeTags(ctx context.Context, q mappedQuery) ([]*models.ScrapedTag, error) {
+	var ret []*models.ScrapedTag
+
+	tagScraperConfig := s.Tag
+	if tagScraperConfig == nil {
+		return nil, nil
+	}
+
+	// parents are not scraped when searching, since they can't be
+	// associated with a specific result
+	logger.Debug(`Processing tags:`)
+	results := tagScraperConfig.process(ctx, q, s.Common)
+	for _, r := range results {
+		var tag models.ScrapedTag
+		r.apply(&tag)
+		ret = append(ret, &tag)
+	}
+
+	return ret, nil
+}
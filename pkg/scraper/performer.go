@@ -1,5 +1,11 @@
 package scraper
 
+import (
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
 type ScrapedPerformerInput struct {
 	// Set if performer matched
 	StoredID       *string  `json:"stored_id"`
@@ -29,3 +35,59 @@ type ScrapedPerformerInput struct {
 	Weight         *string  `json:"weight"`
 	RemoteSiteID   *string  `json:"remote_site_id"`
 }
+
+// performerInputFromPerformer returns the fragment input for an existing
+// performer. The performer's aliases and urls must be loaded.
+func performerInputFromPerformer(p *models.Performer) ScrapedPerformerInput {
+	ret := ScrapedPerformerInput{
+		Name: &p.Name,
+		URLs: p.URLs.List(),
+	}
+
+	setString := func(v string) *string {
+		if v == "" {
+			return nil
+		}
+		return &v
+	}
+
+	ret.Disambiguation = setString(p.Disambiguation)
+	ret.Ethnicity = setString(p.Ethnicity)
+	ret.Country = setString(p.Country)
+	ret.EyeColor = setString(p.EyeColor)
+	ret.HairColor = setString(p.HairColor)
+	ret.Measurements = setString(p.Measurements)
+	ret.FakeTits = setString(p.FakeTits)
+	ret.CareerLength = setString(p.CareerLength)
+	ret.Tattoos = setString(p.Tattoos)
+	ret.Piercings = setString(p.Piercings)
+	ret.Details = setString(p.Details)
+
+	if p.Gender != nil {
+		ret.Gender = setString(p.Gender.String())
+	}
+	if p.Circumcised != nil {
+		ret.Circumcised = setString(p.Circumcised.String())
+	}
+	if p.Birthdate != nil {
+		ret.Birthdate = setString(p.Birthdate.String())
+	}
+	if p.DeathDate != nil {
+		ret.DeathDate = setString(p.DeathDate.String())
+	}
+	if p.Height != nil {
+		ret.Height = setString(strconv.Itoa(*p.Height))
+	}
+	if p.Weight != nil {
+		ret.Weight = setString(strconv.Itoa(*p.Weight))
+	}
+	if p.PenisLength != nil {
+		ret.PenisLength = setString(strconv.FormatFloat(*p.PenisLength, 'f', -1, 64))
+	}
+	if aliases := p.Aliases.List(); len(aliases) > 0 {
+		joined := joinAliases(aliases)
+		ret.Aliases = &joined
+	}
+
+	return ret
+}
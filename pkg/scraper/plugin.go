@@ -0,0 +1,347 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stashapp/stash/pkg/plugin/common"
+)
+
+// PluginCache provides the scrapers implemented by plugins.
+type PluginCache interface {
+	ListScraperPlugins() []plugin.ScraperPlugin
+	RunScraper(ctx context.Context, pluginID string, operation *plugin.OperationConfig, scrapeContext common.ScrapeContext) (interface{}, error)
+}
+
+// pluginScraper is a scraper which performs its scrape operations by
+// running a plugin.
+type pluginScraper struct {
+	plugin plugin.ScraperPlugin
+	cache  PluginCache
+
+	// config is used to generate the spec and check the supported
+	// content types and URLs. Its scraper type configurations are never
+	// executed.
+	config config
+}
+
+func newPluginScraper(p plugin.ScraperPlugin, cache PluginCache) scraper {
+	toURLConfigs := func(l []*plugin.ScrapeByURLConfig) []*scrapeByURLConfig {
+		var ret []*scrapeByURLConfig
+		for _, v := range l {
+			ret = append(ret, &scrapeByURLConfig{URL: v.URL})
+		}
+		return ret
+	}
+
+	toTypeConfig := func(o *plugin.OperationConfig) *scraperTypeConfig {
+		if o == nil {
+			return nil
+		}
+		return &scraperTypeConfig{}
+	}
+
+	s := p.Scraper
+	c := config{
+		ID:                   p.ID,
+		Name:                 p.Name,
+		PerformerByName:      toTypeConfig(s.PerformerByName),
+		PerformerByFragment:  toTypeConfig(s.PerformerByFragment),
+		PerformerByURL:       toURLConfigs(s.PerformerByURL),
+		SceneByFragment:      toTypeConfig(s.SceneByFragment),
+		SceneByName:          toTypeConfig(s.SceneByName),
+		SceneByQueryFragment: toTypeConfig(s.SceneByQueryFragment),
+		SceneByURL:           toURLConfigs(s.SceneByURL),
+		GalleryByFragment:    toTypeConfig(s.GalleryByFragment),
+		GalleryByURL:         toURLConfigs(s.GalleryByURL),
+		MovieByURL:           toURLConfigs(s.MovieByURL),
+		ImageByFragment:      toTypeConfig(s.ImageByFragment),
+		ImageByURL:           toURLConfigs(s.ImageByURL),
+		StudioByName:         toTypeConfig(s.StudioByName),
+		StudioByFragment:     toTypeConfig(s.StudioByFragment),
+		StudioByURL:          toURLConfigs(s.StudioByURL),
+		TagByName:            toTypeConfig(s.TagByName),
+		TagByFragment:        toTypeConfig(s.TagByFragment),
+		TagByURL:             toURLConfigs(s.TagByURL),
+	}
+
+	return pluginScraper{
+		plugin: p,
+		cache:  cache,
+		config: c,
+	}
+}
+
+func (s pluginScraper) spec() Scraper {
+	return s.config.spec()
+}
+
+func (s pluginScraper) supports(ty ScrapeContentType) bool {
+	return s.config.supports(ty)
+}
+
+func (s pluginScraper) supportsURL(url string, ty ScrapeContentType) bool {
+	return s.config.matchesURL(url, ty)
+}
+
+// run runs the plugin scrape operation and decodes its output into out.
+func (s pluginScraper) run(ctx context.Context, operation *plugin.OperationConfig, scrapeType string, input interface{}, out interface{}) error {
+	output, err := s.cache.RunScraper(ctx, s.plugin.ID, operation, common.ScrapeContext{
+		Type:  scrapeType,
+		Input: input,
+	})
+	if err != nil {
+		return fmt.Errorf("plugin %s: %w", s.plugin.ID, err)
+	}
+
+	if output == nil {
+		return nil
+	}
+
+	// plugin output is decoded from json or javascript values, so
+	// round-trip through json to decode it into the scraped type
+	data, err := json.Marshal(output)
+	if err != nil {
+		return fmt.Errorf("plugin %s: could not marshal output: %w", s.plugin.ID, err)
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("plugin %s: could not unmarshal output: %w", s.plugin.ID, err)
+	}
+
+	return nil
+}
+
+func (s pluginScraper) scrape(ctx context.Context, operation *plugin.OperationConfig, scrapeType string, input interface{}, ty ScrapeContentType) (ScrapedContent, error) {
+	// don't assign nil concrete pointers to the returned interface
+	switch ty {
+	case ScrapeContentTypePerformer:
+		var performer *models.ScrapedPerformer
+		if err := s.run(ctx, operation, scrapeType, input, &performer); err != nil || performer == nil {
+			return nil, err
+		}
+		return performer, nil
+	case ScrapeContentTypeGallery:
+		var gallery *ScrapedGallery
+		if err := s.run(ctx, operation, scrapeType, input, &gallery); err != nil || gallery == nil {
+			return nil, err
+		}
+		return gallery, nil
+	case ScrapeContentTypeImage:
+		var image *ScrapedImage
+		if err := s.run(ctx, operation, scrapeType, input, &image); err != nil || image == nil {
+			return nil, err
+		}
+		return image, nil
+	case ScrapeContentTypeScene:
+		var scene *ScrapedScene
+		if err := s.run(ctx, operation, scrapeType, input, &scene); err != nil || scene == nil {
+			return nil, err
+		}
+		return scene, nil
+	case ScrapeContentTypeMovie:
+		var movie *models.ScrapedMovie
+		if err := s.run(ctx, operation, scrapeType, input, &movie); err != nil || movie == nil {
+			return nil, err
+		}
+		return movie, nil
+	case ScrapeContentTypeStudio:
+		var studio *models.ScrapedStudio
+		if err := s.run(ctx, operation, scrapeType, input, &studio); err != nil || studio == nil {
+			return nil, err
+		}
+		return studio, nil
+	case ScrapeContentTypeTag:
+		var tag *models.ScrapedTag
+		if err := s.run(ctx, operation, scrapeType, input, &tag); err != nil || tag == nil {
+			return nil, err
+		}
+		return tag, nil
+	}
+
+	return nil, ErrNotSupported
+}
+
+func (s pluginScraper) viaURL(ctx context.Context, client *http.Client, url string, ty ScrapeContentType) (ScrapedContent, error) {
+	var candidates []*plugin.ScrapeByURLConfig
+	var scrapeType string
+	switch ty {
+	case ScrapeContentTypePerformer:
+		candidates, scrapeType = s.plugin.Scraper.PerformerByURL, "performerByURL"
+	case ScrapeContentTypeScene:
+		candidates, scrapeType = s.plugin.Scraper.SceneByURL, "sceneByURL"
+	case ScrapeContentTypeGallery:
+		candidates, scrapeType = s.plugin.Scraper.GalleryByURL, "galleryByURL"
+	case ScrapeContentTypeImage:
+		candidates, scrapeType = s.plugin.Scraper.ImageByURL, "imageByURL"
+	case ScrapeContentTypeMovie:
+		candidates, scrapeType = s.plugin.Scraper.MovieByURL, "movieByURL"
+	case ScrapeContentTypeStudio:
+		candidates, scrapeType = s.plugin.Scraper.StudioByURL, "studioByURL"
+	case ScrapeContentTypeTag:
+		candidates, scrapeType = s.plugin.Scraper.TagByURL, "tagByURL"
+	}
+
+	input := map[string]string{"url": url}
+	for _, c := range candidates {
+		if !(scrapeByURLConfig{URL: c.URL}).matchesURL(url) {
+			continue
+		}
+
+		ret, err := s.scrape(ctx, &c.OperationConfig, scrapeType, input, ty)
+		if err != nil {
+			return nil, err
+		}
+
+		if ret != nil {
+			return ret, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (s pluginScraper) viaName(ctx context.Context, client *http.Client, name string, ty ScrapeContentType) ([]ScrapedContent, error) {
+	input := map[string]string{"name": name}
+
+	var ret []ScrapedContent
+	switch ty {
+	case ScrapeContentTypePerformer:
+		if s.plugin.Scraper.PerformerByName == nil {
+			break
+		}
+
+		var performers []models.ScrapedPerformer
+		if err := s.run(ctx, s.plugin.Scraper.PerformerByName, "performerByName", input, &performers); err != nil {
+			return nil, err
+		}
+		for _, p := range performers {
+			v := p
+			ret = append(ret, &v)
+		}
+		return ret, nil
+	case ScrapeContentTypeScene:
+		if s.plugin.Scraper.SceneByName == nil {
+			break
+		}
+
+		var scenes []ScrapedScene
+		if err := s.run(ctx, s.plugin.Scraper.SceneByName, "sceneByName", input, &scenes); err != nil {
+			return nil, err
+		}
+		for _, s := range scenes {
+			v := s
+			ret = append(ret, &v)
+		}
+		return ret, nil
+	case ScrapeContentTypeStudio:
+		if s.plugin.Scraper.StudioByName == nil {
+			break
+		}
+
+		var studios []models.ScrapedStudio
+		if err := s.run(ctx, s.plugin.Scraper.StudioByName, "studioByName", input, &studios); err != nil {
+			return nil, err
+		}
+		for _, s := range studios {
+			v := s
+			ret = append(ret, &v)
+		}
+		return ret, nil
+	case ScrapeContentTypeTag:
+		if s.plugin.Scraper.TagByName == nil {
+			break
+		}
+
+		var tags []models.ScrapedTag
+		if err := s.run(ctx, s.plugin.Scraper.TagByName, "tagByName", input, &tags); err != nil {
+			return nil, err
+		}
+		for _, t := range tags {
+			v := t
+			ret = append(ret, &v)
+		}
+		return ret, nil
+	}
+
+	return nil, fmt.Errorf("%w: cannot load %v by name", ErrNotSupported, ty)
+}
+
+func (s pluginScraper) viaFragment(ctx context.Context, client *http.Client, input Input) (ScrapedContent, error) {
+	switch {
+	case input.Performer != nil:
+		if s.plugin.Scraper.PerformerByFragment == nil {
+			// fall back to an URL scrape if there's an URL in the input
+			if input.Performer.URL != nil && *input.Performer.URL != "" {
+				return s.viaURL(ctx, client, *input.Performer.URL, ScrapeContentTypePerformer)
+			}
+			break
+		}
+		return s.scrape(ctx, s.plugin.Scraper.PerformerByFragment, "performerByFragment", *input.Performer, ScrapeContentTypePerformer)
+	case input.Gallery != nil:
+		if s.plugin.Scraper.GalleryByFragment == nil {
+			break
+		}
+		return s.scrape(ctx, s.plugin.Scraper.GalleryByFragment, "galleryByFragment", *input.Gallery, ScrapeContentTypeGallery)
+	case input.Image != nil:
+		if s.plugin.Scraper.ImageByFragment == nil {
+			break
+		}
+		return s.scrape(ctx, s.plugin.Scraper.ImageByFragment, "imageByFragment", *input.Image, ScrapeContentTypeImage)
+	case input.Scene != nil:
+		if s.plugin.Scraper.SceneByQueryFragment == nil {
+			break
+		}
+		return s.scrape(ctx, s.plugin.Scraper.SceneByQueryFragment, "sceneByQueryFragment", *input.Scene, ScrapeContentTypeScene)
+	case input.Studio != nil:
+		if s.plugin.Scraper.StudioByFragment == nil {
+			// fall back to an URL scrape if there's an URL in the input
+			if input.Studio.URL != nil && *input.Studio.URL != "" {
+				return s.viaURL(ctx, client, *input.Studio.URL, ScrapeContentTypeStudio)
+			}
+			break
+		}
+		return s.scrape(ctx, s.plugin.Scraper.StudioByFragment, "studioByFragment", *input.Studio, ScrapeContentTypeStudio)
+	case input.Tag != nil:
+		if s.plugin.Scraper.TagByFragment == nil {
+			break
+		}
+		return s.scrape(ctx, s.plugin.Scraper.TagByFragment, "tagByFragment", *input.Tag, ScrapeContentTypeTag)
+	}
+
+	return nil, ErrNotSupported
+}
+
+func (s pluginScraper) viaScene(ctx context.Context, client *http.Client, scene *models.Scene) (*ScrapedScene, error) {
+	if s.plugin.Scraper.SceneByFragment == nil {
+		return nil, ErrNotSupported
+	}
+
+	var ret *ScrapedScene
+	err := s.run(ctx, s.plugin.Scraper.SceneByFragment, "sceneByFragment", sceneInputFromScene(scene), &ret)
+	return ret, err
+}
+
+func (s pluginScraper) viaGallery(ctx context.Context, client *http.Client, gallery *models.Gallery) (*ScrapedGallery, error) {
+	if s.plugin.Scraper.GalleryByFragment == nil {
+		return nil, ErrNotSupported
+	}
+
+	var ret *ScrapedGallery
+	err := s.run(ctx, s.plugin.Scraper.GalleryByFragment, "galleryByFragment", galleryInputFromGallery(gallery), &ret)
+	return ret, err
+}
+
+func (s pluginScraper) viaImage(ctx context.Context, client *http.Client, image *models.Image) (*ScrapedImage, error) {
+	if s.plugin.Scraper.ImageByFragment == nil {
+		return nil, ErrNotSupported
+	}
+
+	var ret *ScrapedImage
+	err := s.run(ctx, s.plugin.Scraper.ImageByFragment, "imageByFragment", imageInputFromImage(image), &ret)
+	return ret, err
+}
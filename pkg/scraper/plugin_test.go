@@ -0,0 +1,99 @@
+package scraper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stashapp/stash/pkg/plugin/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPluginCache struct {
+	plugins []plugin.ScraperPlugin
+	output  interface{}
+
+	scrapeContext common.ScrapeContext
+}
+
+func (c *testPluginCache) ListScraperPlugins() []plugin.ScraperPlugin {
+	return c.plugins
+}
+
+func (c *testPluginCache) RunScraper(ctx context.Context, pluginID string, operation *plugin.OperationConfig, scrapeContext common.ScrapeContext) (interface{}, error) {
+	c.scrapeContext = scrapeContext
+	return c.output, nil
+}
+
+func TestPluginScraper(t *testing.T) {
+	const pluginID = "testPlugin"
+
+	pc := &testPluginCache{
+		plugins: []plugin.ScraperPlugin{
+			{
+				ID:   pluginID,
+				Name: "Test Plugin",
+				Scraper: plugin.ScraperConfig{
+					PerformerByName: &plugin.OperationConfig{},
+					SceneByURL: []*plugin.ScrapeByURLConfig{
+						{URL: []string{"example.com/scenes"}},
+					},
+				},
+			},
+		},
+	}
+
+	c := &Cache{
+		pluginCache: pc,
+	}
+
+	listed := c.ListScrapers([]ScrapeContentType{ScrapeContentTypePerformer, ScrapeContentTypeScene})
+	if assert.Len(t, listed, 1) {
+		assert.Equal(t, pluginID, listed[0].ID)
+		assert.Equal(t, []ScrapeType{ScrapeTypeName}, listed[0].Performer.SupportedScrapes)
+		assert.Equal(t, []string{"example.com/scenes"}, listed[0].Scene.Urls)
+		assert.Nil(t, listed[0].Gallery)
+	}
+
+	assert.Empty(t, c.ListScrapers([]ScrapeContentType{ScrapeContentTypeMovie}))
+
+	s := c.findScraper(pluginID)
+	if !assert.NotNil(t, s) {
+		return
+	}
+
+	ctx := context.Background()
+
+	pc.output = []interface{}{
+		map[string]interface{}{"name": "Performer 1"},
+		map[string]interface{}{"name": "Performer 2"},
+	}
+
+	performers, err := s.(nameScraper).viaName(ctx, nil, "Performer", ScrapeContentTypePerformer)
+	assert.Nil(t, err)
+	assert.Equal(t, "performerByName", pc.scrapeContext.Type)
+	assert.Equal(t, map[string]string{"name": "Performer"}, pc.scrapeContext.Input)
+	if assert.Len(t, performers, 2) {
+		p := performers[1].(*models.ScrapedPerformer)
+		assert.Equal(t, "Performer 2", *p.Name)
+	}
+
+	pc.output = map[string]interface{}{"title": "Scene Title"}
+
+	scene, err := s.(urlScraper).viaURL(ctx, nil, "https://example.com/scenes/1", ScrapeContentTypeScene)
+	assert.Nil(t, err)
+	assert.Equal(t, "sceneByURL", pc.scrapeContext.Type)
+	if assert.IsType(t, &ScrapedScene{}, scene) {
+		assert.Equal(t, "Scene Title", *scene.(*ScrapedScene).Title)
+	}
+
+	pc.output = nil
+
+	scene, err = s.(urlScraper).viaURL(ctx, nil, "https://example.com/scenes/2", ScrapeContentTypeScene)
+	assert.Nil(t, err)
+	assert.Nil(t, scene)
+
+	_, err = s.(nameScraper).viaName(ctx, nil, "Scene", ScrapeContentTypeScene)
+	assert.ErrorIs(t, err, ErrNotSupported)
+}
@@ -33,12 +33,30 @@ func (c Cache) postScrape(ctx context.Context, content ScrapedContent) (ScrapedC
 		}
 	case ScrapedGallery:
 		return c.postScrapeGallery(ctx, v)
+	case *ScrapedImage:
+		if v != nil {
+			return c.postScrapeImage(ctx, *v)
+		}
+	case ScrapedImage:
+		return c.postScrapeImage(ctx, v)
 	case *models.ScrapedMovie:
 		if v != nil {
 			return c.postScrapeMovie(ctx, *v)
 		}
 	case models.ScrapedMovie:
 		return c.postScrapeMovie(ctx, v)
+	case *models.ScrapedStudio:
+		if v != nil {
+			return c.postScrapeStudio(ctx, *v)
+		}
+	case models.ScrapedStudio:
+		return c.postScrapeStudio(ctx, v)
+	case *models.ScrapedTag:
+		if v != nil {
+			return c.postScrapeTag(ctx, *v)
+		}
+	case models.ScrapedTag:
+		return c.postScrapeTag(ctx, v)
 	}
 
 	// If nothing matches, pass the content through
@@ -248,6 +266,81 @@ func (c Cache) postScrapeGallery(ctx context.Context, g ScrapedGallery) (Scraped
 	return g, nil
 }
 
+func (c Cache) postScrapeImage(ctx context.Context, image ScrapedImage) (ScrapedContent, error) {
+	r := c.repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		pqb := r.PerformerFinder
+		tqb := r.TagFinder
+		sqb := r.StudioFinder
+
+		for _, p := range image.Performers {
+			err := match.ScrapedPerformer(ctx, pqb, p, nil)
+			if err != nil {
+				return err
+			}
+		}
+
+		tags, err := postProcessTags(ctx, tqb, image.Tags)
+		if err != nil {
+			return err
+		}
+		image.Tags = tags
+
+		if image.Studio != nil {
+			err := match.ScrapedStudio(ctx, sqb, image.Studio, nil)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+func (c Cache) postScrapeStudio(ctx context.Context, s models.ScrapedStudio) (ScrapedContent, error) {
+	r := c.repository
+	if s.Parent != nil {
+		if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+			return match.ScrapedStudio(ctx, r.StudioFinder, s.Parent, nil)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// post-process - set the image if applicable
+	if err := setStudioImage(ctx, c.client, &s, c.globalConfig); err != nil {
+		logger.Warnf("Could not set image using URL %s: %v", *s.Image, err)
+	}
+
+	return s, nil
+}
+
+func (c Cache) postScrapeTag(ctx context.Context, t models.ScrapedTag) (ScrapedContent, error) {
+	r := c.repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		parents, err := postProcessTags(ctx, r.TagFinder, t.Parents)
+		if err != nil {
+			return err
+		}
+		t.Parents = parents
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// post-process - set the image if applicable
+	if err := setTagImage(ctx, c.client, &t, c.globalConfig); err != nil {
+		logger.Warnf("Could not set image using URL %s: %v", *t.Image, err)
+	}
+
+	return t, nil
+}
+
 func postProcessTags(ctx context.Context, tqb models.TagQueryer, scrapedTags []*models.ScrapedTag) ([]*models.ScrapedTag, error) {
 	var ret []*models.ScrapedTag
 
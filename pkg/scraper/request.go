@@ -0,0 +1,371 @@
+package scraper
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+const (
+	// defaultRateLimitInterval is the rate limit interval used when
+	// requests is set but interval is not.
+	defaultRateLimitInterval = time.Second
+
+	// defaultRetryBackoff is the initial retry backoff used when attempts
+	// is set but backoff is not.
+	defaultRetryBackoff = time.Second
+
+	// maxRetryAfter is the maximum wait honoured from a Retry-After header.
+	maxRetryAfter = time.Minute
+
+	// responseCacheDir is the subdirectory of the cache path where scraper
+	// responses are stored.
+	responseCacheDir = "scrapers"
+)
+
+type rateLimitOptions struct {
+	// Requests is the maximum number of requests made in each interval.
+	Requests int `yaml:"requests"`
+	// Interval is the length of the interval in seconds. Defaults to 1.
+	Interval int `yaml:"interval"`
+	// Concurrency is the maximum number of requests in flight at once.
+	Concurrency int `yaml:"concurrency"`
+}
+
+func (o rateLimitOptions) validate() error {
+	if o.Requests < 0 || o.Interval < 0 || o.Concurrency < 0 {
+		return errors.New("rateLimit values must not be negative")
+	}
+
+	return nil
+}
+
+type retryOptions struct {
+	// Attempts is the number of times a request is retried after a 429 or 5xx
+	// response, a timeout or a dropped connection.
+	Attempts int `yaml:"attempts"`
+	// Backoff is the initial wait in seconds before retrying. It is doubled
+	// after each attempt. Defaults to 1.
+	Backoff int `yaml:"backoff"`
+}
+
+func (o retryOptions) validate() error {
+	if o.Attempts < 0 || o.Backoff < 0 {
+		return errors.New("retry values must not be negative")
+	}
+
+	return nil
+}
+
+func (o retryOptions) backoff(attempt int) time.Duration {
+	ret := defaultRetryBackoff
+	if o.Backoff > 0 {
+		ret = time.Duration(o.Backoff) * time.Second
+	}
+
+	return ret << attempt
+}
+
+type responseCacheOptions struct {
+	// TTL is the time in seconds that a cached response remains valid.
+	TTL int `yaml:"ttl"`
+}
+
+func (o responseCacheOptions) validate() error {
+	if o.TTL < 0 {
+		return errors.New("cache ttl must not be negative")
+	}
+
+	return nil
+}
+
+// requestLimiter throttles the requests made by a single scraper.
+// A nil requestLimiter does not limit requests.
+type requestLimiter struct {
+	sem chan struct{}
+
+	mutex    sync.Mutex
+	requests int
+	interval time.Duration
+	history  []time.Time
+}
+
+func newRequestLimiter(o *rateLimitOptions) *requestLimiter {
+	if o == nil || (o.Requests == 0 && o.Concurrency == 0) {
+		return nil
+	}
+
+	ret := &requestLimiter{
+		requests: o.Requests,
+		interval: defaultRateLimitInterval,
+	}
+
+	if o.Interval > 0 {
+		ret.interval = time.Duration(o.Interval) * time.Second
+	}
+
+	if o.Concurrency > 0 {
+		ret.sem = make(chan struct{}, o.Concurrency)
+	}
+
+	return ret
+}
+
+// acquire blocks until a request may be made. The returned function must be
+// called once the request has completed.
+func (l *requestLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	if l.sem != nil {
+		select {
+		case l.sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	release := func() {
+		if l.sem != nil {
+			<-l.sem
+		}
+	}
+
+	if err := l.waitForSlot(ctx); err != nil {
+		release()
+		return nil, err
+	}
+
+	return release, nil
+}
+
+func (l *requestLimiter) waitForSlot(ctx context.Context) error {
+	if l.requests == 0 {
+		return nil
+	}
+
+	for {
+		l.mutex.Lock()
+		now := time.Now()
+
+		// drop requests that are outside of the current interval
+		i := 0
+		for i < len(l.history) && now.Sub(l.history[i]) >= l.interval {
+			i++
+		}
+		l.history = l.history[i:]
+
+		if len(l.history) < l.requests {
+			l.history = append(l.history, now)
+			l.mutex.Unlock()
+			return nil
+		}
+
+		wait := l.interval - now.Sub(l.history[0])
+		l.mutex.Unlock()
+
+		logger.Debugf("[scraper] rate limit reached, waiting %v", wait)
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// isRetryableStatus returns true if a response with the given status code
+// should be retried.
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// isRetryableError returns true if a request that failed with err should
+// be retried. Timeouts and dropped or refused connections are retried.
+func isRetryableError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retryAfter returns the wait requested by the Retry-After header of resp,
+// limited to maxRetryAfter. Returns false if the header is not present or
+// invalid.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	var ret time.Duration
+	if secs, err := strconv.Atoi(v); err == nil {
+		ret = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		ret = time.Until(t)
+	} else {
+		return 0, false
+	}
+
+	if ret < 0 {
+		ret = 0
+	}
+	if ret > maxRetryAfter {
+		ret = maxRetryAfter
+	}
+
+	return ret, true
+}
+
+// responseCache stores scraped responses on disk.
+// A nil responseCache does not cache anything.
+type responseCache struct {
+	scraperID string
+	dir       string
+	ttl       time.Duration
+}
+
+type cachedResponse struct {
+	URL         string    `json:"url"`
+	ContentType string    `json:"content_type"`
+	Body        []byte    `json:"body"`
+	Time        time.Time `json:"time"`
+}
+
+func newResponseCache(c config, globalConfig GlobalConfig) *responseCache {
+	driverOptions := c.DriverOptions
+	if driverOptions == nil || driverOptions.Cache == nil || driverOptions.Cache.TTL == 0 {
+		return nil
+	}
+
+	cachePath := globalConfig.GetCachePath()
+	if cachePath == "" {
+		return nil
+	}
+
+	return &responseCache{
+		scraperID: c.ID,
+		dir:       filepath.Join(cachePath, responseCacheDir, c.ID),
+		ttl:       time.Duration(driverOptions.Cache.TTL) * time.Second,
+	}
+}
+
+func (c *responseCache) path(url string) string {
+	hash := sha256.Sum256([]byte(url))
+	return filepath.Join(c.dir, hex.EncodeToString(hash[:])+".json")
+}
+
+// get returns the cached response for url, or nil if there is no valid
+// cached response.
+func (c *responseCache) get(url string) *cachedResponse {
+	if c == nil {
+		return nil
+	}
+
+	fn := c.path(url)
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Warnf("[scraper] %s: error reading cached response: %v", c.scraperID, err)
+		}
+		return nil
+	}
+
+	var ret cachedResponse
+	if err := json.Unmarshal(data, &ret); err != nil {
+		logger.Warnf("[scraper] %s: error parsing cached response: %v", c.scraperID, err)
+		return nil
+	}
+
+	// guard against hash collisions
+	if ret.URL != url {
+		return nil
+	}
+
+	if time.Since(ret.Time) >= c.ttl {
+		if err := os.Remove(fn); err != nil {
+			logger.Warnf("[scraper] %s: error removing expired cached response: %v", c.scraperID, err)
+		}
+		return nil
+	}
+
+	logger.Debugf("[scraper] %s: using cached response for %s", c.scraperID, url)
+	return &ret
+}
+
+func (c *responseCache) set(url string, contentType string, body []byte) {
+	if c == nil {
+		return
+	}
+
+	data, err := json.Marshal(cachedResponse{
+		URL:         url,
+		ContentType: contentType,
+		Body:        body,
+		Time:        time.Now(),
+	})
+	if err != nil {
+		logger.Warnf("[scraper] %s: error encoding response for cache: %v", c.scraperID, err)
+		return
+	}
+
+	if err := fsutil.EnsureDirAll(c.dir); err != nil {
+		logger.Warnf("[scraper] %s: error creating response cache directory: %v", c.scraperID, err)
+		return
+	}
+
+	if err := os.WriteFile(c.path(url), data, 0644); err != nil {
+		logger.Warnf("[scraper] %s: error writing cached response: %v", c.scraperID, err)
+	}
+}
+
+func (o *scraperDriverOptions) validate() error {
+	if o.RateLimit != nil {
+		if err := o.RateLimit.validate(); err != nil {
+			return err
+		}
+	}
+
+	if o.Retry != nil {
+		if err := o.Retry.validate(); err != nil {
+			return err
+		}
+	}
+
+	if o.Cache != nil {
+		if err := o.Cache.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,238 @@
+package scraper
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type cachePathGlobalConfig struct {
+	mockGlobalConfig
+	cachePath string
+}
+
+func (c cachePathGlobalConfig) GetCachePath() string {
+	return c.cachePath
+}
+
+func TestLoadURLRetry(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	c := config{
+		ID: "test",
+		DriverOptions: &scraperDriverOptions{
+			Retry: &retryOptions{
+				Attempts: 1,
+			},
+		},
+	}
+
+	// one retry is not enough
+	if _, err := loadURL(context.Background(), ts.URL, ts.Client(), c, mockGlobalConfig{}); err == nil {
+		t.Error("expected error after exhausting retries")
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	c.DriverOptions.Retry.Attempts = 2
+
+	r, err := loadURL(context.Background(), ts.URL, ts.Client(), c, mockGlobalConfig{})
+	if err != nil {
+		t.Fatalf("loadURL() error = %v", err)
+	}
+
+	body, _ := io.ReadAll(r)
+	if string(body) != "ok" {
+		t.Errorf("loadURL() body = %q, want %q", string(body), "ok")
+	}
+
+	if calls != 3 {
+		t.Errorf("expected 3 requests, got %d", calls)
+	}
+}
+
+func TestLoadURLRetryTransportError(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 2 {
+			// drop the connection without responding
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("Hijack() error = %v", err)
+				return
+			}
+			conn.Close()
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	c := config{
+		ID: "test",
+		DriverOptions: &scraperDriverOptions{
+			Retry: &retryOptions{
+				Attempts: 1,
+			},
+		},
+	}
+
+	r, err := loadURL(context.Background(), ts.URL, ts.Client(), c, mockGlobalConfig{})
+	if err != nil {
+		t.Fatalf("loadURL() error = %v", err)
+	}
+
+	body, _ := io.ReadAll(r)
+	if string(body) != "ok" {
+		t.Errorf("loadURL() body = %q, want %q", string(body), "ok")
+	}
+
+	if calls != 2 {
+		t.Errorf("expected 2 requests, got %d", calls)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"missing", "", 0, false},
+		{"invalid", "soon", 0, false},
+		{"seconds", "30", 30 * time.Second, true},
+		{"capped", "3600", maxRetryAfter, true},
+		{"negative", "-5", 0, true},
+		{"past date", "Mon, 02 Jan 2006 15:04:05 GMT", 0, true},
+		{"future date", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), maxRetryAfter, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.header != "" {
+				resp.Header.Set("Retry-After", tt.header)
+			}
+
+			got, ok := retryAfter(resp)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("retryAfter() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLoadURLNoRetryOnClientError(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := config{
+		ID: "test",
+		DriverOptions: &scraperDriverOptions{
+			Retry: &retryOptions{
+				Attempts: 3,
+			},
+		},
+	}
+
+	if _, err := loadURL(context.Background(), ts.URL, ts.Client(), c, mockGlobalConfig{}); err == nil {
+		t.Error("expected error for 404 response")
+	}
+
+	if calls != 1 {
+		t.Errorf("expected 1 request, got %d", calls)
+	}
+}
+
+func TestLoadURLCache(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	c := config{
+		ID: "test",
+		DriverOptions: &scraperDriverOptions{
+			Cache: &responseCacheOptions{
+				TTL: 60,
+			},
+		},
+	}
+	gc := cachePathGlobalConfig{cachePath: t.TempDir()}
+
+	for i := 0; i < 2; i++ {
+		r, err := loadURL(context.Background(), ts.URL, ts.Client(), c, gc)
+		if err != nil {
+			t.Fatalf("loadURL() error = %v", err)
+		}
+
+		body, _ := io.ReadAll(r)
+		if string(body) != "ok" {
+			t.Errorf("loadURL() body = %q, want %q", string(body), "ok")
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("expected 1 request, got %d", calls)
+	}
+
+	// expired responses are fetched again
+	cache := newResponseCache(c, gc)
+	cache.ttl = 0
+	if cache.get(ts.URL) != nil {
+		t.Error("expected expired response to be ignored")
+	}
+}
+
+func TestRequestLimiter(t *testing.T) {
+	l := newRequestLimiter(&rateLimitOptions{
+		Requests: 2,
+		Interval: 1,
+	})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		release, err := l.acquire(context.Background())
+		if err != nil {
+			t.Fatalf("acquire() error = %v", err)
+		}
+		release()
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected third request to wait for the interval, took %v", elapsed)
+	}
+
+	l = newRequestLimiter(&rateLimitOptions{
+		Requests: 1,
+		Interval: 60,
+	})
+	if _, err := l.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.acquire(ctx); err == nil {
+		t.Error("expected error when context is cancelled")
+	}
+}
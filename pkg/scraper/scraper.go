@@ -30,21 +30,27 @@ type ScrapeContentType string
 
 const (
 	ScrapeContentTypeGallery   ScrapeContentType = "GALLERY"
+	ScrapeContentTypeImage     ScrapeContentType = "IMAGE"
 	ScrapeContentTypeMovie     ScrapeContentType = "MOVIE"
 	ScrapeContentTypePerformer ScrapeContentType = "PERFORMER"
 	ScrapeContentTypeScene     ScrapeContentType = "SCENE"
+	ScrapeContentTypeStudio    ScrapeContentType = "STUDIO"
+	ScrapeContentTypeTag       ScrapeContentType = "TAG"
 )
 
 var AllScrapeContentType = []ScrapeContentType{
 	ScrapeContentTypeGallery,
+	ScrapeContentTypeImage,
 	ScrapeContentTypeMovie,
 	ScrapeContentTypePerformer,
 	ScrapeContentTypeScene,
+	ScrapeContentTypeStudio,
+	ScrapeContentTypeTag,
 }
 
 func (e ScrapeContentType) IsValid() bool {
 	switch e {
-	case ScrapeContentTypeGallery, ScrapeContentTypeMovie, ScrapeContentTypePerformer, ScrapeContentTypeScene:
+	case ScrapeContentTypeGallery, ScrapeContentTypeImage, ScrapeContentTypeMovie, ScrapeContentTypePerformer, ScrapeContentTypeScene, ScrapeContentTypeStudio, ScrapeContentTypeTag:
 		return true
 	}
 	return false
@@ -80,8 +86,14 @@ type Scraper struct {
 	Scene *ScraperSpec `json:"scene"`
 	// Details for gallery scraper
 	Gallery *ScraperSpec `json:"gallery"`
+	// Details for image scraper
+	Image *ScraperSpec `json:"image"`
 	// Details for movie scraper
 	Movie *ScraperSpec `json:"movie"`
+	// Details for studio scraper
+	Studio *ScraperSpec `json:"studio"`
+	// Details for tag scraper
+	Tag *ScraperSpec `json:"tag"`
 }
 
 type ScraperSpec struct {
@@ -155,6 +167,9 @@ type Input struct {
 	Performer *ScrapedPerformerInput
 	Scene     *ScrapedSceneInput
 	Gallery   *ScrapedGalleryInput
+	Image     *ScrapedImageInput
+	Studio    *ScrapedStudioInput
+	Tag       *ScrapedTagInput
 }
 
 // populateURL populates the URL field of the input based on the
@@ -226,3 +241,11 @@ type galleryScraper interface {
 
 	viaGallery(ctx context.Context, client *http.Client, gallery *models.Gallery) (*ScrapedGallery, error)
 }
+
+// imageScraper is a scraper which supports image scrapes with
+// image data as the input.
+type imageScraper interface {
+	scraper
+
+	viaImage(ctx context.Context, client *http.Client, image *models.Image) (*ScrapedImage, error)
+}
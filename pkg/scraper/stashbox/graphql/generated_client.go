@@ -5,6 +5,7 @@ package graphql
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/Yamashou/gqlgenc/client"
 )
@@ -18,6 +19,9 @@ type StashBoxGraphQLClient interface {
 	FindPerformerByID(ctx context.Context, id string, httpRequestOptions ...client.HTTPRequestOption) (*FindPerformerByID, error)
 	FindSceneByID(ctx context.Context, id string, httpRequestOptions ...client.HTTPRequestOption) (*FindSceneByID, error)
 	FindStudio(ctx context.Context, id *string, name *string, httpRequestOptions ...client.HTTPRequestOption) (*FindStudio, error)
+	FindSceneStatus(ctx context.Context, id string, httpRequestOptions ...client.HTTPRequestOption) (*FindSceneStatus, error)
+	FindPerformerStatus(ctx context.Context, id string, httpRequestOptions ...client.HTTPRequestOption) (*FindPerformerStatus, error)
+	FindStudioStatus(ctx context.Context, id string, httpRequestOptions ...client.HTTPRequestOption) (*FindStudioStatus, error)
 	SubmitFingerprint(ctx context.Context, input FingerprintSubmission, httpRequestOptions ...client.HTTPRequestOption) (*SubmitFingerprint, error)
 	Me(ctx context.Context, httpRequestOptions ...client.HTTPRequestOption) (*Me, error)
 	SubmitSceneDraft(ctx context.Context, input SceneDraftInput, httpRequestOptions ...client.HTTPRequestOption) (*SubmitSceneDraft, error)
@@ -180,9 +184,10 @@ type PerformerAppearanceFragment struct {
 	Performer PerformerFragment "json:\"performer\" graphql:\"performer\""
 }
 type FingerprintFragment struct {
-	Algorithm FingerprintAlgorithm "json:\"algorithm\" graphql:\"algorithm\""
-	Hash      string               "json:\"hash\" graphql:\"hash\""
-	Duration  int                  "json:\"duration\" graphql:\"duration\""
+	Algorithm   FingerprintAlgorithm "json:\"algorithm\" graphql:\"algorithm\""
+	Hash        string               "json:\"hash\" graphql:\"hash\""
+	Duration    int                  "json:\"duration\" graphql:\"duration\""
+	Submissions int                  "json:\"submissions\" graphql:\"submissions\""
 }
 type SceneFragment struct {
 	ID           string                         "json:\"id\" graphql:\"id\""
@@ -223,6 +228,27 @@ type FindSceneByID struct {
 type FindStudio struct {
 	FindStudio *StudioFragment "json:\"findStudio\" graphql:\"findStudio\""
 }
+type FindSceneStatus struct {
+	FindScene *struct {
+		ID      string    "json:\"id\" graphql:\"id\""
+		Deleted bool      "json:\"deleted\" graphql:\"deleted\""
+		Updated time.Time "json:\"updated\" graphql:\"updated\""
+	} "json:\"findScene\" graphql:\"findScene\""
+}
+type FindPerformerStatus struct {
+	FindPerformer *struct {
+		ID      string    "json:\"id\" graphql:\"id\""
+		Deleted bool      "json:\"deleted\" graphql:\"deleted\""
+		Updated time.Time "json:\"updated\" graphql:\"updated\""
+	} "json:\"findPerformer\" graphql:\"findPerformer\""
+}
+type FindStudioStatus struct {
+	FindStudio *struct {
+		ID      string    "json:\"id\" graphql:\"id\""
+		Deleted bool      "json:\"deleted\" graphql:\"deleted\""
+		Updated time.Time "json:\"updated\" graphql:\"updated\""
+	} "json:\"findStudio\" graphql:\"findStudio\""
+}
 type SubmitFingerprint struct {
 	SubmitFingerprint bool "json:\"submitFingerprint\" graphql:\"submitFingerprint\""
 }
@@ -251,6 +277,7 @@ fragment FingerprintFragment on Fingerprint {
 	algorithm
 	hash
 	duration
+	submissions
 }
 fragment SceneFragment on Scene {
 	id
@@ -391,6 +418,7 @@ fragment FingerprintFragment on Fingerprint {
 	algorithm
 	hash
 	duration
+	submissions
 }
 fragment ImageFragment on Image {
 	id
@@ -545,6 +573,7 @@ fragment FingerprintFragment on Fingerprint {
 	algorithm
 	hash
 	duration
+	submissions
 }
 fragment URLFragment on URL {
 	url
@@ -696,6 +725,7 @@ fragment FingerprintFragment on Fingerprint {
 	algorithm
 	hash
 	duration
+	submissions
 }
 fragment URLFragment on URL {
 	url
@@ -971,6 +1001,7 @@ fragment FingerprintFragment on Fingerprint {
 	algorithm
 	hash
 	duration
+	submissions
 }
 fragment ImageFragment on Image {
 	id
@@ -1113,6 +1144,72 @@ func (c *Client) FindStudio(ctx context.Context, id *string, name *string, httpR
 	return &res, nil
 }
 
+const FindSceneStatusDocument = `query FindSceneStatus ($id: ID!) {
+	findScene(id: $id) {
+		id
+		deleted
+		updated
+	}
+}
+`
+
+func (c *Client) FindSceneStatus(ctx context.Context, id string, httpRequestOptions ...client.HTTPRequestOption) (*FindSceneStatus, error) {
+	vars := map[string]interface{}{
+		"id": id,
+	}
+
+	var res FindSceneStatus
+	if err := c.Client.Post(ctx, "FindSceneStatus", FindSceneStatusDocument, &res, vars, httpRequestOptions...); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+const FindPerformerStatusDocument = `query FindPerformerStatus ($id: ID!) {
+	findPerformer(id: $id) {
+		id
+		deleted
+		updated
+	}
+}
+`
+
+func (c *Client) FindPerformerStatus(ctx context.Context, id string, httpRequestOptions ...client.HTTPRequestOption) (*FindPerformerStatus, error) {
+	vars := map[string]interface{}{
+		"id": id,
+	}
+
+	var res FindPerformerStatus
+	if err := c.Client.Post(ctx, "FindPerformerStatus", FindPerformerStatusDocument, &res, vars, httpRequestOptions...); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+const FindStudioStatusDocument = `query FindStudioStatus ($id: ID!) {
+	findStudio(id: $id) {
+		id
+		deleted
+		updated
+	}
+}
+`
+
+func (c *Client) FindStudioStatus(ctx context.Context, id string, httpRequestOptions ...client.HTTPRequestOption) (*FindStudioStatus, error) {
+	vars := map[string]interface{}{
+		"id": id,
+	}
+
+	var res FindStudioStatus
+	if err := c.Client.Post(ctx, "FindStudioStatus", FindStudioStatusDocument, &res, vars, httpRequestOptions...); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
 const SubmitFingerprintDocument = `mutation SubmitFingerprint ($input: FingerprintSubmission!) {
 	submitFingerprint(input: $input)
 }
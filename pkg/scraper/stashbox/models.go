@@ -1,6 +1,10 @@
 package stashbox
 
-import "github.com/stashapp/stash/pkg/models"
+import (
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
 
 type StashBoxStudioQueryResult struct {
 	Query   string                  `json:"query"`
@@ -11,3 +15,21 @@ type StashBoxPerformerQueryResult struct {
 	Query   string                     `json:"query"`
 	Results []*models.ScrapedPerformer `json:"results"`
 }
+
+// FingerprintSubmission is a scene fingerprint to submit to stash-box.
+type FingerprintSubmission struct {
+	// StashID is the stash-box ID of the scene
+	StashID   string `json:"stash_id"`
+	Algorithm string `json:"algorithm"`
+	Hash      string `json:"hash"`
+	Duration  int    `json:"duration"`
+}
+
+// UpstreamStatus is the state of a stash-box scene, performer or studio.
+type UpstreamStatus struct {
+	// ID is the current stash-box ID of the object. It differs from the
+	// requested ID if the requested object was merged into another.
+	ID      string    `json:"id"`
+	Deleted bool      `json:"deleted"`
+	Updated time.Time `json:"updated"`
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -257,102 +258,130 @@ func (c Client) SubmitStashBoxFingerprints(ctx context.Context, sceneIDs []strin
 		return false, err
 	}
 
+	var fingerprints []FingerprintSubmission
+	for _, sceneID := range ids {
+		sceneFingerprints, err := c.GetSceneFingerprints(ctx, sceneID)
+		if err != nil {
+			return false, err
+		}
+
+		fingerprints = append(fingerprints, sceneFingerprints...)
+	}
+
+	for _, fingerprint := range fingerprints {
+		if err := c.SubmitFingerprint(ctx, fingerprint); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// GetSceneFingerprints returns the fingerprints of the scene files to submit
+// to stash-box. Scenes without a stash ID for the endpoint have no fingerprints
+// to submit.
+func (c Client) GetSceneFingerprints(ctx context.Context, sceneID int) ([]FingerprintSubmission, error) {
 	endpoint := c.box.Endpoint
 
-	var fingerprints []graphql.FingerprintSubmission
+	var ret []FingerprintSubmission
 
 	r := c.repository
 	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
 		qb := r.Scene
 
-		for _, sceneID := range ids {
-			scene, err := qb.Find(ctx, sceneID)
-			if err != nil {
-				return err
+		scene, err := qb.Find(ctx, sceneID)
+		if err != nil {
+			return err
+		}
+
+		if scene == nil {
+			return nil
+		}
+
+		if err := scene.LoadStashIDs(ctx, qb); err != nil {
+			return err
+		}
+
+		if err := scene.LoadFiles(ctx, qb); err != nil {
+			return err
+		}
+
+		sceneStashID := ""
+		for _, stashID := range scene.StashIDs.List() {
+			if stashID.Endpoint == endpoint {
+				sceneStashID = stashID.StashID
 			}
+		}
 
-			if scene == nil {
+		if sceneStashID == "" {
+			return nil
+		}
+
+		for _, f := range scene.Files.List() {
+			duration := int(f.Duration)
+			if duration == 0 {
 				continue
 			}
 
-			if err := scene.LoadStashIDs(ctx, qb); err != nil {
-				return err
+			add := func(algorithm graphql.FingerprintAlgorithm, hash string) {
+				ret = append(ret, FingerprintSubmission{
+					StashID:   sceneStashID,
+					Algorithm: algorithm.String(),
+					Hash:      hash,
+					Duration:  duration,
+				})
 			}
 
-			if err := scene.LoadFiles(ctx, qb); err != nil {
-				return err
+			if checksum := f.Fingerprints.GetString(models.FingerprintTypeMD5); checksum != "" {
+				add(graphql.FingerprintAlgorithmMd5, checksum)
 			}
 
-			stashIDs := scene.StashIDs.List()
-			sceneStashID := ""
-			for _, stashID := range stashIDs {
-				if stashID.Endpoint == endpoint {
-					sceneStashID = stashID.StashID
-				}
+			if oshash := f.Fingerprints.GetString(models.FingerprintTypeOshash); oshash != "" {
+				add(graphql.FingerprintAlgorithmOshash, oshash)
 			}
 
-			if sceneStashID != "" {
-				for _, f := range scene.Files.List() {
-					duration := f.Duration
-
-					if duration != 0 {
-						if checksum := f.Fingerprints.GetString(models.FingerprintTypeMD5); checksum != "" {
-							fingerprint := graphql.FingerprintInput{
-								Hash:      checksum,
-								Algorithm: graphql.FingerprintAlgorithmMd5,
-								Duration:  int(duration),
-							}
-							fingerprints = append(fingerprints, graphql.FingerprintSubmission{
-								SceneID:     sceneStashID,
-								Fingerprint: &fingerprint,
-							})
-						}
-
-						if oshash := f.Fingerprints.GetString(models.FingerprintTypeOshash); oshash != "" {
-							fingerprint := graphql.FingerprintInput{
-								Hash:      oshash,
-								Algorithm: graphql.FingerprintAlgorithmOshash,
-								Duration:  int(duration),
-							}
-							fingerprints = append(fingerprints, graphql.FingerprintSubmission{
-								SceneID:     sceneStashID,
-								Fingerprint: &fingerprint,
-							})
-						}
-
-						if phash := f.Fingerprints.GetInt64(models.FingerprintTypePhash); phash != 0 {
-							fingerprint := graphql.FingerprintInput{
-								Hash:      utils.PhashToString(phash),
-								Algorithm: graphql.FingerprintAlgorithmPhash,
-								Duration:  int(duration),
-							}
-							fingerprints = append(fingerprints, graphql.FingerprintSubmission{
-								SceneID:     sceneStashID,
-								Fingerprint: &fingerprint,
-							})
-						}
-					}
-				}
+			if phash := f.Fingerprints.GetInt64(models.FingerprintTypePhash); phash != 0 {
+				add(graphql.FingerprintAlgorithmPhash, utils.PhashToString(phash))
 			}
 		}
 
 		return nil
 	}); err != nil {
-		return false, err
+		return nil, err
 	}
 
-	return c.submitStashBoxFingerprints(ctx, fingerprints)
+	return ret, nil
 }
 
-func (c Client) submitStashBoxFingerprints(ctx context.Context, fingerprints []graphql.FingerprintSubmission) (bool, error) {
-	for _, fingerprint := range fingerprints {
-		_, err := c.client.SubmitFingerprint(ctx, fingerprint)
-		if err != nil {
-			return false, err
-		}
+// SubmitFingerprint submits a single scene fingerprint to stash-box.
+func (c Client) SubmitFingerprint(ctx context.Context, fingerprint FingerprintSubmission) error {
+	_, err := c.client.SubmitFingerprint(ctx, graphql.FingerprintSubmission{
+		SceneID: fingerprint.StashID,
+		Fingerprint: &graphql.FingerprintInput{
+			Hash:      fingerprint.Hash,
+			Algorithm: graphql.FingerprintAlgorithm(fingerprint.Algorithm),
+			Duration:  fingerprint.Duration,
+		},
+	})
+	return err
+}
+
+// IsRetryableError returns true if err was caused by stash-box being
+// unreachable or temporarily unavailable, rather than by stash-box rejecting
+// the request.
+func IsRetryableError(err error) bool {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
 	}
 
-	return true, nil
+	var respErr *client.ErrorResponse
+	if errors.As(err, &respErr) && respErr.NetworkError != nil {
+		code := respErr.NetworkError.Code
+		return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+	}
+
+	return false
 }
 
 // QueryStashBoxPerformer queries stash-box for performers using a query string.
@@ -725,9 +754,10 @@ func getFingerprints(scene *graphql.SceneFragment) []*models.StashBoxFingerprint
 	fingerprints := []*models.StashBoxFingerprint{}
 	for _, fp := range scene.Fingerprints {
 		fingerprint := models.StashBoxFingerprint{
-			Algorithm: fp.Algorithm.String(),
-			Hash:      fp.Hash,
-			Duration:  fp.Duration,
+			Algorithm:   fp.Algorithm.String(),
+			Hash:        fp.Hash,
+			Duration:    fp.Duration,
+			Submissions: fp.Submissions,
 		}
 		fingerprints = append(fingerprints, &fingerprint)
 	}
@@ -835,6 +865,21 @@ func (c Client) sceneFragmentToScrapedScene(ctx context.Context, s *graphql.Scen
 	return ss, nil
 }
 
+// FindStashBoxSceneByID returns the stash-box scene with the given stash ID,
+// or nil if it is not found.
+func (c Client) FindStashBoxSceneByID(ctx context.Context, id string) (*scraper.ScrapedScene, error) {
+	scene, err := c.client.FindSceneByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if scene.FindScene == nil {
+		return nil, nil
+	}
+
+	return c.sceneFragmentToScrapedScene(ctx, scene.FindScene)
+}
+
 func (c Client) FindStashBoxPerformerByID(ctx context.Context, id string) (*models.ScrapedPerformer, error) {
 	performer, err := c.client.FindPerformerByID(ctx, id)
 	if err != nil {
@@ -937,6 +982,63 @@ func (c Client) FindStashBoxStudio(ctx context.Context, query string) (*models.S
 	return ret, nil
 }
 
+// GetSceneStatus returns the status of the stash-box scene with the given
+// ID, or nil if it is not found.
+func (c Client) GetSceneStatus(ctx context.Context, id string) (*UpstreamStatus, error) {
+	res, err := c.client.FindSceneStatus(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.FindScene == nil {
+		return nil, nil
+	}
+
+	return &UpstreamStatus{
+		ID:      res.FindScene.ID,
+		Deleted: res.FindScene.Deleted,
+		Updated: res.FindScene.Updated,
+	}, nil
+}
+
+// GetPerformerStatus returns the status of the stash-box performer with the
+// given ID, or nil if it is not found.
+func (c Client) GetPerformerStatus(ctx context.Context, id string) (*UpstreamStatus, error) {
+	res, err := c.client.FindPerformerStatus(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.FindPerformer == nil {
+		return nil, nil
+	}
+
+	return &UpstreamStatus{
+		ID:      res.FindPerformer.ID,
+		Deleted: res.FindPerformer.Deleted,
+		Updated: res.FindPerformer.Updated,
+	}, nil
+}
+
+// GetStudioStatus returns the status of the stash-box studio with the given
+// ID, or nil if it is not found.
+func (c Client) GetStudioStatus(ctx context.Context, id string) (*UpstreamStatus, error) {
+	res, err := c.client.FindStudioStatus(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.FindStudio == nil {
+		return nil, nil
+	}
+
+	return &UpstreamStatus{
+		ID:      res.FindStudio.ID,
+		Deleted: res.FindStudio.Deleted,
+		Updated: res.FindStudio.Updated,
+	}, nil
+}
+
 func (c Client) GetUser(ctx context.Context) (*graphql.Me, error) {
 	return c.client.Me(ctx)
 }
@@ -0,0 +1,31 @@
+package scraper
+
+import "github.com/stashapp/stash/pkg/models"
+
+type ScrapedStudioInput struct {
+	Name    *string `json:"name"`
+	URL     *string `json:"url"`
+	Details *string `json:"details"`
+	Aliases *string `json:"aliases"`
+}
+
+// studioInputFromStudio returns the fragment input for an existing studio.
+// The studio's aliases must be loaded.
+func studioInputFromStudio(studio *models.Studio) ScrapedStudioInput {
+	ret := ScrapedStudioInput{
+		Name: &studio.Name,
+	}
+
+	if studio.URL != "" {
+		ret.URL = &studio.URL
+	}
+	if studio.Details != "" {
+		ret.Details = &studio.Details
+	}
+	if aliases := studio.Aliases.List(); len(aliases) > 0 {
+		joined := joinAliases(aliases)
+		ret.Aliases = &joined
+	}
+
+	return ret
+}
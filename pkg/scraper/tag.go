@@ -0,0 +1,37 @@
+package scraper
+
+import (
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type ScrapedTagInput struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Aliases     *string `json:"aliases"`
+}
+
+// tagInputFromTag returns the fragment input for an existing tag.
+// The tag's aliases must be loaded.
+func tagInputFromTag(tag *models.Tag) ScrapedTagInput {
+	ret := ScrapedTagInput{
+		Name: &tag.Name,
+	}
+
+	if tag.Description != "" {
+		ret.Description = &tag.Description
+	}
+	if aliases := tag.Aliases.List(); len(aliases) > 0 {
+		joined := joinAliases(aliases)
+		ret.Aliases = &joined
+	}
+
+	return ret
+}
+
+// joinAliases joins aliases into the comma-separated form used by scraped
+// content.
+func joinAliases(aliases []string) string {
+	return strings.Join(aliases, ", ")
+}
@@ -26,10 +26,66 @@ import (
 const scrapeDefaultSleep = time.Second * 2
 
 func loadURL(ctx context.Context, loadURL string, client *http.Client, scraperConfig config, globalConfig GlobalConfig) (io.Reader, error) {
+	fixtures := fixturesFromContext(ctx)
+	if fixtures == nil {
+		return fetchURL(ctx, loadURL, client, scraperConfig, globalConfig)
+	}
+
+	if !fixtures.record {
+		logger.Debugf("[scraper] using fixture response for %s", loadURL)
+		body, err := fixtures.get(loadURL)
+		if err != nil {
+			return nil, err
+		}
+		return strings.NewReader(body), nil
+	}
+
+	r, err := fetchURL(ctx, loadURL, client, scraperConfig, globalConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	fixtures.add(loadURL, string(body))
+	return bytes.NewReader(body), nil
+}
+
+func fetchURL(ctx context.Context, loadURL string, client *http.Client, scraperConfig config, globalConfig GlobalConfig) (io.Reader, error) {
+	cache := newResponseCache(scraperConfig, globalConfig)
+	if cached := cache.get(loadURL); cached != nil {
+		return charset.NewReader(bytes.NewReader(cached.Body), cached.ContentType)
+	}
+
+	var limiter *requestLimiter
 	driverOptions := scraperConfig.DriverOptions
+	if driverOptions != nil {
+		limiter = driverOptions.limiter
+	}
+
 	if driverOptions != nil && driverOptions.UseCDP {
+		release, err := limiter.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
 		// get the page using chrome dp
-		return urlFromCDP(ctx, loadURL, *driverOptions, globalConfig)
+		r, err := urlFromCDP(ctx, loadURL, *driverOptions, globalConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+
+		cache.set(loadURL, "text/html; charset=utf-8", body)
+		return bytes.NewReader(body), nil
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, loadURL, nil)
@@ -58,6 +114,7 @@ func loadURL(ctx context.Context, loadURL string, client *http.Client, scraperCo
 		req.Header.Set("User-Agent", userAgent)
 	}
 
+	var retry retryOptions
 	if driverOptions != nil { // setting the Headers after the UA allows us to override it from inside the scraper
 		for _, h := range driverOptions.Headers {
 			if h.Key != "" {
@@ -65,26 +122,74 @@ func loadURL(ctx context.Context, loadURL string, client *http.Client, scraperCo
 				logger.Debugf("[scraper] adding header <%s:%s>", h.Key, h.Value)
 			}
 		}
+
+		if driverOptions.Retry != nil {
+			retry = *driverOptions.Retry
+		}
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
+	for attempt := 0; ; attempt++ {
+		resp, body, err := doRequest(ctx, client, req, limiter)
+		if err != nil {
+			if ctx.Err() != nil || attempt >= retry.Attempts || !isRetryableError(err) {
+				return nil, err
+			}
+
+			wait := retry.backoff(attempt)
+			logger.Debugf("[scraper] error loading %s: %v, retrying in %v (attempt %d of %d)", loadURL, err, wait, attempt+1, retry.Attempts)
+			if err := sleepContext(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if resp.StatusCode >= 400 {
+			if attempt >= retry.Attempts || !isRetryableStatus(resp.StatusCode) {
+				return nil, fmt.Errorf("http error %d:%s", resp.StatusCode, http.StatusText(resp.StatusCode))
+			}
+
+			// prefer the wait requested by the server
+			wait, ok := retryAfter(resp)
+			if !ok {
+				wait = retry.backoff(attempt)
+			}
+
+			logger.Debugf("[scraper] http error %d loading %s, retrying in %v (attempt %d of %d)", resp.StatusCode, loadURL, wait, attempt+1, retry.Attempts)
+			if err := sleepContext(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		contentType := resp.Header.Get("Content-Type")
+		cache.set(loadURL, contentType, body)
+
+		printCookies(jar, scraperConfig, "Jar cookies found for scraper urls")
+		return charset.NewReader(bytes.NewReader(body), contentType)
 	}
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("http error %d:%s", resp.StatusCode, http.StatusText(resp.StatusCode))
+}
+
+// doRequest performs req once the limiter allows it, returning the response
+// and its body.
+func doRequest(ctx context.Context, client *http.Client, req *http.Request, limiter *requestLimiter) (*http.Response, []byte, error) {
+	release, err := limiter.acquire(ctx)
+	if err != nil {
+		return nil, nil, err
 	}
+	defer release()
 
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	bodyReader := bytes.NewReader(body)
-	printCookies(jar, scraperConfig, "Jar cookies found for scraper urls")
-	return charset.NewReader(bodyReader, resp.Header.Get("Content-Type"))
+	return resp, body, nil
 }
 
 // func urlFromCDP uses chrome cdp and DOM to load and process the url
@@ -838,6 +838,14 @@ func (mockGlobalConfig) GetProxy() string {
 	return ""
 }
 
+func (mockGlobalConfig) GetCachePath() string {
+	return ""
+}
+
+func (mockGlobalConfig) GetEmbeddedMetadataMappings() models.EmbeddedMetadataMappings {
+	return models.DefaultEmbeddedMetadataMappings()
+}
+
 func TestSubScrape(t *testing.T) {
 	retHTML := `
 	<div>
@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/securecookie"
 	"github.com/gorilla/sessions"
 	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/plugin/hook"
 )
 
@@ -33,6 +34,15 @@ func (s *Store) VisitedPluginHandler() func(http.Handler) http.Handler {
 				visitedPlugins, _ := val.([]VisitedPluginHook)
 
 				ctx := setVisitedPluginHooks(r.Context(), visitedPlugins)
+
+				// attribute changes made by the plugin to it
+				if pluginID, ok := session.Values[pluginIDKey].(string); ok && pluginID != "" {
+					ctx = models.WithChangeOrigin(ctx, models.ChangeOrigin{
+						Type: models.ChangeOriginTypePlugin,
+						ID:   &pluginID,
+					})
+				}
+
 				r = r.WithContext(ctx)
 			}
 
@@ -71,6 +81,10 @@ func (s *Store) MakePluginCookie(ctx context.Context) *http.Cookie {
 
 	session.Values[visitedPluginHooksKey] = visitedPlugins
 
+	if origin := models.ChangeOriginFromContext(ctx); origin.Type == models.ChangeOriginTypePlugin && origin.ID != nil {
+		session.Values[pluginIDKey] = *origin.ID
+	}
+
 	encoded, err := securecookie.EncodeMulti(session.Name(), session.Values,
 		s.sessionStore.Codecs...)
 	if err != nil {
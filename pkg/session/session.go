@@ -19,6 +19,7 @@ const (
 const (
 	userIDKey             = "userID"
 	visitedPluginHooksKey = "visitedPluginsHooks"
+	pluginIDKey           = "pluginID"
 )
 
 const (
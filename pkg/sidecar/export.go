@@ -0,0 +1,54 @@
+package sidecar
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/performer"
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+type PerformerFinder interface {
+	FindBySceneID(ctx context.Context, sceneID int) ([]*models.Performer, error)
+}
+
+// SceneMetadata returns the sidecar metadata of the provided scene.
+func SceneMetadata(ctx context.Context, s *models.Scene, urlLoader models.URLLoader, studioReader models.StudioGetter, performerReader PerformerFinder, tagReader scene.TagFinder) (*Metadata, error) {
+	if err := s.LoadURLs(ctx, urlLoader); err != nil {
+		return nil, fmt.Errorf("loading scene urls: %w", err)
+	}
+
+	ret := &Metadata{
+		Title:     s.Title,
+		Code:      s.Code,
+		Details:   s.Details,
+		Director:  s.Director,
+		URLs:      s.URLs.List(),
+		Rating:    s.Rating,
+		Organized: &s.Organized,
+	}
+
+	if s.Date != nil {
+		ret.Date = s.Date.String()
+	}
+
+	var err error
+	ret.Studio, err = scene.GetStudioName(ctx, studioReader, s)
+	if err != nil {
+		return nil, fmt.Errorf("getting scene studio: %w", err)
+	}
+
+	performers, err := performerReader.FindBySceneID(ctx, s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting scene performers: %w", err)
+	}
+	ret.Performers = performer.GetNames(performers)
+
+	ret.Tags, err = scene.GetTagNames(ctx, tagReader, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
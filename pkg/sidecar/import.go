@@ -0,0 +1,265 @@
+package sidecar
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/match"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type PerformerFinderCreator interface {
+	match.PerformerFinder
+	models.PerformerCreator
+}
+
+type StudioFinderCreator interface {
+	match.StudioFinder
+	models.StudioCreator
+}
+
+type TagFinderCreator interface {
+	models.TagQueryer
+	models.TagCreator
+}
+
+// Importer applies sidecar metadata to scenes, images and galleries.
+// Fields that are set in the sidecar overwrite the existing values.
+// Performers, tags and URLs are added to the existing values. Performers,
+// studios and tags are matched by name or alias, and are created if they do
+// not exist.
+type Importer struct {
+	PerformerWriter PerformerFinderCreator
+	StudioWriter    StudioFinderCreator
+	TagWriter       TagFinderCreator
+}
+
+// values holds the metadata values in the form used by the partial objects.
+type values struct {
+	title        models.OptionalString
+	code         models.OptionalString
+	details      models.OptionalString
+	director     models.OptionalString
+	photographer models.OptionalString
+	date         models.OptionalDate
+	rating       models.OptionalInt
+	organized    models.OptionalBool
+	studioID     models.OptionalInt
+	urls         *models.UpdateStrings
+	performerIDs *models.UpdateIDs
+	tagIDs       *models.UpdateIDs
+}
+
+func optionalString(v string) models.OptionalString {
+	if v == "" {
+		return models.OptionalString{}
+	}
+	return models.NewOptionalString(v)
+}
+
+func addIDs(ids []int) *models.UpdateIDs {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return &models.UpdateIDs{
+		IDs:  ids,
+		Mode: models.RelationshipUpdateModeAdd,
+	}
+}
+
+func (i *Importer) values(ctx context.Context, m *Metadata) (*values, error) {
+	ret := &values{
+		title:        optionalString(m.Title),
+		code:         optionalString(m.Code),
+		details:      optionalString(m.Details),
+		director:     optionalString(m.Director),
+		photographer: optionalString(m.Photographer),
+	}
+
+	if m.Date != "" {
+		if d, err := models.ParseDate(m.Date); err == nil {
+			ret.date = models.NewOptionalDate(d)
+		}
+	}
+
+	if m.Rating != nil {
+		ret.rating = models.NewOptionalInt(*m.Rating)
+	}
+	if m.Organized != nil {
+		ret.organized = models.NewOptionalBool(*m.Organized)
+	}
+
+	if len(m.URLs) > 0 {
+		ret.urls = &models.UpdateStrings{
+			Values: m.URLs,
+			Mode:   models.RelationshipUpdateModeAdd,
+		}
+	}
+
+	if m.Studio != "" {
+		id, err := i.studioID(ctx, m.Studio)
+		if err != nil {
+			return nil, err
+		}
+		ret.studioID = models.NewOptionalInt(id)
+	}
+
+	var performerIDs []int
+	for _, name := range m.Performers {
+		id, err := i.performerID(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		performerIDs = append(performerIDs, id)
+	}
+	ret.performerIDs = addIDs(performerIDs)
+
+	var tagIDs []int
+	for _, name := range m.Tags {
+		id, err := i.tagID(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		tagIDs = append(tagIDs, id)
+	}
+	ret.tagIDs = addIDs(tagIDs)
+
+	return ret, nil
+}
+
+func (i *Importer) studioID(ctx context.Context, name string) (int, error) {
+	s := &models.ScrapedStudio{Name: name}
+	if err := match.ScrapedStudio(ctx, i.StudioWriter, s, nil); err != nil {
+		return 0, fmt.Errorf("matching studio %q: %w", name, err)
+	}
+	if s.StoredID != nil {
+		return strconv.Atoi(*s.StoredID)
+	}
+
+	newStudio := models.NewStudio()
+	newStudio.Name = name
+	if err := i.StudioWriter.Create(ctx, &newStudio); err != nil {
+		return 0, fmt.Errorf("creating studio %q: %w", name, err)
+	}
+
+	return newStudio.ID, nil
+}
+
+func (i *Importer) performerID(ctx context.Context, name string) (int, error) {
+	p := &models.ScrapedPerformer{Name: &name}
+	if err := match.ScrapedPerformer(ctx, i.PerformerWriter, p, nil); err != nil {
+		return 0, fmt.Errorf("matching performer %q: %w", name, err)
+	}
+	if p.StoredID != nil {
+		return strconv.Atoi(*p.StoredID)
+	}
+
+	newPerformer := models.NewPerformer()
+	newPerformer.Name = name
+	if err := i.PerformerWriter.Create(ctx, &newPerformer); err != nil {
+		return 0, fmt.Errorf("creating performer %q: %w", name, err)
+	}
+
+	return newPerformer.ID, nil
+}
+
+func (i *Importer) tagID(ctx context.Context, name string) (int, error) {
+	t := &models.ScrapedTag{Name: name}
+	if err := match.ScrapedTag(ctx, i.TagWriter, t); err != nil {
+		return 0, fmt.Errorf("matching tag %q: %w", name, err)
+	}
+	if t.StoredID != nil {
+		return strconv.Atoi(*t.StoredID)
+	}
+
+	newTag := models.NewTag()
+	newTag.Name = name
+	if err := i.TagWriter.Create(ctx, &newTag); err != nil {
+		return 0, fmt.Errorf("creating tag %q: %w", name, err)
+	}
+
+	return newTag.ID, nil
+}
+
+// ApplyScene applies the metadata to the scene with the provided ID.
+func (i *Importer) ApplyScene(ctx context.Context, sceneID int, m *Metadata, w models.SceneUpdater) error {
+	v, err := i.values(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	partial := models.NewScenePartial()
+	partial.Title = v.title
+	partial.Code = v.code
+	partial.Details = v.details
+	partial.Director = v.director
+	partial.Date = v.date
+	partial.Rating = v.rating
+	partial.Organized = v.organized
+	partial.StudioID = v.studioID
+	partial.URLs = v.urls
+	partial.PerformerIDs = v.performerIDs
+	partial.TagIDs = v.tagIDs
+
+	if _, err := w.UpdatePartial(ctx, sceneID, partial); err != nil {
+		return fmt.Errorf("updating scene: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyImage applies the metadata to the image with the provided ID.
+func (i *Importer) ApplyImage(ctx context.Context, imageID int, m *Metadata, w models.ImageUpdater) error {
+	v, err := i.values(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	partial := models.NewImagePartial()
+	partial.Title = v.title
+	partial.Code = v.code
+	partial.Details = v.details
+	partial.Photographer = v.photographer
+	partial.Date = v.date
+	partial.Rating = v.rating
+	partial.Organized = v.organized
+	partial.StudioID = v.studioID
+	partial.URLs = v.urls
+	partial.PerformerIDs = v.performerIDs
+	partial.TagIDs = v.tagIDs
+
+	if _, err := w.UpdatePartial(ctx, imageID, partial); err != nil {
+		return fmt.Errorf("updating image: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyGallery applies the metadata to the gallery with the provided ID.
+func (i *Importer) ApplyGallery(ctx context.Context, galleryID int, m *Metadata, w models.GalleryUpdater) error {
+	v, err := i.values(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	partial := models.NewGalleryPartial()
+	partial.Title = v.title
+	partial.Code = v.code
+	partial.Details = v.details
+	partial.Photographer = v.photographer
+	partial.Date = v.date
+	partial.Rating = v.rating
+	partial.Organized = v.organized
+	partial.StudioID = v.studioID
+	partial.URLs = v.urls
+	partial.PerformerIDs = v.performerIDs
+	partial.TagIDs = v.tagIDs
+
+	if _, err := w.UpdatePartial(ctx, galleryID, partial); err != nil {
+		return fmt.Errorf("updating gallery: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package sidecar
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nfoRootElements are the NFO root elements that are read. NFO files are
+// written with the movie root element.
+var nfoRootElements = []string{"movie", "episodedetails", "musicvideo"}
+
+type nfoActor struct {
+	Name string `xml:"name"`
+}
+
+type nfo struct {
+	XMLName     xml.Name
+	Title       string     `xml:"title,omitempty"`
+	Plot        string     `xml:"plot,omitempty"`
+	Outline     string     `xml:"outline,omitempty"`
+	Premiered   string     `xml:"premiered,omitempty"`
+	Aired       string     `xml:"aired,omitempty"`
+	ReleaseDate string     `xml:"releasedate,omitempty"`
+	Year        string     `xml:"year,omitempty"`
+	UserRating  string     `xml:"userrating,omitempty"`
+	Rating      string     `xml:"rating,omitempty"`
+	Directors   []string   `xml:"director,omitempty"`
+	Studios     []string   `xml:"studio,omitempty"`
+	Actors      []nfoActor `xml:"actor,omitempty"`
+	Tags        []string   `xml:"tag,omitempty"`
+	Genres      []string   `xml:"genre,omitempty"`
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// nfoDate returns the date part of an NFO date value, or an empty string if
+// it is not a valid date.
+func nfoDate(v string) string {
+	if len(v) > 10 {
+		v = v[:10]
+	}
+	if _, err := time.Parse("2006-01-02", v); err != nil {
+		return ""
+	}
+	return v
+}
+
+// nfoRating converts a rating on a 0-10 scale to a rating on a 1-100 scale.
+func nfoRating(v string) *int {
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil || f <= 0 || f > 10 {
+		return nil
+	}
+
+	ret := int(math.Round(f * 10))
+	return &ret
+}
+
+// ParseNFO parses an NFO file. Any content after the root element, such as
+// a scraper URL, is ignored.
+func ParseNFO(r io.Reader) (*Metadata, error) {
+	d := xml.NewDecoder(r)
+	d.Strict = false
+
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return nil, fmt.Errorf("parsing NFO sidecar: %w", err)
+		}
+
+		se, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		supported := false
+		for _, e := range nfoRootElements {
+			if strings.EqualFold(se.Name.Local, e) {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return nil, fmt.Errorf("parsing NFO sidecar: unsupported root element %q", se.Name.Local)
+		}
+
+		var n nfo
+		if err := d.DecodeElement(&n, &se); err != nil {
+			return nil, fmt.Errorf("parsing NFO sidecar: %w", err)
+		}
+
+		return n.metadata(), nil
+	}
+}
+
+func (n nfo) metadata() *Metadata {
+	ret := &Metadata{
+		Title:   n.Title,
+		Details: firstNonEmpty(n.Plot, n.Outline),
+		Date:    nfoDate(firstNonEmpty(n.Premiered, n.Aired, n.ReleaseDate)),
+		Rating:  nfoRating(n.UserRating),
+	}
+
+	if ret.Rating == nil {
+		ret.Rating = nfoRating(n.Rating)
+	}
+
+	ret.Director = firstNonEmpty(n.Directors...)
+	ret.Studio = firstNonEmpty(n.Studios...)
+
+	for _, a := range n.Actors {
+		ret.Performers = append(ret.Performers, a.Name)
+	}
+
+	ret.Tags = append(ret.Tags, n.Tags...)
+	ret.Tags = append(ret.Tags, n.Genres...)
+
+	ret.clean()
+	return ret
+}
+
+// WriteNFO writes the metadata as an NFO file with a movie root element.
+func WriteNFO(w io.Writer, m *Metadata) error {
+	n := nfo{
+		XMLName:   xml.Name{Local: nfoRootElements[0]},
+		Title:     m.Title,
+		Plot:      m.Details,
+		Premiered: m.Date,
+		Tags:      m.Tags,
+	}
+
+	if len(m.Date) >= 4 {
+		n.Year = m.Date[:4]
+	}
+
+	if m.Rating != nil {
+		if r := int(math.Round(float64(*m.Rating) / 10)); r > 0 {
+			n.UserRating = strconv.Itoa(r)
+		}
+	}
+
+	if m.Director != "" {
+		n.Directors = []string{m.Director}
+	}
+	if m.Studio != "" {
+		n.Studios = []string{m.Studio}
+	}
+	for _, p := range m.Performers {
+		n.Actors = append(n.Actors, nfoActor{Name: p})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(n); err != nil {
+		return fmt.Errorf("writing NFO sidecar: %w", err)
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,158 @@
+package sidecar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestParseNFO(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    *Metadata
+		wantErr bool
+	}{
+		{
+			"movie",
+			`<?xml version="1.0" encoding="UTF-8" standalone="yes" ?>
+<movie>
+  <title> Title </title>
+  <plot>Plot</plot>
+  <outline>Outline</outline>
+  <premiered>2021-02-03</premiered>
+  <userrating>7.5</userrating>
+  <rating>9</rating>
+  <director>Director</director>
+  <studio>Studio</studio>
+  <studio>Other Studio</studio>
+  <actor><name>Performer 1</name><role>Role</role></actor>
+  <actor><name>Performer 2</name></actor>
+  <tag>Tag 1</tag>
+  <genre>Genre</genre>
+  <genre>tag 1</genre>
+</movie>
+https://example.com/scraper-url`,
+			&Metadata{
+				Title:      "Title",
+				Details:    "Plot",
+				Date:       "2021-02-03",
+				Rating:     intPtr(75),
+				Director:   "Director",
+				Studio:     "Studio",
+				Performers: []string{"Performer 1", "Performer 2"},
+				Tags:       []string{"Tag 1", "Genre"},
+			},
+			false,
+		},
+		{
+			"episode",
+			`<episodedetails>
+  <title>Title</title>
+  <outline>Outline</outline>
+  <aired>2021-02-03 10:11:12</aired>
+  <rating>4</rating>
+</episodedetails>`,
+			&Metadata{
+				Title:   "Title",
+				Details: "Outline",
+				Date:    "2021-02-03",
+				Rating:  intPtr(40),
+			},
+			false,
+		},
+		{
+			"invalid values",
+			`<movie><premiered>2021</premiered><userrating>0</userrating><rating>11</rating></movie>`,
+			&Metadata{},
+			false,
+		},
+		{
+			"unsupported root",
+			`<tvshow><title>Title</title></tvshow>`,
+			nil,
+			true,
+		},
+		{
+			"url only",
+			`https://example.com/scraper-url`,
+			nil,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNFO(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseNFO() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWriteNFO(t *testing.T) {
+	organized := true
+	m := &Metadata{
+		Title:      "Title & more",
+		Code:       "ABC-123",
+		Details:    "Details",
+		Director:   "Director",
+		Date:       "2021-02-03",
+		URLs:       []string{"https://example.com"},
+		Rating:     intPtr(84),
+		Organized:  &organized,
+		Studio:     "Studio",
+		Performers: []string{"Performer 1", "Performer 2"},
+		Tags:       []string{"Tag 1"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteNFO(&buf, m); err != nil {
+		t.Fatalf("WriteNFO() error = %v", err)
+	}
+
+	const want = `<?xml version="1.0" encoding="UTF-8"?>
+<movie>
+  <title>Title &amp; more</title>
+  <plot>Details</plot>
+  <premiered>2021-02-03</premiered>
+  <year>2021</year>
+  <userrating>8</userrating>
+  <director>Director</director>
+  <studio>Studio</studio>
+  <actor>
+    <name>Performer 1</name>
+  </actor>
+  <actor>
+    <name>Performer 2</name>
+  </actor>
+  <tag>Tag 1</tag>
+</movie>
+`
+	assert.Equal(t, want, buf.String())
+
+	// fields not supported by NFO files are not written
+	got, err := ParseNFO(&buf)
+	if err != nil {
+		t.Fatalf("ParseNFO() error = %v", err)
+	}
+	assert.Equal(t, &Metadata{
+		Title:      m.Title,
+		Details:    m.Details,
+		Director:   m.Director,
+		Date:       m.Date,
+		Rating:     intPtr(80),
+		Studio:     m.Studio,
+		Performers: m.Performers,
+		Tags:       m.Tags,
+	}, got)
+}
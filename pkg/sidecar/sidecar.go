@@ -0,0 +1,140 @@
+// Package sidecar reads and writes metadata sidecar files, which are stored
+// next to media files. NFO files, as used by Kodi and Jellyfin, and JSON files
+// are supported.
+package sidecar
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+)
+
+const (
+	NFOExt  = "nfo"
+	JSONExt = "json"
+
+	// GalleryBasename is the basename of sidecar files for folder-based
+	// galleries. These are stored in the gallery folder.
+	GalleryBasename = "gallery"
+)
+
+var Exts = []string{NFOExt, JSONExt}
+
+// Metadata is the metadata read from or written to a sidecar file. It is also
+// the format of JSON sidecar files. Empty fields are not applied.
+type Metadata struct {
+	Title        string `json:"title,omitempty"`
+	Code         string `json:"code,omitempty"`
+	Details      string `json:"details,omitempty"`
+	Director     string `json:"director,omitempty"`
+	Photographer string `json:"photographer,omitempty"`
+	// Date in YYYY-MM-DD format
+	Date string   `json:"date,omitempty"`
+	URLs []string `json:"urls,omitempty"`
+	// Rating expressed in 1-100 scale
+	Rating     *int     `json:"rating,omitempty"`
+	Organized  *bool    `json:"organized,omitempty"`
+	Studio     string   `json:"studio,omitempty"`
+	Performers []string `json:"performers,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// IsSidecar returns true if the path has a sidecar file extension.
+func IsSidecar(path string) bool {
+	return fsutil.MatchExtension(path, Exts)
+}
+
+// MediaBasename returns the basename without extension of the media files
+// that the sidecar file applies to.
+func MediaBasename(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// IsGallerySidecar returns true if the path is a sidecar file for the
+// folder-based gallery of its parent folder.
+func IsGallerySidecar(path string) bool {
+	return MediaBasename(path) == GalleryBasename
+}
+
+// Path returns the path of the sidecar file with the provided extension for
+// the provided media file.
+func Path(mediaPath string, ext string) string {
+	return strings.TrimSuffix(mediaPath, filepath.Ext(mediaPath)) + "." + ext
+}
+
+// Read reads the sidecar file at path. The format is determined by the
+// file extension.
+func Read(path string) (*Metadata, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	switch ext {
+	case NFOExt:
+		return ParseNFO(f)
+	case JSONExt:
+		return ParseJSON(f)
+	}
+
+	return nil, fmt.Errorf("unsupported sidecar file extension %q", ext)
+}
+
+// ParseJSON parses a JSON sidecar file.
+func ParseJSON(r io.Reader) (*Metadata, error) {
+	var ret Metadata
+	if err := json.NewDecoder(r).Decode(&ret); err != nil {
+		return nil, fmt.Errorf("parsing JSON sidecar: %w", err)
+	}
+
+	ret.clean()
+	return &ret, nil
+}
+
+// clean trims the values and removes empty and duplicate list values.
+func (m *Metadata) clean() {
+	m.Title = strings.TrimSpace(m.Title)
+	m.Code = strings.TrimSpace(m.Code)
+	m.Details = strings.TrimSpace(m.Details)
+	m.Director = strings.TrimSpace(m.Director)
+	m.Photographer = strings.TrimSpace(m.Photographer)
+	m.Date = strings.TrimSpace(m.Date)
+	m.Studio = strings.TrimSpace(m.Studio)
+	m.URLs = cleanList(m.URLs)
+	m.Performers = cleanList(m.Performers)
+	m.Tags = cleanList(m.Tags)
+
+	if m.Rating != nil && (*m.Rating < 1 || *m.Rating > 100) {
+		m.Rating = nil
+	}
+}
+
+func cleanList(l []string) []string {
+	var ret []string
+	for _, v := range l {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		found := false
+		for _, vv := range ret {
+			if strings.EqualFold(v, vv) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
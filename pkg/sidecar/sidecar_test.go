@@ -0,0 +1,50 @@
+package sidecar
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJSON(t *testing.T) {
+	const input = `{
+  "title": " Title ",
+  "date": "2021-02-03",
+  "urls": ["https://example.com", ""],
+  "rating": 101,
+  "organized": true,
+  "studio": "Studio",
+  "performers": ["Performer", "performer", " "],
+  "tags": ["Tag"],
+  "unknown": "ignored"
+}`
+
+	got, err := ParseJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseJSON() error = %v", err)
+	}
+
+	organized := true
+	assert.Equal(t, &Metadata{
+		Title:      "Title",
+		Date:       "2021-02-03",
+		URLs:       []string{"https://example.com"},
+		Organized:  &organized,
+		Studio:     "Studio",
+		Performers: []string{"Performer"},
+		Tags:       []string{"Tag"},
+	}, got)
+
+	_, err = ParseJSON(strings.NewReader("not json"))
+	assert.Error(t, err)
+}
+
+func TestPaths(t *testing.T) {
+	assert.Equal(t, "/dir/video.nfo", Path("/dir/video.mp4", NFOExt))
+	assert.Equal(t, "video.part1", MediaBasename("/dir/video.part1.json"))
+	assert.True(t, IsSidecar("/dir/video.NFO"))
+	assert.False(t, IsSidecar("/dir/video.mp4"))
+	assert.True(t, IsGallerySidecar("/dir/gallery.json"))
+	assert.False(t, IsGallerySidecar("/dir/gallery.zip.json"))
+}
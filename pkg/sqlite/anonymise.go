@@ -45,8 +45,20 @@ func (db *Anonymiser) Anonymise(ctx context.Context) error {
 		defer db.Close()
 
 		return utils.Do([]func() error{
+			func() error { return db.clearFullTextIndexes() },
 			func() error { return db.deleteBlobs() },
 			func() error { return db.deleteStashIDs() },
+			func() error { return db.truncateTable(pluginStorageTable) },
+			func() error { return db.truncateTable(changeTable) },
+			func() error { return db.truncateTable(identifyProposalTable) },
+			func() error { return db.truncateTable(stashBoxSubmissionTable) },
+			func() error { return db.truncateTable(stashBoxSubmittedFingerprintTable) },
+			func() error { return db.truncateTable(stashBoxUpdateTable) },
+			func() error { return db.truncateTable(stashBoxUpstreamStateTable) },
+			func() error { return db.truncateTable(autoTagRuleTable) },
+			func() error { return db.truncateTable(filenameParserTemplateTable) },
+			func() error { return db.truncateTable(playlistItemsTable) },
+			func() error { return db.truncateTable(playlistTable) },
 			func() error { return db.anonymiseFolders(ctx) },
 			func() error { return db.anonymiseFiles(ctx) },
 			func() error { return db.anonymiseFingerprints(ctx) },
@@ -80,6 +92,36 @@ func (db *Anonymiser) truncateTable(tableName string) error {
 	return err
 }
 
+// clearFullTextIndexes removes the contents of the full-text indexes, which
+// contain the original values. The triggers are dropped so that the indexes
+// are rebuilt when the anonymised database is opened.
+func (db *Anonymiser) clearFullTextIndexes() error {
+	for _, i := range fullTextIndexes {
+		for _, stmt := range i.dropTriggersSQL() {
+			if _, err := db.db.Exec(stmt); err != nil {
+				return err
+			}
+		}
+
+		// the shadow tables can be written to without FTS5 being available
+		for _, suffix := range []string{"_data", "_idx", "_content", "_docsize"} {
+			table := i.ftsTable() + suffix
+			var count int
+			if err := db.db.Get(&count, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table); err != nil {
+				return err
+			}
+
+			if count > 0 {
+				if err := db.truncateTable(table); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 func (db *Anonymiser) deleteBlobs() error {
 	return utils.Do([]func() error{
 		func() error { return db.truncateColumn("tags", "image_blob") },
@@ -149,7 +191,11 @@ func (db *Anonymiser) anonymiseFiles(ctx context.Context) error {
 	logger.Infof("Anonymising files")
 	return txn.WithTxn(ctx, db, func(ctx context.Context) error {
 		table := fileTableMgr.table
-		stmt := dialect.Update(table).Set(goqu.Record{"basename": goqu.Cast(table.Col(idColumn), "VARCHAR")})
+		// embedded metadata is cleared rather than anonymised
+		stmt := dialect.Update(table).Set(goqu.Record{
+			"basename": goqu.Cast(table.Col(idColumn), "VARCHAR"),
+			"metadata": goqu.L("'{}'"),
+		})
 
 		if _, err := exec(ctx, stmt); err != nil {
 			return fmt.Errorf("anonymising %s: %w", table.GetTable(), err)
@@ -0,0 +1,201 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const autoTagRuleTable = "auto_tag_rules"
+
+type autoTagRuleRow struct {
+	ID         int       `db:"id" goqu:"skipinsert"`
+	Name       string    `db:"name"`
+	Enabled    bool      `db:"enabled"`
+	Conditions string    `db:"conditions"`
+	Actions    string    `db:"actions"`
+	CreatedAt  Timestamp `db:"created_at"`
+	UpdatedAt  Timestamp `db:"updated_at"`
+}
+
+func (r *autoTagRuleRow) fromAutoTagRule(o models.AutoTagRule) error {
+	conditions, err := json.Marshal(o.Conditions)
+	if err != nil {
+		return fmt.Errorf("encoding conditions: %w", err)
+	}
+
+	actions, err := json.Marshal(o.Actions)
+	if err != nil {
+		return fmt.Errorf("encoding actions: %w", err)
+	}
+
+	r.ID = o.ID
+	r.Name = o.Name
+	r.Enabled = o.Enabled
+	r.Conditions = string(conditions)
+	r.Actions = string(actions)
+	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
+	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
+
+	return nil
+}
+
+func (r *autoTagRuleRow) resolve() (*models.AutoTagRule, error) {
+	ret := &models.AutoTagRule{
+		ID:        r.ID,
+		Name:      r.Name,
+		Enabled:   r.Enabled,
+		CreatedAt: r.CreatedAt.Timestamp,
+		UpdatedAt: r.UpdatedAt.Timestamp,
+	}
+
+	if err := json.Unmarshal([]byte(r.Conditions), &ret.Conditions); err != nil {
+		return nil, fmt.Errorf("decoding conditions: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(r.Actions), &ret.Actions); err != nil {
+		return nil, fmt.Errorf("decoding actions: %w", err)
+	}
+
+	return ret, nil
+}
+
+// AutoTagRuleStore stores the user-defined auto-tag rules.
+type AutoTagRuleStore struct{}
+
+func NewAutoTagRuleStore() *AutoTagRuleStore {
+	return &AutoTagRuleStore{}
+}
+
+func (qb *AutoTagRuleStore) table() exp.IdentifierExpression {
+	return goqu.T(autoTagRuleTable)
+}
+
+func (qb *AutoTagRuleStore) selectDataset() *goqu.SelectDataset {
+	return dialect.From(qb.table()).Select(qb.table().All())
+}
+
+func (qb *AutoTagRuleStore) Create(ctx context.Context, newObject *models.AutoTagRule) error {
+	var r autoTagRuleRow
+	if err := r.fromAutoTagRule(*newObject); err != nil {
+		return err
+	}
+
+	q := dialect.Insert(qb.table()).Prepared(true).Rows(r)
+	result, err := exec(ctx, q)
+	if err != nil {
+		return fmt.Errorf("inserting into %s: %w", autoTagRuleTable, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	newObject.ID = int(id)
+	return nil
+}
+
+func (qb *AutoTagRuleStore) Update(ctx context.Context, updatedObject *models.AutoTagRule) error {
+	var r autoTagRuleRow
+	if err := r.fromAutoTagRule(*updatedObject); err != nil {
+		return err
+	}
+
+	table := qb.table()
+	q := dialect.Update(table).Prepared(true).Set(r).Where(table.Col(idColumn).Eq(updatedObject.ID))
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("updating %s: %w", autoTagRuleTable, err)
+	}
+
+	return nil
+}
+
+func (qb *AutoTagRuleStore) Destroy(ctx context.Context, id int) error {
+	table := qb.table()
+	q := dialect.Delete(table).Where(table.Col(idColumn).Eq(id))
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("deleting from %s: %w", autoTagRuleTable, err)
+	}
+
+	return nil
+}
+
+// returns nil, nil if not found
+func (qb *AutoTagRuleStore) Find(ctx context.Context, id int) (*models.AutoTagRule, error) {
+	q := qb.selectDataset().Where(qb.table().Col(idColumn).Eq(id))
+
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
+
+func (qb *AutoTagRuleStore) FindMany(ctx context.Context, ids []int) ([]*models.AutoTagRule, error) {
+	q := qb.selectDataset().Where(qb.table().Col(idColumn).In(ids))
+
+	unsorted, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*models.AutoTagRule, len(ids))
+	for _, s := range unsorted {
+		for i, id := range ids {
+			if id == s.ID {
+				ret[i] = s
+			}
+		}
+	}
+
+	for i := range ret {
+		if ret[i] == nil {
+			return nil, fmt.Errorf("auto-tag rule with id %d not found", ids[i])
+		}
+	}
+
+	return ret, nil
+}
+
+func (qb *AutoTagRuleStore) All(ctx context.Context) ([]*models.AutoTagRule, error) {
+	q := qb.selectDataset().Order(qb.table().Col(idColumn).Asc())
+
+	return qb.getMany(ctx, q)
+}
+
+func (qb *AutoTagRuleStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.AutoTagRule, error) {
+	const single = false
+	var ret []*models.AutoTagRule
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var f autoTagRuleRow
+		if err := r.StructScan(&f); err != nil {
+			return err
+		}
+
+		o, err := f.resolve()
+		if err != nil {
+			return err
+		}
+
+		ret = append(ret, o)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("querying %s: %w", autoTagRuleTable, err)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,343 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func Test_AutoTagRuleStore_Create(t *testing.T) {
+	var (
+		pathRegex     = `.*\.mp4$`
+		folder        = "/videos"
+		minDuration   = 60
+		maxDuration   = 600
+		minResolution = models.ResolutionEnumStandardHd
+		maxResolution = models.ResolutionEnumFourK
+		studioID      = studioIDs[studioIdxWithScene]
+		organized     = true
+		movieID       = movieIDs[movieIdxWithScene]
+		galleryID     = galleryIDs[galleryIdxWithScene]
+	)
+
+	tests := []struct {
+		name      string
+		newObject models.AutoTagRule
+	}{
+		{
+			"full",
+			models.AutoTagRule{
+				Name:    "full",
+				Enabled: true,
+				Conditions: models.AutoTagRuleConditions{
+					PathRegex:      &pathRegex,
+					Folder:         &folder,
+					MinDuration:    &minDuration,
+					MaxDuration:    &maxDuration,
+					MinResolution:  &minResolution,
+					MaxResolution:  &maxResolution,
+					TagIDs:         []int{tagIDs[tagIdxWithScene]},
+					ExcludedTagIDs: []int{tagIDs[tagIdx1WithScene]},
+					StudioIDs:      []int{studioID},
+				},
+				Actions: models.AutoTagRuleActions{
+					AddTagIDs:       []int{tagIDs[tagIdxWithImage]},
+					AddPerformerIDs: []int{performerIDs[performerIdxWithScene]},
+					StudioID:        &studioID,
+					Organized:       &organized,
+					MovieID:         &movieID,
+					GalleryID:       &galleryID,
+				},
+				CreatedAt: autoTagRuleTime,
+				UpdatedAt: autoTagRuleTime,
+			},
+		},
+		{
+			"disabled",
+			models.AutoTagRule{
+				Name:    "disabled",
+				Enabled: false,
+				Conditions: models.AutoTagRuleConditions{
+					TagIDs: []int{tagIDs[tagIdxWithScene]},
+				},
+				CreatedAt: autoTagRuleTime,
+				UpdatedAt: autoTagRuleTime,
+			},
+		},
+		{
+			// matches all items
+			"no conditions or actions",
+			models.AutoTagRule{
+				Name:      "empty",
+				Enabled:   true,
+				CreatedAt: autoTagRuleTime,
+				UpdatedAt: autoTagRuleTime,
+			},
+		},
+	}
+
+	qb := db.AutoTagRule
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			r := tt.newObject
+			if err := qb.Create(ctx, &r); err != nil {
+				t.Errorf("AutoTagRuleStore.Create() error = %v", err)
+				return
+			}
+
+			assert.NotZero(r.ID)
+
+			found, err := qb.Find(ctx, r.ID)
+			if err != nil {
+				t.Errorf("AutoTagRuleStore.Find() error = %v", err)
+			}
+
+			if !assert.NotNil(found) {
+				return
+			}
+
+			want := tt.newObject
+			want.ID = r.ID
+			assert.Equal(want, *found)
+		})
+	}
+}
+
+func Test_AutoTagRuleStore_Update(t *testing.T) {
+	var (
+		folder      = "/other"
+		maxDuration = 120
+		organized   = false
+	)
+
+	tests := []struct {
+		name   string
+		update func(r *models.AutoTagRule)
+	}{
+		{
+			"disable",
+			func(r *models.AutoTagRule) {
+				r.Enabled = false
+			},
+		},
+		{
+			"rename",
+			func(r *models.AutoTagRule) {
+				r.Name = "renamed"
+			},
+		},
+		{
+			"replace conditions",
+			func(r *models.AutoTagRule) {
+				r.Conditions = models.AutoTagRuleConditions{
+					Folder:      &folder,
+					MaxDuration: &maxDuration,
+				}
+			},
+		},
+		{
+			"clear conditions",
+			func(r *models.AutoTagRule) {
+				r.Conditions = models.AutoTagRuleConditions{}
+			},
+		},
+		{
+			// a false organized flag must be kept, not treated as unset
+			"replace actions",
+			func(r *models.AutoTagRule) {
+				r.Actions = models.AutoTagRuleActions{
+					AddPerformerIDs: []int{performerIDs[performerIdxWithScene]},
+					Organized:       &organized,
+				}
+			},
+		},
+		{
+			"update time",
+			func(r *models.AutoTagRule) {
+				r.UpdatedAt = autoTagRuleTime.Add(time.Hour)
+			},
+		},
+	}
+
+	qb := db.AutoTagRule
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			id := autoTagRuleIDs[autoTagRuleIdxEnabled]
+			otherID := autoTagRuleIDs[autoTagRuleIdxDisabled]
+
+			r, err := qb.Find(ctx, id)
+			if err != nil {
+				t.Errorf("AutoTagRuleStore.Find() error = %v", err)
+				return
+			}
+
+			other, err := qb.Find(ctx, otherID)
+			if err != nil {
+				t.Errorf("AutoTagRuleStore.Find() error = %v", err)
+				return
+			}
+
+			tt.update(r)
+			if err := qb.Update(ctx, r); err != nil {
+				t.Errorf("AutoTagRuleStore.Update() error = %v", err)
+				return
+			}
+
+			got, err := qb.FindMany(ctx, []int{id, otherID})
+			if err != nil {
+				t.Errorf("AutoTagRuleStore.FindMany() error = %v", err)
+				return
+			}
+
+			assert.Equal(r, got[0])
+
+			// other rules are not changed
+			assert.Equal(other, got[1])
+		})
+	}
+}
+
+func Test_AutoTagRuleStore_Destroy(t *testing.T) {
+	tests := []struct {
+		name string
+		id   int
+		// indexes of the remaining rules
+		want []int
+	}{
+		{"enabled", autoTagRuleIDs[autoTagRuleIdxEnabled], []int{autoTagRuleIdxDisabled}},
+		{"disabled", autoTagRuleIDs[autoTagRuleIdxDisabled], []int{autoTagRuleIdxEnabled}},
+		{"invalid id", invalidID, []int{autoTagRuleIdxEnabled, autoTagRuleIdxDisabled}},
+	}
+
+	qb := db.AutoTagRule
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			if err := qb.Destroy(ctx, tt.id); err != nil {
+				t.Errorf("AutoTagRuleStore.Destroy() error = %v", err)
+				return
+			}
+
+			all, err := qb.All(ctx)
+			if err != nil {
+				t.Errorf("AutoTagRuleStore.All() error = %v", err)
+				return
+			}
+
+			var gotIDs []int
+			for _, r := range all {
+				gotIDs = append(gotIDs, r.ID)
+			}
+
+			assert.Equal(t, indexesToIDs(autoTagRuleIDs, tt.want), gotIDs)
+		})
+	}
+}
+
+func Test_AutoTagRuleStore_Find(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       int
+		wantName string
+		wantNil  bool
+	}{
+		{"valid", autoTagRuleIDs[autoTagRuleIdxDisabled], autoTagRuleSpecs[autoTagRuleIdxDisabled].name, false},
+		{"invalid", invalidID, "", true},
+	}
+
+	qb := db.AutoTagRule
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			got, err := qb.Find(ctx, tt.id)
+			if err != nil {
+				t.Errorf("AutoTagRuleStore.Find() error = %v", err)
+				return
+			}
+
+			if tt.wantNil {
+				assert.Nil(t, got)
+				return
+			}
+
+			if assert.NotNil(t, got) {
+				assert.Equal(t, tt.wantName, got.Name)
+			}
+		})
+	}
+}
+
+func Test_AutoTagRuleStore_FindMany(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     []int
+		wantErr bool
+	}{
+		{
+			// returned in the order requested
+			"valid",
+			[]int{autoTagRuleIDs[autoTagRuleIdxDisabled], autoTagRuleIDs[autoTagRuleIdxEnabled]},
+			false,
+		},
+		{
+			"invalid",
+			[]int{autoTagRuleIDs[autoTagRuleIdxEnabled], invalidID},
+			true,
+		},
+	}
+
+	qb := db.AutoTagRule
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			got, err := qb.FindMany(ctx, tt.ids)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AutoTagRuleStore.FindMany() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			var gotIDs []int
+			for _, r := range got {
+				gotIDs = append(gotIDs, r.ID)
+			}
+			assert.Equal(t, tt.ids, gotIDs)
+		})
+	}
+}
+
+func Test_AutoTagRuleStore_All(t *testing.T) {
+	qb := db.AutoTagRule
+
+	// disabled rules are included, in the order they were created
+	runWithRollbackTxn(t, "all", func(t *testing.T, ctx context.Context) {
+		all, err := qb.All(ctx)
+		if err != nil {
+			t.Errorf("AutoTagRuleStore.All() error = %v", err)
+			return
+		}
+
+		var gotIDs []int
+		for _, r := range all {
+			gotIDs = append(gotIDs, r.ID)
+		}
+		assert.Equal(t, autoTagRuleIDs, gotIDs)
+		assert.Len(t, all, totalAutoTagRules)
+	})
+}
@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// CheckIntegrity runs an integrity check on the database file at dbPath,
+// returning an error if the database is corrupt.
+func CheckIntegrity(dbPath string) error {
+	conn, err := sqlx.Connect(sqlite3Driver, "file:"+dbPath+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("open database %s failed: %w", dbPath, err)
+	}
+	defer conn.Close()
+
+	var results []string
+	if err := conn.Select(&results, "PRAGMA integrity_check"); err != nil {
+		return fmt.Errorf("integrity check failed: %w", err)
+	}
+
+	if len(results) != 1 || results[0] != "ok" {
+		return fmt.Errorf("database %s failed integrity check: %s", dbPath, strings.Join(results, "; "))
+	}
+
+	return nil
+}
+
+// DatabaseFileSchemaVersion returns the schema version of the database file
+// at dbPath, without opening it as the current database.
+func DatabaseFileSchemaVersion(dbPath string) (uint, error) {
+	conn, err := sqlx.Connect(sqlite3Driver, "file:"+dbPath+"?mode=ro")
+	if err != nil {
+		return 0, fmt.Errorf("open database %s failed: %w", dbPath, err)
+	}
+	defer conn.Close()
+
+	var ret struct {
+		Version uint `db:"version"`
+		Dirty   bool `db:"dirty"`
+	}
+	if err := conn.Get(&ret, "SELECT version, dirty FROM schema_migrations LIMIT 1"); err != nil {
+		return 0, fmt.Errorf("reading schema version: %w", err)
+	}
+
+	if ret.Dirty {
+		return 0, fmt.Errorf("database %s has an incomplete migration", dbPath)
+	}
+
+	return ret.Version, nil
+}
+
+// Restore closes the database, replaces it with the database file at
+// backupPath and reopens it. The backup file is moved, not copied.
+// If the database cannot be replaced, the original database is reopened.
+// A *MigrationNeededError is returned if the restored database must be
+// migrated before it can be used.
+func (db *Database) Restore(backupPath string) error {
+	if err := db.Close(); err != nil {
+		return fmt.Errorf("closing database: %w", err)
+	}
+
+	// the database was closed cleanly, so any write-ahead log files left
+	// are stale, and must not be applied to the restored database. They
+	// are removed before the database is replaced, so that the original
+	// database can be reopened if they cannot be removed.
+	for _, wf := range []string{db.dbPath + "-shm", db.dbPath + "-wal"} {
+		if exists, _ := fsutil.FileExists(wf); exists {
+			if err := os.Remove(wf); err != nil {
+				return db.reopenAfterFailedRestore(fmt.Errorf("removing %s: %w", wf, err))
+			}
+		}
+	}
+
+	if err := db.RestoreFromBackup(backupPath); err != nil {
+		return db.reopenAfterFailedRestore(fmt.Errorf("restoring database: %w", err))
+	}
+
+	err := db.Open(db.dbPath)
+	var migrationNeeded *MigrationNeededError
+	if errors.As(err, &migrationNeeded) {
+		logger.Warnf("restored database requires migration from schema version %d", migrationNeeded.CurrentSchemaVersion)
+	}
+
+	return err
+}
+
+// reopenAfterFailedRestore reopens the original database after it could
+// not be replaced by Restore, and returns restoreErr.
+func (db *Database) reopenAfterFailedRestore(restoreErr error) error {
+	if err := db.Open(db.dbPath); err != nil {
+		logger.Errorf("Error reopening database after failed restore: %v", err)
+	}
+
+	return restoreErr
+}
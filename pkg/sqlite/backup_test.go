@@ -0,0 +1,88 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/sqlite"
+)
+
+func TestBackupIntegrity(t *testing.T) {
+	dir := t.TempDir()
+
+	backupPath := filepath.Join(dir, "backup.sqlite")
+	if err := db.Backup(backupPath); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+
+	assert.NoError(t, sqlite.CheckIntegrity(backupPath))
+
+	version, err := sqlite.DatabaseFileSchemaVersion(backupPath)
+	assert.NoError(t, err)
+	assert.Equal(t, db.AppSchemaVersion(), version)
+
+	corruptPath := filepath.Join(dir, "corrupt.sqlite")
+	if err := os.WriteFile(corruptPath, []byte("not a database"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Error(t, sqlite.CheckIntegrity(corruptPath))
+}
+
+func TestDatabaseRestore(t *testing.T) {
+	dir := t.TempDir()
+
+	// use a copy of the test database so that other tests are unaffected
+	dbPath := filepath.Join(dir, "db.sqlite")
+	if err := db.Backup(dbPath); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+
+	restoreDB := sqlite.NewDatabase()
+	if err := restoreDB.Open(dbPath); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer restoreDB.Close()
+
+	backupPath := filepath.Join(dir, "backup.sqlite")
+	if err := restoreDB.Backup(backupPath); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+
+	if err := restoreDB.Restore(backupPath); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	assert.NoError(t, restoreDB.Ready())
+	assert.NoFileExists(t, backupPath)
+
+	// database must be usable after restoring
+	assert.NoError(t, restoreDB.Backup(filepath.Join(dir, "after.sqlite")))
+}
+
+func TestDatabaseRestoreFailed(t *testing.T) {
+	dir := t.TempDir()
+
+	dbPath := filepath.Join(dir, "db.sqlite")
+	if err := db.Backup(dbPath); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+
+	restoreDB := sqlite.NewDatabase()
+	if err := restoreDB.Open(dbPath); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer restoreDB.Close()
+
+	assert.Error(t, restoreDB.Restore(filepath.Join(dir, "missing.sqlite")))
+
+	// the original database must be reopened
+	assert.NoError(t, restoreDB.Ready())
+	assert.NoError(t, restoreDB.Backup(filepath.Join(dir, "after.sqlite")))
+}
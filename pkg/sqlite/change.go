@@ -0,0 +1,229 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	changeTable = "changes"
+)
+
+type changeRow struct {
+	ID         int         `db:"id" goqu:"skipinsert"`
+	ObjectType string      `db:"object_type"`
+	ObjectID   int         `db:"object_id"`
+	Field      string      `db:"field"`
+	OldValue   string      `db:"old_value"`
+	NewValue   string      `db:"new_value"`
+	OriginType string      `db:"origin_type"`
+	OriginID   null.String `db:"origin_id"`
+	CreatedAt  Timestamp   `db:"created_at"`
+}
+
+func (r *changeRow) fromChange(o models.Change) {
+	r.ID = o.ID
+	r.ObjectType = o.ObjectType.String()
+	r.ObjectID = o.ObjectID
+	r.Field = o.Field
+	r.OldValue = o.OldValue
+	r.NewValue = o.NewValue
+	r.OriginType = o.OriginType.String()
+	r.OriginID = null.StringFromPtr(o.OriginID)
+	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
+}
+
+func (r *changeRow) resolve() *models.Change {
+	return &models.Change{
+		ID:         r.ID,
+		ObjectType: models.ChangeObjectType(r.ObjectType),
+		ObjectID:   r.ObjectID,
+		Field:      r.Field,
+		OldValue:   r.OldValue,
+		NewValue:   r.NewValue,
+		OriginType: models.ChangeOriginType(r.OriginType),
+		OriginID:   r.OriginID.Ptr(),
+		CreatedAt:  r.CreatedAt.Timestamp,
+	}
+}
+
+// ChangeStore stores the field-level change history of objects.
+type ChangeStore struct{}
+
+func NewChangeStore() *ChangeStore {
+	return &ChangeStore{}
+}
+
+func (qb *ChangeStore) table() exp.IdentifierExpression {
+	return goqu.T(changeTable)
+}
+
+func (qb *ChangeStore) selectDataset() *goqu.SelectDataset {
+	return dialect.From(qb.table()).Select(qb.table().All())
+}
+
+// Record stores a change for each field that differs between before and after.
+// Changes are attributed to the origin in ctx.
+func (qb *ChangeStore) Record(ctx context.Context, objectType models.ChangeObjectType, objectID int, before models.ChangeValues, after models.ChangeValues) error {
+	changes, err := before.Diff(after)
+	if err != nil {
+		return err
+	}
+
+	origin := models.ChangeOriginFromContext(ctx)
+	now := time.Now()
+
+	for _, c := range changes {
+		c.ObjectType = objectType
+		c.ObjectID = objectID
+		c.OriginType = origin.Type
+		c.OriginID = origin.ID
+		c.CreatedAt = now
+
+		var r changeRow
+		r.fromChange(*c)
+
+		q := dialect.Insert(qb.table()).Prepared(true).Rows(r)
+		if _, err := exec(ctx, q); err != nil {
+			return fmt.Errorf("inserting into %s: %w", changeTable, err)
+		}
+	}
+
+	return nil
+}
+
+// returns nil, nil if not found
+func (qb *ChangeStore) Find(ctx context.Context, id int) (*models.Change, error) {
+	q := qb.selectDataset().Where(qb.table().Col(idColumn).Eq(id))
+
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
+
+func (qb *ChangeStore) Query(ctx context.Context, changeFilter *models.ChangeFilterType, findFilter *models.FindFilterType) ([]*models.Change, int, error) {
+	if findFilter == nil {
+		findFilter = &models.FindFilterType{}
+	}
+
+	table := qb.table()
+	q := qb.selectDataset()
+
+	if changeFilter != nil {
+		if changeFilter.ObjectType != nil {
+			q = q.Where(table.Col("object_type").Eq(changeFilter.ObjectType.String()))
+		}
+		if changeFilter.ObjectID != nil {
+			q = q.Where(table.Col("object_id").Eq(*changeFilter.ObjectID))
+		}
+		if changeFilter.Field != nil {
+			q = q.Where(table.Col("field").Eq(*changeFilter.Field))
+		}
+		if changeFilter.OriginType != nil {
+			q = q.Where(table.Col("origin_type").Eq(changeFilter.OriginType.String()))
+		}
+		if changeFilter.OriginID != nil {
+			q = q.Where(table.Col("origin_id").Eq(*changeFilter.OriginID))
+		}
+	}
+
+	total, err := count(ctx, q.Select(goqu.COUNT("*")))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q = q.Order(table.Col(idColumn).Desc())
+	if !findFilter.IsGetAll() {
+		pageSize := findFilter.GetPageSize()
+		q = q.Limit(uint(pageSize)).Offset(uint((findFilter.GetPage() - 1) * pageSize))
+	}
+
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ret, total, nil
+}
+
+func (qb *ChangeStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.Change, error) {
+	const single = false
+	var ret []*models.Change
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var f changeRow
+		if err := r.StructScan(&f); err != nil {
+			return err
+		}
+
+		ret = append(ret, f.resolve())
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("querying %s: %w", changeTable, err)
+	}
+
+	return ret, nil
+}
+
+// destroyObject removes the change history of the object. It is called when
+// the object is destroyed, since the changes table has no foreign keys.
+func (qb *ChangeStore) destroyObject(ctx context.Context, objectType models.ChangeObjectType, objectID int) error {
+	table := qb.table()
+	q := dialect.Delete(table).Where(
+		table.Col("object_type").Eq(objectType.String()),
+		table.Col("object_id").Eq(objectID),
+	)
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("deleting from %s: %w", changeTable, err)
+	}
+
+	return nil
+}
+
+type changeValuesFunc func(ctx context.Context, id int) (models.ChangeValues, error)
+
+// withChangeLog records the changes made to the object by fn in the change log.
+// getValues should only load the fields that fn may change.
+// Changes made by the scan task are not recorded.
+func (qb *ChangeStore) withChangeLog(ctx context.Context, objectType models.ChangeObjectType, id int, getValues changeValuesFunc, fn func() error) error {
+	if models.ChangeOriginFromContext(ctx).Type == models.ChangeOriginTypeScan {
+		return fn()
+	}
+
+	before, err := getValues(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		// let fn report the missing object
+		return fn()
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	after, err := getValues(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return qb.Record(ctx, objectType, id, before, after)
+}
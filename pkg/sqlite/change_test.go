@@ -0,0 +1,254 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func TestChangeLog(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Scene
+		sceneID := sceneIDs[sceneIdxWithTag]
+
+		before, err := qb.Find(ctx, sceneID)
+		if err != nil {
+			t.Errorf("SceneStore.Find() error = %v", err)
+			return nil
+		}
+
+		const sourceID = "scraper"
+		sourceIDStr := sourceID
+		identifyCtx := models.WithChangeOrigin(ctx, models.ChangeOrigin{
+			Type: models.ChangeOriginTypeIdentify,
+			ID:   &sourceIDStr,
+		})
+
+		partial := models.NewScenePartial()
+		partial.Title = models.NewOptionalString("changed title")
+		partial.TagIDs = &models.UpdateIDs{
+			IDs:  []int{tagIDs[tagIdx1WithScene]},
+			Mode: models.RelationshipUpdateModeAdd,
+		}
+		if _, err := qb.UpdatePartial(identifyCtx, sceneID, partial); err != nil {
+			t.Errorf("SceneStore.UpdatePartial() error = %v", err)
+			return nil
+		}
+
+		objectType := models.ChangeObjectTypeScene
+		changes, count, err := db.Change.Query(ctx, &models.ChangeFilterType{
+			ObjectType: &objectType,
+			ObjectID:   &sceneID,
+		}, nil)
+		if err != nil {
+			t.Errorf("ChangeStore.Query() error = %v", err)
+			return nil
+		}
+
+		// updated_at is not audited
+		assert.Equal(t, 2, count)
+		if !assert.Len(t, changes, 2) {
+			return nil
+		}
+
+		var titleChange *models.Change
+		for _, c := range changes {
+			assert.Equal(t, models.ChangeOriginTypeIdentify, c.OriginType)
+			if assert.NotNil(t, c.OriginID) {
+				assert.Equal(t, sourceID, *c.OriginID)
+			}
+			if c.Field == "title" {
+				titleChange = c
+			}
+		}
+
+		if !assert.NotNil(t, titleChange) {
+			return nil
+		}
+		assert.Equal(t, `"changed title"`, titleChange.NewValue)
+
+		// revert the title
+		partial = models.NewScenePartial()
+		if err := partial.SetChangeValue(titleChange.Field, titleChange.OldValue); err != nil {
+			t.Errorf("ScenePartial.SetChangeValue() error = %v", err)
+			return nil
+		}
+		reverted, err := qb.UpdatePartial(ctx, sceneID, partial)
+		if err != nil {
+			t.Errorf("SceneStore.UpdatePartial() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, before.Title, reverted.Title)
+
+		field := "title"
+		origin := models.ChangeOriginTypeUser
+		changes, count, err = db.Change.Query(ctx, &models.ChangeFilterType{
+			ObjectType: &objectType,
+			ObjectID:   &sceneID,
+			Field:      &field,
+			OriginType: &origin,
+		}, nil)
+		if err != nil {
+			t.Errorf("ChangeStore.Query() error = %v", err)
+			return nil
+		}
+
+		assert.Equal(t, 1, count)
+		if assert.Len(t, changes, 1) {
+			assert.Equal(t, titleChange.NewValue, changes[0].OldValue)
+			assert.Equal(t, titleChange.OldValue, changes[0].NewValue)
+		}
+
+		found, err := db.Change.Find(ctx, titleChange.ID)
+		if err != nil {
+			t.Errorf("ChangeStore.Find() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, titleChange, found)
+
+		return nil
+	})
+}
+
+func queryObjectChanges(ctx context.Context, objectType models.ChangeObjectType, id int) ([]*models.Change, error) {
+	changes, _, err := db.Change.Query(ctx, &models.ChangeFilterType{
+		ObjectType: &objectType,
+		ObjectID:   &id,
+	}, nil)
+	return changes, err
+}
+
+func TestChangeLog_Origin(t *testing.T) {
+	tests := []struct {
+		name       string
+		origin     models.ChangeOriginType
+		wantChange bool
+	}{
+		{"user", models.ChangeOriginTypeUser, true},
+		{"auto tag", models.ChangeOriginTypeAutoTag, true},
+		{"scan", models.ChangeOriginTypeScan, false},
+	}
+
+	qb := db.Scene
+	sceneID := sceneIDs[sceneIdxWithGallery]
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			ctx = models.WithChangeOrigin(ctx, models.ChangeOrigin{Type: tt.origin})
+
+			partial := models.NewScenePartial()
+			partial.Details = models.NewOptionalString("changed details")
+			_, err := qb.UpdatePartial(ctx, sceneID, partial)
+			if !assert.NoError(err) {
+				return
+			}
+
+			changes, err := queryObjectChanges(ctx, models.ChangeObjectTypeScene, sceneID)
+			assert.NoError(err)
+
+			if !tt.wantChange {
+				assert.Len(changes, 0)
+				return
+			}
+
+			if assert.Len(changes, 1) {
+				assert.Equal("details", changes[0].Field)
+				assert.Equal(tt.origin, changes[0].OriginType)
+			}
+		})
+	}
+}
+
+func TestChangeLog_UnloadedRelationships(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		assert := assert.New(t)
+		qb := db.Scene
+		sceneID := sceneIDs[sceneIdxWithTag]
+
+		// relationships that are not loaded are not updated, and so must not
+		// be recorded
+		s, err := qb.Find(ctx, sceneID)
+		if !assert.NoError(err) || !assert.NotNil(s) {
+			return nil
+		}
+
+		s.Director = "changed director"
+		if !assert.NoError(qb.Update(ctx, s)) {
+			return nil
+		}
+
+		changes, err := queryObjectChanges(ctx, models.ChangeObjectTypeScene, sceneID)
+		assert.NoError(err)
+		if assert.Len(changes, 1) {
+			assert.Equal("director", changes[0].Field)
+		}
+
+		return nil
+	})
+}
+
+func TestChangeLog_Destroy(t *testing.T) {
+	tests := []struct {
+		name       string
+		objectType models.ChangeObjectType
+		id         int
+		update     func(ctx context.Context, id int) error
+		destroy    func(ctx context.Context, id int) error
+	}{
+		{
+			"scene",
+			models.ChangeObjectTypeScene,
+			sceneIDs[sceneIdxWithGallery],
+			func(ctx context.Context, id int) error {
+				partial := models.NewScenePartial()
+				partial.Title = models.NewOptionalString("changed title")
+				_, err := db.Scene.UpdatePartial(ctx, id, partial)
+				return err
+			},
+			db.Scene.Destroy,
+		},
+		{
+			"performer",
+			models.ChangeObjectTypePerformer,
+			performerIDs[performerIdxWithScene],
+			func(ctx context.Context, id int) error {
+				partial := models.NewPerformerPartial()
+				partial.Details = models.NewOptionalString("changed details")
+				_, err := db.Performer.UpdatePartial(ctx, id, partial)
+				return err
+			},
+			db.Performer.Destroy,
+		},
+	}
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			if !assert.NoError(tt.update(ctx, tt.id)) {
+				return
+			}
+
+			changes, err := queryObjectChanges(ctx, tt.objectType, tt.id)
+			assert.NoError(err)
+			assert.Len(changes, 1)
+
+			// the change history is removed with the object
+			if !assert.NoError(tt.destroy(ctx, tt.id)) {
+				return
+			}
+
+			changes, err = queryObjectChanges(ctx, tt.objectType, tt.id)
+			assert.NoError(err)
+			assert.Len(changes, 0)
+		})
+	}
+}
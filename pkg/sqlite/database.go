@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -30,7 +32,7 @@ const (
 	dbConnTimeout = 30
 )
 
-var appSchemaVersion uint = 64
+var appSchemaVersion uint = 74
 
 //go:embed migrations/*.sql
 var migrationsBox embed.FS
@@ -62,19 +64,27 @@ func (e *MismatchedSchemaVersionError) Error() string {
 }
 
 type storeRepository struct {
-	Blobs          *BlobStore
-	File           *FileStore
-	Folder         *FolderStore
-	Image          *ImageStore
-	Gallery        *GalleryStore
-	GalleryChapter *GalleryChapterStore
-	Scene          *SceneStore
-	SceneMarker    *SceneMarkerStore
-	Performer      *PerformerStore
-	SavedFilter    *SavedFilterStore
-	Studio         *StudioStore
-	Tag            *TagStore
-	Movie          *MovieStore
+	Blobs                  *BlobStore
+	Change                 *ChangeStore
+	File                   *FileStore
+	Folder                 *FolderStore
+	Image                  *ImageStore
+	Gallery                *GalleryStore
+	GalleryChapter         *GalleryChapterStore
+	IdentifyProposal       *IdentifyProposalStore
+	Scene                  *SceneStore
+	SceneMarker            *SceneMarkerStore
+	Performer              *PerformerStore
+	PluginStorage          *PluginStorageStore
+	SavedFilter            *SavedFilterStore
+	StashBoxSubmission     *StashBoxSubmissionStore
+	StashBoxUpdate         *StashBoxUpdateStore
+	AutoTagRule            *AutoTagRuleStore
+	FilenameParserTemplate *FilenameParserTemplateStore
+	Playlist               *PlaylistStore
+	Studio                 *StudioStore
+	Tag                    *TagStore
+	Movie                  *MovieStore
 }
 
 type Database struct {
@@ -85,33 +95,52 @@ type Database struct {
 
 	schemaVersion uint
 
+	// fullTextSearch is true if searches should use the full-text indexes
+	// when available
+	fullTextSearch bool
+	// fullTextSearchEnabled is true if the full-text indexes are present and
+	// maintained in the open database
+	fullTextSearchEnabled atomic.Bool
+
 	lockChan chan struct{}
+
+	commitSubs      []chan struct{}
+	commitSubsMutex sync.Mutex
 }
 
 func NewDatabase() *Database {
 	fileStore := NewFileStore()
 	folderStore := NewFolderStore()
-	galleryStore := NewGalleryStore(fileStore, folderStore)
+	changeStore := NewChangeStore()
+	galleryStore := NewGalleryStore(fileStore, folderStore, changeStore)
 	blobStore := NewBlobStore(BlobStoreOptions{})
-	performerStore := NewPerformerStore(blobStore)
-	studioStore := NewStudioStore(blobStore)
+	performerStore := NewPerformerStore(blobStore, changeStore)
+	studioStore := NewStudioStore(blobStore, changeStore)
 	tagStore := NewTagStore(blobStore)
 
 	r := &storeRepository{}
 	*r = storeRepository{
-		Blobs:          blobStore,
-		File:           fileStore,
-		Folder:         folderStore,
-		Scene:          NewSceneStore(r, blobStore),
-		SceneMarker:    NewSceneMarkerStore(),
-		Image:          NewImageStore(r),
-		Gallery:        galleryStore,
-		GalleryChapter: NewGalleryChapterStore(),
-		Performer:      performerStore,
-		Studio:         studioStore,
-		Tag:            tagStore,
-		Movie:          NewMovieStore(blobStore),
-		SavedFilter:    NewSavedFilterStore(),
+		Blobs:                  blobStore,
+		Change:                 changeStore,
+		File:                   fileStore,
+		Folder:                 folderStore,
+		Scene:                  NewSceneStore(r, blobStore),
+		SceneMarker:            NewSceneMarkerStore(),
+		Image:                  NewImageStore(r),
+		Gallery:                galleryStore,
+		GalleryChapter:         NewGalleryChapterStore(),
+		IdentifyProposal:       NewIdentifyProposalStore(),
+		Performer:              performerStore,
+		Studio:                 studioStore,
+		Tag:                    tagStore,
+		Movie:                  NewMovieStore(blobStore, changeStore),
+		SavedFilter:            NewSavedFilterStore(),
+		PluginStorage:          NewPluginStorageStore(),
+		StashBoxSubmission:     NewStashBoxSubmissionStore(),
+		StashBoxUpdate:         NewStashBoxUpdateStore(),
+		AutoTagRule:            NewAutoTagRuleStore(),
+		FilenameParserTemplate: NewFilenameParserTemplateStore(),
+		Playlist:               NewPlaylistStore(),
 	}
 
 	ret := &Database{
@@ -126,6 +155,17 @@ func (db *Database) SetBlobStoreOptions(options BlobStoreOptions) {
 	*db.Blobs = *NewBlobStore(options)
 }
 
+// SetFullTextSearch sets whether searches use the full-text indexes. It takes
+// effect when the database is next opened.
+func (db *Database) SetFullTextSearch(enabled bool) {
+	db.fullTextSearch = enabled
+}
+
+// FullTextSearchEnabled returns true if searches use the full-text indexes.
+func (db *Database) FullTextSearchEnabled() bool {
+	return db.fullTextSearchEnabled.Load()
+}
+
 // Ready returns an error if the database is not ready to begin transactions.
 func (db *Database) Ready() error {
 	if db.db == nil {
@@ -183,6 +223,10 @@ func (db *Database) Open(dbPath string) error {
 		}
 	}
 
+	if err := db.ensureFullTextIndex(context.Background()); err != nil {
+		return fmt.Errorf("initializing full-text search: %w", err)
+	}
+
 	return nil
 }
 
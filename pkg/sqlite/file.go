@@ -36,6 +36,7 @@ type basicFileRow struct {
 	ParentFolderID models.FolderID `db:"parent_folder_id"`
 	Size           int64           `db:"size"`
 	ModTime        Timestamp       `db:"mod_time"`
+	Metadata       null.String     `db:"metadata"`
 	CreatedAt      Timestamp       `db:"created_at"`
 	UpdatedAt      Timestamp       `db:"updated_at"`
 }
@@ -47,6 +48,10 @@ func (r *basicFileRow) fromBasicFile(o models.BaseFile) {
 	r.ParentFolderID = o.ParentFolderID
 	r.Size = o.Size
 	r.ModTime = Timestamp{Timestamp: o.ModTime}
+	// nil metadata is stored as null so that it is extracted on the next scan
+	if o.Metadata != nil {
+		r.Metadata = null.StringFrom(encodeJSONOrEmpty(o.Metadata))
+	}
 	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
 	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
 }
@@ -173,6 +178,7 @@ type fileQueryRow struct {
 	ParentFolderID null.Int      `db:"parent_folder_id"`
 	Size           null.Int      `db:"size"`
 	ModTime        NullTimestamp `db:"mod_time"`
+	Metadata       null.String   `db:"file_metadata"`
 	CreatedAt      NullTimestamp `db:"file_created_at"`
 	UpdatedAt      NullTimestamp `db:"file_updated_at"`
 
@@ -200,6 +206,11 @@ func (r *fileQueryRow) resolve() models.File {
 		UpdatedAt:      r.UpdatedAt.Timestamp,
 	}
 
+	if r.Metadata.Valid {
+		basic.Metadata = make(map[string]string)
+		decodeJSON(r.Metadata.String, &basic.Metadata)
+	}
+
 	if basic.ZipFileID != nil && r.ZipFolderPath.Valid && r.ZipBasename.Valid {
 		basic.ZipFile = &models.BaseFile{
 			ID:       *basic.ZipFileID,
@@ -454,6 +465,7 @@ func (qb *FileStore) selectDataset() *goqu.SelectDataset {
 		table.Col("parent_folder_id"),
 		table.Col("size"),
 		table.Col("mod_time"),
+		table.Col("metadata").As("file_metadata"),
 		table.Col("created_at").As("file_created_at"),
 		table.Col("updated_at").As("file_updated_at"),
 		folderTable.Col("path").As("parent_folder_path"),
@@ -94,6 +94,10 @@ func Test_fileFileStore_Create(t *testing.T) {
 							Fingerprint: fingerprintValue,
 						},
 					},
+					Metadata: map[string]string{
+						"title":  "title",
+						"artist": "artist",
+					},
 					CreatedAt: createdAt,
 					UpdatedAt: updatedAt,
 				},
@@ -0,0 +1,209 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const filenameParserTemplateTable = "filename_parser_templates"
+
+type filenameParserTemplateRow struct {
+	ID        int       `db:"id" goqu:"skipinsert"`
+	Name      string    `db:"name"`
+	Pattern   string    `db:"pattern"`
+	Options   string    `db:"options"`
+	Paths     string    `db:"paths"`
+	Enabled   bool      `db:"enabled"`
+	CreatedAt Timestamp `db:"created_at"`
+	UpdatedAt Timestamp `db:"updated_at"`
+}
+
+func (r *filenameParserTemplateRow) fromFilenameParserTemplate(o models.FilenameParserTemplate) error {
+	options, err := json.Marshal(o.Options)
+	if err != nil {
+		return fmt.Errorf("encoding options: %w", err)
+	}
+
+	paths := o.Paths
+	if paths == nil {
+		paths = []string{}
+	}
+
+	pathsJSON, err := json.Marshal(paths)
+	if err != nil {
+		return fmt.Errorf("encoding paths: %w", err)
+	}
+
+	r.ID = o.ID
+	r.Name = o.Name
+	r.Pattern = o.Pattern
+	r.Options = string(options)
+	r.Paths = string(pathsJSON)
+	r.Enabled = o.Enabled
+	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
+	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
+
+	return nil
+}
+
+func (r *filenameParserTemplateRow) resolve() (*models.FilenameParserTemplate, error) {
+	ret := &models.FilenameParserTemplate{
+		ID:        r.ID,
+		Name:      r.Name,
+		Pattern:   r.Pattern,
+		Enabled:   r.Enabled,
+		CreatedAt: r.CreatedAt.Timestamp,
+		UpdatedAt: r.UpdatedAt.Timestamp,
+	}
+
+	if err := json.Unmarshal([]byte(r.Options), &ret.Options); err != nil {
+		return nil, fmt.Errorf("decoding options: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(r.Paths), &ret.Paths); err != nil {
+		return nil, fmt.Errorf("decoding paths: %w", err)
+	}
+
+	return ret, nil
+}
+
+// FilenameParserTemplateStore stores the saved scene filename parser templates.
+type FilenameParserTemplateStore struct{}
+
+func NewFilenameParserTemplateStore() *FilenameParserTemplateStore {
+	return &FilenameParserTemplateStore{}
+}
+
+func (qb *FilenameParserTemplateStore) table() exp.IdentifierExpression {
+	return goqu.T(filenameParserTemplateTable)
+}
+
+func (qb *FilenameParserTemplateStore) selectDataset() *goqu.SelectDataset {
+	return dialect.From(qb.table()).Select(qb.table().All())
+}
+
+func (qb *FilenameParserTemplateStore) Create(ctx context.Context, newObject *models.FilenameParserTemplate) error {
+	var r filenameParserTemplateRow
+	if err := r.fromFilenameParserTemplate(*newObject); err != nil {
+		return err
+	}
+
+	q := dialect.Insert(qb.table()).Prepared(true).Rows(r)
+	result, err := exec(ctx, q)
+	if err != nil {
+		return fmt.Errorf("inserting into %s: %w", filenameParserTemplateTable, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	newObject.ID = int(id)
+	return nil
+}
+
+func (qb *FilenameParserTemplateStore) Update(ctx context.Context, updatedObject *models.FilenameParserTemplate) error {
+	var r filenameParserTemplateRow
+	if err := r.fromFilenameParserTemplate(*updatedObject); err != nil {
+		return err
+	}
+
+	table := qb.table()
+	q := dialect.Update(table).Prepared(true).Set(r).Where(table.Col(idColumn).Eq(updatedObject.ID))
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("updating %s: %w", filenameParserTemplateTable, err)
+	}
+
+	return nil
+}
+
+func (qb *FilenameParserTemplateStore) Destroy(ctx context.Context, id int) error {
+	table := qb.table()
+	q := dialect.Delete(table).Where(table.Col(idColumn).Eq(id))
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("deleting from %s: %w", filenameParserTemplateTable, err)
+	}
+
+	return nil
+}
+
+// returns nil, nil if not found
+func (qb *FilenameParserTemplateStore) Find(ctx context.Context, id int) (*models.FilenameParserTemplate, error) {
+	q := qb.selectDataset().Where(qb.table().Col(idColumn).Eq(id))
+
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
+
+func (qb *FilenameParserTemplateStore) FindMany(ctx context.Context, ids []int) ([]*models.FilenameParserTemplate, error) {
+	q := qb.selectDataset().Where(qb.table().Col(idColumn).In(ids))
+
+	unsorted, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*models.FilenameParserTemplate, len(ids))
+	for _, s := range unsorted {
+		for i, id := range ids {
+			if id == s.ID {
+				ret[i] = s
+			}
+		}
+	}
+
+	for i := range ret {
+		if ret[i] == nil {
+			return nil, fmt.Errorf("filename parser template with id %d not found", ids[i])
+		}
+	}
+
+	return ret, nil
+}
+
+func (qb *FilenameParserTemplateStore) All(ctx context.Context) ([]*models.FilenameParserTemplate, error) {
+	q := qb.selectDataset().Order(qb.table().Col(idColumn).Asc())
+
+	return qb.getMany(ctx, q)
+}
+
+func (qb *FilenameParserTemplateStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.FilenameParserTemplate, error) {
+	const single = false
+	var ret []*models.FilenameParserTemplate
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var f filenameParserTemplateRow
+		if err := r.StructScan(&f); err != nil {
+			return err
+		}
+
+		o, err := f.resolve()
+		if err != nil {
+			return err
+		}
+
+		ret = append(ret, o)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("querying %s: %w", filenameParserTemplateTable, err)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,332 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func Test_FilenameParserTemplateStore_Create(t *testing.T) {
+	var (
+		whitespace      = "._"
+		capitalizeTitle = true
+		ignoreOrganized = false
+	)
+
+	full := models.FilenameParserTemplate{
+		Name:    "studio releases",
+		Pattern: "{studio}.{yy}.{mm}.{dd}.{title}.{ext}",
+		Options: models.SceneParserInput{
+			IgnoreWords:          []string{"1080p", "x264"},
+			WhitespaceCharacters: &whitespace,
+			CapitalizeTitle:      &capitalizeTitle,
+			IgnoreOrganized:      &ignoreOrganized,
+		},
+		Paths:     []string{"/videos/releases", "/other"},
+		Enabled:   true,
+		CreatedAt: filenameParserTemplateTime,
+		UpdatedAt: filenameParserTemplateTime,
+	}
+
+	disabled := models.FilenameParserTemplate{
+		Name:      "disabled",
+		Pattern:   "{title}.{ext}",
+		Paths:     []string{"/videos"},
+		CreatedAt: filenameParserTemplateTime,
+		UpdatedAt: filenameParserTemplateTime,
+	}
+
+	noPaths := models.FilenameParserTemplate{
+		Name:      "all scenes",
+		Pattern:   "{title}.{ext}",
+		Enabled:   true,
+		CreatedAt: filenameParserTemplateTime,
+		UpdatedAt: filenameParserTemplateTime,
+	}
+
+	tests := []struct {
+		name      string
+		newObject models.FilenameParserTemplate
+		want      models.FilenameParserTemplate
+	}{
+		{
+			"full",
+			full,
+			full,
+		},
+		{
+			"disabled",
+			disabled,
+			disabled,
+		},
+		{
+			// nil paths are stored as an empty list
+			"nil paths",
+			noPaths,
+			func() models.FilenameParserTemplate {
+				ret := noPaths
+				ret.Paths = []string{}
+				return ret
+			}(),
+		},
+	}
+
+	qb := db.FilenameParserTemplate
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			o := tt.newObject
+			if err := qb.Create(ctx, &o); err != nil {
+				t.Errorf("FilenameParserTemplateStore.Create() error = %v", err)
+				return
+			}
+
+			assert.NotZero(o.ID)
+
+			found, err := qb.Find(ctx, o.ID)
+			if err != nil {
+				t.Errorf("FilenameParserTemplateStore.Find() error = %v", err)
+			}
+
+			if !assert.NotNil(found) {
+				return
+			}
+
+			want := tt.want
+			want.ID = o.ID
+			assert.Equal(want, *found)
+		})
+	}
+}
+
+func Test_FilenameParserTemplateStore_Update(t *testing.T) {
+	capitalizeTitle := false
+
+	tests := []struct {
+		name   string
+		update func(o *models.FilenameParserTemplate)
+		// the paths expected after the update
+		wantPaths []string
+	}{
+		{
+			"disable",
+			func(o *models.FilenameParserTemplate) {
+				o.Enabled = false
+			},
+			[]string{"/videos"},
+		},
+		{
+			"pattern",
+			func(o *models.FilenameParserTemplate) {
+				o.Pattern = "{performer} - {title}.{ext}"
+			},
+			[]string{"/videos"},
+		},
+		{
+			"options",
+			func(o *models.FilenameParserTemplate) {
+				o.Options = models.SceneParserInput{
+					IgnoreWords:     []string{"720p"},
+					CapitalizeTitle: &capitalizeTitle,
+				}
+			},
+			[]string{"/videos"},
+		},
+		{
+			"replace paths",
+			func(o *models.FilenameParserTemplate) {
+				o.Paths = []string{"/other", "/more"}
+			},
+			[]string{"/other", "/more"},
+		},
+		{
+			// the template applies to all scenes once the paths are cleared
+			"clear paths",
+			func(o *models.FilenameParserTemplate) {
+				o.Paths = nil
+			},
+			[]string{},
+		},
+	}
+
+	qb := db.FilenameParserTemplate
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			id := filenameParserTemplateIDs[filenameParserTemplateIdxWithPaths]
+			otherID := filenameParserTemplateIDs[filenameParserTemplateIdxDisabled]
+
+			o, err := qb.Find(ctx, id)
+			if err != nil {
+				t.Errorf("FilenameParserTemplateStore.Find() error = %v", err)
+				return
+			}
+
+			other, err := qb.Find(ctx, otherID)
+			if err != nil {
+				t.Errorf("FilenameParserTemplateStore.Find() error = %v", err)
+				return
+			}
+
+			tt.update(o)
+			if err := qb.Update(ctx, o); err != nil {
+				t.Errorf("FilenameParserTemplateStore.Update() error = %v", err)
+				return
+			}
+
+			got, err := qb.FindMany(ctx, []int{id, otherID})
+			if err != nil {
+				t.Errorf("FilenameParserTemplateStore.FindMany() error = %v", err)
+				return
+			}
+
+			o.Paths = tt.wantPaths
+			assert.Equal(o, got[0])
+
+			// other templates are not changed
+			assert.Equal(other, got[1])
+		})
+	}
+}
+
+func Test_FilenameParserTemplateStore_Destroy(t *testing.T) {
+	tests := []struct {
+		name string
+		id   int
+		// indexes of the remaining templates
+		want []int
+	}{
+		{"with paths", filenameParserTemplateIDs[filenameParserTemplateIdxWithPaths], []int{filenameParserTemplateIdxDisabled}},
+		{"disabled", filenameParserTemplateIDs[filenameParserTemplateIdxDisabled], []int{filenameParserTemplateIdxWithPaths}},
+		{"invalid id", invalidID, []int{filenameParserTemplateIdxWithPaths, filenameParserTemplateIdxDisabled}},
+	}
+
+	qb := db.FilenameParserTemplate
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			if err := qb.Destroy(ctx, tt.id); err != nil {
+				t.Errorf("FilenameParserTemplateStore.Destroy() error = %v", err)
+				return
+			}
+
+			all, err := qb.All(ctx)
+			if err != nil {
+				t.Errorf("FilenameParserTemplateStore.All() error = %v", err)
+				return
+			}
+
+			var gotIDs []int
+			for _, o := range all {
+				gotIDs = append(gotIDs, o.ID)
+			}
+
+			assert.Equal(t, indexesToIDs(filenameParserTemplateIDs, tt.want), gotIDs)
+		})
+	}
+}
+
+func Test_FilenameParserTemplateStore_Find(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       int
+		wantName string
+		wantNil  bool
+	}{
+		{"valid", filenameParserTemplateIDs[filenameParserTemplateIdxDisabled], filenameParserTemplateSpecs[filenameParserTemplateIdxDisabled].name, false},
+		{"invalid", invalidID, "", true},
+	}
+
+	qb := db.FilenameParserTemplate
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			got, err := qb.Find(ctx, tt.id)
+			if err != nil {
+				t.Errorf("FilenameParserTemplateStore.Find() error = %v", err)
+				return
+			}
+
+			if tt.wantNil {
+				assert.Nil(t, got)
+				return
+			}
+
+			if assert.NotNil(t, got) {
+				assert.Equal(t, tt.wantName, got.Name)
+			}
+		})
+	}
+}
+
+func Test_FilenameParserTemplateStore_FindMany(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     []int
+		wantErr bool
+	}{
+		{
+			// returned in the order requested
+			"valid",
+			[]int{filenameParserTemplateIDs[filenameParserTemplateIdxDisabled], filenameParserTemplateIDs[filenameParserTemplateIdxWithPaths]},
+			false,
+		},
+		{
+			"invalid",
+			[]int{filenameParserTemplateIDs[filenameParserTemplateIdxWithPaths], invalidID},
+			true,
+		},
+	}
+
+	qb := db.FilenameParserTemplate
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			got, err := qb.FindMany(ctx, tt.ids)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FilenameParserTemplateStore.FindMany() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			var gotIDs []int
+			for _, o := range got {
+				gotIDs = append(gotIDs, o.ID)
+			}
+			assert.Equal(t, tt.ids, gotIDs)
+		})
+	}
+}
+
+func Test_FilenameParserTemplateStore_All(t *testing.T) {
+	qb := db.FilenameParserTemplate
+
+	// disabled templates are included, in the order they were created
+	runWithRollbackTxn(t, "all", func(t *testing.T, ctx context.Context) {
+		all, err := qb.All(ctx)
+		if err != nil {
+			t.Errorf("FilenameParserTemplateStore.All() error = %v", err)
+			return
+		}
+
+		var gotIDs []int
+		for _, o := range all {
+			gotIDs = append(gotIDs, o.ID)
+		}
+		assert.Equal(t, filenameParserTemplateIDs, gotIDs)
+		assert.Len(t, all, totalFilenameParserTemplates)
+	})
+}
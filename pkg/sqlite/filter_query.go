@@ -0,0 +1,85 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type filterQueryObject struct {
+	table      string
+	nameColumn string
+	// aliasTable and aliasIDColumn are set if the object has aliases
+	aliasTable    string
+	aliasIDColumn string
+}
+
+var filterQueryObjects = map[string]filterQueryObject{
+	"tag":       {tagTable, "name", tagAliasesTable, tagIDColumn},
+	"studio":    {studioTable, "name", studioAliasesTable, studioIDColumn},
+	"performer": {performerTable, "name", performersAliasesTable, performerIDColumn},
+	"movie":     {movieTable, "name", "", ""},
+	"gallery":   {galleryTable, "title", "", ""},
+	"scene":     {sceneTable, "title", "", ""},
+}
+
+// filterQueryResolver resolves the names of related objects in filter queries
+// using the current transaction.
+type filterQueryResolver struct{}
+
+func (filterQueryResolver) FindIDsByName(ctx context.Context, objectType string, name string) ([]string, error) {
+	o, ok := filterQueryObjects[objectType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported object type %q", objectType)
+	}
+
+	query := fmt.Sprintf("SELECT id FROM %s WHERE %s = ? COLLATE NOCASE", o.table, o.nameColumn)
+	args := []interface{}{name}
+	if o.aliasTable != "" {
+		query += fmt.Sprintf(" UNION SELECT %s FROM %s WHERE alias = ? COLLATE NOCASE", o.aliasIDColumn, o.aliasTable)
+		args = append(args, name)
+	}
+
+	var ids []int
+	if err := dbWrapper.Select(ctx, &ids, query, args...); err != nil {
+		return nil, fmt.Errorf("finding %s %q: %w", objectType, name, err)
+	}
+
+	ret := make([]string, len(ids))
+	for i, id := range ids {
+		ret[i] = strconv.Itoa(id)
+	}
+	return ret, nil
+}
+
+// applyFilterQuery combines the filter query of the find filter with the
+// object filter. The free text of the filter query is added to the search
+// query of the returned find filter.
+func applyFilterQuery[T any](ctx context.Context, filter *T, findFilter *models.FindFilterType) (*T, *models.FindFilterType, error) {
+	if findFilter == nil || findFilter.FilterQuery == nil || *findFilter.FilterQuery == "" {
+		return filter, findFilter, nil
+	}
+
+	ret, text, err := models.ParseFilterQuery(ctx, *findFilter.FilterQuery, filter, filterQueryResolver{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	retFindFilter := *findFilter
+	retFindFilter.FilterQuery = nil
+	if text != "" {
+		var q []string
+		if findFilter.Q != nil && *findFilter.Q != "" {
+			q = append(q, *findFilter.Q)
+		}
+		q = append(q, text)
+
+		joined := strings.Join(q, " ")
+		retFindFilter.Q = &joined
+	}
+
+	return ret, &retFindFilter, nil
+}
@@ -0,0 +1,85 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func TestSceneQueryFilterQuery(t *testing.T) {
+	tag2 := getTagStringValue(tagIdx2WithScene, "Name")
+	tag3 := getTagStringValue(tagIdx3WithScene, "Name")
+
+	tests := []struct {
+		name        string
+		filterQuery string
+		includeIdxs []int
+		excludeIdxs []int
+	}{
+		{
+			"name",
+			"tag:" + tag2,
+			[]int{sceneIdxWithTwoTags, sceneIdxWithThreeTags},
+			[]int{sceneIdxWithTag},
+		},
+		{
+			"case insensitive",
+			"tag:" + strings.ToUpper(tag2),
+			[]int{sceneIdxWithTwoTags, sceneIdxWithThreeTags},
+			[]int{sceneIdxWithTag},
+		},
+		{
+			"not",
+			"tag:" + tag2 + " -tag:" + tag3,
+			[]int{sceneIdxWithTwoTags},
+			[]int{sceneIdxWithThreeTags},
+		},
+		{
+			"or",
+			"tag:" + strconv.Itoa(tagIDs[tagIdxWithScene]) + " | tag:" + tag3,
+			[]int{sceneIdxWithTag, sceneIdxWithThreeTags},
+			[]int{sceneIdxWithTwoTags},
+		},
+	}
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			filterQuery := tt.filterQuery
+			scenes := queryScene(ctx, t, db.Scene, nil, &models.FindFilterType{
+				FilterQuery: &filterQuery,
+			})
+
+			ids := scenesToIDs(scenes)
+			for _, idx := range tt.includeIdxs {
+				assert.Contains(t, ids, sceneIDs[idx])
+			}
+			for _, idx := range tt.excludeIdxs {
+				assert.NotContains(t, ids, sceneIDs[idx])
+			}
+		})
+	}
+}
+
+func TestSceneQueryFilterQueryError(t *testing.T) {
+	withTxn(func(ctx context.Context) error {
+		filterQuery := "tag:nonexistent_tag_name"
+		_, err := db.Scene.Query(ctx, models.SceneQueryOptions{
+			QueryOptions: models.QueryOptions{
+				FindFilter: &models.FindFilterType{
+					FilterQuery: &filterQuery,
+				},
+			},
+		})
+		assert.Error(t, err)
+
+		return nil
+	})
+}
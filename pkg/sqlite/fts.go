@@ -0,0 +1,448 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// Full-text search uses SQLite FTS5 virtual tables, which are kept in sync
+// with the object tables using triggers. Full-text search is opt-in, and FTS5
+// is only available if the sqlite3 driver is built with the sqlite_fts5 build
+// tag. If it is not enabled or not available, searches use LIKE clauses.
+
+const (
+	fullTextTableSuffix = "_fts"
+	fullTextTokenizer   = "porter unicode61 remove_diacritics 2"
+
+	// the sort option to sort by full-text search relevance
+	relevanceSort = "relevance"
+)
+
+// fullTextDependency is a table that contains values indexed by a full-text
+// index.
+type fullTextDependency struct {
+	table string
+	// columns are the indexed columns of the table. Updates to rows are
+	// ignored if none of these columns change. Updates are ignored entirely
+	// if empty.
+	columns []string
+	// ids selects the ids of the objects affected by a row change. %[1]s is
+	// replaced with NEW or OLD.
+	ids string
+	// updateOnly is true if inserting or deleting rows cannot affect the
+	// index. This is the case where deleting rows cascades to another
+	// dependency.
+	updateOnly bool
+}
+
+// fullTextIndex is the full-text index of an object table.
+type fullTextIndex struct {
+	table   string
+	columns []string
+	// source selects the object id followed by the values of the columns
+	// from the object table
+	source       string
+	dependencies []fullTextDependency
+}
+
+func (i fullTextIndex) ftsTable() string {
+	return i.table + fullTextTableSuffix
+}
+
+const (
+	fullTextPathSelect = "folders.path || '/' || files.basename"
+)
+
+var (
+	sceneFullTextIndex = fullTextIndex{
+		table:   sceneTable,
+		columns: []string{"title", "code", "details", "director", "paths", "fingerprints", "markers"},
+		source: "SELECT scenes.id, scenes.title, scenes.code, scenes.details, scenes.director, " +
+			"(SELECT group_concat(" + fullTextPathSelect + ", ' ') FROM scenes_files INNER JOIN files ON files.id = scenes_files.file_id INNER JOIN folders ON folders.id = files.parent_folder_id WHERE scenes_files.scene_id = scenes.id), " +
+			"(SELECT group_concat(files_fingerprints.fingerprint, ' ') FROM scenes_files INNER JOIN files_fingerprints ON files_fingerprints.file_id = scenes_files.file_id WHERE scenes_files.scene_id = scenes.id), " +
+			"(SELECT group_concat(scene_markers.title, ' ') FROM scene_markers WHERE scene_markers.scene_id = scenes.id) " +
+			"FROM scenes",
+		dependencies: []fullTextDependency{
+			{table: sceneTable, columns: []string{"title", "code", "details", "director"}, ids: "SELECT %[1]s.id"},
+			{table: scenesFilesTable, ids: "SELECT %[1]s.scene_id"},
+			{table: fileTable, columns: []string{"basename", "parent_folder_id"}, updateOnly: true, ids: "SELECT scene_id FROM scenes_files WHERE file_id = %[1]s.id"},
+			{table: folderTable, columns: []string{"path"}, updateOnly: true, ids: "SELECT scenes_files.scene_id FROM scenes_files INNER JOIN files ON files.id = scenes_files.file_id WHERE files.parent_folder_id = %[1]s.id"},
+			{table: fingerprintTable, columns: []string{"fingerprint"}, ids: "SELECT scene_id FROM scenes_files WHERE file_id = %[1]s.file_id"},
+			{table: sceneMarkerTable, columns: []string{"title", "scene_id"}, ids: "SELECT %[1]s.scene_id"},
+		},
+	}
+
+	imageFullTextIndex = fullTextIndex{
+		table:   imageTable,
+		columns: []string{"title", "code", "details", "photographer", "paths", "fingerprints"},
+		source: "SELECT images.id, images.title, images.code, images.details, images.photographer, " +
+			"(SELECT group_concat(" + fullTextPathSelect + ", ' ') FROM images_files INNER JOIN files ON files.id = images_files.file_id INNER JOIN folders ON folders.id = files.parent_folder_id WHERE images_files.image_id = images.id), " +
+			"(SELECT group_concat(files_fingerprints.fingerprint, ' ') FROM images_files INNER JOIN files_fingerprints ON files_fingerprints.file_id = images_files.file_id WHERE images_files.image_id = images.id) " +
+			"FROM images",
+		dependencies: []fullTextDependency{
+			{table: imageTable, columns: []string{"title", "code", "details", "photographer"}, ids: "SELECT %[1]s.id"},
+			{table: imagesFilesTable, ids: "SELECT %[1]s.image_id"},
+			{table: fileTable, columns: []string{"basename", "parent_folder_id"}, updateOnly: true, ids: "SELECT image_id FROM images_files WHERE file_id = %[1]s.id"},
+			{table: folderTable, columns: []string{"path"}, updateOnly: true, ids: "SELECT images_files.image_id FROM images_files INNER JOIN files ON files.id = images_files.file_id WHERE files.parent_folder_id = %[1]s.id"},
+			{table: fingerprintTable, columns: []string{"fingerprint"}, ids: "SELECT image_id FROM images_files WHERE file_id = %[1]s.file_id"},
+		},
+	}
+
+	galleryFullTextIndex = fullTextIndex{
+		table:   galleryTable,
+		columns: []string{"title", "code", "details", "photographer", "paths", "fingerprints", "chapters"},
+		source: "SELECT galleries.id, galleries.title, galleries.code, galleries.details, galleries.photographer, " +
+			"(SELECT group_concat(path, ' ') FROM (" +
+			"SELECT " + fullTextPathSelect + " AS path FROM galleries_files INNER JOIN files ON files.id = galleries_files.file_id INNER JOIN folders ON folders.id = files.parent_folder_id WHERE galleries_files.gallery_id = galleries.id " +
+			"UNION ALL SELECT folders.path FROM folders WHERE folders.id = galleries.folder_id)), " +
+			"(SELECT group_concat(files_fingerprints.fingerprint, ' ') FROM galleries_files INNER JOIN files_fingerprints ON files_fingerprints.file_id = galleries_files.file_id WHERE galleries_files.gallery_id = galleries.id), " +
+			"(SELECT group_concat(galleries_chapters.title, ' ') FROM galleries_chapters WHERE galleries_chapters.gallery_id = galleries.id) " +
+			"FROM galleries",
+		dependencies: []fullTextDependency{
+			{table: galleryTable, columns: []string{"title", "code", "details", "photographer", "folder_id"}, ids: "SELECT %[1]s.id"},
+			{table: galleriesFilesTable, ids: "SELECT %[1]s.gallery_id"},
+			{table: fileTable, columns: []string{"basename", "parent_folder_id"}, updateOnly: true, ids: "SELECT gallery_id FROM galleries_files WHERE file_id = %[1]s.id"},
+			{table: folderTable, columns: []string{"path"}, updateOnly: true, ids: "SELECT galleries_files.gallery_id FROM galleries_files INNER JOIN files ON files.id = galleries_files.file_id WHERE files.parent_folder_id = %[1]s.id " +
+				"UNION SELECT galleries.id FROM galleries WHERE galleries.folder_id = %[1]s.id"},
+			{table: fingerprintTable, columns: []string{"fingerprint"}, ids: "SELECT gallery_id FROM galleries_files WHERE file_id = %[1]s.file_id"},
+			{table: galleriesChaptersTable, columns: []string{"title", "gallery_id"}, ids: "SELECT %[1]s.gallery_id"},
+		},
+	}
+
+	performerFullTextIndex = fullTextIndex{
+		table:   performerTable,
+		columns: []string{"name", "disambiguation", "aliases", "details"},
+		source: "SELECT performers.id, performers.name, performers.disambiguation, " +
+			"(SELECT group_concat(performer_aliases.alias, ' ') FROM performer_aliases WHERE performer_aliases.performer_id = performers.id), " +
+			"performers.details FROM performers",
+		dependencies: []fullTextDependency{
+			{table: performerTable, columns: []string{"name", "disambiguation", "details"}, ids: "SELECT %[1]s.id"},
+			{table: performersAliasesTable, columns: []string{"alias"}, ids: "SELECT %[1]s.performer_id"},
+		},
+	}
+
+	studioFullTextIndex = fullTextIndex{
+		table:   studioTable,
+		columns: []string{"name", "aliases", "details"},
+		source: "SELECT studios.id, studios.name, " +
+			"(SELECT group_concat(studio_aliases.alias, ' ') FROM studio_aliases WHERE studio_aliases.studio_id = studios.id), " +
+			"studios.details FROM studios",
+		dependencies: []fullTextDependency{
+			{table: studioTable, columns: []string{"name", "details"}, ids: "SELECT %[1]s.id"},
+			{table: studioAliasesTable, columns: []string{"alias"}, ids: "SELECT %[1]s.studio_id"},
+		},
+	}
+
+	tagFullTextIndex = fullTextIndex{
+		table:   tagTable,
+		columns: []string{"name", "aliases", "description"},
+		source: "SELECT tags.id, tags.name, " +
+			"(SELECT group_concat(tag_aliases.alias, ' ') FROM tag_aliases WHERE tag_aliases.tag_id = tags.id), " +
+			"tags.description FROM tags",
+		dependencies: []fullTextDependency{
+			{table: tagTable, columns: []string{"name", "description"}, ids: "SELECT %[1]s.id"},
+			{table: tagAliasesTable, columns: []string{"alias"}, ids: "SELECT %[1]s.tag_id"},
+		},
+	}
+
+	movieFullTextIndex = fullTextIndex{
+		table:   movieTable,
+		columns: []string{"name", "aliases", "director", "synopsis"},
+		source:  "SELECT movies.id, movies.name, movies.aliases, movies.director, movies.synopsis FROM movies",
+		dependencies: []fullTextDependency{
+			{table: movieTable, columns: []string{"name", "aliases", "director", "synopsis"}, ids: "SELECT %[1]s.id"},
+		},
+	}
+
+	sceneMarkerFullTextIndex = fullTextIndex{
+		table:   sceneMarkerTable,
+		columns: []string{"title", "scene", "tags"},
+		source: "SELECT scene_markers.id, scene_markers.title, " +
+			"(SELECT scenes.title FROM scenes WHERE scenes.id = scene_markers.scene_id), " +
+			"(SELECT group_concat(tags.name, ' ') FROM tags WHERE tags.id = scene_markers.primary_tag_id OR tags.id IN (SELECT tag_id FROM scene_markers_tags WHERE scene_marker_id = scene_markers.id)) " +
+			"FROM scene_markers",
+		dependencies: []fullTextDependency{
+			{table: sceneMarkerTable, columns: []string{"title", "scene_id", "primary_tag_id"}, ids: "SELECT %[1]s.id"},
+			{table: sceneMarkersTagsTable, ids: "SELECT %[1]s.scene_marker_id"},
+			{table: sceneTable, columns: []string{"title"}, updateOnly: true, ids: "SELECT id FROM scene_markers WHERE scene_id = %[1]s.id"},
+			{table: tagTable, columns: []string{"name"}, updateOnly: true, ids: "SELECT id FROM scene_markers WHERE primary_tag_id = %[1]s.id " +
+				"UNION SELECT scene_marker_id FROM scene_markers_tags WHERE tag_id = %[1]s.id"},
+		},
+	}
+
+	fullTextIndexes = []fullTextIndex{
+		sceneFullTextIndex,
+		imageFullTextIndex,
+		galleryFullTextIndex,
+		performerFullTextIndex,
+		studioFullTextIndex,
+		tagFullTextIndex,
+		movieFullTextIndex,
+		sceneMarkerFullTextIndex,
+	}
+)
+
+// refreshSQL returns the statements that re-index the objects with ids
+// selected by the provided query.
+func (i fullTextIndex) refreshSQL(ids string) string {
+	return fmt.Sprintf("DELETE FROM %[1]s WHERE rowid IN (%[3]s); INSERT INTO %[1]s (rowid, %[2]s) %[4]s WHERE %[5]s.id IN (%[3]s);",
+		i.ftsTable(), strings.Join(i.columns, ", "), ids, i.source, i.table)
+}
+
+func (i fullTextIndex) triggerName(d fullTextDependency, event string) string {
+	return fmt.Sprintf("%s_%s_%s", i.ftsTable(), d.table, event)
+}
+
+func (i fullTextIndex) createSQL() []string {
+	ret := []string{
+		fmt.Sprintf("CREATE VIRTUAL TABLE %s USING fts5(%s, tokenize = '%s')", i.ftsTable(), strings.Join(i.columns, ", "), fullTextTokenizer),
+	}
+
+	for _, d := range i.dependencies {
+		if !d.updateOnly {
+			ret = append(ret,
+				fmt.Sprintf("CREATE TRIGGER %s AFTER INSERT ON %s BEGIN %s END", i.triggerName(d, "insert"), d.table, i.refreshSQL(fmt.Sprintf(d.ids, "NEW"))),
+				fmt.Sprintf("CREATE TRIGGER %s AFTER DELETE ON %s BEGIN %s END", i.triggerName(d, "delete"), d.table, i.refreshSQL(fmt.Sprintf(d.ids, "OLD"))),
+			)
+		}
+
+		if len(d.columns) > 0 {
+			var changed []string
+			for _, c := range d.columns {
+				changed = append(changed, fmt.Sprintf("OLD.%[1]s IS NOT NEW.%[1]s", c))
+			}
+
+			ids := fmt.Sprintf(d.ids, "OLD") + " UNION " + fmt.Sprintf(d.ids, "NEW")
+			ret = append(ret, fmt.Sprintf("CREATE TRIGGER %s AFTER UPDATE OF %s ON %s WHEN %s BEGIN %s END",
+				i.triggerName(d, "update"), strings.Join(d.columns, ", "), d.table, strings.Join(changed, " OR "), i.refreshSQL(ids)))
+		}
+	}
+
+	// build the index for the existing objects
+	ret = append(ret, fmt.Sprintf("INSERT INTO %s (rowid, %s) %s", i.ftsTable(), strings.Join(i.columns, ", "), i.source))
+
+	return ret
+}
+
+func (i fullTextIndex) dropSQL() []string {
+	ret := []string{
+		"DROP TABLE IF EXISTS " + i.ftsTable(),
+	}
+	return append(ret, i.dropTriggersSQL()...)
+}
+
+func (i fullTextIndex) dropTriggersSQL() []string {
+	var ret []string
+	for _, d := range i.dependencies {
+		for _, event := range []string{"insert", "delete", "update"} {
+			ret = append(ret, "DROP TRIGGER IF EXISTS "+i.triggerName(d, event))
+		}
+	}
+	return ret
+}
+
+func fullTextSearchAvailable(ctx context.Context, db *sqlx.DB) (bool, error) {
+	var ret bool
+	if err := db.GetContext(ctx, &ret, "SELECT sqlite_compileoption_used('ENABLE_FTS5')"); err != nil {
+		return false, err
+	}
+	return ret, nil
+}
+
+// fullTextIndexMaintained returns true if the triggers maintaining the
+// full-text indexes are present. The indexes are only in sync with the object
+// tables while the triggers are present.
+func fullTextIndexMaintained(ctx context.Context, db *sqlx.DB) (bool, error) {
+	var count int
+	if err := db.GetContext(ctx, &count, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name LIKE ?", "%"+fullTextTableSuffix+"_%"); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ensureFullTextIndex creates and builds the full-text indexes if full-text
+// search is enabled and available, and the indexes are not being maintained.
+// If full-text search is disabled, the indexes are dropped so that they are
+// not maintained when writing. If full-text search is not available, the
+// triggers maintaining the indexes are dropped, so that the database can be
+// written to. In either case the indexes are rebuilt once full-text search is
+// enabled and available again.
+func (db *Database) ensureFullTextIndex(ctx context.Context) error {
+	available, err := fullTextSearchAvailable(ctx, db.db)
+	if err != nil {
+		return fmt.Errorf("checking full-text search availability: %w", err)
+	}
+
+	maintained, err := fullTextIndexMaintained(ctx, db.db)
+	if err != nil {
+		return fmt.Errorf("checking full-text indexes: %w", err)
+	}
+
+	enabled := db.fullTextSearch && available
+
+	var stmts []string
+	switch {
+	case enabled && !maintained:
+		logger.Info("Building full-text search indexes...")
+		for _, i := range fullTextIndexes {
+			stmts = append(stmts, i.dropSQL()...)
+			stmts = append(stmts, i.createSQL()...)
+		}
+	case available && !enabled:
+		if maintained {
+			logger.Info("Full-text search is disabled. Dropping full-text search indexes...")
+		}
+		for _, i := range fullTextIndexes {
+			stmts = append(stmts, i.dropSQL()...)
+		}
+	case !available && maintained:
+		logger.Warn("Full-text search is not available in this build. Full-text search indexes will be rebuilt when it is available.")
+		for _, i := range fullTextIndexes {
+			stmts = append(stmts, i.dropTriggersSQL()...)
+		}
+	case !available && db.fullTextSearch:
+		logger.Warn("Full-text search is enabled but is not available in this build")
+	}
+
+	if len(stmts) > 0 {
+		tx, err := db.db.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		for _, stmt := range stmts {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("executing %q: %w", stmt, err)
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	db.fullTextSearchEnabled.Store(enabled)
+	return nil
+}
+
+// fullTextTerm returns the FTS5 query string for a search term. Terms match
+// tokens starting with the term, except for phrases, which must match
+// exactly. Phrases are quoted or contain whitespace, since the quotes are
+// removed from phrases by models.ParseSearchString. It returns an empty string
+// if the term contains no searchable characters.
+func fullTextTerm(t string) string {
+	hasToken := false
+	for _, r := range t {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			hasToken = true
+			break
+		}
+	}
+	if !hasToken {
+		return ""
+	}
+
+	phrase := strings.ContainsFunc(t, unicode.IsSpace)
+	if len(t) > 1 && t[0] == '"' && t[len(t)-1] == '"' {
+		t = t[1 : len(t)-1]
+		phrase = true
+	}
+
+	ret := `"` + strings.ReplaceAll(t, `"`, `""`) + `"`
+	if !phrase {
+		ret += "*"
+	}
+	return ret
+}
+
+// fullTextQuery returns the FTS5 query strings for the provided search
+// string. match must be matched, and each of the notMatch queries must not be
+// matched.
+func fullTextQuery(q string) (match string, notMatch []string) {
+	specs := models.ParseSearchString(q)
+
+	var terms []string
+	for _, t := range specs.MustHave {
+		if ft := fullTextTerm(t); ft != "" {
+			terms = append(terms, ft)
+		}
+	}
+
+	for _, set := range specs.AnySets {
+		var anyTerms []string
+		for _, t := range set {
+			if ft := fullTextTerm(t); ft != "" {
+				anyTerms = append(anyTerms, ft)
+			}
+		}
+
+		if len(anyTerms) > 0 {
+			terms = append(terms, "("+strings.Join(anyTerms, " OR ")+")")
+		}
+	}
+
+	for _, t := range specs.MustNot {
+		if ft := fullTextTerm(t); ft != "" {
+			notMatch = append(notMatch, ft)
+		}
+	}
+
+	return strings.Join(terms, " AND "), notMatch
+}
+
+// useFullTextSearch returns true if searches in the current transaction use
+// the full-text indexes of the database.
+func useFullTextSearch(ctx context.Context) bool {
+	enabled, _ := ctx.Value(fullTextSearchKey).(bool)
+	return enabled
+}
+
+// parseFullTextQuery adds the clauses matching the search string using the
+// full-text index. The full-text table is joined if the search string has
+// terms to match, and can then be used for sorting by relevance.
+func (qb *queryBuilder) parseFullTextQuery(index fullTextIndex, q string) {
+	ftsTable := index.ftsTable()
+	match, notMatch := fullTextQuery(q)
+
+	if match != "" {
+		qb.addJoins(join{
+			table:    ftsTable,
+			onClause: fmt.Sprintf("%s.rowid = %s.id", ftsTable, index.table),
+			joinType: "INNER",
+		})
+		qb.addWhere(ftsTable + " MATCH ?")
+		qb.addArg(match)
+		qb.fullTextTable = ftsTable
+	}
+
+	for _, nm := range notMatch {
+		qb.addWhere(fmt.Sprintf("%s.id NOT IN (SELECT rowid FROM %s WHERE %s MATCH ?)", index.table, ftsTable, ftsTable))
+		qb.addArg(nm)
+	}
+}
+
+// relevanceSort returns the clause sorting by full-text search relevance. The
+// most relevant results are returned first when sorting in descending order.
+// Results are sorted by id if no full-text search was performed.
+func (qb *queryBuilder) relevanceSort(direction string) string {
+	if qb.fullTextTable == "" {
+		return getSort("id", direction, qb.repository.tableName)
+	}
+
+	// rank is lower for more relevant results
+	if getSortDirection(direction) == "DESC" {
+		direction = "ASC"
+	} else {
+		direction = "DESC"
+	}
+
+	return fmt.Sprintf(" ORDER BY %s.rank %s", qb.fullTextTable, direction)
+}
@@ -0,0 +1,32 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_fullTextQuery(t *testing.T) {
+	tests := []struct {
+		q            string
+		wantMatch    string
+		wantNotMatch []string
+	}{
+		{"foo", `"foo"*`, nil},
+		{"foo bar", `"foo"* AND "bar"*`, nil},
+		{`"foo bar"`, `"foo bar"`, nil},
+		{"foo -bar", `"foo"*`, []string{`"bar"*`}},
+		{"-bar", "", []string{`"bar"*`}},
+		{"foo OR bar baz", `"baz"* AND ("foo"* OR "bar"*)`, nil},
+		{"foo - ...", `"foo"*`, nil},
+		{`fo"o`, `"fo""o"*`, nil},
+		{"/media/foo.mp4", `"/media/foo.mp4"*`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.q, func(t *testing.T) {
+			match, notMatch := fullTextQuery(tt.q)
+			assert.Equal(t, tt.wantMatch, match)
+			assert.Equal(t, tt.wantNotMatch, notMatch)
+		})
+	}
+}
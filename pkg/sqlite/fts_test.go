@@ -0,0 +1,217 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+// withFullTextSearchTxn runs f in a transaction of a new database with
+// full-text search enabled. The shared test database does not use full-text
+// search. The test is skipped if full-text search is not available.
+func withFullTextSearchTxn(t *testing.T, f func(ctx context.Context, ftsDB *sqlite.Database)) {
+	t.Helper()
+
+	file, err := os.CreateTemp("", "*.sqlite")
+	if err != nil {
+		t.Fatalf("creating temporary file: %v", err)
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	ftsDB := sqlite.NewDatabase()
+	ftsDB.SetBlobStoreOptions(sqlite.BlobStoreOptions{
+		UseDatabase: true,
+	})
+	ftsDB.SetFullTextSearch(true)
+
+	if err := ftsDB.Open(file.Name()); err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	defer ftsDB.Close()
+
+	if !ftsDB.FullTextSearchEnabled() {
+		t.Skip("full-text search is not available")
+	}
+
+	if err := txn.WithTxn(context.Background(), ftsDB, func(ctx context.Context) error {
+		f(ctx, ftsDB)
+		return nil
+	}); err != nil {
+		t.Errorf("transaction error = %v", err)
+	}
+}
+
+func queryPerformerIDs(ctx context.Context, t *testing.T, qb models.PerformerReader, q string) []int {
+	t.Helper()
+	performers, _, err := qb.Query(ctx, nil, &models.FindFilterType{Q: &q})
+	if err != nil {
+		t.Errorf("PerformerStore.Query() error = %v", err)
+		return nil
+	}
+
+	var ret []int
+	for _, p := range performers {
+		ret = append(ret, p.ID)
+	}
+	return ret
+}
+
+func TestFullTextSearch(t *testing.T) {
+	withFullTextSearchTxn(t, func(ctx context.Context, ftsDB *sqlite.Database) {
+		qb := ftsDB.Performer
+
+		p := models.Performer{
+			Name:    "Quentin Fulltext",
+			Aliases: models.NewRelatedStrings([]string{"Zebulon Quux"}),
+			Details: "Appears in the léger series",
+		}
+		if err := qb.Create(ctx, &p); err != nil {
+			t.Errorf("PerformerStore.Create() error = %v", err)
+			return
+		}
+
+		// prefix of alias
+		assert.Equal(t, []int{p.ID}, queryPerformerIDs(ctx, t, qb, "zebul"))
+		// diacritics are ignored
+		assert.Equal(t, []int{p.ID}, queryPerformerIDs(ctx, t, qb, "leger"))
+		// phrases must match exactly
+		assert.Equal(t, []int{p.ID}, queryPerformerIDs(ctx, t, qb, `"zebulon quux"`))
+		assert.Len(t, queryPerformerIDs(ctx, t, qb, `"quux zebulon"`), 0)
+		// excluded terms
+		assert.Len(t, queryPerformerIDs(ctx, t, qb, "zebulon -fulltext"), 0)
+		assert.Equal(t, []int{p.ID}, queryPerformerIDs(ctx, t, qb, "zebulon | nonexistent"))
+
+		// index is updated with the aliases
+		if _, err := qb.UpdatePartial(ctx, p.ID, models.PerformerPartial{
+			Aliases: &models.UpdateStrings{
+				Values: []string{"Xanthe Quux"},
+				Mode:   models.RelationshipUpdateModeSet,
+			},
+		}); err != nil {
+			t.Errorf("PerformerStore.UpdatePartial() error = %v", err)
+			return
+		}
+
+		assert.Len(t, queryPerformerIDs(ctx, t, qb, "zebulon"), 0)
+		assert.Equal(t, []int{p.ID}, queryPerformerIDs(ctx, t, qb, "xanthe"))
+
+		// index is updated when destroyed
+		if err := qb.Destroy(ctx, p.ID); err != nil {
+			t.Errorf("PerformerStore.Destroy() error = %v", err)
+			return
+		}
+
+		assert.Len(t, queryPerformerIDs(ctx, t, qb, "xanthe"), 0)
+	})
+}
+
+func TestFullTextSearchRelevanceSort(t *testing.T) {
+	withFullTextSearchTxn(t, func(ctx context.Context, ftsDB *sqlite.Database) {
+		qb := ftsDB.Tag
+
+		less := models.Tag{
+			Name:        "Relevance Less",
+			Description: "a long description which mentions wombat only once amongst many other words",
+		}
+		more := models.Tag{
+			Name:        "Wombat",
+			Description: "wombat",
+		}
+		for _, tag := range []*models.Tag{&less, &more} {
+			if err := qb.Create(ctx, tag); err != nil {
+				t.Errorf("TagStore.Create() error = %v", err)
+				return
+			}
+		}
+
+		q := "wombat"
+		sort := "relevance"
+		direction := models.SortDirectionEnumDesc
+		tags, _, err := qb.Query(ctx, nil, &models.FindFilterType{
+			Q:         &q,
+			Sort:      &sort,
+			Direction: &direction,
+		})
+		if err != nil {
+			t.Errorf("TagStore.Query() error = %v", err)
+			return
+		}
+
+		if assert.Len(t, tags, 2) {
+			assert.Equal(t, more.ID, tags[0].ID)
+			assert.Equal(t, less.ID, tags[1].ID)
+		}
+	})
+}
+
+func TestFullTextSearchDisabled(t *testing.T) {
+	file, err := os.CreateTemp("", "*.sqlite")
+	if err != nil {
+		t.Fatalf("creating temporary file: %v", err)
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	open := func(fullTextSearch bool) *sqlite.Database {
+		ret := sqlite.NewDatabase()
+		ret.SetBlobStoreOptions(sqlite.BlobStoreOptions{
+			UseDatabase: true,
+		})
+		ret.SetFullTextSearch(fullTextSearch)
+
+		if err := ret.Open(file.Name()); err != nil {
+			t.Fatalf("opening database: %v", err)
+		}
+		return ret
+	}
+
+	ftsDB := open(true)
+	enabled := ftsDB.FullTextSearchEnabled()
+	ftsDB.Close()
+
+	if !enabled {
+		t.Skip("full-text search is not available")
+	}
+
+	// the indexes are dropped once disabled
+	likeDB := open(false)
+	defer likeDB.Close()
+
+	assert.False(t, likeDB.FullTextSearchEnabled())
+
+	if err := txn.WithTxn(context.Background(), likeDB, func(ctx context.Context) error {
+		qb := likeDB.Tag
+
+		tag := models.Tag{
+			Name: "Disabled Wombat",
+		}
+		if err := qb.Create(ctx, &tag); err != nil {
+			return err
+		}
+
+		// partial words match using LIKE clauses
+		q := "isabled wom"
+		tags, _, err := qb.Query(ctx, nil, &models.FindFilterType{Q: &q})
+		if err != nil {
+			return err
+		}
+
+		if assert.Len(t, tags, 1) {
+			assert.Equal(t, tag.ID, tags[0].ID)
+		}
+
+		return nil
+	}); err != nil {
+		t.Errorf("transaction error = %v", err)
+	}
+}
@@ -185,12 +185,14 @@ type GalleryStore struct {
 
 	fileStore   *FileStore
 	folderStore *FolderStore
+	changeStore *ChangeStore
 }
 
-func NewGalleryStore(fileStore *FileStore, folderStore *FolderStore) *GalleryStore {
+func NewGalleryStore(fileStore *FileStore, folderStore *FolderStore, changeStore *ChangeStore) *GalleryStore {
 	return &GalleryStore{
 		tableMgr:    galleryTableMgr,
 		fileStore:   fileStore,
+		changeStore: changeStore,
 		folderStore: folderStore,
 	}
 }
@@ -278,6 +280,12 @@ func (qb *GalleryStore) Create(ctx context.Context, newObject *models.Gallery, f
 }
 
 func (qb *GalleryStore) Update(ctx context.Context, updatedObject *models.Gallery) error {
+	return qb.changeStore.withChangeLog(ctx, models.ChangeObjectTypeGallery, updatedObject.ID, qb.changeValues(updatedObject.ChangeRelationships()), func() error {
+		return qb.update(ctx, updatedObject)
+	})
+}
+
+func (qb *GalleryStore) update(ctx context.Context, updatedObject *models.Gallery) error {
 	var r galleryRow
 	r.fromGallery(*updatedObject)
 
@@ -321,6 +329,16 @@ func (qb *GalleryStore) Update(ctx context.Context, updatedObject *models.Galler
 }
 
 func (qb *GalleryStore) UpdatePartial(ctx context.Context, id int, partial models.GalleryPartial) (*models.Gallery, error) {
+	var ret *models.Gallery
+	err := qb.changeStore.withChangeLog(ctx, models.ChangeObjectTypeGallery, id, qb.changeValues(partial.ChangeRelationships()), func() error {
+		var err error
+		ret, err = qb.updatePartial(ctx, id, partial)
+		return err
+	})
+	return ret, err
+}
+
+func (qb *GalleryStore) updatePartial(ctx context.Context, id int, partial models.GalleryPartial) (*models.Gallery, error) {
 	r := galleryRowRecord{
 		updateRecord{
 			Record: make(exp.Record),
@@ -366,6 +384,10 @@ func (qb *GalleryStore) UpdatePartial(ctx context.Context, id int, partial model
 }
 
 func (qb *GalleryStore) Destroy(ctx context.Context, id int) error {
+	if err := qb.changeStore.destroyObject(ctx, models.ChangeObjectTypeGallery, id); err != nil {
+		return err
+	}
+
 	return qb.tableMgr.destroyExisting(ctx, []int{id})
 }
 
@@ -442,6 +464,35 @@ func (qb *GalleryStore) find(ctx context.Context, id int) (*models.Gallery, erro
 	return ret, nil
 }
 
+// changeValues returns a function that returns the audited field values of
+// the gallery for the change log. Only the relationships provided are loaded.
+func (qb *GalleryStore) changeValues(relationships []string) changeValuesFunc {
+	return func(ctx context.Context, id int) (models.ChangeValues, error) {
+		ret, err := qb.find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range relationships {
+			switch r {
+			case "urls":
+				err = ret.LoadURLs(ctx, qb)
+			case "scene_ids":
+				err = ret.LoadSceneIDs(ctx, qb)
+			case "performer_ids":
+				err = ret.LoadPerformerIDs(ctx, qb)
+			case "tag_ids":
+				err = ret.LoadTagIDs(ctx, qb)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return ret.ChangeValues(), nil
+	}
+}
+
 func (qb *GalleryStore) findBySubquery(ctx context.Context, sq *goqu.SelectDataset) ([]*models.Gallery, error) {
 	table := qb.table()
 
@@ -679,6 +730,11 @@ func (qb *GalleryStore) All(ctx context.Context) ([]*models.Gallery, error) {
 }
 
 func (qb *GalleryStore) makeQuery(ctx context.Context, galleryFilter *models.GalleryFilterType, findFilter *models.FindFilterType) (*queryBuilder, error) {
+	galleryFilter, findFilter, err := applyFilterQuery(ctx, galleryFilter, findFilter)
+	if err != nil {
+		return nil, err
+	}
+
 	if galleryFilter == nil {
 		galleryFilter = &models.GalleryFilterType{}
 	}
@@ -690,38 +746,42 @@ func (qb *GalleryStore) makeQuery(ctx context.Context, galleryFilter *models.Gal
 	distinctIDs(&query, galleryTable)
 
 	if q := findFilter.Q; q != nil && *q != "" {
-		query.addJoins(
-			join{
-				table:    galleriesFilesTable,
-				onClause: "galleries_files.gallery_id = galleries.id",
-			},
-			join{
-				table:    fileTable,
-				onClause: "galleries_files.file_id = files.id",
-			},
-			join{
-				table:    folderTable,
-				onClause: "files.parent_folder_id = folders.id",
-			},
-			join{
-				table:    fingerprintTable,
-				onClause: "files_fingerprints.file_id = galleries_files.file_id",
-			},
-			join{
-				table:    folderTable,
-				as:       "gallery_folder",
-				onClause: "galleries.folder_id = gallery_folder.id",
-			},
-			join{
-				table:    galleriesChaptersTable,
-				onClause: "galleries_chapters.gallery_id = galleries.id",
-			},
-		)
-
-		// add joins for files and checksum
-		filepathColumn := "folders.path || '" + string(filepath.Separator) + "' || files.basename"
-		searchColumns := []string{"galleries.title", "gallery_folder.path", filepathColumn, "files_fingerprints.fingerprint", "galleries_chapters.title"}
-		query.parseQueryString(searchColumns, *q)
+		if useFullTextSearch(ctx) {
+			query.parseFullTextQuery(galleryFullTextIndex, *q)
+		} else {
+			query.addJoins(
+				join{
+					table:    galleriesFilesTable,
+					onClause: "galleries_files.gallery_id = galleries.id",
+				},
+				join{
+					table:    fileTable,
+					onClause: "galleries_files.file_id = files.id",
+				},
+				join{
+					table:    folderTable,
+					onClause: "files.parent_folder_id = folders.id",
+				},
+				join{
+					table:    fingerprintTable,
+					onClause: "files_fingerprints.file_id = galleries_files.file_id",
+				},
+				join{
+					table:    folderTable,
+					as:       "gallery_folder",
+					onClause: "galleries.folder_id = gallery_folder.id",
+				},
+				join{
+					table:    galleriesChaptersTable,
+					onClause: "galleries_chapters.gallery_id = galleries.id",
+				},
+			)
+
+			// add joins for files and checksum
+			filepathColumn := "folders.path || '" + string(filepath.Separator) + "' || files.basename"
+			searchColumns := []string{"galleries.title", "gallery_folder.path", filepathColumn, "files_fingerprints.fingerprint", "galleries_chapters.title"}
+			query.parseQueryString(searchColumns, *q)
+		}
 	}
 
 	filter := filterBuilderFromHandler(ctx, &galleryFilterHandler{
@@ -779,6 +839,7 @@ var gallerySortOptions = sortOptions{
 	"performer_count",
 	"random",
 	"rating",
+	"relevance",
 	"tag_count",
 	"title",
 	"updated_at",
@@ -825,6 +886,8 @@ func (qb *GalleryStore) setGallerySort(query *queryBuilder, findFilter *models.F
 	}
 
 	switch sort {
+	case relevanceSort:
+		query.sortAndPagination += query.relevanceSort(direction)
 	case "file_count":
 		query.sortAndPagination += getCountSort(galleryTable, galleriesFilesTable, galleryIDColumn, direction)
 	case "images_count":
@@ -146,6 +146,12 @@ func (qb *galleryFilterHandler) criterionHandler() criterionHandler {
 				galleryRepository.tags.innerJoin(f, "gallery_tag", "galleries.id")
 			},
 		},
+
+		&savedFiltersCriterionHandler{
+			criterion: filter.SavedFilters,
+			mode:      models.FilterModeGalleries,
+			idColumn:  galleryTable + ".id",
+		},
 	}
 }
 
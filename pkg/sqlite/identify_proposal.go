@@ -0,0 +1,241 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	identifyProposalTable = "identify_proposals"
+)
+
+type identifyProposalRow struct {
+	ID         int        `db:"id" goqu:"skipinsert"`
+	SceneID    int        `db:"scene_id"`
+	SourceID   string     `db:"source_id"`
+	SourceName string     `db:"source_name"`
+	Confidence null.Float `db:"confidence"`
+	Diffs      string     `db:"diffs"`
+	Data       string     `db:"data"`
+	Status     string     `db:"status"`
+	CreatedAt  Timestamp  `db:"created_at"`
+	UpdatedAt  Timestamp  `db:"updated_at"`
+}
+
+func (r *identifyProposalRow) fromIdentifyProposal(o models.IdentifyProposal) error {
+	diffs := o.Diffs
+	if diffs == nil {
+		diffs = []*models.IdentifyFieldDiff{}
+	}
+	encoded, err := json.Marshal(diffs)
+	if err != nil {
+		return fmt.Errorf("encoding diffs: %w", err)
+	}
+
+	r.ID = o.ID
+	r.SceneID = o.SceneID
+	r.SourceID = o.SourceID
+	r.SourceName = o.SourceName
+	r.Confidence = null.FloatFromPtr(o.Confidence)
+	r.Diffs = string(encoded)
+	r.Data = o.Data
+	r.Status = o.Status.String()
+	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
+	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
+
+	return nil
+}
+
+func (r *identifyProposalRow) resolve() (*models.IdentifyProposal, error) {
+	ret := &models.IdentifyProposal{
+		ID:         r.ID,
+		SceneID:    r.SceneID,
+		SourceID:   r.SourceID,
+		SourceName: r.SourceName,
+		Confidence: r.Confidence.Ptr(),
+		Data:       r.Data,
+		Status:     models.IdentifyProposalStatus(r.Status),
+		CreatedAt:  r.CreatedAt.Timestamp,
+		UpdatedAt:  r.UpdatedAt.Timestamp,
+	}
+
+	if err := json.Unmarshal([]byte(r.Diffs), &ret.Diffs); err != nil {
+		return nil, fmt.Errorf("decoding diffs: %w", err)
+	}
+
+	return ret, nil
+}
+
+// IdentifyProposalStore stores the scene matches found by identify dry runs.
+type IdentifyProposalStore struct{}
+
+func NewIdentifyProposalStore() *IdentifyProposalStore {
+	return &IdentifyProposalStore{}
+}
+
+func (qb *IdentifyProposalStore) table() exp.IdentifierExpression {
+	return goqu.T(identifyProposalTable)
+}
+
+func (qb *IdentifyProposalStore) selectDataset() *goqu.SelectDataset {
+	return dialect.From(qb.table()).Select(qb.table().All())
+}
+
+func (qb *IdentifyProposalStore) Create(ctx context.Context, newObject *models.IdentifyProposal) error {
+	table := qb.table()
+
+	// replace any existing pending proposal for the scene
+	q := dialect.Delete(table).Where(
+		table.Col(sceneIDColumn).Eq(newObject.SceneID),
+		table.Col("status").Eq(models.IdentifyProposalStatusPending.String()),
+	)
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("deleting pending proposals for scene %d: %w", newObject.SceneID, err)
+	}
+
+	var r identifyProposalRow
+	if err := r.fromIdentifyProposal(*newObject); err != nil {
+		return err
+	}
+
+	insert := dialect.Insert(table).Prepared(true).Rows(r)
+	result, err := exec(ctx, insert)
+	if err != nil {
+		return fmt.Errorf("inserting into %s: %w", identifyProposalTable, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	newObject.ID = int(id)
+	return nil
+}
+
+func (qb *IdentifyProposalStore) UpdateStatus(ctx context.Context, id int, status models.IdentifyProposalStatus) error {
+	table := qb.table()
+	q := dialect.Update(table).Prepared(true).Set(goqu.Record{
+		"status":     status.String(),
+		"updated_at": Timestamp{Timestamp: time.Now()},
+	}).Where(table.Col(idColumn).Eq(id))
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("updating %s: %w", identifyProposalTable, err)
+	}
+
+	return nil
+}
+
+// returns nil, nil if not found
+func (qb *IdentifyProposalStore) Find(ctx context.Context, id int) (*models.IdentifyProposal, error) {
+	q := qb.selectDataset().Where(qb.table().Col(idColumn).Eq(id))
+
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
+
+func (qb *IdentifyProposalStore) FindMany(ctx context.Context, ids []int) ([]*models.IdentifyProposal, error) {
+	q := qb.selectDataset().Where(qb.table().Col(idColumn).In(ids))
+
+	unsorted, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*models.IdentifyProposal, len(ids))
+	for _, p := range unsorted {
+		for i, id := range ids {
+			if id == p.ID {
+				ret[i] = p
+			}
+		}
+	}
+
+	for i := range ret {
+		if ret[i] == nil {
+			return nil, fmt.Errorf("identify proposal with id %d not found", ids[i])
+		}
+	}
+
+	return ret, nil
+}
+
+func (qb *IdentifyProposalStore) Query(ctx context.Context, proposalFilter *models.IdentifyProposalFilterType, findFilter *models.FindFilterType) ([]*models.IdentifyProposal, int, error) {
+	if findFilter == nil {
+		findFilter = &models.FindFilterType{}
+	}
+
+	table := qb.table()
+	q := qb.selectDataset()
+
+	if proposalFilter != nil {
+		if proposalFilter.Status != nil {
+			q = q.Where(table.Col("status").Eq(proposalFilter.Status.String()))
+		}
+		if proposalFilter.SceneID != nil {
+			q = q.Where(table.Col(sceneIDColumn).Eq(*proposalFilter.SceneID))
+		}
+		if proposalFilter.SourceID != nil {
+			q = q.Where(table.Col("source_id").Eq(*proposalFilter.SourceID))
+		}
+	}
+
+	total, err := count(ctx, q.Select(goqu.COUNT("*")))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q = q.Order(table.Col(idColumn).Desc())
+	if !findFilter.IsGetAll() {
+		pageSize := findFilter.GetPageSize()
+		q = q.Limit(uint(pageSize)).Offset(uint((findFilter.GetPage() - 1) * pageSize))
+	}
+
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ret, total, nil
+}
+
+func (qb *IdentifyProposalStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.IdentifyProposal, error) {
+	const single = false
+	var ret []*models.IdentifyProposal
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var f identifyProposalRow
+		if err := r.StructScan(&f); err != nil {
+			return err
+		}
+
+		p, err := f.resolve()
+		if err != nil {
+			return err
+		}
+
+		ret = append(ret, p)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("querying %s: %w", identifyProposalTable, err)
+	}
+
+	return ret, nil
+}
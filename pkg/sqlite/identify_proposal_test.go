@@ -0,0 +1,355 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func Test_IdentifyProposalStore_Create(t *testing.T) {
+	confidence := 0.75
+
+	tests := []struct {
+		name      string
+		newObject models.IdentifyProposal
+		// diffs expected after the proposal is stored
+		wantDiffs []*models.IdentifyFieldDiff
+		wantErr   bool
+	}{
+		{
+			"full",
+			models.IdentifyProposal{
+				SceneID:    sceneIDs[sceneIdxWithPerformer],
+				SourceID:   "source",
+				SourceName: "Source",
+				Confidence: &confidence,
+				Diffs: []*models.IdentifyFieldDiff{
+					{Field: "title", OldValue: `""`, NewValue: `"title"`},
+					{Field: "date", OldValue: `null`, NewValue: `"2001-01-01"`},
+				},
+				Data:      `{"title":"title"}`,
+				Status:    models.IdentifyProposalStatusPending,
+				CreatedAt: identifyProposalCreatedAt,
+				UpdatedAt: identifyProposalUpdatedAt,
+			},
+			[]*models.IdentifyFieldDiff{
+				{Field: "title", OldValue: `""`, NewValue: `"title"`},
+				{Field: "date", OldValue: `null`, NewValue: `"2001-01-01"`},
+			},
+			false,
+		},
+		{
+			// nil diffs are stored as an empty list
+			"nil diffs",
+			models.IdentifyProposal{
+				SceneID:    sceneIDs[sceneIdxWithPerformer],
+				SourceID:   "source",
+				SourceName: "Source",
+				Data:       "{}",
+				Status:     models.IdentifyProposalStatusPending,
+				CreatedAt:  identifyProposalCreatedAt,
+				UpdatedAt:  identifyProposalUpdatedAt,
+			},
+			[]*models.IdentifyFieldDiff{},
+			false,
+		},
+		{
+			"invalid scene id",
+			models.IdentifyProposal{
+				SceneID:    invalidID,
+				SourceID:   "source",
+				SourceName: "Source",
+				Data:       "{}",
+				Status:     models.IdentifyProposalStatusPending,
+				CreatedAt:  identifyProposalCreatedAt,
+				UpdatedAt:  identifyProposalUpdatedAt,
+			},
+			nil,
+			true,
+		},
+	}
+
+	qb := db.IdentifyProposal
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			p := tt.newObject
+			if err := qb.Create(ctx, &p); (err != nil) != tt.wantErr {
+				t.Errorf("IdentifyProposalStore.Create() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				assert.Zero(p.ID)
+				return
+			}
+
+			assert.NotZero(p.ID)
+
+			found, err := qb.Find(ctx, p.ID)
+			if err != nil {
+				t.Errorf("IdentifyProposalStore.Find() error = %v", err)
+			}
+
+			if !assert.NotNil(found) {
+				return
+			}
+
+			want := tt.newObject
+			want.ID = p.ID
+			want.Diffs = tt.wantDiffs
+			assert.Equal(want, *found)
+		})
+	}
+}
+
+func Test_IdentifyProposalStore_CreateReplacesPending(t *testing.T) {
+	tests := []struct {
+		name     string
+		idx      int
+		wantKept bool
+	}{
+		{"pending for scene", identifyProposalIdxPending, false},
+		{"rejected for scene", identifyProposalIdxRejected, true},
+		{"pending for other scene", identifyProposalIdxWithOtherScene, true},
+	}
+
+	qb := db.IdentifyProposal
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			p := models.IdentifyProposal{
+				SceneID:    sceneIDs[sceneIdxWithGallery],
+				SourceID:   "new",
+				SourceName: "new",
+				Data:       "{}",
+				Status:     models.IdentifyProposalStatusPending,
+				CreatedAt:  identifyProposalCreatedAt,
+				UpdatedAt:  identifyProposalUpdatedAt,
+			}
+			if err := qb.Create(ctx, &p); err != nil {
+				t.Errorf("IdentifyProposalStore.Create() error = %v", err)
+				return
+			}
+
+			found, err := qb.Find(ctx, identifyProposalIDs[tt.idx])
+			if err != nil {
+				t.Errorf("IdentifyProposalStore.Find() error = %v", err)
+			}
+
+			if tt.wantKept {
+				assert.NotNil(found)
+			} else {
+				assert.Nil(found)
+			}
+		})
+	}
+}
+
+func Test_IdentifyProposalStore_UpdateStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status models.IdentifyProposalStatus
+	}{
+		{"accepted", models.IdentifyProposalStatusAccepted},
+		{"rejected", models.IdentifyProposalStatusRejected},
+	}
+
+	qb := db.IdentifyProposal
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			id := identifyProposalIDs[identifyProposalIdxPending]
+			if err := qb.UpdateStatus(ctx, id, tt.status); err != nil {
+				t.Errorf("IdentifyProposalStore.UpdateStatus() error = %v", err)
+				return
+			}
+
+			found, err := qb.Find(ctx, id)
+			if err != nil {
+				t.Errorf("IdentifyProposalStore.Find() error = %v", err)
+			}
+
+			if !assert.NotNil(found) {
+				return
+			}
+
+			assert.Equal(tt.status, found.Status)
+			assert.True(found.UpdatedAt.After(identifyProposalUpdatedAt))
+			assert.Equal(identifyProposalCreatedAt, found.CreatedAt)
+		})
+	}
+}
+
+func Test_IdentifyProposalStore_Find(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          int
+		wantSceneID int
+		wantNil     bool
+	}{
+		{"valid", identifyProposalIDs[identifyProposalIdxWithOtherScene], sceneIDs[sceneIdxWithTag], false},
+		{"invalid", invalidID, 0, true},
+	}
+
+	qb := db.IdentifyProposal
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			got, err := qb.Find(ctx, tt.id)
+			if err != nil {
+				t.Errorf("IdentifyProposalStore.Find() error = %v", err)
+				return
+			}
+
+			if tt.wantNil {
+				assert.Nil(t, got)
+				return
+			}
+
+			if assert.NotNil(t, got) {
+				assert.Equal(t, tt.wantSceneID, got.SceneID)
+			}
+		})
+	}
+}
+
+func Test_IdentifyProposalStore_FindMany(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     []int
+		wantErr bool
+	}{
+		{
+			"valid",
+			[]int{identifyProposalIDs[identifyProposalIdxWithOtherScene], identifyProposalIDs[identifyProposalIdxRejected]},
+			false,
+		},
+		{
+			"invalid",
+			[]int{identifyProposalIDs[identifyProposalIdxRejected], invalidID},
+			true,
+		},
+	}
+
+	qb := db.IdentifyProposal
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			got, err := qb.FindMany(ctx, tt.ids)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IdentifyProposalStore.FindMany() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			var gotIDs []int
+			for _, p := range got {
+				gotIDs = append(gotIDs, p.ID)
+			}
+			assert.Equal(t, tt.ids, gotIDs)
+		})
+	}
+}
+
+func Test_IdentifyProposalStore_Query(t *testing.T) {
+	var (
+		pending  = models.IdentifyProposalStatusPending
+		rejected = models.IdentifyProposalStatusRejected
+		sceneID  = sceneIDs[sceneIdxWithGallery]
+		sourceID = "second"
+		page     = 2
+		perPage  = 1
+	)
+
+	tests := []struct {
+		name       string
+		filter     *models.IdentifyProposalFilterType
+		findFilter *models.FindFilterType
+		// newest first
+		want      []int
+		wantCount int
+	}{
+		{
+			"all",
+			nil,
+			nil,
+			[]int{identifyProposalIdxWithOtherScene, identifyProposalIdxPending, identifyProposalIdxRejected},
+			totalIdentifyProposals,
+		},
+		{
+			"status",
+			&models.IdentifyProposalFilterType{Status: &rejected},
+			nil,
+			[]int{identifyProposalIdxRejected},
+			1,
+		},
+		{
+			"scene",
+			&models.IdentifyProposalFilterType{SceneID: &sceneID},
+			nil,
+			[]int{identifyProposalIdxPending, identifyProposalIdxRejected},
+			2,
+		},
+		{
+			"source",
+			&models.IdentifyProposalFilterType{SourceID: &sourceID},
+			nil,
+			[]int{identifyProposalIdxWithOtherScene},
+			1,
+		},
+		{
+			"status and scene",
+			&models.IdentifyProposalFilterType{Status: &pending, SceneID: &sceneID},
+			nil,
+			[]int{identifyProposalIdxPending},
+			1,
+		},
+		{
+			"paged",
+			nil,
+			&models.FindFilterType{Page: &page, PerPage: &perPage},
+			[]int{identifyProposalIdxPending},
+			totalIdentifyProposals,
+		},
+	}
+
+	qb := db.IdentifyProposal
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			got, count, err := qb.Query(ctx, tt.filter, tt.findFilter)
+			if err != nil {
+				t.Errorf("IdentifyProposalStore.Query() error = %v", err)
+				return
+			}
+
+			var gotIDs, wantIDs []int
+			for _, p := range got {
+				gotIDs = append(gotIDs, p.ID)
+			}
+			for _, idx := range tt.want {
+				wantIDs = append(wantIDs, identifyProposalIDs[idx])
+			}
+
+			assert.Equal(wantIDs, gotIDs)
+			assert.Equal(tt.wantCount, count)
+		})
+	}
+}
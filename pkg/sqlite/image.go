@@ -284,6 +284,16 @@ func (qb *ImageStore) Create(ctx context.Context, newObject *models.Image, fileI
 }
 
 func (qb *ImageStore) UpdatePartial(ctx context.Context, id int, partial models.ImagePartial) (*models.Image, error) {
+	var ret *models.Image
+	err := qb.repo.Change.withChangeLog(ctx, models.ChangeObjectTypeImage, id, qb.changeValues(partial.ChangeRelationships()), func() error {
+		var err error
+		ret, err = qb.updatePartial(ctx, id, partial)
+		return err
+	})
+	return ret, err
+}
+
+func (qb *ImageStore) updatePartial(ctx context.Context, id int, partial models.ImagePartial) (*models.Image, error) {
 	r := imageRowRecord{
 		updateRecord{
 			Record: make(exp.Record),
@@ -330,6 +340,12 @@ func (qb *ImageStore) UpdatePartial(ctx context.Context, id int, partial models.
 }
 
 func (qb *ImageStore) Update(ctx context.Context, updatedObject *models.Image) error {
+	return qb.repo.Change.withChangeLog(ctx, models.ChangeObjectTypeImage, updatedObject.ID, qb.changeValues(updatedObject.ChangeRelationships()), func() error {
+		return qb.update(ctx, updatedObject)
+	})
+}
+
+func (qb *ImageStore) update(ctx context.Context, updatedObject *models.Image) error {
 	var r imageRow
 	r.fromImage(*updatedObject)
 
@@ -375,6 +391,10 @@ func (qb *ImageStore) Update(ctx context.Context, updatedObject *models.Image) e
 }
 
 func (qb *ImageStore) Destroy(ctx context.Context, id int) error {
+	if err := qb.repo.Change.destroyObject(ctx, models.ChangeObjectTypeImage, id); err != nil {
+		return err
+	}
+
 	return qb.tableMgr.destroyExisting(ctx, []int{id})
 }
 
@@ -428,6 +448,35 @@ func (qb *ImageStore) find(ctx context.Context, id int) (*models.Image, error) {
 	return ret, nil
 }
 
+// changeValues returns a function that returns the audited field values of
+// the image for the change log. Only the relationships provided are loaded.
+func (qb *ImageStore) changeValues(relationships []string) changeValuesFunc {
+	return func(ctx context.Context, id int) (models.ChangeValues, error) {
+		ret, err := qb.find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range relationships {
+			switch r {
+			case "urls":
+				err = ret.LoadURLs(ctx, qb)
+			case "gallery_ids":
+				err = ret.LoadGalleryIDs(ctx, qb)
+			case "performer_ids":
+				err = ret.LoadPerformerIDs(ctx, qb)
+			case "tag_ids":
+				err = ret.LoadTagIDs(ctx, qb)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return ret.ChangeValues(), nil
+	}
+}
+
 func (qb *ImageStore) findBySubquery(ctx context.Context, sq *goqu.SelectDataset) ([]*models.Image, error) {
 	table := qb.table()
 
@@ -706,6 +755,11 @@ func (qb *ImageStore) All(ctx context.Context) ([]*models.Image, error) {
 }
 
 func (qb *ImageStore) makeQuery(ctx context.Context, imageFilter *models.ImageFilterType, findFilter *models.FindFilterType) (*queryBuilder, error) {
+	imageFilter, findFilter, err := applyFilterQuery(ctx, imageFilter, findFilter)
+	if err != nil {
+		return nil, err
+	}
+
 	if imageFilter == nil {
 		imageFilter = &models.ImageFilterType{}
 	}
@@ -717,28 +771,32 @@ func (qb *ImageStore) makeQuery(ctx context.Context, imageFilter *models.ImageFi
 	distinctIDs(&query, imageTable)
 
 	if q := findFilter.Q; q != nil && *q != "" {
-		query.addJoins(
-			join{
-				table:    imagesFilesTable,
-				onClause: "images_files.image_id = images.id",
-			},
-			join{
-				table:    fileTable,
-				onClause: "images_files.file_id = files.id",
-			},
-			join{
-				table:    folderTable,
-				onClause: "files.parent_folder_id = folders.id",
-			},
-			join{
-				table:    fingerprintTable,
-				onClause: "files_fingerprints.file_id = images_files.file_id",
-			},
-		)
+		if useFullTextSearch(ctx) {
+			query.parseFullTextQuery(imageFullTextIndex, *q)
+		} else {
+			query.addJoins(
+				join{
+					table:    imagesFilesTable,
+					onClause: "images_files.image_id = images.id",
+				},
+				join{
+					table:    fileTable,
+					onClause: "images_files.file_id = files.id",
+				},
+				join{
+					table:    folderTable,
+					onClause: "files.parent_folder_id = folders.id",
+				},
+				join{
+					table:    fingerprintTable,
+					onClause: "files_fingerprints.file_id = images_files.file_id",
+				},
+			)
 
-		filepathColumn := "folders.path || '" + string(filepath.Separator) + "' || files.basename"
-		searchColumns := []string{"images.title", filepathColumn, "files_fingerprints.fingerprint"}
-		query.parseQueryString(searchColumns, *q)
+			filepathColumn := "folders.path || '" + string(filepath.Separator) + "' || files.basename"
+			searchColumns := []string{"images.title", filepathColumn, "files_fingerprints.fingerprint"}
+			query.parseQueryString(searchColumns, *q)
+		}
 	}
 
 	filter := filterBuilderFromHandler(ctx, &imageFilterHandler{
@@ -858,6 +916,7 @@ var imageSortOptions = sortOptions{
 	"performer_count",
 	"random",
 	"rating",
+	"relevance",
 	"tag_count",
 	"title",
 	"updated_at",
@@ -901,6 +960,8 @@ func (qb *ImageStore) setImageSortAndPagination(q *queryBuilder, findFilter *mod
 		}
 
 		switch sort {
+		case relevanceSort:
+			sortClause = q.relevanceSort(direction)
 		case "path":
 			addFilesJoin()
 			addFolderJoin()
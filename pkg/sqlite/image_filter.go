@@ -123,6 +123,12 @@ func (qb *imageFilterHandler) criterionHandler() criterionHandler {
 				imageRepository.tags.innerJoin(f, "image_tag", "images.id")
 			},
 		},
+
+		&savedFiltersCriterionHandler{
+			criterion: imageFilter.SavedFilters,
+			mode:      models.FilterModeImages,
+			idColumn:  imageTable + ".id",
+		},
 	}
 }
 
@@ -143,6 +143,10 @@ func (db *Database) ReInitialise() error {
 		return fmt.Errorf("re-initializing the database: %w", err)
 	}
 
+	if err := db.ensureFullTextIndex(context.Background()); err != nil {
+		return fmt.Errorf("initializing full-text search: %w", err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,6 @@
+CREATE TABLE `plugin_storage` (
+  `plugin_id` varchar(255) NOT NULL,
+  `key` varchar(255) NOT NULL,
+  `value` text NOT NULL,
+  PRIMARY KEY(`plugin_id`, `key`)
+);
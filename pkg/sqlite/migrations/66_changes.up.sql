@@ -0,0 +1,12 @@
+CREATE TABLE `changes` (
+  `id` integer not null primary key autoincrement,
+  `object_type` varchar(255) NOT NULL,
+  `object_id` integer NOT NULL,
+  `field` varchar(255) NOT NULL,
+  `old_value` text NOT NULL,
+  `new_value` text NOT NULL,
+  `origin_type` varchar(255) NOT NULL,
+  `origin_id` varchar(255),
+  `created_at` datetime NOT NULL
+);
+CREATE INDEX `index_changes_on_object` ON `changes` (`object_type`, `object_id`);
@@ -0,0 +1,15 @@
+CREATE TABLE `identify_proposals` (
+  `id` integer not null primary key autoincrement,
+  `scene_id` integer NOT NULL,
+  `source_id` varchar(255) NOT NULL,
+  `source_name` varchar(255) NOT NULL,
+  `confidence` real,
+  `diffs` text NOT NULL,
+  `data` text NOT NULL,
+  `status` varchar(255) NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE
+);
+CREATE INDEX `index_identify_proposals_on_scene_id` ON `identify_proposals` (`scene_id`);
+CREATE INDEX `index_identify_proposals_on_status` ON `identify_proposals` (`status`);
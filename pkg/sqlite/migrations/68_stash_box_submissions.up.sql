@@ -0,0 +1,27 @@
+CREATE TABLE `stash_box_submissions` (
+  `id` integer not null primary key autoincrement,
+  `type` varchar(255) NOT NULL,
+  `endpoint` varchar(255) NOT NULL,
+  `scene_id` integer,
+  `performer_id` integer,
+  `status` varchar(255) NOT NULL,
+  `attempts` integer NOT NULL DEFAULT 0,
+  `last_error` text,
+  `remote_id` varchar(255),
+  `next_attempt_at` datetime,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  foreign key(`performer_id`) references `performers`(`id`) on delete CASCADE
+);
+CREATE INDEX `index_stash_box_submissions_on_scene_id` ON `stash_box_submissions` (`scene_id`);
+CREATE INDEX `index_stash_box_submissions_on_performer_id` ON `stash_box_submissions` (`performer_id`);
+CREATE INDEX `index_stash_box_submissions_on_status_next_attempt_at` ON `stash_box_submissions` (`status`, `next_attempt_at`);
+CREATE TABLE `stash_box_submitted_fingerprints` (
+  `endpoint` varchar(255) NOT NULL,
+  `stash_id` varchar(36) NOT NULL,
+  `algorithm` varchar(255) NOT NULL,
+  `hash` varchar(255) NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`endpoint`, `stash_id`, `algorithm`, `hash`)
+);
@@ -0,0 +1,28 @@
+CREATE TABLE `stash_box_updates` (
+  `id` integer not null primary key autoincrement,
+  `object_type` varchar(255) NOT NULL,
+  `object_id` integer NOT NULL,
+  `endpoint` varchar(255) NOT NULL,
+  `stash_id` varchar(36) NOT NULL,
+  `type` varchar(255) NOT NULL,
+  `merged_stash_id` varchar(36),
+  `diffs` text NOT NULL,
+  `status` varchar(255) NOT NULL,
+  `upstream_updated_at` datetime,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL
+);
+CREATE INDEX `index_stash_box_updates_on_object` ON `stash_box_updates` (`object_type`, `object_id`);
+CREATE INDEX `index_stash_box_updates_on_status` ON `stash_box_updates` (`status`);
+
+CREATE TABLE `stash_box_upstream_states` (
+  `object_type` varchar(255) NOT NULL,
+  `object_id` integer NOT NULL,
+  `endpoint` varchar(255) NOT NULL,
+  `stash_id` varchar(36) NOT NULL,
+  `deleted` boolean NOT NULL default '0',
+  `upstream_updated_at` datetime,
+  `checked_at` datetime NOT NULL,
+  PRIMARY KEY (`object_type`, `object_id`, `endpoint`)
+);
+CREATE INDEX `index_stash_box_upstream_states_on_upstream_updated_at` ON `stash_box_upstream_states` (`object_type`, `upstream_updated_at`);
@@ -0,0 +1,9 @@
+CREATE TABLE `auto_tag_rules` (
+  `id` integer not null primary key autoincrement,
+  `name` varchar(255) NOT NULL,
+  `enabled` boolean NOT NULL default '1',
+  `conditions` text NOT NULL,
+  `actions` text NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL
+);
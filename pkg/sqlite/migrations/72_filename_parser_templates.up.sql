@@ -0,0 +1,10 @@
+CREATE TABLE `filename_parser_templates` (
+  `id` integer not null primary key autoincrement,
+  `name` varchar(255) NOT NULL,
+  `pattern` text NOT NULL,
+  `options` text NOT NULL,
+  `paths` text NOT NULL,
+  `enabled` boolean NOT NULL default '1',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL
+);
@@ -0,0 +1,2 @@
+-- full-text search indexes are created when the database is opened, since
+-- they are opt-in and depend on FTS5 being available
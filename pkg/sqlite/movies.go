@@ -129,10 +129,11 @@ type MovieStore struct {
 	blobJoinQueryBuilder
 	tagRelationshipStore
 
-	tableMgr *table
+	tableMgr    *table
+	changeStore *ChangeStore
 }
 
-func NewMovieStore(blobStore *BlobStore) *MovieStore {
+func NewMovieStore(blobStore *BlobStore, changeStore *ChangeStore) *MovieStore {
 	return &MovieStore{
 		blobJoinQueryBuilder: blobJoinQueryBuilder{
 			blobStore: blobStore,
@@ -144,7 +145,8 @@ func NewMovieStore(blobStore *BlobStore) *MovieStore {
 			},
 		},
 
-		tableMgr: movieTableMgr,
+		tableMgr:    movieTableMgr,
+		changeStore: changeStore,
 	}
 }
 
@@ -187,6 +189,16 @@ func (qb *MovieStore) Create(ctx context.Context, newObject *models.Movie) error
 }
 
 func (qb *MovieStore) UpdatePartial(ctx context.Context, id int, partial models.MoviePartial) (*models.Movie, error) {
+	var ret *models.Movie
+	err := qb.changeStore.withChangeLog(ctx, models.ChangeObjectTypeMovie, id, qb.changeValues(partial.ChangeRelationships()), func() error {
+		var err error
+		ret, err = qb.updatePartial(ctx, id, partial)
+		return err
+	})
+	return ret, err
+}
+
+func (qb *MovieStore) updatePartial(ctx context.Context, id int, partial models.MoviePartial) (*models.Movie, error) {
 	r := movieRowRecord{
 		updateRecord{
 			Record: make(exp.Record),
@@ -215,6 +227,12 @@ func (qb *MovieStore) UpdatePartial(ctx context.Context, id int, partial models.
 }
 
 func (qb *MovieStore) Update(ctx context.Context, updatedObject *models.Movie) error {
+	return qb.changeStore.withChangeLog(ctx, models.ChangeObjectTypeMovie, updatedObject.ID, qb.changeValues(updatedObject.ChangeRelationships()), func() error {
+		return qb.update(ctx, updatedObject)
+	})
+}
+
+func (qb *MovieStore) update(ctx context.Context, updatedObject *models.Movie) error {
 	var r movieRow
 	r.fromMovie(*updatedObject)
 
@@ -236,6 +254,10 @@ func (qb *MovieStore) Update(ctx context.Context, updatedObject *models.Movie) e
 }
 
 func (qb *MovieStore) Destroy(ctx context.Context, id int) error {
+	if err := qb.changeStore.destroyObject(ctx, models.ChangeObjectTypeMovie, id); err != nil {
+		return err
+	}
+
 	// must handle image checksums manually
 	if err := qb.destroyImages(ctx, id); err != nil {
 		return err
@@ -295,6 +317,31 @@ func (qb *MovieStore) find(ctx context.Context, id int) (*models.Movie, error) {
 	return ret, nil
 }
 
+// changeValues returns a function that returns the audited field values of
+// the movie for the change log. Only the relationships provided are loaded.
+func (qb *MovieStore) changeValues(relationships []string) changeValuesFunc {
+	return func(ctx context.Context, id int) (models.ChangeValues, error) {
+		ret, err := qb.find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range relationships {
+			switch r {
+			case "urls":
+				err = ret.LoadURLs(ctx, qb)
+			case "tag_ids":
+				err = ret.LoadTagIDs(ctx, qb)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return ret.ChangeValues(), nil
+	}
+}
+
 // returns nil, sql.ErrNoRows if not found
 func (qb *MovieStore) get(ctx context.Context, q *goqu.SelectDataset) (*models.Movie, error) {
 	ret, err := qb.getMany(ctx, q)
@@ -389,6 +436,11 @@ func (qb *MovieStore) All(ctx context.Context) ([]*models.Movie, error) {
 }
 
 func (qb *MovieStore) makeQuery(ctx context.Context, movieFilter *models.MovieFilterType, findFilter *models.FindFilterType) (*queryBuilder, error) {
+	movieFilter, findFilter, err := applyFilterQuery(ctx, movieFilter, findFilter)
+	if err != nil {
+		return nil, err
+	}
+
 	if findFilter == nil {
 		findFilter = &models.FindFilterType{}
 	}
@@ -400,8 +452,12 @@ func (qb *MovieStore) makeQuery(ctx context.Context, movieFilter *models.MovieFi
 	distinctIDs(&query, movieTable)
 
 	if q := findFilter.Q; q != nil && *q != "" {
-		searchColumns := []string{"movies.name", "movies.aliases"}
-		query.parseQueryString(searchColumns, *q)
+		if useFullTextSearch(ctx) {
+			query.parseFullTextQuery(movieFullTextIndex, *q)
+		} else {
+			searchColumns := []string{"movies.name", "movies.aliases"}
+			query.parseQueryString(searchColumns, *q)
+		}
 	}
 
 	filter := filterBuilderFromHandler(ctx, &movieFilterHandler{
@@ -412,8 +468,7 @@ func (qb *MovieStore) makeQuery(ctx context.Context, movieFilter *models.MovieFi
 		return nil, err
 	}
 
-	var err error
-	query.sortAndPagination, err = qb.getMovieSort(findFilter)
+	query.sortAndPagination, err = qb.getMovieSort(&query, findFilter)
 	if err != nil {
 		return nil, err
 	}
@@ -460,11 +515,12 @@ var movieSortOptions = sortOptions{
 	"random",
 	"rating",
 	"scenes_count",
+	"relevance",
 	"tag_count",
 	"updated_at",
 }
 
-func (qb *MovieStore) getMovieSort(findFilter *models.FindFilterType) (string, error) {
+func (qb *MovieStore) getMovieSort(query *queryBuilder, findFilter *models.FindFilterType) (string, error) {
 	var sort string
 	var direction string
 	if findFilter == nil {
@@ -482,6 +538,8 @@ func (qb *MovieStore) getMovieSort(findFilter *models.FindFilterType) (string, e
 
 	sortQuery := ""
 	switch sort {
+	case relevanceSort:
+		sortQuery += query.relevanceSort(direction)
 	case "tag_count":
 		sortQuery += getCountSort(movieTable, moviesTagsTable, movieIDColumn, direction)
 	case "scenes_count": // generic getSort won't work for this
@@ -83,6 +83,12 @@ func (qb *movieFilterHandler) criterionHandler() criterionHandler {
 			relatedRepo:    studioRepository.repository,
 			relatedHandler: &studioFilterHandler{movieFilter.StudiosFilter},
 		},
+
+		&savedFiltersCriterionHandler{
+			criterion: movieFilter.SavedFilters,
+			mode:      models.FilterModeMovies,
+			idColumn:  movieTable + ".id",
+		},
 	}
 }
 
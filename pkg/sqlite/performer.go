@@ -227,16 +227,18 @@ var (
 type PerformerStore struct {
 	blobJoinQueryBuilder
 
-	tableMgr *table
+	tableMgr    *table
+	changeStore *ChangeStore
 }
 
-func NewPerformerStore(blobStore *BlobStore) *PerformerStore {
+func NewPerformerStore(blobStore *BlobStore, changeStore *ChangeStore) *PerformerStore {
 	return &PerformerStore{
 		blobJoinQueryBuilder: blobJoinQueryBuilder{
 			blobStore: blobStore,
 			joinTable: performerTable,
 		},
-		tableMgr: performerTableMgr,
+		tableMgr:    performerTableMgr,
+		changeStore: changeStore,
 	}
 }
 
@@ -293,6 +295,16 @@ func (qb *PerformerStore) Create(ctx context.Context, newObject *models.Performe
 }
 
 func (qb *PerformerStore) UpdatePartial(ctx context.Context, id int, partial models.PerformerPartial) (*models.Performer, error) {
+	var ret *models.Performer
+	err := qb.changeStore.withChangeLog(ctx, models.ChangeObjectTypePerformer, id, qb.changeValues(partial.ChangeRelationships()), func() error {
+		var err error
+		ret, err = qb.updatePartial(ctx, id, partial)
+		return err
+	})
+	return ret, err
+}
+
+func (qb *PerformerStore) updatePartial(ctx context.Context, id int, partial models.PerformerPartial) (*models.Performer, error) {
 	r := performerRowRecord{
 		updateRecord{
 			Record: make(exp.Record),
@@ -334,6 +346,12 @@ func (qb *PerformerStore) UpdatePartial(ctx context.Context, id int, partial mod
 }
 
 func (qb *PerformerStore) Update(ctx context.Context, updatedObject *models.Performer) error {
+	return qb.changeStore.withChangeLog(ctx, models.ChangeObjectTypePerformer, updatedObject.ID, qb.changeValues(updatedObject.ChangeRelationships()), func() error {
+		return qb.update(ctx, updatedObject)
+	})
+}
+
+func (qb *PerformerStore) update(ctx context.Context, updatedObject *models.Performer) error {
 	var r performerRow
 	r.fromPerformer(*updatedObject)
 
@@ -369,6 +387,10 @@ func (qb *PerformerStore) Update(ctx context.Context, updatedObject *models.Perf
 }
 
 func (qb *PerformerStore) Destroy(ctx context.Context, id int) error {
+	if err := qb.changeStore.destroyObject(ctx, models.ChangeObjectTypePerformer, id); err != nil {
+		return err
+	}
+
 	// must handle image checksums manually
 	if err := qb.destroyImage(ctx, id); err != nil {
 		return err
@@ -428,6 +450,35 @@ func (qb *PerformerStore) find(ctx context.Context, id int) (*models.Performer,
 	return ret, nil
 }
 
+// changeValues returns a function that returns the audited field values of
+// the performer for the change log. Only the relationships provided are loaded.
+func (qb *PerformerStore) changeValues(relationships []string) changeValuesFunc {
+	return func(ctx context.Context, id int) (models.ChangeValues, error) {
+		ret, err := qb.find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range relationships {
+			switch r {
+			case "aliases":
+				err = ret.LoadAliases(ctx, qb)
+			case "urls":
+				err = ret.LoadURLs(ctx, qb)
+			case "tag_ids":
+				err = ret.LoadTagIDs(ctx, qb)
+			case "stash_ids":
+				err = ret.LoadStashIDs(ctx, qb)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return ret.ChangeValues(), nil
+	}
+}
+
 func (qb *PerformerStore) findBySubquery(ctx context.Context, sq *goqu.SelectDataset) ([]*models.Performer, error) {
 	table := qb.table()
 
@@ -588,6 +639,11 @@ func (qb *PerformerStore) QueryForAutoTag(ctx context.Context, words []string) (
 }
 
 func (qb *PerformerStore) makeQuery(ctx context.Context, performerFilter *models.PerformerFilterType, findFilter *models.FindFilterType) (*queryBuilder, error) {
+	performerFilter, findFilter, err := applyFilterQuery(ctx, performerFilter, findFilter)
+	if err != nil {
+		return nil, err
+	}
+
 	if performerFilter == nil {
 		performerFilter = &models.PerformerFilterType{}
 	}
@@ -599,9 +655,13 @@ func (qb *PerformerStore) makeQuery(ctx context.Context, performerFilter *models
 	distinctIDs(&query, performerTable)
 
 	if q := findFilter.Q; q != nil && *q != "" {
-		query.join(performersAliasesTable, "", "performer_aliases.performer_id = performers.id")
-		searchColumns := []string{"performers.name", "performer_aliases.alias"}
-		query.parseQueryString(searchColumns, *q)
+		if useFullTextSearch(ctx) {
+			query.parseFullTextQuery(performerFullTextIndex, *q)
+		} else {
+			query.join(performersAliasesTable, "", "performer_aliases.performer_id = performers.id")
+			searchColumns := []string{"performers.name", "performer_aliases.alias"}
+			query.parseQueryString(searchColumns, *q)
+		}
 	}
 
 	filter := filterBuilderFromHandler(ctx, &performerFilterHandler{
@@ -612,8 +672,7 @@ func (qb *PerformerStore) makeQuery(ctx context.Context, performerFilter *models
 		return nil, err
 	}
 
-	var err error
-	query.sortAndPagination, err = qb.getPerformerSort(findFilter)
+	query.sortAndPagination, err = qb.getPerformerSort(&query, findFilter)
 	if err != nil {
 		return nil, err
 	}
@@ -724,11 +783,12 @@ var performerSortOptions = sortOptions{
 	"random",
 	"rating",
 	"scenes_count",
+	"relevance",
 	"tag_count",
 	"updated_at",
 }
 
-func (qb *PerformerStore) getPerformerSort(findFilter *models.FindFilterType) (string, error) {
+func (qb *PerformerStore) getPerformerSort(query *queryBuilder, findFilter *models.FindFilterType) (string, error) {
 	var sort string
 	var direction string
 	if findFilter == nil {
@@ -746,6 +806,8 @@ func (qb *PerformerStore) getPerformerSort(findFilter *models.FindFilterType) (s
 
 	sortQuery := ""
 	switch sort {
+	case relevanceSort:
+		sortQuery += query.relevanceSort(direction)
 	case "tag_count":
 		sortQuery += getCountSort(performerTable, performersTagsTable, performerIDColumn, direction)
 	case "scenes_count":
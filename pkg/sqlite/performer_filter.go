@@ -167,6 +167,7 @@ func (qb *performerFilterHandler) criterionHandler() criterionHandler {
 		&dateCriterionHandler{filter.DeathDate, tableName + ".death_date", nil},
 		&timestampCriterionHandler{filter.CreatedAt, tableName + ".created_at", nil},
 		&timestampCriterionHandler{filter.UpdatedAt, tableName + ".updated_at", nil},
+		upstreamUpdatedAtCriterionHandler(filter.UpstreamUpdatedAt, models.ChangeObjectTypePerformer, tableName+".id"),
 
 		&relatedFilterHandler{
 			relatedIDCol:   "performers_scenes.scene_id",
@@ -203,6 +204,12 @@ func (qb *performerFilterHandler) criterionHandler() criterionHandler {
 				performerRepository.tags.innerJoin(f, "performer_tag", "performers.id")
 			},
 		},
+
+		&savedFiltersCriterionHandler{
+			criterion: filter.SavedFilters,
+			mode:      models.FilterModePerformers,
+			idColumn:  performerTable + ".id",
+		},
 	}
 }
 
@@ -0,0 +1,340 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+	"gopkg.in/guregu/null.v4"
+	"gopkg.in/guregu/null.v4/zero"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	playlistTable      = "playlists"
+	playlistItemsTable = "playlist_items"
+	playlistIDColumn   = "playlist_id"
+)
+
+type playlistRow struct {
+	ID          int         `db:"id" goqu:"skipinsert"`
+	Name        string      `db:"name"`
+	Description zero.String `db:"description"`
+	CreatedAt   Timestamp   `db:"created_at"`
+	UpdatedAt   Timestamp   `db:"updated_at"`
+}
+
+func (r *playlistRow) fromPlaylist(o models.Playlist) {
+	r.ID = o.ID
+	r.Name = o.Name
+	r.Description = zero.StringFrom(o.Description)
+	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
+	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
+}
+
+func (r *playlistRow) resolve() *models.Playlist {
+	return &models.Playlist{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description.String,
+		CreatedAt:   r.CreatedAt.Timestamp,
+		UpdatedAt:   r.UpdatedAt.Timestamp,
+	}
+}
+
+type playlistItemRow struct {
+	ID            int        `db:"id" goqu:"skipinsert"`
+	PlaylistID    int        `db:"playlist_id"`
+	Position      int        `db:"position"`
+	SceneID       null.Int   `db:"scene_id"`
+	SceneMarkerID null.Int   `db:"scene_marker_id"`
+	ImageID       null.Int   `db:"image_id"`
+	EndSeconds    null.Float `db:"end_seconds"`
+}
+
+func (r *playlistItemRow) fromPlaylistItem(playlistID int, position int, o models.PlaylistItem) {
+	r.ID = o.ID
+	r.PlaylistID = playlistID
+	r.Position = position
+	r.SceneID = intFromPtr(o.SceneID)
+	r.SceneMarkerID = intFromPtr(o.SceneMarkerID)
+	r.ImageID = intFromPtr(o.ImageID)
+	r.EndSeconds = null.FloatFromPtr(o.EndSeconds)
+}
+
+func (r *playlistItemRow) resolve() models.PlaylistItem {
+	return models.PlaylistItem{
+		ID:            r.ID,
+		SceneID:       nullIntPtr(r.SceneID),
+		SceneMarkerID: nullIntPtr(r.SceneMarkerID),
+		ImageID:       nullIntPtr(r.ImageID),
+		EndSeconds:    nullFloatPtr(r.EndSeconds),
+	}
+}
+
+// PlaylistStore stores the user-curated playlists and their items.
+type PlaylistStore struct{}
+
+func NewPlaylistStore() *PlaylistStore {
+	return &PlaylistStore{}
+}
+
+func (qb *PlaylistStore) table() exp.IdentifierExpression {
+	return goqu.T(playlistTable)
+}
+
+func (qb *PlaylistStore) itemsTable() exp.IdentifierExpression {
+	return goqu.T(playlistItemsTable)
+}
+
+func (qb *PlaylistStore) selectDataset() *goqu.SelectDataset {
+	return dialect.From(qb.table()).Select(qb.table().All())
+}
+
+func (qb *PlaylistStore) Create(ctx context.Context, newObject *models.Playlist) error {
+	var r playlistRow
+	r.fromPlaylist(*newObject)
+
+	q := dialect.Insert(qb.table()).Prepared(true).Rows(r)
+	result, err := exec(ctx, q)
+	if err != nil {
+		return fmt.Errorf("inserting into %s: %w", playlistTable, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	newObject.ID = int(id)
+	return nil
+}
+
+func (qb *PlaylistStore) Update(ctx context.Context, updatedObject *models.Playlist) error {
+	var r playlistRow
+	r.fromPlaylist(*updatedObject)
+
+	table := qb.table()
+	q := dialect.Update(table).Prepared(true).Set(r).Where(table.Col(idColumn).Eq(updatedObject.ID))
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("updating %s: %w", playlistTable, err)
+	}
+
+	return nil
+}
+
+func (qb *PlaylistStore) Destroy(ctx context.Context, id int) error {
+	table := qb.table()
+	q := dialect.Delete(table).Where(table.Col(idColumn).Eq(id))
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("deleting from %s: %w", playlistTable, err)
+	}
+
+	return nil
+}
+
+// returns nil, nil if not found
+func (qb *PlaylistStore) Find(ctx context.Context, id int) (*models.Playlist, error) {
+	q := qb.selectDataset().Where(qb.table().Col(idColumn).Eq(id))
+
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
+
+func (qb *PlaylistStore) FindMany(ctx context.Context, ids []int) ([]*models.Playlist, error) {
+	q := qb.selectDataset().Where(qb.table().Col(idColumn).In(ids))
+
+	unsorted, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*models.Playlist, len(ids))
+	for _, s := range unsorted {
+		for i, id := range ids {
+			if id == s.ID {
+				ret[i] = s
+			}
+		}
+	}
+
+	for i := range ret {
+		if ret[i] == nil {
+			return nil, fmt.Errorf("playlist with id %d not found", ids[i])
+		}
+	}
+
+	return ret, nil
+}
+
+// returns nil, nil if not found
+func (qb *PlaylistStore) FindByName(ctx context.Context, name string, nocase bool) (*models.Playlist, error) {
+	where := "name = ?"
+	if nocase {
+		where += " COLLATE NOCASE"
+	}
+	q := qb.selectDataset().Prepared(true).Where(goqu.L(where, name)).Limit(1)
+
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
+
+func (qb *PlaylistStore) All(ctx context.Context) ([]*models.Playlist, error) {
+	table := qb.table()
+	q := qb.selectDataset().Order(table.Col("name").Asc(), table.Col(idColumn).Asc())
+
+	return qb.getMany(ctx, q)
+}
+
+func (qb *PlaylistStore) Query(ctx context.Context, findFilter *models.FindFilterType) ([]*models.Playlist, int, error) {
+	if findFilter == nil {
+		findFilter = &models.FindFilterType{}
+	}
+
+	table := qb.table()
+	q := qb.selectDataset().Prepared(true)
+
+	if findFilter.Q != nil && *findFilter.Q != "" {
+		q = q.Where(table.Col("name").Like("%" + *findFilter.Q + "%"))
+	}
+
+	total, err := count(ctx, q.Select(goqu.COUNT("*")))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q = q.Order(table.Col("name").Asc(), table.Col(idColumn).Asc())
+	if !findFilter.IsGetAll() {
+		pageSize := findFilter.GetPageSize()
+		q = q.Limit(uint(pageSize)).Offset(uint((findFilter.GetPage() - 1) * pageSize))
+	}
+
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ret, total, nil
+}
+
+func (qb *PlaylistStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.Playlist, error) {
+	const single = false
+	var ret []*models.Playlist
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var f playlistRow
+		if err := r.StructScan(&f); err != nil {
+			return err
+		}
+
+		ret = append(ret, f.resolve())
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("querying %s: %w", playlistTable, err)
+	}
+
+	return ret, nil
+}
+
+func (qb *PlaylistStore) GetItems(ctx context.Context, playlistID int) ([]models.PlaylistItem, error) {
+	table := qb.itemsTable()
+	q := dialect.From(table).Select(table.All()).Where(table.Col(playlistIDColumn).Eq(playlistID)).Order(table.Col("position").Asc())
+
+	const single = false
+	var ret []models.PlaylistItem
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var f playlistItemRow
+		if err := r.StructScan(&f); err != nil {
+			return err
+		}
+
+		ret = append(ret, f.resolve())
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("querying %s: %w", playlistItemsTable, err)
+	}
+
+	return ret, nil
+}
+
+func (qb *PlaylistStore) UpdateItems(ctx context.Context, playlistID int, items []models.PlaylistItem) error {
+	existing, err := qb.GetItems(ctx, playlistID)
+	if err != nil {
+		return err
+	}
+
+	existingIDs := make(map[int]bool)
+	for _, i := range existing {
+		existingIDs[i.ID] = true
+	}
+
+	keep := make(map[int]bool)
+	for _, i := range items {
+		if err := i.Validate(); err != nil {
+			return err
+		}
+
+		if i.ID != 0 {
+			if !existingIDs[i.ID] {
+				return fmt.Errorf("playlist item %d not found in playlist %d", i.ID, playlistID)
+			}
+			keep[i.ID] = true
+		}
+	}
+
+	table := qb.itemsTable()
+
+	var remove []int
+	for _, i := range existing {
+		if !keep[i.ID] {
+			remove = append(remove, i.ID)
+		}
+	}
+
+	if len(remove) > 0 {
+		q := dialect.Delete(table).Where(table.Col(idColumn).In(remove))
+		if _, err := exec(ctx, q); err != nil {
+			return fmt.Errorf("deleting from %s: %w", playlistItemsTable, err)
+		}
+	}
+
+	for position, i := range items {
+		var r playlistItemRow
+		r.fromPlaylistItem(playlistID, position, i)
+
+		if i.ID == 0 {
+			q := dialect.Insert(table).Prepared(true).Rows(r)
+			if _, err := exec(ctx, q); err != nil {
+				return fmt.Errorf("inserting into %s: %w", playlistItemsTable, err)
+			}
+			continue
+		}
+
+		q := dialect.Update(table).Prepared(true).Set(r).Where(table.Col(idColumn).Eq(i.ID))
+		if _, err := exec(ctx, q); err != nil {
+			return fmt.Errorf("updating %s: %w", playlistItemsTable, err)
+		}
+	}
+
+	return nil
+}
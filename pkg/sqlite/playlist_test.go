@@ -0,0 +1,530 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func Test_PlaylistStore_Create(t *testing.T) {
+	tests := []struct {
+		name      string
+		newObject models.Playlist
+	}{
+		{
+			"with description",
+			models.Playlist{
+				Name:        "with description",
+				Description: "description",
+				CreatedAt:   playlistTime,
+				UpdatedAt:   playlistTime,
+			},
+		},
+		{
+			"no description",
+			models.Playlist{
+				Name:      "no description",
+				CreatedAt: playlistTime,
+				UpdatedAt: playlistTime,
+			},
+		},
+	}
+
+	qb := db.Playlist
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			p := tt.newObject
+			if err := qb.Create(ctx, &p); err != nil {
+				t.Errorf("PlaylistStore.Create() error = %v", err)
+				return
+			}
+
+			assert.NotZero(p.ID)
+
+			found, err := qb.Find(ctx, p.ID)
+			if err != nil {
+				t.Errorf("PlaylistStore.Find() error = %v", err)
+			}
+
+			if !assert.NotNil(found) {
+				return
+			}
+
+			want := tt.newObject
+			want.ID = p.ID
+			assert.Equal(want, *found)
+
+			// new playlists have no items
+			items, err := qb.GetItems(ctx, p.ID)
+			assert.NoError(err)
+			assert.Len(items, 0)
+		})
+	}
+}
+
+func Test_PlaylistStore_Update(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(p *models.Playlist)
+	}{
+		{
+			"rename",
+			func(p *models.Playlist) {
+				p.Name = "renamed"
+			},
+		},
+		{
+			"set description",
+			func(p *models.Playlist) {
+				p.Description = "new description"
+			},
+		},
+		{
+			"clear description",
+			func(p *models.Playlist) {
+				p.Description = ""
+			},
+		},
+	}
+
+	qb := db.Playlist
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			id := playlistIDs[playlistIdxWithItems]
+
+			p, err := qb.Find(ctx, id)
+			if err != nil {
+				t.Errorf("PlaylistStore.Find() error = %v", err)
+				return
+			}
+
+			items, err := qb.GetItems(ctx, id)
+			if err != nil {
+				t.Errorf("PlaylistStore.GetItems() error = %v", err)
+				return
+			}
+
+			tt.update(p)
+			p.UpdatedAt = playlistTime.Add(time.Hour)
+			if err := qb.Update(ctx, p); err != nil {
+				t.Errorf("PlaylistStore.Update() error = %v", err)
+				return
+			}
+
+			found, err := qb.Find(ctx, id)
+			if err != nil {
+				t.Errorf("PlaylistStore.Find() error = %v", err)
+			}
+
+			if !assert.NotNil(found) {
+				return
+			}
+
+			assert.Equal(*p, *found)
+
+			// the items are not changed
+			got, err := qb.GetItems(ctx, id)
+			assert.NoError(err)
+			assert.Equal(items, got)
+		})
+	}
+}
+
+func Test_PlaylistStore_Destroy(t *testing.T) {
+	var (
+		id      = playlistIDs[playlistIdxWithItems]
+		otherID = playlistIDs[playlistIdx1WithItems]
+	)
+
+	qb := db.Playlist
+
+	runWithRollbackTxn(t, "destroy", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+
+		otherItems, err := qb.GetItems(ctx, otherID)
+		if err != nil {
+			t.Errorf("PlaylistStore.GetItems() error = %v", err)
+			return
+		}
+
+		assert.NoError(qb.Destroy(ctx, id))
+
+		found, err := qb.Find(ctx, id)
+		assert.NoError(err)
+		assert.Nil(found)
+
+		// items are removed with the playlist
+		items, err := qb.GetItems(ctx, id)
+		assert.NoError(err)
+		assert.Len(items, 0)
+
+		// other playlists are not changed
+		items, err = qb.GetItems(ctx, otherID)
+		assert.NoError(err)
+		assert.Equal(otherItems, items)
+	})
+
+	runWithRollbackTxn(t, "image destroyed", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+
+		items, err := qb.GetItems(ctx, id)
+		if err != nil {
+			t.Errorf("PlaylistStore.GetItems() error = %v", err)
+			return
+		}
+
+		// items are removed with the object they refer to
+		assert.NoError(db.Image.Destroy(ctx, *items[2].ImageID))
+
+		got, err := qb.GetItems(ctx, id)
+		assert.NoError(err)
+		assert.Equal(items[:2], got)
+	})
+}
+
+func Test_PlaylistStore_FindByName(t *testing.T) {
+	tests := []struct {
+		name   string
+		find   string
+		nocase bool
+		// index of the playlist expected, or -1 if none
+		want int
+	}{
+		{"exact", "Favourite Clips", false, playlistIdxWithItems},
+		{"other case", "favourite CLIPS", false, -1},
+		{"other case nocase", "favourite CLIPS", true, playlistIdxWithItems},
+		{"partial", "Favourite", true, -1},
+		{"not found", "unknown", true, -1},
+	}
+
+	qb := db.Playlist
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			found, err := qb.FindByName(ctx, tt.find, tt.nocase)
+			if err != nil {
+				t.Errorf("PlaylistStore.FindByName() error = %v", err)
+				return
+			}
+
+			if tt.want == -1 {
+				assert.Nil(found)
+				return
+			}
+
+			if assert.NotNil(found) {
+				assert.Equal(playlistIDs[tt.want], found.ID)
+			}
+		})
+	}
+}
+
+func Test_PlaylistStore_FindMany(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     []int
+		wantErr bool
+	}{
+		{
+			// returned in the order requested
+			"valid",
+			[]int{playlistIDs[playlistIdxWithoutItems], playlistIDs[playlistIdxWithItems]},
+			false,
+		},
+		{
+			"invalid",
+			[]int{playlistIDs[playlistIdxWithItems], invalidID},
+			true,
+		},
+	}
+
+	qb := db.Playlist
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			got, err := qb.FindMany(ctx, tt.ids)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PlaylistStore.FindMany() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			var gotIDs []int
+			for _, p := range got {
+				gotIDs = append(gotIDs, p.ID)
+			}
+			assert.Equal(t, tt.ids, gotIDs)
+		})
+	}
+}
+
+func Test_PlaylistStore_All(t *testing.T) {
+	qb := db.Playlist
+
+	runWithRollbackTxn(t, "ordered by name", func(t *testing.T, ctx context.Context) {
+		all, err := qb.All(ctx)
+		if err != nil {
+			t.Errorf("PlaylistStore.All() error = %v", err)
+			return
+		}
+
+		var gotIDs []int
+		for _, p := range all {
+			gotIDs = append(gotIDs, p.ID)
+		}
+
+		want := indexesToIDs(playlistIDs, []int{playlistIdxWithItems, playlistIdxWithoutItems, playlistIdx1WithItems})
+		assert.Equal(t, want, gotIDs)
+	})
+}
+
+func Test_PlaylistStore_Query(t *testing.T) {
+	var (
+		clips   = "clips"
+		unknown = "unknown"
+		page    = 2
+		perPage = 1
+	)
+
+	tests := []struct {
+		name       string
+		findFilter *models.FindFilterType
+		// ordered by name
+		want      []int
+		wantCount int
+	}{
+		{
+			"all",
+			nil,
+			[]int{playlistIdxWithItems, playlistIdxWithoutItems, playlistIdx1WithItems},
+			totalPlaylists,
+		},
+		{
+			"query",
+			&models.FindFilterType{Q: &clips},
+			[]int{playlistIdxWithItems, playlistIdx1WithItems},
+			2,
+		},
+		{
+			"no match",
+			&models.FindFilterType{Q: &unknown},
+			[]int{},
+			0,
+		},
+		{
+			// the count is not limited to the page
+			"paged",
+			&models.FindFilterType{Q: &clips, Page: &page, PerPage: &perPage},
+			[]int{playlistIdx1WithItems},
+			2,
+		},
+	}
+
+	qb := db.Playlist
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			got, count, err := qb.Query(ctx, tt.findFilter)
+			if err != nil {
+				t.Errorf("PlaylistStore.Query() error = %v", err)
+				return
+			}
+
+			gotIDs := []int{}
+			for _, p := range got {
+				gotIDs = append(gotIDs, p.ID)
+			}
+
+			assert.Equal(indexesToIDs(playlistIDs, tt.want), gotIDs)
+			assert.Equal(tt.wantCount, count)
+		})
+	}
+}
+
+func Test_PlaylistStore_UpdateItems(t *testing.T) {
+	var (
+		otherImageID = imageIDs[imageIdxWithTag]
+		sceneID      = sceneIDs[sceneIdxWithMovie]
+		imageID      = imageIDs[imageIdxWithGallery]
+		endSeconds   = 60.0
+	)
+
+	// existing is the scene, scene marker and image items of the playlist.
+	// otherItem is an item of another playlist.
+	tests := []struct {
+		name    string
+		items   func(existing []models.PlaylistItem, otherItem models.PlaylistItem) []models.PlaylistItem
+		wantErr bool
+	}{
+		{
+			"unchanged",
+			func(existing []models.PlaylistItem, otherItem models.PlaylistItem) []models.PlaylistItem {
+				return existing
+			},
+			false,
+		},
+		{
+			"reorder",
+			func(existing []models.PlaylistItem, otherItem models.PlaylistItem) []models.PlaylistItem {
+				return []models.PlaylistItem{existing[2], existing[0], existing[1]}
+			},
+			false,
+		},
+		{
+			"add and remove",
+			func(existing []models.PlaylistItem, otherItem models.PlaylistItem) []models.PlaylistItem {
+				return []models.PlaylistItem{{ImageID: &otherImageID}, existing[2], existing[1]}
+			},
+			false,
+		},
+		{
+			// the same object may be in the playlist more than once
+			"duplicate object",
+			func(existing []models.PlaylistItem, otherItem models.PlaylistItem) []models.PlaylistItem {
+				return append(existing, models.PlaylistItem{SceneID: &sceneID})
+			},
+			false,
+		},
+		{
+			"update end seconds",
+			func(existing []models.PlaylistItem, otherItem models.PlaylistItem) []models.PlaylistItem {
+				existing[1].EndSeconds = &endSeconds
+				return existing
+			},
+			false,
+		},
+		{
+			"clear end seconds",
+			func(existing []models.PlaylistItem, otherItem models.PlaylistItem) []models.PlaylistItem {
+				existing[1].EndSeconds = nil
+				return existing
+			},
+			false,
+		},
+		{
+			"clear",
+			func(existing []models.PlaylistItem, otherItem models.PlaylistItem) []models.PlaylistItem {
+				return nil
+			},
+			false,
+		},
+		{
+			"item of other playlist",
+			func(existing []models.PlaylistItem, otherItem models.PlaylistItem) []models.PlaylistItem {
+				return append(existing, otherItem)
+			},
+			true,
+		},
+		{
+			"multiple objects",
+			func(existing []models.PlaylistItem, otherItem models.PlaylistItem) []models.PlaylistItem {
+				return append(existing[1:], models.PlaylistItem{SceneID: &sceneID, ImageID: &imageID})
+			},
+			true,
+		},
+		{
+			"no object",
+			func(existing []models.PlaylistItem, otherItem models.PlaylistItem) []models.PlaylistItem {
+				return append(existing[1:], models.PlaylistItem{})
+			},
+			true,
+		},
+		{
+			"end seconds for scene",
+			func(existing []models.PlaylistItem, otherItem models.PlaylistItem) []models.PlaylistItem {
+				existing[0].EndSeconds = &endSeconds
+				return existing
+			},
+			true,
+		},
+		{
+			"invalid scene id",
+			func(existing []models.PlaylistItem, otherItem models.PlaylistItem) []models.PlaylistItem {
+				return append(existing, models.PlaylistItem{SceneID: &invalidID})
+			},
+			true,
+		},
+	}
+
+	qb := db.Playlist
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			id := playlistIDs[playlistIdxWithItems]
+			otherID := playlistIDs[playlistIdx1WithItems]
+
+			existing, err := qb.GetItems(ctx, id)
+			if err != nil {
+				t.Errorf("PlaylistStore.GetItems() error = %v", err)
+				return
+			}
+
+			otherItems, err := qb.GetItems(ctx, otherID)
+			if err != nil {
+				t.Errorf("PlaylistStore.GetItems() error = %v", err)
+				return
+			}
+
+			items := tt.items(append([]models.PlaylistItem(nil), existing...), otherItems[0])
+			if err := qb.UpdateItems(ctx, id, items); (err != nil) != tt.wantErr {
+				t.Errorf("PlaylistStore.UpdateItems() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				// the existing items are not changed
+				got, err := qb.GetItems(ctx, id)
+				assert.NoError(err)
+				assert.Equal(existing, got)
+				return
+			}
+
+			got, err := qb.GetItems(ctx, id)
+			if err != nil {
+				t.Errorf("PlaylistStore.GetItems() error = %v", err)
+				return
+			}
+
+			if !assert.Len(got, len(items)) {
+				return
+			}
+
+			// existing items keep their ids, new items are assigned one
+			want := append([]models.PlaylistItem(nil), items...)
+			for i := range want {
+				if want[i].ID == 0 {
+					assert.NotZero(got[i].ID)
+					want[i].ID = got[i].ID
+				}
+			}
+			assert.Equal(want, got)
+
+			// other playlists are not changed
+			got, err = qb.GetItems(ctx, otherID)
+			assert.NoError(err)
+			assert.Equal(otherItems, got)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	pluginStorageTable          = "plugin_storage"
+	pluginStoragePluginIDColumn = "plugin_id"
+	pluginStorageKeyColumn      = "key"
+	pluginStorageValueColumn    = "value"
+)
+
+type pluginStorageRow struct {
+	PluginID string `db:"plugin_id"`
+	Key      string `db:"key"`
+	Value    string `db:"value"`
+}
+
+// PluginStorageStore stores key-value pairs on behalf of plugins.
+// Keys are scoped to the plugin ID.
+type PluginStorageStore struct{}
+
+func NewPluginStorageStore() *PluginStorageStore {
+	return &PluginStorageStore{}
+}
+
+func (qb *PluginStorageStore) table() exp.IdentifierExpression {
+	return goqu.T(pluginStorageTable)
+}
+
+func (qb *PluginStorageStore) byKey(pluginID string, key string) exp.Expression {
+	table := qb.table()
+	return goqu.And(
+		table.Col(pluginStoragePluginIDColumn).Eq(pluginID),
+		table.Col(pluginStorageKeyColumn).Eq(key),
+	)
+}
+
+// Get returns the value stored for the plugin and key.
+// Returns nil if no value is stored.
+func (qb *PluginStorageStore) Get(ctx context.Context, pluginID string, key string) (*string, error) {
+	table := qb.table()
+	q := dialect.From(table).Select(table.Col(pluginStorageValueColumn)).Where(qb.byKey(pluginID, key))
+
+	var ret *string
+	const single = true
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var v string
+		if err := r.Scan(&v); err != nil {
+			return err
+		}
+
+		ret = &v
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("querying %s: %w", pluginStorageTable, err)
+	}
+
+	return ret, nil
+}
+
+// Set stores the value for the plugin and key, replacing any existing value.
+func (qb *PluginStorageStore) Set(ctx context.Context, pluginID string, key string, value string) error {
+	table := qb.table()
+	q := dialect.Insert(table).Prepared(true).Rows(pluginStorageRow{
+		PluginID: pluginID,
+		Key:      key,
+		Value:    value,
+	}).OnConflict(goqu.DoUpdate(
+		pluginStoragePluginIDColumn+", "+pluginStorageKeyColumn,
+		goqu.Record{pluginStorageValueColumn: value},
+	))
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("setting %s: %w", pluginStorageTable, err)
+	}
+
+	return nil
+}
+
+// Delete removes the value stored for the plugin and key.
+// Does nothing if no value is stored.
+func (qb *PluginStorageStore) Delete(ctx context.Context, pluginID string, key string) error {
+	q := dialect.Delete(qb.table()).Where(qb.byKey(pluginID, key))
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("deleting from %s: %w", pluginStorageTable, err)
+	}
+
+	return nil
+}
+
+// Keys returns the keys stored for the plugin, in ascending order.
+func (qb *PluginStorageStore) Keys(ctx context.Context, pluginID string) ([]string, error) {
+	table := qb.table()
+	q := dialect.From(table).Select(table.Col(pluginStorageKeyColumn)).
+		Where(table.Col(pluginStoragePluginIDColumn).Eq(pluginID)).
+		Order(table.Col(pluginStorageKeyColumn).Asc())
+
+	var ret []string
+	const single = false
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var v string
+		if err := r.Scan(&v); err != nil {
+			return err
+		}
+
+		ret = append(ret, v)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("querying %s: %w", pluginStorageTable, err)
+	}
+
+	return ret, nil
+}
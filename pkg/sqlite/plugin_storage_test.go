@@ -0,0 +1,63 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginStorage(t *testing.T) {
+	const (
+		pluginID      = "testPlugin"
+		otherPluginID = "otherPlugin"
+		key           = "key"
+		otherKey      = "anotherKey"
+	)
+
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.PluginStorage
+
+		v, err := qb.Get(ctx, pluginID, key)
+		assert.Nil(t, err)
+		assert.Nil(t, v)
+
+		assert.Nil(t, qb.Set(ctx, pluginID, key, "value"))
+		assert.Nil(t, qb.Set(ctx, pluginID, otherKey, "other"))
+		assert.Nil(t, qb.Set(ctx, otherPluginID, key, "otherPlugin"))
+
+		// replace existing value
+		assert.Nil(t, qb.Set(ctx, pluginID, key, "newValue"))
+
+		v, err = qb.Get(ctx, pluginID, key)
+		assert.Nil(t, err)
+		if assert.NotNil(t, v) {
+			assert.Equal(t, "newValue", *v)
+		}
+
+		v, err = qb.Get(ctx, otherPluginID, key)
+		assert.Nil(t, err)
+		if assert.NotNil(t, v) {
+			assert.Equal(t, "otherPlugin", *v)
+		}
+
+		keys, err := qb.Keys(ctx, pluginID)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{otherKey, key}, keys)
+
+		assert.Nil(t, qb.Delete(ctx, pluginID, key))
+
+		v, err = qb.Get(ctx, pluginID, key)
+		assert.Nil(t, err)
+		assert.Nil(t, v)
+
+		keys, err = qb.Keys(ctx, otherPluginID)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{key}, keys)
+
+		return nil
+	})
+}
@@ -21,6 +21,9 @@ type queryBuilder struct {
 	withClauses   []string
 	recursiveWith bool
 
+	// fullTextTable is the joined full-text search table, if any
+	fullTextTable string
+
 	sortAndPagination string
 }
 
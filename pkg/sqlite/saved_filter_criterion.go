@@ -0,0 +1,181 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// savedFilterFindFilter returns the find filter used to evaluate the saved
+// filter. Only the search query of the saved find filter is used.
+func savedFilterFindFilter(savedFilter *models.SavedFilter) *models.FindFilterType {
+	ret := &models.FindFilterType{}
+	if savedFilter.FindFilter != nil {
+		ret.Q = savedFilter.FindFilter.Q
+		ret.FilterQuery = savedFilter.FindFilter.FilterQuery
+	}
+	return ret
+}
+
+// savedFilterQuery returns the query selecting the ids of the objects matching
+// the saved filter.
+func savedFilterQuery(ctx context.Context, savedFilter *models.SavedFilter) (*queryBuilder, error) {
+	findFilter := savedFilterFindFilter(savedFilter)
+	objectFilter := savedFilter.ObjectFilter
+
+	switch savedFilter.Mode {
+	case models.FilterModeScenes:
+		filter, err := models.DecodeSavedObjectFilter[models.SceneFilterType](objectFilter)
+		if err != nil {
+			return nil, err
+		}
+		return (&SceneStore{}).makeQuery(ctx, filter, findFilter)
+	case models.FilterModeImages:
+		filter, err := models.DecodeSavedObjectFilter[models.ImageFilterType](objectFilter)
+		if err != nil {
+			return nil, err
+		}
+		return (&ImageStore{}).makeQuery(ctx, filter, findFilter)
+	case models.FilterModeGalleries:
+		filter, err := models.DecodeSavedObjectFilter[models.GalleryFilterType](objectFilter)
+		if err != nil {
+			return nil, err
+		}
+		return (&GalleryStore{}).makeQuery(ctx, filter, findFilter)
+	case models.FilterModePerformers:
+		filter, err := models.DecodeSavedObjectFilter[models.PerformerFilterType](objectFilter)
+		if err != nil {
+			return nil, err
+		}
+		return (&PerformerStore{}).makeQuery(ctx, filter, findFilter)
+	case models.FilterModeStudios:
+		filter, err := models.DecodeSavedObjectFilter[models.StudioFilterType](objectFilter)
+		if err != nil {
+			return nil, err
+		}
+		return (&StudioStore{}).makeQuery(ctx, filter, findFilter)
+	case models.FilterModeTags:
+		filter, err := models.DecodeSavedObjectFilter[models.TagFilterType](objectFilter)
+		if err != nil {
+			return nil, err
+		}
+		return (&TagStore{}).makeQuery(ctx, filter, findFilter)
+	case models.FilterModeMovies:
+		filter, err := models.DecodeSavedObjectFilter[models.MovieFilterType](objectFilter)
+		if err != nil {
+			return nil, err
+		}
+		return (&MovieStore{}).makeQuery(ctx, filter, findFilter)
+	case models.FilterModeSceneMarkers:
+		filter, err := models.DecodeSavedObjectFilter[models.SceneMarkerFilterType](objectFilter)
+		if err != nil {
+			return nil, err
+		}
+		return (&SceneMarkerStore{}).makeQuery(ctx, filter, findFilter)
+	}
+
+	return nil, fmt.Errorf("unsupported saved filter mode %q", savedFilter.Mode)
+}
+
+// savedFiltersCriterionHandler filters objects by whether they match saved
+// filters of the same mode.
+type savedFiltersCriterionHandler struct {
+	criterion *models.MultiCriterionInput
+	mode      models.FilterMode
+	// idColumn is the id column of the filtered objects
+	idColumn string
+}
+
+// savedFilterClause returns the clause matching the objects of the saved
+// filter. Saved filters referring to themselves, directly or through other
+// saved filters, return an error.
+func (h *savedFiltersCriterionHandler) savedFilterClause(ctx context.Context, id string, not bool) (string, []interface{}, error) {
+	savedFilterID, err := strconv.Atoi(id)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid saved filter id %q: %w", id, err)
+	}
+
+	visited, _ := ctx.Value(savedFiltersKey).([]int)
+	for _, v := range visited {
+		if v == savedFilterID {
+			return "", nil, fmt.Errorf("saved filter %d refers to itself", savedFilterID)
+		}
+	}
+
+	savedFilter, err := NewSavedFilterStore().Find(ctx, savedFilterID)
+	if err != nil {
+		return "", nil, fmt.Errorf("finding saved filter %d: %w", savedFilterID, err)
+	}
+	if savedFilter == nil {
+		return "", nil, fmt.Errorf("saved filter %d not found", savedFilterID)
+	}
+	if savedFilter.Mode != h.mode {
+		return "", nil, fmt.Errorf("saved filter %d has mode %s, expected %s", savedFilterID, savedFilter.Mode, h.mode)
+	}
+
+	// copy the visited ids so that sibling criteria are unaffected
+	visited = append(visited[:len(visited):len(visited)], savedFilterID)
+	ctx = context.WithValue(ctx, savedFiltersKey, visited)
+
+	query, err := savedFilterQuery(ctx, savedFilter)
+	if err != nil {
+		return "", nil, fmt.Errorf("saved filter %d: %w", savedFilterID, err)
+	}
+
+	op := "IN"
+	if not {
+		op = "NOT IN"
+	}
+
+	return fmt.Sprintf("%s %s (%s)", h.idColumn, op, query.toSQL(false)), query.args, nil
+}
+
+func (h *savedFiltersCriterionHandler) handle(ctx context.Context, f *filterBuilder) {
+	c := h.criterion
+	if c == nil {
+		return
+	}
+
+	var joinOp string
+	not := false
+	switch c.Modifier {
+	case models.CriterionModifierIncludes:
+		joinOp = " OR "
+	case models.CriterionModifierIncludesAll:
+		joinOp = " AND "
+	case models.CriterionModifierExcludes:
+		joinOp = " AND "
+		not = true
+	default:
+		f.setError(fmt.Errorf("invalid saved filters modifier %s", c.Modifier))
+		return
+	}
+
+	var clauses []string
+	var args []interface{}
+	for _, id := range c.Value {
+		clause, clauseArgs, err := h.savedFilterClause(ctx, id, not)
+		if err != nil {
+			f.setError(err)
+			return
+		}
+		clauses = append(clauses, clause)
+		args = append(args, clauseArgs...)
+	}
+
+	if len(clauses) > 0 {
+		f.addWhere("("+strings.Join(clauses, joinOp)+")", args...)
+	}
+
+	for _, id := range c.Excludes {
+		clause, clauseArgs, err := h.savedFilterClause(ctx, id, true)
+		if err != nil {
+			f.setError(err)
+			return
+		}
+		f.addWhere(clause, clauseArgs...)
+	}
+}
@@ -5,6 +5,7 @@ package sqlite_test
 
 import (
 	"context"
+	"strconv"
 	"testing"
 
 	"github.com/stashapp/stash/pkg/models"
@@ -102,3 +103,139 @@ func TestSavedFilterDestroy(t *testing.T) {
 // TODO GetMarkerStrings
 // TODO Wall
 // TODO Query
+
+func createSavedFilterWithObjectFilter(ctx context.Context, t *testing.T, name string, mode models.FilterMode, objectFilter map[string]interface{}) int {
+	savedFilter := models.SavedFilter{
+		Mode:         mode,
+		Name:         name,
+		ObjectFilter: objectFilter,
+	}
+	if err := db.SavedFilter.Create(ctx, &savedFilter); err != nil {
+		t.Fatalf("Error creating saved filter: %s", err.Error())
+	}
+	return savedFilter.ID
+}
+
+// savedTagsCriterion returns a tags criterion in the format saved by the UI
+func savedTagsCriterion(modifier models.CriterionModifier, tagIdx int) map[string]interface{} {
+	return map[string]interface{}{
+		"modifier": modifier.String(),
+		"value": map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{
+					"id":    strconv.Itoa(tagIDs[tagIdx]),
+					"label": getTagStringValue(tagIdx, "Name"),
+				},
+			},
+			"excluded": []interface{}{},
+			"depth":    0,
+		},
+	}
+}
+
+func TestSceneQuerySavedFilters(t *testing.T) {
+	runWithRollbackTxn(t, "saved filters", func(t *testing.T, ctx context.Context) {
+		withTag2 := createSavedFilterWithObjectFilter(ctx, t, "withTag2", models.FilterModeScenes, map[string]interface{}{
+			"tags": savedTagsCriterion(models.CriterionModifierIncludes, tagIdx2WithScene),
+		})
+		// refers to the other saved filter
+		withTag2NotTag3 := createSavedFilterWithObjectFilter(ctx, t, "withTag2NotTag3", models.FilterModeScenes, map[string]interface{}{
+			"saved_filters": map[string]interface{}{
+				"modifier": models.CriterionModifierIncludes.String(),
+				"value": []interface{}{
+					map[string]interface{}{"id": strconv.Itoa(withTag2), "label": "withTag2"},
+				},
+			},
+			"tags": savedTagsCriterion(models.CriterionModifierExcludes, tagIdx3WithScene),
+		})
+
+		tests := []struct {
+			name        string
+			modifier    models.CriterionModifier
+			savedFilter int
+			includeIdxs []int
+			excludeIdxs []int
+		}{
+			{
+				"includes",
+				models.CriterionModifierIncludes,
+				withTag2,
+				[]int{sceneIdxWithTwoTags, sceneIdxWithThreeTags},
+				[]int{sceneIdxWithTag},
+			},
+			{
+				"nested",
+				models.CriterionModifierIncludes,
+				withTag2NotTag3,
+				[]int{sceneIdxWithTwoTags},
+				[]int{sceneIdxWithTag, sceneIdxWithThreeTags},
+			},
+			{
+				"excludes",
+				models.CriterionModifierExcludes,
+				withTag2,
+				[]int{sceneIdxWithTag},
+				[]int{sceneIdxWithTwoTags, sceneIdxWithThreeTags},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				scenes := queryScene(ctx, t, db.Scene, &models.SceneFilterType{
+					SavedFilters: &models.MultiCriterionInput{
+						Value:    []string{strconv.Itoa(tt.savedFilter)},
+						Modifier: tt.modifier,
+					},
+				}, nil)
+
+				ids := scenesToIDs(scenes)
+				for _, idx := range tt.includeIdxs {
+					assert.Contains(t, ids, sceneIDs[idx])
+				}
+				for _, idx := range tt.excludeIdxs {
+					assert.NotContains(t, ids, sceneIDs[idx])
+				}
+			})
+		}
+
+		count, err := db.Scene.QueryCount(ctx, &models.SceneFilterType{
+			SavedFilters: &models.MultiCriterionInput{
+				Value:    []string{strconv.Itoa(withTag2NotTag3)},
+				Modifier: models.CriterionModifierIncludes,
+			},
+		}, nil)
+		if err != nil {
+			t.Errorf("Error counting scenes: %s", err.Error())
+		}
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestSceneQuerySavedFiltersError(t *testing.T) {
+	runWithRollbackTxn(t, "saved filters error", func(t *testing.T, ctx context.Context) {
+		selfReferencing := createSavedFilterWithObjectFilter(ctx, t, "selfReferencing", models.FilterModeScenes, nil)
+		if err := db.SavedFilter.Update(ctx, &models.SavedFilter{
+			ID:   selfReferencing,
+			Mode: models.FilterModeScenes,
+			Name: "selfReferencing",
+			ObjectFilter: map[string]interface{}{
+				"saved_filters": map[string]interface{}{
+					"modifier": models.CriterionModifierIncludes.String(),
+					"value":    []interface{}{strconv.Itoa(selfReferencing)},
+				},
+			},
+		}); err != nil {
+			t.Fatalf("Error updating saved filter: %s", err.Error())
+		}
+
+		for _, id := range []int{selfReferencing, savedFilterIDs[savedFilterIdxImage]} {
+			_, err := db.Scene.QueryCount(ctx, &models.SceneFilterType{
+				SavedFilters: &models.MultiCriterionInput{
+					Value:    []string{strconv.Itoa(id)},
+					Modifier: models.CriterionModifierIncludes,
+				},
+			}, nil)
+			assert.Error(t, err)
+		}
+	})
+}
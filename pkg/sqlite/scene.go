@@ -360,6 +360,16 @@ func (qb *SceneStore) Create(ctx context.Context, newObject *models.Scene, fileI
 }
 
 func (qb *SceneStore) UpdatePartial(ctx context.Context, id int, partial models.ScenePartial) (*models.Scene, error) {
+	var ret *models.Scene
+	err := qb.repo.Change.withChangeLog(ctx, models.ChangeObjectTypeScene, id, qb.changeValues(partial.ChangeRelationships()), func() error {
+		var err error
+		ret, err = qb.updatePartial(ctx, id, partial)
+		return err
+	})
+	return ret, err
+}
+
+func (qb *SceneStore) updatePartial(ctx context.Context, id int, partial models.ScenePartial) (*models.Scene, error) {
 	r := sceneRowRecord{
 		updateRecord{
 			Record: make(exp.Record),
@@ -414,6 +424,12 @@ func (qb *SceneStore) UpdatePartial(ctx context.Context, id int, partial models.
 }
 
 func (qb *SceneStore) Update(ctx context.Context, updatedObject *models.Scene) error {
+	return qb.repo.Change.withChangeLog(ctx, models.ChangeObjectTypeScene, updatedObject.ID, qb.changeValues(updatedObject.ChangeRelationships()), func() error {
+		return qb.update(ctx, updatedObject)
+	})
+}
+
+func (qb *SceneStore) update(ctx context.Context, updatedObject *models.Scene) error {
 	var r sceneRow
 	r.fromScene(*updatedObject)
 
@@ -472,6 +488,10 @@ func (qb *SceneStore) Update(ctx context.Context, updatedObject *models.Scene) e
 }
 
 func (qb *SceneStore) Destroy(ctx context.Context, id int) error {
+	if err := qb.repo.Change.destroyObject(ctx, models.ChangeObjectTypeScene, id); err != nil {
+		return err
+	}
+
 	// must handle image checksums manually
 	if err := qb.destroyCover(ctx, id); err != nil {
 		return err
@@ -534,6 +554,39 @@ func (qb *SceneStore) find(ctx context.Context, id int) (*models.Scene, error) {
 	return ret, nil
 }
 
+// changeValues returns a function that returns the audited field values of
+// the scene for the change log. Only the relationships provided are loaded.
+func (qb *SceneStore) changeValues(relationships []string) changeValuesFunc {
+	return func(ctx context.Context, id int) (models.ChangeValues, error) {
+		ret, err := qb.find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range relationships {
+			switch r {
+			case "urls":
+				err = ret.LoadURLs(ctx, qb)
+			case "gallery_ids":
+				err = ret.LoadGalleryIDs(ctx, qb)
+			case "performer_ids":
+				err = ret.LoadPerformerIDs(ctx, qb)
+			case "tag_ids":
+				err = ret.LoadTagIDs(ctx, qb)
+			case "movies":
+				err = ret.LoadMovies(ctx, qb)
+			case "stash_ids":
+				err = ret.LoadStashIDs(ctx, qb)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return ret.ChangeValues(), nil
+	}
+}
+
 func (qb *SceneStore) findBySubquery(ctx context.Context, sq *goqu.SelectDataset) ([]*models.Scene, error) {
 	table := qb.table()
 
@@ -920,6 +973,11 @@ func (qb *SceneStore) All(ctx context.Context) ([]*models.Scene, error) {
 }
 
 func (qb *SceneStore) makeQuery(ctx context.Context, sceneFilter *models.SceneFilterType, findFilter *models.FindFilterType) (*queryBuilder, error) {
+	sceneFilter, findFilter, err := applyFilterQuery(ctx, sceneFilter, findFilter)
+	if err != nil {
+		return nil, err
+	}
+
 	if sceneFilter == nil {
 		sceneFilter = &models.SceneFilterType{}
 	}
@@ -931,32 +989,36 @@ func (qb *SceneStore) makeQuery(ctx context.Context, sceneFilter *models.SceneFi
 	distinctIDs(&query, sceneTable)
 
 	if q := findFilter.Q; q != nil && *q != "" {
-		query.addJoins(
-			join{
-				table:    scenesFilesTable,
-				onClause: "scenes_files.scene_id = scenes.id",
-			},
-			join{
-				table:    fileTable,
-				onClause: "scenes_files.file_id = files.id",
-			},
-			join{
-				table:    folderTable,
-				onClause: "files.parent_folder_id = folders.id",
-			},
-			join{
-				table:    fingerprintTable,
-				onClause: "files_fingerprints.file_id = scenes_files.file_id",
-			},
-			join{
-				table:    sceneMarkerTable,
-				onClause: "scene_markers.scene_id = scenes.id",
-			},
-		)
-
-		filepathColumn := "folders.path || '" + string(filepath.Separator) + "' || files.basename"
-		searchColumns := []string{"scenes.title", "scenes.details", filepathColumn, "files_fingerprints.fingerprint", "scene_markers.title"}
-		query.parseQueryString(searchColumns, *q)
+		if useFullTextSearch(ctx) {
+			query.parseFullTextQuery(sceneFullTextIndex, *q)
+		} else {
+			query.addJoins(
+				join{
+					table:    scenesFilesTable,
+					onClause: "scenes_files.scene_id = scenes.id",
+				},
+				join{
+					table:    fileTable,
+					onClause: "scenes_files.file_id = files.id",
+				},
+				join{
+					table:    folderTable,
+					onClause: "files.parent_folder_id = folders.id",
+				},
+				join{
+					table:    fingerprintTable,
+					onClause: "files_fingerprints.file_id = scenes_files.file_id",
+				},
+				join{
+					table:    sceneMarkerTable,
+					onClause: "scene_markers.scene_id = scenes.id",
+				},
+			)
+
+			filepathColumn := "folders.path || '" + string(filepath.Separator) + "' || files.basename"
+			searchColumns := []string{"scenes.title", "scenes.details", filepathColumn, "files_fingerprints.fingerprint", "scene_markers.title"}
+			query.parseQueryString(searchColumns, *q)
+		}
 	}
 
 	filter := filterBuilderFromHandler(ctx, &sceneFilterHandler{
@@ -1085,6 +1147,7 @@ var sceneSortOptions = sortOptions{
 	"performer_count",
 	"play_count",
 	"play_duration",
+	"relevance",
 	"resume_time",
 	"path",
 	"perceptual_similarity",
@@ -1140,6 +1203,8 @@ func (qb *SceneStore) setSceneSort(query *queryBuilder, findFilter *models.FindF
 
 	direction := findFilter.GetDirection()
 	switch sort {
+	case relevanceSort:
+		query.sortAndPagination += query.relevanceSort(direction)
 	case "movie_scene_number":
 		query.join(moviesScenesTable, "", "scenes.id = movies_scenes.scene_id")
 		query.sortAndPagination += getSort("scene_index", direction, moviesScenesTable)
@@ -156,6 +156,7 @@ func (qb *sceneFilterHandler) criterionHandler() criterionHandler {
 		&dateCriterionHandler{sceneFilter.Date, "scenes.date", nil},
 		&timestampCriterionHandler{sceneFilter.CreatedAt, "scenes.created_at", nil},
 		&timestampCriterionHandler{sceneFilter.UpdatedAt, "scenes.updated_at", nil},
+		upstreamUpdatedAtCriterionHandler(sceneFilter.UpstreamUpdatedAt, models.ChangeObjectTypeScene, "scenes.id"),
 
 		&relatedFilterHandler{
 			relatedIDCol:   "scenes_galleries.gallery_id",
@@ -207,6 +208,12 @@ func (qb *sceneFilterHandler) criterionHandler() criterionHandler {
 				f.addInnerJoin("scene_markers", "", "scenes.id")
 			},
 		},
+
+		&savedFiltersCriterionHandler{
+			criterion: sceneFilter.SavedFilters,
+			mode:      models.FilterModeScenes,
+			idColumn:  sceneTable + ".id",
+		},
 	}
 }
 
@@ -14,7 +14,10 @@ import (
 	"github.com/stashapp/stash/pkg/sliceutil"
 )
 
-const sceneMarkerTable = "scene_markers"
+const (
+	sceneMarkerTable      = "scene_markers"
+	sceneMarkersTagsTable = "scene_markers_tags"
+)
 
 const countSceneMarkersForTagQuery = `
 SELECT scene_markers.id FROM scene_markers
@@ -94,7 +97,7 @@ var (
 		},
 		tags: joinRepository{
 			repository: repository{
-				tableName: "scene_markers_tags",
+				tableName: sceneMarkersTagsTable,
 				idColumn:  "scene_marker_id",
 			},
 			fkColumn: tagIDColumn,
@@ -290,6 +293,11 @@ func (qb *SceneMarkerStore) Wall(ctx context.Context, q *string) ([]*models.Scen
 }
 
 func (qb *SceneMarkerStore) makeQuery(ctx context.Context, sceneMarkerFilter *models.SceneMarkerFilterType, findFilter *models.FindFilterType) (*queryBuilder, error) {
+	sceneMarkerFilter, findFilter, err := applyFilterQuery(ctx, sceneMarkerFilter, findFilter)
+	if err != nil {
+		return nil, err
+	}
+
 	if sceneMarkerFilter == nil {
 		sceneMarkerFilter = &models.SceneMarkerFilterType{}
 	}
@@ -301,10 +309,14 @@ func (qb *SceneMarkerStore) makeQuery(ctx context.Context, sceneMarkerFilter *mo
 	distinctIDs(&query, sceneMarkerTable)
 
 	if q := findFilter.Q; q != nil && *q != "" {
-		query.join(sceneTable, "", "scenes.id = scene_markers.scene_id")
-		query.join(tagTable, "", "scene_markers.primary_tag_id = tags.id")
-		searchColumns := []string{"scene_markers.title", "scenes.title", "tags.name"}
-		query.parseQueryString(searchColumns, *q)
+		if useFullTextSearch(ctx) {
+			query.parseFullTextQuery(sceneMarkerFullTextIndex, *q)
+		} else {
+			query.join(sceneTable, "", "scenes.id = scene_markers.scene_id")
+			query.join(tagTable, "", "scene_markers.primary_tag_id = tags.id")
+			searchColumns := []string{"scene_markers.title", "scenes.title", "tags.name"}
+			query.parseQueryString(searchColumns, *q)
+		}
 	}
 
 	filter := filterBuilderFromHandler(ctx, &sceneMarkerFilterHandler{
@@ -356,6 +368,7 @@ var sceneMarkerSortOptions = sortOptions{
 	"id",
 	"title",
 	"random",
+	"relevance",
 	"scene_id",
 	"scenes_updated_at",
 	"seconds",
@@ -372,6 +385,8 @@ func (qb *SceneMarkerStore) setSceneMarkerSort(query *queryBuilder, findFilter *
 	}
 
 	switch sort {
+	case relevanceSort:
+		query.sortAndPagination += query.relevanceSort(direction)
 	case "scenes_updated_at":
 		sort = "updated_at"
 		query.join(sceneTable, "", "scenes.id = scene_markers.scene_id")
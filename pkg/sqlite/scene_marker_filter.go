@@ -54,6 +54,12 @@ func (qb *sceneMarkerFilterHandler) criterionHandler() criterionHandler {
 				qb.joinScenes(f)
 			},
 		},
+
+		&savedFiltersCriterionHandler{
+			criterion: sceneMarkerFilter.SavedFilters,
+			mode:      models.FilterModeSceneMarkers,
+			idColumn:  sceneMarkerTable + ".id",
+		},
 	}
 }
 
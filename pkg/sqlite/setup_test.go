@@ -287,6 +287,62 @@ const (
 	totalSavedFilters
 )
 
+const (
+	identifyProposalIdxRejected = iota
+	identifyProposalIdxPending
+	identifyProposalIdxWithOtherScene
+
+	// new indexes above
+	totalIdentifyProposals
+)
+
+const (
+	stashBoxSubmissionIdxFingerprints = iota
+	stashBoxSubmissionIdxFailedSceneDraft
+	stashBoxSubmissionIdxWithOtherEndpoint
+	stashBoxSubmissionIdxPerformerDraft
+	stashBoxSubmissionIdxWithoutNextAttempt
+
+	// new indexes above
+	totalStashBoxSubmissions
+)
+
+const (
+	stashBoxUpdateIdxDismissed = iota
+	stashBoxUpdateIdxPending
+	stashBoxUpdateIdxWithOtherEndpoint
+	stashBoxUpdateIdxWithOtherScene
+	stashBoxUpdateIdxPerformerDeleted
+
+	// new indexes above
+	totalStashBoxUpdates
+)
+
+const (
+	autoTagRuleIdxEnabled = iota
+	autoTagRuleIdxDisabled
+
+	// new indexes above
+	totalAutoTagRules
+)
+
+const (
+	filenameParserTemplateIdxWithPaths = iota
+	filenameParserTemplateIdxDisabled
+
+	// new indexes above
+	totalFilenameParserTemplates
+)
+
+const (
+	playlistIdxWithItems = iota
+	playlistIdx1WithItems
+	playlistIdxWithoutItems
+
+	// new indexes above
+	totalPlaylists
+)
+
 const (
 	pathField            = "Path"
 	checksumField        = "Checksum"
@@ -314,6 +370,14 @@ var (
 	markerIDs      []int
 	savedFilterIDs []int
 
+	identifyProposalIDs   []int
+	stashBoxSubmissionIDs []int
+	stashBoxUpdateIDs     []int
+	autoTagRuleIDs        []int
+
+	filenameParserTemplateIDs []int
+	playlistIDs               []int
+
 	folderPaths []string
 
 	tagNames       []string
@@ -435,6 +499,117 @@ var (
 	}
 )
 
+type identifyProposalSpec struct {
+	sceneIdx int
+	sourceID string
+	status   models.IdentifyProposalStatus
+}
+
+var (
+	// indexed by identify proposal
+	// creating a pending proposal replaces the pending proposal of the scene,
+	// so the rejected proposal is created first
+	identifyProposalSpecs = []identifyProposalSpec{
+		{sceneIdxWithGallery, "first", models.IdentifyProposalStatusRejected},
+		{sceneIdxWithGallery, "first", models.IdentifyProposalStatusPending},
+		{sceneIdxWithTag, "second", models.IdentifyProposalStatusPending},
+	}
+)
+
+const (
+	stashBoxEndpoint      = "https://stashbox.example/graphql"
+	stashBoxOtherEndpoint = "https://other.example/graphql"
+)
+
+type stashBoxSubmissionSpec struct {
+	submissionType models.StashBoxSubmissionType
+	endpoint       string
+	// -1 if not set
+	sceneIdx     int
+	performerIdx int
+	status       models.StashBoxSubmissionStatus
+	// hours after stashBoxSubmissionTime, or -1 if not set
+	nextAttemptHours int
+}
+
+var (
+	// indexed by stash-box submission
+	stashBoxSubmissionSpecs = []stashBoxSubmissionSpec{
+		{models.StashBoxSubmissionTypeFingerprints, stashBoxEndpoint, sceneIdxWithGallery, -1, models.StashBoxSubmissionStatusPending, 1},
+		{models.StashBoxSubmissionTypeSceneDraft, stashBoxEndpoint, sceneIdxWithGallery, -1, models.StashBoxSubmissionStatusFailed, 0},
+		{models.StashBoxSubmissionTypeFingerprints, stashBoxOtherEndpoint, sceneIdxWithGallery, -1, models.StashBoxSubmissionStatusPending, 3},
+		{models.StashBoxSubmissionTypePerformerDraft, stashBoxEndpoint, -1, performerIdxWithScene, models.StashBoxSubmissionStatusPending, 2},
+		{models.StashBoxSubmissionTypeFingerprints, stashBoxEndpoint, sceneIdxWithTag, -1, models.StashBoxSubmissionStatusPending, -1},
+	}
+)
+
+type stashBoxUpdateSpec struct {
+	objectType models.ChangeObjectType
+	// scene or performer index, depending on objectType
+	objectIdx  int
+	endpoint   string
+	updateType models.StashBoxUpdateType
+	status     models.StashBoxUpdateStatus
+}
+
+var (
+	// indexed by stash-box update
+	// creating a pending update replaces the pending update of the object,
+	// so the dismissed update is created first
+	stashBoxUpdateSpecs = []stashBoxUpdateSpec{
+		{models.ChangeObjectTypeScene, sceneIdxWithGallery, stashBoxEndpoint, models.StashBoxUpdateTypeUpdated, models.StashBoxUpdateStatusDismissed},
+		{models.ChangeObjectTypeScene, sceneIdxWithGallery, stashBoxEndpoint, models.StashBoxUpdateTypeUpdated, models.StashBoxUpdateStatusPending},
+		{models.ChangeObjectTypeScene, sceneIdxWithGallery, stashBoxOtherEndpoint, models.StashBoxUpdateTypeUpdated, models.StashBoxUpdateStatusPending},
+		{models.ChangeObjectTypeScene, sceneIdxWithTag, stashBoxEndpoint, models.StashBoxUpdateTypeUpdated, models.StashBoxUpdateStatusPending},
+		{models.ChangeObjectTypePerformer, performerIdxWithScene, stashBoxEndpoint, models.StashBoxUpdateTypeDeleted, models.StashBoxUpdateStatusPending},
+	}
+)
+
+type autoTagRuleSpec struct {
+	name    string
+	enabled bool
+}
+
+var (
+	// indexed by auto-tag rule
+	// names are not in id order, to test that rules are ordered by id
+	autoTagRuleSpecs = []autoTagRuleSpec{
+		{"tag scenes", true},
+		{"disabled", false},
+	}
+)
+
+type filenameParserTemplateSpec struct {
+	name    string
+	paths   []string
+	enabled bool
+}
+
+var (
+	// indexed by filename parser template
+	// names are not in id order, to test that templates are ordered by id
+	filenameParserTemplateSpecs = []filenameParserTemplateSpec{
+		{"scene releases", []string{"/videos"}, true},
+		{"disabled", nil, false},
+	}
+)
+
+type playlistSpec struct {
+	name        string
+	description string
+	withItems   bool
+}
+
+var (
+	// indexed by playlist
+	// names are not in id order, to test that playlists are ordered by name
+	playlistSpecs = []playlistSpec{
+		{"Favourite Clips", "description", true},
+		{"Other Clips", "", true},
+		{"Images", "", false},
+	}
+)
+
 var (
 	imageGalleries = linkMap{
 		imageIdxWithGallery:      {galleryIdxWithImage},
@@ -710,6 +885,42 @@ func populateDB() error {
 			}
 		}
 
+		for _, ps := range identifyProposalSpecs {
+			if err := createIdentifyProposal(ctx, db.IdentifyProposal, ps); err != nil {
+				return fmt.Errorf("error creating identify proposal: %s", err.Error())
+			}
+		}
+
+		for _, ss := range stashBoxSubmissionSpecs {
+			if err := createStashBoxSubmission(ctx, db.StashBoxSubmission, ss); err != nil {
+				return fmt.Errorf("error creating stash-box submission: %s", err.Error())
+			}
+		}
+
+		for _, us := range stashBoxUpdateSpecs {
+			if err := createStashBoxUpdate(ctx, db.StashBoxUpdate, us); err != nil {
+				return fmt.Errorf("error creating stash-box update: %s", err.Error())
+			}
+		}
+
+		for _, rs := range autoTagRuleSpecs {
+			if err := createAutoTagRule(ctx, db.AutoTagRule, rs); err != nil {
+				return fmt.Errorf("error creating auto-tag rule: %s", err.Error())
+			}
+		}
+
+		for _, ts := range filenameParserTemplateSpecs {
+			if err := createFilenameParserTemplate(ctx, db.FilenameParserTemplate, ts); err != nil {
+				return fmt.Errorf("error creating filename parser template: %s", err.Error())
+			}
+		}
+
+		for _, ps := range playlistSpecs {
+			if err := createPlaylist(ctx, db.Playlist, ps); err != nil {
+				return fmt.Errorf("error creating playlist: %s", err.Error())
+			}
+		}
+
 		return nil
 	}); err != nil {
 		return err
@@ -1775,6 +1986,178 @@ func createChapter(ctx context.Context, mqb models.GalleryChapterReaderWriter, c
 	return nil
 }
 
+var (
+	identifyProposalCreatedAt = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+	identifyProposalUpdatedAt = time.Date(2001, 1, 2, 0, 0, 0, 0, time.UTC)
+)
+
+func createIdentifyProposal(ctx context.Context, qb models.IdentifyProposalReaderWriter, spec identifyProposalSpec) error {
+	proposal := models.IdentifyProposal{
+		SceneID:    sceneIDs[spec.sceneIdx],
+		SourceID:   spec.sourceID,
+		SourceName: spec.sourceID,
+		Diffs:      []*models.IdentifyFieldDiff{},
+		Data:       "{}",
+		Status:     spec.status,
+		CreatedAt:  identifyProposalCreatedAt,
+		UpdatedAt:  identifyProposalUpdatedAt,
+	}
+
+	if err := qb.Create(ctx, &proposal); err != nil {
+		return fmt.Errorf("error creating identify proposal %v+: %w", proposal, err)
+	}
+
+	identifyProposalIDs = append(identifyProposalIDs, proposal.ID)
+
+	return nil
+}
+
+var stashBoxSubmissionTime = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func createStashBoxSubmission(ctx context.Context, qb models.StashBoxSubmissionReaderWriter, spec stashBoxSubmissionSpec) error {
+	submission := models.StashBoxSubmission{
+		Type:      spec.submissionType,
+		Endpoint:  spec.endpoint,
+		Status:    spec.status,
+		CreatedAt: stashBoxSubmissionTime,
+		UpdatedAt: stashBoxSubmissionTime,
+	}
+
+	if spec.sceneIdx != -1 {
+		sceneID := sceneIDs[spec.sceneIdx]
+		submission.SceneID = &sceneID
+	}
+	if spec.performerIdx != -1 {
+		performerID := performerIDs[spec.performerIdx]
+		submission.PerformerID = &performerID
+	}
+	if spec.nextAttemptHours != -1 {
+		nextAttemptAt := stashBoxSubmissionTime.Add(time.Duration(spec.nextAttemptHours) * time.Hour)
+		submission.NextAttemptAt = &nextAttemptAt
+	}
+
+	if err := qb.Create(ctx, &submission); err != nil {
+		return fmt.Errorf("error creating stash-box submission %v+: %w", submission, err)
+	}
+
+	stashBoxSubmissionIDs = append(stashBoxSubmissionIDs, submission.ID)
+
+	return nil
+}
+
+var stashBoxUpdateTime = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func createStashBoxUpdate(ctx context.Context, qb models.StashBoxUpdateReaderWriter, spec stashBoxUpdateSpec) error {
+	objectID := sceneIDs[spec.objectIdx]
+	if spec.objectType == models.ChangeObjectTypePerformer {
+		objectID = performerIDs[spec.objectIdx]
+	}
+
+	update := models.StashBoxUpdate{
+		ObjectType: spec.objectType,
+		ObjectID:   objectID,
+		Endpoint:   spec.endpoint,
+		StashID:    "stash-id",
+		Type:       spec.updateType,
+		Diffs:      []*models.StashBoxFieldDiff{},
+		Status:     spec.status,
+		CreatedAt:  stashBoxUpdateTime,
+		UpdatedAt:  stashBoxUpdateTime,
+	}
+
+	if err := qb.Create(ctx, &update); err != nil {
+		return fmt.Errorf("error creating stash-box update %v+: %w", update, err)
+	}
+
+	stashBoxUpdateIDs = append(stashBoxUpdateIDs, update.ID)
+
+	return nil
+}
+
+var autoTagRuleTime = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func createAutoTagRule(ctx context.Context, qb models.AutoTagRuleReaderWriter, spec autoTagRuleSpec) error {
+	rule := models.AutoTagRule{
+		Name:    spec.name,
+		Enabled: spec.enabled,
+		Conditions: models.AutoTagRuleConditions{
+			TagIDs: []int{tagIDs[tagIdxWithScene]},
+		},
+		Actions: models.AutoTagRuleActions{
+			AddTagIDs: []int{tagIDs[tagIdxWithImage]},
+		},
+		CreatedAt: autoTagRuleTime,
+		UpdatedAt: autoTagRuleTime,
+	}
+
+	if err := qb.Create(ctx, &rule); err != nil {
+		return fmt.Errorf("error creating auto-tag rule %v+: %w", rule, err)
+	}
+
+	autoTagRuleIDs = append(autoTagRuleIDs, rule.ID)
+
+	return nil
+}
+
+var filenameParserTemplateTime = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func createFilenameParserTemplate(ctx context.Context, qb models.FilenameParserTemplateReaderWriter, spec filenameParserTemplateSpec) error {
+	template := models.FilenameParserTemplate{
+		Name:      spec.name,
+		Pattern:   "{title}.{ext}",
+		Paths:     spec.paths,
+		Enabled:   spec.enabled,
+		CreatedAt: filenameParserTemplateTime,
+		UpdatedAt: filenameParserTemplateTime,
+	}
+
+	if err := qb.Create(ctx, &template); err != nil {
+		return fmt.Errorf("error creating filename parser template %v+: %w", template, err)
+	}
+
+	filenameParserTemplateIDs = append(filenameParserTemplateIDs, template.ID)
+
+	return nil
+}
+
+var playlistTime = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// createPlaylist creates a playlist from the spec. Playlists with items
+// contain a scene, a scene marker clip and an image.
+func createPlaylist(ctx context.Context, qb models.PlaylistReaderWriter, spec playlistSpec) error {
+	playlist := models.Playlist{
+		Name:        spec.name,
+		Description: spec.description,
+		CreatedAt:   playlistTime,
+		UpdatedAt:   playlistTime,
+	}
+
+	if err := qb.Create(ctx, &playlist); err != nil {
+		return fmt.Errorf("error creating playlist %v+: %w", playlist, err)
+	}
+
+	if spec.withItems {
+		var (
+			sceneID    = sceneIDs[sceneIdxWithMovie]
+			markerID   = markerIDs[markerIdxWithScene]
+			imageID    = imageIDs[imageIdxWithGallery]
+			endSeconds = 30.0
+		)
+
+		if err := qb.UpdateItems(ctx, playlist.ID, []models.PlaylistItem{
+			{SceneID: &sceneID},
+			{SceneMarkerID: &markerID, EndSeconds: &endSeconds},
+			{ImageID: &imageID},
+		}); err != nil {
+			return fmt.Errorf("error setting items of playlist %d: %w", playlist.ID, err)
+		}
+	}
+
+	playlistIDs = append(playlistIDs, playlist.ID)
+
+	return nil
+}
+
 func getSavedFilterMode(index int) models.FilterMode {
 	switch index {
 	case savedFilterIdxScene, savedFilterIdxDefaultScene:
@@ -0,0 +1,317 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+	"gopkg.in/guregu/null.v4"
+	"gopkg.in/guregu/null.v4/zero"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	stashBoxSubmissionTable           = "stash_box_submissions"
+	stashBoxSubmittedFingerprintTable = "stash_box_submitted_fingerprints"
+)
+
+type stashBoxSubmissionRow struct {
+	ID          int         `db:"id" goqu:"skipinsert"`
+	Type        string      `db:"type"`
+	Endpoint    string      `db:"endpoint"`
+	SceneID     null.Int    `db:"scene_id,omitempty"`
+	PerformerID null.Int    `db:"performer_id,omitempty"`
+	Status      string      `db:"status"`
+	Attempts    int         `db:"attempts"`
+	LastError   zero.String `db:"last_error"`
+	RemoteID    zero.String `db:"remote_id"`
+	// stored in UTC so that due submissions can be found by comparing values
+	NextAttemptAt NullTimestamp `db:"next_attempt_at"`
+	CreatedAt     Timestamp     `db:"created_at"`
+	UpdatedAt     Timestamp     `db:"updated_at"`
+}
+
+func (r *stashBoxSubmissionRow) fromStashBoxSubmission(o models.StashBoxSubmission) {
+	r.ID = o.ID
+	r.Type = o.Type.String()
+	r.Endpoint = o.Endpoint
+	r.SceneID = intFromPtr(o.SceneID)
+	r.PerformerID = intFromPtr(o.PerformerID)
+	r.Status = o.Status.String()
+	r.Attempts = o.Attempts
+	r.LastError = zero.StringFromPtr(o.LastError)
+	r.RemoteID = zero.StringFromPtr(o.RemoteID)
+	r.NextAttemptAt = NullTimestamp{}
+	if o.NextAttemptAt != nil {
+		r.NextAttemptAt = NullTimestamp{Timestamp: o.NextAttemptAt.UTC(), Valid: true}
+	}
+	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
+	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
+}
+
+func (r *stashBoxSubmissionRow) resolve() *models.StashBoxSubmission {
+	return &models.StashBoxSubmission{
+		ID:            r.ID,
+		Type:          models.StashBoxSubmissionType(r.Type),
+		Endpoint:      r.Endpoint,
+		SceneID:       nullIntPtr(r.SceneID),
+		PerformerID:   nullIntPtr(r.PerformerID),
+		Status:        models.StashBoxSubmissionStatus(r.Status),
+		Attempts:      r.Attempts,
+		LastError:     r.LastError.Ptr(),
+		RemoteID:      r.RemoteID.Ptr(),
+		NextAttemptAt: r.NextAttemptAt.TimePtr(),
+		CreatedAt:     r.CreatedAt.Timestamp,
+		UpdatedAt:     r.UpdatedAt.Timestamp,
+	}
+}
+
+type stashBoxSubmittedFingerprintRow struct {
+	Endpoint  string    `db:"endpoint"`
+	StashID   string    `db:"stash_id"`
+	Algorithm string    `db:"algorithm"`
+	Hash      string    `db:"hash"`
+	CreatedAt Timestamp `db:"created_at"`
+}
+
+// StashBoxSubmissionStore stores the queue of submissions to stash-box
+// instances, and the fingerprints that have been submitted.
+type StashBoxSubmissionStore struct{}
+
+func NewStashBoxSubmissionStore() *StashBoxSubmissionStore {
+	return &StashBoxSubmissionStore{}
+}
+
+func (qb *StashBoxSubmissionStore) table() exp.IdentifierExpression {
+	return goqu.T(stashBoxSubmissionTable)
+}
+
+func (qb *StashBoxSubmissionStore) selectDataset() *goqu.SelectDataset {
+	return dialect.From(qb.table()).Select(qb.table().All())
+}
+
+func (qb *StashBoxSubmissionStore) Create(ctx context.Context, newObject *models.StashBoxSubmission) error {
+	table := qb.table()
+
+	// replace any existing pending submission for the same object
+	q := dialect.Delete(table).Where(
+		table.Col("type").Eq(newObject.Type.String()),
+		table.Col("endpoint").Eq(newObject.Endpoint),
+		table.Col("status").Eq(models.StashBoxSubmissionStatusPending.String()),
+	)
+	if newObject.SceneID != nil {
+		q = q.Where(table.Col(sceneIDColumn).Eq(*newObject.SceneID))
+	} else {
+		q = q.Where(table.Col(sceneIDColumn).IsNull())
+	}
+	if newObject.PerformerID != nil {
+		q = q.Where(table.Col(performerIDColumn).Eq(*newObject.PerformerID))
+	} else {
+		q = q.Where(table.Col(performerIDColumn).IsNull())
+	}
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("deleting pending submissions: %w", err)
+	}
+
+	var r stashBoxSubmissionRow
+	r.fromStashBoxSubmission(*newObject)
+
+	insert := dialect.Insert(table).Prepared(true).Rows(r)
+	result, err := exec(ctx, insert)
+	if err != nil {
+		return fmt.Errorf("inserting into %s: %w", stashBoxSubmissionTable, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	newObject.ID = int(id)
+	return nil
+}
+
+func (qb *StashBoxSubmissionStore) Update(ctx context.Context, updatedObject *models.StashBoxSubmission) error {
+	var r stashBoxSubmissionRow
+	r.fromStashBoxSubmission(*updatedObject)
+
+	table := qb.table()
+	q := dialect.Update(table).Prepared(true).Set(r).Where(table.Col(idColumn).Eq(updatedObject.ID))
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("updating %s: %w", stashBoxSubmissionTable, err)
+	}
+
+	return nil
+}
+
+func (qb *StashBoxSubmissionStore) Destroy(ctx context.Context, id int) error {
+	table := qb.table()
+	q := dialect.Delete(table).Where(table.Col(idColumn).Eq(id))
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("deleting from %s: %w", stashBoxSubmissionTable, err)
+	}
+
+	return nil
+}
+
+// returns nil, nil if not found
+func (qb *StashBoxSubmissionStore) Find(ctx context.Context, id int) (*models.StashBoxSubmission, error) {
+	q := qb.selectDataset().Where(qb.table().Col(idColumn).Eq(id))
+
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
+
+func (qb *StashBoxSubmissionStore) FindMany(ctx context.Context, ids []int) ([]*models.StashBoxSubmission, error) {
+	q := qb.selectDataset().Where(qb.table().Col(idColumn).In(ids))
+
+	unsorted, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*models.StashBoxSubmission, len(ids))
+	for _, s := range unsorted {
+		for i, id := range ids {
+			if id == s.ID {
+				ret[i] = s
+			}
+		}
+	}
+
+	for i := range ret {
+		if ret[i] == nil {
+			return nil, fmt.Errorf("stash-box submission with id %d not found", ids[i])
+		}
+	}
+
+	return ret, nil
+}
+
+func (qb *StashBoxSubmissionStore) FindBySceneID(ctx context.Context, sceneID int) ([]*models.StashBoxSubmission, error) {
+	table := qb.table()
+	q := qb.selectDataset().Where(table.Col(sceneIDColumn).Eq(sceneID)).Order(table.Col(idColumn).Desc())
+
+	return qb.getMany(ctx, q)
+}
+
+func (qb *StashBoxSubmissionStore) FindDue(ctx context.Context, t time.Time) ([]*models.StashBoxSubmission, error) {
+	table := qb.table()
+	q := qb.selectDataset().Where(
+		table.Col("status").Eq(models.StashBoxSubmissionStatusPending.String()),
+		table.Col("next_attempt_at").Lte(UTCTimestamp{Timestamp{Timestamp: t}}),
+	).Order(table.Col("next_attempt_at").Asc(), table.Col(idColumn).Asc())
+
+	return qb.getMany(ctx, q)
+}
+
+func (qb *StashBoxSubmissionStore) Query(ctx context.Context, submissionFilter *models.StashBoxSubmissionFilterType, findFilter *models.FindFilterType) ([]*models.StashBoxSubmission, int, error) {
+	if findFilter == nil {
+		findFilter = &models.FindFilterType{}
+	}
+
+	table := qb.table()
+	q := qb.selectDataset()
+
+	if submissionFilter != nil {
+		if submissionFilter.Type != nil {
+			q = q.Where(table.Col("type").Eq(submissionFilter.Type.String()))
+		}
+		if submissionFilter.Status != nil {
+			q = q.Where(table.Col("status").Eq(submissionFilter.Status.String()))
+		}
+		if submissionFilter.Endpoint != nil {
+			q = q.Where(table.Col("endpoint").Eq(*submissionFilter.Endpoint))
+		}
+		if submissionFilter.SceneID != nil {
+			q = q.Where(table.Col(sceneIDColumn).Eq(*submissionFilter.SceneID))
+		}
+		if submissionFilter.PerformerID != nil {
+			q = q.Where(table.Col(performerIDColumn).Eq(*submissionFilter.PerformerID))
+		}
+	}
+
+	total, err := count(ctx, q.Select(goqu.COUNT("*")))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q = q.Order(table.Col(idColumn).Desc())
+	if !findFilter.IsGetAll() {
+		pageSize := findFilter.GetPageSize()
+		q = q.Limit(uint(pageSize)).Offset(uint((findFilter.GetPage() - 1) * pageSize))
+	}
+
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ret, total, nil
+}
+
+func (qb *StashBoxSubmissionStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.StashBoxSubmission, error) {
+	const single = false
+	var ret []*models.StashBoxSubmission
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var f stashBoxSubmissionRow
+		if err := r.StructScan(&f); err != nil {
+			return err
+		}
+
+		ret = append(ret, f.resolve())
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("querying %s: %w", stashBoxSubmissionTable, err)
+	}
+
+	return ret, nil
+}
+
+func (qb *StashBoxSubmissionStore) IsFingerprintSubmitted(ctx context.Context, fingerprint models.SubmittedFingerprint) (bool, error) {
+	table := goqu.T(stashBoxSubmittedFingerprintTable)
+	q := dialect.From(table).Select(goqu.COUNT("*")).Where(
+		table.Col("endpoint").Eq(fingerprint.Endpoint),
+		table.Col("stash_id").Eq(fingerprint.StashID),
+		table.Col("algorithm").Eq(fingerprint.Algorithm),
+		table.Col("hash").Eq(fingerprint.Hash),
+	)
+
+	n, err := count(ctx, q)
+	if err != nil {
+		return false, fmt.Errorf("querying %s: %w", stashBoxSubmittedFingerprintTable, err)
+	}
+
+	return n > 0, nil
+}
+
+func (qb *StashBoxSubmissionStore) AddSubmittedFingerprint(ctx context.Context, fingerprint models.SubmittedFingerprint) error {
+	q := dialect.Insert(stashBoxSubmittedFingerprintTable).Prepared(true).Rows(stashBoxSubmittedFingerprintRow{
+		Endpoint:  fingerprint.Endpoint,
+		StashID:   fingerprint.StashID,
+		Algorithm: fingerprint.Algorithm,
+		Hash:      fingerprint.Hash,
+		CreatedAt: Timestamp{Timestamp: time.Now()},
+	}).OnConflict(goqu.DoNothing())
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("inserting into %s: %w", stashBoxSubmittedFingerprintTable, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,531 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func Test_StashBoxSubmissionStore_Create(t *testing.T) {
+	var (
+		sceneID     = sceneIDs[sceneIdxWithPerformer]
+		performerID = performerIDs[performerIdx1WithScene]
+		lastError   = "error"
+		remoteID    = "remote"
+	)
+
+	tests := []struct {
+		name      string
+		newObject models.StashBoxSubmission
+		wantErr   bool
+	}{
+		{
+			"scene",
+			models.StashBoxSubmission{
+				Type:          models.StashBoxSubmissionTypeSceneDraft,
+				Endpoint:      stashBoxEndpoint,
+				SceneID:       &sceneID,
+				Status:        models.StashBoxSubmissionStatusSubmitted,
+				Attempts:      2,
+				LastError:     &lastError,
+				RemoteID:      &remoteID,
+				NextAttemptAt: &stashBoxSubmissionTime,
+				CreatedAt:     stashBoxSubmissionTime,
+				UpdatedAt:     stashBoxSubmissionTime,
+			},
+			false,
+		},
+		{
+			"performer without next attempt",
+			models.StashBoxSubmission{
+				Type:        models.StashBoxSubmissionTypePerformerDraft,
+				Endpoint:    stashBoxEndpoint,
+				PerformerID: &performerID,
+				Status:      models.StashBoxSubmissionStatusFailed,
+				CreatedAt:   stashBoxSubmissionTime,
+				UpdatedAt:   stashBoxSubmissionTime,
+			},
+			false,
+		},
+		{
+			"invalid scene id",
+			models.StashBoxSubmission{
+				Type:      models.StashBoxSubmissionTypeFingerprints,
+				Endpoint:  stashBoxEndpoint,
+				SceneID:   &invalidID,
+				Status:    models.StashBoxSubmissionStatusPending,
+				CreatedAt: stashBoxSubmissionTime,
+				UpdatedAt: stashBoxSubmissionTime,
+			},
+			true,
+		},
+	}
+
+	qb := db.StashBoxSubmission
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			s := tt.newObject
+			if err := qb.Create(ctx, &s); (err != nil) != tt.wantErr {
+				t.Errorf("StashBoxSubmissionStore.Create() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				assert.Zero(s.ID)
+				return
+			}
+
+			assert.NotZero(s.ID)
+
+			found, err := qb.Find(ctx, s.ID)
+			if err != nil {
+				t.Errorf("StashBoxSubmissionStore.Find() error = %v", err)
+			}
+
+			if !assert.NotNil(found) {
+				return
+			}
+
+			want := tt.newObject
+			want.ID = s.ID
+			assert.Equal(want, *found)
+		})
+	}
+}
+
+func Test_StashBoxSubmissionStore_CreateReplacesPending(t *testing.T) {
+	newSubmission := func(submissionType models.StashBoxSubmissionType, sceneIdx int, performerIdx int) models.StashBoxSubmission {
+		ret := models.StashBoxSubmission{
+			Type:      submissionType,
+			Endpoint:  stashBoxEndpoint,
+			Status:    models.StashBoxSubmissionStatusPending,
+			CreatedAt: stashBoxSubmissionTime,
+			UpdatedAt: stashBoxSubmissionTime,
+		}
+		if sceneIdx != -1 {
+			ret.SceneID = &sceneIDs[sceneIdx]
+		}
+		if performerIdx != -1 {
+			ret.PerformerID = &performerIDs[performerIdx]
+		}
+		return ret
+	}
+
+	sceneFingerprints := newSubmission(models.StashBoxSubmissionTypeFingerprints, sceneIdxWithGallery, -1)
+
+	tests := []struct {
+		name      string
+		newObject models.StashBoxSubmission
+		// index of the existing submission
+		idx      int
+		wantKept bool
+	}{
+		{"pending for scene", sceneFingerprints, stashBoxSubmissionIdxFingerprints, false},
+		{"other type", sceneFingerprints, stashBoxSubmissionIdxFailedSceneDraft, true},
+		{"other endpoint", sceneFingerprints, stashBoxSubmissionIdxWithOtherEndpoint, true},
+		{"other scene", sceneFingerprints, stashBoxSubmissionIdxWithoutNextAttempt, true},
+		{
+			// performer submissions have a null scene id
+			"pending for performer",
+			newSubmission(models.StashBoxSubmissionTypePerformerDraft, -1, performerIdxWithScene),
+			stashBoxSubmissionIdxPerformerDraft,
+			false,
+		},
+		{
+			"other performer",
+			newSubmission(models.StashBoxSubmissionTypePerformerDraft, -1, performerIdx1WithScene),
+			stashBoxSubmissionIdxPerformerDraft,
+			true,
+		},
+	}
+
+	qb := db.StashBoxSubmission
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			s := tt.newObject
+			if err := qb.Create(ctx, &s); err != nil {
+				t.Errorf("StashBoxSubmissionStore.Create() error = %v", err)
+				return
+			}
+
+			found, err := qb.Find(ctx, stashBoxSubmissionIDs[tt.idx])
+			if err != nil {
+				t.Errorf("StashBoxSubmissionStore.Find() error = %v", err)
+			}
+
+			if tt.wantKept {
+				assert.NotNil(found)
+			} else {
+				assert.Nil(found)
+			}
+		})
+	}
+
+	// a submission that is no longer pending is not replaced
+	runWithRollbackTxn(t, "submitted not replaced", func(t *testing.T, ctx context.Context) {
+		s, err := qb.Find(ctx, stashBoxSubmissionIDs[stashBoxSubmissionIdxFingerprints])
+		if err != nil {
+			t.Errorf("StashBoxSubmissionStore.Find() error = %v", err)
+			return
+		}
+
+		s.Status = models.StashBoxSubmissionStatusSubmitted
+		if err := qb.Update(ctx, s); err != nil {
+			t.Errorf("StashBoxSubmissionStore.Update() error = %v", err)
+			return
+		}
+
+		newObject := *s
+		newObject.Status = models.StashBoxSubmissionStatusPending
+		if err := qb.Create(ctx, &newObject); err != nil {
+			t.Errorf("StashBoxSubmissionStore.Create() error = %v", err)
+			return
+		}
+
+		found, err := qb.Find(ctx, s.ID)
+		assert.NoError(t, err)
+		assert.NotNil(t, found)
+	})
+}
+
+func Test_StashBoxSubmissionStore_Update(t *testing.T) {
+	var (
+		lastError = "unreachable"
+		remoteID  = "remote"
+		// next attempt times are stored in UTC
+		nextAttemptAt = time.Date(2001, 1, 1, 10, 0, 0, 0, time.FixedZone("UTC+10", 10*60*60))
+	)
+
+	tests := []struct {
+		name   string
+		update func(s *models.StashBoxSubmission)
+	}{
+		{
+			"retry",
+			func(s *models.StashBoxSubmission) {
+				s.Attempts = 1
+				s.LastError = &lastError
+				s.NextAttemptAt = &nextAttemptAt
+			},
+		},
+		{
+			"submitted",
+			func(s *models.StashBoxSubmission) {
+				s.Status = models.StashBoxSubmissionStatusSubmitted
+				s.RemoteID = &remoteID
+				s.NextAttemptAt = nil
+			},
+		},
+	}
+
+	qb := db.StashBoxSubmission
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			s, err := qb.Find(ctx, stashBoxSubmissionIDs[stashBoxSubmissionIdxFingerprints])
+			if err != nil {
+				t.Errorf("StashBoxSubmissionStore.Find() error = %v", err)
+				return
+			}
+
+			tt.update(s)
+			if err := qb.Update(ctx, s); err != nil {
+				t.Errorf("StashBoxSubmissionStore.Update() error = %v", err)
+				return
+			}
+
+			found, err := qb.Find(ctx, s.ID)
+			if err != nil {
+				t.Errorf("StashBoxSubmissionStore.Find() error = %v", err)
+			}
+
+			if !assert.NotNil(found) {
+				return
+			}
+
+			if s.NextAttemptAt != nil {
+				assert.NotNil(found.NextAttemptAt)
+				assert.True(s.NextAttemptAt.Equal(*found.NextAttemptAt))
+				s.NextAttemptAt = found.NextAttemptAt
+			}
+			assert.Equal(*s, *found)
+		})
+	}
+
+}
+
+func Test_StashBoxSubmissionStore_Destroy(t *testing.T) {
+	qb := db.StashBoxSubmission
+
+	runWithRollbackTxn(t, "destroy", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+
+		id := stashBoxSubmissionIDs[stashBoxSubmissionIdxFingerprints]
+		assert.NoError(qb.Destroy(ctx, id))
+
+		found, err := qb.Find(ctx, id)
+		assert.NoError(err)
+		assert.Nil(found)
+	})
+
+	runWithRollbackTxn(t, "scene destroyed", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+
+		// submissions are removed with the scene
+		sceneID := sceneIDs[sceneIdxWithTag]
+		assert.NoError(db.Scene.Destroy(ctx, sceneID))
+
+		found, err := qb.FindBySceneID(ctx, sceneID)
+		assert.NoError(err)
+		assert.Len(found, 0)
+	})
+}
+
+func Test_StashBoxSubmissionStore_FindBySceneID(t *testing.T) {
+	tests := []struct {
+		name     string
+		sceneIdx int
+		// newest first
+		want []int
+	}{
+		{
+			"with submissions",
+			sceneIdxWithGallery,
+			[]int{stashBoxSubmissionIdxWithOtherEndpoint, stashBoxSubmissionIdxFailedSceneDraft, stashBoxSubmissionIdxFingerprints},
+		},
+		{
+			"without submissions",
+			sceneIdxWithPerformer,
+			[]int{},
+		},
+	}
+
+	qb := db.StashBoxSubmission
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			got, err := qb.FindBySceneID(ctx, sceneIDs[tt.sceneIdx])
+			if err != nil {
+				t.Errorf("StashBoxSubmissionStore.FindBySceneID() error = %v", err)
+				return
+			}
+
+			assert.Equal(t, indexesToIDs(stashBoxSubmissionIDs, tt.want), stashBoxSubmissionsToIDs(got))
+		})
+	}
+}
+
+func Test_StashBoxSubmissionStore_FindDue(t *testing.T) {
+	tests := []struct {
+		name string
+		t    time.Time
+		// ordered by next attempt
+		want []int
+	}{
+		{
+			"none due",
+			stashBoxSubmissionTime.Add(30 * time.Minute),
+			[]int{},
+		},
+		{
+			"due",
+			stashBoxSubmissionTime.Add(2 * time.Hour),
+			[]int{stashBoxSubmissionIdxFingerprints, stashBoxSubmissionIdxPerformerDraft},
+		},
+		{
+			// the same instant in another time zone
+			"other time zone",
+			stashBoxSubmissionTime.Add(2 * time.Hour).In(time.FixedZone("UTC-5", -5*60*60)),
+			[]int{stashBoxSubmissionIdxFingerprints, stashBoxSubmissionIdxPerformerDraft},
+		},
+		{
+			// failed submissions and submissions without a next attempt are not due
+			"all due",
+			stashBoxSubmissionTime.Add(24 * time.Hour),
+			[]int{stashBoxSubmissionIdxFingerprints, stashBoxSubmissionIdxPerformerDraft, stashBoxSubmissionIdxWithOtherEndpoint},
+		},
+	}
+
+	qb := db.StashBoxSubmission
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			got, err := qb.FindDue(ctx, tt.t)
+			if err != nil {
+				t.Errorf("StashBoxSubmissionStore.FindDue() error = %v", err)
+				return
+			}
+
+			assert.Equal(t, indexesToIDs(stashBoxSubmissionIDs, tt.want), stashBoxSubmissionsToIDs(got))
+		})
+	}
+}
+
+func Test_StashBoxSubmissionStore_Query(t *testing.T) {
+	var (
+		draftType     = models.StashBoxSubmissionTypeSceneDraft
+		failed        = models.StashBoxSubmissionStatusFailed
+		otherEndpoint = stashBoxOtherEndpoint
+		sceneID       = sceneIDs[sceneIdxWithGallery]
+		performerID   = performerIDs[performerIdxWithScene]
+	)
+
+	tests := []struct {
+		name   string
+		filter *models.StashBoxSubmissionFilterType
+		// newest first
+		want []int
+	}{
+		{
+			"all",
+			nil,
+			[]int{
+				stashBoxSubmissionIdxWithoutNextAttempt,
+				stashBoxSubmissionIdxPerformerDraft,
+				stashBoxSubmissionIdxWithOtherEndpoint,
+				stashBoxSubmissionIdxFailedSceneDraft,
+				stashBoxSubmissionIdxFingerprints,
+			},
+		},
+		{
+			"type",
+			&models.StashBoxSubmissionFilterType{Type: &draftType},
+			[]int{stashBoxSubmissionIdxFailedSceneDraft},
+		},
+		{
+			"status",
+			&models.StashBoxSubmissionFilterType{Status: &failed},
+			[]int{stashBoxSubmissionIdxFailedSceneDraft},
+		},
+		{
+			"endpoint",
+			&models.StashBoxSubmissionFilterType{Endpoint: &otherEndpoint},
+			[]int{stashBoxSubmissionIdxWithOtherEndpoint},
+		},
+		{
+			"scene",
+			&models.StashBoxSubmissionFilterType{SceneID: &sceneID},
+			[]int{stashBoxSubmissionIdxWithOtherEndpoint, stashBoxSubmissionIdxFailedSceneDraft, stashBoxSubmissionIdxFingerprints},
+		},
+		{
+			"performer",
+			&models.StashBoxSubmissionFilterType{PerformerID: &performerID},
+			[]int{stashBoxSubmissionIdxPerformerDraft},
+		},
+	}
+
+	qb := db.StashBoxSubmission
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			got, count, err := qb.Query(ctx, tt.filter, nil)
+			if err != nil {
+				t.Errorf("StashBoxSubmissionStore.Query() error = %v", err)
+				return
+			}
+
+			assert.Equal(t, indexesToIDs(stashBoxSubmissionIDs, tt.want), stashBoxSubmissionsToIDs(got))
+			assert.Equal(t, len(tt.want), count)
+		})
+	}
+}
+
+func Test_StashBoxSubmissionStore_SubmittedFingerprints(t *testing.T) {
+	submitted := models.SubmittedFingerprint{
+		Endpoint:  stashBoxEndpoint,
+		StashID:   "stash-id",
+		Algorithm: "MD5",
+		Hash:      "hash",
+	}
+
+	tests := []struct {
+		name        string
+		fingerprint func(fp models.SubmittedFingerprint) models.SubmittedFingerprint
+		want        bool
+	}{
+		{
+			"same",
+			func(fp models.SubmittedFingerprint) models.SubmittedFingerprint { return fp },
+			true,
+		},
+		{
+			"other endpoint",
+			func(fp models.SubmittedFingerprint) models.SubmittedFingerprint {
+				fp.Endpoint = stashBoxOtherEndpoint
+				return fp
+			},
+			false,
+		},
+		{
+			"other stash id",
+			func(fp models.SubmittedFingerprint) models.SubmittedFingerprint {
+				fp.StashID = "other"
+				return fp
+			},
+			false,
+		},
+		{
+			"other algorithm",
+			func(fp models.SubmittedFingerprint) models.SubmittedFingerprint {
+				fp.Algorithm = "OSHASH"
+				return fp
+			},
+			false,
+		},
+		{
+			"other hash",
+			func(fp models.SubmittedFingerprint) models.SubmittedFingerprint {
+				fp.Hash = "other"
+				return fp
+			},
+			false,
+		},
+	}
+
+	qb := db.StashBoxSubmission
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			// adding the same fingerprint twice is not an error
+			for i := 0; i < 2; i++ {
+				if err := qb.AddSubmittedFingerprint(ctx, submitted); err != nil {
+					t.Errorf("StashBoxSubmissionStore.AddSubmittedFingerprint() error = %v", err)
+					return
+				}
+			}
+
+			got, err := qb.IsFingerprintSubmitted(ctx, tt.fingerprint(submitted))
+			if err != nil {
+				t.Errorf("StashBoxSubmissionStore.IsFingerprintSubmitted() error = %v", err)
+				return
+			}
+
+			assert.Equal(tt.want, got)
+		})
+	}
+}
+
+func stashBoxSubmissionsToIDs(i []*models.StashBoxSubmission) []int {
+	ret := []int{}
+	for _, ii := range i {
+		ret = append(ret, ii.ID)
+	}
+
+	return ret
+}
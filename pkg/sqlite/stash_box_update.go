@@ -0,0 +1,351 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+	"gopkg.in/guregu/null.v4/zero"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	stashBoxUpdateTable        = "stash_box_updates"
+	stashBoxUpstreamStateTable = "stash_box_upstream_states"
+)
+
+type stashBoxUpdateRow struct {
+	ID                int           `db:"id" goqu:"skipinsert"`
+	ObjectType        string        `db:"object_type"`
+	ObjectID          int           `db:"object_id"`
+	Endpoint          string        `db:"endpoint"`
+	StashID           string        `db:"stash_id"`
+	Type              string        `db:"type"`
+	MergedStashID     zero.String   `db:"merged_stash_id"`
+	Diffs             string        `db:"diffs"`
+	Status            string        `db:"status"`
+	UpstreamUpdatedAt NullTimestamp `db:"upstream_updated_at"`
+	CreatedAt         Timestamp     `db:"created_at"`
+	UpdatedAt         Timestamp     `db:"updated_at"`
+}
+
+func (r *stashBoxUpdateRow) fromStashBoxUpdate(o models.StashBoxUpdate) error {
+	diffs := o.Diffs
+	if diffs == nil {
+		diffs = []*models.StashBoxFieldDiff{}
+	}
+	encoded, err := json.Marshal(diffs)
+	if err != nil {
+		return fmt.Errorf("encoding diffs: %w", err)
+	}
+
+	r.ID = o.ID
+	r.ObjectType = o.ObjectType.String()
+	r.ObjectID = o.ObjectID
+	r.Endpoint = o.Endpoint
+	r.StashID = o.StashID
+	r.Type = o.Type.String()
+	r.MergedStashID = zero.StringFromPtr(o.MergedStashID)
+	r.Diffs = string(encoded)
+	r.Status = o.Status.String()
+	r.UpstreamUpdatedAt = NullTimestampFromTimePtr(o.UpstreamUpdatedAt)
+	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
+	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
+
+	return nil
+}
+
+func (r *stashBoxUpdateRow) resolve() (*models.StashBoxUpdate, error) {
+	ret := &models.StashBoxUpdate{
+		ID:                r.ID,
+		ObjectType:        models.ChangeObjectType(r.ObjectType),
+		ObjectID:          r.ObjectID,
+		Endpoint:          r.Endpoint,
+		StashID:           r.StashID,
+		Type:              models.StashBoxUpdateType(r.Type),
+		MergedStashID:     r.MergedStashID.Ptr(),
+		Status:            models.StashBoxUpdateStatus(r.Status),
+		UpstreamUpdatedAt: r.UpstreamUpdatedAt.TimePtr(),
+		CreatedAt:         r.CreatedAt.Timestamp,
+		UpdatedAt:         r.UpdatedAt.Timestamp,
+	}
+
+	if err := json.Unmarshal([]byte(r.Diffs), &ret.Diffs); err != nil {
+		return nil, fmt.Errorf("decoding diffs: %w", err)
+	}
+
+	return ret, nil
+}
+
+type stashBoxUpstreamStateRow struct {
+	ObjectType        string        `db:"object_type"`
+	ObjectID          int           `db:"object_id"`
+	Endpoint          string        `db:"endpoint"`
+	StashID           string        `db:"stash_id"`
+	Deleted           bool          `db:"deleted"`
+	UpstreamUpdatedAt NullTimestamp `db:"upstream_updated_at"`
+	CheckedAt         Timestamp     `db:"checked_at"`
+}
+
+func (r *stashBoxUpstreamStateRow) resolve() *models.StashBoxUpstreamState {
+	return &models.StashBoxUpstreamState{
+		ObjectType:        models.ChangeObjectType(r.ObjectType),
+		ObjectID:          r.ObjectID,
+		Endpoint:          r.Endpoint,
+		StashID:           r.StashID,
+		Deleted:           r.Deleted,
+		UpstreamUpdatedAt: r.UpstreamUpdatedAt.TimePtr(),
+		CheckedAt:         r.CheckedAt.Timestamp,
+	}
+}
+
+// StashBoxUpdateStore stores the changes to linked stash-box objects found
+// by the stash-box update check, and the state of the linked objects as of
+// the last check.
+type StashBoxUpdateStore struct{}
+
+func NewStashBoxUpdateStore() *StashBoxUpdateStore {
+	return &StashBoxUpdateStore{}
+}
+
+func (qb *StashBoxUpdateStore) table() exp.IdentifierExpression {
+	return goqu.T(stashBoxUpdateTable)
+}
+
+func (qb *StashBoxUpdateStore) selectDataset() *goqu.SelectDataset {
+	return dialect.From(qb.table()).Select(qb.table().All())
+}
+
+func (qb *StashBoxUpdateStore) Create(ctx context.Context, newObject *models.StashBoxUpdate) error {
+	table := qb.table()
+
+	// replace any existing pending update for the object
+	q := dialect.Delete(table).Where(
+		table.Col("object_type").Eq(newObject.ObjectType.String()),
+		table.Col("object_id").Eq(newObject.ObjectID),
+		table.Col("endpoint").Eq(newObject.Endpoint),
+		table.Col("status").Eq(models.StashBoxUpdateStatusPending.String()),
+	)
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("deleting pending updates: %w", err)
+	}
+
+	var r stashBoxUpdateRow
+	if err := r.fromStashBoxUpdate(*newObject); err != nil {
+		return err
+	}
+
+	insert := dialect.Insert(table).Prepared(true).Rows(r)
+	result, err := exec(ctx, insert)
+	if err != nil {
+		return fmt.Errorf("inserting into %s: %w", stashBoxUpdateTable, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	newObject.ID = int(id)
+	return nil
+}
+
+func (qb *StashBoxUpdateStore) UpdateStatus(ctx context.Context, id int, status models.StashBoxUpdateStatus) error {
+	table := qb.table()
+	q := dialect.Update(table).Prepared(true).Set(goqu.Record{
+		"status":     status.String(),
+		"updated_at": Timestamp{Timestamp: time.Now()},
+	}).Where(table.Col(idColumn).Eq(id))
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("updating %s: %w", stashBoxUpdateTable, err)
+	}
+
+	return nil
+}
+
+// returns nil, nil if not found
+func (qb *StashBoxUpdateStore) Find(ctx context.Context, id int) (*models.StashBoxUpdate, error) {
+	q := qb.selectDataset().Where(qb.table().Col(idColumn).Eq(id))
+
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
+
+func (qb *StashBoxUpdateStore) FindMany(ctx context.Context, ids []int) ([]*models.StashBoxUpdate, error) {
+	q := qb.selectDataset().Where(qb.table().Col(idColumn).In(ids))
+
+	unsorted, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*models.StashBoxUpdate, len(ids))
+	for _, u := range unsorted {
+		for i, id := range ids {
+			if id == u.ID {
+				ret[i] = u
+			}
+		}
+	}
+
+	for i := range ret {
+		if ret[i] == nil {
+			return nil, fmt.Errorf("stash-box update with id %d not found", ids[i])
+		}
+	}
+
+	return ret, nil
+}
+
+func (qb *StashBoxUpdateStore) Query(ctx context.Context, updateFilter *models.StashBoxUpdateFilterType, findFilter *models.FindFilterType) ([]*models.StashBoxUpdate, int, error) {
+	if findFilter == nil {
+		findFilter = &models.FindFilterType{}
+	}
+
+	table := qb.table()
+	q := qb.selectDataset()
+
+	if updateFilter != nil {
+		if updateFilter.ObjectType != nil {
+			q = q.Where(table.Col("object_type").Eq(updateFilter.ObjectType.String()))
+		}
+		if updateFilter.ObjectID != nil {
+			q = q.Where(table.Col("object_id").Eq(*updateFilter.ObjectID))
+		}
+		if updateFilter.Endpoint != nil {
+			q = q.Where(table.Col("endpoint").Eq(*updateFilter.Endpoint))
+		}
+		if updateFilter.Type != nil {
+			q = q.Where(table.Col("type").Eq(updateFilter.Type.String()))
+		}
+		if updateFilter.Status != nil {
+			q = q.Where(table.Col("status").Eq(updateFilter.Status.String()))
+		}
+	}
+
+	total, err := count(ctx, q.Select(goqu.COUNT("*")))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q = q.Order(table.Col(idColumn).Desc())
+	if !findFilter.IsGetAll() {
+		pageSize := findFilter.GetPageSize()
+		q = q.Limit(uint(pageSize)).Offset(uint((findFilter.GetPage() - 1) * pageSize))
+	}
+
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ret, total, nil
+}
+
+func (qb *StashBoxUpdateStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.StashBoxUpdate, error) {
+	const single = false
+	var ret []*models.StashBoxUpdate
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var f stashBoxUpdateRow
+		if err := r.StructScan(&f); err != nil {
+			return err
+		}
+
+		u, err := f.resolve()
+		if err != nil {
+			return err
+		}
+
+		ret = append(ret, u)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("querying %s: %w", stashBoxUpdateTable, err)
+	}
+
+	return ret, nil
+}
+
+// returns nil, nil if not found
+func (qb *StashBoxUpdateStore) GetUpstreamState(ctx context.Context, objectType models.ChangeObjectType, objectID int, endpoint string) (*models.StashBoxUpstreamState, error) {
+	table := goqu.T(stashBoxUpstreamStateTable)
+	q := dialect.From(table).Select(table.All()).Where(
+		table.Col("object_type").Eq(objectType.String()),
+		table.Col("object_id").Eq(objectID),
+		table.Col("endpoint").Eq(endpoint),
+	)
+
+	const single = true
+	var ret *models.StashBoxUpstreamState
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var f stashBoxUpstreamStateRow
+		if err := r.StructScan(&f); err != nil {
+			return err
+		}
+
+		ret = f.resolve()
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("querying %s: %w", stashBoxUpstreamStateTable, err)
+	}
+
+	return ret, nil
+}
+
+func (qb *StashBoxUpdateStore) SetUpstreamState(ctx context.Context, state models.StashBoxUpstreamState) error {
+	r := stashBoxUpstreamStateRow{
+		ObjectType:        state.ObjectType.String(),
+		ObjectID:          state.ObjectID,
+		Endpoint:          state.Endpoint,
+		StashID:           state.StashID,
+		Deleted:           state.Deleted,
+		UpstreamUpdatedAt: NullTimestampFromTimePtr(state.UpstreamUpdatedAt),
+		CheckedAt:         Timestamp{Timestamp: state.CheckedAt},
+	}
+
+	q := dialect.Insert(stashBoxUpstreamStateTable).Prepared(true).Rows(r).OnConflict(
+		goqu.DoUpdate("object_type, object_id, endpoint", goqu.Record{
+			"stash_id":            r.StashID,
+			"deleted":             r.Deleted,
+			"upstream_updated_at": r.UpstreamUpdatedAt,
+			"checked_at":          r.CheckedAt,
+		}),
+	)
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("setting %s: %w", stashBoxUpstreamStateTable, err)
+	}
+
+	return nil
+}
+
+// upstreamUpdatedAtCriterionHandler filters objects by the time their linked
+// stash-box object was last updated, as of the last update check. If the
+// object is linked to more than one stash-box instance, the latest time is
+// used.
+func upstreamUpdatedAtCriterionHandler(c *models.TimestampCriterionInput, objectType models.ChangeObjectType, parentIDCol string) criterionHandlerFunc {
+	return func(ctx context.Context, f *filterBuilder) {
+		if c == nil {
+			return
+		}
+
+		// a correlated subquery is used instead of a join so that the
+		// criterion may be used more than once in sub-filters
+		column := fmt.Sprintf("(SELECT MAX(upstream_updated_at) FROM %s WHERE object_type = ? AND object_id = %s)", stashBoxUpstreamStateTable, parentIDCol)
+		clause, args := getTimestampCriterionWhereClause(column, *c)
+		f.addWhere(clause, append([]interface{}{objectType.String()}, args...)...)
+	}
+}
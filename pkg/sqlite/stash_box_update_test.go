@@ -0,0 +1,564 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func Test_StashBoxUpdateStore_Create(t *testing.T) {
+	var (
+		mergedStashID     = "merged-stash-id"
+		upstreamUpdatedAt = time.Date(2000, 6, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	tests := []struct {
+		name      string
+		newObject models.StashBoxUpdate
+		// diffs expected after the update is stored
+		wantDiffs []*models.StashBoxFieldDiff
+	}{
+		{
+			"merged",
+			models.StashBoxUpdate{
+				ObjectType:    models.ChangeObjectTypeScene,
+				ObjectID:      sceneIDs[sceneIdxWithPerformer],
+				Endpoint:      stashBoxEndpoint,
+				StashID:       "stash-id",
+				Type:          models.StashBoxUpdateTypeMerged,
+				MergedStashID: &mergedStashID,
+				Diffs: []*models.StashBoxFieldDiff{
+					{Field: "title", OldValue: `"old"`, NewValue: `"new"`},
+				},
+				Status:            models.StashBoxUpdateStatusPending,
+				UpstreamUpdatedAt: &upstreamUpdatedAt,
+				CreatedAt:         stashBoxUpdateTime,
+				UpdatedAt:         stashBoxUpdateTime,
+			},
+			[]*models.StashBoxFieldDiff{
+				{Field: "title", OldValue: `"old"`, NewValue: `"new"`},
+			},
+		},
+		{
+			// nil diffs are stored as an empty list
+			"nil diffs",
+			models.StashBoxUpdate{
+				ObjectType: models.ChangeObjectTypePerformer,
+				ObjectID:   performerIDs[performerIdx1WithScene],
+				Endpoint:   stashBoxEndpoint,
+				StashID:    "stash-id",
+				Type:       models.StashBoxUpdateTypeDeleted,
+				Status:     models.StashBoxUpdateStatusPending,
+				CreatedAt:  stashBoxUpdateTime,
+				UpdatedAt:  stashBoxUpdateTime,
+			},
+			[]*models.StashBoxFieldDiff{},
+		},
+	}
+
+	qb := db.StashBoxUpdate
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			u := tt.newObject
+			if err := qb.Create(ctx, &u); err != nil {
+				t.Errorf("StashBoxUpdateStore.Create() error = %v", err)
+				return
+			}
+
+			assert.NotZero(u.ID)
+
+			found, err := qb.Find(ctx, u.ID)
+			if err != nil {
+				t.Errorf("StashBoxUpdateStore.Find() error = %v", err)
+			}
+
+			if !assert.NotNil(found) {
+				return
+			}
+
+			want := tt.newObject
+			want.ID = u.ID
+			want.Diffs = tt.wantDiffs
+			assert.Equal(want, *found)
+		})
+	}
+}
+
+func Test_StashBoxUpdateStore_CreateReplacesPending(t *testing.T) {
+	tests := []struct {
+		name       string
+		objectType models.ChangeObjectType
+		idx        int
+		wantKept   bool
+	}{
+		{"pending for scene", models.ChangeObjectTypeScene, stashBoxUpdateIdxPending, false},
+		{"dismissed for scene", models.ChangeObjectTypeScene, stashBoxUpdateIdxDismissed, true},
+		{"other endpoint", models.ChangeObjectTypeScene, stashBoxUpdateIdxWithOtherEndpoint, true},
+		{"other scene", models.ChangeObjectTypeScene, stashBoxUpdateIdxWithOtherScene, true},
+		// same id as the scene
+		{"other object type", models.ChangeObjectTypePerformer, stashBoxUpdateIdxPending, true},
+	}
+
+	qb := db.StashBoxUpdate
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			u := models.StashBoxUpdate{
+				ObjectType: tt.objectType,
+				ObjectID:   sceneIDs[sceneIdxWithGallery],
+				Endpoint:   stashBoxEndpoint,
+				StashID:    "new",
+				Type:       models.StashBoxUpdateTypeUpdated,
+				Status:     models.StashBoxUpdateStatusPending,
+				CreatedAt:  stashBoxUpdateTime,
+				UpdatedAt:  stashBoxUpdateTime,
+			}
+			if err := qb.Create(ctx, &u); err != nil {
+				t.Errorf("StashBoxUpdateStore.Create() error = %v", err)
+				return
+			}
+
+			found, err := qb.Find(ctx, stashBoxUpdateIDs[tt.idx])
+			if err != nil {
+				t.Errorf("StashBoxUpdateStore.Find() error = %v", err)
+			}
+
+			if tt.wantKept {
+				assert.NotNil(found)
+			} else {
+				assert.Nil(found)
+			}
+		})
+	}
+}
+
+func Test_StashBoxUpdateStore_UpdateStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status models.StashBoxUpdateStatus
+	}{
+		{"applied", models.StashBoxUpdateStatusApplied},
+		{"dismissed", models.StashBoxUpdateStatusDismissed},
+	}
+
+	qb := db.StashBoxUpdate
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			id := stashBoxUpdateIDs[stashBoxUpdateIdxPending]
+			if err := qb.UpdateStatus(ctx, id, tt.status); err != nil {
+				t.Errorf("StashBoxUpdateStore.UpdateStatus() error = %v", err)
+				return
+			}
+
+			found, err := qb.Find(ctx, id)
+			if err != nil {
+				t.Errorf("StashBoxUpdateStore.Find() error = %v", err)
+			}
+
+			if !assert.NotNil(found) {
+				return
+			}
+
+			assert.Equal(tt.status, found.Status)
+			assert.True(found.UpdatedAt.After(stashBoxUpdateTime))
+			assert.Equal(stashBoxUpdateTime, found.CreatedAt)
+		})
+	}
+}
+
+func Test_StashBoxUpdateStore_Find(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           int
+		wantObjectID int
+		wantNil      bool
+	}{
+		{"valid", stashBoxUpdateIDs[stashBoxUpdateIdxWithOtherScene], sceneIDs[sceneIdxWithTag], false},
+		{"invalid", invalidID, 0, true},
+	}
+
+	qb := db.StashBoxUpdate
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			got, err := qb.Find(ctx, tt.id)
+			if err != nil {
+				t.Errorf("StashBoxUpdateStore.Find() error = %v", err)
+				return
+			}
+
+			if tt.wantNil {
+				assert.Nil(t, got)
+				return
+			}
+
+			if assert.NotNil(t, got) {
+				assert.Equal(t, tt.wantObjectID, got.ObjectID)
+			}
+		})
+	}
+}
+
+func Test_StashBoxUpdateStore_FindMany(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     []int
+		wantErr bool
+	}{
+		{
+			"valid",
+			[]int{stashBoxUpdateIDs[stashBoxUpdateIdxWithOtherScene], stashBoxUpdateIDs[stashBoxUpdateIdxDismissed]},
+			false,
+		},
+		{
+			"invalid",
+			[]int{stashBoxUpdateIDs[stashBoxUpdateIdxDismissed], invalidID},
+			true,
+		},
+	}
+
+	qb := db.StashBoxUpdate
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			got, err := qb.FindMany(ctx, tt.ids)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("StashBoxUpdateStore.FindMany() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			var gotIDs []int
+			for _, u := range got {
+				gotIDs = append(gotIDs, u.ID)
+			}
+			assert.Equal(t, tt.ids, gotIDs)
+		})
+	}
+}
+
+func Test_StashBoxUpdateStore_Query(t *testing.T) {
+	var (
+		sceneType     = models.ChangeObjectTypeScene
+		performerType = models.ChangeObjectTypePerformer
+		sceneID       = sceneIDs[sceneIdxWithGallery]
+		otherEndpoint = stashBoxOtherEndpoint
+		deletedType   = models.StashBoxUpdateTypeDeleted
+		dismissed     = models.StashBoxUpdateStatusDismissed
+		page          = 2
+		perPage       = 2
+	)
+
+	tests := []struct {
+		name       string
+		filter     *models.StashBoxUpdateFilterType
+		findFilter *models.FindFilterType
+		// newest first
+		want      []int
+		wantCount int
+	}{
+		{
+			"all",
+			nil,
+			nil,
+			[]int{stashBoxUpdateIdxPerformerDeleted, stashBoxUpdateIdxWithOtherScene, stashBoxUpdateIdxWithOtherEndpoint, stashBoxUpdateIdxPending, stashBoxUpdateIdxDismissed},
+			totalStashBoxUpdates,
+		},
+		{
+			"object type",
+			&models.StashBoxUpdateFilterType{ObjectType: &performerType},
+			nil,
+			[]int{stashBoxUpdateIdxPerformerDeleted},
+			1,
+		},
+		{
+			"object",
+			&models.StashBoxUpdateFilterType{ObjectType: &sceneType, ObjectID: &sceneID},
+			nil,
+			[]int{stashBoxUpdateIdxWithOtherEndpoint, stashBoxUpdateIdxPending, stashBoxUpdateIdxDismissed},
+			3,
+		},
+		{
+			"endpoint",
+			&models.StashBoxUpdateFilterType{Endpoint: &otherEndpoint},
+			nil,
+			[]int{stashBoxUpdateIdxWithOtherEndpoint},
+			1,
+		},
+		{
+			"type",
+			&models.StashBoxUpdateFilterType{Type: &deletedType},
+			nil,
+			[]int{stashBoxUpdateIdxPerformerDeleted},
+			1,
+		},
+		{
+			"status",
+			&models.StashBoxUpdateFilterType{Status: &dismissed},
+			nil,
+			[]int{stashBoxUpdateIdxDismissed},
+			1,
+		},
+		{
+			// the count is not limited to the page
+			"paged",
+			nil,
+			&models.FindFilterType{Page: &page, PerPage: &perPage},
+			[]int{stashBoxUpdateIdxWithOtherEndpoint, stashBoxUpdateIdxPending},
+			totalStashBoxUpdates,
+		},
+	}
+
+	qb := db.StashBoxUpdate
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			got, count, err := qb.Query(ctx, tt.filter, tt.findFilter)
+			if err != nil {
+				t.Errorf("StashBoxUpdateStore.Query() error = %v", err)
+				return
+			}
+
+			var gotIDs, wantIDs []int
+			for _, u := range got {
+				gotIDs = append(gotIDs, u.ID)
+			}
+			for _, idx := range tt.want {
+				wantIDs = append(wantIDs, stashBoxUpdateIDs[idx])
+			}
+
+			assert.Equal(wantIDs, gotIDs)
+			assert.Equal(tt.wantCount, count)
+		})
+	}
+}
+
+func Test_StashBoxUpdateStore_UpstreamState(t *testing.T) {
+	var (
+		performerType = models.ChangeObjectTypePerformer
+		performerID   = performerIDs[performerIdxWithScene]
+		updated       = time.Date(2000, 6, 1, 12, 0, 0, 0, time.UTC)
+		later         = time.Date(2000, 7, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	state := func(endpoint string, stashID string, deleted bool, upstreamUpdatedAt *time.Time) models.StashBoxUpstreamState {
+		return models.StashBoxUpstreamState{
+			ObjectType:        performerType,
+			ObjectID:          performerID,
+			Endpoint:          endpoint,
+			StashID:           stashID,
+			Deleted:           deleted,
+			UpstreamUpdatedAt: upstreamUpdatedAt,
+			CheckedAt:         stashBoxUpdateTime,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		states []models.StashBoxUpstreamState
+		// nil if no state is expected
+		want *models.StashBoxUpstreamState
+	}{
+		{
+			"not checked",
+			nil,
+			nil,
+		},
+		{
+			"set",
+			[]models.StashBoxUpstreamState{
+				state(stashBoxEndpoint, "stash-id", false, &updated),
+			},
+			func() *models.StashBoxUpstreamState {
+				ret := state(stashBoxEndpoint, "stash-id", false, &updated)
+				return &ret
+			}(),
+		},
+		{
+			"no upstream update time",
+			[]models.StashBoxUpstreamState{
+				state(stashBoxEndpoint, "stash-id", false, nil),
+			},
+			func() *models.StashBoxUpstreamState {
+				ret := state(stashBoxEndpoint, "stash-id", false, nil)
+				return &ret
+			}(),
+		},
+		{
+			"replaced",
+			[]models.StashBoxUpstreamState{
+				state(stashBoxEndpoint, "stash-id", false, &updated),
+				state(stashBoxEndpoint, "merged-id", true, &later),
+			},
+			func() *models.StashBoxUpstreamState {
+				ret := state(stashBoxEndpoint, "merged-id", true, &later)
+				return &ret
+			}(),
+		},
+		{
+			"other endpoint",
+			[]models.StashBoxUpstreamState{
+				state(stashBoxOtherEndpoint, "stash-id", false, &updated),
+			},
+			nil,
+		},
+	}
+
+	qb := db.StashBoxUpdate
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			for _, s := range tt.states {
+				if err := qb.SetUpstreamState(ctx, s); err != nil {
+					t.Errorf("StashBoxUpdateStore.SetUpstreamState() error = %v", err)
+					return
+				}
+			}
+
+			got, err := qb.GetUpstreamState(ctx, performerType, performerID, stashBoxEndpoint)
+			if err != nil {
+				t.Errorf("StashBoxUpdateStore.GetUpstreamState() error = %v", err)
+				return
+			}
+
+			assert.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestPerformerQueryUpstreamUpdatedAt(t *testing.T) {
+	var (
+		newer = performerIDs[performerIdxWithScene]
+		older = performerIDs[performerIdx1WithScene]
+		// has the same id as a scene with an upstream state
+		sceneOnly = performerIDs[performerIdx2WithScene]
+
+		newerTime = time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+		olderTime = time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	criterion := func(modifier models.CriterionModifier, value time.Time) *models.TimestampCriterionInput {
+		return &models.TimestampCriterionInput{
+			Value:    value.Format(time.RFC3339),
+			Modifier: modifier,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter models.PerformerFilterType
+		want   []int
+	}{
+		{
+			"greater than",
+			models.PerformerFilterType{
+				UpstreamUpdatedAt: criterion(models.CriterionModifierGreaterThan, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			[]int{newer},
+		},
+		{
+			"less than",
+			models.PerformerFilterType{
+				UpstreamUpdatedAt: criterion(models.CriterionModifierLessThan, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			[]int{older},
+		},
+		{
+			// states of other object types are ignored
+			"not null",
+			models.PerformerFilterType{
+				UpstreamUpdatedAt: &models.TimestampCriterionInput{Modifier: models.CriterionModifierNotNull},
+			},
+			[]int{newer, older},
+		},
+		{
+			"is null",
+			models.PerformerFilterType{
+				UpstreamUpdatedAt: &models.TimestampCriterionInput{Modifier: models.CriterionModifierIsNull},
+			},
+			[]int{sceneOnly},
+		},
+		{
+			"or",
+			models.PerformerFilterType{
+				UpstreamUpdatedAt: criterion(models.CriterionModifierGreaterThan, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+				OperatorFilter: models.OperatorFilter[models.PerformerFilterType]{
+					Or: &models.PerformerFilterType{
+						UpstreamUpdatedAt: criterion(models.CriterionModifierLessThan, time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)),
+					},
+				},
+			},
+			[]int{newer, older},
+		},
+		{
+			"and",
+			models.PerformerFilterType{
+				UpstreamUpdatedAt: criterion(models.CriterionModifierGreaterThan, time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)),
+				OperatorFilter: models.OperatorFilter[models.PerformerFilterType]{
+					And: &models.PerformerFilterType{
+						UpstreamUpdatedAt: criterion(models.CriterionModifierLessThan, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+					},
+				},
+			},
+			[]int{older},
+		},
+	}
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			states := []models.StashBoxUpstreamState{
+				{ObjectType: models.ChangeObjectTypePerformer, ObjectID: newer, Endpoint: stashBoxEndpoint, StashID: "newer", UpstreamUpdatedAt: &newerTime},
+				{ObjectType: models.ChangeObjectTypePerformer, ObjectID: older, Endpoint: stashBoxEndpoint, StashID: "older", UpstreamUpdatedAt: &olderTime},
+				{ObjectType: models.ChangeObjectTypeScene, ObjectID: sceneOnly, Endpoint: stashBoxEndpoint, StashID: "scene", UpstreamUpdatedAt: &newerTime},
+			}
+			for _, s := range states {
+				s.CheckedAt = newerTime
+				if err := db.StashBoxUpdate.SetUpstreamState(ctx, s); err != nil {
+					t.Errorf("StashBoxUpdateStore.SetUpstreamState() error = %v", err)
+					return
+				}
+			}
+
+			filter := tt.filter
+			performers, _, err := db.Performer.Query(ctx, &filter, nil)
+			if err != nil {
+				t.Errorf("PerformerStore.Query() error = %v", err)
+				return
+			}
+
+			// only consider the performers used by this test
+			var got []int
+			for _, p := range performers {
+				if p.ID == newer || p.ID == older || p.ID == sceneOnly {
+					got = append(got, p.ID)
+				}
+			}
+
+			assert.ElementsMatch(tt.want, got)
+		})
+	}
+}
@@ -142,10 +142,11 @@ type StudioStore struct {
 	blobJoinQueryBuilder
 	tagRelationshipStore
 
-	tableMgr *table
+	tableMgr    *table
+	changeStore *ChangeStore
 }
 
-func NewStudioStore(blobStore *BlobStore) *StudioStore {
+func NewStudioStore(blobStore *BlobStore, changeStore *ChangeStore) *StudioStore {
 	return &StudioStore{
 		blobJoinQueryBuilder: blobJoinQueryBuilder{
 			blobStore: blobStore,
@@ -157,7 +158,8 @@ func NewStudioStore(blobStore *BlobStore) *StudioStore {
 			},
 		},
 
-		tableMgr: studioTableMgr,
+		tableMgr:    studioTableMgr,
+		changeStore: changeStore,
 	}
 }
 
@@ -210,6 +212,16 @@ func (qb *StudioStore) Create(ctx context.Context, newObject *models.Studio) err
 }
 
 func (qb *StudioStore) UpdatePartial(ctx context.Context, input models.StudioPartial) (*models.Studio, error) {
+	var ret *models.Studio
+	err := qb.changeStore.withChangeLog(ctx, models.ChangeObjectTypeStudio, input.ID, qb.changeValues(input.ChangeRelationships()), func() error {
+		var err error
+		ret, err = qb.updatePartial(ctx, input)
+		return err
+	})
+	return ret, err
+}
+
+func (qb *StudioStore) updatePartial(ctx context.Context, input models.StudioPartial) (*models.Studio, error) {
 	r := studioRowRecord{
 		updateRecord{
 			Record: make(exp.Record),
@@ -249,6 +261,12 @@ func (qb *StudioStore) UpdatePartial(ctx context.Context, input models.StudioPar
 
 // This is only used by the Import/Export functionality
 func (qb *StudioStore) Update(ctx context.Context, updatedObject *models.Studio) error {
+	return qb.changeStore.withChangeLog(ctx, models.ChangeObjectTypeStudio, updatedObject.ID, qb.changeValues(updatedObject.ChangeRelationships()), func() error {
+		return qb.update(ctx, updatedObject)
+	})
+}
+
+func (qb *StudioStore) update(ctx context.Context, updatedObject *models.Studio) error {
 	var r studioRow
 	r.fromStudio(*updatedObject)
 
@@ -276,6 +294,10 @@ func (qb *StudioStore) Update(ctx context.Context, updatedObject *models.Studio)
 }
 
 func (qb *StudioStore) Destroy(ctx context.Context, id int) error {
+	if err := qb.changeStore.destroyObject(ctx, models.ChangeObjectTypeStudio, id); err != nil {
+		return err
+	}
+
 	// must handle image checksums manually
 	if err := qb.destroyImage(ctx, id); err != nil {
 		return err
@@ -335,6 +357,33 @@ func (qb *StudioStore) find(ctx context.Context, id int) (*models.Studio, error)
 	return ret, nil
 }
 
+// changeValues returns a function that returns the audited field values of
+// the studio for the change log. Only the relationships provided are loaded.
+func (qb *StudioStore) changeValues(relationships []string) changeValuesFunc {
+	return func(ctx context.Context, id int) (models.ChangeValues, error) {
+		ret, err := qb.find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range relationships {
+			switch r {
+			case "aliases":
+				err = ret.LoadAliases(ctx, qb)
+			case "tag_ids":
+				err = ret.LoadTagIDs(ctx, qb)
+			case "stash_ids":
+				err = ret.LoadStashIDs(ctx, qb)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return ret.ChangeValues(), nil
+	}
+}
+
 // returns nil, sql.ErrNoRows if not found
 func (qb *StudioStore) get(ctx context.Context, q *goqu.SelectDataset) (*models.Studio, error) {
 	ret, err := qb.getMany(ctx, q)
@@ -514,6 +563,11 @@ func (qb *StudioStore) QueryForAutoTag(ctx context.Context, words []string) ([]*
 }
 
 func (qb *StudioStore) makeQuery(ctx context.Context, studioFilter *models.StudioFilterType, findFilter *models.FindFilterType) (*queryBuilder, error) {
+	studioFilter, findFilter, err := applyFilterQuery(ctx, studioFilter, findFilter)
+	if err != nil {
+		return nil, err
+	}
+
 	if studioFilter == nil {
 		studioFilter = &models.StudioFilterType{}
 	}
@@ -525,9 +579,13 @@ func (qb *StudioStore) makeQuery(ctx context.Context, studioFilter *models.Studi
 	distinctIDs(&query, studioTable)
 
 	if q := findFilter.Q; q != nil && *q != "" {
-		query.join(studioAliasesTable, "", "studio_aliases.studio_id = studios.id")
-		searchColumns := []string{"studios.name", "studio_aliases.alias"}
-		query.parseQueryString(searchColumns, *q)
+		if useFullTextSearch(ctx) {
+			query.parseFullTextQuery(studioFullTextIndex, *q)
+		} else {
+			query.join(studioAliasesTable, "", "studio_aliases.studio_id = studios.id")
+			searchColumns := []string{"studios.name", "studio_aliases.alias"}
+			query.parseQueryString(searchColumns, *q)
+		}
 	}
 
 	filter := filterBuilderFromHandler(ctx, &studioFilterHandler{
@@ -538,8 +596,7 @@ func (qb *StudioStore) makeQuery(ctx context.Context, studioFilter *models.Studi
 		return nil, err
 	}
 
-	var err error
-	query.sortAndPagination, err = qb.getStudioSort(findFilter)
+	query.sortAndPagination, err = qb.getStudioSort(&query, findFilter)
 	if err != nil {
 		return nil, err
 	}
@@ -586,10 +643,11 @@ var studioSortOptions = sortOptions{
 	"scenes_count",
 	"random",
 	"rating",
+	"relevance",
 	"updated_at",
 }
 
-func (qb *StudioStore) getStudioSort(findFilter *models.FindFilterType) (string, error) {
+func (qb *StudioStore) getStudioSort(query *queryBuilder, findFilter *models.FindFilterType) (string, error) {
 	var sort string
 	var direction string
 	if findFilter == nil {
@@ -607,6 +665,8 @@ func (qb *StudioStore) getStudioSort(findFilter *models.FindFilterType) (string,
 
 	sortQuery := ""
 	switch sort {
+	case relevanceSort:
+		sortQuery += query.relevanceSort(direction)
 	case "tag_count":
 		sortQuery += getCountSort(studioTable, studiosTagsTable, studioIDColumn, direction)
 	case "scenes_count":
@@ -84,6 +84,7 @@ func (qb *studioFilterHandler) criterionHandler() criterionHandler {
 		qb.childCountCriterionHandler(studioFilter.ChildCount),
 		&timestampCriterionHandler{studioFilter.CreatedAt, studioTable + ".created_at", nil},
 		&timestampCriterionHandler{studioFilter.UpdatedAt, studioTable + ".updated_at", nil},
+		upstreamUpdatedAtCriterionHandler(studioFilter.UpstreamUpdatedAt, models.ChangeObjectTypeStudio, studioTable+".id"),
 
 		&relatedFilterHandler{
 			relatedIDCol:   "scenes.id",
@@ -111,6 +112,12 @@ func (qb *studioFilterHandler) criterionHandler() criterionHandler {
 				studioRepository.galleries.innerJoin(f, "", "studios.id")
 			},
 		},
+
+		&savedFiltersCriterionHandler{
+			criterion: studioFilter.SavedFilters,
+			mode:      models.FilterModeStudios,
+			idColumn:  studioTable + ".id",
+		},
 	}
 }
 
@@ -590,7 +590,12 @@ func (qb *TagStore) QueryForAutoTag(ctx context.Context, words []string) ([]*mod
 	return qb.queryTags(ctx, query+" WHERE "+where, args)
 }
 
-func (qb *TagStore) Query(ctx context.Context, tagFilter *models.TagFilterType, findFilter *models.FindFilterType) ([]*models.Tag, int, error) {
+func (qb *TagStore) makeQuery(ctx context.Context, tagFilter *models.TagFilterType, findFilter *models.FindFilterType) (*queryBuilder, error) {
+	tagFilter, findFilter, err := applyFilterQuery(ctx, tagFilter, findFilter)
+	if err != nil {
+		return nil, err
+	}
+
 	if tagFilter == nil {
 		tagFilter = &models.TagFilterType{}
 	}
@@ -602,9 +607,13 @@ func (qb *TagStore) Query(ctx context.Context, tagFilter *models.TagFilterType,
 	distinctIDs(&query, tagTable)
 
 	if q := findFilter.Q; q != nil && *q != "" {
-		query.join(tagAliasesTable, "", "tag_aliases.tag_id = tags.id")
-		searchColumns := []string{"tags.name", "tag_aliases.alias"}
-		query.parseQueryString(searchColumns, *q)
+		if useFullTextSearch(ctx) {
+			query.parseFullTextQuery(tagFullTextIndex, *q)
+		} else {
+			query.join(tagAliasesTable, "", "tag_aliases.tag_id = tags.id")
+			searchColumns := []string{"tags.name", "tag_aliases.alias"}
+			query.parseQueryString(searchColumns, *q)
+		}
 	}
 
 	filter := filterBuilderFromHandler(ctx, &tagFilterHandler{
@@ -612,15 +621,24 @@ func (qb *TagStore) Query(ctx context.Context, tagFilter *models.TagFilterType,
 	})
 
 	if err := query.addFilter(filter); err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	var err error
 	query.sortAndPagination, err = qb.getTagSort(&query, findFilter)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	query.sortAndPagination += getPagination(findFilter)
+
+	return &query, nil
+}
+
+func (qb *TagStore) Query(ctx context.Context, tagFilter *models.TagFilterType, findFilter *models.FindFilterType) ([]*models.Tag, int, error) {
+	query, err := qb.makeQuery(ctx, tagFilter, findFilter)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	idsResult, countResult, err := query.executeFind(ctx)
 	if err != nil {
 		return nil, 0, err
@@ -640,6 +658,7 @@ var tagSortOptions = sortOptions{
 	"id",
 	"images_count",
 	"movies_count",
+	"relevance",
 	"studios_count",
 	"name",
 	"performers_count",
@@ -653,6 +672,15 @@ func (qb *TagStore) getDefaultTagSort() string {
 	return getSort("name", "ASC", "tags")
 }
 
+func (qb *TagStore) QueryCount(ctx context.Context, tagFilter *models.TagFilterType, findFilter *models.FindFilterType) (int, error) {
+	query, err := qb.makeQuery(ctx, tagFilter, findFilter)
+	if err != nil {
+		return 0, err
+	}
+
+	return query.executeCount(ctx)
+}
+
 func (qb *TagStore) getTagSort(query *queryBuilder, findFilter *models.FindFilterType) (string, error) {
 	var sort string
 	var direction string
@@ -671,6 +699,8 @@ func (qb *TagStore) getTagSort(query *queryBuilder, findFilter *models.FindFilte
 
 	sortQuery := ""
 	switch sort {
+	case relevanceSort:
+		sortQuery += query.relevanceSort(direction)
 	case "scenes_count":
 		sortQuery += getCountSort(tagTable, scenesTagsTable, tagIDColumn, direction)
 	case "scene_markers_count":
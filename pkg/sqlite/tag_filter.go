@@ -102,6 +102,12 @@ func (qb *tagFilterHandler) criterionHandler() criterionHandler {
 				tagRepository.galleries.innerJoin(f, "", "tags.id")
 			},
 		},
+
+		&savedFiltersCriterionHandler{
+			criterion: tagFilter.SavedFilters,
+			mode:      models.FilterModeTags,
+			idColumn:  tagTable + ".id",
+		},
 	}
 }
 
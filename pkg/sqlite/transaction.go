@@ -18,6 +18,8 @@ const (
 	txnKey key = iota + 1
 	dbKey
 	exclusiveKey
+	savedFiltersKey
+	fullTextSearchKey
 )
 
 func (db *Database) WithDatabase(ctx context.Context) (context.Context, error) {
@@ -26,6 +28,8 @@ func (db *Database) WithDatabase(ctx context.Context) (context.Context, error) {
 		return ctx, nil
 	}
 
+	ctx = context.WithValue(ctx, fullTextSearchKey, db.fullTextSearchEnabled.Load())
+
 	return context.WithValue(ctx, dbKey, db.db), nil
 }
 
@@ -53,6 +57,7 @@ func (db *Database) Begin(ctx context.Context, exclusive bool) (context.Context,
 	}
 
 	ctx = context.WithValue(ctx, exclusiveKey, exclusive)
+	ctx = context.WithValue(ctx, fullTextSearchKey, db.fullTextSearchEnabled.Load())
 
 	return context.WithValue(ctx, txnKey, tx), nil
 }
@@ -69,9 +74,54 @@ func (db *Database) Commit(ctx context.Context) error {
 		return err
 	}
 
+	if exclusive := ctx.Value(exclusiveKey).(bool); exclusive {
+		db.notifyCommit()
+	}
+
 	return nil
 }
 
+// SubscribeCommits returns a channel that receives a value after write
+// transactions are committed. Notifications are not queued: a single value is
+// received for commits that occur before the previous value is read. The
+// channel is closed when the context is done.
+func (db *Database) SubscribeCommits(ctx context.Context) <-chan struct{} {
+	db.commitSubsMutex.Lock()
+	defer db.commitSubsMutex.Unlock()
+
+	c := make(chan struct{}, 1)
+	db.commitSubs = append(db.commitSubs, c)
+
+	go func() {
+		<-ctx.Done()
+		db.commitSubsMutex.Lock()
+		defer db.commitSubsMutex.Unlock()
+		close(c)
+
+		for i, s := range db.commitSubs {
+			if s == c {
+				db.commitSubs = append(db.commitSubs[:i], db.commitSubs[i+1:]...)
+				break
+			}
+		}
+	}()
+
+	return c
+}
+
+func (db *Database) notifyCommit() {
+	db.commitSubsMutex.Lock()
+	defer db.commitSubsMutex.Unlock()
+
+	for _, c := range db.commitSubs {
+		select {
+		case c <- struct{}{}:
+		default:
+			// a notification is already pending
+		}
+	}
+}
+
 func (db *Database) Rollback(ctx context.Context) error {
 	tx, err := getTx(ctx)
 	if err != nil {
@@ -125,19 +175,26 @@ func (db *Database) IsLocked(err error) bool {
 
 func (db *Database) Repository() models.Repository {
 	return models.Repository{
-		TxnManager:     db,
-		Blob:           db.Blobs,
-		File:           db.File,
-		Folder:         db.Folder,
-		Gallery:        db.Gallery,
-		GalleryChapter: db.GalleryChapter,
-		Image:          db.Image,
-		Movie:          db.Movie,
-		Performer:      db.Performer,
-		Scene:          db.Scene,
-		SceneMarker:    db.SceneMarker,
-		Studio:         db.Studio,
-		Tag:            db.Tag,
-		SavedFilter:    db.SavedFilter,
+		TxnManager:             db,
+		Blob:                   db.Blobs,
+		File:                   db.File,
+		Folder:                 db.Folder,
+		Gallery:                db.Gallery,
+		GalleryChapter:         db.GalleryChapter,
+		Image:                  db.Image,
+		Movie:                  db.Movie,
+		Performer:              db.Performer,
+		Scene:                  db.Scene,
+		SceneMarker:            db.SceneMarker,
+		Studio:                 db.Studio,
+		Tag:                    db.Tag,
+		SavedFilter:            db.SavedFilter,
+		Change:                 db.Change,
+		IdentifyProposal:       db.IdentifyProposal,
+		StashBoxSubmission:     db.StashBoxSubmission,
+		StashBoxUpdate:         db.StashBoxUpdate,
+		AutoTagRule:            db.AutoTagRule,
+		FilenameParserTemplate: db.FilenameParserTemplate,
+		Playlist:               db.Playlist,
 	}
 }
@@ -93,6 +93,12 @@ func withTxn(ctx context.Context, m Manager, fn TxnFunc, exclusive bool, execCom
 	return err
 }
 
+// InTxn returns true if ctx belongs to a transaction started by WithTxn or
+// WithReadTxn.
+func InTxn(ctx context.Context) bool {
+	return hookManagerCtx(ctx) != nil
+}
+
 func begin(ctx context.Context, m Manager, exclusive bool) (context.Context, error) {
 	var err error
 	ctx, err = m.Begin(ctx, exclusive)